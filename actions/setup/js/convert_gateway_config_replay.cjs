@@ -0,0 +1,63 @@
+// @ts-check
+"use strict";
+
+// Ensures global.core is available when running outside github-script context
+require("./shim.cjs");
+
+/**
+ * convert_gateway_config_replay.cjs
+ *
+ * Converts the MCP gateway's standard HTTP-based configuration to the JSON
+ * file read by the replay engine (replay_engine.cjs). Rewrites URLs to use
+ * the host-side domain and keeps every server, including CLI-mounted ones,
+ * because a recorded transcript may contain calls made through CLI wrappers.
+ *
+ * Required environment variables:
+ * - MCP_GATEWAY_OUTPUT: Path to gateway output configuration file
+ * - MCP_GATEWAY_DOMAIN: Domain for MCP server URLs (required by loadGatewayContext)
+ * - MCP_GATEWAY_HOST_DOMAIN: Host-side domain for replay MCP URLs (e.g., localhost)
+ * - MCP_GATEWAY_PORT: Port for MCP gateway (e.g., 80)
+ */
+
+const { rewriteUrl, loadGatewayContext, filterAndTransformServers, writeSecureOutput } = require("./convert_gateway_config_shared.cjs");
+
+const OUTPUT_PATH = "/tmp/gh-aw/mcp-config/replay-mcp-config.json";
+
+/**
+ * @param {Record<string, unknown>} entry
+ * @param {string} urlPrefix
+ * @returns {Record<string, unknown>}
+ */
+function transformReplayEntry(entry, urlPrefix) {
+  const transformed = { ...entry };
+  if (typeof transformed.url === "string") {
+    transformed.url = rewriteUrl(transformed.url, urlPrefix);
+  }
+  return transformed;
+}
+
+function main() {
+  const { gatewayOutput, port, servers } = loadGatewayContext();
+
+  // The replay runner runs directly on the host runner, like Gemini, so use
+  // MCP_GATEWAY_HOST_DOMAIN (localhost) instead of MCP_GATEWAY_DOMAIN.
+  const hostDomain = process.env.MCP_GATEWAY_HOST_DOMAIN || "localhost";
+  const urlPrefix = `http://${hostDomain}:${port}`;
+
+  core.info("Converting gateway configuration to replay format...");
+  core.info(`Input: ${gatewayOutput}`);
+  core.info(`Target domain: ${hostDomain}:${port}`);
+  const result = filterAndTransformServers(servers, new Set(), (_name, entry) => transformReplayEntry(entry, urlPrefix));
+  core.info(`Servers: ${Object.keys(result).length} included`);
+
+  // Write with owner-only permissions (0o600) to protect the gateway bearer token.
+  writeSecureOutput(OUTPUT_PATH, JSON.stringify({ mcpServers: result }, null, 2));
+
+  core.info(`Replay configuration written to ${OUTPUT_PATH}`);
+}
+
+if (require.main === module) {
+  main();
+}
+
+module.exports = { transformReplayEntry, main };
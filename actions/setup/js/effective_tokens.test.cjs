@@ -3,7 +3,7 @@
 
 const { defaultTokenClassWeights, getTokenClassWeights, getModelMultiplier, computeBaseWeightedTokens, computeEffectiveTokens, formatET, _resetCache } = require("./effective_tokens.cjs");
 
-// Model multipliers JSON used in tests (matches pkg/cli/data/model_multipliers.json)
+// Model multipliers JSON used in tests (matches pkg/workflow/data/model_multipliers.json)
 const TEST_MULTIPLIERS_JSON = JSON.stringify({
   version: "1",
   description: "Test model multipliers",
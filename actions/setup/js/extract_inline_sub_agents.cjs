@@ -25,6 +25,13 @@
 //   description   Human-readable description of the sub-agent's role.
 //   model         AI model to use.  Default is "inherited" (uses the parent
 //                 workflow's model when not set).
+//   tools         Tool allowlist, rewritten to the engine's native form
+//                 (claude: comma-separated string, others: list).
+//   max-turns     Turn limit, written as max_turns for engines that support it.
+//   network       Validated at compile time; never written to the agent file.
+//
+// The compiler rejects tools/max-turns/network for engines that cannot enforce
+// them, so anything dropped here was already reported.
 //
 // If no ## agent: markers are present the content is returned unchanged and no
 // files are written.
@@ -34,7 +41,10 @@ const path = require("path");
 
 // Supported frontmatter fields for inline sub-agents.
 // Any other field is stripped with a warning.
-const SUPPORTED_FRONTMATTER_FIELDS = ["description", "model"];
+const SUPPORTED_FRONTMATTER_FIELDS = ["description", "model", "tools", "max-turns", "network"];
+
+// Engines whose sub-agent files accept a max_turns field.
+const MAX_TURNS_ENGINES = ["gemini"];
 
 // Regex for the start marker: ## agent: `name` (lowercase identifier)
 const START_MARKER_RE = /^##[ \t]+agent:[ \t]+`([a-z][a-z0-9_-]*)`[ \t]*$/gm;
@@ -43,23 +53,57 @@ const START_MARKER_RE = /^##[ \t]+agent:[ \t]+`([a-z][a-z0-9_-]*)`[ \t]*$/gm;
 // Used to find the boundary where each agent block ends.
 const H2_HEADING_RE = /^##[ \t]/gm;
 
+/**
+ * Parses the value of a `tools` frontmatter field into a list of tool names.
+ * Accepts a flow list (`[a, b]`), a block list (`- a` lines) or a
+ * comma-separated string. Returns null for any other shape (e.g. a map).
+ *
+ * @param {string} inlineValue - Text after `tools:` on the key line.
+ * @param {string[]} continuation - Lines following the key line.
+ * @returns {string[] | null}
+ */
+function parseToolsValue(inlineValue, continuation) {
+  const unquote = (/** @type {string} */ s) => s.trim().replace(/^["']|["']$/g, "");
+  const value = inlineValue.trim();
+  if (value.startsWith("[") && value.endsWith("]")) {
+    return value
+      .slice(1, -1)
+      .split(",")
+      .map(unquote)
+      .filter(Boolean);
+  }
+  if (value !== "") {
+    return unquote(value)
+      .split(",")
+      .map(t => t.trim())
+      .filter(Boolean);
+  }
+  const items = continuation.filter(line => line.trim() !== "" && !line.trim().startsWith("#"));
+  if (items.length === 0 || !items.every(line => line.trim().startsWith("- "))) {
+    return null;
+  }
+  return items.map(line => unquote(line.trim().slice(2)));
+}
+
 /**
  * Filters sub-agent frontmatter to only retain supported fields.
  *
- * Only `description` and `model` are valid fields in a sub-agent frontmatter
- * block.  Any other top-level key is stripped and a warning is emitted.
- * If `model` is not present its implicit default is "inherited" (the sub-agent
- * uses the parent workflow's model), but the key is NOT written unless the
- * workflow author explicitly sets it.
+ * `description` and `model` are kept as written.  `tools` and `max-turns` are
+ * rewritten to the engine's native form; `network` is dropped (it is enforced
+ * by the compiler).  Any other top-level key is stripped and a warning is
+ * emitted.  If `model` is not present its implicit default is "inherited" (the
+ * sub-agent uses the parent workflow's model), but the key is NOT written
+ * unless the workflow author explicitly sets it.
  *
  * When no YAML frontmatter delimiter (`---`) is found at the start of the
  * content, the content is returned unchanged.
  *
  * @param {string} content   - Raw agent block content (frontmatter + prompt).
  * @param {string} agentName - Agent name used in log messages.
+ * @param {string} [engineId] - The engine ID (e.g. "claude", "copilot").
  * @returns {string} Content with only supported frontmatter fields retained.
  */
-function filterSubAgentFrontmatter(content, agentName) {
+function filterSubAgentFrontmatter(content, agentName, engineId) {
   // A YAML frontmatter block must start immediately at the beginning of the
   // content (after trimming performed by the caller).
   if (!content.startsWith("---\n")) {
@@ -78,32 +122,63 @@ function filterSubAgentFrontmatter(content, agentName) {
   // Everything after the closing "\n---" (including the optional newline).
   const body = content.slice(closeIdx + 4);
 
+  // Group lines by top-level key: continuation / comment / blank lines belong
+  // to the preceding key so multi-line values (e.g. `description: |`) stay intact.
+  /** @type {Array<{key: string, value: string, lines: string[], continuation: string[]}>} */
+  const fields = [];
+  for (const line of fmLines) {
+    const keyMatch = line.match(/^([a-zA-Z_][a-zA-Z0-9_-]*)[ \t]*:(.*)$/);
+    if (keyMatch) {
+      fields.push({ key: keyMatch[1], value: keyMatch[2], lines: [line], continuation: [] });
+    } else if (fields.length > 0) {
+      fields[fields.length - 1].lines.push(line);
+      fields[fields.length - 1].continuation.push(line);
+    }
+  }
+
+  const engine = (engineId || "").toLowerCase();
+  /** @type {string[]} */
   const kept = [];
+  /** @type {string[]} */
   const stripped = [];
+  /** @type {string[]} */
+  const unenforced = [];
 
-  for (const line of fmLines) {
-    // Match a simple scalar YAML key at the start of the line.
-    // YAML keys for description and model are plain identifiers (no hyphens).
-    const keyMatch = line.match(/^([a-zA-Z_][a-zA-Z0-9_]*)[ \t]*:/);
-    if (keyMatch) {
-      const key = keyMatch[1];
-      if (SUPPORTED_FRONTMATTER_FIELDS.includes(key)) {
-        kept.push(line);
+  for (const field of fields) {
+    if (!SUPPORTED_FRONTMATTER_FIELDS.includes(field.key)) {
+      stripped.push(field.key);
+      continue;
+    }
+    if (field.key === "tools") {
+      const tools = parseToolsValue(field.value, field.continuation);
+      if (tools === null) {
+        stripped.push(field.key);
+      } else if (engine === "claude") {
+        kept.push(`tools: ${tools.join(", ")}`);
       } else {
-        stripped.push(key);
+        kept.push(`tools: ${JSON.stringify(tools)}`);
       }
-    } else {
-      // Continuation / comment / blank line — keep only when at least one
-      // supported key has already been accepted, so multi-line values (e.g.
-      // `description: |`) are preserved correctly.
-      if (kept.length > 0) {
-        kept.push(line);
+      continue;
+    }
+    if (field.key === "max-turns") {
+      if (MAX_TURNS_ENGINES.includes(engine)) {
+        kept.push(`max_turns: ${field.value.trim()}`);
+      } else {
+        unenforced.push(field.key);
       }
+      continue;
     }
+    if (field.key === "network") {
+      continue;
+    }
+    kept.push(...field.lines);
   }
 
   if (stripped.length > 0) {
-    core.warning(`[extractInlineSubAgents] sub-agent "${agentName}": unsupported frontmatter field(s) stripped: ${stripped.join(", ")} (only "description" and "model" are supported)`);
+    core.warning(`[extractInlineSubAgents] sub-agent "${agentName}": unsupported frontmatter field(s) stripped: ${stripped.join(", ")} (supported: ${SUPPORTED_FRONTMATTER_FIELDS.join(", ")})`);
+  }
+  if (unenforced.length > 0) {
+    core.warning(`[extractInlineSubAgents] sub-agent "${agentName}": field(s) not supported by engine "${engine || "copilot"}" stripped: ${unenforced.join(", ")}`);
   }
 
   // If no supported fields remain, omit the frontmatter block entirely.
@@ -234,7 +309,7 @@ function writeInlineSubAgents(content, workspaceDir, agentsBaseDir, engineId) {
 
   for (const agent of agents) {
     const agentPath = path.join(agentsDir, agent.name + ext);
-    const filteredContent = filterSubAgentFrontmatter(agent.content, agent.name);
+    const filteredContent = filterSubAgentFrontmatter(agent.content, agent.name, engineId);
     const agentContent = filteredContent.endsWith("\n") ? filteredContent : filteredContent + "\n";
     fs.writeFileSync(agentPath, agentContent, "utf8");
     core.info(`[extractInlineSubAgents] Written sub-agent: ${agentPath} (${agentContent.length} bytes)`);
@@ -273,3 +273,22 @@ describe("filterSubAgentFrontmatter", () => {
     expect(result).toBe("---\ndescription: Summarizes files\n---\nYou are a summarizer.");
   });
 });
+
+describe("filterSubAgentFrontmatter restrictions", () => {
+  it("rewrites tools to a list for copilot", () => {
+    const content = "---\ndescription: Reviewer\ntools:\n  - read\n  - search\n---\nPrompt.";
+    const result = filterSubAgentFrontmatter(content, "agent", "copilot");
+    expect(result).toBe('---\ndescription: Reviewer\ntools: ["read","search"]\n---\nPrompt.');
+  });
+
+  it("rewrites tools to a comma-separated string for claude", () => {
+    const content = "---\ntools: [Read, Grep]\n---\nPrompt.";
+    expect(filterSubAgentFrontmatter(content, "agent", "claude")).toBe("---\ntools: Read, Grep\n---\nPrompt.");
+  });
+
+  it("writes max_turns only for engines that support it and never writes network", () => {
+    const content = "---\nmax-turns: 5\nnetwork:\n  allowed:\n    - example.com\nmodel: gpt-4o\n---\nPrompt.";
+    expect(filterSubAgentFrontmatter(content, "agent", "gemini")).toBe("---\nmax_turns: 5\nmodel: gpt-4o\n---\nPrompt.");
+    expect(filterSubAgentFrontmatter(content, "agent", "copilot")).toBe("---\nmodel: gpt-4o\n---\nPrompt.");
+  });
+});
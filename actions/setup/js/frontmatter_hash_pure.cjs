@@ -103,6 +103,13 @@ async function computeFrontmatterHash(workflowPath, options = {}) {
     log(`canonical.imported-frontmatters:\n${canonical["imported-frontmatters"]}`);
   }
 
+  // Add the contents of local sub-agent files, which are compiled into the prompt
+  const subAgents = await processSubAgentsTextBased(frontmatterText, baseDir, fileReader);
+  if (subAgents.length > 0) {
+    canonical["sub-agents"] = subAgents;
+    log(`canonical.sub-agents: ${subAgents.length} file(s)`);
+  }
+
   // When inlined-imports is enabled, the entire markdown body is compiled into the lock
   // file, so any change to the body must invalidate the hash. Include the full body text.
   // Otherwise, only extract the relevant template expressions (env./vars. references).
@@ -270,6 +277,86 @@ function extractImportsFromText(frontmatterText) {
   return imports;
 }
 
+/**
+ * Extract the specs listed under the top-level "sub-agents:" key using simple text parsing
+ * @param {string} frontmatterText - The frontmatter text
+ * @returns {string[]} Array of sub-agent specs
+ */
+function extractSubAgentsFromText(frontmatterText) {
+  const specs = [];
+  let inSubAgents = false;
+
+  for (const line of frontmatterText.split("\n")) {
+    const trimmed = line.trim();
+    if (!trimmed || trimmed.startsWith("#")) continue;
+
+    // A new top-level key ends the list; unindented list items still belong to it
+    if (line.search(/\S/) === 0 && !trimmed.startsWith("-")) {
+      inSubAgents = trimmed.startsWith("sub-agents:");
+      continue;
+    }
+
+    if (inSubAgents && trimmed.startsWith("-")) {
+      const item = trimmed.substring(1).trim().replace(/^["']|["']$/g, "");
+      if (item) {
+        specs.push(item);
+      }
+    }
+  }
+
+  return specs;
+}
+
+/**
+ * Resolve a local sub-agent spec the way the compiler does: ".github/" and "/" prefixed
+ * specs are relative to the repository root, others to the workflow's directory
+ * @param {string} spec - The sub-agent spec
+ * @param {string} baseDir - Directory of the workflow
+ * @returns {string} Path of the sub-agent file
+ */
+function resolveSubAgentTextPath(spec, baseDir) {
+  let githubFolder = baseDir;
+  while (!githubFolder.endsWith(".github")) {
+    const parent = path.dirname(githubFolder);
+    if (parent === githubFolder || parent === "." || parent === "/") {
+      return path.join(baseDir, spec);
+    }
+    githubFolder = parent;
+  }
+
+  const repoRoot = path.dirname(githubFolder);
+  if (spec.startsWith(".github/")) {
+    return path.join(repoRoot, spec);
+  }
+  if (spec.startsWith("/")) {
+    return path.join(repoRoot, spec.substring(1));
+  }
+  return path.join(baseDir, spec);
+}
+
+/**
+ * Read the local files listed under sub-agents: so that editing a sub-agent invalidates
+ * the hash. Remote specs are pinned to a commit SHA, which is part of the frontmatter
+ * text already, and files that cannot be read are skipped.
+ * @param {string} frontmatterText - The frontmatter text
+ * @param {string} baseDir - Directory of the workflow
+ * @param {Function} fileReader - File reader function (async (filePath) => content)
+ * @returns {Promise<string[]>} Sorted "spec\ncontent" entries
+ */
+async function processSubAgentsTextBased(frontmatterText, baseDir, fileReader = defaultFileReader) {
+  const entries = [];
+  for (const spec of extractSubAgentsFromText(frontmatterText)) {
+    try {
+      const content = await fileReader(resolveSubAgentTextPath(spec, baseDir));
+      entries.push(`${spec}\n${normalizeFrontmatterText(content)}`);
+    } catch (err) {
+      // Skip remote specs and files that can't be read
+      continue;
+    }
+  }
+  return entries.sort();
+}
+
 /**
  * Normalize frontmatter text for consistent hashing
  * Removes leading/trailing whitespace and normalizes line endings
@@ -416,6 +503,7 @@ module.exports = {
   computeFrontmatterHash,
   extractFrontmatterAndBody,
   extractImportsFromText,
+  extractSubAgentsFromText,
   extractRelevantTemplateExpressions,
   marshalCanonicalJSON,
   marshalSorted,
@@ -423,6 +511,7 @@ module.exports = {
   normalizeFrontmatterText,
   parseBoolFromFrontmatter,
   processImportsTextBased,
+  processSubAgentsTextBased,
   defaultFileReader,
   createGitHubFileReader,
 };
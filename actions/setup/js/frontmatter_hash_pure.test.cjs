@@ -6,6 +6,7 @@ const {
   computeFrontmatterHash,
   extractFrontmatterAndBody,
   extractImportsFromText,
+  extractSubAgentsFromText,
   extractRelevantTemplateExpressions,
   marshalCanonicalJSON,
   marshalSorted,
@@ -96,6 +97,23 @@ engine: copilot`;
     });
   });
 
+  describe("extractSubAgentsFromText", () => {
+    it("should extract sub-agent specs until the next top-level key", () => {
+      const frontmatterText = `engine: claude
+sub-agents:
+  - .github/agents/a.md
+  - "shared/b.md"
+tools:
+  - github`;
+
+      expect(extractSubAgentsFromText(frontmatterText)).toEqual([".github/agents/a.md", "shared/b.md"]);
+    });
+
+    it("should handle no sub-agents", () => {
+      expect(extractSubAgentsFromText("engine: claude")).toEqual([]);
+    });
+  });
+
   describe("extractRelevantTemplateExpressions", () => {
     it("should extract env expressions", () => {
       const markdown = "Use $" + "{{ env.MY_VAR }} here\nAnd also $" + "{{ env.OTHER }}";
@@ -393,6 +411,29 @@ name: "Test Workflow"`;
       }
     });
 
+    it("should include sub-agent file contents in hash", async () => {
+      const mainFile = "/repo/.github/workflows/main.md";
+      const mockFileSystem = {
+        [mainFile]: "---\nengine: claude\nsub-agents:\n  - /.github/agents/reviewer.md\n  - shared/planner.md\n---\n\nMain body",
+        "/repo/.github/agents/reviewer.md": "---\nname: reviewer\n---\nReview the change.",
+        "/repo/.github/workflows/shared/planner.md": "---\nname: planner\n---\nPlan the work.",
+      };
+      const customFileReader = async filePath => {
+        if (mockFileSystem[filePath]) {
+          return mockFileSystem[filePath];
+        }
+        throw new Error(`File not found: ${filePath}`);
+      };
+
+      const hash = await computeFrontmatterHash(mainFile, { fileReader: customFileReader });
+      for (const filePath of ["/repo/.github/agents/reviewer.md", "/repo/.github/workflows/shared/planner.md"]) {
+        const original = mockFileSystem[filePath];
+        mockFileSystem[filePath] = `${original}\nBe thorough.`;
+        expect(await computeFrontmatterHash(mainFile, { fileReader: customFileReader })).not.toBe(hash);
+        mockFileSystem[filePath] = original;
+      }
+    });
+
     it("should include body-text in hash when inlined-imports is true", async () => {
       const tmpDir = fs.mkdtempSync(path.join(require("os").tmpdir(), "frontmatter-hash-test-"));
       const testFile = path.join(tmpDir, "test.md");
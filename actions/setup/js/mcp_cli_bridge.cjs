@@ -1125,6 +1125,10 @@ if (require.main === module) {
 }
 
 module.exports = {
+  ensureAuditDir,
+  mcpInitialize,
+  mcpNotifyInitialized,
+  mcpToolsCall,
   parseToolArgs,
   coerceToolArgValue,
   extractJSONRPCMessages,
@@ -0,0 +1,205 @@
+// @ts-check
+
+/**
+ * replay_engine.cjs
+ *
+ * @safe-outputs-exempt SEC-004: "body" references are transport payloads/responses, not user-authored comment bodies
+ *
+ * Runner for the replay engine. Replays a transcript of MCP tool calls recorded
+ * from a real run (gh aw logs --record-transcript) against the workflow's MCP
+ * servers and the safe-outputs MCP server, without calling a model.
+ *
+ * Every recorded call is checked against the compiled tool policy before any
+ * call is made, so a transcript that is not permitted by the workflow's
+ * configuration fails the run without side effects. A recorded call that returns
+ * an error during replay is a mismatch and also fails the run.
+ *
+ * Transcript format:
+ *   {
+ *     "version": 1,
+ *     "workflow": "triage",
+ *     "run_id": 123,
+ *     "calls": [{ "server": "github", "tool": "get_issue", "arguments": { ... } }]
+ *   }
+ *
+ * Environment variables:
+ * - GH_AW_REPLAY_TRANSCRIPT: Path to the transcript file (required)
+ * - GH_AW_REPLAY_ALLOWED_TOOLS: JSON object mapping server IDs to allowed tools ("*" allows all)
+ * - GH_AW_REPLAY_MCP_CONFIG: Path to the MCP config written by convert_gateway_config_replay.cjs
+ */
+
+require("./shim.cjs");
+
+const fs = require("fs");
+const { ensureAuditDir, mcpInitialize, mcpNotifyInitialized, mcpToolsCall, extractJSONRPCMessages } = require("./mcp_cli_bridge.cjs");
+
+/** Transcript format version understood by this runner */
+const TRANSCRIPT_VERSION = 1;
+
+/**
+ * @typedef {{ server: string, tool: string, arguments?: Record<string, unknown> }} ReplayCall
+ * @typedef {{ version: number, workflow?: string, run_id?: number, run_url?: string, calls: ReplayCall[] }} ReplayTranscript
+ */
+
+/**
+ * Load and validate a transcript file.
+ *
+ * @param {string} transcriptPath
+ * @returns {ReplayTranscript}
+ */
+function loadTranscript(transcriptPath) {
+  if (!fs.existsSync(transcriptPath)) {
+    throw new Error(`Transcript not found: ${transcriptPath}. Record one with: gh aw logs <run-id> --record-transcript`);
+  }
+  const transcript = JSON.parse(fs.readFileSync(transcriptPath, "utf8"));
+  if (!transcript || typeof transcript !== "object" || Array.isArray(transcript)) {
+    throw new Error(`Transcript ${transcriptPath} must be a JSON object`);
+  }
+  if (transcript.version !== TRANSCRIPT_VERSION) {
+    throw new Error(`Unsupported transcript version ${transcript.version} (expected ${TRANSCRIPT_VERSION})`);
+  }
+  if (!Array.isArray(transcript.calls)) {
+    throw new Error(`Transcript ${transcriptPath} is missing the calls array`);
+  }
+  for (const [index, call] of transcript.calls.entries()) {
+    if (!call || typeof call.server !== "string" || typeof call.tool !== "string") {
+      throw new Error(`Transcript call #${index + 1} must have string server and tool fields`);
+    }
+  }
+  return transcript;
+}
+
+/**
+ * Check every recorded call against the compiled tool policy and the servers
+ * available through the gateway. Returns one message per rejected call.
+ *
+ * @param {ReplayCall[]} calls
+ * @param {Record<string, string[]>} policy
+ * @param {Record<string, unknown>} servers
+ * @returns {string[]}
+ */
+function findPolicyViolations(calls, policy, servers) {
+  /** @type {string[]} */
+  const violations = [];
+  for (const [index, call] of calls.entries()) {
+    const label = `call #${index + 1} ${call.server}.${call.tool}`;
+    const allowed = policy[call.server];
+    if (!allowed) {
+      violations.push(`${label}: server "${call.server}" is not configured for this workflow`);
+      continue;
+    }
+    if (!allowed.includes("*") && !allowed.includes(call.tool)) {
+      violations.push(`${label}: tool "${call.tool}" is not in the allowed list (${allowed.join(", ") || "none"})`);
+      continue;
+    }
+    if (!servers[call.server]) {
+      violations.push(`${label}: server "${call.server}" is not available through the MCP gateway`);
+    }
+  }
+  return violations;
+}
+
+/**
+ * Describe a tools/call response as an error message, or "" when the call succeeded.
+ *
+ * @param {unknown} responseBody
+ * @returns {string}
+ */
+function responseError(responseBody) {
+  for (const message of extractJSONRPCMessages(responseBody)) {
+    if (!message || typeof message !== "object") continue;
+    if ("error" in message && message.error && typeof message.error === "object") {
+      const err = /** @type {Record<string, unknown>} */ message.error;
+      return String(err.message || "Unknown error");
+    }
+    if ("result" in message && message.result && typeof message.result === "object") {
+      const result = /** @type {Record<string, unknown>} */ message.result;
+      if (result.isError === true) {
+        return Array.isArray(result.content) ? result.content.map(item => String(item?.text ?? "")).join("\n") : "tool returned isError=true";
+      }
+      return "";
+    }
+  }
+  return "";
+}
+
+/**
+ * @param {string} configPath
+ * @returns {Record<string, Record<string, unknown>>}
+ */
+function loadServers(configPath) {
+  if (!configPath || !fs.existsSync(configPath)) {
+    return {};
+  }
+  const config = JSON.parse(fs.readFileSync(configPath, "utf8"));
+  return config && typeof config.mcpServers === "object" && config.mcpServers ? config.mcpServers : {};
+}
+
+async function main() {
+  const core = global.core;
+  const transcriptPath = process.env.GH_AW_REPLAY_TRANSCRIPT || "";
+  if (!transcriptPath) {
+    core.setFailed("GH_AW_REPLAY_TRANSCRIPT environment variable is required");
+    return;
+  }
+
+  const transcript = loadTranscript(transcriptPath);
+  /** @type {Record<string, string[]>} */
+  const policy = JSON.parse(process.env.GH_AW_REPLAY_ALLOWED_TOOLS || "{}");
+  const servers = loadServers(process.env.GH_AW_REPLAY_MCP_CONFIG || "");
+
+  core.info(`Replaying ${transcript.calls.length} tool call(s) from ${transcriptPath}`);
+  if (transcript.run_url) {
+    core.info(`Recorded from ${transcript.run_url}`);
+  }
+
+  const violations = findPolicyViolations(transcript.calls, policy, servers);
+  if (violations.length > 0) {
+    for (const violation of violations) {
+      core.error(violation);
+    }
+    core.setFailed(`${violations.length} recorded call(s) are not permitted by the compiled workflow configuration`);
+    return;
+  }
+
+  ensureAuditDir();
+
+  /** @type {Map<string, string>} */
+  const sessions = new Map();
+  let failedCalls = 0;
+  for (const [index, call] of transcript.calls.entries()) {
+    const server = /** @type {Record<string, unknown>} */ servers[call.server];
+    const serverUrl = String(server.url || "");
+    const headers = /** @type {Record<string, string>} */ server.headers || {};
+    const apiKey = headers.Authorization || "";
+
+    if (!sessions.has(call.server)) {
+      const sessionId = await mcpInitialize(serverUrl, apiKey, call.server);
+      await mcpNotifyInitialized(serverUrl, apiKey, sessionId, call.server);
+      sessions.set(call.server, sessionId);
+    }
+
+    const resp = await mcpToolsCall(serverUrl, apiKey, sessions.get(call.server) || "", call.tool, call.arguments || {}, call.server);
+    const error = resp.statusCode >= 400 ? `HTTP ${resp.statusCode}` : responseError(resp.body);
+    if (error) {
+      failedCalls++;
+      core.error(`call #${index + 1} ${call.server}.${call.tool} returned an error: ${error}`);
+    }
+  }
+
+  if (failedCalls > 0) {
+    // A recorded call that now fails means the workflow no longer behaves as recorded
+    core.setFailed(`Replay mismatch: ${failedCalls} of ${transcript.calls.length} recorded call(s) returned errors`);
+    return;
+  }
+  core.info(`Replay complete: ${transcript.calls.length} call(s) replayed without errors`);
+}
+
+if (require.main === module) {
+  main().catch(err => {
+    const message = err instanceof Error ? err.message : String(err);
+    global.core.setFailed(`replay_engine: ${message}`);
+  });
+}
+
+module.exports = { loadTranscript, findPolicyViolations, responseError, loadServers, main };
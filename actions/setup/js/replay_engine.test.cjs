@@ -0,0 +1,112 @@
+import { afterEach, beforeEach, describe, expect, it, vi } from "vitest";
+import { mkdtempSync, rmSync, writeFileSync } from "fs";
+import { join } from "path";
+import { tmpdir } from "os";
+import { createRequire } from "module";
+
+import { findPolicyViolations, loadTranscript, responseError } from "./replay_engine.cjs";
+import { transformReplayEntry } from "./convert_gateway_config_replay.cjs";
+
+const req = createRequire(import.meta.url);
+
+describe("replay_engine.cjs", () => {
+  let originalCore;
+  let tmpDir;
+
+  beforeEach(() => {
+    originalCore = global.core;
+    global.core = { info: vi.fn(), warning: vi.fn(), error: vi.fn(), setFailed: vi.fn() };
+    tmpDir = mkdtempSync(join(tmpdir(), "replay-engine-"));
+  });
+
+  afterEach(() => {
+    global.core = originalCore;
+    rmSync(tmpDir, { recursive: true, force: true });
+  });
+
+  it("loads a version 1 transcript", () => {
+    const path = join(tmpDir, "triage.json");
+    writeFileSync(path, JSON.stringify({ version: 1, workflow: "triage", calls: [{ server: "github", tool: "get_issue", arguments: { issue_number: 1 } }] }));
+
+    const transcript = loadTranscript(path);
+    expect(transcript.calls).toHaveLength(1);
+  });
+
+  it("rejects transcripts with an unknown version or malformed calls", () => {
+    const path = join(tmpDir, "bad.json");
+    writeFileSync(path, JSON.stringify({ version: 2, calls: [] }));
+    expect(() => loadTranscript(path)).toThrow("Unsupported transcript version");
+
+    writeFileSync(path, JSON.stringify({ version: 1, calls: [{ server: "github" }] }));
+    expect(() => loadTranscript(path)).toThrow("call #1");
+  });
+
+  it("reports calls that the compiled policy does not permit", () => {
+    const policy = { github: ["get_issue"], safeoutputs: ["*"] };
+    const servers = { github: {}, safeoutputs: {} };
+    const calls = [
+      { server: "github", tool: "get_issue" },
+      { server: "github", tool: "delete_repository" },
+      { server: "safeoutputs", tool: "add_comment" },
+      { server: "playwright", tool: "browser_navigate" },
+    ];
+
+    const violations = findPolicyViolations(calls, policy, servers);
+    expect(violations).toHaveLength(2);
+    expect(violations[0]).toContain("call #2 github.delete_repository");
+    expect(violations[1]).toContain('server "playwright" is not configured');
+  });
+
+  it("reports permitted servers missing from the gateway configuration", () => {
+    const violations = findPolicyViolations([{ server: "github", tool: "get_issue" }], { github: ["*"] }, {});
+    expect(violations[0]).toContain("not available through the MCP gateway");
+  });
+
+  it("extracts errors from tools/call responses", () => {
+    expect(responseError({ jsonrpc: "2.0", id: 2, result: { content: [{ type: "text", text: "ok" }] } })).toBe("");
+    expect(responseError({ jsonrpc: "2.0", id: 2, error: { code: -32600, message: "denied" } })).toBe("denied");
+    expect(responseError('data: {"jsonrpc":"2.0","id":2,"result":{"isError":true,"content":[{"type":"text","text":"boom"}]}}')).toBe("boom");
+  });
+
+  it("fails with the number of recorded calls that returned errors", async () => {
+    const bridge = req("./mcp_cli_bridge.cjs");
+    const spies = [
+      vi.spyOn(bridge, "ensureAuditDir").mockImplementation(() => {}),
+      vi.spyOn(bridge, "mcpInitialize").mockResolvedValue("session-1"),
+      vi.spyOn(bridge, "mcpNotifyInitialized").mockResolvedValue(undefined),
+      vi
+        .spyOn(bridge, "mcpToolsCall")
+        .mockResolvedValueOnce({ statusCode: 200, body: { jsonrpc: "2.0", id: 2, result: { content: [] } } })
+        .mockResolvedValueOnce({ statusCode: 500, body: "" }),
+    ];
+    delete req.cache[req.resolve("./replay_engine.cjs")];
+    const { main } = req("./replay_engine.cjs");
+
+    const transcriptPath = join(tmpDir, "triage.json");
+    writeFileSync(transcriptPath, JSON.stringify({ version: 1, calls: [{ server: "github", tool: "get_issue" }, { server: "github", tool: "get_issue" }] }));
+    const configPath = join(tmpDir, "mcp-config.json");
+    writeFileSync(configPath, JSON.stringify({ mcpServers: { github: { url: "http://localhost:80/mcp/github" } } }));
+    process.env.GH_AW_REPLAY_TRANSCRIPT = transcriptPath;
+    process.env.GH_AW_REPLAY_ALLOWED_TOOLS = JSON.stringify({ github: ["*"] });
+    process.env.GH_AW_REPLAY_MCP_CONFIG = configPath;
+
+    try {
+      await main();
+    } finally {
+      delete process.env.GH_AW_REPLAY_TRANSCRIPT;
+      delete process.env.GH_AW_REPLAY_ALLOWED_TOOLS;
+      delete process.env.GH_AW_REPLAY_MCP_CONFIG;
+      spies.forEach(spy => spy.mockRestore());
+      delete req.cache[req.resolve("./replay_engine.cjs")];
+    }
+
+    expect(global.core.setFailed).toHaveBeenCalledWith("Replay mismatch: 1 of 2 recorded call(s) returned errors");
+  });
+
+  it("rewrites gateway URLs to the host domain", () => {
+    const entry = { type: "http", url: "http://host.docker.internal:80/mcp/github", headers: { Authorization: "key" } };
+    const result = transformReplayEntry(entry, "http://localhost:80");
+    expect(result.url).toBe("http://localhost:80/mcp/github");
+    expect(result.headers).toEqual({ Authorization: "key" });
+  });
+});
@@ -691,6 +691,7 @@ async function main() {
     codex: "convert_gateway_config_codex.cjs",
     claude: "convert_gateway_config_claude.cjs",
     gemini: "convert_gateway_config_gemini.cjs",
+    replay: "convert_gateway_config_replay.cjs",
   };
 
   const converterFile = converters[/** @type {keyof typeof converters} */ engineType];
@@ -0,0 +1,201 @@
+// @ts-check
+/// <reference types="@actions/github-script" />
+
+const { getErrorMessage } = require("./error_helpers.cjs");
+const { ERR_CONFIG } = require("./error_codes.cjs");
+const { sleep } = require("./error_recovery.cjs");
+
+/** Name of the activation step generated by the compiler, followed by " (<priority>)" */
+const BUDGET_STEP_NAME = "Wait for agent concurrency slot";
+
+/** Delay before a run that was admitted confirms its slot, so that runs which started at the same time see each other */
+const SETTLE_DELAY_MS = 10 * 1000;
+
+/** Polling backs off exponentially from the initial to the maximum delay; a random jitter keeps waiting runs out of lockstep */
+const INITIAL_POLL_DELAY_MS = 15 * 1000;
+const MAX_POLL_DELAY_MS = 5 * 60 * 1000;
+const POLL_JITTER_RATIO = 0.25;
+
+/**
+ * Returns true when a job name refers to the given job, including jobs of
+ * reusable workflows, which are reported as "<caller job> / <job>".
+ *
+ * @param {string} jobName
+ * @param {string} name
+ * @returns {boolean}
+ */
+function isJob(jobName, name) {
+  return jobName === name || jobName.endsWith(` / ${name}`);
+}
+
+/**
+ * Returns the priority class encoded in the budget step name, or "normal".
+ *
+ * @param {string} stepName
+ * @returns {string}
+ */
+function stepPriority(stepName) {
+  const match = /\((\w+)\)\s*$/.exec(stepName);
+  return match ? match[1] : "normal";
+}
+
+/**
+ * @typedef {{ id: number, startedAt: number, priority: string }} WaitingRun
+ * @typedef {{ active: number, waiting: WaitingRun[] }} BudgetState
+ */
+
+/**
+ * Reads the state of the repository-wide budget from the in-progress agentic
+ * workflow runs (compiled .lock.yml workflows). A run is:
+ *
+ *   - active while its agent job is running, and from the moment its budget step
+ *     admitted it until the agent job has finished (including while the agent job
+ *     waits for a runner);
+ *   - waiting while its budget step is running, including the current run.
+ *
+ * Runs that have not started any job yet have status "queued" and are skipped, as
+ * they neither hold nor wait for a slot.
+ *
+ * @param {string} owner
+ * @param {string} repo
+ * @param {string} agentJobName
+ * @returns {Promise<BudgetState>}
+ */
+async function readBudgetState(owner, repo, agentJobName) {
+  /** @type {BudgetState} */
+  const state = { active: 0, waiting: [] };
+  const runs = await github.paginate(github.rest.actions.listWorkflowRunsForRepo, { owner, repo, status: "in_progress", per_page: 100 });
+  for (const run of runs) {
+    if (!String(run.path || "").endsWith(".lock.yml")) {
+      continue;
+    }
+    const jobs = await github.paginate(github.rest.actions.listJobsForWorkflowRun, { owner, repo, run_id: run.id, filter: "latest", per_page: 100 });
+    const agent = jobs.find(job => isJob(job.name, agentJobName));
+    const activation = jobs.find(job => isJob(job.name, "activation"));
+    if (!activation || agent?.status === "completed") {
+      continue;
+    }
+    if (agent?.status === "in_progress" || (activation.status === "completed" && activation.conclusion === "success")) {
+      state.active++;
+      continue;
+    }
+    const step = (activation.steps || []).find(step => String(step.name || "").startsWith(BUDGET_STEP_NAME));
+    if (step?.status === "completed" && step.conclusion === "success") {
+      state.active++;
+    } else if (step?.status === "in_progress") {
+      state.waiting.push({ id: run.id, startedAt: Date.parse(run.run_started_at || run.created_at) || 0, priority: stepPriority(step.name) });
+    }
+  }
+  return state;
+}
+
+/**
+ * Decides whether the current run may start its agent job. Waiting runs are
+ * admitted in the order they started, each against the limit of its own priority
+ * class, as if the runs ahead of the current run had already taken their slots.
+ * Every waiting run evaluates the same order, so two runs can never both take the
+ * last slot. While the current run's budget step is not reported as running yet,
+ * the run is placed behind every other waiting run.
+ *
+ * @param {BudgetState} state
+ * @param {number} currentRunId
+ * @param {string} priority
+ * @param {Record<string, number>} limits
+ * @returns {{ admitted: boolean, ahead: number }}
+ */
+function admit(state, currentRunId, priority, limits) {
+  const waiting = [...state.waiting].sort((a, b) => a.startedAt - b.startedAt || a.id - b.id);
+  if (!waiting.some(run => run.id === currentRunId)) {
+    waiting.push({ id: currentRunId, startedAt: Infinity, priority });
+  }
+  let taken = state.active;
+  for (const run of waiting) {
+    const limit = limits[run.priority] ?? limits.normal;
+    if (run.id === currentRunId) {
+      return { admitted: taken < limit, ahead: taken - state.active };
+    }
+    if (taken < limit) {
+      taken++;
+    }
+  }
+  return { admitted: false, ahead: taken - state.active };
+}
+
+/**
+ * Waits until this run is admitted to the repository-wide agent budget. After the
+ * queue timeout the run starts anyway with a warning, so a misconfigured budget can
+ * delay runs but never drop them.
+ *
+ * @param {{ sleep?: (ms: number) => Promise<void>, now?: () => number }} [deps]
+ */
+async function main(deps = {}) {
+  const wait = deps.sleep || sleep;
+  const now = deps.now || Date.now;
+
+  const agentJobName = process.env.GH_AW_AGENT_JOB_NAME || "agent";
+  const priority = process.env.GH_AW_AGENT_PRIORITY || "normal";
+  const limit = parseInt(process.env.GH_AW_AGENT_LIMIT || "", 10);
+  const max = parseInt(process.env.GH_AW_AGENT_MAX || "", 10);
+  const timeoutMinutes = parseInt(process.env.GH_AW_AGENT_QUEUE_TIMEOUT_MINUTES || "60", 10);
+
+  if (isNaN(limit) || limit < 1) {
+    core.setFailed(`${ERR_CONFIG}: Configuration error: GH_AW_AGENT_LIMIT must be a positive number.`);
+    return;
+  }
+
+  /** @type {Record<string, number>} */
+  let limits;
+  try {
+    limits = { normal: limit, ...JSON.parse(process.env.GH_AW_AGENT_LIMITS || "{}"), [priority]: limit };
+  } catch (error) {
+    core.setFailed(`${ERR_CONFIG}: Configuration error: GH_AW_AGENT_LIMITS must be a JSON object: ${getErrorMessage(error)}`);
+    return;
+  }
+
+  const { owner, repo } = context.repo;
+  const currentRunId = Number(context.runId);
+  const started = now();
+  const deadline = started + timeoutMinutes * 60 * 1000;
+  const waited = () => Math.round((now() - started) / 1000);
+  core.info(`Agent concurrency budget: priority=${priority}, limit=${limit} of ${isNaN(max) ? limit : max} agent job(s)`);
+
+  let delay = INITIAL_POLL_DELAY_MS;
+  let settling = false;
+  for (;;) {
+    let state;
+    try {
+      state = await readBudgetState(owner, repo, agentJobName);
+    } catch (error) {
+      // Failing open keeps workflows running when the API is unavailable or rate limited
+      core.warning(`Could not check the agent concurrency budget: ${getErrorMessage(error)}. Starting without waiting.`);
+      core.setOutput("waited_seconds", String(waited()));
+      return;
+    }
+
+    const decision = admit(state, currentRunId, priority, limits);
+    if (decision.admitted) {
+      if (settling) {
+        core.info(`${state.active} agent job(s) active, ${decision.ahead} admitted ahead, below the ${priority} priority limit of ${limit}. Starting after ${waited()}s.`);
+        core.setOutput("waited_seconds", String(waited()));
+        return;
+      }
+      // Confirm the slot once runs that started at the same time are visible in the API
+      settling = true;
+      await wait(SETTLE_DELAY_MS);
+      continue;
+    }
+    settling = false;
+
+    if (now() >= deadline) {
+      core.warning(`Agent concurrency budget still full after ${timeoutMinutes} minute(s) (${state.active} active, ${priority} priority limit ${limit}). Starting anyway.`);
+      core.setOutput("waited_seconds", String(waited()));
+      return;
+    }
+
+    core.info(`${state.active} agent job(s) active and ${decision.ahead} admitted ahead, ${priority} priority limit is ${limit}. Waiting for a slot...`);
+    await wait(delay + Math.floor(Math.random() * delay * POLL_JITTER_RATIO));
+    delay = Math.min(delay * 2, MAX_POLL_DELAY_MS);
+  }
+}
+
+module.exports = { main, readBudgetState, admit };
@@ -0,0 +1,251 @@
+import { describe, it, expect, beforeEach, afterEach, vi } from "vitest";
+
+describe("wait_for_agent_slot.cjs", () => {
+  let mockCore;
+  let mockGithub;
+
+  /**
+   * Builds a paginate mock from a list of runs and a map of run ID to jobs
+   * @param {Array<object>} runs
+   * @param {Record<number, Array<object>>} jobsByRun
+   */
+  function mockRepository(runs, jobsByRun) {
+    mockGithub.paginate.mockImplementation(async (method, params) => {
+      if (method === mockGithub.rest.actions.listWorkflowRunsForRepo) {
+        return runs.filter(run => run.status === params.status);
+      }
+      return jobsByRun[params.run_id] || [];
+    });
+  }
+
+  const activated = [{ name: "activation", status: "completed", conclusion: "success" }];
+
+  beforeEach(() => {
+    mockCore = {
+      info: vi.fn(),
+      warning: vi.fn(),
+      setFailed: vi.fn(),
+      setOutput: vi.fn(),
+    };
+    mockGithub = {
+      rest: {
+        actions: {
+          listWorkflowRunsForRepo: vi.fn(),
+          listJobsForWorkflowRun: vi.fn(),
+        },
+      },
+      paginate: vi.fn(),
+    };
+
+    global.core = mockCore;
+    global.github = mockGithub;
+    global.context = { repo: { owner: "test-owner", repo: "test-repo" }, runId: 1 };
+
+    process.env.GH_AW_AGENT_PRIORITY = "low";
+    process.env.GH_AW_AGENT_LIMIT = "2";
+    process.env.GH_AW_AGENT_LIMITS = '{"high":4,"normal":3,"low":2}';
+    process.env.GH_AW_AGENT_MAX = "4";
+    process.env.GH_AW_AGENT_QUEUE_TIMEOUT_MINUTES = "1";
+
+    vi.resetModules();
+  });
+
+  afterEach(() => {
+    vi.clearAllMocks();
+    delete global.core;
+    delete global.github;
+    delete global.context;
+    delete process.env.GH_AW_AGENT_PRIORITY;
+    delete process.env.GH_AW_AGENT_LIMIT;
+    delete process.env.GH_AW_AGENT_LIMITS;
+    delete process.env.GH_AW_AGENT_MAX;
+    delete process.env.GH_AW_AGENT_QUEUE_TIMEOUT_MINUTES;
+  });
+
+  /** Activation job of a run whose budget step is still waiting */
+  const waitingFor = priority => [
+    { name: "activation", status: "in_progress", steps: [{ name: `Wait for agent concurrency slot (${priority})`, status: "in_progress" }] },
+    { name: "agent", status: "queued" },
+  ];
+
+  it("reads active and waiting runs from in-progress agentic workflow runs", async () => {
+    mockRepository(
+      [
+        { id: 1, status: "in_progress", path: ".github/workflows/self.lock.yml", run_started_at: "2025-06-01T10:00:03Z" },
+        { id: 2, status: "in_progress", path: ".github/workflows/a.lock.yml" },
+        { id: 3, status: "in_progress", path: ".github/workflows/b.lock.yml" },
+        { id: 4, status: "in_progress", path: ".github/workflows/c.lock.yml", run_started_at: "2025-06-01T10:00:01Z" },
+        { id: 5, status: "in_progress", path: ".github/workflows/ci.yml" },
+        { id: 6, status: "in_progress", path: ".github/workflows/d.lock.yml" },
+        { id: 7, status: "queued", path: ".github/workflows/e.lock.yml" },
+      ],
+      {
+        1: waitingFor("low"),
+        2: [...activated, { name: "agent", status: "in_progress" }],
+        3: [...activated, { name: "agent", status: "queued" }],
+        4: waitingFor("high"),
+        5: [{ name: "agent", status: "in_progress" }],
+        6: [{ name: "activation", status: "in_progress", steps: [{ name: "Wait for agent concurrency slot (normal)", status: "completed", conclusion: "success" }] }],
+        7: [...activated, { name: "agent", status: "queued" }],
+      }
+    );
+
+    const { readBudgetState } = await import("./wait_for_agent_slot.cjs");
+    const state = await readBudgetState("test-owner", "test-repo", "agent");
+
+    // Run 6 was admitted and is finishing its activation job, ci.yml is not an agentic workflow and only in-progress runs are listed
+    expect(state.active).toBe(3);
+    expect(state.waiting).toEqual([
+      { id: 1, startedAt: Date.parse("2025-06-01T10:00:03Z"), priority: "low" },
+      { id: 4, startedAt: Date.parse("2025-06-01T10:00:01Z"), priority: "high" },
+    ]);
+    expect(mockGithub.paginate).toHaveBeenCalledTimes(7);
+  });
+
+  it("admits waiting runs in start order so only one run takes the last slot", async () => {
+    const { admit } = await import("./wait_for_agent_slot.cjs");
+    const limits = { high: 3, normal: 2, low: 2 };
+    const state = {
+      active: 1,
+      waiting: [
+        { id: 11, startedAt: 2, priority: "normal" },
+        { id: 10, startedAt: 1, priority: "normal" },
+      ],
+    };
+
+    expect(admit(state, 10, "normal", limits)).toEqual({ admitted: true, ahead: 0 });
+    expect(admit(state, 11, "normal", limits)).toEqual({ admitted: false, ahead: 1 });
+  });
+
+  it("does not let a waiting lower priority run block a higher priority run", async () => {
+    const { admit } = await import("./wait_for_agent_slot.cjs");
+    const limits = { high: 3, normal: 2, low: 1 };
+    const state = {
+      active: 1,
+      waiting: [
+        { id: 10, startedAt: 1, priority: "low" },
+        { id: 11, startedAt: 2, priority: "high" },
+      ],
+    };
+
+    expect(admit(state, 10, "low", limits).admitted).toBe(false);
+    expect(admit(state, 11, "high", limits).admitted).toBe(true);
+  });
+
+  it("places the current run last until its budget step is visible", async () => {
+    const { admit } = await import("./wait_for_agent_slot.cjs");
+    const state = { active: 1, waiting: [{ id: 10, startedAt: 5, priority: "normal" }] };
+
+    expect(admit(state, 1, "normal", { normal: 2 })).toEqual({ admitted: false, ahead: 1 });
+  });
+
+  it("starts after confirming the slot when the budget has room", async () => {
+    mockRepository([{ id: 2, status: "in_progress", path: ".github/workflows/a.lock.yml" }], { 2: [...activated, { name: "agent", status: "in_progress" }] });
+    const sleep = vi.fn();
+
+    const { main } = await import("./wait_for_agent_slot.cjs");
+    await main({ sleep });
+
+    expect(sleep).toHaveBeenCalledTimes(1);
+    expect(mockCore.setOutput).toHaveBeenCalledWith("waited_seconds", "0");
+  });
+
+  it("waits with backoff until a slot frees up", async () => {
+    const jobs = { 2: [...activated, { name: "agent", status: "in_progress" }], 3: [...activated, { name: "agent", status: "in_progress" }] };
+    mockRepository(
+      [
+        { id: 2, status: "in_progress", path: ".github/workflows/a.lock.yml" },
+        { id: 3, status: "in_progress", path: ".github/workflows/b.lock.yml" },
+      ],
+      jobs
+    );
+    const delays = [];
+    const sleep = vi.fn().mockImplementation(async ms => {
+      delays.push(ms);
+      if (delays.length === 2) {
+        jobs[3] = [...activated, { name: "agent", status: "completed" }];
+      }
+    });
+
+    const { main } = await import("./wait_for_agent_slot.cjs");
+    await main({ sleep });
+
+    // Two backoff delays, then the settle delay before starting
+    expect(sleep).toHaveBeenCalledTimes(3);
+    expect(delays[1]).toBeGreaterThanOrEqual(2 * delays[0] * 0.8);
+    expect(mockCore.info).toHaveBeenCalledWith(expect.stringContaining("Waiting for a slot"));
+    expect(mockCore.warning).not.toHaveBeenCalled();
+  });
+
+  it("keeps waiting when the slot is taken while settling", async () => {
+    const jobs = { 2: [...activated, { name: "agent", status: "in_progress" }] };
+    mockRepository(
+      [
+        { id: 2, status: "in_progress", path: ".github/workflows/a.lock.yml" },
+        { id: 3, status: "in_progress", path: ".github/workflows/b.lock.yml" },
+      ],
+      jobs
+    );
+    const sleep = vi.fn().mockImplementation(async () => {
+      if (sleep.mock.calls.length === 1) {
+        jobs[3] = [...activated, { name: "agent", status: "in_progress" }];
+      } else {
+        jobs[3] = [...activated, { name: "agent", status: "completed" }];
+      }
+    });
+
+    const { main } = await import("./wait_for_agent_slot.cjs");
+    await main({ sleep });
+
+    expect(sleep).toHaveBeenCalledTimes(3);
+    expect(mockCore.info).toHaveBeenCalledWith(expect.stringContaining("Waiting for a slot"));
+  });
+
+  it("starts with a warning after the queue timeout", async () => {
+    mockRepository(
+      [
+        { id: 2, status: "in_progress", path: ".github/workflows/a.lock.yml" },
+        { id: 3, status: "in_progress", path: ".github/workflows/b.lock.yml" },
+      ],
+      { 2: [...activated, { name: "agent", status: "in_progress" }], 3: [...activated, { name: "agent", status: "in_progress" }] }
+    );
+    let clock = 0;
+    const sleep = vi.fn().mockImplementation(async ms => {
+      clock += ms;
+    });
+
+    const { main } = await import("./wait_for_agent_slot.cjs");
+    await main({ sleep, now: () => clock });
+
+    expect(mockCore.warning).toHaveBeenCalledWith(expect.stringContaining("Starting anyway"));
+    expect(mockCore.setFailed).not.toHaveBeenCalled();
+  });
+
+  it("fails open when the API is unavailable", async () => {
+    mockGithub.paginate.mockRejectedValue(new Error("API rate limit exceeded"));
+
+    const { main } = await import("./wait_for_agent_slot.cjs");
+    await main({ sleep: vi.fn() });
+
+    expect(mockCore.warning).toHaveBeenCalledWith(expect.stringContaining("Starting without waiting"));
+    expect(mockCore.setFailed).not.toHaveBeenCalled();
+  });
+
+  it("fails on an invalid limit", async () => {
+    process.env.GH_AW_AGENT_LIMIT = "0";
+
+    const { main } = await import("./wait_for_agent_slot.cjs");
+    await main({ sleep: vi.fn() });
+
+    expect(mockCore.setFailed).toHaveBeenCalledWith(expect.stringContaining("GH_AW_AGENT_LIMIT"));
+  });
+
+  it("fails on invalid class limits", async () => {
+    process.env.GH_AW_AGENT_LIMITS = "{high";
+
+    const { main } = await import("./wait_for_agent_slot.cjs");
+    await main({ sleep: vi.fn() });
+
+    expect(mockCore.setFailed).toHaveBeenCalledWith(expect.stringContaining("GH_AW_AGENT_LIMITS"));
+  });
+});
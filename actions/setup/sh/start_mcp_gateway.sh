@@ -413,6 +413,10 @@ case "$ENGINE_TYPE" in
     echo "Using Crush converter..."
     bash ${RUNNER_TEMP}/gh-aw/actions/convert_gateway_config_crush.sh
     ;;
+  replay)
+    echo "Using Replay converter..."
+    node "${RUNNER_TEMP}/gh-aw/actions/convert_gateway_config_replay.cjs"
+    ;;
   *)
     echo "No agent-specific converter found for engine: $ENGINE_TYPE"
     echo "Using gateway output directly"
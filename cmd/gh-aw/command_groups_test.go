@@ -26,6 +26,7 @@ func TestCommandGroupAssignments(t *testing.T) {
 		{name: "deploy command in setup group", commandName: "deploy", expectedGroup: "setup", shouldHaveGroup: true},
 		{name: "upgrade command in setup group", commandName: "upgrade", expectedGroup: "setup", shouldHaveGroup: true},
 		{name: "secrets command in setup group", commandName: "secrets", expectedGroup: "setup", shouldHaveGroup: true},
+		{name: "convert command in setup group", commandName: "convert", expectedGroup: "setup", shouldHaveGroup: true},
 
 		// Development Commands
 		{name: "compile command in development group", commandName: "compile", expectedGroup: "development", shouldHaveGroup: true},
@@ -33,6 +34,7 @@ func TestCommandGroupAssignments(t *testing.T) {
 		{name: "mcp command in development group", commandName: "mcp", expectedGroup: "development", shouldHaveGroup: true},
 		{name: "fix command in development group", commandName: "fix", expectedGroup: "development", shouldHaveGroup: true},
 		{name: "domains command in development group", commandName: "domains", expectedGroup: "development", shouldHaveGroup: true},
+		{name: "models command in development group", commandName: "models", expectedGroup: "development", shouldHaveGroup: true},
 
 		// Execution Commands
 		{name: "run command in execution group", commandName: "run", expectedGroup: "execution", shouldHaveGroup: true},
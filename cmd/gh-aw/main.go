@@ -282,6 +282,9 @@ Examples:
 		poutine, _ := cmd.Flags().GetBool("poutine")
 		actionlint, _ := cmd.Flags().GetBool("actionlint")
 		runnerGuard, _ := cmd.Flags().GetBool("runner-guard")
+		sarifFile, _ := cmd.Flags().GetString("sarif")
+		sarifBaseline, _ := cmd.Flags().GetString("sarif-baseline")
+		updateSARIFBaseline, _ := cmd.Flags().GetBool("update-sarif-baseline")
 		jsonOutput, _ := cmd.Flags().GetBool("json")
 		fix, _ := cmd.Flags().GetBool("fix")
 		stats, _ := cmd.Flags().GetBool("stats")
@@ -353,6 +356,9 @@ Examples:
 			ValidateImages:         validateImages,
 			PriorManifestFile:      priorManifestFile,
 			GHESCompat:             ghes,
+			SARIFFile:              sarifFile,
+			SARIFBaseline:          sarifBaseline,
+			UpdateSARIFBaseline:    updateSARIFBaseline,
 		}
 		if _, err := cli.CompileWorkflows(cmd.Context(), config); err != nil {
 			// Return error as-is without additional formatting
@@ -393,7 +399,8 @@ Examples:
   gh aw run daily-perf-improver -F name=value -F env=prod  # Pass workflow inputs
   gh aw run daily-perf-improver --push  # Commit and push workflow files before running
   gh aw run daily-perf-improver --dry-run  # Validate without actually running
-  gh aw run daily-perf-improver --json  # Output results in JSON format`,
+  gh aw run daily-perf-improver --json  # Output results in JSON format
+  gh aw run daily-perf-improver --follow  # Stream agent turns, tool calls and errors live`,
 	Args: cobra.ArbitraryArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		repeatCount, _ := cmd.Flags().GetInt("repeat")
@@ -407,6 +414,7 @@ Examples:
 		dryRun, _ := cmd.Flags().GetBool("dry-run")
 		jsonOutput, _ := cmd.Flags().GetBool("json")
 		approveRun, _ := cmd.Flags().GetBool("approve")
+		follow, _ := cmd.Flags().GetBool("follow")
 
 		if err := validateEngine(engineOverride); err != nil {
 			return err
@@ -429,6 +437,9 @@ Examples:
 			if len(inputs) > 0 {
 				return errors.New("workflow inputs cannot be specified in interactive mode (they will be collected interactively)")
 			}
+			if follow {
+				return errors.New("--follow flag is not supported in interactive mode")
+			}
 
 			return cli.RunWorkflowInteractively(cmd.Context(), verboseFlag, repoOverride, refOverride, autoMergePRs, push, engineOverride, dryRun)
 		}
@@ -446,6 +457,7 @@ Examples:
 			DryRun:         dryRun,
 			JSON:           jsonOutput,
 			Approve:        approveRun,
+			Follow:         follow,
 		})
 	},
 }
@@ -699,6 +711,9 @@ Use "` + string(constants.CLIExtensionPrefix) + ` help all" to show help for all
 	compileCmd.Flags().Bool("poutine", false, "Run poutine security scanner on generated .lock.yml files")
 	compileCmd.Flags().Bool("actionlint", false, "Run actionlint linter on generated .lock.yml files")
 	compileCmd.Flags().Bool("runner-guard", false, "Run runner-guard taint analysis scanner on generated .lock.yml files (uses Docker image "+cli.RunnerGuardImage+")")
+	compileCmd.Flags().String("sarif", "", "Write findings from all enabled security scanners and compiler warnings to a single SARIF file, mapped to .md source lines where possible")
+	compileCmd.Flags().String("sarif-baseline", "", "Security baseline file of accepted findings; findings not in the baseline fail compilation (default: .github/aw/security-baseline.json)")
+	compileCmd.Flags().Bool("update-sarif-baseline", false, "Accept all current findings by rewriting the security baseline file")
 	compileCmd.Flags().Bool("fix", false, "Apply automatic codemod fixes to workflows before compiling")
 	compileCmd.Flags().BoolP("json", "j", false, "Output results in JSON format")
 	compileCmd.Flags().Bool("stats", false, "Display statistics table sorted by workflow file size (shows jobs, steps, scripts, and shells)")
@@ -748,6 +763,7 @@ Use "` + string(constants.CLIExtensionPrefix) + ` help all" to show help for all
 	runCmd.Flags().Bool("push", false, "Commit and push workflow files (including transitive imports) before running")
 	runCmd.Flags().Bool("dry-run", false, "Validate workflow without actually triggering execution on GitHub Actions")
 	runCmd.Flags().BoolP("json", "j", false, "Output results in JSON format")
+	runCmd.Flags().Bool("follow", false, "Stream the run's agent turns, tool calls and errors until it completes, then show the audit summary (Ctrl-C cancels the run)")
 	runCmd.Flags().Bool("approve", false, "Approve all safe update changes. When strict mode is active (the default), the compiler emits warnings for new restricted secrets or unapproved action additions/removals not present in the existing gh-aw-manifest. Use this flag to approve and skip safe update enforcement")
 	// Register completions for run command
 	runCmd.ValidArgsFunction = cli.CompleteWorkflowNames
@@ -764,11 +780,12 @@ Use "` + string(constants.CLIExtensionPrefix) + ` help all" to show help for all
 	logsCmd := cli.NewLogsCommand()
 	auditCmd := cli.NewAuditCommand()
 	healthCmd := cli.NewHealthCommand()
+	dashboardCmd := cli.NewDashboardCommand()
 	mcpServerCmd := cli.NewMCPServerCommand()
 	prCmd := cli.NewPRCommand()
 	secretsCmd := cli.NewSecretsCommand()
 	fixCmd := cli.NewFixCommand()
-	upgradeCmd := cli.NewUpgradeCommand()
+	upgradeCmd := cli.NewUpgradeCommand(validateEngine)
 	completionCmd := cli.NewCompletionCommand()
 	hashCmd := cli.NewHashCommand()
 	projectCmd := cli.NewProjectCommand()
@@ -778,6 +795,12 @@ Use "` + string(constants.CLIExtensionPrefix) + ` help all" to show help for all
 	domainsCmd := cli.NewDomainsCommand()
 	experimentsCmd := cli.NewExperimentsCommand()
 	forecastCmd := cli.NewForecastCommand()
+	lockCmd := cli.NewLockCommand()
+	verifyPinsCmd := cli.NewVerifyPinsCommand()
+	graphCmd := cli.NewGraphCommand()
+	lifecycleCmd := cli.NewLifecycleCommand()
+	convertCmd := cli.NewConvertCommand()
+	modelsCmd := cli.NewModelsCommand()
 
 	// Assign commands to groups
 	// Setup Commands
@@ -790,6 +813,7 @@ Use "` + string(constants.CLIExtensionPrefix) + ` help all" to show help for all
 	deployCmd.GroupID = "setup"
 	upgradeCmd.GroupID = "setup"
 	secretsCmd.GroupID = "setup"
+	convertCmd.GroupID = "setup"
 
 	// Development Commands
 	compileCmd.GroupID = "development"
@@ -798,6 +822,9 @@ Use "` + string(constants.CLIExtensionPrefix) + ` help all" to show help for all
 	mcpCmd.GroupID = "development"
 	fixCmd.GroupID = "development"
 	domainsCmd.GroupID = "development"
+	modelsCmd.GroupID = "development"
+	lockCmd.GroupID = "development"
+	verifyPinsCmd.GroupID = "development"
 	statusCmd.GroupID = "analysis"
 	listCmd.GroupID = "analysis"
 
@@ -811,9 +838,12 @@ Use "` + string(constants.CLIExtensionPrefix) + ` help all" to show help for all
 	logsCmd.GroupID = "analysis"
 	auditCmd.GroupID = "analysis"
 	healthCmd.GroupID = "analysis"
+	dashboardCmd.GroupID = "analysis"
 	checksCmd.GroupID = "analysis"
 	experimentsCmd.GroupID = "analysis"
 	forecastCmd.GroupID = "analysis"
+	graphCmd.GroupID = "analysis"
+	lifecycleCmd.GroupID = "analysis"
 
 	// Utilities
 	mcpServerCmd.GroupID = "utilities"
@@ -844,6 +874,7 @@ Use "` + string(constants.CLIExtensionPrefix) + ` help all" to show help for all
 	rootCmd.AddCommand(logsCmd)
 	rootCmd.AddCommand(auditCmd)
 	rootCmd.AddCommand(healthCmd)
+	rootCmd.AddCommand(dashboardCmd)
 	rootCmd.AddCommand(outcomesCmd)
 	rootCmd.AddCommand(checksCmd)
 	rootCmd.AddCommand(mcpCmd)
@@ -860,6 +891,12 @@ Use "` + string(constants.CLIExtensionPrefix) + ` help all" to show help for all
 	rootCmd.AddCommand(domainsCmd)
 	rootCmd.AddCommand(experimentsCmd)
 	rootCmd.AddCommand(forecastCmd)
+	rootCmd.AddCommand(lockCmd)
+	rootCmd.AddCommand(verifyPinsCmd)
+	rootCmd.AddCommand(graphCmd)
+	rootCmd.AddCommand(lifecycleCmd)
+	rootCmd.AddCommand(convertCmd)
+	rootCmd.AddCommand(modelsCmd)
 
 	// Fix help flag descriptions for all subcommands to be consistent with the
 	// root command ("Show help for gh aw" vs the Cobra default "help for [cmd]").
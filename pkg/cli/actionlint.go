@@ -29,6 +29,8 @@ type actionlintRunOptions struct {
 	// IgnorePatterns contains regular expressions passed to actionlint via
 	// repeated -ignore flags to suppress known false positives.
 	IgnorePatterns []string
+	// Findings collects the reported errors for the SARIF report; nil discards them.
+	Findings *SecurityFindingCollector
 }
 
 // buildActionlintIntegrationStatus returns a human-readable description of the
@@ -212,10 +214,11 @@ func getActionlintVersion(ctx context.Context) (string, error) {
 
 // runActionlintOnFiles runs the actionlint linter on one or more .lock.yml files using Docker.
 // The provided context allows caller-driven cancellation.
-func runActionlintOnFiles(ctx context.Context, lockFiles []string, verbose bool, strict bool) error {
+func runActionlintOnFiles(ctx context.Context, lockFiles []string, verbose bool, strict bool, collector *SecurityFindingCollector) error {
 	return runActionlintOnFilesWithOptions(ctx, lockFiles, verbose, strict, actionlintRunOptions{
 		IncludeShellcheck: true,
 		IncludePyflakes:   true,
+		Findings:          collector,
 	})
 }
 
@@ -327,7 +330,7 @@ func runActionlintOnFilesWithOptions(ctx context.Context, lockFiles []string, ve
 	}
 
 	// Parse and reformat the output, get total error count and error details
-	totalErrors, errorsByKind, parseErr := parseAndDisplayActionlintOutput(stdout.String(), verbose)
+	totalErrors, errorsByKind, parseErr := parseAndDisplayActionlintOutput(stdout.String(), verbose, options.Findings)
 	if parseErr != nil {
 		actionlintLog.Printf("Failed to parse actionlint output: %v", parseErr)
 		// Track this as an integration error: output was produced but could not be parsed
@@ -408,7 +411,7 @@ func runActionlintOnFilesWithOptions(ctx context.Context, lockFiles []string, ve
 
 // parseAndDisplayActionlintOutput parses actionlint JSON output and displays it in the desired format
 // Returns the total number of errors found and a breakdown by kind
-func parseAndDisplayActionlintOutput(stdout string, verbose bool) (int, map[string]int, error) {
+func parseAndDisplayActionlintOutput(stdout string, verbose bool, collector *SecurityFindingCollector) (int, map[string]int, error) {
 	// Skip if no output
 	if stdout == "" || strings.TrimSpace(stdout) == "" {
 		actionlintLog.Print("No actionlint output to parse")
@@ -472,6 +475,17 @@ func parseAndDisplayActionlintOutput(stdout string, verbose bool) (int, map[stri
 			Context: context,
 		}
 
+		collector.Add(SecurityFinding{
+			Tool:    "actionlint",
+			RuleID:  err.Kind,
+			Level:   errorType,
+			Message: err.Message,
+			HelpURI: getActionlintDocsURL(err.Kind),
+			File:    err.Filepath,
+			Line:    err.Line,
+			Column:  err.Column,
+		})
+
 		fmt.Fprint(os.Stderr, console.FormatError(compilerErr))
 	}
 
@@ -101,7 +101,7 @@ func TestParseAndDisplayActionlintOutput(t *testing.T) {
 			var err error
 
 			output := testutil.CaptureStderr(t, func() {
-				count, kinds, err = parseAndDisplayActionlintOutput(tt.stdout, tt.verbose)
+				count, kinds, err = parseAndDisplayActionlintOutput(tt.stdout, tt.verbose, nil)
 			})
 
 			if tt.expectError {
@@ -0,0 +1,597 @@
+// This file contains user-defined agentic assessment rules loaded from
+// .github/aw/assessments.yml.
+//
+// # Assessment Rules
+//
+// Each rule is a condition over a run's metrics, tool calls, firewall events and
+// safe outputs. When the condition holds, the rule produces an AgenticAssessment
+// that is reported by audit, included in the logs JSON output and considered by
+// episode escalation:
+//
+//	rules:
+//	  - id: excessive-bash
+//	    title: Excessive shell usage
+//	    when: count(tools, 'bash*') > 40
+//	    severity: medium
+//	    recommendation: Move repeated shell work into deterministic steps.
+//	  - id: force-push
+//	    when: any(bash_commands, '*push --force*', '*push -f*')
+//	    severity: high
+//	  - id: non-docs-fetch
+//	    when: any(firewall.allowed_domains, '*', '!docs.*', '!*.github.com')
+//	    severity: low
+//
+// Conditions combine comparisons with &&, || and ! and parentheses. Operands are
+// numbers, quoted strings, variables such as turns or agentic_fraction, and the
+// collection functions count(collection, patterns...) and any(collection, patterns...).
+// Patterns are case-insensitive globs in which * also matches /; a leading !
+// excludes matches. Bash calls appear in tools with a shortened command, so rules
+// about commands match the full commands in bash_commands.
+
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/gitutil"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/goccy/go-yaml"
+)
+
+var assessmentRulesLog = logger.New("cli:assessment_rules")
+
+// AssessmentRulesPath is the location of the assessment rules file relative to the repository root.
+const AssessmentRulesPath = ".github/aw/assessments.yml"
+
+// assessmentSeverities lists the severities a rule may assign, from lowest to highest.
+var assessmentSeverities = []string{"info", "low", "medium", "high"}
+
+// AssessmentRulesFile is the parsed assessment rules file.
+type AssessmentRulesFile struct {
+	Rules []AssessmentRule `yaml:"rules"`
+}
+
+// AssessmentRule turns a condition over a run into an agentic assessment.
+type AssessmentRule struct {
+	ID             string `yaml:"id"`
+	Title          string `yaml:"title,omitempty"`
+	When           string `yaml:"when"`
+	Severity       string `yaml:"severity"`
+	Category       string `yaml:"category,omitempty"`
+	Summary        string `yaml:"summary,omitempty"`
+	Recommendation string `yaml:"recommendation,omitempty"`
+
+	condition workflow.ConditionNode
+}
+
+// assessmentRuleInput is the run data that rule conditions are evaluated against.
+type assessmentRuleInput struct {
+	numbers     map[string]float64
+	strings     map[string]string
+	collections map[string]map[string]int
+}
+
+// ParseAssessmentRules parses and validates the content of an assessment rules file.
+func ParseAssessmentRules(content []byte) ([]AssessmentRule, error) {
+	var file AssessmentRulesFile
+	if err := yaml.Unmarshal(content, &file); err != nil {
+		return nil, fmt.Errorf("invalid assessment rules: %w", err)
+	}
+
+	seen := make(map[string]bool, len(file.Rules))
+	schema := newAssessmentRuleInput(ProcessedRun{}, MetricsData{}, nil, nil, nil, nil)
+	for i := range file.Rules {
+		rule := &file.Rules[i]
+		if rule.ID == "" {
+			return nil, fmt.Errorf("assessment rule %d: id is required", i+1)
+		}
+		if seen[rule.ID] {
+			return nil, fmt.Errorf("assessment rule %s: duplicate id", rule.ID)
+		}
+		seen[rule.ID] = true
+		if rule.Severity == "" {
+			rule.Severity = "medium"
+		}
+		if !slices.Contains(assessmentSeverities, rule.Severity) {
+			return nil, fmt.Errorf("assessment rule %s: invalid severity '%s' (valid: %s)", rule.ID, rule.Severity, strings.Join(assessmentSeverities, ", "))
+		}
+		if strings.TrimSpace(rule.When) == "" {
+			return nil, fmt.Errorf("assessment rule %s: when is required", rule.ID)
+		}
+		condition, err := workflow.ParseExpression(rule.When)
+		if err != nil {
+			return nil, fmt.Errorf("assessment rule %s: invalid condition: %w", rule.ID, err)
+		}
+		rule.condition = condition
+		// Evaluating every comparison against an empty run validates operands, functions and variable names
+		if err := workflow.VisitExpressionTree(condition, func(expr *workflow.ExpressionNode) error {
+			_, err := schema.evaluateComparison(expr.Expression, nil)
+			return err
+		}); err != nil {
+			return nil, fmt.Errorf("assessment rule %s: %w", rule.ID, err)
+		}
+	}
+
+	assessmentRulesLog.Printf("Parsed %d assessment rules", len(file.Rules))
+	return file.Rules, nil
+}
+
+// LoadAssessmentRules reads the assessment rules file from the repository root.
+// It returns no rules when the file does not exist.
+func LoadAssessmentRules(repoRoot string) ([]AssessmentRule, error) {
+	rulesPath := filepath.Join(repoRoot, AssessmentRulesPath)
+	content, err := os.ReadFile(rulesPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", AssessmentRulesPath, err)
+	}
+	rules, err := ParseAssessmentRules(content)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", AssessmentRulesPath, err)
+	}
+	return rules, nil
+}
+
+// assessmentRulesCacheState holds the rules of the current repository, loaded once per process.
+type assessmentRulesCacheState struct {
+	mu    sync.Mutex
+	rules []AssessmentRule
+	done  bool
+}
+
+var repoAssessmentRulesCache assessmentRulesCacheState
+
+// repoAssessmentRules returns the assessment rules of the current repository. Load errors
+// are reported once as a warning and disable user-defined rules for the process.
+func repoAssessmentRules() []AssessmentRule {
+	repoAssessmentRulesCache.mu.Lock()
+	defer repoAssessmentRulesCache.mu.Unlock()
+	if repoAssessmentRulesCache.done {
+		return repoAssessmentRulesCache.rules
+	}
+	repoAssessmentRulesCache.done = true
+
+	gitRoot, err := gitutil.FindGitRoot()
+	if err != nil {
+		assessmentRulesLog.Printf("Not in a git repository, skipping assessment rules: %v", err)
+		return nil
+	}
+	rules, err := LoadAssessmentRules(gitRoot)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, console.FormatWarningMessage("Ignoring assessment rules: "+err.Error()))
+		return nil
+	}
+	repoAssessmentRulesCache.rules = rules
+	return rules
+}
+
+// evaluateAssessmentRules returns the assessments produced by the rules whose condition holds for the run.
+func evaluateAssessmentRules(rules []AssessmentRule, processedRun ProcessedRun, metrics MetricsData, toolUsage []ToolUsageInfo, createdItems []CreatedItemReport, domain *TaskDomainInfo, fingerprint *BehaviorFingerprint) []AgenticAssessment {
+	if len(rules) == 0 {
+		return nil
+	}
+	input := newAssessmentRuleInput(processedRun, metrics, toolUsage, createdItems, domain, fingerprint)
+
+	var assessments []AgenticAssessment
+	for _, rule := range rules {
+		var evidence []string
+		matched, err := input.evaluate(rule.condition, &evidence)
+		if err != nil {
+			assessmentRulesLog.Printf("Rule %s failed to evaluate: %v", rule.ID, err)
+			continue
+		}
+		if !matched {
+			continue
+		}
+		summary := rule.Summary
+		if summary == "" {
+			summary = fmt.Sprintf("Assessment rule %s matched this run.", rule.ID)
+			if rule.Title != "" {
+				summary = rule.Title + "."
+			}
+		}
+		assessments = append(assessments, AgenticAssessment{
+			Kind:           rule.ID,
+			Severity:       rule.Severity,
+			Summary:        summary,
+			Evidence:       strings.Join(evidence, "; "),
+			Recommendation: rule.Recommendation,
+			Title:          rule.Title,
+			Category:       rule.Category,
+			Source:         AssessmentRulesPath,
+		})
+	}
+
+	assessmentRulesLog.Printf("Evaluated %d assessment rules for run %d: matched=%d", len(rules), processedRun.Run.DatabaseID, len(assessments))
+	return assessments
+}
+
+// newAssessmentRuleInput collects the variables and collections available to rule conditions.
+func newAssessmentRuleInput(processedRun ProcessedRun, metrics MetricsData, toolUsage []ToolUsageInfo, createdItems []CreatedItemReport, domain *TaskDomainInfo, fingerprint *BehaviorFingerprint) *assessmentRuleInput {
+	run := processedRun.Run
+	input := &assessmentRuleInput{
+		numbers: map[string]float64{
+			"turns":                     float64(metrics.Turns),
+			"token_usage":               float64(metrics.TokenUsage),
+			"estimated_cost":            metrics.EstimatedCost,
+			"action_minutes":            metrics.ActionMinutes,
+			"duration_minutes":          run.Duration.Minutes(),
+			"error_count":               float64(metrics.ErrorCount),
+			"warning_count":             float64(metrics.WarningCount),
+			"tool_types":                float64(len(toolUsage)),
+			"tool_calls":                0,
+			"write_actions":             float64(len(createdItems) + run.SafeItemsCount),
+			"agentic_fraction":          0,
+			"missing_tools":             float64(len(processedRun.MissingTools)),
+			"missing_data":              float64(len(processedRun.MissingData)),
+			"mcp_failures":              float64(len(processedRun.MCPFailures)),
+			"noops":                     float64(len(processedRun.Noops)),
+			"firewall.total_requests":   0,
+			"firewall.allowed_requests": 0,
+			"firewall.blocked_requests": 0,
+		},
+		strings: map[string]string{
+			"workflow":         run.WorkflowName,
+			"event":            run.Event,
+			"conclusion":       run.Conclusion,
+			"task_domain":      "",
+			"execution_style":  "",
+			"tool_breadth":     "",
+			"actuation_style":  "",
+			"resource_profile": "",
+			"dispatch_mode":    "",
+		},
+		collections: map[string]map[string]int{
+			"tools":                    {},
+			"bash_commands":            {},
+			"safe_outputs":             {},
+			"firewall.allowed_domains": {},
+			"firewall.blocked_domains": {},
+		},
+	}
+
+	for _, tool := range toolUsage {
+		input.collections["tools"][tool.Name] += tool.CallCount
+		for _, command := range tool.Commands {
+			input.collections["bash_commands"][command]++
+		}
+		input.numbers["tool_calls"] += float64(tool.CallCount)
+	}
+	for _, item := range createdItems {
+		input.collections["safe_outputs"][item.Type]++
+	}
+	if domain != nil {
+		input.strings["task_domain"] = domain.Name
+	}
+	if fingerprint != nil {
+		input.numbers["agentic_fraction"] = fingerprint.AgenticFraction
+		input.strings["execution_style"] = fingerprint.ExecutionStyle
+		input.strings["tool_breadth"] = fingerprint.ToolBreadth
+		input.strings["actuation_style"] = fingerprint.ActuationStyle
+		input.strings["resource_profile"] = fingerprint.ResourceProfile
+		input.strings["dispatch_mode"] = fingerprint.DispatchMode
+	}
+	if firewall := processedRun.FirewallAnalysis; firewall != nil {
+		input.numbers["firewall.total_requests"] = float64(firewall.TotalRequests)
+		input.numbers["firewall.allowed_requests"] = float64(firewall.AllowedRequests)
+		input.numbers["firewall.blocked_requests"] = float64(firewall.BlockedRequests)
+		if len(firewall.RequestsByDomain) > 0 {
+			for domainName, stats := range firewall.RequestsByDomain {
+				host := stripDomainPort(domainName)
+				if stats.Allowed > 0 {
+					input.collections["firewall.allowed_domains"][host] += stats.Allowed
+				}
+				if stats.Blocked > 0 {
+					input.collections["firewall.blocked_domains"][host] += stats.Blocked
+				}
+			}
+		} else {
+			for _, domainName := range firewall.AllowedDomains {
+				input.collections["firewall.allowed_domains"][stripDomainPort(domainName)]++
+			}
+			for _, domainName := range firewall.BlockedDomains {
+				input.collections["firewall.blocked_domains"][stripDomainPort(domainName)]++
+			}
+		}
+	}
+	return input
+}
+
+// stripDomainPort removes the port from a firewall domain such as "api.github.com:443".
+func stripDomainPort(domain string) string {
+	host, _, _ := strings.Cut(domain, ":")
+	return host
+}
+
+// evaluate evaluates a condition tree. When evidence is non-nil, the operand values of the
+// evaluated comparisons are appended to it as name=value pairs.
+func (in *assessmentRuleInput) evaluate(node workflow.ConditionNode, evidence *[]string) (bool, error) {
+	switch n := node.(type) {
+	case *workflow.AndNode:
+		left, err := in.evaluate(n.Left, evidence)
+		if err != nil || !left {
+			return false, err
+		}
+		return in.evaluate(n.Right, evidence)
+	case *workflow.OrNode:
+		left, err := in.evaluate(n.Left, evidence)
+		if err != nil || left {
+			return left, err
+		}
+		return in.evaluate(n.Right, evidence)
+	case *workflow.NotNode:
+		child, err := in.evaluate(n.Child, evidence)
+		return !child, err
+	case *workflow.ExpressionNode:
+		return in.evaluateComparison(n.Expression, evidence)
+	default:
+		return false, fmt.Errorf("unsupported condition %q", node.Render())
+	}
+}
+
+// assessmentComparisonOperators lists comparison operators, longest first so that
+// ">=" is not read as ">".
+var assessmentComparisonOperators = []string{">=", "<=", "==", "!=", ">", "<"}
+
+// evaluateComparison evaluates a single comparison such as count(tools, 'bash*') > 40,
+// or a bare operand such as any(safe_outputs, 'create_issue').
+func (in *assessmentRuleInput) evaluateComparison(expression string, evidence *[]string) (bool, error) {
+	leftText, operator, rightText := splitAssessmentComparison(expression)
+
+	left, err := in.evaluateOperand(leftText)
+	if err != nil {
+		return false, err
+	}
+	if evidence != nil && !isAssessmentLiteral(leftText) {
+		*evidence = append(*evidence, fmt.Sprintf("%s=%s", leftText, formatAssessmentValue(left)))
+	}
+
+	if operator == "" {
+		switch value := left.(type) {
+		case bool:
+			return value, nil
+		case float64:
+			return value != 0, nil
+		default:
+			return false, fmt.Errorf("%q is not a condition; compare it with ==", leftText)
+		}
+	}
+
+	right, err := in.evaluateOperand(rightText)
+	if err != nil {
+		return false, err
+	}
+
+	switch l := left.(type) {
+	case float64:
+		r, ok := right.(float64)
+		if !ok {
+			return false, fmt.Errorf("cannot compare number %s with %s", leftText, rightText)
+		}
+		switch operator {
+		case ">=":
+			return l >= r, nil
+		case "<=":
+			return l <= r, nil
+		case "==":
+			return l == r, nil
+		case "!=":
+			return l != r, nil
+		case ">":
+			return l > r, nil
+		default:
+			return l < r, nil
+		}
+	case string:
+		r, ok := right.(string)
+		if !ok || (operator != "==" && operator != "!=") {
+			return false, fmt.Errorf("strings support only == and != in %q", expression)
+		}
+		return strings.EqualFold(l, r) == (operator == "=="), nil
+	default:
+		r, ok := right.(bool)
+		if !ok || (operator != "==" && operator != "!=") {
+			return false, fmt.Errorf("conditions support only == and != in %q", expression)
+		}
+		return (left == r) == (operator == "=="), nil
+	}
+}
+
+// splitAssessmentComparison splits an expression at its first comparison operator
+// outside quotes and parentheses.
+func splitAssessmentComparison(expression string) (string, string, string) {
+	depth := 0
+	var quote byte
+	for i := 0; i < len(expression); i++ {
+		ch := expression[i]
+		switch {
+		case quote != 0:
+			if ch == quote {
+				quote = 0
+			}
+		case ch == '\'' || ch == '"':
+			quote = ch
+		case ch == '(':
+			depth++
+		case ch == ')':
+			depth--
+		case depth == 0:
+			for _, operator := range assessmentComparisonOperators {
+				if strings.HasPrefix(expression[i:], operator) {
+					return strings.TrimSpace(expression[:i]), operator, strings.TrimSpace(expression[i+len(operator):])
+				}
+			}
+		}
+	}
+	return strings.TrimSpace(expression), "", ""
+}
+
+// evaluateOperand evaluates a number, quoted string, true/false, variable or function call.
+func (in *assessmentRuleInput) evaluateOperand(text string) (any, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, errors.New("missing operand")
+	}
+	if unquoted, ok := unquoteAssessmentString(text); ok {
+		return unquoted, nil
+	}
+	if number, err := strconv.ParseFloat(text, 64); err == nil {
+		return number, nil
+	}
+	switch text {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	}
+
+	if name, args, ok := strings.Cut(text, "("); ok {
+		if !strings.HasSuffix(args, ")") {
+			return nil, fmt.Errorf("missing ')' in %q", text)
+		}
+		return in.evaluateFunction(strings.TrimSpace(name), strings.TrimSuffix(args, ")"))
+	}
+
+	if !isAssessmentIdentifier(text) {
+		return nil, fmt.Errorf("invalid operand %q", text)
+	}
+	if value, ok := in.numbers[text]; ok {
+		return value, nil
+	}
+	if value, ok := in.strings[text]; ok {
+		return value, nil
+	}
+	if _, ok := in.collections[text]; ok {
+		return nil, fmt.Errorf("%s is a collection; use count(%s) or any(%s, ...)", text, text, text)
+	}
+	return nil, fmt.Errorf("unknown variable %q (available: %s)", text, strings.Join(in.variableNames(), ", "))
+}
+
+// evaluateFunction evaluates count(collection, patterns...) and any(collection, patterns...).
+func (in *assessmentRuleInput) evaluateFunction(name, argsText string) (any, error) {
+	if name != "count" && name != "any" {
+		return nil, fmt.Errorf("unknown function %q (available: count, any)", name)
+	}
+
+	var args []string
+	for arg := range strings.SplitSeq(argsText, ",") {
+		args = append(args, strings.TrimSpace(arg))
+	}
+	collection, ok := in.collections[args[0]]
+	if !ok {
+		return nil, fmt.Errorf("%s: unknown collection %q (available: %s)", name, args[0], strings.Join(slices.Sorted(maps.Keys(in.collections)), ", "))
+	}
+	var patterns []string
+	for _, arg := range args[1:] {
+		pattern, ok := unquoteAssessmentString(arg)
+		if !ok {
+			return nil, fmt.Errorf("%s: pattern %s must be a quoted string", name, arg)
+		}
+		if _, err := path.Match(strings.TrimPrefix(pattern, "!"), ""); err != nil {
+			return nil, fmt.Errorf("%s: invalid pattern %q: %w", name, pattern, err)
+		}
+		patterns = append(patterns, strings.ToLower(pattern))
+	}
+	if name == "any" && len(patterns) == 0 {
+		return nil, errors.New("any: at least one pattern is required")
+	}
+
+	total := 0
+	for item, count := range collection {
+		if matchesAssessmentPatterns(strings.ToLower(item), patterns) {
+			total += count
+		}
+	}
+	if name == "any" {
+		return total > 0, nil
+	}
+	return float64(total), nil
+}
+
+// matchesAssessmentPatterns reports whether value matches at least one include pattern
+// and no exclude (!-prefixed) pattern. With no include patterns every value is included.
+func matchesAssessmentPatterns(value string, patterns []string) bool {
+	included := true
+	for _, pattern := range patterns {
+		if !strings.HasPrefix(pattern, "!") {
+			included = false
+			break
+		}
+	}
+	for _, pattern := range patterns {
+		if exclude, ok := strings.CutPrefix(pattern, "!"); ok {
+			if matchAssessmentGlob(exclude, value) {
+				return false
+			}
+			continue
+		}
+		if matchAssessmentGlob(pattern, value) {
+			included = true
+		}
+	}
+	return included
+}
+
+// matchAssessmentGlob matches a glob in which * also matches /, since commands
+// and paths in collections contain slashes.
+func matchAssessmentGlob(pattern, value string) bool {
+	matched, _ := path.Match(strings.ReplaceAll(pattern, "/", "\x00"), strings.ReplaceAll(value, "/", "\x00"))
+	return matched
+}
+
+// variableNames returns the sorted names of the numeric and string variables.
+func (in *assessmentRuleInput) variableNames() []string {
+	names := slices.Collect(maps.Keys(in.numbers))
+	names = slices.AppendSeq(names, maps.Keys(in.strings))
+	slices.Sort(names)
+	return names
+}
+
+func unquoteAssessmentString(text string) (string, bool) {
+	if len(text) >= 2 && (text[0] == '\'' || text[0] == '"') && text[len(text)-1] == text[0] {
+		return text[1 : len(text)-1], true
+	}
+	return "", false
+}
+
+func isAssessmentIdentifier(text string) bool {
+	for _, ch := range text {
+		if !unicode.IsLetter(ch) && !unicode.IsDigit(ch) && ch != '_' && ch != '.' {
+			return false
+		}
+	}
+	return true
+}
+
+func isAssessmentLiteral(text string) bool {
+	if _, ok := unquoteAssessmentString(text); ok {
+		return true
+	}
+	_, err := strconv.ParseFloat(text, 64)
+	return err == nil || text == "true" || text == "false"
+}
+
+func formatAssessmentValue(value any) string {
+	switch v := value.(type) {
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case string:
+		return strconv.Quote(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,137 @@
+//go:build !integration
+
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/constants"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testAssessmentRules = `rules:
+  - id: excessive-bash
+    title: Excessive shell usage
+    when: count(tools, 'bash*') > 40
+    recommendation: Move repeated shell work into deterministic steps.
+  - id: force-push
+    when: any(bash_commands, '*push --force*', '*push -f*')
+    severity: high
+  - id: non-docs-fetch
+    when: any(firewall.allowed_domains, '*', '!docs.*', '!api.github.com')
+    severity: low
+    category: network
+  - id: issue-flood
+    when: count(safe_outputs, 'create_issue') > 3
+  - id: low-reasoning
+    when: agentic_fraction > 0 && agentic_fraction < 0.2 && !(task_domain == 'research')
+    severity: info
+`
+
+func TestParseAssessmentRules(t *testing.T) {
+	rules, err := ParseAssessmentRules([]byte(testAssessmentRules))
+	require.NoError(t, err)
+	require.Len(t, rules, 5)
+	assert.Equal(t, "medium", rules[0].Severity, "severity should default to medium")
+	assert.Equal(t, "high", rules[1].Severity)
+}
+
+func TestParseAssessmentRules_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		errText string
+	}{
+		{name: "missing id", content: "rules:\n  - when: turns > 1\n", errText: "id is required"},
+		{name: "duplicate id", content: "rules:\n  - id: a\n    when: turns > 1\n  - id: a\n    when: turns > 2\n", errText: "duplicate id"},
+		{name: "invalid severity", content: "rules:\n  - id: a\n    when: turns > 1\n    severity: critical\n", errText: "invalid severity"},
+		{name: "missing condition", content: "rules:\n  - id: a\n", errText: "when is required"},
+		{name: "unknown variable", content: "rules:\n  - id: a\n    when: turns > 1 && bash_calls > 40\n", errText: "unknown variable"},
+		{name: "unknown function", content: "rules:\n  - id: a\n    when: sum(tools) > 1\n", errText: "unknown function"},
+		{name: "unknown collection", content: "rules:\n  - id: a\n    when: count(commands) > 1\n", errText: "unknown collection"},
+		{name: "unquoted pattern", content: "rules:\n  - id: a\n    when: any(tools, bash)\n", errText: "quoted string"},
+		{name: "string ordering", content: "rules:\n  - id: a\n    when: workflow > 'a'\n", errText: "only == and !="},
+		{name: "bare string", content: "rules:\n  - id: a\n    when: workflow\n", errText: "not a condition"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseAssessmentRules([]byte(tt.content))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errText)
+		})
+	}
+}
+
+func TestEvaluateAssessmentRules(t *testing.T) {
+	rules, err := ParseAssessmentRules([]byte(testAssessmentRules))
+	require.NoError(t, err)
+
+	processedRun := ProcessedRun{
+		Run: WorkflowRun{DatabaseID: 7, WorkflowName: "triage"},
+		FirewallAnalysis: &FirewallAnalysis{
+			RequestsByDomain: map[string]DomainRequestStats{
+				"docs.github.com:443":  {Allowed: 3},
+				"api.github.com:443":   {Allowed: 10},
+				"evil.example.com:443": {Blocked: 1},
+			},
+		},
+	}
+	toolUsage := []ToolUsageInfo{
+		{Name: "bash_ls", CallCount: 30},
+		{Name: "bash_cat", CallCount: 15},
+		{Name: "github_issue_read", CallCount: 2},
+	}
+	createdItems := []CreatedItemReport{{Type: "create_issue"}, {Type: "create_issue"}}
+	fingerprint := &BehaviorFingerprint{AgenticFraction: 0.1}
+	domain := &TaskDomainInfo{Name: "triage"}
+
+	assessments := evaluateAssessmentRules(rules, processedRun, MetricsData{}, toolUsage, createdItems, domain, fingerprint)
+	require.Len(t, assessments, 2, "only excessive-bash and low-reasoning should match")
+
+	assert.Equal(t, "excessive-bash", assessments[0].Kind)
+	assert.Equal(t, "medium", assessments[0].Severity)
+	assert.Equal(t, "Excessive shell usage.", assessments[0].Summary, "summary should default to the title")
+	assert.Equal(t, "count(tools, 'bash*')=45", assessments[0].Evidence)
+	assert.Equal(t, AssessmentRulesPath, assessments[0].Source)
+
+	assert.Equal(t, "low-reasoning", assessments[1].Kind)
+	assert.Contains(t, assessments[1].Evidence, "agentic_fraction=0.1")
+
+	processedRun.FirewallAnalysis.RequestsByDomain["pastebin.com:443"] = DomainRequestStats{Allowed: 1}
+	toolUsage = append(toolUsage, ToolUsageInfo{Name: "bash_git push", CallCount: 1, Commands: []string{"git push --force origin feature/assessment-rules"}})
+	createdItems = append(createdItems, CreatedItemReport{Type: "create_issue"}, CreatedItemReport{Type: "create_issue"})
+	domain.Name = "research"
+
+	assessments = evaluateAssessmentRules(rules, processedRun, MetricsData{}, toolUsage, createdItems, domain, fingerprint)
+	kinds := make([]string, 0, len(assessments))
+	for _, assessment := range assessments {
+		kinds = append(kinds, assessment.Kind)
+	}
+	assert.Equal(t, []string{"excessive-bash", "force-push", "non-docs-fetch", "issue-flood"}, kinds)
+}
+
+func TestLoadAssessmentRules(t *testing.T) {
+	repoRoot := t.TempDir()
+	rules, err := LoadAssessmentRules(repoRoot)
+	require.NoError(t, err, "a missing rules file is not an error")
+	assert.Empty(t, rules)
+
+	rulesPath := filepath.Join(repoRoot, AssessmentRulesPath)
+	require.NoError(t, os.MkdirAll(filepath.Dir(rulesPath), constants.DirPermPublic))
+	require.NoError(t, os.WriteFile(rulesPath, []byte("rules:\n  - id: a\n    when: turns >\n"), constants.FilePermPublic))
+	_, err = LoadAssessmentRules(repoRoot)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), AssessmentRulesPath, "errors should name the rules file")
+}
+
+func TestGenerateAgenticAssessmentFindings_RuleAssessment(t *testing.T) {
+	findings := generateAgenticAssessmentFindings([]AgenticAssessment{
+		{Kind: "non-docs-fetch", Severity: "low", Summary: "Fetched a non-docs domain.", Title: "Non-docs fetch", Category: "network", Source: AssessmentRulesPath},
+	})
+	require.Len(t, findings, 1)
+	assert.Equal(t, "Non-docs fetch", findings[0].Title)
+	assert.Equal(t, "network", findings[0].Category)
+}
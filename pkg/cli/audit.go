@@ -39,6 +39,7 @@ type AuditOptions struct {
 	ArtifactSets     []string
 	ExperimentFilter string
 	VariantFilter    string
+	Timeline         string // Timeline output: "text", "html" or "mermaid" (empty disables it)
 }
 
 // NewAuditCommand creates the audit command
@@ -77,6 +78,8 @@ Examples:
   ` + string(constants.CLIExtensionPrefix) + ` audit 1234567890 -v                 # Verbose output
   ` + string(constants.CLIExtensionPrefix) + ` audit 1234567890 --parse            # Parse agent logs and firewall logs, generating log.md and firewall.md
   ` + string(constants.CLIExtensionPrefix) + ` audit 1234567890 --repo owner/repo  # Audit run from a specific repository
+  ` + string(constants.CLIExtensionPrefix) + ` audit 1234567890 --timeline text    # Show a swimlane timeline of the run
+  ` + string(constants.CLIExtensionPrefix) + ` audit 1234567890 --timeline html    # Export the timeline as a self-contained HTML gantt chart
   ` + string(constants.CLIExtensionPrefix) + ` audit 1234567890 1234567891         # Diff two runs (base vs comparison)
   ` + string(constants.CLIExtensionPrefix) + ` audit 1234567890 1234567891 1234567892  # Diff base against multiple runs
   ` + string(constants.CLIExtensionPrefix) + ` audit 1234567890 1234567891 --format markdown  # Markdown diff output for PR comments`,
@@ -91,6 +94,11 @@ Examples:
 			stdin, _ := cmd.Flags().GetBool("stdin")
 			experimentFilter, _ := cmd.Flags().GetString("experiment")
 			variantFilter, _ := cmd.Flags().GetString("variant")
+			timeline, _ := cmd.Flags().GetString("timeline")
+
+			if err := validateTimelineFormat(timeline); err != nil {
+				return err
+			}
 
 			// --variant requires --experiment to be meaningful.
 			if variantFilter != "" && experimentFilter == "" {
@@ -163,9 +171,14 @@ Examples:
 					ArtifactSets:     artifacts,
 					ExperimentFilter: experimentFilter,
 					VariantFilter:    variantFilter,
+					Timeline:         timeline,
 				})
 			}
 
+			if timeline != "" {
+				return errors.New("--timeline is only supported when auditing a single run")
+			}
+
 			// Multiple runs: diff mode (first is base, rest are comparisons)
 			format, _ := cmd.Flags().GetString("format")
 			return runAuditMulti(cmd.Context(), args, repoFlag, outputDir, verbose, jsonOutput, format, artifacts)
@@ -182,6 +195,7 @@ Examples:
 	cmd.Flags().Bool("stdin", false, "Read workflow run IDs or URLs from stdin (one per line) instead of positional arguments")
 	cmd.Flags().String("experiment", "", "Filter to runs that include this experiment name")
 	cmd.Flags().String("variant", "", "Filter to runs with a specific variant value (requires --experiment)")
+	cmd.Flags().String("timeline", "", "Render a timestamp-ordered timeline of jobs, steps, agent turns, tool calls and firewall events: text (swimlane), html (writes timeline.html), mermaid (writes timeline.mmd)")
 
 	// Register completions for audit command
 	RegisterDirFlagCompletion(cmd, "output")
@@ -278,6 +292,7 @@ func AuditWorkflowRun(ctx context.Context, runID int64, opts AuditOptions) error
 	artifactSets := opts.ArtifactSets
 	experimentFilter := opts.ExperimentFilter
 	variantFilter := opts.VariantFilter
+	timeline := opts.Timeline
 
 	// Auto-detect GHES host from git remote if hostname is not provided
 	if hostname == "" {
@@ -386,6 +401,7 @@ func AuditWorkflowRun(ctx context.Context, runID int64, opts AuditOptions) error
 			Verbose:    verbose,
 			Parse:      parse,
 			JSONOutput: jsonOutput,
+			Timeline:   timeline,
 		})
 	}
 
@@ -706,6 +722,7 @@ func AuditWorkflowRun(ctx context.Context, runID int64, opts AuditOptions) error
 		Verbose:    verbose,
 		Parse:      parse,
 		JSONOutput: jsonOutput,
+		Timeline:   timeline,
 	})
 }
 
@@ -777,6 +794,12 @@ func renderAuditReport(ctx context.Context, processedRun ProcessedRun, metrics L
 		}
 	}
 
+	if opts.Timeline != "" {
+		if err := renderAuditTimeline(processedRun, mcpToolUsage, opts.Timeline, runOutputDir); err != nil {
+			return err
+		}
+	}
+
 	// Display logs location (only for console output)
 	if !opts.JSONOutput {
 		absOutputDir, _ := filepath.Abs(runOutputDir)
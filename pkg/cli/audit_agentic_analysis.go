@@ -38,6 +38,9 @@ type AgenticAssessment struct {
 	Summary        string `json:"summary"`
 	Evidence       string `json:"evidence,omitempty"`
 	Recommendation string `json:"recommendation,omitempty"`
+	Title          string `json:"title,omitempty"`    // Set by user-defined assessment rules
+	Category       string `json:"category,omitempty"` // Set by user-defined assessment rules
+	Source         string `json:"source,omitempty"`   // Rules file for user-defined assessments
 }
 
 func buildToolUsageInfo(metrics LogMetrics) []ToolUsageInfo {
@@ -47,6 +50,7 @@ func buildToolUsageInfo(metrics LogMetrics) []ToolUsageInfo {
 		displayKey := workflow.PrettifyToolName(toolCall.Name)
 		if existing, exists := toolStats[displayKey]; exists {
 			existing.CallCount += toolCall.CallCount
+			existing.Commands = append(existing.Commands, toolCall.Commands...)
 			if toolCall.MaxInputSize > existing.MaxInputSize {
 				existing.MaxInputSize = toolCall.MaxInputSize
 			}
@@ -67,6 +71,7 @@ func buildToolUsageInfo(metrics LogMetrics) []ToolUsageInfo {
 			CallCount:     toolCall.CallCount,
 			MaxInputSize:  toolCall.MaxInputSize,
 			MaxOutputSize: toolCall.MaxOutputSize,
+			Commands:      slices.Clone(toolCall.Commands),
 		}
 		if toolCall.MaxDuration > 0 {
 			info.MaxDuration = timeutil.FormatDuration(toolCall.MaxDuration)
@@ -394,6 +399,8 @@ func buildAgenticAssessments(processedRun ProcessedRun, metrics MetricsData, too
 		})
 	}
 
+	assessments = append(assessments, evaluateAssessmentRules(repoAssessmentRules(), processedRun, metrics, toolUsage, createdItems, domain, fingerprint)...)
+
 	auditAgenticLog.Printf("Built %d agentic assessments", len(assessments))
 	return assessments
 }
@@ -423,10 +430,20 @@ func generateAgenticAssessmentFindings(assessments []AgenticAssessment) []Findin
 			category = "coordination"
 			impact = "Context continuity improves downstream debugging and auditability"
 		}
+		title := prettifyAssessmentKind(assessment.Kind)
+		if assessment.Source != "" {
+			impact = "Matched a user-defined assessment rule in " + assessment.Source
+			if assessment.Category != "" {
+				category = assessment.Category
+			}
+			if assessment.Title != "" {
+				title = assessment.Title
+			}
+		}
 		findings = append(findings, Finding{
 			Category:    category,
 			Severity:    assessment.Severity,
-			Title:       prettifyAssessmentKind(assessment.Kind),
+			Title:       title,
 			Description: assessment.Summary,
 			Impact:      impact,
 		})
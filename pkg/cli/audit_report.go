@@ -154,6 +154,8 @@ type ToolUsageInfo struct {
 	MaxInputSize  int    `json:"max_input_size,omitempty" console:"header:Max Input,format:number,omitempty"`
 	MaxOutputSize int    `json:"max_output_size,omitempty" console:"header:Max Output,format:number,omitempty"`
 	MaxDuration   string `json:"max_duration,omitempty" console:"header:Max Duration,omitempty"`
+	// Commands holds the full commands of bash calls; Name only carries a shortened command
+	Commands []string `json:"-" console:"-"`
 }
 
 // MCPToolUsageData contains detailed MCP tool usage statistics and individual call records
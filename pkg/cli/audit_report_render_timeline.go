@@ -0,0 +1,400 @@
+package cli
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/constants"
+	"github.com/github/gh-aw/pkg/timeutil"
+)
+
+// timelineBarWidth is the number of characters used for the swimlane bar column.
+const timelineBarWidth = 40
+
+// Export file names written to the run output directory.
+const (
+	timelineHTMLFileName    = "timeline.html"
+	timelineMermaidFileName = "timeline.mmd"
+)
+
+// timelineLaneTitles maps lane identifiers to display names.
+var timelineLaneTitles = map[string]string{
+	timelineLaneJobs:     "Jobs",
+	timelineLaneSteps:    "Steps",
+	timelineLaneAgent:    "Agent turns",
+	timelineLaneTools:    "Tool calls",
+	timelineLaneFirewall: "Firewall",
+}
+
+// validateTimelineFormat returns an error when format is not a supported --timeline value.
+func validateTimelineFormat(format string) error {
+	switch format {
+	case "", timelineFormatText, timelineFormatHTML, timelineFormatMermaid:
+		return nil
+	default:
+		return fmt.Errorf("invalid --timeline value %q: must be one of %s, %s, %s", format, timelineFormatText, timelineFormatHTML, timelineFormatMermaid)
+	}
+}
+
+// renderAuditTimeline builds the run timeline and renders it in the requested format.
+// The text swimlane is written to stderr; HTML and Mermaid exports are written to the
+// run output directory.
+func renderAuditTimeline(processedRun ProcessedRun, mcpToolUsage *MCPToolUsageData, format, runOutputDir string) error {
+	timeline := buildRunTimeline(processedRun, mcpToolUsage, runOutputDir)
+	if len(timeline.Events) == 0 {
+		fmt.Fprintln(os.Stderr, console.FormatWarningMessage("No timestamped events found for this run; timeline is empty"))
+		return nil
+	}
+
+	switch format {
+	case timelineFormatText:
+		fmt.Fprintln(os.Stderr, console.FormatSectionHeader("Run Timeline"))
+		fmt.Fprintln(os.Stderr)
+		renderTimelineSwimlane(os.Stderr, timeline)
+		fmt.Fprintln(os.Stderr)
+		fmt.Fprint(os.Stderr, renderTimelineLaneSummary(timeline))
+		return nil
+	case timelineFormatHTML:
+		content, err := generateTimelineHTML(timeline)
+		if err != nil {
+			return err
+		}
+		return writeTimelineExport(filepath.Join(runOutputDir, timelineHTMLFileName), content, timeline.RunID)
+	case timelineFormatMermaid:
+		return writeTimelineExport(filepath.Join(runOutputDir, timelineMermaidFileName), generateTimelineMermaid(timeline), timeline.RunID)
+	default:
+		return validateTimelineFormat(format)
+	}
+}
+
+func writeTimelineExport(path, content string, runID int64) error {
+	if err := os.WriteFile(path, []byte(content), constants.FilePermSensitive); err != nil {
+		return fmt.Errorf("failed to write timeline: %w", err)
+	}
+	fmt.Fprintln(os.Stderr, console.FormatSuccessMessage(fmt.Sprintf("✓ Wrote timeline for run %d → %s", runID, path)))
+	return nil
+}
+
+// renderTimelineSwimlane writes one row per event, ordered by start time. Each row shows
+// the offset from the start of the run, the lane, the duration, a bar marking where the
+// event falls within the run, and a label.
+func renderTimelineSwimlane(w io.Writer, timeline *RunTimeline) {
+	fmt.Fprintf(w, "%-9s %-8s %-8s %-*s  %s\n", "OFFSET", "LANE", "DURATION", timelineBarWidth+2, "TIMELINE", "EVENT")
+	for _, event := range timeline.Events {
+		duration := "—"
+		if !event.IsPoint() {
+			duration = timeutil.FormatDuration(event.Duration())
+		}
+		fmt.Fprintf(w, "%-9s %-8s %-8s [%s]  %s\n",
+			formatTimelineOffset(event.Start.Sub(timeline.Start)),
+			event.Lane,
+			duration,
+			timelineBar(timeline, event),
+			timelineEventLabel(event))
+	}
+}
+
+// timelineBar draws the position of an event within the overall run span.
+func timelineBar(timeline *RunTimeline, event TimelineEvent) string {
+	bar := []rune(strings.Repeat(" ", timelineBarWidth))
+	span := timeline.Duration()
+	column := func(t time.Time) int {
+		if span <= 0 {
+			return 0
+		}
+		col := int(float64(t.Sub(timeline.Start)) / float64(span) * timelineBarWidth)
+		return min(max(col, 0), timelineBarWidth-1)
+	}
+
+	startCol := column(event.Start)
+	if event.IsPoint() {
+		marker := '•'
+		if event.Status == timelineStatusDenied || event.Status == timelineStatusFailure {
+			marker = '✗'
+		}
+		bar[startCol] = marker
+		return string(bar)
+	}
+	endCol := column(event.End)
+	for i := startCol; i <= endCol; i++ {
+		bar[i] = '█'
+	}
+	return string(bar)
+}
+
+// timelineEventLabel returns the human-readable label for an event including its outcome.
+func timelineEventLabel(event TimelineEvent) string {
+	if event.Lane == timelineLaneFirewall {
+		label := event.Status + " " + event.Label
+		if event.Count > 1 {
+			label += fmt.Sprintf(" (×%d)", event.Count)
+		}
+		return label
+	}
+	switch event.Status {
+	case timelineStatusFailure:
+		return event.Label + " ✗"
+	case timelineStatusSkipped:
+		return event.Label + " (skipped)"
+	default:
+		return event.Label
+	}
+}
+
+// formatTimelineOffset formats an offset from the start of the run as +mm:ss or +h:mm:ss.
+func formatTimelineOffset(d time.Duration) string {
+	d = d.Round(time.Second)
+	hours := int(d / time.Hour)
+	minutes := int(d%time.Hour) / int(time.Minute)
+	seconds := int(d%time.Minute) / int(time.Second)
+	if hours > 0 {
+		return fmt.Sprintf("+%d:%02d:%02d", hours, minutes, seconds)
+	}
+	return fmt.Sprintf("+%02d:%02d", minutes, seconds)
+}
+
+// timelineLaneStats summarizes a single lane of the timeline.
+type timelineLaneStats struct {
+	Lane   string
+	Events int
+	Busy   time.Duration
+	Share  float64 // percentage of the run span during which the lane was busy
+	Denied int     // denied firewall requests (firewall lane only)
+}
+
+// computeTimelineLaneStats returns per-lane statistics in lane order. Busy time is the
+// union of the lane's intervals, so parallel tool calls are not counted twice.
+func computeTimelineLaneStats(timeline *RunTimeline) []timelineLaneStats {
+	byLane := make(map[string][]TimelineEvent)
+	for _, event := range timeline.Events {
+		byLane[event.Lane] = append(byLane[event.Lane], event)
+	}
+
+	var stats []timelineLaneStats
+	for _, lane := range timelineLanes {
+		events := byLane[lane]
+		if len(events) == 0 {
+			continue
+		}
+		s := timelineLaneStats{Lane: lane}
+		sort.SliceStable(events, func(i, j int) bool { return events[i].Start.Before(events[j].Start) })
+		var cursor time.Time
+		for _, event := range events {
+			if lane == timelineLaneFirewall {
+				s.Events += event.Count
+				if event.Status == timelineStatusDenied {
+					s.Denied += event.Count
+				}
+				continue
+			}
+			s.Events++
+			start := event.Start
+			if start.Before(cursor) {
+				start = cursor
+			}
+			if event.End.After(start) {
+				s.Busy += event.End.Sub(start)
+				cursor = event.End
+			}
+		}
+		if span := timeline.Duration(); span > 0 {
+			s.Share = float64(s.Busy) / float64(span) * 100
+		}
+		stats = append(stats, s)
+	}
+	return stats
+}
+
+// renderTimelineLaneSummary renders a table showing where the run's time went.
+func renderTimelineLaneSummary(timeline *RunTimeline) string {
+	var rows [][]string
+	for _, s := range computeTimelineLaneStats(timeline) {
+		busy, share := "—", "—"
+		if s.Lane != timelineLaneFirewall {
+			busy = timeutil.FormatDuration(s.Busy)
+			share = fmt.Sprintf("%.0f%%", s.Share)
+		}
+		events := strconv.Itoa(s.Events)
+		if s.Denied > 0 {
+			events += fmt.Sprintf(" (%d denied)", s.Denied)
+		}
+		rows = append(rows, []string{timelineLaneTitles[s.Lane], events, busy, share})
+	}
+	return console.RenderTable(console.TableConfig{
+		Title:   "Time by lane (run span " + timeutil.FormatDuration(timeline.Duration()) + ")",
+		Headers: []string{"Lane", "Events", "Busy", "Share"},
+		Rows:    rows,
+	})
+}
+
+// generateTimelineMermaid renders the timeline as a Mermaid gantt chart with one
+// section per lane. Firewall requests are rendered as milestones.
+func generateTimelineMermaid(timeline *RunTimeline) string {
+	var sb strings.Builder
+	sb.WriteString("gantt\n")
+	fmt.Fprintf(&sb, "    title Run %d timeline\n", timeline.RunID)
+	sb.WriteString("    dateFormat x\n")
+	sb.WriteString("    axisFormat %H:%M:%S\n")
+
+	id := 0
+	for _, lane := range timelineLanes {
+		wroteSection := false
+		for _, event := range timeline.Events {
+			if event.Lane != lane {
+				continue
+			}
+			if !wroteSection {
+				fmt.Fprintf(&sb, "    section %s\n", timelineLaneTitles[lane])
+				wroteSection = true
+			}
+			id++
+			var tags []string
+			switch event.Status {
+			case timelineStatusSuccess:
+				tags = append(tags, "done")
+			case timelineStatusFailure, timelineStatusDenied:
+				tags = append(tags, "crit")
+			}
+			if event.Lane == timelineLaneFirewall {
+				tags = append(tags, "milestone")
+			}
+			tags = append(tags, fmt.Sprintf("e%d", id),
+				strconv.FormatInt(event.Start.UnixMilli(), 10),
+				strconv.FormatInt(event.End.UnixMilli(), 10))
+			fmt.Fprintf(&sb, "    %s :%s\n", sanitizeMermaidGanttLabel(timelineEventLabel(event)), strings.Join(tags, ", "))
+		}
+	}
+	return sb.String()
+}
+
+// sanitizeMermaidGanttLabel removes characters that Mermaid treats as gantt syntax.
+func sanitizeMermaidGanttLabel(label string) string {
+	label = strings.NewReplacer(":", " ", ";", " ", "#", "", "\n", " ").Replace(label)
+	return strings.TrimSpace(label)
+}
+
+// timelineHTMLRow is a single bar in the HTML gantt chart.
+type timelineHTMLRow struct {
+	Lane     string
+	Label    string
+	Status   string
+	Offset   string
+	Duration string
+	Left     string
+	Width    string
+	Point    bool
+}
+
+// timelineHTMLData is the template data for the HTML export.
+type timelineHTMLData struct {
+	RunID    int64
+	Start    string
+	Span     string
+	Lanes    []timelineLaneStats
+	Titles   map[string]string
+	Rows     []timelineHTMLRow
+	Mermaid  string
+	ShareFmt func(float64) string
+	BusyFmt  func(time.Duration) string
+}
+
+var timelineHTMLTemplate = template.Must(template.New("timeline").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Run {{.RunID}} timeline</title>
+<style>
+body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Helvetica, Arial, sans-serif; margin: 24px; color: #1f2328; }
+h1 { font-size: 20px; }
+table { border-collapse: collapse; margin-bottom: 24px; }
+th, td { text-align: left; padding: 4px 10px; border-bottom: 1px solid #d0d7de; font-size: 13px; }
+.gantt { width: 100%; }
+.gantt td.label { white-space: nowrap; max-width: 420px; overflow: hidden; text-overflow: ellipsis; }
+.gantt td.track { width: 60%; position: relative; padding: 0; }
+.bar { position: absolute; top: 6px; height: 12px; min-width: 2px; border-radius: 2px; }
+.point { position: absolute; top: 5px; width: 8px; height: 14px; margin-left: -4px; border-radius: 50%; }
+.jobs { background: #0969da; } .steps { background: #54aeff; } .agent { background: #8250df; }
+.tools { background: #1a7f37; } .firewall { background: #9a6700; }
+.failure, .denied { background: #cf222e; }
+.skipped { opacity: 0.4; }
+pre { background: #f6f8fa; padding: 12px; overflow: auto; font-size: 12px; }
+</style>
+</head>
+<body>
+<h1>Run {{.RunID}} timeline</h1>
+<p>Started {{.Start}} · span {{.Span}}</p>
+<table>
+<tr><th>Lane</th><th>Events</th><th>Busy</th><th>Share</th></tr>
+{{- range .Lanes}}
+<tr><td>{{index $.Titles .Lane}}</td><td>{{.Events}}{{if .Denied}} ({{.Denied}} denied){{end}}</td><td>{{call $.BusyFmt .Busy}}</td><td>{{call $.ShareFmt .Share}}</td></tr>
+{{- end}}
+</table>
+<table class="gantt">
+<tr><th>Offset</th><th>Lane</th><th>Duration</th><th>Event</th><th>Timeline</th></tr>
+{{- range .Rows}}
+<tr><td>{{.Offset}}</td><td>{{.Lane}}</td><td>{{.Duration}}</td><td class="label" title="{{.Label}}">{{.Label}}</td><td class="track"><div class="{{if .Point}}point{{else}}bar{{end}} {{.Lane}} {{.Status}}" style="left: {{.Left}}; width: {{.Width}}"></div></td></tr>
+{{- end}}
+</table>
+<details>
+<summary>Mermaid source</summary>
+<pre>{{.Mermaid}}</pre>
+</details>
+</body>
+</html>
+`))
+
+// generateTimelineHTML renders the timeline as a self-contained HTML gantt chart.
+// The page has no external dependencies and embeds the Mermaid source for reuse
+// in Markdown.
+func generateTimelineHTML(timeline *RunTimeline) (string, error) {
+	span := float64(timeline.Duration())
+	percent := func(d time.Duration) string {
+		if span <= 0 {
+			return "0%"
+		}
+		return strconv.FormatFloat(float64(d)/span*100, 'f', 3, 64) + "%"
+	}
+
+	data := timelineHTMLData{
+		RunID:   timeline.RunID,
+		Start:   timeline.Start.UTC().Format(time.RFC3339),
+		Span:    timeutil.FormatDuration(timeline.Duration()),
+		Lanes:   computeTimelineLaneStats(timeline),
+		Titles:  timelineLaneTitles,
+		Mermaid: generateTimelineMermaid(timeline),
+		ShareFmt: func(share float64) string {
+			return fmt.Sprintf("%.0f%%", share)
+		},
+		BusyFmt: timeutil.FormatDuration,
+	}
+	for _, event := range timeline.Events {
+		row := timelineHTMLRow{
+			Lane:     event.Lane,
+			Label:    timelineEventLabel(event),
+			Status:   event.Status,
+			Offset:   formatTimelineOffset(event.Start.Sub(timeline.Start)),
+			Duration: "—",
+			Left:     percent(event.Start.Sub(timeline.Start)),
+			Point:    event.IsPoint(),
+		}
+		if !row.Point {
+			row.Duration = timeutil.FormatDuration(event.Duration())
+			row.Width = percent(event.Duration())
+		}
+		data.Rows = append(data.Rows, row)
+	}
+
+	var sb strings.Builder
+	if err := timelineHTMLTemplate.Execute(&sb, data); err != nil {
+		return "", fmt.Errorf("failed to render timeline HTML: %w", err)
+	}
+	return sb.String(), nil
+}
@@ -0,0 +1,478 @@
+package cli
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/workflow"
+)
+
+var auditTimelineLog = logger.New("cli:audit_timeline")
+
+// Timeline lanes, in the order they are rendered.
+const (
+	timelineLaneJobs     = "jobs"
+	timelineLaneSteps    = "steps"
+	timelineLaneAgent    = "agent"
+	timelineLaneTools    = "tools"
+	timelineLaneFirewall = "firewall"
+)
+
+var timelineLanes = []string{timelineLaneJobs, timelineLaneSteps, timelineLaneAgent, timelineLaneTools, timelineLaneFirewall}
+
+// Normalized timeline event statuses.
+const (
+	timelineStatusSuccess = "success"
+	timelineStatusFailure = "failure"
+	timelineStatusSkipped = "skipped"
+	timelineStatusAllowed = "allowed"
+	timelineStatusDenied  = "denied"
+)
+
+// Valid values for the audit --timeline flag.
+const (
+	timelineFormatText    = "text"
+	timelineFormatHTML    = "html"
+	timelineFormatMermaid = "mermaid"
+)
+
+// TimelineEvent is a single entry on a run timeline. Point-in-time events
+// (such as firewall requests) have End equal to Start.
+type TimelineEvent struct {
+	Lane   string    `json:"lane"`
+	Start  time.Time `json:"start"`
+	End    time.Time `json:"end"`
+	Label  string    `json:"label"`
+	Status string    `json:"status,omitempty"`
+	Count  int       `json:"count,omitempty"` // number of merged firewall requests
+}
+
+// Duration returns the time between the start and end of the event.
+func (e TimelineEvent) Duration() time.Duration {
+	if e.End.Before(e.Start) {
+		return 0
+	}
+	return e.End.Sub(e.Start)
+}
+
+// IsPoint reports whether the event has no duration.
+func (e TimelineEvent) IsPoint() bool {
+	return !e.End.After(e.Start)
+}
+
+// RunTimeline is a timestamp-ordered view of everything that happened during a run.
+type RunTimeline struct {
+	RunID  int64           `json:"run_id"`
+	Start  time.Time       `json:"start"`
+	End    time.Time       `json:"end"`
+	Events []TimelineEvent `json:"events"`
+}
+
+// Duration returns the wall-clock span covered by the timeline.
+func (t *RunTimeline) Duration() time.Duration {
+	return t.End.Sub(t.Start)
+}
+
+// buildRunTimeline collects job and step boundaries, agent turns, tool calls and
+// firewall requests for a run and merges them into a single timeline.
+//
+// Sources:
+//   - jobs and steps from the GitHub Actions jobs API (processedRun.JobDetails)
+//   - agent turns from the API proxy token-usage.jsonl (one entry per LLM request)
+//   - MCP tool calls from the MCP gateway logs (mcpToolUsage)
+//   - other tool calls (bash, file edits, ...) from the engine's agent events, or from
+//     the Copilot events.jsonl when the agent logs carry no timed tool calls
+//   - firewall allow/deny decisions from the proxy access logs
+func buildRunTimeline(processedRun ProcessedRun, mcpToolUsage *MCPToolUsageData, runDir string) *RunTimeline {
+	var events []TimelineEvent
+	events = append(events, jobTimelineEvents(processedRun.JobDetails)...)
+
+	if path := findTokenUsageFile(runDir); path != "" {
+		entries, err := readTokenUsageEntries(path)
+		if err != nil {
+			auditTimelineLog.Printf("Failed to read token usage entries: %v", err)
+		}
+		events = append(events, agentTurnTimelineEvents(entries)...)
+	}
+
+	var mcpCalls []MCPToolCall
+	if mcpToolUsage != nil {
+		mcpCalls = mcpToolUsage.ToolCalls
+	}
+	events = append(events, mcpToolTimelineEvents(mcpCalls)...)
+
+	agentEvents, err := buildRunAgentEvents(runDir, processedRun.Run)
+	if err != nil {
+		auditTimelineLog.Printf("Failed to parse agent events: %v", err)
+	}
+	toolEvents := agentToolTimelineEvents(agentEvents, mcpCalls)
+	if len(toolEvents) == 0 {
+		if path := findEventsJSONLFile(runDir); path != "" {
+			toolEvents, err = copilotToolTimelineEvents(path, len(mcpCalls) == 0)
+			if err != nil {
+				auditTimelineLog.Printf("Failed to read tool events from %s: %v", path, err)
+			}
+		}
+	}
+	events = append(events, toolEvents...)
+
+	var firewallEntries []FirewallLogEntry
+	for _, path := range findFirewallLogFiles(runDir) {
+		entries, err := readFirewallLogEntries(path)
+		if err != nil {
+			auditTimelineLog.Printf("Failed to read firewall log %s: %v", path, err)
+			continue
+		}
+		firewallEntries = append(firewallEntries, entries...)
+	}
+	events = append(events, firewallTimelineEvents(firewallEntries)...)
+
+	timeline := newRunTimeline(processedRun.Run.DatabaseID, events)
+	auditTimelineLog.Printf("Built timeline for run %d: events=%d, span=%s", timeline.RunID, len(timeline.Events), timeline.Duration())
+	return timeline
+}
+
+// newRunTimeline sorts the events by start time (then lane order) and computes the overall span.
+func newRunTimeline(runID int64, events []TimelineEvent) *RunTimeline {
+	laneOrder := make(map[string]int, len(timelineLanes))
+	for i, lane := range timelineLanes {
+		laneOrder[lane] = i
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		if !events[i].Start.Equal(events[j].Start) {
+			return events[i].Start.Before(events[j].Start)
+		}
+		return laneOrder[events[i].Lane] < laneOrder[events[j].Lane]
+	})
+
+	timeline := &RunTimeline{RunID: runID, Events: events}
+	for _, event := range events {
+		if timeline.Start.IsZero() || event.Start.Before(timeline.Start) {
+			timeline.Start = event.Start
+		}
+		if event.End.After(timeline.End) {
+			timeline.End = event.End
+		}
+	}
+	return timeline
+}
+
+// jobTimelineEvents converts jobs and their steps into timeline events.
+// Jobs or steps that never started are omitted.
+func jobTimelineEvents(jobs []JobInfoWithDuration) []TimelineEvent {
+	var events []TimelineEvent
+	for _, job := range jobs {
+		if job.StartedAt.IsZero() {
+			continue
+		}
+		events = append(events, TimelineEvent{
+			Lane:   timelineLaneJobs,
+			Start:  job.StartedAt,
+			End:    timelineEnd(job.StartedAt, job.CompletedAt),
+			Label:  job.Name,
+			Status: normalizeJobConclusion(job.Conclusion),
+		})
+		for _, step := range job.Steps {
+			if step.StartedAt.IsZero() {
+				continue
+			}
+			events = append(events, TimelineEvent{
+				Lane:   timelineLaneSteps,
+				Start:  step.StartedAt,
+				End:    timelineEnd(step.StartedAt, step.CompletedAt),
+				Label:  job.Name + " › " + step.Name,
+				Status: normalizeJobConclusion(step.Conclusion),
+			})
+		}
+	}
+	return events
+}
+
+// agentTurnTimelineEvents converts token usage entries into agent turns.
+// The API proxy records each request when its response completes, so the
+// turn starts duration_ms before the recorded timestamp.
+func agentTurnTimelineEvents(entries []TokenUsageEntry) []TimelineEvent {
+	var events []TimelineEvent
+	turn := 0
+	for _, entry := range entries {
+		ts, ok := parseTokenUsageTimestamp(entry.Timestamp)
+		if !ok {
+			continue
+		}
+		turn++
+		start := ts.Add(-time.Duration(entry.DurationMs) * time.Millisecond)
+		label := fmt.Sprintf("turn %d", turn)
+		if entry.Model != "" {
+			label += " · " + entry.Model
+		}
+		label += fmt.Sprintf(" · %s in / %s out", console.FormatNumber(entry.InputTokens+entry.CacheReadTokens+entry.CacheWriteTokens), console.FormatNumber(entry.OutputTokens))
+		status := timelineStatusSuccess
+		if entry.Status >= 400 {
+			status = timelineStatusFailure
+		}
+		events = append(events, TimelineEvent{
+			Lane:   timelineLaneAgent,
+			Start:  start,
+			End:    ts,
+			Label:  label,
+			Status: status,
+		})
+	}
+	return events
+}
+
+// mcpToolTimelineEvents converts MCP gateway tool calls into timeline events.
+func mcpToolTimelineEvents(calls []MCPToolCall) []TimelineEvent {
+	var events []TimelineEvent
+	for _, call := range calls {
+		start, ok := parseTimelineTimestamp(call.Timestamp)
+		if !ok {
+			continue
+		}
+		end := start
+		if call.Duration != "" {
+			if d, err := time.ParseDuration(call.Duration); err == nil {
+				end = start.Add(d)
+			}
+		}
+		status := ""
+		switch call.Status {
+		case "success":
+			status = timelineStatusSuccess
+		case "error":
+			status = timelineStatusFailure
+		}
+		events = append(events, TimelineEvent{
+			Lane:   timelineLaneTools,
+			Start:  start,
+			End:    end,
+			Label:  call.ServerName + "." + call.ToolName,
+			Status: status,
+		})
+	}
+	return events
+}
+
+// agentToolTimelineEvents converts the tool calls of a normalized agent event stream
+// into timeline events, ending each call at its tool result. Results are paired by
+// tool call ID, or with the oldest open call of the same tool for engines that do
+// not log IDs. Calls without a timestamp are omitted, as are calls already reported
+// by the MCP gateway.
+func agentToolTimelineEvents(agentEvents []workflow.AgentEvent, mcpCalls []MCPToolCall) []TimelineEvent {
+	gatewayTools := make(map[string]bool)
+	for _, call := range mcpCalls {
+		for _, name := range []string{
+			call.ServerName + "." + call.ToolName,
+			call.ServerName + "_" + call.ToolName,
+			call.ServerName + "-" + call.ToolName,
+		} {
+			gatewayTools[name] = true
+		}
+	}
+
+	var events []TimelineEvent
+	openByID := make(map[string]int)
+	openByTool := make(map[string][]int)
+	for _, evt := range agentEvents {
+		switch evt.Type {
+		case workflow.AgentEventToolCall:
+			ts, ok := parseTimelineTimestamp(evt.Timestamp)
+			if !ok || evt.Tool == "" || gatewayTools[evt.Tool] {
+				continue
+			}
+			events = append(events, TimelineEvent{Lane: timelineLaneTools, Start: ts, End: ts, Label: evt.Tool})
+			if evt.ToolCallID != "" {
+				openByID[evt.ToolCallID] = len(events) - 1
+			} else {
+				openByTool[evt.Tool] = append(openByTool[evt.Tool], len(events)-1)
+			}
+		case workflow.AgentEventToolResult:
+			idx, ok := openByID[evt.ToolCallID]
+			if ok {
+				delete(openByID, evt.ToolCallID)
+			} else if open := openByTool[evt.Tool]; evt.ToolCallID == "" && len(open) > 0 {
+				idx, ok = open[0], true
+				openByTool[evt.Tool] = open[1:]
+			}
+			if !ok {
+				continue
+			}
+			if ts, ok := parseTimelineTimestamp(evt.Timestamp); ok {
+				events[idx].End = timelineEnd(events[idx].Start, ts)
+			}
+			if evt.IsError {
+				events[idx].Status = timelineStatusFailure
+			} else {
+				events[idx].Status = timelineStatusSuccess
+			}
+		}
+	}
+	return events
+}
+
+// copilotToolTimelineEvents reads tool executions from a Copilot events.jsonl file,
+// pairing tool.execution_start and tool.execution_complete events by tool call ID.
+// MCP tool executions are only included when includeMCP is true, so that calls
+// already reported by the MCP gateway are not shown twice.
+func copilotToolTimelineEvents(path string, includeMCP bool) ([]TimelineEvent, error) {
+	file, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open events.jsonl: %w", err)
+	}
+	defer file.Close()
+
+	var events []TimelineEvent
+	started := make(map[string]int)
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, maxScannerBufferSize), maxScannerBufferSize)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(line, "{") {
+			continue
+		}
+		var entry copilotEventsJSONLEntry
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			continue
+		}
+		ts, ok := parseTimelineTimestamp(entry.Timestamp)
+		if !ok {
+			continue
+		}
+
+		switch entry.Type {
+		case "tool.execution_start":
+			label := entry.Data.ToolName
+			if entry.Data.MCPServerName != "" {
+				if !includeMCP {
+					continue
+				}
+				label = entry.Data.MCPServerName + "." + entry.Data.MCPToolName
+			}
+			if label == "" {
+				continue
+			}
+			events = append(events, TimelineEvent{Lane: timelineLaneTools, Start: ts, End: ts, Label: label})
+			if entry.Data.ToolCallID != "" {
+				started[entry.Data.ToolCallID] = len(events) - 1
+			}
+		case "tool.execution_complete":
+			idx, ok := started[entry.Data.ToolCallID]
+			if !ok {
+				continue
+			}
+			delete(started, entry.Data.ToolCallID)
+			events[idx].End = timelineEnd(events[idx].Start, ts)
+			if entry.Data.Success {
+				events[idx].Status = timelineStatusSuccess
+			} else {
+				events[idx].Status = timelineStatusFailure
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return events, fmt.Errorf("error reading events.jsonl: %w", err)
+	}
+	return events, nil
+}
+
+// firewallTimelineEvents converts firewall log entries into point events.
+// Consecutive requests to the same domain with the same decision are merged
+// into a single event so that chatty clients do not flood the timeline.
+func firewallTimelineEvents(entries []FirewallLogEntry) []TimelineEvent {
+	type stamped struct {
+		ts    time.Time
+		entry FirewallLogEntry
+	}
+	var requests []stamped
+	for _, entry := range entries {
+		ts, ok := parseFirewallTimestamp(entry.Timestamp)
+		if !ok {
+			continue
+		}
+		requests = append(requests, stamped{ts: ts, entry: entry})
+	}
+	sort.SliceStable(requests, func(i, j int) bool { return requests[i].ts.Before(requests[j].ts) })
+
+	var events []TimelineEvent
+	for _, req := range requests {
+		domain := req.entry.Domain
+		if domain == "-" && req.entry.DestIPPort != "-" && req.entry.DestIPPort != "-:-" {
+			domain = req.entry.DestIPPort
+		} else if domain == "-" {
+			domain = unknownDomain
+		}
+		status := timelineStatusDenied
+		if isRequestAllowed(req.entry.Decision, req.entry.Status) {
+			status = timelineStatusAllowed
+		}
+		if n := len(events); n > 0 && events[n-1].Label == domain && events[n-1].Status == status {
+			events[n-1].End = req.ts
+			events[n-1].Count++
+			continue
+		}
+		events = append(events, TimelineEvent{
+			Lane:   timelineLaneFirewall,
+			Start:  req.ts,
+			End:    req.ts,
+			Label:  domain,
+			Status: status,
+			Count:  1,
+		})
+	}
+	return events
+}
+
+// normalizeJobConclusion maps a GitHub Actions job or step conclusion to a timeline status.
+func normalizeJobConclusion(conclusion string) string {
+	switch conclusion {
+	case "success":
+		return timelineStatusSuccess
+	case "failure", "cancelled", "timed_out", "action_required":
+		return timelineStatusFailure
+	case "skipped", "neutral":
+		return timelineStatusSkipped
+	default:
+		return ""
+	}
+}
+
+// timelineEnd returns end, or start when end is unknown or precedes start.
+func timelineEnd(start, end time.Time) time.Time {
+	if end.IsZero() || end.Before(start) {
+		return start
+	}
+	return end
+}
+
+// parseTimelineTimestamp parses an RFC 3339 timestamp as written by the MCP gateway
+// and the engine logs. Timestamps without a zone, as in Codex logs, are read as UTC.
+func parseTimelineTimestamp(value string) (time.Time, bool) {
+	if ts, ok := parseTokenUsageTimestamp(value); ok {
+		return ts, true
+	}
+	if ts, err := time.Parse("2006-01-02T15:04:05", value); err == nil {
+		return ts, true
+	}
+	return time.Time{}, false
+}
+
+// parseFirewallTimestamp parses the fractional Unix epoch seconds used in firewall logs.
+func parseFirewallTimestamp(value string) (time.Time, bool) {
+	seconds, err := strconv.ParseFloat(value, 64)
+	if err != nil || seconds <= 0 {
+		return time.Time{}, false
+	}
+	whole := int64(seconds)
+	nanos := int64((seconds - float64(whole)) * float64(time.Second))
+	return time.Unix(whole, nanos).UTC(), true
+}
@@ -0,0 +1,193 @@
+//go:build !integration
+
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTimelineTestRun(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+
+	logsDir := filepath.Join(dir, "sandbox", "firewall", "logs")
+	require.NoError(t, os.MkdirAll(filepath.Join(logsDir, "api-proxy-logs"), 0o755), "failed to create logs dir")
+	require.NoError(t, os.WriteFile(filepath.Join(logsDir, "api-proxy-logs", "token-usage.jsonl"), []byte(
+		`{"timestamp":"2026-10-01T10:01:10Z","model":"claude-sonnet-4.5","status":200,"input_tokens":1000,"output_tokens":200,"duration_ms":10000}
+{"timestamp":"2026-10-01T10:03:00Z","model":"claude-sonnet-4.5","status":529,"input_tokens":10,"duration_ms":1000}
+`), 0o600), "failed to write token usage")
+
+	// 2026-10-01T10:02:00Z and 10:02:05Z
+	require.NoError(t, os.WriteFile(filepath.Join(logsDir, "access.log"), []byte(
+		`1790848920.000 172.30.0.20:35288 api.github.com:443 140.82.112.22:443 1.1 CONNECT 200 TCP_TUNNEL:HIER_DIRECT api.github.com:443 "-"
+1790848921.000 172.30.0.20:35290 api.github.com:443 140.82.112.22:443 1.1 CONNECT 200 TCP_TUNNEL:HIER_DIRECT api.github.com:443 "-"
+1790848925.000 172.30.0.20:35292 evil.example.com:443 - 1.1 CONNECT 403 NONE_NONE:HIER_NONE evil.example.com:443 "-"
+`), 0o600), "failed to write firewall log")
+
+	sessionDir := filepath.Join(dir, "sandbox", "agent", "logs", "copilot-session-state", "abc")
+	require.NoError(t, os.MkdirAll(sessionDir, 0o755), "failed to create session dir")
+	require.NoError(t, os.WriteFile(filepath.Join(sessionDir, "events.jsonl"), []byte(
+		`{"type":"tool.execution_start","timestamp":"2026-10-01T10:01:20Z","data":{"toolCallId":"1","toolName":"bash"}}
+{"type":"tool.execution_complete","timestamp":"2026-10-01T10:01:50Z","data":{"toolCallId":"1","success":false}}
+{"type":"tool.execution_start","timestamp":"2026-10-01T10:01:55Z","data":{"toolCallId":"2","toolName":"github-search_issues","mcpServerName":"github","mcpToolName":"search_issues"}}
+{"type":"tool.execution_complete","timestamp":"2026-10-01T10:01:56Z","data":{"toolCallId":"2","success":true}}
+`), 0o600), "failed to write events.jsonl")
+	return dir
+}
+
+func timelineTestRun() ProcessedRun {
+	start := time.Date(2026, 10, 1, 10, 0, 0, 0, time.UTC)
+	return ProcessedRun{
+		Run: WorkflowRun{DatabaseID: 42},
+		JobDetails: []JobInfoWithDuration{{JobInfo: JobInfo{
+			Name: "agent", Conclusion: "failure", StartedAt: start, CompletedAt: start.Add(5 * time.Minute),
+			Steps: []StepInfo{
+				{Number: 1, Name: "Checkout", Conclusion: "success", StartedAt: start, CompletedAt: start.Add(10 * time.Second)},
+				{Number: 2, Name: "Never ran", Conclusion: "skipped"},
+			},
+		}}},
+	}
+}
+
+func TestBuildRunTimeline(t *testing.T) {
+	dir := writeTimelineTestRun(t)
+	mcpUsage := &MCPToolUsageData{ToolCalls: []MCPToolCall{
+		{Timestamp: "2026-10-01T10:01:30Z", ServerName: "github", ToolName: "get_issue", Duration: "450ms", Status: "success"},
+	}}
+
+	timeline := buildRunTimeline(timelineTestRun(), mcpUsage, dir)
+
+	assert.Equal(t, int64(42), timeline.RunID, "run ID should be recorded")
+	assert.Equal(t, 5*time.Minute, timeline.Duration(), "span should cover the job")
+
+	byLane := make(map[string][]TimelineEvent)
+	for i, event := range timeline.Events {
+		if i > 0 {
+			assert.False(t, event.Start.Before(timeline.Events[i-1].Start), "events should be ordered by start time")
+		}
+		byLane[event.Lane] = append(byLane[event.Lane], event)
+	}
+
+	require.Len(t, byLane[timelineLaneJobs], 1, "job should be on the timeline")
+	assert.Equal(t, timelineStatusFailure, byLane[timelineLaneJobs][0].Status, "failed job should be marked")
+	require.Len(t, byLane[timelineLaneSteps], 1, "steps that never started should be omitted")
+	assert.Equal(t, "agent › Checkout", byLane[timelineLaneSteps][0].Label, "step label should include the job")
+
+	turns := byLane[timelineLaneAgent]
+	require.Len(t, turns, 2, "each LLM request should be a turn")
+	assert.Equal(t, time.Date(2026, 10, 1, 10, 1, 0, 0, time.UTC), turns[0].Start, "turn should start duration_ms before its timestamp")
+	assert.Equal(t, timelineStatusFailure, turns[1].Status, "failed request should be marked")
+
+	tools := byLane[timelineLaneTools]
+	require.Len(t, tools, 2, "MCP calls from events.jsonl should not duplicate gateway calls")
+	assert.Equal(t, "bash", tools[0].Label, "bash call should come from events.jsonl")
+	assert.Equal(t, 30*time.Second, tools[0].Duration(), "bash latency should pair start and complete events")
+	assert.Equal(t, "github.get_issue", tools[1].Label, "MCP call should come from the gateway")
+	assert.Equal(t, 450*time.Millisecond, tools[1].Duration(), "MCP latency should be parsed")
+
+	firewall := byLane[timelineLaneFirewall]
+	require.Len(t, firewall, 2, "consecutive requests to the same domain should be merged")
+	assert.Equal(t, 2, firewall[0].Count, "merged event should count its requests")
+	assert.Equal(t, timelineStatusDenied, firewall[1].Status, "blocked request should be denied")
+}
+
+func TestBuildRunTimeline_AgentLogToolCalls(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "aw_info.json"), []byte(`{"engine_id":"claude"}`), 0o600), "failed to write aw_info.json")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "agent-stdio.log"), []byte(
+		`{"type":"assistant","timestamp":"2026-10-01T10:01:20Z","message":{"id":"m1","content":[{"type":"tool_use","id":"t1","name":"Bash","input":{"command":"make"}},{"type":"tool_use","id":"t2","name":"mcp__github__get_issue","input":{}}]}}
+{"type":"user","timestamp":"2026-10-01T10:01:50Z","message":{"content":[{"type":"tool_result","tool_use_id":"t1","content":"error","is_error":true}]}}
+{"type":"user","timestamp":"2026-10-01T10:01:51Z","message":{"content":[{"type":"tool_result","tool_use_id":"t2","content":"{}"}]}}
+`), 0o600), "failed to write agent log")
+	mcpUsage := &MCPToolUsageData{ToolCalls: []MCPToolCall{
+		{Timestamp: "2026-10-01T10:01:30Z", ServerName: "github", ToolName: "get_issue", Duration: "450ms", Status: "success"},
+	}}
+
+	timeline := buildRunTimeline(timelineTestRun(), mcpUsage, dir)
+
+	var tools []TimelineEvent
+	for _, event := range timeline.Events {
+		if event.Lane == timelineLaneTools {
+			tools = append(tools, event)
+		}
+	}
+	require.Len(t, tools, 2, "MCP calls from the agent log should not duplicate gateway calls")
+	assert.Equal(t, "bash", tools[0].Label, "bash call should come from the Claude log")
+	assert.Equal(t, 30*time.Second, tools[0].Duration(), "bash latency should pair the call and its result")
+	assert.Equal(t, timelineStatusFailure, tools[0].Status, "failed tool result should be marked")
+	assert.Equal(t, "github.get_issue", tools[1].Label, "MCP call should come from the gateway")
+}
+
+func TestAgentToolTimelineEvents_PairsByToolName(t *testing.T) {
+	events := agentToolTimelineEvents([]workflow.AgentEvent{
+		{Type: workflow.AgentEventToolCall, Timestamp: "2026-10-01T10:00:00", Tool: "bash"},
+		{Type: workflow.AgentEventToolResult, Timestamp: "2026-10-01T10:00:04", Tool: "bash"},
+		{Type: workflow.AgentEventToolCall, Tool: "bash"},
+	}, nil)
+
+	require.Len(t, events, 1, "calls without a timestamp should be omitted")
+	assert.Equal(t, 4*time.Second, events[0].Duration(), "results without IDs should end the open call of the same tool")
+	assert.Equal(t, timelineStatusSuccess, events[0].Status, "successful result should be marked")
+}
+
+func TestComputeTimelineLaneStats(t *testing.T) {
+	start := time.Date(2026, 10, 1, 10, 0, 0, 0, time.UTC)
+	timeline := newRunTimeline(1, []TimelineEvent{
+		{Lane: timelineLaneJobs, Start: start, End: start.Add(100 * time.Second)},
+		{Lane: timelineLaneTools, Start: start.Add(10 * time.Second), End: start.Add(30 * time.Second)},
+		{Lane: timelineLaneTools, Start: start.Add(20 * time.Second), End: start.Add(40 * time.Second)},
+		{Lane: timelineLaneFirewall, Start: start, End: start, Status: timelineStatusDenied, Count: 3},
+	})
+
+	stats := computeTimelineLaneStats(timeline)
+	require.Len(t, stats, 3, "only lanes with events should be summarized")
+	assert.Equal(t, timelineLaneTools, stats[1].Lane, "lanes should be in render order")
+	assert.Equal(t, 30*time.Second, stats[1].Busy, "overlapping calls should not be double counted")
+	assert.InDelta(t, 30.0, stats[1].Share, 0.001, "share should be relative to the run span")
+	assert.Equal(t, 3, stats[2].Denied, "denied firewall requests should be counted")
+}
+
+func TestTimelineRenderers(t *testing.T) {
+	start := time.Date(2026, 10, 1, 10, 0, 0, 0, time.UTC)
+	timeline := newRunTimeline(7, []TimelineEvent{
+		{Lane: timelineLaneJobs, Start: start, End: start.Add(time.Minute), Label: "agent", Status: timelineStatusSuccess},
+		{Lane: timelineLaneTools, Start: start.Add(30 * time.Second), End: start.Add(40 * time.Second), Label: "bash: <rm -rf>", Status: timelineStatusFailure},
+		{Lane: timelineLaneFirewall, Start: start.Add(45 * time.Second), End: start.Add(45 * time.Second), Label: "evil.example.com", Status: timelineStatusDenied, Count: 1},
+	})
+
+	var buf bytes.Buffer
+	renderTimelineSwimlane(&buf, timeline)
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 4, "swimlane should have a header and one row per event")
+	assert.Contains(t, lines[2], "+00:30", "row should show the offset from the run start")
+	assert.Contains(t, lines[2], "bash: <rm -rf> ✗", "failed events should be marked")
+	assert.Contains(t, lines[3], "denied evil.example.com", "firewall decisions should be labelled")
+
+	mermaid := generateTimelineMermaid(timeline)
+	assert.True(t, strings.HasPrefix(mermaid, "gantt\n"), "mermaid output should be a gantt chart")
+	assert.Contains(t, mermaid, "section Tool calls", "lanes should become sections")
+	assert.Contains(t, mermaid, "bash  <rm -rf> ✗ :crit, e2,", "labels should be sanitized and failures marked critical")
+	assert.Contains(t, mermaid, ":crit, milestone, e3,", "firewall events should be milestones")
+
+	html, err := generateTimelineHTML(timeline)
+	require.NoError(t, err, "HTML should render")
+	assert.Contains(t, html, "Run 7 timeline", "HTML should have a title")
+	assert.Contains(t, html, "&lt;rm -rf&gt;", "labels should be escaped")
+	assert.NotContains(t, html, "<script", "HTML export should be self-contained")
+}
+
+func TestValidateTimelineFormat(t *testing.T) {
+	for _, format := range []string{"", "text", "html", "mermaid"} {
+		assert.NoError(t, validateTimelineFormat(format), "format %q should be valid", format)
+	}
+	assert.Error(t, validateTimelineFormat("svg"), "unknown format should be rejected")
+}
@@ -11,10 +11,11 @@ var agentSessionCodemodLog = logger.New("cli:codemod_agent_session")
 // getAgentTaskToAgentSessionCodemod creates a codemod for migrating create-agent-task to create-agent-session
 func getAgentTaskToAgentSessionCodemod() Codemod {
 	return Codemod{
-		ID:           "agent-task-to-agent-session-migration",
-		Name:         "Migrate create-agent-task to create-agent-session",
-		Description:  "Replaces deprecated 'safe-outputs.create-agent-task' field with 'safe-outputs.create-agent-session'",
-		IntroducedIn: "0.4.0",
+		ID:              "agent-task-to-agent-session-migration",
+		Name:            "Migrate create-agent-task to create-agent-session",
+		Description:     "Replaces deprecated 'safe-outputs.create-agent-task' field with 'safe-outputs.create-agent-session'",
+		IntroducedIn:    "0.4.0",
+		DeprecatedPaths: []string{"safe-outputs.create-agent-task"},
 		Apply: func(content string, frontmatter map[string]any) (string, bool, error) {
 			// Check if safe-outputs.create-agent-task exists
 			safeOutputsValue, hasSafeOutputs := frontmatter["safe-outputs"]
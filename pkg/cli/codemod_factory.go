@@ -14,15 +14,16 @@ type PostTransformFunc func(lines []string, frontmatter map[string]any, fieldVal
 
 // fieldRemovalCodemodConfig holds the configuration for a field-removal codemod.
 type fieldRemovalCodemodConfig struct {
-	ID            string
-	Name          string
-	Description   string
-	IntroducedIn  string
-	ParentKey     string            // Top-level frontmatter key that contains the field
-	FieldKey      string            // Child field to remove from the parent block
-	LogMsg        string            // Debug log message emitted when the codemod is applied
-	Log           *logger.Logger    // Logger for the codemod
-	PostTransform PostTransformFunc // Optional hook for additional transforms after field removal
+	ID              string
+	Name            string
+	Description     string
+	IntroducedIn    string
+	DeprecatedPaths []string          // Schema deprecations migrated by the codemod (see Codemod.DeprecatedPaths)
+	ParentKey       string            // Top-level frontmatter key that contains the field
+	FieldKey        string            // Child field to remove from the parent block
+	LogMsg          string            // Debug log message emitted when the codemod is applied
+	Log             *logger.Logger    // Logger for the codemod
+	PostTransform   PostTransformFunc // Optional hook for additional transforms after field removal
 }
 
 // newFieldRemovalCodemod creates a Codemod that:
@@ -32,10 +33,11 @@ type fieldRemovalCodemodConfig struct {
 //  4. Optionally invokes PostTransform for any additional line-level changes.
 func newFieldRemovalCodemod(cfg fieldRemovalCodemodConfig) Codemod {
 	return Codemod{
-		ID:           cfg.ID,
-		Name:         cfg.Name,
-		Description:  cfg.Description,
-		IntroducedIn: cfg.IntroducedIn,
+		ID:              cfg.ID,
+		Name:            cfg.Name,
+		Description:     cfg.Description,
+		IntroducedIn:    cfg.IntroducedIn,
+		DeprecatedPaths: cfg.DeprecatedPaths,
 		Apply: func(content string, frontmatter map[string]any) (string, bool, error) {
 			parentValue, hasParent := frontmatter[cfg.ParentKey]
 			if !hasParent {
@@ -12,10 +12,11 @@ var githubReposCodemodLog = logger.New("cli:codemod_github_repos")
 // 'repos:' field to 'allowed-repos:' within the tools.github configuration block.
 func getGitHubReposToAllowedReposCodemod() Codemod {
 	return Codemod{
-		ID:           "github-repos-to-allowed-repos",
-		Name:         "Rename 'tools.github.repos' to 'tools.github.allowed-repos'",
-		Description:  "Renames the deprecated 'repos:' field to 'allowed-repos:' inside the tools.github configuration block.",
-		IntroducedIn: "1.0.0",
+		ID:              "github-repos-to-allowed-repos",
+		Name:            "Rename 'tools.github.repos' to 'tools.github.allowed-repos'",
+		Description:     "Renames the deprecated 'repos:' field to 'allowed-repos:' inside the tools.github configuration block.",
+		IntroducedIn:    "1.0.0",
+		DeprecatedPaths: []string{"tools.github.repos"},
 		Apply: func(content string, frontmatter map[string]any) (string, bool, error) {
 			if !hasDeprecatedGitHubReposField(frontmatter) {
 				return content, false, nil
@@ -13,10 +13,11 @@ var mcpNetworkCodemodLog = logger.New("cli:codemod_mcp_network")
 // getMCPNetworkMigrationCodemod creates a codemod for migrating per-server MCP network configuration to top-level network configuration
 func getMCPNetworkMigrationCodemod() Codemod {
 	return Codemod{
-		ID:           "mcp-network-to-top-level-migration",
-		Name:         "Migrate MCP network config to top-level",
-		Description:  "Moves per-server MCP 'network.allowed' configuration to top-level workflow 'network.allowed'. Per-server network configuration is deprecated.",
-		IntroducedIn: "0.6.0",
+		ID:              "mcp-network-to-top-level-migration",
+		Name:            "Migrate MCP network config to top-level",
+		Description:     "Moves per-server MCP 'network.allowed' configuration to top-level workflow 'network.allowed'. Per-server network configuration is deprecated.",
+		IntroducedIn:    "0.6.0",
+		DeprecatedPaths: []string{"mcp-servers.*.network"},
 		Apply: func(content string, frontmatter map[string]any) (string, bool, error) {
 			// Check if mcp-servers section exists
 			mcpServersValue, hasMCPServers := frontmatter["mcp-servers"]
@@ -0,0 +1,304 @@
+package cli
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/parser"
+	"github.com/goccy/go-yaml"
+)
+
+var schemaDeprecationCodemodLog = logger.New("cli:codemod_schema_deprecations")
+
+// schemaDeprecationCodemodsIntroducedIn is the version that introduced schema-derived codemods.
+const schemaDeprecationCodemodsIntroducedIn = "1.0.45"
+
+// schemaReplacementPattern matches replacements that can be migrated without bespoke code:
+// a property name (rename) or a dotted path of property names (move into an object),
+// relative to the object that contains the deprecated field.
+var schemaReplacementPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+(\.[A-Za-z0-9_-]+)*$`)
+
+// getSchemaDeprecationCodemods derives codemods from the deprecation metadata of the main
+// workflow schema. Deprecations already listed in the DeprecatedPaths of a hand-written
+// codemod are skipped, as are deprecations without a usable replacement.
+func getSchemaDeprecationCodemods(manual []Codemod) []Codemod {
+	deprecations, err := parser.GetMainWorkflowSchemaDeprecations()
+	if err != nil {
+		schemaDeprecationCodemodLog.Printf("Failed to load schema deprecations: %v", err)
+		return nil
+	}
+
+	covered := make(map[string]bool)
+	for _, codemod := range manual {
+		for _, path := range codemod.DeprecatedPaths {
+			covered[path] = true
+		}
+	}
+
+	var codemods []Codemod
+	for _, deprecation := range deprecations {
+		if covered[deprecation.Path] || !schemaReplacementPattern.MatchString(deprecation.Replacement) {
+			continue
+		}
+		codemods = append(codemods, newSchemaDeprecationCodemod(deprecation))
+	}
+	schemaDeprecationCodemodLog.Printf("Generated %d codemods from %d schema deprecations", len(codemods), len(deprecations))
+	return codemods
+}
+
+// newSchemaDeprecationCodemod creates a codemod that renames or moves a deprecated field to
+// its replacement, mapping scalar values through the deprecation's value map when present.
+// Occurrences whose replacement already exists are left alone.
+func newSchemaDeprecationCodemod(deprecation parser.SchemaDeprecation) Codemod {
+	path := strings.Split(deprecation.Path, ".")
+	replacement := strings.Split(deprecation.Replacement, ".")
+	target := append(slices.Clone(path[:len(path)-1]), replacement...)
+
+	verb := "Rename"
+	if len(replacement) > 1 {
+		verb = "Move"
+	}
+	description := fmt.Sprintf("%ss the deprecated '%s' field to '%s'", verb, deprecation.Path, strings.Join(target, "."))
+	if len(deprecation.ValueMap) > 0 {
+		description += ", mapping its value to the new field's meaning"
+	}
+	description += ". Generated from the workflow schema deprecation metadata."
+
+	return Codemod{
+		ID:              strings.ReplaceAll(strings.ReplaceAll(deprecation.Path, "*.", ""), ".", "-") + "-to-" + strings.ReplaceAll(deprecation.Replacement, ".", "-"),
+		Name:            fmt.Sprintf("%s '%s' to '%s'", verb, deprecation.Path, strings.Join(target, ".")),
+		Description:     description,
+		IntroducedIn:    schemaDeprecationCodemodsIntroducedIn,
+		DeprecatedPaths: []string{deprecation.Path},
+		Apply: func(content string, frontmatter map[string]any) (string, bool, error) {
+			matches := findDeprecatedFieldMatches(frontmatter, path, replacement, deprecation.ValueMap)
+			if len(matches) == 0 {
+				return content, false, nil
+			}
+			newContent, applied, err := applyFrontmatterLineTransform(content, func(lines []string) ([]string, bool) {
+				modified := false
+				for _, match := range matches {
+					var migrated bool
+					lines, migrated = migrateDeprecatedField(lines, match, replacement, deprecation.ValueMap)
+					modified = modified || migrated
+				}
+				return lines, modified
+			})
+			if applied {
+				schemaDeprecationCodemodLog.Printf("Migrated %d occurrence(s) of deprecated '%s'", len(matches), deprecation.Path)
+			}
+			return newContent, applied, err
+		},
+	}
+}
+
+// deprecatedFieldMatch is a concrete occurrence of a deprecated field in the frontmatter.
+type deprecatedFieldMatch struct {
+	path  []string // Concrete path of the deprecated field, with "*" segments resolved
+	value any      // Current value of the field
+}
+
+// findDeprecatedFieldMatches returns the occurrences of a deprecation path in the frontmatter
+// that can be migrated: the replacement must not exist yet, every intermediate object of the
+// replacement must be a map (or absent), and mapped values must appear in the value map.
+func findDeprecatedFieldMatches(frontmatter map[string]any, path, replacement []string, valueMap map[string]any) []deprecatedFieldMatch {
+	var matches []deprecatedFieldMatch
+
+	var walk func(node map[string]any, prefix []string, rest []string)
+	walk = func(node map[string]any, prefix []string, rest []string) {
+		segment := rest[0]
+		if len(rest) == 1 {
+			value, exists := node[segment]
+			if !exists || isReplacementBlocked(node, replacement) {
+				return
+			}
+			if valueMap != nil {
+				if _, mapped := valueMap[fmt.Sprint(value)]; !mapped {
+					schemaDeprecationCodemodLog.Printf("Skipping %s: value %v has no mapping", strings.Join(append(prefix, segment), "."), value)
+					return
+				}
+			}
+			matches = append(matches, deprecatedFieldMatch{path: append(slices.Clone(prefix), segment), value: value})
+			return
+		}
+
+		keys := []string{segment}
+		if segment == "*" {
+			keys = keys[:0]
+			for key := range node {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+		}
+		for _, key := range keys {
+			if child, ok := node[key].(map[string]any); ok {
+				walk(child, append(slices.Clone(prefix), key), rest[1:])
+			}
+		}
+	}
+	walk(frontmatter, nil, path)
+	return matches
+}
+
+// isReplacementBlocked reports whether the replacement path cannot receive the deprecated
+// field: either it already exists or one of its intermediate values is not a map.
+func isReplacementBlocked(parent map[string]any, replacement []string) bool {
+	current := parent
+	for i, segment := range replacement {
+		value, exists := current[segment]
+		if !exists {
+			return false
+		}
+		if i == len(replacement)-1 {
+			return true
+		}
+		next, ok := value.(map[string]any)
+		if !ok {
+			return true
+		}
+		current = next
+	}
+	return false
+}
+
+// migrateDeprecatedField rewrites the frontmatter lines of one deprecated field occurrence.
+// A single-segment replacement renames the key in place; a dotted replacement moves the
+// field block into the (possibly newly created) object under the same parent.
+func migrateDeprecatedField(lines []string, match deprecatedFieldMatch, replacement []string, valueMap map[string]any) ([]string, bool) {
+	keyIdx := findYAMLKeyLine(lines, match.path)
+	if keyIdx == -1 {
+		return lines, false
+	}
+	oldKey := match.path[len(match.path)-1]
+	newKey := replacement[len(replacement)-1]
+
+	keyLine, renamed := findAndReplaceInLine(lines[keyIdx], oldKey, newKey)
+	if !renamed {
+		return lines, false
+	}
+	if valueMap != nil {
+		mappedLine, ok := mapYAMLScalarLine(keyLine, valueMap[fmt.Sprint(match.value)])
+		if !ok {
+			return lines, false
+		}
+		keyLine = mappedLine
+	}
+
+	if len(replacement) == 1 {
+		result := slices.Clone(lines)
+		result[keyIdx] = keyLine
+		return result, true
+	}
+
+	blockEnd := findYAMLBlockEnd(lines, keyIdx)
+	block := append([]string{keyLine}, lines[keyIdx+1:blockEnd]...)
+	blockIndent := getIndentation(lines[keyIdx])
+	result := slices.Concat(lines[:keyIdx], lines[blockEnd:])
+
+	// Find or create each intermediate object of the replacement under the field's parent
+	parentPath := match.path[:len(match.path)-1]
+	insertAt := keyIdx
+	childIndent := blockIndent
+	for i, segment := range replacement[:len(replacement)-1] {
+		objectPath := append(slices.Clone(parentPath), replacement[:i+1]...)
+		objectIdx := findYAMLKeyLine(result, objectPath)
+		if objectIdx == -1 {
+			result = slices.Insert(result, insertAt, childIndent+segment+":")
+			objectIdx = insertAt
+		} else if _, value, _ := strings.Cut(strings.TrimSpace(result[objectIdx]), ":"); strings.TrimSpace(value) != "" && !strings.HasPrefix(strings.TrimSpace(value), "#") {
+			// Flow-style objects (e.g. "on: { push: {} }") are left for the user to migrate
+			return lines, false
+		}
+		insertAt = objectIdx + 1
+		childIndent = getIndentation(result[objectIdx]) + "  "
+		for _, child := range result[insertAt:findYAMLBlockEnd(result, objectIdx)] {
+			if strings.TrimSpace(child) != "" {
+				childIndent = getIndentation(child)
+				break
+			}
+		}
+	}
+
+	moved := make([]string, 0, len(block))
+	for _, line := range block {
+		if strings.TrimSpace(line) == "" {
+			moved = append(moved, line)
+			continue
+		}
+		moved = append(moved, childIndent+strings.TrimPrefix(line, blockIndent))
+	}
+	return slices.Insert(result, insertAt, moved...), true
+}
+
+// mapYAMLScalarLine replaces the inline scalar value of a "key: value" line, keeping any
+// trailing comment. It returns false when the line has no inline value.
+func mapYAMLScalarLine(line string, value any) (string, bool) {
+	key, rest, found := strings.Cut(line, ":")
+	if !found || strings.TrimSpace(rest) == "" {
+		return line, false
+	}
+	encoded, err := yaml.Marshal(value)
+	if err != nil {
+		return line, false
+	}
+	mapped := key + ": " + strings.TrimSpace(string(encoded))
+	if commentIdx := strings.Index(rest, " #"); commentIdx != -1 {
+		mapped += rest[commentIdx:]
+	}
+	return mapped, true
+}
+
+// findYAMLKeyLine returns the index of the line declaring the given key path in block-style
+// frontmatter lines, or -1 when the path is not present.
+func findYAMLKeyLine(lines []string, path []string) int {
+	start, end := 0, len(lines)
+	keyIdx := -1
+	for _, segment := range path {
+		keyIdx = -1
+		childIndent := -1
+		for i := start; i < end; i++ {
+			trimmed := strings.TrimSpace(lines[i])
+			if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+				continue
+			}
+			indent := len(getIndentation(lines[i]))
+			if childIndent == -1 {
+				childIndent = indent
+			}
+			if indent != childIndent {
+				continue
+			}
+			key, _, found := strings.Cut(trimmed, ":")
+			if found && strings.Trim(key, `"'`) == segment {
+				keyIdx = i
+				break
+			}
+		}
+		if keyIdx == -1 {
+			return -1
+		}
+		start, end = keyIdx+1, findYAMLBlockEnd(lines, keyIdx)
+	}
+	return keyIdx
+}
+
+// findYAMLBlockEnd returns the index just past the last line nested under lines[keyIdx].
+// Trailing blank lines are not considered part of the block.
+func findYAMLBlockEnd(lines []string, keyIdx int) int {
+	keyIndent := len(getIndentation(lines[keyIdx]))
+	end := keyIdx + 1
+	for i := keyIdx + 1; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) == "" {
+			continue
+		}
+		if len(getIndentation(lines[i])) <= keyIndent {
+			break
+		}
+		end = i + 1
+	}
+	return end
+}
@@ -0,0 +1,135 @@
+//go:build !integration
+
+package cli
+
+import (
+	"testing"
+
+	"github.com/github/gh-aw/pkg/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSchemaDeprecationsHaveCodemods fails when a field is deprecated in the workflow schema
+// without a generated or hand-written codemod. Either give the deprecation a replacement
+// ("Use 'new-field' instead") or list its path in the DeprecatedPaths of a manual codemod.
+func TestSchemaDeprecationsHaveCodemods(t *testing.T) {
+	deprecations, err := parser.GetMainWorkflowSchemaDeprecations()
+	require.NoError(t, err)
+	require.NotEmpty(t, deprecations)
+
+	covered := make(map[string][]string)
+	for _, codemod := range GetAllCodemods() {
+		for _, path := range codemod.DeprecatedPaths {
+			covered[path] = append(covered[path], codemod.ID)
+		}
+	}
+	for _, deprecation := range deprecations {
+		assert.NotEmpty(t, covered[deprecation.Path], "deprecated field '%s' has no codemod", deprecation.Path)
+		assert.LessOrEqual(t, len(covered[deprecation.Path]), 1, "deprecated field '%s' is migrated by several codemods: %v", deprecation.Path, covered[deprecation.Path])
+	}
+}
+
+func TestSchemaDeprecationCodemod_Rename(t *testing.T) {
+	codemod := newSchemaDeprecationCodemod(parser.SchemaDeprecation{Path: "tools.github.toolset", Replacement: "toolsets"})
+	assert.Equal(t, "tools-github-toolset-to-toolsets", codemod.ID)
+
+	content := `---
+tools:
+  github:
+    toolset: [repos, issues] # scoped
+    mode: remote
+---
+
+# Test
+`
+	frontmatter := map[string]any{"tools": map[string]any{"github": map[string]any{"toolset": []any{"repos", "issues"}, "mode": "remote"}}}
+	result, applied, err := codemod.Apply(content, frontmatter)
+	require.NoError(t, err)
+	assert.True(t, applied)
+	assert.Contains(t, result, "    toolsets: [repos, issues] # scoped\n    mode: remote")
+
+	frontmatter["tools"].(map[string]any)["github"].(map[string]any)["toolsets"] = []any{"default"}
+	_, applied, err = codemod.Apply(content, frontmatter)
+	require.NoError(t, err)
+	assert.False(t, applied, "fields whose replacement already exists should be left alone")
+}
+
+func TestSchemaDeprecationCodemod_ValueMap(t *testing.T) {
+	codemod := newSchemaDeprecationCodemod(parser.SchemaDeprecation{
+		Path:        "infer",
+		Replacement: "disable-model-invocation",
+		ValueMap:    map[string]any{"true": false, "false": true},
+	})
+
+	content := `---
+engine: copilot
+infer: false
+---
+
+# Test
+`
+	result, applied, err := codemod.Apply(content, map[string]any{"engine": "copilot", "infer": false})
+	require.NoError(t, err)
+	assert.True(t, applied)
+	assert.Contains(t, result, "\ndisable-model-invocation: true\n")
+	assert.NotContains(t, result, "infer:")
+
+	_, applied, err = codemod.Apply("---\ninfer: ${{ vars.INFER }}\n---\n", map[string]any{"infer": "${{ vars.INFER }}"})
+	require.NoError(t, err)
+	assert.False(t, applied, "values without a mapping should be left alone")
+}
+
+func TestSchemaDeprecationCodemod_MoveIntoObject(t *testing.T) {
+	codemod := newSchemaDeprecationCodemod(parser.SchemaDeprecation{Path: "mcp-servers.*.allowed", Replacement: "network.allowed"})
+
+	content := `---
+mcp-servers:
+  fetch:
+    command: fetch
+    allowed:
+      - example.com
+    network:
+      mode: strict
+  search:
+    command: search
+    allowed: [api.example.com]
+---
+
+# Test
+`
+	frontmatter := map[string]any{"mcp-servers": map[string]any{
+		"fetch":  map[string]any{"command": "fetch", "allowed": []any{"example.com"}, "network": map[string]any{"mode": "strict"}},
+		"search": map[string]any{"command": "search", "allowed": []any{"api.example.com"}},
+	}}
+	result, applied, err := codemod.Apply(content, frontmatter)
+	require.NoError(t, err)
+	assert.True(t, applied)
+	assert.Contains(t, result, `  fetch:
+    command: fetch
+    network:
+      allowed:
+        - example.com
+      mode: strict
+  search:
+    command: search
+    network:
+      allowed: [api.example.com]
+---`)
+}
+
+func TestFindYAMLKeyLine(t *testing.T) {
+	lines := []string{
+		"on:",
+		"  # comment",
+		"  issues:",
+		"    types: [opened]",
+		"tools:",
+		"  github:",
+		"    \"issues\": true",
+	}
+	assert.Equal(t, 2, findYAMLKeyLine(lines, []string{"on", "issues"}))
+	assert.Equal(t, 6, findYAMLKeyLine(lines, []string{"tools", "github", "issues"}))
+	assert.Equal(t, -1, findYAMLKeyLine(lines, []string{"issues"}), "nested keys should not match top-level paths")
+	assert.Equal(t, -1, findYAMLKeyLine(lines, []string{"on", "types"}))
+}
@@ -17,10 +17,11 @@ var serenaImportCodemodLog = logger.New("cli:codemod_serena_import")
 // shared/mcp/serena.md. The existing source: pin is preserved unchanged.
 func getSerenaToSharedImportCodemod() Codemod {
 	return Codemod{
-		ID:           "serena-tools-to-shared-import",
-		Name:         "Migrate tools.serena or engine.tools.serena to shared Serena import",
-		Description:  "Removes 'tools.serena' or 'engine.tools.serena' and adds an equivalent 'imports' entry using shared/mcp/serena.md with languages. The existing 'source:' pin is preserved.",
-		IntroducedIn: "1.0.0",
+		ID:              "serena-tools-to-shared-import",
+		Name:            "Migrate tools.serena or engine.tools.serena to shared Serena import",
+		Description:     "Removes 'tools.serena' or 'engine.tools.serena' and adds an equivalent 'imports' entry using shared/mcp/serena.md with languages. The existing 'source:' pin is preserved.",
+		IntroducedIn:    "1.0.0",
+		DeprecatedPaths: []string{"tools.serena"},
 		Apply: func(content string, frontmatter map[string]any) (string, bool, error) {
 			languages, ok := findSerenaLanguagesForMigration(frontmatter)
 			isListForm := false
@@ -11,10 +11,11 @@ var slashCommandCodemodLog = logger.New("cli:codemod_slash_command")
 // getCommandToSlashCommandCodemod creates a codemod for migrating on.command to on.slash_command
 func getCommandToSlashCommandCodemod() Codemod {
 	return Codemod{
-		ID:           "command-to-slash-command-migration",
-		Name:         "Migrate on.command to on.slash_command",
-		Description:  "Replaces deprecated 'on.command' field with 'on.slash_command'",
-		IntroducedIn: "0.2.0",
+		ID:              "command-to-slash-command-migration",
+		Name:            "Migrate on.command to on.slash_command",
+		Description:     "Replaces deprecated 'on.command' field with 'on.slash_command'",
+		IntroducedIn:    "0.2.0",
+		DeprecatedPaths: []string{"on.command"},
 		Apply: func(content string, frontmatter map[string]any) (string, bool, error) {
 			// Check if on.command exists
 			onValue, hasOn := frontmatter["on"]
@@ -0,0 +1,129 @@
+package cli
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/logger"
+)
+
+var codeOwnersLog = logger.New("cli:codeowners")
+
+// codeOwnersLocations lists the CODEOWNERS locations in the order GitHub checks them
+var codeOwnersLocations = []string{".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS"}
+
+// codeOwnersRule is a single CODEOWNERS line: a path pattern and its owners
+type codeOwnersRule struct {
+	pattern string
+	regex   *regexp.Regexp
+	owners  []string
+}
+
+// CodeOwners resolves the owners of repository paths from a CODEOWNERS file
+type CodeOwners struct {
+	rules []codeOwnersRule
+}
+
+// loadCodeOwners reads the first CODEOWNERS file found in the repository.
+// A repository without a CODEOWNERS file yields an empty (ownerless) result.
+func loadCodeOwners(gitRoot string) (*CodeOwners, error) {
+	return loadCodeOwnersWith(localRepoFileReader(gitRoot))
+}
+
+// loadCodeOwnersWith reads the first CODEOWNERS file returned by read, which
+// may read a local checkout or a remote repository
+func loadCodeOwnersWith(read repoFileReader) (*CodeOwners, error) {
+	for _, location := range codeOwnersLocations {
+		content, err := read(location)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return nil, err
+		}
+		codeOwnersLog.Printf("Loading CODEOWNERS from %s", location)
+		return parseCodeOwners(strings.Split(string(content), "\n")), nil
+	}
+	codeOwnersLog.Print("No CODEOWNERS file found")
+	return &CodeOwners{}, nil
+}
+
+// parseCodeOwners parses CODEOWNERS lines. Comments and blank lines are ignored.
+func parseCodeOwners(lines []string) *CodeOwners {
+	owners := &CodeOwners{}
+	for _, line := range lines {
+		if idx := strings.Index(line, "#"); idx >= 0 {
+			line = line[:idx]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		owners.rules = append(owners.rules, codeOwnersRule{
+			pattern: fields[0],
+			regex:   compileCodeOwnersPattern(fields[0]),
+			owners:  fields[1:],
+		})
+	}
+	codeOwnersLog.Printf("Parsed %d CODEOWNERS rules", len(owners.rules))
+	return owners
+}
+
+// Owners returns the owners of a repository-relative path. As on GitHub, the
+// last matching rule wins; a matching rule without owners clears ownership.
+func (c *CodeOwners) Owners(path string) []string {
+	if c == nil {
+		return nil
+	}
+	path = strings.TrimPrefix(filepath.ToSlash(path), "/")
+	for i := len(c.rules) - 1; i >= 0; i-- {
+		if c.rules[i].regex.MatchString(path) {
+			return c.rules[i].owners
+		}
+	}
+	return nil
+}
+
+// compileCodeOwnersPattern converts a gitignore-style CODEOWNERS pattern to a
+// regular expression matching repository-relative paths
+func compileCodeOwnersPattern(pattern string) *regexp.Regexp {
+	dirOnly := strings.HasSuffix(pattern, "/")
+	trimmed := strings.TrimSuffix(pattern, "/")
+	// Patterns containing a slash (other than a trailing one) are anchored to the
+	// repository root; all others match at any depth.
+	anchored := strings.Contains(trimmed, "/")
+	trimmed = strings.TrimPrefix(trimmed, "/")
+
+	var b strings.Builder
+	if anchored {
+		b.WriteString("^")
+	} else {
+		b.WriteString("^(?:.*/)?")
+	}
+	for i := 0; i < len(trimmed); i++ {
+		switch {
+		case strings.HasPrefix(trimmed[i:], "**/"):
+			b.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(trimmed[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case trimmed[i] == '*':
+			b.WriteString("[^/]*")
+		case trimmed[i] == '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(trimmed[i])))
+		}
+	}
+	// A pattern naming a directory also matches everything beneath it
+	if dirOnly {
+		b.WriteString("/.*$")
+	} else {
+		b.WriteString("(?:/.*)?$")
+	}
+	return regexp.MustCompile(b.String())
+}
@@ -0,0 +1,68 @@
+//go:build !integration
+
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCodeOwnersOwners(t *testing.T) {
+	owners := parseCodeOwners([]string{
+		"# Default owners",
+		"*                          @org/platform",
+		"/.github/workflows/        @org/automation",
+		".github/workflows/docs-*.md @org/docs @octocat",
+		"*.lock.yml                 # generated files have no owner",
+		"docs/**/api               @org/api",
+	})
+
+	tests := []struct {
+		path string
+		want []string
+	}{
+		{path: "README.md", want: []string{"@org/platform"}},
+		{path: ".github/workflows/triage.md", want: []string{"@org/automation"}},
+		{path: ".github/workflows/docs-update.md", want: []string{"@org/docs", "@octocat"}},
+		{path: ".github/workflows/triage.lock.yml", want: []string{}},
+		{path: "docs/v1/reference/api/index.md", want: []string{"@org/api"}},
+		{path: "src/.github/workflows/triage.md", want: []string{"@org/platform"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			assert.Equal(t, tt.want, owners.Owners(tt.path), "last matching rule should win")
+		})
+	}
+}
+
+func TestLoadCodeOwners(t *testing.T) {
+	dir := t.TempDir()
+	empty, err := loadCodeOwners(dir)
+	require.NoError(t, err, "missing CODEOWNERS should not be an error")
+	assert.Nil(t, empty.Owners("a.md"), "missing CODEOWNERS should have no owners")
+
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, ".github"), 0o755), "failed to create .github")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".github", "CODEOWNERS"), []byte("* @org/team\n"), 0o600), "failed to write CODEOWNERS")
+	loaded, err := loadCodeOwners(dir)
+	require.NoError(t, err, "CODEOWNERS should load")
+	assert.Equal(t, []string{"@org/team"}, loaded.Owners("a.md"), "rules from .github/CODEOWNERS should apply")
+}
+
+func TestLoadCodeOwnersWith(t *testing.T) {
+	files := map[string]string{"CODEOWNERS": "*.md @org/docs\n"}
+	read := func(path string) ([]byte, error) {
+		content, ok := files[path]
+		if !ok {
+			return nil, os.ErrNotExist
+		}
+		return []byte(content), nil
+	}
+
+	owners, err := loadCodeOwnersWith(read)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"@org/docs"}, owners.Owners(".github/workflows/triage.md"), "CODEOWNERS should be read through the reader")
+}
@@ -655,7 +655,7 @@ This workflow uses an include.
 	}
 
 	// Run cleanup
-	err = cleanupOrphanedIncludes(true)
+	err = cleanupOrphanedIncludes("", true)
 	if err != nil {
 		t.Fatalf("cleanupOrphanedIncludes failed: %v", err)
 	}
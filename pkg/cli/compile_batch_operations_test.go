@@ -9,29 +9,29 @@ import (
 )
 
 func TestRunActionlintOnFiles_EmptyList(t *testing.T) {
-	err := RunActionlintOnFiles(nil, false, false)
+	err := RunActionlintOnFiles(nil, false, false, nil)
 	if err != nil {
 		t.Fatalf("expected nil error for empty lock file list, got %v", err)
 	}
 }
 
 func TestRunBatchDirectoryTool_NonStrictSwallowsErrors(t *testing.T) {
-	runner := func(_ string, _ bool, _ bool) error {
+	runner := func(_ string, _ bool, _ bool, _ *SecurityFindingCollector) error {
 		return errors.New("boom")
 	}
 
-	err := runBatchDirectoryTool("poutine", "/tmp/workflows", false, false, runner)
+	err := runBatchDirectoryTool("poutine", "/tmp/workflows", false, false, nil, runner)
 	if err != nil {
 		t.Fatalf("expected nil error in non-strict mode, got %v", err)
 	}
 }
 
 func TestRunBatchDirectoryTool_StrictWrapsErrors(t *testing.T) {
-	runner := func(_ string, _ bool, _ bool) error {
+	runner := func(_ string, _ bool, _ bool, _ *SecurityFindingCollector) error {
 		return errors.New("boom")
 	}
 
-	err := runBatchDirectoryTool("runner-guard", "/tmp/workflows", false, true, runner)
+	err := runBatchDirectoryTool("runner-guard", "/tmp/workflows", false, true, nil, runner)
 	if err == nil {
 		t.Fatal("expected error in strict mode, got nil")
 	}
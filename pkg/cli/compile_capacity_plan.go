@@ -0,0 +1,113 @@
+package cli
+
+// compile_capacity_plan.go renders the agent concurrency capacity plan for the
+// --stats flag. It shows, for each priority class of the aw.json concurrency
+// budget, how many agent jobs may be active before the class has to queue and
+// which workflows belong to the class.
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/gitutil"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/workflow"
+)
+
+var compileCapacityPlanLog = logger.New("cli:compile_capacity_plan")
+
+// maxCapacityPlanExamples limits the workflow names listed per priority class.
+const maxCapacityPlanExamples = 3
+
+// agentCapacityClass is one row of the capacity plan.
+type agentCapacityClass struct {
+	Priority  workflow.AgentPriority
+	Limit     int      // agent jobs that may be active before this class queues
+	Reserved  int      // slots only this class and the classes above it may use
+	Workflows []string // workflow names in this class
+}
+
+// buildAgentCapacityPlan groups the compiled workflows by priority class.
+// Workflows compiled without the budget step are returned as unbudgeted.
+func buildAgentCapacityPlan(budget *workflow.AgentConcurrencyConfig, statsList []*WorkflowStats) ([]agentCapacityClass, []string) {
+	byPriority := make(map[workflow.AgentPriority][]string)
+	var unbudgeted []string
+	for _, stats := range statsList {
+		name := strings.TrimSuffix(stats.Workflow, ".lock.yml")
+		if stats.AgentPriority == "" {
+			unbudgeted = append(unbudgeted, name)
+			continue
+		}
+		priority := workflow.AgentPriority(stats.AgentPriority)
+		byPriority[priority] = append(byPriority[priority], name)
+	}
+
+	classes := make([]agentCapacityClass, 0, len(workflow.AgentPriorities))
+	for i, priority := range workflow.AgentPriorities {
+		reserved := budget.Limit(priority)
+		if i+1 < len(workflow.AgentPriorities) {
+			reserved -= budget.Limit(workflow.AgentPriorities[i+1])
+		}
+		classes = append(classes, agentCapacityClass{
+			Priority:  priority,
+			Limit:     budget.Limit(priority),
+			Reserved:  reserved,
+			Workflows: byPriority[priority],
+		})
+	}
+	return classes, unbudgeted
+}
+
+// displayAgentCapacityPlan renders the agent concurrency capacity plan to stderr.
+// Nothing is rendered when aw.json does not configure a concurrency budget.
+//
+// Only rendered in regular (non-JSON) output mode.
+func displayAgentCapacityPlan(statsList []*WorkflowStats) {
+	if len(statsList) == 0 {
+		return
+	}
+	gitRoot, err := gitutil.FindGitRoot()
+	if err != nil {
+		compileCapacityPlanLog.Printf("Not in a git repository, skipping capacity plan: %v", err)
+		return
+	}
+	repoConfig, err := workflow.LoadRepoConfig(gitRoot)
+	if err != nil {
+		compileCapacityPlanLog.Printf("Failed to load repo config, skipping capacity plan: %v", err)
+		return
+	}
+	budget := repoConfig.AgentConcurrency()
+	if budget == nil {
+		return
+	}
+
+	classes, unbudgeted := buildAgentCapacityPlan(budget, statsList)
+	rows := make([][]string, 0, len(classes))
+	for _, class := range classes {
+		examples := class.Workflows
+		if len(examples) > maxCapacityPlanExamples {
+			examples = append(examples[:maxCapacityPlanExamples:maxCapacityPlanExamples], fmt.Sprintf("+%d more", len(class.Workflows)-maxCapacityPlanExamples))
+		}
+		rows = append(rows, []string{
+			string(class.Priority),
+			fmt.Sprintf("%d of %d", class.Limit, budget.MaxAgentJobs),
+			strconv.Itoa(class.Reserved),
+			strconv.Itoa(len(class.Workflows)),
+			strings.Join(examples, ", "),
+		})
+	}
+
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprint(os.Stderr, console.RenderTable(console.TableConfig{
+		Title:   "Agent Capacity Plan",
+		Headers: []string{"PRIORITY", "SLOTS", "RESERVED", "WORKFLOWS", "EXAMPLES"},
+		Rows:    rows,
+	}))
+	fmt.Fprintf(os.Stderr, "  Runs wait up to %d minute(s) for a slot before starting anyway.\n", budget.QueueTimeout())
+	if len(unbudgeted) > 0 {
+		fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("%d workflow(s) were compiled without the agent concurrency budget: %s. Recompile them to apply it.", len(unbudgeted), strings.Join(unbudgeted, ", "))))
+	}
+}
@@ -0,0 +1,63 @@
+//go:build !integration
+
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollectWorkflowStats_AgentPriority(t *testing.T) {
+	lockFilePath := filepath.Join(t.TempDir(), "triage.lock.yml")
+	lockYAML := `name: Triage
+on: [issues]
+jobs:
+  activation:
+    runs-on: ubuntu-slim
+    steps:
+      - name: Wait for agent concurrency slot (high)
+        id: agent_budget
+        uses: actions/github-script@v8
+        env:
+          GH_AW_AGENT_PRIORITY: "high"
+          GH_AW_AGENT_LIMIT: "5"
+`
+	require.NoError(t, os.WriteFile(lockFilePath, []byte(lockYAML), 0o600), "failed to write lock file")
+
+	stats, err := collectWorkflowStats(lockFilePath)
+	require.NoError(t, err, "lock file should parse")
+	assert.Equal(t, "high", stats.AgentPriority, "priority should be read from the budget step")
+}
+
+func TestBuildAgentCapacityPlan(t *testing.T) {
+	budget := &workflow.AgentConcurrencyConfig{MaxAgentJobs: 5, Reserved: workflow.AgentReservedSlots{High: 2, Normal: 1}}
+	statsList := []*WorkflowStats{
+		{Workflow: "fix.lock.yml", AgentPriority: "high"},
+		{Workflow: "review.lock.yml", AgentPriority: "normal"},
+		{Workflow: "daily-docs.lock.yml", AgentPriority: "low"},
+		{Workflow: "daily-news.lock.yml", AgentPriority: "low"},
+		{Workflow: "stale.lock.yml"},
+	}
+
+	classes, unbudgeted := buildAgentCapacityPlan(budget, statsList)
+	require.Len(t, classes, 3, "plan should have one row per priority class")
+
+	assert.Equal(t, workflow.AgentPriorityHigh, classes[0].Priority, "high priority should come first")
+	assert.Equal(t, 5, classes[0].Limit, "high priority may use every slot")
+	assert.Equal(t, 2, classes[0].Reserved, "high priority reservation should match the config")
+	assert.Equal(t, []string{"fix"}, classes[0].Workflows)
+
+	assert.Equal(t, 3, classes[1].Limit, "normal priority excludes the high reservation")
+	assert.Equal(t, 1, classes[1].Reserved, "normal priority reservation should match the config")
+
+	assert.Equal(t, 2, classes[2].Limit, "low priority excludes all reservations")
+	assert.Equal(t, 2, classes[2].Reserved, "low priority owns the unreserved slots")
+	assert.Equal(t, []string{"daily-docs", "daily-news"}, classes[2].Workflows)
+
+	assert.Equal(t, []string{"stale"}, unbudgeted, "workflows without the budget step should be reported")
+}
@@ -35,6 +35,9 @@ type CompileConfig struct {
 	ValidateImages         bool     // Require Docker to be available for container image validation (fail instead of skipping when Docker is unavailable)
 	PriorManifestFile      string   // Path to a JSON file containing pre-cached manifests (map[lockFile]*GHAWManifest) collected at MCP server startup; takes precedence over git HEAD / filesystem reads for safe update enforcement
 	GHESCompat             bool     // Enable GHES compatibility mode: emit v3.x artifact action pins instead of v7/v8 (overrides aw.json ghes field)
+	SARIFFile              string   // Write all security scanner findings and compiler warnings to this SARIF file
+	SARIFBaseline          string   // Path to the security baseline file (default: .github/aw/security-baseline.json)
+	UpdateSARIFBaseline    bool     // Rewrite the security baseline to accept all current findings
 }
 
 // CompileValidationError represents a single validation error or warning
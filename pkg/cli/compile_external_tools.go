@@ -34,32 +34,36 @@ var compileExternalToolsLog = logger.New("cli:compile_external_tools")
 
 // RunActionlintOnFiles runs actionlint on multiple lock files in a single batch.
 // This is more efficient than running actionlint once per file.
-func RunActionlintOnFiles(lockFiles []string, verbose bool, strict bool) error {
-	return runBatchLockFileTool("actionlint", lockFiles, verbose, strict, func(files []string, runVerbose bool, runStrict bool) error {
-		return runActionlintOnFiles(context.Background(), files, runVerbose, runStrict)
+// Errors are recorded in findings when it is non-nil.
+func RunActionlintOnFiles(lockFiles []string, verbose bool, strict bool, findings *SecurityFindingCollector) error {
+	return runBatchLockFileTool("actionlint", lockFiles, verbose, strict, findings, func(files []string, runVerbose bool, runStrict bool, findings *SecurityFindingCollector) error {
+		return runActionlintOnFiles(context.Background(), files, runVerbose, runStrict, findings)
 	})
 }
 
 // RunZizmorOnFiles runs zizmor on multiple lock files in a single batch.
 // This is more efficient than running zizmor once per file.
-func RunZizmorOnFiles(lockFiles []string, verbose bool, strict bool) error {
-	return runBatchLockFileTool("zizmor", lockFiles, verbose, strict, runZizmorOnFiles)
+// Findings are recorded in findings when it is non-nil.
+func RunZizmorOnFiles(lockFiles []string, verbose bool, strict bool, findings *SecurityFindingCollector) error {
+	return runBatchLockFileTool("zizmor", lockFiles, verbose, strict, findings, runZizmorOnFiles)
 }
 
 // RunPoutineOnDirectory runs poutine security scanner once on a directory.
 // Poutine scans all workflows in a directory, so it only needs to run once.
-func RunPoutineOnDirectory(workflowDir string, verbose bool, strict bool) error {
-	return runPoutineOnDirectory(workflowDir, verbose, strict)
+// Findings are recorded in findings when it is non-nil.
+func RunPoutineOnDirectory(workflowDir string, verbose bool, strict bool, findings *SecurityFindingCollector) error {
+	return runPoutineOnDirectory(workflowDir, verbose, strict, findings)
 }
 
 // RunRunnerGuardOnDirectory runs runner-guard taint analysis scanner once on a directory.
 // Runner-guard scans all workflows in a directory, so it only needs to run once.
-func RunRunnerGuardOnDirectory(workflowDir string, verbose bool, strict bool) error {
-	return runRunnerGuardOnDirectory(workflowDir, verbose, strict)
+// Findings are recorded in findings when it is non-nil.
+func RunRunnerGuardOnDirectory(workflowDir string, verbose bool, strict bool, findings *SecurityFindingCollector) error {
+	return runRunnerGuardOnDirectory(workflowDir, verbose, strict, findings)
 }
 
 // runBatchLockFileTool runs a batch tool on lock files with uniform error handling
-func runBatchLockFileTool(toolName string, lockFiles []string, verbose bool, strict bool, runner func([]string, bool, bool) error) error {
+func runBatchLockFileTool(toolName string, lockFiles []string, verbose bool, strict bool, findings *SecurityFindingCollector, runner func([]string, bool, bool, *SecurityFindingCollector) error) error {
 	if len(lockFiles) == 0 {
 		compileExternalToolsLog.Printf("No lock files to process with %s", toolName)
 		return nil
@@ -67,14 +71,14 @@ func runBatchLockFileTool(toolName string, lockFiles []string, verbose bool, str
 
 	compileExternalToolsLog.Printf("Running batch %s on %d lock files", toolName, len(lockFiles))
 
-	return handleBatchToolError(toolName, runner(lockFiles, verbose, strict), strict, verbose)
+	return handleBatchToolError(toolName, runner(lockFiles, verbose, strict, findings), strict, verbose)
 }
 
 // runBatchDirectoryTool runs a directory-based batch tool with uniform error handling
-func runBatchDirectoryTool(toolName string, workflowDir string, verbose bool, strict bool, runner func(string, bool, bool) error) error {
+func runBatchDirectoryTool(toolName string, workflowDir string, verbose bool, strict bool, findings *SecurityFindingCollector, runner func(string, bool, bool, *SecurityFindingCollector) error) error {
 	compileExternalToolsLog.Printf("Running batch %s on directory: %s", toolName, workflowDir)
 
-	return handleBatchToolError(toolName, runner(workflowDir, verbose, strict), strict, verbose)
+	return handleBatchToolError(toolName, runner(workflowDir, verbose, strict, findings), strict, verbose)
 }
 
 // handleBatchToolError applies uniform strict/non-strict error handling for batch tool results.
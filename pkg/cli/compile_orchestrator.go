@@ -8,6 +8,7 @@ import (
 
 	"github.com/github/gh-aw/pkg/console"
 	"github.com/github/gh-aw/pkg/constants"
+	"github.com/github/gh-aw/pkg/gitutil"
 	"github.com/github/gh-aw/pkg/logger"
 	"github.com/github/gh-aw/pkg/workflow"
 )
@@ -42,6 +43,16 @@ func CompileWorkflows(ctx context.Context, config CompileConfig) ([]*workflow.Wo
 		initActionlintStats()
 	}
 
+	// Collect security findings for the SARIF report
+	var findings *SecurityFindingCollector
+	if config.SARIFFile != "" {
+		gitRoot, err := gitutil.FindGitRoot()
+		if err != nil {
+			return nil, fmt.Errorf("--sarif must be used inside a git repository: %w", err)
+		}
+		findings = newSecurityFindingCollector(gitRoot)
+	}
+
 	// Track compilation statistics
 	stats := &CompilationStats{}
 
@@ -102,9 +113,9 @@ func CompileWorkflows(ctx context.Context, config CompileConfig) ([]*workflow.Wo
 	// Compile specific files or all files in directory
 	if len(config.MarkdownFiles) > 0 {
 		// Compile specific workflow files
-		return compileSpecificFiles(compiler, config, stats, &validationResults)
+		return compileSpecificFiles(compiler, config, stats, &validationResults, findings)
 	}
 
 	// Compile all workflow files in directory
-	return compileAllFilesInDirectory(compiler, config, workflowDir, stats, &validationResults)
+	return compileAllFilesInDirectory(compiler, config, workflowDir, stats, &validationResults, findings)
 }
@@ -43,6 +43,7 @@ func compileSpecificFiles(
 	config CompileConfig,
 	stats *CompilationStats,
 	validationResults *[]ValidationResult,
+	findings *SecurityFindingCollector,
 ) ([]*workflow.WorkflowData, error) {
 	compileOrchestrationLog.Printf("Compiling %d specific workflow files", len(config.MarkdownFiles))
 
@@ -98,7 +99,7 @@ func compileSpecificFiles(
 		fileResult := compileWorkflowFile(
 			compiler, resolvedFile, config.Verbose, config.JSONOutput,
 			config.NoEmit, false, false, false, // Disable per-file security tools
-			config.Strict, shouldValidate,
+			config.Strict, shouldValidate, findings,
 		)
 
 		if !fileResult.success {
@@ -135,7 +136,7 @@ func compileSpecificFiles(
 
 	// Run batch actionlint on all collected lock files
 	if config.Actionlint && !config.NoEmit && len(lockFilesForActionlint) > 0 {
-		if err := RunActionlintOnFiles(lockFilesForActionlint, config.Verbose && !config.JSONOutput, config.Strict); err != nil {
+		if err := RunActionlintOnFiles(lockFilesForActionlint, config.Verbose && !config.JSONOutput, config.Strict, findings); err != nil {
 			if config.Strict {
 				return workflowDataList, err
 			}
@@ -144,7 +145,7 @@ func compileSpecificFiles(
 
 	// Run batch zizmor on all collected lock files
 	if config.Zizmor && !config.NoEmit && len(lockFilesForZizmor) > 0 {
-		if err := RunZizmorOnFiles(lockFilesForZizmor, config.Verbose && !config.JSONOutput, config.Strict); err != nil {
+		if err := RunZizmorOnFiles(lockFilesForZizmor, config.Verbose && !config.JSONOutput, config.Strict, findings); err != nil {
 			if config.Strict {
 				return workflowDataList, err
 			}
@@ -155,7 +156,7 @@ func compileSpecificFiles(
 	// Get the directory from the first lock file (all should be in same directory)
 	if config.Poutine && !config.NoEmit && len(lockFilesForDirTools) > 0 {
 		workflowDir := filepath.Dir(lockFilesForDirTools[0])
-		if err := runBatchDirectoryTool("poutine", workflowDir, config.Verbose && !config.JSONOutput, config.Strict, RunPoutineOnDirectory); err != nil {
+		if err := runBatchDirectoryTool("poutine", workflowDir, config.Verbose && !config.JSONOutput, config.Strict, findings, RunPoutineOnDirectory); err != nil {
 			if config.Strict {
 				return workflowDataList, err
 			}
@@ -166,7 +167,7 @@ func compileSpecificFiles(
 	// Get the directory from the first lock file (all should be in same directory)
 	if config.RunnerGuard && !config.NoEmit && len(lockFilesForDirTools) > 0 {
 		workflowDir := filepath.Dir(lockFilesForDirTools[0])
-		if err := runBatchDirectoryTool("runner-guard", workflowDir, config.Verbose && !config.JSONOutput, config.Strict, RunRunnerGuardOnDirectory); err != nil {
+		if err := runBatchDirectoryTool("runner-guard", workflowDir, config.Verbose && !config.JSONOutput, config.Strict, findings, RunRunnerGuardOnDirectory); err != nil {
 			if config.Strict {
 				return workflowDataList, err
 			}
@@ -182,6 +183,9 @@ func compileSpecificFiles(
 	// Display safe update warnings (emitted as prompts for the calling agent)
 	displaySafeUpdateWarnings(compiler, config.JSONOutput)
 
+	// Write the combined SARIF report; a baseline violation is returned after the summary is shown
+	sarifErr := writeSecurityReport(config, findings)
+
 	// Post-processing
 	if err := runPostProcessing(compiler, workflowDataList, config, compiledCount); err != nil {
 		return workflowDataList, err
@@ -199,7 +203,7 @@ func compileSpecificFiles(
 		return workflowDataList, errors.New("compilation failed")
 	}
 
-	return workflowDataList, nil
+	return workflowDataList, sarifErr
 }
 
 // compileAllFilesInDirectory compiles all workflow files in a directory
@@ -209,6 +213,7 @@ func compileAllFilesInDirectory(
 	workflowDir string,
 	stats *CompilationStats,
 	validationResults *[]ValidationResult,
+	findings *SecurityFindingCollector,
 ) ([]*workflow.WorkflowData, error) {
 	// Find git root for consistent behavior
 	gitRoot, err := gitutil.FindGitRoot()
@@ -275,7 +280,7 @@ func compileAllFilesInDirectory(
 		fileResult := compileWorkflowFile(
 			compiler, file, config.Verbose, config.JSONOutput,
 			config.NoEmit, false, false, false, // Disable per-file security tools
-			config.Strict, shouldValidate,
+			config.Strict, shouldValidate, findings,
 		)
 
 		if !fileResult.success {
@@ -312,7 +317,7 @@ func compileAllFilesInDirectory(
 
 	// Run batch actionlint
 	if config.Actionlint && !config.NoEmit && len(lockFilesForActionlint) > 0 {
-		if err := RunActionlintOnFiles(lockFilesForActionlint, config.Verbose && !config.JSONOutput, config.Strict); err != nil {
+		if err := RunActionlintOnFiles(lockFilesForActionlint, config.Verbose && !config.JSONOutput, config.Strict, findings); err != nil {
 			if config.Strict {
 				return workflowDataList, err
 			}
@@ -321,7 +326,7 @@ func compileAllFilesInDirectory(
 
 	// Run batch zizmor
 	if config.Zizmor && !config.NoEmit && len(lockFilesForZizmor) > 0 {
-		if err := RunZizmorOnFiles(lockFilesForZizmor, config.Verbose && !config.JSONOutput, config.Strict); err != nil {
+		if err := RunZizmorOnFiles(lockFilesForZizmor, config.Verbose && !config.JSONOutput, config.Strict, findings); err != nil {
 			if config.Strict {
 				return workflowDataList, err
 			}
@@ -330,7 +335,7 @@ func compileAllFilesInDirectory(
 
 	// Run batch poutine once on the workflow directory
 	if config.Poutine && !config.NoEmit && len(lockFilesForDirTools) > 0 {
-		if err := runBatchDirectoryTool("poutine", workflowsDir, config.Verbose && !config.JSONOutput, config.Strict, RunPoutineOnDirectory); err != nil {
+		if err := runBatchDirectoryTool("poutine", workflowsDir, config.Verbose && !config.JSONOutput, config.Strict, findings, RunPoutineOnDirectory); err != nil {
 			if config.Strict {
 				return workflowDataList, err
 			}
@@ -339,13 +344,18 @@ func compileAllFilesInDirectory(
 
 	// Run batch runner-guard once on the workflow directory
 	if config.RunnerGuard && !config.NoEmit && len(lockFilesForDirTools) > 0 {
-		if err := runBatchDirectoryTool("runner-guard", workflowsDir, config.Verbose && !config.JSONOutput, config.Strict, RunRunnerGuardOnDirectory); err != nil {
+		if err := runBatchDirectoryTool("runner-guard", workflowsDir, config.Verbose && !config.JSONOutput, config.Strict, findings, RunRunnerGuardOnDirectory); err != nil {
 			if config.Strict {
 				return workflowDataList, err
 			}
 		}
 	}
 
+	// Check dispatch, call and workflow_run relationships across all compiled workflows
+	if !config.NoEmit && !config.JSONOutput {
+		checkOrchestrationGraph(compiler, workflowsDir)
+	}
+
 	// Emit recommendation when many slash commands are present without centralized strategy.
 	displayCentralizedSlashCommandRecommendation(compiler, workflowDataList, config.JSONOutput)
 
@@ -358,6 +368,9 @@ func compileAllFilesInDirectory(
 	// Display safe update warnings (emitted as prompts for the calling agent)
 	displaySafeUpdateWarnings(compiler, config.JSONOutput)
 
+	// Write the combined SARIF report; a baseline violation is returned after the summary is shown
+	sarifErr := writeSecurityReport(config, findings)
+
 	if config.Verbose {
 		fmt.Fprintln(os.Stderr, console.FormatSuccessMessage(fmt.Sprintf("Successfully compiled %d out of %d workflow files", successCount, len(mdFiles))))
 	}
@@ -387,7 +400,7 @@ func compileAllFilesInDirectory(
 		return workflowDataList, errors.New("compilation failed")
 	}
 
-	return workflowDataList, nil
+	return workflowDataList, sarifErr
 }
 
 // purgeTrackingData holds data needed for purge operations
@@ -558,6 +571,7 @@ func outputResults(
 		}
 		displayStatsTable(statsList)
 		displayScheduleCalendar(statsList)
+		displayAgentCapacityPlan(statsList)
 	}
 
 	// Output JSON if requested
@@ -8,6 +8,7 @@ import (
 	"strconv"
 
 	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/constants"
 	"github.com/github/gh-aw/pkg/logger"
 	"github.com/github/gh-aw/pkg/stringutil"
 	"github.com/github/gh-aw/pkg/styles"
@@ -44,6 +45,8 @@ type WorkflowStats struct {
 	ShellCount  int
 	ShellSize   int
 	Schedules   []string // Cron expressions from on.schedule[*].cron
+
+	AgentPriority string // Priority class in the aw.json agent concurrency budget, empty when not budgeted
 }
 
 // collectWorkflowStats parses a lock file and collects statistics
@@ -97,6 +100,13 @@ func collectWorkflowStats(lockFilePath string) (*WorkflowStats, error) {
 								stats.ShellCount++
 								stats.ShellSize += len(shell)
 							}
+
+							// Check for the agent concurrency budget step
+							if id, ok := step["id"].(string); ok && id == string(constants.AgentBudgetStepID) {
+								if env, ok := step["env"].(map[string]any); ok {
+									stats.AgentPriority, _ = env["GH_AW_AGENT_PRIORITY"].(string)
+								}
+							}
 						}
 					}
 				}
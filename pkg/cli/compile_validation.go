@@ -82,14 +82,14 @@ func CompileWorkflowWithValidation(compiler *workflow.Compiler, filePath string,
 
 	// Run zizmor on the generated lock file if requested
 	if runZizmorPerFile {
-		if err := runZizmorOnFile(lockFile, verbose, strict); err != nil {
+		if err := runZizmorOnFile(lockFile, verbose, strict, nil); err != nil {
 			return fmt.Errorf("zizmor security scan failed: %w", err)
 		}
 	}
 
 	// Run poutine on the generated lock file if requested
 	if runPoutinePerFile {
-		if err := runPoutineOnFile(lockFile, verbose, strict); err != nil {
+		if err := runPoutineOnFile(lockFile, verbose, strict, nil); err != nil {
 			return fmt.Errorf("poutine security scan failed: %w", err)
 		}
 	}
@@ -97,7 +97,7 @@ func CompileWorkflowWithValidation(compiler *workflow.Compiler, filePath string,
 	// Run actionlint on the generated lock file if requested
 	// Note: For batch processing, use RunActionlintOnFiles instead
 	if runActionlintPerFile {
-		if err := runActionlintOnFiles(context.Background(), []string{lockFile}, verbose, strict); err != nil {
+		if err := runActionlintOnFiles(context.Background(), []string{lockFile}, verbose, strict, nil); err != nil {
 			return fmt.Errorf("actionlint linter failed: %w", err)
 		}
 	}
@@ -150,14 +150,14 @@ func CompileWorkflowDataWithValidation(compiler *workflow.Compiler, workflowData
 
 	// Run zizmor on the generated lock file if requested
 	if runZizmorPerFile {
-		if err := runZizmorOnFile(lockFile, verbose, strict); err != nil {
+		if err := runZizmorOnFile(lockFile, verbose, strict, nil); err != nil {
 			return fmt.Errorf("zizmor security scan failed: %w", err)
 		}
 	}
 
 	// Run poutine on the generated lock file if requested
 	if runPoutinePerFile {
-		if err := runPoutineOnFile(lockFile, verbose, strict); err != nil {
+		if err := runPoutineOnFile(lockFile, verbose, strict, nil); err != nil {
 			return fmt.Errorf("poutine security scan failed: %w", err)
 		}
 	}
@@ -165,7 +165,7 @@ func CompileWorkflowDataWithValidation(compiler *workflow.Compiler, workflowData
 	// Run actionlint on the generated lock file if requested
 	// Note: For batch processing, use RunActionlintOnFiles instead
 	if runActionlintPerFile {
-		if err := runActionlintOnFiles(context.Background(), []string{lockFile}, verbose, strict); err != nil {
+		if err := runActionlintOnFiles(context.Background(), []string{lockFile}, verbose, strict, nil); err != nil {
 			return fmt.Errorf("actionlint linter failed: %w", err)
 		}
 	}
@@ -196,6 +196,16 @@ func validateCompileConfig(config CompileConfig) error {
 		return errors.New("--purge flag can only be used when compiling all markdown files (no specific files specified)")
 	}
 
+	// Validate SARIF flag usage
+	if config.SARIFFile == "" && (config.SARIFBaseline != "" || config.UpdateSARIFBaseline) {
+		compileValidationLog.Print("Config validation failed: SARIF baseline flags without --sarif")
+		return errors.New("--sarif-baseline and --update-sarif-baseline require --sarif")
+	}
+	if config.SARIFFile != "" && (config.Watch || config.NoEmit) {
+		compileValidationLog.Print("Config validation failed: sarif with watch or no-emit")
+		return errors.New("--sarif cannot be used with --watch or --no-emit")
+	}
+
 	// Validate workflow directory path
 	if config.WorkflowDir != "" && filepath.IsAbs(config.WorkflowDir) {
 		compileValidationLog.Printf("Config validation failed: absolute path in workflowDir: %s", config.WorkflowDir)
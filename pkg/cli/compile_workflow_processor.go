@@ -56,6 +56,7 @@ func compileWorkflowFile(
 	actionlint bool,
 	strict bool,
 	validate bool,
+	findings *SecurityFindingCollector,
 ) compileWorkflowFileResult {
 	compileWorkflowProcessorLog.Printf("Processing workflow file: %s", resolvedFile)
 
@@ -69,6 +70,12 @@ func compileWorkflowFile(
 		success: false,
 	}
 
+	// Record the compiler's warnings and errors for this workflow in the SARIF report
+	diagnosticsStart := len(compiler.GetDiagnostics())
+	defer func() {
+		findings.AddCompilerDiagnostics(resolvedFile, compiler.GetDiagnostics()[diagnosticsStart:], result.validationResult.Errors)
+	}()
+
 	// Generate lock file name
 	lockFile := stringutil.MarkdownToLockFile(resolvedFile)
 	result.lockFile = lockFile
@@ -0,0 +1,848 @@
+package cli
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/constants"
+	"github.com/github/gh-aw/pkg/fileutil"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/parser"
+	"github.com/github/gh-aw/pkg/stringutil"
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/goccy/go-yaml"
+)
+
+var convertLog = logger.New("cli:convert")
+
+// agentActionSpec describes how a third-party agent action receives its prompt and settings.
+type agentActionSpec struct {
+	engine           string   // Equivalent gh-aw engine
+	promptInputs     []string // Inputs holding the prompt text
+	promptFileInputs []string // Inputs holding a path to a prompt file
+	modelInputs      []string // Inputs holding the model name
+	secretInputs     []string // Inputs holding the engine API key
+	toolsInputs      []string // Inputs holding an allowed-tools list
+	argsInputs       []string // Inputs holding extra CLI arguments (--model, --allowedTools)
+}
+
+// knownAgentActions maps third-party agent actions (without ref) to their invocation details.
+var knownAgentActions = map[string]agentActionSpec{
+	"anthropics/claude-code-action": {
+		engine:       "claude",
+		promptInputs: []string{"prompt", "direct_prompt", "override_prompt"},
+		modelInputs:  []string{"model"},
+		secretInputs: []string{"anthropic_api_key", "claude_code_oauth_token"},
+		toolsInputs:  []string{"allowed_tools"},
+		argsInputs:   []string{"claude_args"},
+	},
+	"anthropics/claude-code-base-action": {
+		engine:           "claude",
+		promptInputs:     []string{"prompt"},
+		promptFileInputs: []string{"prompt_file"},
+		modelInputs:      []string{"model"},
+		secretInputs:     []string{"anthropic_api_key", "claude_code_oauth_token"},
+		toolsInputs:      []string{"allowed_tools"},
+		argsInputs:       []string{"claude_args"},
+	},
+	"openai/codex-action": {
+		engine:           "codex",
+		promptInputs:     []string{"prompt"},
+		promptFileInputs: []string{"prompt-file"},
+		modelInputs:      []string{"model"},
+		secretInputs:     []string{"openai-api-key"},
+	},
+	"google-github-actions/run-gemini-cli": {
+		engine:       "gemini",
+		promptInputs: []string{"prompt"},
+		secretInputs: []string{"gemini_api_key"},
+	},
+	"actions/ai-inference": {
+		engine:           "copilot",
+		promptInputs:     []string{"prompt"},
+		promptFileInputs: []string{"prompt-file"},
+		modelInputs:      []string{"model"},
+	},
+}
+
+// llmAPIHosts maps LLM API hosts called directly from run steps to the equivalent engine.
+var llmAPIHosts = map[string]string{
+	"api.anthropic.com":                 "claude",
+	"api.openai.com":                    "codex",
+	"generativelanguage.googleapis.com": "gemini",
+	"models.github.ai":                  "copilot",
+	"models.inference.ai.azure.com":     "copilot",
+}
+
+// engineSecretNames is the repository secret each engine reads its API key from.
+var engineSecretNames = map[string]string{
+	"claude":  "ANTHROPIC_API_KEY",
+	"codex":   "OPENAI_API_KEY",
+	"copilot": "COPILOT_GITHUB_TOKEN",
+	"gemini":  "GEMINI_API_KEY",
+}
+
+// engineKeyEnvVars are environment variables that carry an LLM API key.
+var engineKeyEnvVars = []string{"ANTHROPIC_API_KEY", "CLAUDE_CODE_OAUTH_TOKEN", "OPENAI_API_KEY", "CODEX_API_KEY", "GEMINI_API_KEY", "GOOGLE_API_KEY", "COPILOT_GITHUB_TOKEN"}
+
+// writeOperationPattern maps a write operation found in a step to a safe output.
+type writeOperationPattern struct {
+	pattern    *regexp.Regexp
+	safeOutput string
+}
+
+// runWriteOperations are gh CLI write operations recognized in run scripts.
+var runWriteOperations = []writeOperationPattern{
+	{regexp.MustCompile(`\bgh\s+(issue|pr)\s+comment\b`), "add-comment"},
+	{regexp.MustCompile(`\bgh\s+issue\s+create\b`), "create-issue"},
+	{regexp.MustCompile(`\bgh\s+pr\s+create\b`), "create-pull-request"},
+	{regexp.MustCompile(`\bgh\s+(issue|pr)\s+edit\b[^\n]*--add-label`), "add-labels"},
+	{regexp.MustCompile(`\bgh\s+(issue|pr)\s+edit\b[^\n]*--remove-label`), "remove-labels"},
+	{regexp.MustCompile(`\bgh\s+issue\s+close\b`), "close-issue"},
+	{regexp.MustCompile(`\bgh\s+pr\s+close\b`), "close-pull-request"},
+	{regexp.MustCompile(`\bgh\s+pr\s+review\b`), "submit-pull-request-review"},
+}
+
+// scriptWriteOperations are Octokit write calls recognized in actions/github-script steps.
+var scriptWriteOperations = []writeOperationPattern{
+	{regexp.MustCompile(`\.issues\.createComment\(`), "add-comment"},
+	{regexp.MustCompile(`\.issues\.create\(`), "create-issue"},
+	{regexp.MustCompile(`\.issues\.update\(`), "update-issue"},
+	{regexp.MustCompile(`\.issues\.addLabels\(`), "add-labels"},
+	{regexp.MustCompile(`\.issues\.removeLabel\(`), "remove-labels"},
+	{regexp.MustCompile(`\.pulls\.create\(`), "create-pull-request"},
+	{regexp.MustCompile(`\.pulls\.createReview\(`), "submit-pull-request-review"},
+	{regexp.MustCompile(`\.pulls\.createReviewComment\(`), "create-pull-request-review-comment"},
+	{regexp.MustCompile(`\.pulls\.update\(`), "update-pull-request"},
+}
+
+// actionWriteOperations maps post-processing actions that only write to GitHub to safe outputs.
+var actionWriteOperations = map[string]string{
+	"actions-ecosystem/action-add-labels":     "add-labels",
+	"actions-ecosystem/action-remove-labels":  "remove-labels",
+	"marocchino/sticky-pull-request-comment":  "add-comment",
+	"peter-evans/create-issue-from-file":      "create-issue",
+	"peter-evans/create-or-update-comment":    "add-comment",
+	"peter-evans/create-pull-request":         "create-pull-request",
+	"thollander/actions-comment-pull-request": "add-comment",
+}
+
+var (
+	// unrecognizedWritePattern matches write operations that have no safe output equivalent.
+	unrecognizedWritePattern = regexp.MustCompile(`\bgit\s+push\b|\bgh\s+(api|release|pr\s+merge|workflow\s+run)\b|-X\s*(POST|PUT|PATCH|DELETE)\b[^\n]*api\.github\.com`)
+	// unrecognizedScriptWritePattern matches Octokit calls that are not reads.
+	unrecognizedScriptWritePattern = regexp.MustCompile(`\.(create|update|delete|add|remove|merge|set|lock|unlock)[A-Za-z]*\(`)
+	secretReferencePattern         = regexp.MustCompile(`\$\{\{\s*secrets\.([A-Za-z0-9_]+)\s*\}\}`)
+	githubTokenPattern             = regexp.MustCompile(`\$\{\{\s*(secrets\.GITHUB_TOKEN|github\.token)\s*\}\}`)
+	claudeArgsModelPattern         = regexp.MustCompile(`--model[=\s]+["']?([^"'\s]+)`)
+	claudeArgsToolsPattern         = regexp.MustCompile(`--allowed-?[tT]ools[=\s]+(?:"([^"]*)"|'([^']*)'|(\S+))`)
+)
+
+// agentWorkflowConversion is the result of converting a GitHub Actions workflow that invokes
+// an agent into an agentic workflow.
+type agentWorkflowConversion struct {
+	Markdown     string            // Agentic workflow markdown
+	Engine       string            // Chosen gh-aw engine
+	AgentJob     string            // Job that invoked the agent
+	SafeOutputs  []string          // Safe outputs replacing write operations
+	Permissions  map[string]string // Agent job permissions in the source workflow
+	Untranslated []string          // Parts of the workflow that could not be translated
+	Notes        []string          // Follow-up actions for the user (secrets to create, ...)
+}
+
+// addNote records a follow-up action once.
+func (c *agentWorkflowConversion) addNote(note string) {
+	if !slices.Contains(c.Notes, note) {
+		c.Notes = append(c.Notes, note)
+	}
+}
+
+// agentInvocation is the step of the source workflow that runs the agent.
+type agentInvocation struct {
+	index  int
+	engine string
+	spec   *agentActionSpec // nil for direct LLM API calls from run steps
+	step   map[string]any
+	// hosts lists the LLM API hosts called from a run step in script order; the
+	// engine is chosen from the first one
+	hosts []string
+}
+
+// convertAgentWorkflow converts a GitHub Actions workflow that invokes a third-party agent
+// action (or calls an LLM API directly) into agentic workflow markdown.
+func convertAgentWorkflow(filePath string, content []byte) (*agentWorkflowConversion, error) {
+	isWorkflow, err := parser.IsGitHubActionsWorkflowFile(filePath, content)
+	if err != nil {
+		return nil, err
+	}
+	if !isWorkflow {
+		return nil, fmt.Errorf("%s is not a GitHub Actions workflow file", filePath)
+	}
+
+	var doc map[string]any
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", filePath, err)
+	}
+	jobs, _ := doc["jobs"].(map[string]any)
+
+	jobName, job, invocation := findAgentInvocation(jobs)
+	if invocation == nil {
+		return nil, fmt.Errorf("no agent invocation found in %s: expected a known agent action (%s) or a run step calling an LLM API", filePath, strings.Join(slices.Sorted(maps.Keys(knownAgentActions)), ", "))
+	}
+	convertLog.Printf("Found %s agent invocation in job %s (step %d)", invocation.engine, jobName, invocation.index)
+
+	conversion := &agentWorkflowConversion{
+		Engine:      invocation.engine,
+		AgentJob:    jobName,
+		Permissions: effectivePermissions(doc["permissions"], job["permissions"]),
+	}
+	frontmatter := map[string]any{}
+	if name, ok := doc["name"].(string); ok && name != "" {
+		frontmatter["name"] = name
+	}
+	if on, ok := doc["on"]; ok {
+		frontmatter["on"] = on
+	}
+	if permissions := readOnlyPermissions(conversion.Permissions); len(permissions) > 0 {
+		frontmatter["permissions"] = permissions
+	}
+	for _, key := range []string{"if", "runs-on", "timeout-minutes", "concurrency", "services", "environment"} {
+		if value, ok := job[key]; ok {
+			frontmatter[key] = value
+		}
+	}
+	if value, ok := doc["concurrency"]; ok && frontmatter["concurrency"] == nil {
+		frontmatter["concurrency"] = value
+	}
+	if runsOn, ok := frontmatter["runs-on"].(string); ok && runsOn == "ubuntu-latest" {
+		delete(frontmatter, "runs-on")
+	}
+
+	for name := range jobs {
+		if name != jobName {
+			conversion.Untranslated = append(conversion.Untranslated, fmt.Sprintf("job '%s' was not converted; add it under jobs: if the agentic workflow still needs it", name))
+		}
+	}
+
+	env := convertEnv(conversion, doc["env"], job["env"], invocation.step["env"])
+	if len(env) > 0 {
+		frontmatter["env"] = env
+	}
+
+	engine := map[string]any{"id": invocation.engine}
+	prompt := convertAgentStep(conversion, invocation, engine, frontmatter)
+	frontmatter["engine"] = engine
+
+	steps, _ := job["steps"].([]any)
+	if preSteps := convertPreSteps(conversion, steps[:invocation.index]); len(preSteps) > 0 {
+		frontmatter["steps"] = preSteps
+	}
+	safeOutputs, postSteps := convertPostSteps(conversion, steps, invocation.index+1, invocation.step["id"])
+	safeOutputs = inferSafeOutputs(conversion, safeOutputs)
+	if len(safeOutputs) > 0 {
+		outputs := map[string]any{}
+		for _, name := range safeOutputs {
+			outputs[name] = nil
+		}
+		frontmatter["safe-outputs"] = outputs
+		conversion.SafeOutputs = safeOutputs
+	}
+	if len(postSteps) > 0 {
+		frontmatter["post-steps"] = postSteps
+	}
+
+	yamlBytes, err := workflow.MarshalWithFieldOrder(frontmatter, []string{"name", "on", "permissions", "if", "runs-on", "timeout-minutes", "concurrency", "environment", "services", "env", "engine", "tools", "safe-outputs", "steps", "post-steps"})
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate frontmatter: %w", err)
+	}
+
+	title := strings.TrimSuffix(filepath.Base(filePath), filepath.Ext(filePath))
+	if name, ok := frontmatter["name"].(string); ok {
+		title = name
+	}
+	var markdown strings.Builder
+	markdown.WriteString("---\n")
+	markdown.WriteString(workflow.CleanYAMLNullValues(strings.TrimSpace(string(yamlBytes))))
+	markdown.WriteString("\n---\n\n# " + title + "\n\n")
+	markdown.WriteString(strings.TrimSpace(prompt) + "\n")
+	conversion.Markdown = markdown.String()
+
+	sort.Strings(conversion.Untranslated)
+	return conversion, nil
+}
+
+// findAgentInvocation returns the first job (in name order) with a step that invokes an agent.
+func findAgentInvocation(jobs map[string]any) (string, map[string]any, *agentInvocation) {
+	for _, name := range slices.Sorted(maps.Keys(jobs)) {
+		job, ok := jobs[name].(map[string]any)
+		if !ok {
+			continue
+		}
+		steps, _ := job["steps"].([]any)
+		for i, item := range steps {
+			step, ok := item.(map[string]any)
+			if !ok {
+				continue
+			}
+			if uses, ok := step["uses"].(string); ok {
+				if spec, known := knownAgentActions[actionRepo(uses)]; known {
+					return name, job, &agentInvocation{index: i, engine: spec.engine, spec: &spec, step: step}
+				}
+			}
+			if run, ok := step["run"].(string); ok {
+				if hosts := llmAPIHostsInScript(run); len(hosts) > 0 {
+					return name, job, &agentInvocation{index: i, engine: llmAPIHosts[hosts[0]], step: step, hosts: hosts}
+				}
+			}
+		}
+	}
+	return "", nil, nil
+}
+
+// llmAPIHostsInScript returns the LLM API hosts called from a run script, in the
+// order they first appear.
+func llmAPIHostsInScript(run string) []string {
+	var hosts []string
+	for host := range llmAPIHosts {
+		if strings.Contains(run, host) {
+			hosts = append(hosts, host)
+		}
+	}
+	slices.SortFunc(hosts, func(a, b string) int {
+		return cmp.Compare(strings.Index(run, a), strings.Index(run, b))
+	})
+	return hosts
+}
+
+// convertAgentStep translates the agent step's inputs into engine settings and tools and
+// returns the prompt for the markdown body.
+func convertAgentStep(conversion *agentWorkflowConversion, invocation *agentInvocation, engine map[string]any, frontmatter map[string]any) string {
+	secretName := engineSecretNames[invocation.engine]
+	if invocation.spec == nil {
+		conversion.Untranslated = append(conversion.Untranslated, "the prompt sent to the LLM API from a run step could not be extracted; replace the placeholder in the markdown body with the instructions for the agent")
+		conversion.addNote(fmt.Sprintf("Store the LLM API key in the repository secret %s", secretName))
+		for _, host := range invocation.hosts[1:] {
+			conversion.Untranslated = append(conversion.Untranslated, fmt.Sprintf("the run step also calls %s; only the first LLM API called, %s, was converted to the %s engine", host, invocation.hosts[0], invocation.engine))
+		}
+		run, _ := invocation.step["run"].(string)
+		return "<!-- TODO: describe the task for the agent. The original workflow called the LLM API directly: -->\n\n```bash\n" + strings.TrimSpace(run) + "\n```"
+	}
+
+	with, _ := invocation.step["with"].(map[string]any)
+	spec := invocation.spec
+
+	// API key inputs map to the engine's secret
+	for _, input := range spec.secretInputs {
+		value, ok := with[input].(string)
+		if !ok {
+			continue
+		}
+		if match := secretReferencePattern.FindStringSubmatch(value); match != nil && match[1] != secretName {
+			conversion.addNote(fmt.Sprintf("Store the value of secret %s in the repository secret %s", match[1], secretName))
+		}
+		if input == "claude_code_oauth_token" {
+			conversion.Untranslated = append(conversion.Untranslated, "claude_code_oauth_token: the claude engine authenticates with ANTHROPIC_API_KEY")
+		}
+	}
+	if invocation.engine == "copilot" {
+		conversion.addNote("Store a token with Copilot access in the repository secret COPILOT_GITHUB_TOKEN")
+	}
+
+	var args string
+	for _, input := range spec.argsInputs {
+		if value, ok := with[input].(string); ok {
+			args += " " + value
+		}
+	}
+	for _, input := range spec.modelInputs {
+		if model, ok := with[input].(string); ok && model != "" {
+			engine["model"] = model
+		}
+	}
+	if match := claudeArgsModelPattern.FindStringSubmatch(args); match != nil {
+		engine["model"] = match[1]
+	}
+
+	var allowedTools []string
+	for _, input := range spec.toolsInputs {
+		if value, ok := with[input].(string); ok {
+			allowedTools = append(allowedTools, splitToolList(value)...)
+		}
+	}
+	for _, match := range claudeArgsToolsPattern.FindAllStringSubmatch(args, -1) {
+		allowedTools = append(allowedTools, splitToolList(match[1]+match[2]+match[3])...)
+	}
+	if tools := convertAllowedTools(conversion, allowedTools); len(tools) > 0 {
+		frontmatter["tools"] = tools
+		if _, ok := tools["github"]; ok {
+			// The default GitHub toolsets read issues and pull requests
+			permissions, _ := frontmatter["permissions"].(map[string]any)
+			if permissions == nil {
+				permissions = map[string]any{}
+				frontmatter["permissions"] = permissions
+			}
+			for _, scope := range []string{"contents", "issues", "pull-requests"} {
+				if _, ok := permissions[scope]; !ok {
+					permissions[scope] = "read"
+				}
+			}
+		}
+	}
+
+	handled := slices.Concat(spec.promptInputs, spec.promptFileInputs, spec.modelInputs, spec.secretInputs, spec.toolsInputs, spec.argsInputs, []string{"github_token", "github-token", "token"})
+	for _, input := range slices.Sorted(maps.Keys(with)) {
+		if !slices.Contains(handled, input) {
+			conversion.Untranslated = append(conversion.Untranslated, fmt.Sprintf("input '%s' of %s", input, actionRepo(invocation.step["uses"].(string))))
+		}
+	}
+
+	for _, input := range spec.promptInputs {
+		if prompt, ok := with[input].(string); ok && strings.TrimSpace(prompt) != "" {
+			return prompt
+		}
+	}
+	for _, input := range spec.promptFileInputs {
+		if path, ok := with[input].(string); ok && path != "" {
+			return fmt.Sprintf("{{#runtime-import %s}}", path)
+		}
+	}
+	conversion.Untranslated = append(conversion.Untranslated, "the agent step has no prompt input (it may respond to @-mentions in comments); write the instructions for the agent in the markdown body")
+	return "<!-- TODO: describe the task for the agent. -->"
+}
+
+// splitToolList splits a comma- or newline-separated tool list.
+func splitToolList(value string) []string {
+	var tools []string
+	for tool := range strings.FieldsFuncSeq(value, func(r rune) bool { return r == ',' || r == '\n' }) {
+		if tool = strings.TrimSpace(tool); tool != "" {
+			tools = append(tools, tool)
+		}
+	}
+	return tools
+}
+
+// convertAllowedTools maps Claude Code tool names to gh-aw tools.
+func convertAllowedTools(conversion *agentWorkflowConversion, allowedTools []string) map[string]any {
+	tools := map[string]any{}
+	var bash []any
+	for _, tool := range allowedTools {
+		switch {
+		case strings.HasPrefix(tool, "Bash(") && strings.HasSuffix(tool, ")"):
+			bash = append(bash, strings.TrimSuffix(strings.TrimPrefix(tool, "Bash("), ")"))
+		case tool == "Bash":
+			bash = append(bash, "*")
+		case tool == "Edit" || tool == "MultiEdit" || tool == "Write" || tool == "NotebookEdit":
+			tools["edit"] = nil
+		case tool == "WebFetch":
+			tools["web-fetch"] = nil
+		case tool == "WebSearch":
+			tools["web-search"] = nil
+		case strings.HasPrefix(tool, "mcp__github__"):
+			tools["github"] = nil
+		case tool == "Read" || tool == "Glob" || tool == "Grep" || tool == "LS" || tool == "TodoWrite" || tool == "Task":
+			// Built-in read-only tools are always available
+		default:
+			conversion.Untranslated = append(conversion.Untranslated, fmt.Sprintf("allowed tool '%s'", tool))
+		}
+	}
+	if len(bash) > 0 {
+		tools["bash"] = bash
+	}
+	return tools
+}
+
+// convertEnv keeps non-secret environment variables and reports secrets, which are not
+// passed to the agent automatically.
+func convertEnv(conversion *agentWorkflowConversion, envs ...any) map[string]any {
+	result := map[string]any{}
+	for _, envValue := range envs {
+		env, _ := envValue.(map[string]any)
+		for _, name := range slices.Sorted(maps.Keys(env)) {
+			value := fmt.Sprint(env[name])
+			switch {
+			case githubTokenPattern.MatchString(value):
+				// The compiler provides GitHub tokens to the jobs that need them
+			case slices.Contains(engineKeyEnvVars, name):
+				if match := secretReferencePattern.FindStringSubmatch(value); match != nil && match[1] != engineSecretNames[conversion.Engine] {
+					conversion.addNote(fmt.Sprintf("Store the value of secret %s in the repository secret %s", match[1], engineSecretNames[conversion.Engine]))
+				}
+			case secretReferencePattern.MatchString(value):
+				conversion.Untranslated = append(conversion.Untranslated, fmt.Sprintf("environment variable %s uses a secret; add it under engine.env only if the agent must see it", name))
+			default:
+				result[name] = env[name]
+			}
+		}
+	}
+	return result
+}
+
+// convertPreSteps keeps the steps that run before the agent, except the checkout that
+// the compiler adds itself.
+func convertPreSteps(conversion *agentWorkflowConversion, steps []any) []any {
+	var result []any
+	for _, item := range steps {
+		step, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+		if uses, ok := step["uses"].(string); ok && actionRepo(uses) == "actions/checkout" {
+			with, _ := step["with"].(map[string]any)
+			for key := range with {
+				if key != "fetch-depth" && key != "persist-credentials" {
+					conversion.Untranslated = append(conversion.Untranslated, fmt.Sprintf("checkout input '%s'; configure it with the checkout: field", key))
+				}
+			}
+			continue
+		}
+		result = append(result, workflow.OrderMapFields(step, []string{"name", "id", "if", "uses", "run", "with", "env"}))
+	}
+	return result
+}
+
+// convertPostSteps translates write operations in the steps after the agent into safe
+// outputs. Steps without write operations are kept as post-steps; steps whose writes
+// cannot be translated are reported.
+func convertPostSteps(conversion *agentWorkflowConversion, steps []any, start int, agentStepID any) ([]string, []any) {
+	var safeOutputs []string
+	var postSteps []any
+	agentOutputs := ""
+	if id, ok := agentStepID.(string); ok && id != "" {
+		agentOutputs = "steps." + id + ".outputs"
+	}
+
+	for i := start; i < len(steps); i++ {
+		step, ok := steps[i].(map[string]any)
+		if !ok {
+			continue
+		}
+		label := stepLabel(step, i)
+		outputs, unrecognized := stepWriteOperations(step)
+		switch {
+		case unrecognized != "":
+			conversion.Untranslated = append(conversion.Untranslated, fmt.Sprintf("post-processing step '%s' performs a write with no safe output equivalent (%s)", label, unrecognized))
+		case len(outputs) > 0:
+			for _, output := range outputs {
+				if !slices.Contains(safeOutputs, output) {
+					safeOutputs = append(safeOutputs, output)
+				}
+			}
+			convertLog.Printf("Post-processing step %q translated to safe outputs %v", label, outputs)
+		case agentOutputs != "" && strings.Contains(fmt.Sprint(step), agentOutputs):
+			conversion.Untranslated = append(conversion.Untranslated, fmt.Sprintf("post-processing step '%s' reads the agent action's outputs", label))
+		default:
+			postSteps = append(postSteps, workflow.OrderMapFields(step, []string{"name", "id", "if", "uses", "run", "with", "env"}))
+		}
+	}
+	sort.Strings(safeOutputs)
+	return safeOutputs, postSteps
+}
+
+// stepWriteOperations returns the safe outputs for the writes a step performs, or a
+// description of the first write that has no safe output equivalent.
+func stepWriteOperations(step map[string]any) ([]string, string) {
+	var outputs []string
+	if uses, ok := step["uses"].(string); ok {
+		repo := actionRepo(uses)
+		if output, ok := actionWriteOperations[repo]; ok {
+			return []string{output}, ""
+		}
+		if repo != "actions/github-script" {
+			return nil, ""
+		}
+		with, _ := step["with"].(map[string]any)
+		script, _ := with["script"].(string)
+		remaining := script
+		for _, operation := range scriptWriteOperations {
+			if operation.pattern.MatchString(remaining) {
+				outputs = append(outputs, operation.safeOutput)
+				remaining = operation.pattern.ReplaceAllString(remaining, "(")
+			}
+		}
+		if match := unrecognizedScriptWritePattern.FindString(remaining); match != "" {
+			return nil, strings.Trim(match, ".(")
+		}
+		return outputs, ""
+	}
+
+	run, _ := step["run"].(string)
+	if match := unrecognizedWritePattern.FindString(run); match != "" {
+		return nil, strings.TrimSpace(match)
+	}
+	for _, operation := range runWriteOperations {
+		if operation.pattern.MatchString(run) {
+			outputs = append(outputs, operation.safeOutput)
+		}
+	}
+	return outputs, ""
+}
+
+// inferSafeOutputs adds safe outputs for write permissions the agent action used directly.
+// Agent actions such as claude-code-action post comments and push branches themselves, so
+// their write permissions are not visible as post-processing steps.
+func inferSafeOutputs(conversion *agentWorkflowConversion, safeOutputs []string) []string {
+	inferred := map[string]string{
+		"issues":        "add-comment",
+		"pull-requests": "add-comment",
+		"contents":      "create-pull-request",
+	}
+	for _, scope := range slices.Sorted(maps.Keys(inferred)) {
+		output := inferred[scope]
+		if conversion.Permissions[scope] != "write" || slices.Contains(safeOutputs, output) {
+			continue
+		}
+		safeOutputs = append(safeOutputs, output)
+		conversion.addNote(fmt.Sprintf("Added the %s safe output because the agent job had %s: write; review whether the agent needs it", output, scope))
+	}
+	sort.Strings(safeOutputs)
+	return safeOutputs
+}
+
+// effectivePermissions returns the permissions of a job, falling back to the workflow's.
+// The shorthands read-all and write-all are expanded to the common scopes.
+func effectivePermissions(workflowPermissions, jobPermissions any) map[string]string {
+	permissions := jobPermissions
+	if permissions == nil {
+		permissions = workflowPermissions
+	}
+	result := map[string]string{}
+	switch value := permissions.(type) {
+	case string:
+		level := strings.TrimSuffix(value, "-all")
+		for _, scope := range []string{"actions", "checks", "contents", "discussions", "issues", "pull-requests", "statuses"} {
+			result[scope] = level
+		}
+	case map[string]any:
+		for scope, level := range value {
+			result[scope] = fmt.Sprint(level)
+		}
+	}
+	return result
+}
+
+// readOnlyPermissions downgrades every scope to read access. Write access is granted to
+// the safe output jobs by the compiler instead. id-token is dropped: the agent job never
+// needs to mint OIDC tokens.
+func readOnlyPermissions(permissions map[string]string) map[string]any {
+	result := map[string]any{}
+	for scope, level := range permissions {
+		if scope == "id-token" || level == "none" {
+			continue
+		}
+		result[scope] = "read"
+	}
+	return result
+}
+
+// actionRepo strips the ref from a uses: reference.
+func actionRepo(uses string) string {
+	repo, _, _ := strings.Cut(uses, "@")
+	return repo
+}
+
+// stepLabel returns a step's name, or its position when it has none.
+func stepLabel(step map[string]any, index int) string {
+	if name, ok := step["name"].(string); ok && name != "" {
+		return name
+	}
+	if uses, ok := step["uses"].(string); ok {
+		return uses
+	}
+	return fmt.Sprintf("step %d", index+1)
+}
+
+// PermissionChange is the access level of one permission scope before and after conversion.
+type PermissionChange struct {
+	Scope       string `json:"scope" console:"header:Scope"`
+	Before      string `json:"before" console:"header:Before"`
+	Agent       string `json:"agent" console:"header:Agent job"`
+	SafeOutputs string `json:"safe_outputs" console:"header:Safe output jobs"`
+}
+
+// computePermissionDelta compares the source workflow's agent job permissions with the
+// compiled lock file: the agent job and the jobs that apply safe outputs.
+func computePermissionDelta(before map[string]string, lockContent []byte) ([]PermissionChange, error) {
+	var lock struct {
+		Jobs map[string]struct {
+			Permissions any `yaml:"permissions"`
+		} `yaml:"jobs"`
+	}
+	if err := yaml.Unmarshal(lockContent, &lock); err != nil {
+		return nil, fmt.Errorf("failed to parse lock file: %w", err)
+	}
+	if _, ok := lock.Jobs["agent"]; !ok {
+		return nil, errors.New("lock file has no agent job")
+	}
+
+	agent := effectivePermissions(nil, lock.Jobs["agent"].Permissions)
+	others := map[string]string{}
+	for name, job := range lock.Jobs {
+		if name == "agent" {
+			continue
+		}
+		for scope, level := range effectivePermissions(nil, job.Permissions) {
+			if permissionRank(level) > permissionRank(others[scope]) {
+				others[scope] = level
+			}
+		}
+	}
+
+	scopes := map[string]bool{}
+	for _, permissions := range []map[string]string{before, agent, others} {
+		for scope := range permissions {
+			scopes[scope] = true
+		}
+	}
+	var changes []PermissionChange
+	for _, scope := range slices.Sorted(maps.Keys(scopes)) {
+		changes = append(changes, PermissionChange{
+			Scope:       scope,
+			Before:      permissionLevel(before, scope),
+			Agent:       permissionLevel(agent, scope),
+			SafeOutputs: permissionLevel(others, scope),
+		})
+	}
+	return changes, nil
+}
+
+func permissionLevel(permissions map[string]string, scope string) string {
+	if level, ok := permissions[scope]; ok && level != "" {
+		return level
+	}
+	return "none"
+}
+
+func permissionRank(level string) int {
+	switch level {
+	case "write":
+		return 2
+	case "read":
+		return 1
+	default:
+		return 0
+	}
+}
+
+// ConvertOptions holds the options for the convert command.
+type ConvertOptions struct {
+	InputFile  string
+	OutputFile string // Defaults to the input file with a .md extension
+	Force      bool
+	NoCompile  bool
+	JSON       bool
+	Verbose    bool
+}
+
+// ConvertResult is the JSON output of the convert command.
+type ConvertResult struct {
+	Source           string             `json:"source"`
+	Output           string             `json:"output"`
+	Engine           string             `json:"engine"`
+	AgentJob         string             `json:"agent_job"`
+	SafeOutputs      []string           `json:"safe_outputs,omitempty"`
+	Untranslated     []string           `json:"untranslated,omitempty"`
+	Notes            []string           `json:"notes,omitempty"`
+	Compiled         bool               `json:"compiled"`
+	PermissionsDelta []PermissionChange `json:"permissions_delta,omitempty"`
+}
+
+// RunConvert converts a GitHub Actions workflow that invokes an agent into an agentic
+// workflow, compiles it and reports the permission delta.
+func RunConvert(opts ConvertOptions) error {
+	convertLog.Printf("Converting %s: output=%s, force=%v, no_compile=%v", opts.InputFile, opts.OutputFile, opts.Force, opts.NoCompile)
+
+	content, err := os.ReadFile(opts.InputFile)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", opts.InputFile, err)
+	}
+	conversion, err := convertAgentWorkflow(opts.InputFile, content)
+	if err != nil {
+		return err
+	}
+
+	outputFile := opts.OutputFile
+	if outputFile == "" {
+		outputFile = strings.TrimSuffix(opts.InputFile, filepath.Ext(opts.InputFile)) + ".md"
+	}
+	if !opts.Force && fileutil.FileExists(outputFile) {
+		return fmt.Errorf("%s already exists; use --force to overwrite it", outputFile)
+	}
+	if err := os.WriteFile(outputFile, []byte(conversion.Markdown), constants.FilePermPublic); err != nil {
+		return fmt.Errorf("failed to write %s: %w", outputFile, err)
+	}
+
+	result := ConvertResult{
+		Source:       opts.InputFile,
+		Output:       outputFile,
+		Engine:       conversion.Engine,
+		AgentJob:     conversion.AgentJob,
+		SafeOutputs:  conversion.SafeOutputs,
+		Untranslated: conversion.Untranslated,
+		Notes:        conversion.Notes,
+	}
+	if !opts.JSON {
+		fmt.Fprintln(os.Stderr, console.FormatSuccessMessage(fmt.Sprintf("Converted %s (job '%s') to %s using the %s engine", opts.InputFile, conversion.AgentJob, outputFile, conversion.Engine)))
+	}
+
+	var compileErr error
+	if !opts.NoCompile {
+		compileErr = compileWorkflow(outputFile, opts.Verbose, opts.JSON, "")
+		if compileErr == nil {
+			result.Compiled = true
+			lockContent, err := os.ReadFile(stringutil.MarkdownToLockFile(outputFile))
+			if err != nil {
+				return fmt.Errorf("failed to read lock file: %w", err)
+			}
+			if result.PermissionsDelta, err = computePermissionDelta(conversion.Permissions, lockContent); err != nil {
+				return err
+			}
+		}
+	}
+
+	if opts.JSON {
+		jsonBytes, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(jsonBytes))
+		return compileErr
+	}
+
+	if len(result.SafeOutputs) > 0 {
+		fmt.Fprintln(os.Stderr, console.FormatInfoMessage("Safe outputs: "+strings.Join(result.SafeOutputs, ", ")))
+	}
+	if len(result.PermissionsDelta) > 0 {
+		fmt.Fprintln(os.Stderr)
+		fmt.Fprintln(os.Stderr, console.FormatInfoMessage("Permissions before and after conversion:"))
+		fmt.Fprint(os.Stderr, console.RenderStruct(result.PermissionsDelta))
+	}
+	if len(result.Untranslated) > 0 {
+		fmt.Fprintln(os.Stderr)
+		fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("%d item(s) could not be translated and need manual review:", len(result.Untranslated))))
+		for _, item := range result.Untranslated {
+			fmt.Fprintln(os.Stderr, "  • "+item)
+		}
+	}
+	if len(result.Notes) > 0 {
+		fmt.Fprintln(os.Stderr)
+		fmt.Fprintln(os.Stderr, console.FormatInfoMessage("Next steps:"))
+		for _, note := range result.Notes {
+			fmt.Fprintln(os.Stderr, "  • "+note)
+		}
+	}
+	if compileErr != nil {
+		return fmt.Errorf("converted workflow written to %s but failed to compile: %w", outputFile, compileErr)
+	}
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("Remove or disable %s once the converted workflow is verified, so the agent does not run twice.", opts.InputFile)))
+	return nil
+}
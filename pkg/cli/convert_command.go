@@ -0,0 +1,62 @@
+package cli
+
+import (
+	"github.com/github/gh-aw/pkg/constants"
+	"github.com/spf13/cobra"
+)
+
+// NewConvertCommand creates the convert command.
+func NewConvertCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "convert <workflow.yml>",
+		Short: "Convert a GitHub Actions workflow that runs an agent into an agentic workflow",
+		Long: `Convert a GitHub Actions workflow that invokes a third-party agent action, or calls
+an LLM API directly, into an agentic workflow markdown file.
+
+The converter maps the workflow's triggers, permissions, environment, prompt inputs and
+post-processing steps:
+- The agent action selects the equivalent engine (claude, codex, gemini or copilot)
+- The prompt input becomes the markdown body; a prompt file becomes a runtime import
+- Write permissions are downgraded to read for the agent job
+- Write operations in post-processing steps (gh CLI, github-script, comment and
+  pull request actions) become safe outputs
+- API key secrets are mapped to the engine's secret
+
+Anything that cannot be translated is listed for manual review. The result is compiled
+and the permissions of the original job are compared with the compiled jobs.
+
+Supported agent actions:
+  anthropics/claude-code-action, anthropics/claude-code-base-action,
+  openai/codex-action, google-github-actions/run-gemini-cli, actions/ai-inference
+
+Examples:
+  ` + string(constants.CLIExtensionPrefix) + ` convert .github/workflows/claude.yml              # Writes .github/workflows/claude.md
+  ` + string(constants.CLIExtensionPrefix) + ` convert triage.yml -o .github/workflows/triage.md # Choose the output file
+  ` + string(constants.CLIExtensionPrefix) + ` convert review.yml --no-compile                  # Skip compilation
+  ` + string(constants.CLIExtensionPrefix) + ` convert review.yml --json                        # Output the conversion report as JSON`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			output, _ := cmd.Flags().GetString("output")
+			force, _ := cmd.Flags().GetBool("force")
+			noCompile, _ := cmd.Flags().GetBool("no-compile")
+			jsonOutput, _ := cmd.Flags().GetBool("json")
+			verbose, _ := cmd.Flags().GetBool("verbose")
+
+			return RunConvert(ConvertOptions{
+				InputFile:  args[0],
+				OutputFile: output,
+				Force:      force,
+				NoCompile:  noCompile,
+				JSON:       jsonOutput,
+				Verbose:    verbose,
+			})
+		},
+	}
+
+	cmd.Flags().StringP("output", "o", "", "Output markdown file (default: input file with a .md extension)")
+	cmd.Flags().Bool("force", false, "Overwrite the output file if it exists")
+	cmd.Flags().Bool("no-compile", false, "Write the markdown without compiling it")
+	addJSONFlag(cmd)
+
+	return cmd
+}
@@ -0,0 +1,218 @@
+//go:build !integration
+
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const claudeReviewWorkflow = `name: Claude Review
+on:
+  pull_request:
+    types: [opened]
+permissions:
+  contents: read
+jobs:
+  review:
+    runs-on: ubuntu-latest
+    timeout-minutes: 20
+    permissions:
+      contents: read
+      pull-requests: write
+      id-token: write
+    env:
+      LOG_LEVEL: debug
+      DEPLOY_KEY: ${{ secrets.DEPLOY_KEY }}
+      GH_TOKEN: ${{ secrets.GITHUB_TOKEN }}
+    steps:
+      - uses: actions/checkout@v4
+        with:
+          fetch-depth: 0
+      - name: Install deps
+        run: npm ci
+      - name: Run Claude
+        id: claude
+        uses: anthropics/claude-code-action@v1
+        with:
+          anthropic_api_key: ${{ secrets.CLAUDE_KEY }}
+          prompt: Review this pull request.
+          claude_args: --model claude-sonnet-4-5 --allowedTools "Bash(npm test),Edit,mcp__github__get_pull_request,NotebookRead"
+          track_progress: true
+      - name: Label
+        run: gh pr edit ${{ github.event.pull_request.number }} --add-label reviewed
+      - name: Summary
+        run: echo "${{ steps.claude.outputs.result }}" >> $GITHUB_STEP_SUMMARY
+      - name: Notify
+        run: echo done
+`
+
+func TestConvertAgentWorkflow_ClaudeAction(t *testing.T) {
+	conversion, err := convertAgentWorkflow("review.yml", []byte(claudeReviewWorkflow))
+	require.NoError(t, err, "conversion should succeed")
+
+	assert.Equal(t, "claude", conversion.Engine, "engine should follow the agent action")
+	assert.Equal(t, "review", conversion.AgentJob, "agent job should be detected")
+	assert.Equal(t, []string{"add-comment", "add-labels"}, conversion.SafeOutputs, "gh pr edit and pull-requests: write should become safe outputs")
+
+	result, err := parser.ExtractFrontmatterFromContent(conversion.Markdown)
+	require.NoError(t, err, "generated markdown should have valid frontmatter")
+	fm := result.Frontmatter
+
+	assert.Equal(t, "Claude Review", fm["name"], "name should be preserved")
+	assert.Contains(t, fm, "on", "triggers should be preserved")
+	assert.Equal(t, map[string]any{"contents": "read", "issues": "read", "pull-requests": "read"}, fm["permissions"], "write permissions should be downgraded and id-token dropped")
+	assert.Equal(t, map[string]any{"id": "claude", "model": "claude-sonnet-4-5"}, fm["engine"], "model should be read from claude_args")
+	assert.Equal(t, map[string]any{"LOG_LEVEL": "debug"}, fm["env"], "only non-secret env should be kept")
+	assert.NotContains(t, fm, "runs-on", "default runner should be omitted")
+
+	tools, ok := fm["tools"].(map[string]any)
+	require.True(t, ok, "tools should be generated from allowed tools")
+	assert.Equal(t, []any{"npm test"}, tools["bash"], "Bash(...) should map to bash commands")
+	assert.Contains(t, tools, "edit", "Edit should map to edit")
+	assert.Contains(t, tools, "github", "GitHub MCP tools should map to github")
+
+	steps, ok := fm["steps"].([]any)
+	require.True(t, ok, "pre-agent steps should be kept")
+	require.Len(t, steps, 1, "checkout should be dropped")
+	postSteps, ok := fm["post-steps"].([]any)
+	require.True(t, ok, "post-steps without writes should be kept")
+	require.Len(t, postSteps, 1, "only the Notify step should be kept")
+
+	assert.Contains(t, result.Markdown, "Review this pull request.", "prompt should become the markdown body")
+	assert.Contains(t, conversion.Untranslated, "input 'track_progress' of anthropics/claude-code-action")
+	assert.Contains(t, conversion.Untranslated, "allowed tool 'NotebookRead'")
+	assert.Contains(t, conversion.Untranslated, "post-processing step 'Summary' reads the agent action's outputs")
+	assert.Contains(t, conversion.Untranslated, "environment variable DEPLOY_KEY uses a secret; add it under engine.env only if the agent must see it")
+	assert.Contains(t, conversion.Notes, "Store the value of secret CLAUDE_KEY in the repository secret ANTHROPIC_API_KEY")
+}
+
+func TestConvertAgentWorkflow_DirectAPICall(t *testing.T) {
+	content := `on:
+  issues:
+    types: [opened]
+permissions: write-all
+jobs:
+  triage:
+    runs-on: ubuntu-22.04
+    steps:
+      - name: Ask model
+        run: curl https://api.openai.com/v1/chat/completions -d @req.json
+        env:
+          OPENAI_API_KEY: ${{ secrets.OPENAI_API_KEY }}
+      - uses: actions/github-script@v7
+        with:
+          script: |
+            await github.rest.issues.createComment({});
+            await github.rest.repos.createDispatchEvent({});
+      - run: git push origin HEAD
+  notify:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo done
+`
+	conversion, err := convertAgentWorkflow("triage.yml", []byte(content))
+	require.NoError(t, err, "conversion should succeed")
+
+	assert.Equal(t, "codex", conversion.Engine, "OpenAI API calls should map to codex")
+	assert.Contains(t, conversion.Markdown, "runs-on: ubuntu-22.04", "custom runner should be kept")
+	assert.Contains(t, conversion.Markdown, "TODO: describe the task", "prompt placeholder should be generated")
+	assert.Contains(t, conversion.Untranslated, "post-processing step 'actions/github-script@v7' performs a write with no safe output equivalent (createDispatchEvent)")
+	assert.Contains(t, conversion.Untranslated, "post-processing step 'step 3' performs a write with no safe output equivalent (git push)")
+	assert.Contains(t, conversion.Untranslated, "job 'notify' was not converted; add it under jobs: if the agentic workflow still needs it")
+	assert.Contains(t, conversion.SafeOutputs, "create-pull-request", "contents: write should be inferred as create-pull-request")
+}
+
+func TestFindAgentInvocation_SeveralAPIHosts(t *testing.T) {
+	jobs := map[string]any{
+		"triage": map[string]any{
+			"steps": []any{
+				map[string]any{"run": "curl https://models.github.ai/inference -d @a.json\ncurl https://api.openai.com/v1/responses -d @b.json\ncurl https://api.anthropic.com/v1/messages -d @c.json"},
+			},
+		},
+	}
+	for range 20 {
+		_, _, invocation := findAgentInvocation(jobs)
+		require.NotNil(t, invocation, "the run step should be detected")
+		assert.Equal(t, "copilot", invocation.engine, "the host called first in the script should pick the engine")
+		assert.Equal(t, []string{"models.github.ai", "api.openai.com", "api.anthropic.com"}, invocation.hosts, "hosts should be listed in script order")
+	}
+}
+
+func TestConvertAgentWorkflow_SeveralAPIHostsReported(t *testing.T) {
+	content := "on: push\njobs:\n  triage:\n    runs-on: ubuntu-latest\n    steps:\n      - run: |\n          curl https://api.openai.com/v1/responses -d @a.json\n          curl https://api.anthropic.com/v1/messages -d @b.json\n"
+	conversion, err := convertAgentWorkflow("triage.yml", []byte(content))
+	require.NoError(t, err, "run steps calling LLM APIs should convert")
+
+	assert.Equal(t, "codex", conversion.Engine, "the first API called should pick the engine")
+	assert.Contains(t, conversion.Untranslated, "the run step also calls api.anthropic.com; only the first LLM API called, api.openai.com, was converted to the codex engine",
+		"the other hosts should be reported")
+}
+
+func TestConvertAgentWorkflow_Errors(t *testing.T) {
+	_, err := convertAgentWorkflow("ci.yml", []byte("on: push\njobs:\n  build:\n    runs-on: ubuntu-latest\n    steps:\n      - run: make\n"))
+	require.Error(t, err, "workflows without an agent should be rejected")
+	assert.Contains(t, err.Error(), "no agent invocation found", "error should explain what is missing")
+
+	_, err = convertAgentWorkflow("action.yml", []byte("runs:\n  using: node20\n"))
+	require.Error(t, err, "action definitions should be rejected")
+	assert.Contains(t, err.Error(), "is not a GitHub Actions workflow file", "error should name the problem")
+}
+
+func TestConvertAllowedTools(t *testing.T) {
+	conversion := &agentWorkflowConversion{}
+	tools := convertAllowedTools(conversion, []string{"Bash", "Read", "WebFetch", "WebSearch", "Write", "CustomTool"})
+
+	assert.Equal(t, []any{"*"}, tools["bash"], "bare Bash should allow all commands")
+	assert.Contains(t, tools, "web-fetch", "WebFetch should map to web-fetch")
+	assert.Contains(t, tools, "web-search", "WebSearch should map to web-search")
+	assert.Contains(t, tools, "edit", "Write should map to edit")
+	assert.NotContains(t, tools, "Read", "built-in read tools should not be listed")
+	assert.Equal(t, []string{"allowed tool 'CustomTool'"}, conversion.Untranslated, "unknown tools should be reported")
+}
+
+func TestComputePermissionDelta(t *testing.T) {
+	lock := `jobs:
+  agent:
+    permissions:
+      contents: read
+      pull-requests: read
+  safe_outputs:
+    permissions:
+      pull-requests: write
+  activation:
+    permissions:
+      contents: read
+`
+	changes, err := computePermissionDelta(map[string]string{"contents": "write", "pull-requests": "write"}, []byte(lock))
+	require.NoError(t, err, "lock file should parse")
+
+	assert.Equal(t, []PermissionChange{
+		{Scope: "contents", Before: "write", Agent: "read", SafeOutputs: "read"},
+		{Scope: "pull-requests", Before: "write", Agent: "read", SafeOutputs: "write"},
+	}, changes)
+
+	_, err = computePermissionDelta(nil, []byte("jobs:\n  build: {}\n"))
+	require.Error(t, err, "lock files without an agent job should be rejected")
+}
+
+func TestRunConvert_RefusesToOverwrite(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "review.yml")
+	require.NoError(t, os.WriteFile(input, []byte(claudeReviewWorkflow), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "review.md"), []byte("existing"), 0644))
+
+	err := RunConvert(ConvertOptions{InputFile: input, NoCompile: true})
+	require.Error(t, err, "existing output should not be overwritten")
+	assert.Contains(t, err.Error(), "--force", "error should suggest --force")
+
+	require.NoError(t, RunConvert(ConvertOptions{InputFile: input, NoCompile: true, Force: true, JSON: true}), "--force should overwrite")
+	written, err := os.ReadFile(filepath.Join(dir, "review.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(written), "engine:", "converted workflow should be written")
+}
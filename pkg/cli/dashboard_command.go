@@ -0,0 +1,267 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/github/gh-aw/pkg/constants"
+	"github.com/github/gh-aw/pkg/gitutil"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/tty"
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/spf13/cobra"
+)
+
+var dashboardLog = logger.New("cli:dashboard")
+
+// DashboardConfig holds configuration for dashboard command execution
+type DashboardConfig struct {
+	Days         int
+	Threshold    float64
+	Interval     time.Duration
+	RepoOverride string
+}
+
+// NewDashboardCommand creates the dashboard command
+func NewDashboardCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "dashboard",
+		Short: "Open an interactive dashboard of agentic workflow runs",
+		Long: `Open a full-screen terminal dashboard for the agentic workflows in the repository.
+
+The dashboard lists every agentic workflow with its latest run status, success
+rate, health trend and average cost. Select a workflow to see its runs, and a
+run to browse its audit report (jobs, tools, firewall, MCP, safe outputs).
+Costs come from the run summaries cached by the logs and audit commands, priced
+with the aw.json cost model when one is configured.
+While runs are queued or in progress, the dashboard polls GitHub and updates
+their status live.
+
+Hotkeys:
+  ↑/↓, j/k       Move the selection
+  enter          Drill down (workflow → runs → audit)
+  esc            Go back
+  tab, ←/→       Switch audit section
+  r              Rerun the selected run (asks for confirmation)
+  c              Cancel the selected run (asks for confirmation)
+  d              Dispatch the selected workflow (asks for confirmation)
+  o              Open the selected run in the browser
+  u              Refresh now
+  q              Quit
+
+Examples:
+  ` + string(constants.CLIExtensionPrefix) + ` dashboard                    # Dashboard for the last 7 days
+  ` + string(constants.CLIExtensionPrefix) + ` dashboard --days 30          # Include runs from the last 30 days
+  ` + string(constants.CLIExtensionPrefix) + ` dashboard --interval 10s     # Poll in-progress runs every 10 seconds
+  ` + string(constants.CLIExtensionPrefix) + ` dashboard --repo owner/repo  # Dashboard for another repository`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			days, _ := cmd.Flags().GetInt("days")
+			threshold, _ := cmd.Flags().GetFloat64("threshold")
+			interval, _ := cmd.Flags().GetDuration("interval")
+			repoOverride, _ := cmd.Flags().GetString("repo")
+
+			return RunDashboard(cmd.Context(), DashboardConfig{
+				Days:         days,
+				Threshold:    threshold,
+				Interval:     interval,
+				RepoOverride: repoOverride,
+			})
+		},
+	}
+
+	cmd.Flags().Int("days", 7, "Number of days of runs to load")
+	cmd.Flags().Float64("threshold", 80.0, "Success rate threshold for highlighting workflows (percentage)")
+	cmd.Flags().Duration("interval", 15*time.Second, "Polling interval for in-progress runs")
+	addRepoFlag(cmd)
+
+	return cmd
+}
+
+// RunDashboard runs the interactive dashboard until the user quits
+func RunDashboard(ctx context.Context, config DashboardConfig) error {
+	dashboardLog.Printf("Starting dashboard: days=%d, interval=%v, repo=%s", config.Days, config.Interval, config.RepoOverride)
+
+	if config.Days <= 0 {
+		return fmt.Errorf("invalid days value: %d. Must be a positive number", config.Days)
+	}
+	if config.Interval < time.Second {
+		return fmt.Errorf("invalid interval: %v. Must be at least 1s", config.Interval)
+	}
+	if !tty.IsStdoutTerminal() {
+		return errors.New("the dashboard requires an interactive terminal; use 'status', 'logs' or 'health' for non-interactive output")
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	source := &ghDashboardSource{days: config.Days, repo: config.RepoOverride, logsDir: defaultLogsOutputDir, costModel: loadDashboardCostModel()}
+	poller := &dashboardPoller{}
+	program := tea.NewProgram(newDashboardModel(ctx, source, poller, config.Threshold), tea.WithContext(ctx))
+
+	go func() {
+		err := PollWithSignalHandling(PollOptions{
+			Ctx:          ctx,
+			PollInterval: config.Interval,
+			PollFunc: func(pollCtx context.Context) (PollResult, error) {
+				// Stop through PollSuccess rather than context cancellation so the
+				// poll loop does not print a cancellation message once the program
+				// has restored the terminal.
+				if poller.stopped.Load() {
+					return PollSuccess, nil
+				}
+				if !poller.active.Load() {
+					return PollContinue, nil
+				}
+				runs, err := source.ListRuns(pollCtx)
+				program.Send(dashboardRunsMsg{runs: runs, err: err})
+				return PollContinue, nil
+			},
+		})
+		if err != nil {
+			dashboardLog.Printf("Polling stopped: %v", err)
+		}
+	}()
+
+	_, err := program.Run()
+	poller.stopped.Store(true)
+
+	if err != nil && (ctx.Err() == nil || !errors.Is(err, tea.ErrProgramKilled)) {
+		return fmt.Errorf("dashboard failed: %w", err)
+	}
+	return nil
+}
+
+// ghDashboardSource implements dashboardSource using the GitHub CLI
+type ghDashboardSource struct {
+	days      int
+	repo      string
+	logsDir   string
+	costModel *workflow.CostModelConfig
+}
+
+func (s *ghDashboardSource) ListRuns(_ context.Context) ([]WorkflowRun, error) {
+	runs, err := fetchAllWorkflowRuns(ListWorkflowRunsOptions{
+		StartDate:    time.Now().AddDate(0, 0, -s.days).Format("2006-01-02"),
+		Limit:        100,
+		RepoOverride: s.repo,
+		Quiet:        true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i := range runs {
+		if summary, ok := loadRunSummary(filepath.Join(s.logsDir, fmt.Sprintf("run-%d", runs[i].DatabaseID)), false); ok {
+			runs[i].EstimatedCost = dashboardRunCost(summary, s.costModel)
+		}
+	}
+	return runs, nil
+}
+
+// dashboardRunCost returns the cost of a run from the run summary cached by the
+// logs and audit commands. Token usage is priced with the aw.json cost model when
+// it covers every model; otherwise the engine-reported estimate is used, unless
+// the cost model uses another currency than the estimate.
+func dashboardRunCost(summary *RunSummary, costModel *workflow.CostModelConfig) float64 {
+	if costModel != nil && summary.TokenUsage != nil && len(summary.TokenUsage.ByModel) > 0 {
+		if cost, missing := priceTokenUsage(summary.TokenUsage, costModel); len(missing) == 0 {
+			return cost
+		}
+	}
+	if costModel.CurrencyCode() != workflow.DefaultCostCurrency {
+		return 0
+	}
+	return summary.Run.EstimatedCost
+}
+
+// loadDashboardCostModel returns the aw.json cost model of the current
+// repository, or nil outside a repository or when none is configured
+func loadDashboardCostModel() *workflow.CostModelConfig {
+	gitRoot, err := gitutil.FindGitRoot()
+	if err != nil {
+		return nil
+	}
+	repoConfig, err := workflow.LoadRepoConfig(gitRoot)
+	if err != nil {
+		dashboardLog.Printf("Ignoring cost model: %v", err)
+		return nil
+	}
+	return repoConfig.CostModel()
+}
+
+// Audit runs `gh aw audit --json` as a subprocess so that its progress output
+// cannot interfere with the full-screen terminal UI
+func (s *ghDashboardSource) Audit(ctx context.Context, runID int64) (*AuditData, error) {
+	binary, err := GetBinaryPath()
+	if err != nil {
+		return nil, err
+	}
+	args := []string{"audit", strconv.FormatInt(runID, 10), "--json"}
+	if s.repo != "" {
+		args = append(args, "--repo", s.repo)
+	}
+	dashboardLog.Printf("Running audit: %s %v", binary, args)
+
+	output, err := exec.CommandContext(ctx, binary, args...).Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			return nil, fmt.Errorf("audit of run %d failed: %s", runID, lastNonEmptyLine(string(exitErr.Stderr)))
+		}
+		return nil, fmt.Errorf("audit of run %d failed: %w", runID, err)
+	}
+
+	var audit AuditData
+	if err := json.Unmarshal(output, &audit); err != nil {
+		return nil, fmt.Errorf("failed to parse audit report for run %d: %w", runID, err)
+	}
+	return &audit, nil
+}
+
+func (s *ghDashboardSource) Rerun(ctx context.Context, runID int64) error {
+	return s.runGH(ctx, "run", "rerun", strconv.FormatInt(runID, 10))
+}
+
+func (s *ghDashboardSource) Cancel(ctx context.Context, runID int64) error {
+	return s.runGH(ctx, "run", "cancel", strconv.FormatInt(runID, 10))
+}
+
+func (s *ghDashboardSource) Dispatch(ctx context.Context, workflowName string) error {
+	return s.runGH(ctx, "workflow", "run", workflowName)
+}
+
+func (s *ghDashboardSource) OpenInBrowser(ctx context.Context, runID int64) error {
+	return s.runGH(ctx, "run", "view", strconv.FormatInt(runID, 10), "--web")
+}
+
+// runGH runs a gh command with the dashboard's repository override, returning
+// the last line of gh's output as the error message on failure
+func (s *ghDashboardSource) runGH(ctx context.Context, args ...string) error {
+	if s.repo != "" {
+		args = append(args, "--repo", s.repo)
+	}
+	dashboardLog.Printf("Running gh %v", args)
+	output, err := workflow.ExecGHContext(ctx, args...).CombinedOutput()
+	if err != nil {
+		if line := lastNonEmptyLine(string(output)); line != "" {
+			return errors.New(line)
+		}
+		return err
+	}
+	return nil
+}
+
+// lastNonEmptyLine returns the last non-blank line of command output, which is
+// where gh and gh-aw report the reason for a failure
+func lastNonEmptyLine(output string) string {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
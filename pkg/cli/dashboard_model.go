@@ -0,0 +1,636 @@
+package cli
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/styles"
+	"github.com/github/gh-aw/pkg/timeutil"
+)
+
+var dashboardModelLog = logger.New("cli:dashboard_model")
+
+// dashboardSource provides the data and actions used by the dashboard.
+// The GitHub-backed implementation lives in dashboard_command.go.
+type dashboardSource interface {
+	ListRuns(ctx context.Context) ([]WorkflowRun, error)
+	Audit(ctx context.Context, runID int64) (*AuditData, error)
+	Rerun(ctx context.Context, runID int64) error
+	Cancel(ctx context.Context, runID int64) error
+	Dispatch(ctx context.Context, workflowName string) error
+	OpenInBrowser(ctx context.Context, runID int64) error
+}
+
+// dashboardView identifies the current drill-down level
+type dashboardView int
+
+const (
+	dashboardWorkflowsView dashboardView = iota
+	dashboardRunsView
+	dashboardAuditView
+)
+
+// dashboardAuditSections lists the audit report sections shown in the audit view
+var dashboardAuditSections = []string{"Jobs", "Tools", "Firewall", "MCP", "Safe outputs"}
+
+// dashboardWorkflow groups the runs of a single workflow, newest first
+type dashboardWorkflow struct {
+	Name   string
+	Runs   []WorkflowRun
+	Health WorkflowHealth
+}
+
+// Latest returns the most recent run of the workflow
+func (w dashboardWorkflow) Latest() WorkflowRun {
+	if len(w.Runs) == 0 {
+		return WorkflowRun{}
+	}
+	return w.Runs[0]
+}
+
+// dashboardRunsMsg delivers a fresh list of runs, either from the initial load or a poll
+type dashboardRunsMsg struct {
+	runs []WorkflowRun
+	err  error
+}
+
+// dashboardAuditMsg delivers the audit report for a run
+type dashboardAuditMsg struct {
+	runID int64
+	audit *AuditData
+	err   error
+}
+
+// dashboardActionMsg reports the outcome of a hotkey action
+type dashboardActionMsg struct {
+	description string
+	refresh     bool
+	err         error
+}
+
+// dashboardConfirmation is a hotkey action waiting for the user to confirm it
+type dashboardConfirmation struct {
+	prompt string
+	cmd    tea.Cmd
+}
+
+// dashboardPoller tracks whether the dashboard has in-progress runs worth polling
+// and lets the poll loop know when the program has exited
+type dashboardPoller struct {
+	active  atomic.Bool
+	stopped atomic.Bool
+}
+
+// dashboardModel is the Bubble Tea model for the dashboard
+type dashboardModel struct {
+	ctx       context.Context
+	source    dashboardSource
+	poller    *dashboardPoller
+	threshold float64
+
+	view      dashboardView
+	workflows []dashboardWorkflow
+	wfCursor  int
+	runCursor int
+	section   int
+
+	audit      *AuditData
+	auditRunID int64
+
+	// confirm holds a rerun, cancel or dispatch action until the user presses y
+	confirm *dashboardConfirmation
+
+	loading     bool
+	status      string
+	err         error
+	lastUpdated time.Time
+	width       int
+	height      int
+}
+
+func newDashboardModel(ctx context.Context, source dashboardSource, poller *dashboardPoller, threshold float64) dashboardModel {
+	return dashboardModel{
+		ctx:       ctx,
+		source:    source,
+		poller:    poller,
+		threshold: threshold,
+		loading:   true,
+		status:    "Loading workflow runs...",
+	}
+}
+
+// buildDashboardWorkflows groups runs by workflow, sorts each workflow's runs
+// newest first and computes health metrics
+func buildDashboardWorkflows(runs []WorkflowRun, threshold float64) []dashboardWorkflow {
+	grouped := GroupRunsByWorkflow(runs)
+	workflows := make([]dashboardWorkflow, 0, len(grouped))
+	for name, workflowRuns := range grouped {
+		slices.SortFunc(workflowRuns, func(a, b WorkflowRun) int {
+			return b.CreatedAt.Compare(a.CreatedAt)
+		})
+		workflows = append(workflows, dashboardWorkflow{
+			Name:   name,
+			Runs:   workflowRuns,
+			Health: CalculateWorkflowHealth(name, workflowRuns, threshold),
+		})
+	}
+	slices.SortFunc(workflows, func(a, b dashboardWorkflow) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+	return workflows
+}
+
+// isActiveRun reports whether a run is still queued or executing
+func isActiveRun(run WorkflowRun) bool {
+	return run.Status != "" && run.Status != "completed"
+}
+
+func (m dashboardModel) loadRuns() tea.Cmd {
+	return func() tea.Msg {
+		runs, err := m.source.ListRuns(m.ctx)
+		return dashboardRunsMsg{runs: runs, err: err}
+	}
+}
+
+func (m dashboardModel) loadAudit(runID int64) tea.Cmd {
+	return func() tea.Msg {
+		audit, err := m.source.Audit(m.ctx, runID)
+		return dashboardAuditMsg{runID: runID, audit: audit, err: err}
+	}
+}
+
+func (m dashboardModel) runAction(description string, refresh bool, action func() error) tea.Cmd {
+	return func() tea.Msg {
+		return dashboardActionMsg{description: description, refresh: refresh, err: action()}
+	}
+}
+
+func (m dashboardModel) Init() tea.Cmd {
+	return m.loadRuns()
+}
+
+func (m dashboardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		return m, nil
+
+	case dashboardRunsMsg:
+		m.loading = false
+		if msg.err != nil {
+			dashboardModelLog.Printf("Failed to load runs: %v", msg.err)
+			m.err = msg.err
+			return m, nil
+		}
+		m.err = nil
+		m.status = ""
+		m.lastUpdated = time.Now()
+		m.setRuns(msg.runs)
+		return m, nil
+
+	case dashboardAuditMsg:
+		if msg.runID != m.auditRunID {
+			return m, nil
+		}
+		m.loading = false
+		if msg.err != nil {
+			m.err = msg.err
+			return m, nil
+		}
+		m.err = nil
+		m.status = ""
+		m.audit = msg.audit
+		return m, nil
+
+	case dashboardActionMsg:
+		if msg.err != nil {
+			m.err = fmt.Errorf("%s failed: %w", msg.description, msg.err)
+			return m, nil
+		}
+		m.err = nil
+		m.status = msg.description
+		if msg.refresh {
+			return m, m.loadRuns()
+		}
+		return m, nil
+
+	case tea.KeyPressMsg:
+		return m.handleKey(msg.String())
+	}
+	return m, nil
+}
+
+// setRuns replaces the dashboard data while keeping the selection on the same
+// workflow and run where possible
+func (m *dashboardModel) setRuns(runs []WorkflowRun) {
+	selectedWorkflow := m.selectedWorkflow().Name
+	selectedRun := m.selectedRun().DatabaseID
+
+	m.workflows = buildDashboardWorkflows(runs, m.threshold)
+	m.wfCursor = max(0, slices.IndexFunc(m.workflows, func(w dashboardWorkflow) bool { return w.Name == selectedWorkflow }))
+	m.runCursor = max(0, slices.IndexFunc(m.selectedWorkflow().Runs, func(r WorkflowRun) bool { return r.DatabaseID == selectedRun }))
+
+	if m.poller != nil {
+		m.poller.active.Store(slices.ContainsFunc(runs, isActiveRun))
+	}
+}
+
+func (m dashboardModel) selectedWorkflow() dashboardWorkflow {
+	if m.wfCursor < 0 || m.wfCursor >= len(m.workflows) {
+		return dashboardWorkflow{}
+	}
+	return m.workflows[m.wfCursor]
+}
+
+func (m dashboardModel) selectedRun() WorkflowRun {
+	runs := m.selectedWorkflow().Runs
+	if m.view == dashboardWorkflowsView || m.runCursor < 0 || m.runCursor >= len(runs) {
+		return m.selectedWorkflow().Latest()
+	}
+	return runs[m.runCursor]
+}
+
+func (m dashboardModel) handleKey(key string) (tea.Model, tea.Cmd) {
+	if m.confirm != nil {
+		confirm := m.confirm
+		m.confirm = nil
+		switch key {
+		case "ctrl+c":
+			return m, tea.Quit
+		case "y", "Y":
+			return m, confirm.cmd
+		}
+		m.status = "Cancelled"
+		return m, nil
+	}
+
+	switch key {
+	case "ctrl+c", "q":
+		return m, tea.Quit
+
+	case "up", "k":
+		m.moveCursor(-1)
+	case "down", "j":
+		m.moveCursor(1)
+
+	case "enter":
+		switch m.view {
+		case dashboardWorkflowsView:
+			if len(m.selectedWorkflow().Runs) > 0 {
+				m.view = dashboardRunsView
+				m.runCursor = 0
+			}
+		case dashboardRunsView:
+			run := m.selectedRun()
+			if run.DatabaseID == 0 {
+				return m, nil
+			}
+			m.view = dashboardAuditView
+			m.section = 0
+			m.audit = nil
+			m.auditRunID = run.DatabaseID
+			m.loading = true
+			m.status = fmt.Sprintf("Auditing run %d...", run.DatabaseID)
+			return m, m.loadAudit(run.DatabaseID)
+		}
+
+	case "esc", "backspace":
+		switch m.view {
+		case dashboardRunsView:
+			m.view = dashboardWorkflowsView
+		case dashboardAuditView:
+			m.view = dashboardRunsView
+			m.auditRunID = 0
+			m.loading = false
+		}
+		m.err = nil
+
+	case "tab", "right", "l":
+		if m.view == dashboardAuditView {
+			m.section = (m.section + 1) % len(dashboardAuditSections)
+		}
+	case "shift+tab", "left", "h":
+		if m.view == dashboardAuditView {
+			m.section = (m.section + len(dashboardAuditSections) - 1) % len(dashboardAuditSections)
+		}
+
+	case "u":
+		m.status = "Refreshing..."
+		return m, m.loadRuns()
+
+	case "r":
+		if run := m.selectedRun(); run.DatabaseID != 0 {
+			m.confirm = &dashboardConfirmation{
+				prompt: fmt.Sprintf("Rerun run %d? (y/N)", run.DatabaseID),
+				cmd: m.runAction(fmt.Sprintf("Rerun of run %d requested", run.DatabaseID), true, func() error {
+					return m.source.Rerun(m.ctx, run.DatabaseID)
+				}),
+			}
+		}
+	case "c":
+		if run := m.selectedRun(); isActiveRun(run) {
+			m.confirm = &dashboardConfirmation{
+				prompt: fmt.Sprintf("Cancel run %d? (y/N)", run.DatabaseID),
+				cmd: m.runAction(fmt.Sprintf("Cancellation of run %d requested", run.DatabaseID), true, func() error {
+					return m.source.Cancel(m.ctx, run.DatabaseID)
+				}),
+			}
+		}
+	case "d":
+		if name := m.selectedWorkflow().Name; name != "" {
+			m.confirm = &dashboardConfirmation{
+				prompt: fmt.Sprintf("Dispatch workflow '%s'? (y/N)", name),
+				cmd: m.runAction(fmt.Sprintf("Dispatched workflow '%s'", name), true, func() error {
+					return m.source.Dispatch(m.ctx, name)
+				}),
+			}
+		}
+	case "o":
+		if run := m.selectedRun(); run.DatabaseID != 0 {
+			return m, m.runAction(fmt.Sprintf("Opened run %d in the browser", run.DatabaseID), false, func() error {
+				return m.source.OpenInBrowser(m.ctx, run.DatabaseID)
+			})
+		}
+	}
+	return m, nil
+}
+
+func (m *dashboardModel) moveCursor(delta int) {
+	switch m.view {
+	case dashboardWorkflowsView:
+		m.wfCursor = clampCursor(m.wfCursor+delta, len(m.workflows))
+	case dashboardRunsView:
+		m.runCursor = clampCursor(m.runCursor+delta, len(m.selectedWorkflow().Runs))
+	}
+}
+
+func clampCursor(cursor, length int) int {
+	if length == 0 {
+		return 0
+	}
+	return min(max(cursor, 0), length-1)
+}
+
+func (m dashboardModel) View() tea.View {
+	view := tea.NewView(m.render())
+	view.AltScreen = true
+	return view
+}
+
+// render builds the textual content of the current view
+func (m dashboardModel) render() string {
+	var b strings.Builder
+	b.WriteString(styles.Header.Render("Agentic Workflows Dashboard"))
+	b.WriteString("\n")
+	b.WriteString(styles.Verbose.Render(m.breadcrumb()))
+	b.WriteString("\n\n")
+
+	switch m.view {
+	case dashboardWorkflowsView:
+		b.WriteString(m.renderWorkflows())
+	case dashboardRunsView:
+		b.WriteString(m.renderRuns())
+	case dashboardAuditView:
+		b.WriteString(m.renderAudit())
+	}
+
+	b.WriteString("\n")
+	switch {
+	case m.confirm != nil:
+		b.WriteString(styles.Warning.Render(m.confirm.prompt))
+	case m.err != nil:
+		b.WriteString(styles.Error.Render("✗ " + m.err.Error()))
+	case m.status != "":
+		b.WriteString(styles.Info.Render(m.status))
+	case !m.lastUpdated.IsZero():
+		updated := "Updated " + m.lastUpdated.Format("15:04:05")
+		if m.poller != nil && m.poller.active.Load() {
+			updated += " · polling in-progress runs"
+		}
+		b.WriteString(styles.Verbose.Render(updated))
+	}
+	b.WriteString("\n")
+	b.WriteString(styles.Verbose.Render(m.helpLine()))
+	return b.String()
+}
+
+func (m dashboardModel) breadcrumb() string {
+	parts := []string{"workflows"}
+	if m.view >= dashboardRunsView {
+		parts = append(parts, m.selectedWorkflow().Name)
+	}
+	if m.view == dashboardAuditView {
+		parts = append(parts, fmt.Sprintf("run %d", m.auditRunID))
+	}
+	return strings.Join(parts, " › ")
+}
+
+func (m dashboardModel) helpLine() string {
+	switch m.view {
+	case dashboardWorkflowsView:
+		return "↑/↓ select · enter runs · r rerun latest · c cancel · d dispatch · o open · u refresh · q quit"
+	case dashboardRunsView:
+		return "↑/↓ select · enter audit · r rerun · c cancel · d dispatch · o open · esc back · q quit"
+	default:
+		return "tab/←/→ section · r rerun · c cancel · o open · esc back · q quit"
+	}
+}
+
+func (m dashboardModel) renderWorkflows() string {
+	if m.loading && len(m.workflows) == 0 {
+		return ""
+	}
+	if len(m.workflows) == 0 {
+		return styles.Warning.Render("No agentic workflow runs found") + "\n"
+	}
+
+	var b strings.Builder
+	b.WriteString(styles.TableHeader.Render(fmt.Sprintf("  %-40s %-14s %-10s %-6s %-10s %5s", "WORKFLOW", "LATEST", "SUCCESS", "TREND", "AVG COST", "RUNS")))
+	b.WriteString("\n")
+	for i, wf := range m.workflows {
+		rate := wf.Health.DisplayRate
+		if wf.Health.BelowThresh {
+			rate = styles.Warning.Render(fmt.Sprintf("%-10s", rate))
+		} else {
+			rate = fmt.Sprintf("%-10s", rate)
+		}
+		line := fmt.Sprintf("%-40s %s %s %-6s %-10s %5d",
+			truncateDashboardCell(wf.Name, 40),
+			renderDashboardStatus(wf.Latest(), 14),
+			rate,
+			wf.Health.Trend,
+			wf.Health.DisplayCost,
+			len(wf.Runs))
+		b.WriteString(dashboardCursor(i == m.wfCursor) + line + "\n")
+	}
+	return b.String()
+}
+
+func (m dashboardModel) renderRuns() string {
+	runs := m.selectedWorkflow().Runs
+	var b strings.Builder
+	b.WriteString(styles.TableHeader.Render(fmt.Sprintf("  %-12s %-14s %-12s %-20s %-10s %-8s %s", "RUN", "STATUS", "EVENT", "CREATED", "DURATION", "COST", "TITLE")))
+	b.WriteString("\n")
+	for i, run := range runs {
+		duration := "-"
+		if run.Duration > 0 {
+			duration = timeutil.FormatDuration(run.Duration)
+		}
+		line := fmt.Sprintf("%-12d %s %-12s %-20s %-10s %-8s %s",
+			run.DatabaseID,
+			renderDashboardStatus(run, 14),
+			truncateDashboardCell(run.Event, 12),
+			run.CreatedAt.Local().Format("2006-01-02 15:04"),
+			duration,
+			formatCost(run.EstimatedCost),
+			truncateDashboardCell(run.DisplayTitle, 50))
+		b.WriteString(dashboardCursor(i == m.runCursor) + line + "\n")
+	}
+	return b.String()
+}
+
+func (m dashboardModel) renderAudit() string {
+	var b strings.Builder
+	for i, section := range dashboardAuditSections {
+		if i > 0 {
+			b.WriteString("  ")
+		}
+		if i == m.section {
+			b.WriteString(styles.Highlight.Render(" " + section + " "))
+		} else {
+			b.WriteString(styles.Verbose.Render(" " + section + " "))
+		}
+	}
+	b.WriteString("\n\n")
+
+	if m.audit == nil {
+		return b.String()
+	}
+
+	var lines []string
+	switch dashboardAuditSections[m.section] {
+	case "Jobs":
+		lines = renderDashboardJobs(m.audit.Jobs)
+	case "Tools":
+		lines = renderDashboardTools(m.audit.ToolUsage)
+	case "Firewall":
+		lines = renderDashboardFirewall(m.audit.FirewallAnalysis)
+	case "MCP":
+		lines = renderDashboardMCP(m.audit.MCPServerHealth, m.audit.MCPFailures)
+	case "Safe outputs":
+		lines = renderDashboardSafeOutputs(m.audit.SafeOutputSummary, m.audit.CreatedItems)
+	}
+	if len(lines) == 0 {
+		lines = []string{styles.Verbose.Render("No data for this section")}
+	}
+	b.WriteString(strings.Join(lines, "\n"))
+	b.WriteString("\n")
+	return b.String()
+}
+
+func renderDashboardJobs(jobs []JobData) []string {
+	lines := make([]string, 0, len(jobs))
+	for _, job := range jobs {
+		state := job.Conclusion
+		if state == "" {
+			state = job.Status
+		}
+		lines = append(lines, fmt.Sprintf("%-40s %-12s %s", truncateDashboardCell(job.Name, 40), state, job.Duration))
+	}
+	return lines
+}
+
+func renderDashboardTools(tools []ToolUsageInfo) []string {
+	lines := make([]string, 0, len(tools))
+	for _, tool := range tools {
+		lines = append(lines, fmt.Sprintf("%-50s %6d calls  %s", truncateDashboardCell(tool.Name, 50), tool.CallCount, tool.MaxDuration))
+	}
+	return lines
+}
+
+func renderDashboardFirewall(analysis *FirewallAnalysis) []string {
+	if analysis == nil {
+		return nil
+	}
+	lines := []string{fmt.Sprintf("Requests: %d total, %d allowed, %d blocked", analysis.TotalRequests, analysis.AllowedRequests, analysis.BlockedRequests)}
+	for _, domain := range analysis.BlockedDomains {
+		lines = append(lines, styles.Error.Render("  ✗ "+domain))
+	}
+	for _, domain := range analysis.AllowedDomains {
+		lines = append(lines, "  ✓ "+domain)
+	}
+	return lines
+}
+
+func renderDashboardMCP(health *MCPServerHealth, failures []MCPFailureReport) []string {
+	var lines []string
+	if health != nil {
+		lines = append(lines, health.Summary)
+		for _, server := range health.Servers {
+			lines = append(lines, fmt.Sprintf("  %-30s %-10s %5d requests  %s errors  %s", truncateDashboardCell(server.ServerName, 30), server.Status, server.RequestCount, server.ErrorRateStr, server.AvgLatency))
+		}
+	}
+	for _, failure := range failures {
+		lines = append(lines, styles.Error.Render(fmt.Sprintf("  ✗ %s: %s", failure.ServerName, failure.Status)))
+	}
+	return lines
+}
+
+func renderDashboardSafeOutputs(summary *SafeOutputSummary, items []CreatedItemReport) []string {
+	var lines []string
+	if summary != nil {
+		lines = append(lines, summary.Summary)
+	}
+	for _, item := range items {
+		target := item.URL
+		if target == "" && item.Number > 0 {
+			target = fmt.Sprintf("#%d", item.Number)
+		}
+		lines = append(lines, fmt.Sprintf("  %-28s %s", item.Type, target))
+	}
+	return lines
+}
+
+// renderDashboardStatus renders the run status padded to width, coloured by outcome
+func renderDashboardStatus(run WorkflowRun, width int) string {
+	label := run.Conclusion
+	if label == "" {
+		label = run.Status
+	}
+	if label == "" {
+		label = "-"
+	}
+	cell := fmt.Sprintf("%-*s", width, truncateDashboardCell(label, width))
+	switch {
+	case isActiveRun(run):
+		return styles.Progress.Render(cell)
+	case run.Conclusion == "success":
+		return styles.Success.Render(cell)
+	case isFailureConclusion(run.Conclusion):
+		return styles.Error.Render(cell)
+	default:
+		return styles.Warning.Render(cell)
+	}
+}
+
+func dashboardCursor(selected bool) string {
+	if selected {
+		return styles.Prompt.Render("›") + " "
+	}
+	return "  "
+}
+
+func truncateDashboardCell(s string, width int) string {
+	runes := []rune(s)
+	if len(runes) <= width {
+		return s
+	}
+	return string(runes[:width-1]) + "…"
+}
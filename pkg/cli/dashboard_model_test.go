@@ -0,0 +1,195 @@
+//go:build !integration
+
+package cli
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDashboardSource records the actions requested by the dashboard
+type fakeDashboardSource struct {
+	runs       []WorkflowRun
+	audit      *AuditData
+	rerun      []int64
+	cancelled  []int64
+	dispatched []string
+	opened     []int64
+	actionErr  error
+}
+
+func (f *fakeDashboardSource) ListRuns(context.Context) ([]WorkflowRun, error) { return f.runs, nil }
+func (f *fakeDashboardSource) Audit(context.Context, int64) (*AuditData, error) {
+	return f.audit, nil
+}
+func (f *fakeDashboardSource) Rerun(_ context.Context, runID int64) error {
+	f.rerun = append(f.rerun, runID)
+	return f.actionErr
+}
+func (f *fakeDashboardSource) Cancel(_ context.Context, runID int64) error {
+	f.cancelled = append(f.cancelled, runID)
+	return f.actionErr
+}
+func (f *fakeDashboardSource) Dispatch(_ context.Context, workflowName string) error {
+	f.dispatched = append(f.dispatched, workflowName)
+	return f.actionErr
+}
+func (f *fakeDashboardSource) OpenInBrowser(_ context.Context, runID int64) error {
+	f.opened = append(f.opened, runID)
+	return f.actionErr
+}
+
+func dashboardTestRuns() []WorkflowRun {
+	now := time.Now()
+	return []WorkflowRun{
+		{DatabaseID: 1, WorkflowName: "triage", Status: "completed", Conclusion: "success", CreatedAt: now.Add(-2 * time.Hour)},
+		{DatabaseID: 2, WorkflowName: "triage", Status: "in_progress", CreatedAt: now.Add(-time.Hour)},
+		{DatabaseID: 3, WorkflowName: "docs", Status: "completed", Conclusion: "failure", CreatedAt: now},
+	}
+}
+
+// sendDashboard feeds msg to the model and runs any resulting command once,
+// feeding its message back in, mimicking the Bubble Tea event loop
+func sendDashboard(t *testing.T, m dashboardModel, msg tea.Msg) dashboardModel {
+	t.Helper()
+	next, cmd := m.Update(msg)
+	m = next.(dashboardModel)
+	if cmd != nil {
+		if result := cmd(); result != nil {
+			next, _ = m.Update(result)
+			m = next.(dashboardModel)
+		}
+	}
+	return m
+}
+
+func pressKey(t *testing.T, m dashboardModel, key string) dashboardModel {
+	t.Helper()
+	var msg tea.KeyPressMsg
+	switch key {
+	case "enter":
+		msg = tea.KeyPressMsg{Code: tea.KeyEnter}
+	case "esc":
+		msg = tea.KeyPressMsg{Code: tea.KeyEscape}
+	case "tab":
+		msg = tea.KeyPressMsg{Code: tea.KeyTab}
+	case "down":
+		msg = tea.KeyPressMsg{Code: tea.KeyDown}
+	default:
+		msg = tea.KeyPressMsg{Code: rune(key[0]), Text: key}
+	}
+	return sendDashboard(t, m, msg)
+}
+
+func TestBuildDashboardWorkflows(t *testing.T) {
+	workflows := buildDashboardWorkflows(dashboardTestRuns(), 80)
+
+	require.Len(t, workflows, 2, "runs should be grouped by workflow")
+	assert.Equal(t, "docs", workflows[0].Name, "workflows should be sorted by name")
+	assert.Equal(t, "triage", workflows[1].Name, "workflows should be sorted by name")
+	assert.Equal(t, int64(2), workflows[1].Latest().DatabaseID, "latest run should be the newest one")
+	assert.Equal(t, 2, workflows[1].Health.TotalRuns, "health should be computed from the workflow's runs")
+}
+
+func TestDashboardModel_DrillDown(t *testing.T) {
+	source := &fakeDashboardSource{
+		runs:  dashboardTestRuns(),
+		audit: &AuditData{Jobs: []JobData{{Name: "agent", Conclusion: "success"}}},
+	}
+	poller := &dashboardPoller{}
+	m := newDashboardModel(context.Background(), source, poller, 80)
+	m = sendDashboard(t, m, m.Init()())
+
+	assert.True(t, poller.active.Load(), "poller should be active while a run is in progress")
+	assert.Contains(t, m.render(), "triage", "workflow list should show workflows")
+
+	m = pressKey(t, m, "down")
+	m = pressKey(t, m, "enter")
+	require.Equal(t, dashboardRunsView, m.view, "enter should open the run list")
+	assert.Equal(t, "triage", m.selectedWorkflow().Name, "selected workflow should be kept")
+
+	m = pressKey(t, m, "enter")
+	require.Equal(t, dashboardAuditView, m.view, "enter should open the audit view")
+	assert.Equal(t, int64(2), m.auditRunID, "the selected run should be audited")
+	require.NotNil(t, m.audit, "audit report should be loaded")
+	assert.Contains(t, m.render(), "agent", "jobs section should list jobs")
+
+	m = pressKey(t, m, "tab")
+	assert.Equal(t, 1, m.section, "tab should switch to the next audit section")
+
+	m = pressKey(t, m, "esc")
+	assert.Equal(t, dashboardRunsView, m.view, "esc should go back to the run list")
+	m = pressKey(t, m, "esc")
+	assert.Equal(t, dashboardWorkflowsView, m.view, "esc should go back to the workflow list")
+}
+
+func TestDashboardModel_Hotkeys(t *testing.T) {
+	source := &fakeDashboardSource{runs: dashboardTestRuns()}
+	m := newDashboardModel(context.Background(), source, &dashboardPoller{}, 80)
+	m = sendDashboard(t, m, dashboardRunsMsg{runs: source.runs})
+	m = pressKey(t, m, "down")
+
+	m = pressKey(t, m, "r")
+	assert.Contains(t, m.render(), "Rerun run 2? (y/N)", "r should ask for confirmation")
+	assert.Empty(t, source.rerun, "r should not rerun before confirmation")
+	m = pressKey(t, m, "y")
+	m = pressKey(t, m, "c")
+	m = pressKey(t, m, "y")
+	m = pressKey(t, m, "d")
+	m = pressKey(t, m, "y")
+	m = pressKey(t, m, "o")
+
+	assert.Equal(t, []int64{2}, source.rerun, "r should rerun the latest run of the selected workflow")
+	assert.Equal(t, []int64{2}, source.cancelled, "c should cancel the in-progress run")
+	assert.Equal(t, []string{"triage"}, source.dispatched, "d should dispatch the selected workflow")
+	assert.Equal(t, []int64{2}, source.opened, "o should open the selected run")
+
+	source.actionErr = errors.New("HTTP 403")
+	m = pressKey(t, m, "r")
+	m = pressKey(t, m, "y")
+	require.Error(t, m.err, "failed actions should be surfaced")
+	assert.Contains(t, m.render(), "HTTP 403", "failed actions should be shown in the status line")
+}
+
+func TestDashboardModel_CancelIgnoresCompletedRuns(t *testing.T) {
+	source := &fakeDashboardSource{runs: dashboardTestRuns()}
+	m := newDashboardModel(context.Background(), source, &dashboardPoller{}, 80)
+	m = sendDashboard(t, m, dashboardRunsMsg{runs: source.runs})
+
+	m = pressKey(t, m, "c")
+	pressKey(t, m, "y")
+	assert.Empty(t, source.cancelled, "completed runs should not be cancelled")
+}
+
+func TestDashboardModel_ConfirmationDeclined(t *testing.T) {
+	source := &fakeDashboardSource{runs: dashboardTestRuns()}
+	m := newDashboardModel(context.Background(), source, &dashboardPoller{}, 80)
+	m = sendDashboard(t, m, dashboardRunsMsg{runs: source.runs})
+
+	m = pressKey(t, m, "d")
+	m = pressKey(t, m, "n")
+	assert.Empty(t, source.dispatched, "declining the confirmation should not dispatch")
+	assert.Nil(t, m.confirm, "the confirmation should be cleared")
+	assert.Contains(t, m.render(), "Cancelled")
+}
+
+func TestDashboardRunCost(t *testing.T) {
+	summary := &RunSummary{
+		Run:        WorkflowRun{EstimatedCost: 0.5},
+		TokenUsage: &TokenUsageSummary{ByModel: map[string]*ModelTokenUsage{"claude-sonnet-4": {InputTokens: 1_000_000}}},
+	}
+	assert.InDelta(t, 0.5, dashboardRunCost(summary, nil), 1e-9, "without a cost model the engine estimate should be used")
+
+	priced := &workflow.CostModelConfig{Models: map[string]workflow.ModelPrice{"claude-*": {Input: 3, Output: 15}}}
+	assert.InDelta(t, 3.0, dashboardRunCost(summary, priced), 1e-9, "token usage should be priced with the cost model")
+
+	unpriced := &workflow.CostModelConfig{Currency: "EUR", Models: map[string]workflow.ModelPrice{"gpt-*": {Input: 1}}}
+	assert.Zero(t, dashboardRunCost(summary, unpriced), "USD estimates should not be shown in another currency")
+}
@@ -17,17 +17,16 @@ package cli
 //   - R  = reasoning tokens     (w_reason = 4.0 default)
 //   - m  = per-model multiplier relative to the reference model
 //
-// Token class weights and model multipliers are loaded from the embedded
-// data/model_multipliers.json file and can be updated without recompilation.
+// Token class weights and model multipliers are loaded from the
+// pkg/workflow/data/model_multipliers.json file embedded in the workflow package.
 //
 // Key responsibilities:
-//   - Embedding model_multipliers.json at compile time
+//   - Parsing the embedded model_multipliers.json registry
 //   - Applying token class weights before the model multiplier
 //   - Computing effective tokens from raw per-model token usage data
 //   - Populating effective token counts on TokenUsageSummary after parsing
 
 import (
-	_ "embed"
 	"encoding/json"
 	"maps"
 	"math"
@@ -35,12 +34,13 @@ import (
 
 	"github.com/github/gh-aw/pkg/logger"
 	"github.com/github/gh-aw/pkg/types"
+	"github.com/github/gh-aw/pkg/workflow"
 )
 
 var effectiveTokensLog = logger.New("cli:effective_tokens")
 
-//go:embed data/model_multipliers.json
-var modelMultipliersJSON []byte
+// modelMultipliersJSON is the model multiplier registry shared with the compiler.
+var modelMultipliersJSON = workflow.BuiltinModelMultipliersJSON()
 
 // tokenClassWeights holds the per-token-class weight values from the specification.
 type tokenClassWeights struct {
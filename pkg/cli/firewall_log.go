@@ -245,6 +245,12 @@ func parseFirewallLogLine(line string) *FirewallLogEntry {
 	}
 }
 
+// isInternalSquidEntry reports whether entry is an internal Squid connection error
+// (client IP ::1, no domain, no destination) rather than an external network request.
+func isInternalSquidEntry(entry *FirewallLogEntry) bool {
+	return strings.HasPrefix(entry.ClientIPPort, "::1:") && entry.Domain == "-" && (entry.DestIPPort == "-:-" || entry.DestIPPort == "-")
+}
+
 // isRequestAllowed determines if a request was allowed based on decision and status
 // This mirrors the logic from the JavaScript parser
 func isRequestAllowed(decision, status string) bool {
@@ -304,7 +310,7 @@ func parseFirewallLog(logPath string, verbose bool) (*FirewallAnalysis, error) {
 		// These are internal Squid connection errors (e.g., error:transaction-end-before-headers)
 		// and are not actual external network requests.
 		// Example: 1773003472.027 ::1:52010 - -:- 0.0 - 0 NONE_NONE:HIER_NONE error:transaction-end-before-headers "-"
-		if strings.HasPrefix(entry.ClientIPPort, "::1:") && entry.Domain == "-" && (entry.DestIPPort == "-:-" || entry.DestIPPort == "-") {
+		if isInternalSquidEntry(entry) {
 			continue
 		}
 
@@ -368,6 +374,63 @@ func parseFirewallLog(logPath string, verbose bool) (*FirewallAnalysis, error) {
 	return analysis, nil
 }
 
+// readFirewallLogEntries reads the individual requests from a firewall log file,
+// skipping comments, malformed lines and internal Squid errors.
+func readFirewallLogEntries(logPath string) ([]FirewallLogEntry, error) {
+	file, err := os.Open(logPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open firewall log: %w", err)
+	}
+	defer file.Close()
+
+	var entries []FirewallLogEntry
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		entry := parseFirewallLogLine(scanner.Text())
+		if entry == nil || isInternalSquidEntry(entry) {
+			continue
+		}
+		entries = append(entries, *entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading firewall log: %w", err)
+	}
+	return entries, nil
+}
+
+// findFirewallLogFiles returns the firewall log files for a run directory,
+// using the same locations as analyzeFirewallLogs.
+func findFirewallLogFiles(runDir string) []string {
+	sandboxFirewallLogsDir := filepath.Join(runDir, "sandbox", "firewall", "logs")
+	if _, err := os.Stat(sandboxFirewallLogsDir); err == nil {
+		files, _ := filepath.Glob(filepath.Join(sandboxFirewallLogsDir, "*.log"))
+		return files
+	}
+
+	entries, err := os.ReadDir(runDir)
+	if err != nil {
+		return nil
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() && (strings.HasPrefix(name, "squid-logs") || strings.HasPrefix(name, "firewall-logs")) {
+			files, _ := filepath.Glob(filepath.Join(runDir, name, "*.log"))
+			return files
+		}
+	}
+
+	files, _ := filepath.Glob(filepath.Join(runDir, "*.log"))
+	firewallLogs := sliceutil.Filter(files, func(file string) bool {
+		basename := filepath.Base(file)
+		return strings.Contains(basename, "firewall") ||
+			(strings.Contains(basename, "access") && !strings.Contains(basename, "access-"))
+	})
+	if len(firewallLogs) == 0 {
+		return nil
+	}
+	return firewallLogs[:1]
+}
+
 // analyzeFirewallLogs analyzes firewall logs in a run directory
 // Firewall logs are stored in /tmp/gh-aw/squid-logs-{workflow-name}/ during execution
 // and uploaded as artifacts to the logs directory
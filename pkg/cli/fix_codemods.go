@@ -10,7 +10,10 @@ type Codemod struct {
 	Name         string // Human-readable name
 	Description  string // Description of what the codemod does
 	IntroducedIn string // Version where this codemod was introduced
-	Apply        func(content string, frontmatter map[string]any) (string, bool, error)
+	// DeprecatedPaths lists the schema deprecations (see parser.SchemaDeprecation.Path) this
+	// codemod migrates. Deprecations not listed by any codemod get a generated one.
+	DeprecatedPaths []string
+	Apply           func(content string, frontmatter map[string]any) (string, bool, error)
 }
 
 // CodemodResult represents the result of applying a codemod
@@ -71,6 +74,7 @@ func GetAllCodemods() []Codemod {
 		getSandboxMCPVersionRemovalCodemod(),          // Remove deprecated sandbox.mcp.version (now managed internally)
 		getSandboxAgentFalseRemovalCodemod(),          // Remove deprecated sandbox.agent: false (rejected in strict mode)
 	}
+	codemods = append(codemods, getSchemaDeprecationCodemods(codemods)...)
 	fixCodemodsLog.Printf("Loaded codemod registry: %d codemods available", len(codemods))
 	return codemods
 }
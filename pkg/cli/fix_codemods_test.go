@@ -176,5 +176,7 @@ func expectedCodemodOrder() []string {
 		"sandbox-mcp-container-removal",
 		"sandbox-mcp-version-removal",
 		"sandbox-agent-false-removal",
+		"infer-to-disable-model-invocation",
+		"tools-github-toolset-to-toolsets",
 	}
 }
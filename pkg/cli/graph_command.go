@@ -0,0 +1,130 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/constants"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/spf13/cobra"
+)
+
+var graphCommandLog = logger.New("cli:graph_command")
+
+// NewGraphCommand creates the graph command
+func NewGraphCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "graph",
+		Short: "Show and check how workflows trigger each other",
+		Long: `Build the static orchestration graph of all workflows in the repository.
+
+Edges come from dispatch-workflow and call-workflow safe outputs, reusable
+workflow jobs (uses:), workflow_run triggers and dispatch_repository targets,
+including targets in other repositories. The graph is read from compiled
+lock files and plain GitHub Actions workflows, so imported configuration is
+included; compile workflows first.
+
+The graph is checked end to end:
+  - unresolved targets (dispatch or call targets that do not exist, and
+    workflow_run triggers naming unknown workflows)
+  - input contract mismatches: inputs the compiled caller passes that the
+    callee does not declare, and required callee inputs the caller never passes
+  - cycles between workflows
+  - unreachable workers (triggered only by workflow_call, but never called)
+
+Issues are printed to stderr. The command fails when an error-level issue
+(unresolved target or contract mismatch) is found.
+
+Examples:
+  ` + string(constants.CLIExtensionPrefix) + ` graph                  # Mermaid flowchart
+  ` + string(constants.CLIExtensionPrefix) + ` graph --format dot     # Graphviz DOT
+  ` + string(constants.CLIExtensionPrefix) + ` graph --json           # Nodes, edges and issues as JSON`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			workflowDir, _ := cmd.Flags().GetString("dir")
+			format, _ := cmd.Flags().GetString("format")
+			jsonOutput, _ := cmd.Flags().GetBool("json")
+			if jsonOutput {
+				format = "json"
+			}
+			return RunGraph(workflowDir, format)
+		},
+	}
+
+	cmd.Flags().StringP("dir", "d", "", "Workflow directory (default: .github/workflows)")
+	cmd.Flags().String("format", "mermaid", "Output format: mermaid, dot, json")
+	addJSONFlag(cmd)
+	RegisterDirFlagCompletion(cmd, "dir")
+
+	return cmd
+}
+
+// RunGraph builds, checks and prints the orchestration graph
+func RunGraph(workflowDir, format string) error {
+	graphCommandLog.Printf("Running graph: dir=%s, format=%s", workflowDir, format)
+	if workflowDir == "" {
+		workflowDir = getWorkflowsDir()
+	}
+	if _, err := os.Stat(workflowDir); os.IsNotExist(err) {
+		return fmt.Errorf("no %s directory found", workflowDir)
+	}
+
+	graph, err := buildOrchestrationGraph(workflowDir)
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case "json":
+		data, err := json.MarshalIndent(graph, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal graph: %w", err)
+		}
+		fmt.Println(string(data))
+	case "dot":
+		fmt.Print(renderOrchestrationDOT(graph))
+	case "mermaid":
+		fmt.Print(renderOrchestrationMermaid(graph))
+	default:
+		return fmt.Errorf("unsupported format %q: must be mermaid, dot or json", format)
+	}
+
+	if format != "json" {
+		displayOrchestrationIssues(graph)
+	}
+	if graph.hasErrors() {
+		return errors.New("orchestration graph has errors")
+	}
+	return nil
+}
+
+// displayOrchestrationIssues prints graph issues to stderr
+func displayOrchestrationIssues(graph *OrchestrationGraph) {
+	for _, issue := range graph.Issues {
+		message := fmt.Sprintf("%s: [%s] %s", issue.Workflow, issue.Kind, issue.Message)
+		if issue.Severity == graphSeverityError {
+			fmt.Fprintln(os.Stderr, console.FormatErrorMessage(message))
+		} else {
+			fmt.Fprintln(os.Stderr, console.FormatWarningMessage(message))
+		}
+	}
+}
+
+// checkOrchestrationGraph reports orchestration graph issues after compiling all
+// workflows. Issues are reported as warnings and counted in the compiler's
+// warning count; they do not fail compilation.
+func checkOrchestrationGraph(compiler *workflow.Compiler, workflowDir string) {
+	graph, err := buildOrchestrationGraph(workflowDir)
+	if err != nil {
+		graphCommandLog.Printf("Skipping orchestration graph check: %v", err)
+		return
+	}
+	for _, issue := range graph.Issues {
+		fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("%s: [%s] %s", issue.Workflow, issue.Kind, issue.Message)))
+		compiler.IncrementWarningCount()
+	}
+}
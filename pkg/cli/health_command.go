@@ -176,14 +176,17 @@ func RunHealth(config HealthConfig) error {
 func fetchWorkflowRuns(workflowName, startDate, repoOverride string, verbose bool) ([]WorkflowRun, error) {
 	healthLog.Printf("Fetching workflow runs: workflow=%s, startDate=%s", workflowName, startDate)
 
-	opts := ListWorkflowRunsOptions{
+	return fetchAllWorkflowRuns(ListWorkflowRunsOptions{
 		WorkflowName: workflowName,
 		StartDate:    startDate,
 		Limit:        100,
 		RepoOverride: repoOverride,
 		Verbose:      verbose,
-	}
+	})
+}
 
+// fetchAllWorkflowRuns pages through workflow runs matching opts and fills in run durations
+func fetchAllWorkflowRuns(opts ListWorkflowRunsOptions) ([]WorkflowRun, error) {
 	allRuns := make([]WorkflowRun, 0)
 
 	// Fetch runs in batches
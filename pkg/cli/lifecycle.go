@@ -0,0 +1,390 @@
+package cli
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/parser"
+	"github.com/github/gh-aw/pkg/stringutil"
+	"github.com/github/gh-aw/pkg/workflow"
+)
+
+var lifecycleLog = logger.New("cli:lifecycle")
+
+// Lifecycle states of a workflow
+const (
+	lifecycleStateActive      = "active"
+	lifecycleStateStopping    = "stopping"
+	lifecycleStateStopped     = "stopped"
+	lifecycleStateNotCompiled = "not compiled"
+)
+
+// LifecycleReport lists the lifecycle of every workflow in a repository
+type LifecycleReport struct {
+	Repository string              `json:"repository,omitempty"`
+	Workflows  []WorkflowLifecycle `json:"workflows"`
+	Warnings   []string            `json:"warnings,omitempty"`
+}
+
+// WorkflowLifecycle describes when a workflow stops and what it leaves behind
+type WorkflowLifecycle struct {
+	Workflow      string         `json:"workflow"`
+	State         string         `json:"state"`
+	StopAfter     string         `json:"stop_after,omitempty"` // stop-after as written in the frontmatter
+	StopTime      string         `json:"stop_time,omitempty"`  // resolved stop time from the lock file (UTC)
+	TimeRemaining string         `json:"time_remaining,omitempty"`
+	Expires       []string       `json:"expires,omitempty"` // safe outputs with expires, e.g. "create-issue: 7d"
+	LastRun       *LifecycleRun  `json:"last_run,omitempty"`
+	Pending       []ExpiringItem `json:"pending_expirations,omitempty"`
+}
+
+// LifecycleRun is the most recent run of a workflow
+type LifecycleRun struct {
+	ID         int64  `json:"id"`
+	Status     string `json:"status"`
+	Conclusion string `json:"conclusion,omitempty"`
+	CreatedAt  string `json:"created_at"`
+	URL        string `json:"url"`
+}
+
+// ExpiringItem is an open issue, pull request or discussion with an expiration marker
+type ExpiringItem struct {
+	Type      string `json:"type"` // issue, pull_request or discussion
+	Number    int    `json:"number"`
+	Title     string `json:"title"`
+	URL       string `json:"url"`
+	ExpiresAt string `json:"expires_at"`
+	Expired   bool   `json:"expired"`
+
+	workflowID string
+}
+
+var (
+	// expirationMarkerPattern matches the marker written by safe outputs with expires
+	expirationMarkerPattern = regexp.MustCompile(`<!--\s*gh-aw-expires:\s*([^>]+?)\s*-->`)
+	// workflowIDMarkerPattern matches the marker identifying the workflow that created an item
+	workflowIDMarkerPattern = regexp.MustCompile(`<!--\s*gh-aw-workflow-id:\s*([^>]+?)\s*-->`)
+)
+
+// buildLifecycleReport collects the local lifecycle information of every workflow in dir
+func buildLifecycleReport(dir string, now time.Time) (*LifecycleReport, error) {
+	mdFiles, err := getMarkdownWorkflowFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &LifecycleReport{Workflows: make([]WorkflowLifecycle, 0, len(mdFiles))}
+	for _, file := range mdFiles {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", file, err)
+		}
+		result, err := parser.ExtractFrontmatterFromContent(string(content))
+		if err != nil || result.Frontmatter == nil {
+			lifecycleLog.Printf("Skipping %s: no frontmatter", file)
+			continue
+		}
+		// Shared workflows have no triggers and never run on their own
+		if _, ok := result.Frontmatter["on"]; !ok {
+			continue
+		}
+
+		entry := WorkflowLifecycle{
+			Workflow:  normalizeWorkflowID(file),
+			StopAfter: frontmatterStopAfter(result.Frontmatter),
+			Expires:   frontmatterExpires(result.Frontmatter),
+		}
+		lockFile := stringutil.MarkdownToLockFile(file)
+		if _, err := os.Stat(lockFile); err != nil {
+			entry.State = lifecycleStateNotCompiled
+		} else {
+			entry.StopTime = workflow.ExtractStopTimeFromLockFile(lockFile)
+			entry.State, entry.TimeRemaining = classifyStopTime(entry.StopTime, now)
+		}
+		report.Workflows = append(report.Workflows, entry)
+	}
+	return report, nil
+}
+
+// frontmatterStopAfter returns on.stop-after as written in the frontmatter
+func frontmatterStopAfter(frontmatter map[string]any) string {
+	if on, ok := frontmatter["on"].(map[string]any); ok {
+		if stopAfter, ok := on["stop-after"].(string); ok {
+			return stopAfter
+		}
+	}
+	return ""
+}
+
+// frontmatterExpires lists the safe outputs that configure expires
+func frontmatterExpires(frontmatter map[string]any) []string {
+	safeOutputs, ok := frontmatter["safe-outputs"].(map[string]any)
+	if !ok {
+		return nil
+	}
+	var expires []string
+	for name, config := range safeOutputs {
+		if configMap, ok := config.(map[string]any); ok {
+			if value, ok := configMap["expires"]; ok && value != false {
+				expires = append(expires, fmt.Sprintf("%s: %v", name, value))
+			}
+		}
+	}
+	sort.Strings(expires)
+	return expires
+}
+
+// classifyStopTime returns the lifecycle state and the remaining time for a resolved stop time
+func classifyStopTime(stopTime string, now time.Time) (string, string) {
+	if stopTime == "" {
+		return lifecycleStateActive, ""
+	}
+	stop, err := workflow.ParseStopTime(stopTime)
+	if err != nil {
+		return lifecycleStateActive, "Invalid"
+	}
+	remaining := stop.Sub(now)
+	switch {
+	case remaining <= 0:
+		return lifecycleStateStopped, "Expired"
+	case remaining <= workflow.StopTimeWarningWindow:
+		return lifecycleStateStopping, formatRemaining(remaining)
+	default:
+		return lifecycleStateActive, formatRemaining(remaining)
+	}
+}
+
+// formatRemaining formats a positive duration as days and hours, or hours and minutes
+func formatRemaining(remaining time.Duration) string {
+	days := int(remaining.Hours() / 24)
+	hours := int(remaining.Hours()) % 24
+	if days > 0 {
+		return fmt.Sprintf("%dd %dh", days, hours)
+	}
+	return fmt.Sprintf("%dh %dm", hours, int(remaining.Minutes())%60)
+}
+
+// parseExpiringItem extracts the expiration and workflow markers from an item body.
+// It returns false when the body has no expiration marker.
+func parseExpiringItem(itemType string, number int, title, itemURL, body string, now time.Time) (ExpiringItem, bool) {
+	match := expirationMarkerPattern.FindStringSubmatch(body)
+	if match == nil {
+		return ExpiringItem{}, false
+	}
+	item := ExpiringItem{Type: itemType, Number: number, Title: title, URL: itemURL, ExpiresAt: match[1]}
+	if expiresAt, err := time.Parse(time.RFC3339, match[1]); err == nil {
+		item.Expired = !now.Before(expiresAt)
+	}
+	if id := workflowIDMarkerPattern.FindStringSubmatch(body); id != nil {
+		item.workflowID = id[1]
+	}
+	return item, true
+}
+
+// fetchExpiringItems lists open issues, pull requests and discussions with expiration markers
+func fetchExpiringItems(repoSlug string, now time.Time) ([]ExpiringItem, error) {
+	// The issues endpoint also returns pull requests
+	output, err := workflow.RunGH("Fetching open issues and pull requests...", "api", "--paginate",
+		fmt.Sprintf("/repos/%s/issues?state=open&per_page=100", repoSlug),
+		"--jq", `.[] | select((.body // "") | contains("gh-aw-expires")) | {number, title, url: .html_url, body, pr: (.pull_request != null)}`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list issues: %w", err)
+	}
+	var items []ExpiringItem
+	for line := range strings.SplitSeq(strings.TrimSpace(string(output)), "\n") {
+		if line == "" {
+			continue
+		}
+		var issue struct {
+			Number int    `json:"number"`
+			Title  string `json:"title"`
+			URL    string `json:"url"`
+			Body   string `json:"body"`
+			PR     bool   `json:"pr"`
+		}
+		if err := json.Unmarshal([]byte(line), &issue); err != nil {
+			return nil, fmt.Errorf("failed to parse issue: %w", err)
+		}
+		itemType := "issue"
+		if issue.PR {
+			itemType = "pull_request"
+		}
+		if item, ok := parseExpiringItem(itemType, issue.Number, issue.Title, issue.URL, issue.Body, now); ok {
+			items = append(items, item)
+		}
+	}
+
+	owner, name, _ := strings.Cut(repoSlug, "/")
+	const discussionsQuery = `query($owner: String!, $name: String!, $endCursor: String) {
+  repository(owner: $owner, name: $name) {
+    discussions(first: 100, after: $endCursor, states: [OPEN]) {
+      pageInfo { hasNextPage endCursor }
+      nodes { number title url body }
+    }
+  }
+}`
+	output, err = workflow.RunGH("Fetching open discussions...", "api", "graphql", "--paginate",
+		"-f", "query="+discussionsQuery, "-f", "owner="+owner, "-f", "name="+name,
+		"--jq", `.data.repository.discussions.nodes[] | select(.body | contains("gh-aw-expires"))`)
+	if err != nil {
+		// Discussions may be disabled for the repository
+		lifecycleLog.Printf("Failed to list discussions: %v", err)
+		return items, nil
+	}
+	for line := range strings.SplitSeq(strings.TrimSpace(string(output)), "\n") {
+		if line == "" {
+			continue
+		}
+		var discussion struct {
+			Number int    `json:"number"`
+			Title  string `json:"title"`
+			URL    string `json:"url"`
+			Body   string `json:"body"`
+		}
+		if err := json.Unmarshal([]byte(line), &discussion); err != nil {
+			return nil, fmt.Errorf("failed to parse discussion: %w", err)
+		}
+		if item, ok := parseExpiringItem("discussion", discussion.Number, discussion.Title, discussion.URL, discussion.Body, now); ok {
+			items = append(items, item)
+		}
+	}
+	return items, nil
+}
+
+// fetchLastRun returns the most recent run of a compiled workflow, or nil when it never ran
+func fetchLastRun(repoSlug, workflowID string) (*LifecycleRun, error) {
+	lockFile := url.PathEscape(workflowID + ".lock.yml")
+	output, err := workflow.ExecGH("api", fmt.Sprintf("/repos/%s/actions/workflows/%s/runs?per_page=1", repoSlug, lockFile),
+		"--jq", `.workflow_runs[0] // empty | {id, status, conclusion, created_at, url: .html_url}`).Output()
+	if err != nil {
+		return nil, err
+	}
+	if len(strings.TrimSpace(string(output))) == 0 {
+		return nil, nil
+	}
+	var run LifecycleRun
+	if err := json.Unmarshal(output, &run); err != nil {
+		return nil, fmt.Errorf("failed to parse workflow run: %w", err)
+	}
+	return &run, nil
+}
+
+// addRemoteLifecycle adds the last run and the pending expiring items of each workflow
+func addRemoteLifecycle(report *LifecycleReport, repoSlug string, now time.Time) {
+	spinner := console.NewSpinner("Fetching last workflow runs...")
+	spinner.Start()
+	for i := range report.Workflows {
+		entry := &report.Workflows[i]
+		if entry.State == lifecycleStateNotCompiled {
+			continue
+		}
+		run, err := fetchLastRun(repoSlug, entry.Workflow)
+		if err != nil {
+			// The workflow may not be pushed yet
+			lifecycleLog.Printf("Failed to fetch last run of %s: %v", entry.Workflow, err)
+			continue
+		}
+		entry.LastRun = run
+	}
+	spinner.Stop()
+
+	items, err := fetchExpiringItems(repoSlug, now)
+	if err != nil {
+		report.Warnings = append(report.Warnings, fmt.Sprintf("could not list expiring items: %v", err))
+		return
+	}
+	for _, item := range items {
+		index := slices.IndexFunc(report.Workflows, func(w WorkflowLifecycle) bool { return w.Workflow == item.workflowID })
+		if index < 0 {
+			lifecycleLog.Printf("Expiring %s #%d belongs to unknown workflow %q", item.Type, item.Number, item.workflowID)
+			continue
+		}
+		report.Workflows[index].Pending = append(report.Workflows[index].Pending, item)
+	}
+}
+
+// displayLifecycleReport prints the lifecycle table and the warnings to stderr
+func displayLifecycleReport(report *LifecycleReport) {
+	if len(report.Workflows) == 0 {
+		fmt.Fprintln(os.Stderr, console.FormatInfoMessage("No workflow files found."))
+		return
+	}
+
+	rows := make([][]string, 0, len(report.Workflows))
+	for _, entry := range report.Workflows {
+		stopTime := "-"
+		if entry.StopTime != "" {
+			stopTime = entry.StopTime
+			if entry.TimeRemaining != "" && entry.State != lifecycleStateStopped {
+				stopTime += " (" + entry.TimeRemaining + ")"
+			}
+		}
+		lastRun := "-"
+		if entry.LastRun != nil {
+			lastRun = entry.LastRun.CreatedAt
+			if outcome := cmp.Or(entry.LastRun.Conclusion, entry.LastRun.Status); outcome != "" {
+				lastRun += " " + outcome
+			}
+		}
+		expires := "-"
+		if len(entry.Expires) > 0 {
+			expires = strings.Join(entry.Expires, ", ")
+		}
+		pending := "-"
+		if len(entry.Pending) > 0 {
+			expired := 0
+			for _, item := range entry.Pending {
+				if item.Expired {
+					expired++
+				}
+			}
+			pending = fmt.Sprintf("%d (%d overdue)", len(entry.Pending), expired)
+		}
+		rows = append(rows, []string{entry.Workflow, entry.State, stopTime, lastRun, expires, pending})
+	}
+	fmt.Fprint(os.Stderr, console.RenderTable(console.TableConfig{
+		Title:   "Workflow Lifecycle",
+		Headers: []string{"WORKFLOW", "STATE", "STOP TIME (UTC)", "LAST RUN", "EXPIRES", "PENDING"},
+		Rows:    rows,
+	}))
+
+	for _, entry := range report.Workflows {
+		switch entry.State {
+		case lifecycleStateStopped:
+			fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("%s stopped at %s UTC", entry.Workflow, entry.StopTime)))
+		case lifecycleStateStopping:
+			fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("%s stops in %s", entry.Workflow, entry.TimeRemaining)))
+		}
+		for _, item := range entry.Pending {
+			if item.Expired {
+				fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("%s: %s #%d expired at %s but is still open (is the maintenance workflow running?)", entry.Workflow, item.Type, item.Number, item.ExpiresAt)))
+			}
+		}
+	}
+	for _, warning := range report.Warnings {
+		fmt.Fprintln(os.Stderr, console.FormatWarningMessage(warning))
+	}
+}
+
+// findWorkflowFile resolves a workflow ID to its markdown file in dir
+func findWorkflowFile(dir, workflowID string) (string, error) {
+	if dir == "" {
+		dir = getWorkflowsDir()
+	}
+	file := filepath.Join(dir, normalizeWorkflowID(workflowID)+".md")
+	if _, err := os.Stat(file); err != nil {
+		return "", fmt.Errorf("workflow %q not found in %s", workflowID, dir)
+	}
+	return file, nil
+}
@@ -0,0 +1,233 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/constants"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/stringutil"
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/spf13/cobra"
+)
+
+var lifecycleCommandLog = logger.New("cli:lifecycle_command")
+
+// defaultLifecycleExtension is the stop-after value set by lifecycle extend
+const defaultLifecycleExtension = "+30d"
+
+// NewLifecycleCommand creates the lifecycle command
+func NewLifecycleCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "lifecycle",
+		Short: "Show when workflows stop and which of their outputs expire",
+		Long: `List the lifecycle of every workflow in the repository:
+
+  - the stop time resolved from on.stop-after and the time remaining
+  - the last run of the workflow
+  - the safe outputs configured with expires
+  - open issues, pull requests and discussions with a pending expiration,
+    including items that are past their expiration but still open
+
+Workflows are "stopping" when their stop time is less than 7 days away and
+"stopped" once it has passed. Stopped workflows still trigger, but skip the
+agent. Use the extend subcommand to move the stop time, or retire to remove
+a workflow that is no longer needed.
+
+Remote information (last runs and pending items) requires GitHub CLI
+authentication and is skipped when the repository cannot be detected.
+
+Examples:
+  ` + string(constants.CLIExtensionPrefix) + ` lifecycle                              # Show all workflows
+  ` + string(constants.CLIExtensionPrefix) + ` lifecycle --json                       # Output as JSON
+  ` + string(constants.CLIExtensionPrefix) + ` lifecycle extend daily-report          # Stop 30 days from now
+  ` + string(constants.CLIExtensionPrefix) + ` lifecycle extend daily-report --stop-after +3mo
+  ` + string(constants.CLIExtensionPrefix) + ` lifecycle retire daily-report          # Remove the workflow`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir, _ := cmd.Flags().GetString("dir")
+			repo, _ := cmd.Flags().GetString("repo")
+			jsonOutput, _ := cmd.Flags().GetBool("json")
+			return RunLifecycle(dir, repo, jsonOutput)
+		},
+	}
+
+	cmd.Flags().StringP("dir", "d", "", "Workflow directory (default: .github/workflows)")
+	addRepoFlag(cmd)
+	addJSONFlag(cmd)
+	RegisterDirFlagCompletion(cmd, "dir")
+
+	cmd.AddCommand(newLifecycleExtendSubcommand())
+	cmd.AddCommand(newLifecycleRetireSubcommand())
+
+	return cmd
+}
+
+// newLifecycleExtendSubcommand creates the lifecycle extend subcommand
+func newLifecycleExtendSubcommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "extend <workflow>",
+		Short: "Move a workflow's stop time and recompile it",
+		Long: `Set on.stop-after in the workflow frontmatter and recompile the workflow.
+
+Relative values are resolved from the time of compilation, so the default
+"+30d" lets the workflow run for another 30 days.
+
+Examples:
+  ` + string(constants.CLIExtensionPrefix) + ` lifecycle extend daily-report
+  ` + string(constants.CLIExtensionPrefix) + ` lifecycle extend daily-report --stop-after "2026-12-31 23:59:59"`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir, _ := cmd.Flags().GetString("dir")
+			stopAfter, _ := cmd.Flags().GetString("stop-after")
+			verbose, _ := cmd.Flags().GetBool("verbose")
+			return ExtendWorkflow(dir, args[0], stopAfter, verbose)
+		},
+	}
+
+	cmd.Flags().StringP("dir", "d", "", "Workflow directory (default: .github/workflows)")
+	cmd.Flags().String("stop-after", defaultLifecycleExtension, "New stop-after value (e.g., '+30d', '+3mo', '2026-12-31 23:59:59')")
+	cmd.ValidArgsFunction = CompleteWorkflowNames
+	RegisterDirFlagCompletion(cmd, "dir")
+
+	return cmd
+}
+
+// newLifecycleRetireSubcommand creates the lifecycle retire subcommand
+func newLifecycleRetireSubcommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "retire <workflow>",
+		Short: "Remove a workflow and its lock file",
+		Long: `Remove a workflow's markdown and compiled lock file, along with include
+files that no other workflow uses, and stage the removal in git.
+
+Open items created with expires are left in place; the maintenance workflow
+closes them when they expire.
+
+Examples:
+  ` + string(constants.CLIExtensionPrefix) + ` lifecycle retire daily-report
+  ` + string(constants.CLIExtensionPrefix) + ` lifecycle retire daily-report --yes`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir, _ := cmd.Flags().GetString("dir")
+			yes, _ := cmd.Flags().GetBool("yes")
+			return RetireWorkflow(dir, args[0], yes)
+		},
+	}
+
+	cmd.Flags().StringP("dir", "d", "", "Workflow directory (default: .github/workflows)")
+	cmd.Flags().BoolP("yes", "y", false, "Skip the confirmation prompt")
+	cmd.ValidArgsFunction = CompleteWorkflowNames
+	RegisterDirFlagCompletion(cmd, "dir")
+
+	return cmd
+}
+
+// RunLifecycle builds and prints the lifecycle report
+func RunLifecycle(dir, repo string, jsonOutput bool) error {
+	lifecycleCommandLog.Printf("Running lifecycle: dir=%s, repo=%s", dir, repo)
+	now := time.Now().UTC()
+	report, err := buildLifecycleReport(dir, now)
+	if err != nil {
+		return err
+	}
+
+	report.Repository = repo
+	if report.Repository == "" {
+		if report.Repository, err = GetCurrentRepoSlug(); err != nil {
+			lifecycleCommandLog.Printf("Could not detect repository: %v", err)
+			report.Warnings = append(report.Warnings, "could not detect the repository; last runs and pending expirations are not shown")
+		}
+	}
+	if report.Repository != "" {
+		addRemoteLifecycle(report, report.Repository, now)
+	}
+
+	if jsonOutput {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal lifecycle report: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+	displayLifecycleReport(report)
+	return nil
+}
+
+// ExtendWorkflow sets on.stop-after in a workflow and recompiles it with a fresh stop time
+func ExtendWorkflow(dir, workflowID, stopAfter string, verbose bool) error {
+	lifecycleCommandLog.Printf("Extending workflow %s: stop-after=%s", workflowID, stopAfter)
+	file, err := findWorkflowFile(dir, workflowID)
+	if err != nil {
+		return err
+	}
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", file, err)
+	}
+	updated, err := SetFieldInOnTrigger(string(content), "stop-after", stopAfter)
+	if err != nil {
+		return fmt.Errorf("failed to set stop-after in %s: %w", file, err)
+	}
+	if err := os.WriteFile(file, []byte(updated), constants.FilePermPublic); err != nil {
+		return fmt.Errorf("failed to write %s: %w", file, err)
+	}
+
+	// Relative stop-after values are resolved again instead of keeping the old lock file value
+	if err := compileWorkflowWithRefresh(file, verbose, false, "", true); err != nil {
+		return err
+	}
+	newStopTime := "the new stop time"
+	if stopTime := workflow.ExtractStopTimeFromLockFile(stringutil.MarkdownToLockFile(file)); stopTime != "" {
+		newStopTime = stopTime + " UTC"
+	}
+	fmt.Fprintln(os.Stderr, console.FormatSuccessMessage(fmt.Sprintf("%s now stops at %s", normalizeWorkflowID(file), newStopTime)))
+	return nil
+}
+
+// RetireWorkflow removes a workflow, its lock file and its orphaned includes
+func RetireWorkflow(dir, workflowID string, skipConfirmation bool) error {
+	lifecycleCommandLog.Printf("Retiring workflow %s", workflowID)
+	file, err := findWorkflowFile(dir, workflowID)
+	if err != nil {
+		return err
+	}
+	lockFile := stringutil.MarkdownToLockFile(file)
+
+	if !skipConfirmation {
+		confirmed, err := console.ConfirmAction(
+			fmt.Sprintf("Remove %s and its lock file?", normalizeWorkflowID(file)),
+			"Yes, retire",
+			"No, cancel",
+		)
+		if err != nil {
+			return fmt.Errorf("failed to get confirmation: %w", err)
+		}
+		if !confirmed {
+			fmt.Fprintln(os.Stderr, console.FormatInfoMessage("Operation cancelled."))
+			return nil
+		}
+	}
+
+	for _, path := range []string{file, lockFile} {
+		if err := os.Remove(path); err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return fmt.Errorf("failed to remove %s: %w", path, err)
+		}
+		fmt.Fprintln(os.Stderr, console.FormatSuccessMessage("Removed: "+console.ToRelativePath(path)))
+	}
+
+	if err := cleanupOrphanedIncludes(dir, false); err != nil {
+		fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to clean up orphaned includes: %v", err)))
+	}
+	if isGitRepo() {
+		stageWorkflowChanges()
+	}
+	return nil
+}
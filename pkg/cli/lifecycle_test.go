@@ -0,0 +1,107 @@
+//go:build !integration
+
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClassifyStopTime(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		stopTime  string
+		state     string
+		remaining string
+	}{
+		{name: "no stop time", stopTime: "", state: lifecycleStateActive, remaining: ""},
+		{name: "passed", stopTime: "2026-02-01 00:00:00", state: lifecycleStateStopped, remaining: "Expired"},
+		{name: "within a week", stopTime: "2026-03-03 15:30:00", state: lifecycleStateStopping, remaining: "2d 3h"},
+		{name: "within a day", stopTime: "2026-03-01 14:45:00", state: lifecycleStateStopping, remaining: "2h 45m"},
+		{name: "far away", stopTime: "2026-06-01 12:00:00", state: lifecycleStateActive, remaining: "92d 0h"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state, remaining := classifyStopTime(tt.stopTime, now)
+			assert.Equal(t, tt.state, state, "state should match")
+			assert.Equal(t, tt.remaining, remaining, "time remaining should match")
+		})
+	}
+}
+
+func TestParseExpiringItem(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	body := "Report\n\n> AI generated by Daily Report\n>\n> - [x] expires <!-- gh-aw-expires: 2026-02-20T09:00:00.000Z --> on Feb 20, 2026 UTC\n\n<!-- gh-aw-workflow-id: daily-report -->"
+
+	item, ok := parseExpiringItem("issue", 42, "Report", "https://github.com/o/r/issues/42", body, now)
+	require.True(t, ok, "body with an expiration marker should be parsed")
+	assert.Equal(t, "2026-02-20T09:00:00.000Z", item.ExpiresAt, "expiration should be extracted")
+	assert.True(t, item.Expired, "past expiration should be flagged")
+	assert.Equal(t, "daily-report", item.workflowID, "workflow ID marker should be extracted")
+
+	_, ok = parseExpiringItem("issue", 43, "Bug", "https://github.com/o/r/issues/43", "A regular issue", now)
+	assert.False(t, ok, "body without an expiration marker should be ignored")
+}
+
+func TestBuildLifecycleReport(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"daily-report.md":       "---\non:\n  schedule: daily\n  stop-after: +30d\nsafe-outputs:\n  create-issue:\n    expires: 7d\n  add-comment:\n---\nReport.\n",
+		"daily-report.lock.yml": "jobs:\n  pre_activation:\n    steps:\n      - env:\n          GH_AW_STOP_TIME: \"2026-03-03 12:00:00\"\n",
+		"triage.md":             "---\non: issues\n---\nTriage.\n",
+		"shared.md":             "---\ntools:\n  github:\n---\nShared.\n",
+	}
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600), "failed to write %s", name)
+	}
+
+	report, err := buildLifecycleReport(dir, time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC))
+	require.NoError(t, err, "report should build")
+	require.Len(t, report.Workflows, 2, "shared workflows should be skipped")
+
+	daily := report.Workflows[0]
+	assert.Equal(t, "daily-report", daily.Workflow)
+	assert.Equal(t, lifecycleStateStopping, daily.State, "stop time two days away should be stopping")
+	assert.Equal(t, "+30d", daily.StopAfter, "frontmatter stop-after should be reported")
+	assert.Equal(t, "2026-03-03 12:00:00", daily.StopTime, "resolved stop time should come from the lock file")
+	assert.Equal(t, []string{"create-issue: 7d"}, daily.Expires, "safe outputs with expires should be listed")
+
+	assert.Equal(t, lifecycleStateNotCompiled, report.Workflows[1].State, "workflow without lock file should be not compiled")
+}
+
+func TestRetireWorkflow_CustomDir(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Chdir(tmpDir)
+
+	// The default workflows directory has its own include that must be left alone
+	defaultShared := filepath.Join(".github", "workflows", "shared")
+	require.NoError(t, os.MkdirAll(defaultShared, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(defaultShared, "default.md"), []byte("# Default include\n"), 0o644))
+
+	dir := filepath.Join(tmpDir, "custom")
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "shared"), 0o755))
+	files := map[string]string{
+		"old.md":         "---\non: push\n---\n@include shared/old.md\n",
+		"old.lock.yml":   "name: old\n",
+		"keep.md":        "---\non: push\n---\n@include shared/used.md\n",
+		"shared/old.md":  "# Old include\n",
+		"shared/used.md": "# Used include\n",
+	}
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+	}
+
+	require.NoError(t, RetireWorkflow(dir, "old", true))
+
+	assert.NoFileExists(t, filepath.Join(dir, "old.md"), "workflow should be removed")
+	assert.NoFileExists(t, filepath.Join(dir, "old.lock.yml"), "lock file should be removed")
+	assert.NoFileExists(t, filepath.Join(dir, "shared", "old.md"), "orphaned include in --dir should be removed")
+	assert.FileExists(t, filepath.Join(dir, "shared", "used.md"), "include used by another workflow in --dir should be kept")
+	assert.FileExists(t, filepath.Join(defaultShared, "default.md"), "includes outside --dir should not be touched")
+}
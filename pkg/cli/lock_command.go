@@ -0,0 +1,294 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/constants"
+	"github.com/github/gh-aw/pkg/gitutil"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/stringutil"
+	"github.com/spf13/cobra"
+)
+
+var lockCommandLog = logger.New("cli:lock_command")
+
+// workingTreeRef is the display name used when the head side of a diff is the working tree
+const workingTreeRef = "working tree"
+
+// maxLockValueLength keeps long values (such as trigger or safe output configuration) readable in a table cell
+const maxLockValueLength = 120
+
+// LockDiffReport is the JSON output of the lock diff command
+type LockDiffReport struct {
+	Base      string         `json:"base"`
+	Head      string         `json:"head"`
+	Workflows []LockFileDiff `json:"workflows"`
+	Risk      map[string]int `json:"risk"`
+}
+
+// NewLockCommand creates the lock command with subcommands for working with compiled lock files
+func NewLockCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "lock",
+		Short: "Inspect compiled workflow lock files",
+		Long: `Inspect compiled workflow lock files (.lock.yml).
+
+Available subcommands:
+  - diff - Semantic diff of lock files between two git refs
+
+Examples:
+  ` + string(constants.CLIExtensionPrefix) + ` lock diff
+  ` + string(constants.CLIExtensionPrefix) + ` lock diff origin/main HEAD`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(NewLockDiffSubcommand())
+
+	return cmd
+}
+
+// NewLockDiffSubcommand creates the lock diff subcommand
+func NewLockDiffSubcommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "diff [base-ref] [head-ref]",
+		Short: "Semantic diff of compiled lock files between two git refs",
+		Long: `Compare compiled workflows semantically rather than textually.
+
+Reports changes to triggers and job if: conditions, permissions, pinned actions
+and containers, allowed network domains, referenced secrets, safe outputs and
+tools, and classifies each change as high, medium or low risk.
+
+The base ref defaults to HEAD. When no head ref is given, the working tree is
+compared, so running the command without arguments reviews uncommitted changes.
+
+The report is markdown suitable for a pull request comment; use --json for
+machine-readable output.
+
+Examples:
+  ` + string(constants.CLIExtensionPrefix) + ` lock diff                       # HEAD vs working tree
+  ` + string(constants.CLIExtensionPrefix) + ` lock diff origin/main           # origin/main vs working tree
+  ` + string(constants.CLIExtensionPrefix) + ` lock diff origin/main HEAD      # Review a pull request branch
+  ` + string(constants.CLIExtensionPrefix) + ` lock diff origin/main HEAD --json`,
+		Args: cobra.MaximumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			workflowDir, _ := cmd.Flags().GetString("dir")
+			jsonOutput, _ := cmd.Flags().GetBool("json")
+
+			baseRef := "HEAD"
+			headRef := ""
+			if len(args) > 0 {
+				baseRef = args[0]
+			}
+			if len(args) > 1 {
+				headRef = args[1]
+			}
+			return RunLockDiff(baseRef, headRef, workflowDir, jsonOutput)
+		},
+	}
+
+	cmd.Flags().StringP("dir", "d", "", "Workflow directory (default: .github/workflows)")
+	addJSONFlag(cmd)
+	RegisterDirFlagCompletion(cmd, "dir")
+
+	return cmd
+}
+
+// RunLockDiff compares the lock files of two git refs and prints the report.
+// An empty headRef compares against the working tree.
+func RunLockDiff(baseRef, headRef, workflowDir string, jsonOutput bool) error {
+	lockCommandLog.Printf("Running lock diff: base=%s, head=%s, dir=%s", baseRef, headRef, workflowDir)
+
+	gitRoot, err := gitutil.FindGitRoot()
+	if err != nil {
+		return fmt.Errorf("lock diff must be run inside a git repository: %w", err)
+	}
+	if workflowDir == "" {
+		workflowDir = constants.GetWorkflowDir()
+	}
+	workflowDir = path.Clean(filepath.ToSlash(workflowDir))
+
+	report, err := buildLockDiffReport(gitRoot, baseRef, headRef, workflowDir)
+	if err != nil {
+		return err
+	}
+
+	if jsonOutput {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal lock diff: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if len(report.Workflows) == 0 {
+		fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("No lock file changes between %s and %s", report.Base, report.Head)))
+	}
+	fmt.Print(renderLockDiffMarkdown(report))
+	return nil
+}
+
+// buildLockDiffReport summarizes and compares every lock file that differs between the two refs
+func buildLockDiffReport(gitRoot, baseRef, headRef, workflowDir string) (*LockDiffReport, error) {
+	baseFiles, err := readLockFilesAtRef(gitRoot, baseRef, workflowDir)
+	if err != nil {
+		return nil, err
+	}
+	headFiles, err := readLockFilesAtRef(gitRoot, headRef, workflowDir)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &LockDiffReport{
+		Base:      baseRef,
+		Head:      headRef,
+		Workflows: []LockFileDiff{},
+		Risk:      map[string]int{lockRiskHigh: 0, lockRiskMedium: 0, lockRiskLow: 0},
+	}
+	if report.Head == "" {
+		report.Head = workingTreeRef
+	}
+
+	for _, lockFile := range unionLockKeys(baseFiles, headFiles) {
+		baseContent, inBase := baseFiles[lockFile]
+		headContent, inHead := headFiles[lockFile]
+		if inBase && inHead && baseContent == headContent {
+			continue
+		}
+
+		var before, after *LockFileSummary
+		status := lockChangeChanged
+		if inBase {
+			if before, err = summarizeLockFile(baseContent, lockFile); err != nil {
+				return nil, err
+			}
+		} else {
+			status = lockChangeAdded
+		}
+		if inHead {
+			if after, err = summarizeLockFile(headContent, lockFile); err != nil {
+				return nil, err
+			}
+		} else {
+			status = lockChangeRemoved
+		}
+
+		changes := diffLockSummaries(before, after)
+		if len(changes) == 0 {
+			lockCommandLog.Printf("No semantic changes in %s", lockFile)
+			continue
+		}
+		sort.SliceStable(changes, func(i, j int) bool {
+			return lockRiskRank(changes[i].Risk) > lockRiskRank(changes[j].Risk)
+		})
+		for _, change := range changes {
+			report.Risk[change.Risk]++
+		}
+		report.Workflows = append(report.Workflows, LockFileDiff{LockFile: lockFile, Status: status, Changes: changes})
+	}
+
+	lockCommandLog.Printf("Lock diff complete: workflows=%d, high=%d, medium=%d, low=%d",
+		len(report.Workflows), report.Risk[lockRiskHigh], report.Risk[lockRiskMedium], report.Risk[lockRiskLow])
+	return report, nil
+}
+
+// readLockFilesAtRef returns the lock files directly inside workflowDir at a git ref,
+// keyed by repository-relative path. An empty ref reads the working tree.
+func readLockFilesAtRef(gitRoot, ref, workflowDir string) (map[string]string, error) {
+	files := make(map[string]string)
+
+	if ref == "" {
+		matches, err := filepath.Glob(filepath.Join(gitRoot, filepath.FromSlash(workflowDir), "*.lock.yml"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to list lock files: %w", err)
+		}
+		for _, match := range matches {
+			content, err := os.ReadFile(match)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read lock file: %w", err)
+			}
+			files[path.Join(workflowDir, filepath.Base(match))] = string(content)
+		}
+		return files, nil
+	}
+
+	output, err := exec.Command("git", "-C", gitRoot, "ls-tree", "-r", "--name-only", ref, "--", workflowDir).Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return nil, fmt.Errorf("failed to list lock files at %s: %s", ref, strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return nil, fmt.Errorf("failed to list lock files at %s: %w", ref, err)
+	}
+	for name := range strings.SplitSeq(strings.TrimSpace(string(output)), "\n") {
+		if !strings.HasSuffix(name, ".lock.yml") || path.Dir(name) != workflowDir {
+			continue
+		}
+		content, err := exec.Command("git", "-C", gitRoot, "show", ref+":"+name).Output()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s at %s: %w", name, ref, err)
+		}
+		files[name] = string(content)
+	}
+	lockCommandLog.Printf("Read %d lock file(s) at %s", len(files), ref)
+	return files, nil
+}
+
+// renderLockDiffMarkdown renders the report as markdown suitable for a pull request comment
+func renderLockDiffMarkdown(report *LockDiffReport) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "## Lock file changes: `%s` → `%s`\n\n", report.Base, report.Head)
+
+	if len(report.Workflows) == 0 {
+		sb.WriteString("No semantic changes to compiled workflows.\n")
+		return sb.String()
+	}
+
+	fmt.Fprintf(&sb, "**Risk:** %d high, %d medium, %d low across %d workflow(s)\n",
+		report.Risk[lockRiskHigh], report.Risk[lockRiskMedium], report.Risk[lockRiskLow], len(report.Workflows))
+
+	for _, workflowDiff := range report.Workflows {
+		fmt.Fprintf(&sb, "\n### `%s` (%s)\n\n", workflowDiff.LockFile, workflowDiff.Status)
+		sb.WriteString("| Risk | Category | Change |\n")
+		sb.WriteString("|------|----------|--------|\n")
+		for _, change := range workflowDiff.Changes {
+			fmt.Fprintf(&sb, "| %s | %s | %s |\n", change.Risk, change.Category, escapeMarkdownTableCell(describeLockChange(change)))
+		}
+	}
+	return sb.String()
+}
+
+// describeLockChange renders a single change as a short sentence
+func describeLockChange(change LockDiffChange) string {
+	if change.Category == lockCategoryWorkflow {
+		return change.Subject
+	}
+	subject := "`" + change.Subject + "`"
+	switch change.Kind {
+	case lockChangeAdded:
+		if change.After != "" {
+			return fmt.Sprintf("%s added: `%s`", subject, stringutil.Truncate(change.After, maxLockValueLength))
+		}
+		return subject + " added"
+	case lockChangeRemoved:
+		return subject + " removed"
+	default:
+		return fmt.Sprintf("%s changed: `%s` → `%s`", subject, stringutil.Truncate(change.Before, maxLockValueLength), stringutil.Truncate(change.After, maxLockValueLength))
+	}
+}
+
+func escapeMarkdownTableCell(value string) string {
+	value = strings.ReplaceAll(value, "|", "\\|")
+	return strings.ReplaceAll(value, "\n", " ")
+}
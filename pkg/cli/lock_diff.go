@@ -0,0 +1,474 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/goccy/go-yaml"
+)
+
+var lockDiffLog = logger.New("cli:lock_diff")
+
+// Risk levels assigned to each semantic lock file change
+const (
+	lockRiskHigh   = "high"
+	lockRiskMedium = "medium"
+	lockRiskLow    = "low"
+)
+
+// Categories of semantic lock file changes, in report order
+const (
+	lockCategoryWorkflow    = "workflow"
+	lockCategoryTriggers    = "triggers"
+	lockCategoryConditions  = "conditions"
+	lockCategoryPermissions = "permissions"
+	lockCategorySecrets     = "secrets"
+	lockCategoryNetwork     = "network"
+	lockCategoryActions     = "actions"
+	lockCategoryContainers  = "containers"
+	lockCategorySafeOutputs = "safe-outputs"
+	lockCategoryTools       = "tools"
+)
+
+// Kinds of semantic lock file changes
+const (
+	lockChangeAdded   = "added"
+	lockChangeRemoved = "removed"
+	lockChangeChanged = "changed"
+)
+
+// workflowLevelKey is the pseudo job name used for workflow-level permissions
+const workflowLevelKey = "(workflow)"
+
+// highRiskTriggers run with elevated privileges or on untrusted input
+var highRiskTriggers = map[string]bool{
+	"pull_request_target": true,
+	"workflow_run":        true,
+	"issue_comment":       true,
+	"discussion_comment":  true,
+	"repository_dispatch": true,
+}
+
+var (
+	lockAllowedDomainsPattern   = regexp.MustCompile(`GH_AW_ALLOWED_DOMAINS:\s*"([^"]*)"`)
+	lockSecretPattern           = regexp.MustCompile(`secrets\.([A-Za-z_][A-Za-z0-9_]*)`)
+	lockSafeOutputsConfigRegexp = regexp.MustCompile(`safeoutputs/config\.json" << '[A-Za-z0-9_]+'\n\s*(\{.*\})`)
+	lockAllowToolPattern        = regexp.MustCompile(`(?m)^\s*#\s*--allow-tool\s+(.+?)\s*$`)
+	lockAllowedToolsPattern     = regexp.MustCompile(`--allowed-tools\s+(?:'\\'')?([^'\s]+)`)
+	lockMCPServerPattern        = regexp.MustCompile(`^\s*"([^"]+)":\s*\{`)
+)
+
+// LockFileSummary is the security-relevant content of a compiled workflow,
+// normalised so that two versions can be compared semantically
+type LockFileSummary struct {
+	Triggers    map[string]string            // event name → canonical JSON of its configuration
+	Conditions  map[string]string            // job name → if: expression
+	Permissions map[string]map[string]string // job name (or workflowLevelKey) → scope → level
+	Actions     map[string]string            // action repo → "sha (version)"
+	Containers  map[string]string            // image → digest (empty when not pinned)
+	Domains     map[string]bool              // allowed network domains
+	Secrets     map[string]bool              // secret names
+	SafeOutputs map[string]string            // safe output type → canonical JSON of its configuration
+	Tools       map[string]bool              // allowed engine tools and MCP servers ("mcp:<name>")
+}
+
+// LockDiffChange is a single semantic difference between two versions of a lock file
+type LockDiffChange struct {
+	Category string `json:"category"`
+	Kind     string `json:"kind"`
+	Subject  string `json:"subject"`
+	Before   string `json:"before,omitempty"`
+	After    string `json:"after,omitempty"`
+	Risk     string `json:"risk"`
+}
+
+// LockFileDiff collects the semantic changes of a single lock file
+type LockFileDiff struct {
+	LockFile string           `json:"lock_file"`
+	Status   string           `json:"status"` // "added", "removed" or "changed"
+	Changes  []LockDiffChange `json:"changes"`
+}
+
+// summarizeLockFile extracts the security-relevant content of a compiled workflow
+func summarizeLockFile(content, lockFilePath string) (*LockFileSummary, error) {
+	var parsed map[string]any
+	if err := yaml.Unmarshal([]byte(content), &parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", lockFilePath, err)
+	}
+
+	summary := &LockFileSummary{
+		Triggers:    make(map[string]string),
+		Conditions:  make(map[string]string),
+		Permissions: make(map[string]map[string]string),
+		Actions:     make(map[string]string),
+		Containers:  make(map[string]string),
+		Domains:     make(map[string]bool),
+		Secrets:     make(map[string]bool),
+		SafeOutputs: make(map[string]string),
+		Tools:       make(map[string]bool),
+	}
+
+	switch on := parsed["on"].(type) {
+	case string:
+		summary.Triggers[on] = ""
+	case []any:
+		for _, event := range on {
+			summary.Triggers[fmt.Sprint(event)] = ""
+		}
+	case map[string]any:
+		for event, config := range on {
+			summary.Triggers[event] = canonicalLockValue(config)
+		}
+	}
+
+	if perms, ok := parsed["permissions"]; ok {
+		summary.Permissions[workflowLevelKey] = normalizeLockPermissions(perms)
+	}
+	jobs, _ := parsed["jobs"].(map[string]any)
+	for jobName, rawJob := range jobs {
+		job, ok := rawJob.(map[string]any)
+		if !ok {
+			continue
+		}
+		if condition, ok := job["if"]; ok {
+			summary.Conditions[jobName] = strings.TrimSpace(fmt.Sprint(condition))
+		}
+		if perms, ok := job["permissions"]; ok {
+			summary.Permissions[jobName] = normalizeLockPermissions(perms)
+		}
+	}
+
+	manifest, err := workflow.ExtractGHAWManifestFromLockFile(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest from %s: %w", lockFilePath, err)
+	}
+	if manifest != nil {
+		for _, action := range manifest.Actions {
+			summary.Actions[action.Repo] = formatLockActionRef(action.SHA, action.Version)
+		}
+		for _, container := range manifest.Containers {
+			summary.Containers[container.Image] = container.Digest
+		}
+		for _, secret := range manifest.Secrets {
+			summary.Secrets[secret] = true
+		}
+	} else {
+		// Lock files compiled before the manifest header existed: fall back to scanning the content
+		lockDiffLog.Printf("No gh-aw-manifest in %s, scanning content for actions and secrets", lockFilePath)
+		actions, err := workflow.ExtractActionsFromLockContent(content, lockFilePath)
+		if err != nil {
+			return nil, err
+		}
+		for _, action := range actions {
+			summary.Actions[action.Repo] = formatLockActionRef(action.SHA, action.Version)
+		}
+		for _, match := range lockSecretPattern.FindAllStringSubmatch(content, -1) {
+			summary.Secrets[match[1]] = true
+		}
+	}
+
+	for _, match := range lockAllowedDomainsPattern.FindAllStringSubmatch(content, -1) {
+		for domain := range strings.SplitSeq(match[1], ",") {
+			if domain = strings.TrimSpace(domain); domain != "" {
+				summary.Domains[domain] = true
+			}
+		}
+	}
+
+	if match := lockSafeOutputsConfigRegexp.FindStringSubmatch(content); match != nil {
+		var config map[string]any
+		if err := json.Unmarshal([]byte(match[1]), &config); err != nil {
+			lockDiffLog.Printf("Failed to parse safe outputs config in %s: %v", lockFilePath, err)
+		}
+		for outputType, outputConfig := range config {
+			summary.SafeOutputs[strings.ReplaceAll(outputType, "_", "-")] = canonicalLockValue(outputConfig)
+		}
+	}
+
+	for _, match := range lockAllowToolPattern.FindAllStringSubmatch(content, -1) {
+		summary.Tools[match[1]] = true
+	}
+	for _, match := range lockAllowedToolsPattern.FindAllStringSubmatch(content, -1) {
+		for tool := range strings.SplitSeq(match[1], ",") {
+			if tool = strings.TrimSpace(tool); tool != "" {
+				summary.Tools[tool] = true
+			}
+		}
+	}
+	for _, server := range extractLockMCPServers(content) {
+		summary.Tools["mcp:"+server] = true
+	}
+
+	lockDiffLog.Printf("Summarized %s: triggers=%d, jobs=%d, actions=%d, domains=%d, secrets=%d, safe_outputs=%d, tools=%d",
+		lockFilePath, len(summary.Triggers), len(jobs), len(summary.Actions), len(summary.Domains), len(summary.Secrets), len(summary.SafeOutputs), len(summary.Tools))
+	return summary, nil
+}
+
+// normalizeLockPermissions flattens a permissions block into scope → level.
+// Shorthand forms such as "read-all" are recorded under the "*" scope.
+func normalizeLockPermissions(perms any) map[string]string {
+	result := make(map[string]string)
+	switch p := perms.(type) {
+	case string:
+		result["*"] = strings.TrimSuffix(p, "-all")
+	case map[string]any:
+		for scope, level := range p {
+			result[scope] = fmt.Sprint(level)
+		}
+	}
+	return result
+}
+
+// extractLockMCPServers returns the names of the MCP servers configured in the
+// engine's mcpServers blocks, tracking brace depth to find the top-level keys
+func extractLockMCPServers(content string) []string {
+	seen := make(map[string]bool)
+	depth := -1
+	for line := range strings.SplitSeq(content, "\n") {
+		if depth < 0 {
+			if strings.Contains(line, `"mcpServers": {`) {
+				depth = 0
+			}
+			continue
+		}
+		if depth == 0 {
+			if match := lockMCPServerPattern.FindStringSubmatch(line); match != nil {
+				seen[match[1]] = true
+			}
+		}
+		depth += strings.Count(line, "{") - strings.Count(line, "}")
+		if depth < 0 {
+			depth = -1
+		}
+	}
+	return sortedLockKeys(seen)
+}
+
+// canonicalLockValue encodes a parsed YAML value as JSON with sorted keys
+func canonicalLockValue(v any) string {
+	if v == nil {
+		return ""
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
+
+func formatLockActionRef(sha, version string) string {
+	if version == "" {
+		return sha
+	}
+	return sha + " (" + version + ")"
+}
+
+// diffLockSummaries compares two lock file summaries. A nil before or after
+// summary means the workflow was added or removed.
+func diffLockSummaries(before, after *LockFileSummary) []LockDiffChange {
+	if after == nil {
+		return []LockDiffChange{{Category: lockCategoryWorkflow, Kind: lockChangeRemoved, Subject: "workflow removed", Risk: lockRiskLow}}
+	}
+
+	var changes []LockDiffChange
+	if before == nil {
+		changes = append(changes, LockDiffChange{Category: lockCategoryWorkflow, Kind: lockChangeAdded, Subject: "new workflow", Risk: lockRiskMedium})
+		before = &LockFileSummary{}
+	}
+
+	changes = append(changes, diffLockStringMaps(lockCategoryTriggers, before.Triggers, after.Triggers, triggerChangeRisk)...)
+	changes = append(changes, diffLockStringMaps(lockCategoryConditions, before.Conditions, after.Conditions, conditionChangeRisk)...)
+	changes = append(changes, diffLockPermissions(before.Permissions, after.Permissions)...)
+	changes = append(changes, diffLockSets(lockCategorySecrets, before.Secrets, after.Secrets, func(string) string { return lockRiskHigh })...)
+	changes = append(changes, diffLockSets(lockCategoryNetwork, before.Domains, after.Domains, domainAddedRisk)...)
+	changes = append(changes, diffLockStringMaps(lockCategoryActions, before.Actions, after.Actions, pinnedRefChangeRisk)...)
+	changes = append(changes, diffLockStringMaps(lockCategoryContainers, before.Containers, after.Containers, containerChangeRisk)...)
+	changes = append(changes, diffLockStringMaps(lockCategorySafeOutputs, before.SafeOutputs, after.SafeOutputs, safeOutputChangeRisk)...)
+	changes = append(changes, diffLockSets(lockCategoryTools, before.Tools, after.Tools, toolAddedRisk)...)
+
+	return changes
+}
+
+// diffLockStringMaps reports added, removed and changed keys of two maps
+func diffLockStringMaps(category string, before, after map[string]string, risk func(kind, key, before, after string) string) []LockDiffChange {
+	var changes []LockDiffChange
+	for _, key := range unionLockKeys(before, after) {
+		beforeValue, hadBefore := before[key]
+		afterValue, hasAfter := after[key]
+		var kind string
+		switch {
+		case !hadBefore:
+			kind = lockChangeAdded
+		case !hasAfter:
+			kind = lockChangeRemoved
+		case beforeValue != afterValue:
+			kind = lockChangeChanged
+		default:
+			continue
+		}
+		changes = append(changes, LockDiffChange{
+			Category: category,
+			Kind:     kind,
+			Subject:  key,
+			Before:   beforeValue,
+			After:    afterValue,
+			Risk:     risk(kind, key, beforeValue, afterValue),
+		})
+	}
+	return changes
+}
+
+// diffLockSets reports added and removed members of two sets; only additions
+// are classified by addedRisk, removals are always low risk
+func diffLockSets(category string, before, after map[string]bool, addedRisk func(string) string) []LockDiffChange {
+	var changes []LockDiffChange
+	for _, key := range unionLockKeys(before, after) {
+		switch {
+		case !before[key] && after[key]:
+			changes = append(changes, LockDiffChange{Category: category, Kind: lockChangeAdded, Subject: key, Risk: addedRisk(key)})
+		case before[key] && !after[key]:
+			changes = append(changes, LockDiffChange{Category: category, Kind: lockChangeRemoved, Subject: key, Risk: lockRiskLow})
+		}
+	}
+	return changes
+}
+
+// diffLockPermissions reports permission scopes that were granted, revoked or changed level
+func diffLockPermissions(before, after map[string]map[string]string) []LockDiffChange {
+	var changes []LockDiffChange
+	for _, job := range unionLockKeys(before, after) {
+		for _, change := range diffLockStringMaps(lockCategoryPermissions, before[job], after[job], permissionChangeRisk) {
+			change.Subject = job + ": " + change.Subject
+			changes = append(changes, change)
+		}
+	}
+	return changes
+}
+
+// permissionLevelRank orders permission levels so escalations can be detected
+func permissionLevelRank(level string) int {
+	switch level {
+	case "write":
+		return 2
+	case "read":
+		return 1
+	default:
+		return 0
+	}
+}
+
+func permissionChangeRisk(kind, _, before, after string) string {
+	switch {
+	case kind == lockChangeRemoved || permissionLevelRank(after) <= permissionLevelRank(before):
+		return lockRiskLow
+	case after == "write":
+		return lockRiskHigh
+	default:
+		return lockRiskMedium
+	}
+}
+
+func triggerChangeRisk(kind, event, _, _ string) string {
+	switch {
+	case kind == lockChangeRemoved:
+		return lockRiskLow
+	case kind == lockChangeAdded && highRiskTriggers[event]:
+		return lockRiskHigh
+	default:
+		return lockRiskMedium
+	}
+}
+
+func conditionChangeRisk(kind, _, _, _ string) string {
+	switch kind {
+	case lockChangeAdded:
+		return lockRiskLow
+	case lockChangeRemoved:
+		// Removing a job condition makes the job run unconditionally
+		return lockRiskHigh
+	default:
+		return lockRiskMedium
+	}
+}
+
+func pinnedRefChangeRisk(kind, _, _, _ string) string {
+	if kind == lockChangeRemoved {
+		return lockRiskLow
+	}
+	return lockRiskMedium
+}
+
+func containerChangeRisk(kind, _, _, after string) string {
+	switch {
+	case kind == lockChangeRemoved:
+		return lockRiskLow
+	case after == "":
+		// Image is referenced by a mutable tag only
+		return lockRiskHigh
+	default:
+		return lockRiskMedium
+	}
+}
+
+func safeOutputChangeRisk(kind, _, _, _ string) string {
+	switch kind {
+	case lockChangeAdded:
+		return lockRiskMedium
+	default:
+		return lockRiskLow
+	}
+}
+
+func domainAddedRisk(domain string) string {
+	if strings.Contains(domain, "*") {
+		return lockRiskHigh
+	}
+	return lockRiskMedium
+}
+
+func toolAddedRisk(tool string) string {
+	switch tool {
+	case "shell", "Bash", "write", "shell(*)", "Bash(*)":
+		// Unrestricted shell or file writes
+		return lockRiskHigh
+	}
+	return lockRiskMedium
+}
+
+// lockRiskRank orders risks for sorting and thresholds (higher is riskier)
+func lockRiskRank(risk string) int {
+	switch risk {
+	case lockRiskHigh:
+		return 3
+	case lockRiskMedium:
+		return 2
+	case lockRiskLow:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func unionLockKeys[V any](maps ...map[string]V) []string {
+	seen := make(map[string]bool)
+	for _, m := range maps {
+		for key := range m {
+			seen[key] = true
+		}
+	}
+	return sortedLockKeys(seen)
+}
+
+func sortedLockKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
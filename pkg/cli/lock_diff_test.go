@@ -0,0 +1,151 @@
+//go:build !integration
+
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const lockDiffBaseContent = `# gh-aw-metadata: {"schema_version":"v3","frontmatter_hash":"abc","agent_id":"copilot"}
+# gh-aw-manifest: {"version":1,"secrets":["COPILOT_GITHUB_TOKEN","GITHUB_TOKEN"],"actions":[{"repo":"actions/checkout","sha":"de0fac2e4500dabe0009e67214ff5f5447ce83dd","version":"v6.0.2"}],"containers":[{"image":"node:lts-alpine","digest":"sha256:aaa","pinned_image":"node:lts-alpine@sha256:aaa"}]}
+name: "Triage"
+"on":
+  issues:
+    types: [opened]
+permissions: {}
+jobs:
+  agent:
+    if: needs.activation.outputs.activated == 'true'
+    permissions:
+      contents: read
+      issues: read
+    steps:
+      - name: Write Safe Outputs Config
+        run: |
+          cat > "${RUNNER_TEMP}/gh-aw/safeoutputs/config.json" << 'GH_AW_SAFE_OUTPUTS_CONFIG_abc_EOF'
+          {"add_comment":{"max":1}}
+          GH_AW_SAFE_OUTPUTS_CONFIG_abc_EOF
+      - name: Start MCP Gateway
+        run: |
+          cat << EOF | node start.cjs
+          {
+            "mcpServers": {
+              "github": {
+                "type": "stdio",
+                "env": {"GITHUB_TOKEN": "\${GITHUB_TOKEN}"}
+              },
+              "safeoutputs": {
+                "type": "http"
+              }
+            }
+          }
+          EOF
+      - name: Execute agent
+        # --allow-tool github
+        # --allow-tool shell(cat)
+        env:
+          GH_AW_ALLOWED_DOMAINS: "api.github.com,github.com"
+        run: copilot
+`
+
+func lockDiffHeadContent() string {
+	replacer := strings.NewReplacer(
+		"    types: [opened]\n", "    types: [opened]\n  pull_request_target:\n",
+		"    if: needs.activation.outputs.activated == 'true'\n", "",
+		"      issues: read\n", "      issues: write\n      pull-requests: read\n",
+		`"secrets":["COPILOT_GITHUB_TOKEN","GITHUB_TOKEN"]`, `"secrets":["COPILOT_GITHUB_TOKEN","GITHUB_TOKEN","NPM_TOKEN"]`,
+		`"sha":"de0fac2e4500dabe0009e67214ff5f5447ce83dd","version":"v6.0.2"`, `"sha":"1111111111111111111111111111111111111111","version":"v6.1.0"`,
+		`{"add_comment":{"max":1}}`, `{"add_comment":{"max":3},"create_pull_request":{}}`,
+		`              "safeoutputs": {`, `              "playwright": {"type": "stdio"},
+              "safeoutputs": {`,
+		"        # --allow-tool shell(cat)\n", "        # --allow-tool shell\n",
+		`"api.github.com,github.com"`, `"*.example.com,api.github.com"`,
+	)
+	return replacer.Replace(lockDiffBaseContent)
+}
+
+func TestSummarizeLockFile(t *testing.T) {
+	summary, err := summarizeLockFile(lockDiffBaseContent, "triage.lock.yml")
+	require.NoError(t, err, "lock file should be summarized")
+
+	assert.Equal(t, map[string]string{"issues": `{"types":["opened"]}`}, summary.Triggers, "triggers should be canonical JSON")
+	assert.Equal(t, map[string]string{"agent": "needs.activation.outputs.activated == 'true'"}, summary.Conditions, "job conditions should be recorded")
+	assert.Equal(t, map[string]string{"contents": "read", "issues": "read"}, summary.Permissions["agent"], "job permissions should be flattened")
+	assert.Empty(t, summary.Permissions[workflowLevelKey], "empty workflow permissions should be recorded")
+	assert.Equal(t, "de0fac2e4500dabe0009e67214ff5f5447ce83dd (v6.0.2)", summary.Actions["actions/checkout"], "actions should come from the manifest")
+	assert.Equal(t, "sha256:aaa", summary.Containers["node:lts-alpine"], "container digests should come from the manifest")
+	assert.Equal(t, map[string]bool{"COPILOT_GITHUB_TOKEN": true, "GITHUB_TOKEN": true}, summary.Secrets, "secrets should come from the manifest")
+	assert.Equal(t, map[string]bool{"api.github.com": true, "github.com": true}, summary.Domains, "allowed domains should be split")
+	assert.Equal(t, map[string]string{"add-comment": `{"max":1}`}, summary.SafeOutputs, "safe outputs should be parsed from the config")
+	assert.Equal(t, map[string]bool{"github": true, "shell(cat)": true, "mcp:github": true, "mcp:safeoutputs": true}, summary.Tools, "tools and MCP servers should be collected")
+}
+
+func TestDiffLockSummaries(t *testing.T) {
+	before, err := summarizeLockFile(lockDiffBaseContent, "triage.lock.yml")
+	require.NoError(t, err, "base should be summarized")
+	after, err := summarizeLockFile(lockDiffHeadContent(), "triage.lock.yml")
+	require.NoError(t, err, "head should be summarized")
+
+	risks := make(map[string]string)
+	for _, change := range diffLockSummaries(before, after) {
+		risks[change.Category+" "+change.Kind+" "+change.Subject] = change.Risk
+	}
+
+	assert.Equal(t, map[string]string{
+		"triggers added pull_request_target":     lockRiskHigh,
+		"conditions removed agent":               lockRiskHigh,
+		"permissions changed agent: issues":      lockRiskHigh,
+		"permissions added agent: pull-requests": lockRiskMedium,
+		"secrets added NPM_TOKEN":                lockRiskHigh,
+		"network added *.example.com":            lockRiskHigh,
+		"network removed github.com":             lockRiskLow,
+		"actions changed actions/checkout":       lockRiskMedium,
+		"safe-outputs changed add-comment":       lockRiskLow,
+		"safe-outputs added create-pull-request": lockRiskMedium,
+		"tools added shell":                      lockRiskHigh,
+		"tools removed shell(cat)":               lockRiskLow,
+		"tools added mcp:playwright":             lockRiskMedium,
+	}, risks, "every semantic change should be classified")
+}
+
+func TestDiffLockSummaries_AddedAndRemovedWorkflows(t *testing.T) {
+	summary, err := summarizeLockFile(lockDiffBaseContent, "triage.lock.yml")
+	require.NoError(t, err, "lock file should be summarized")
+
+	removed := diffLockSummaries(summary, nil)
+	require.Len(t, removed, 1, "removed workflow should be a single change")
+	assert.Equal(t, lockRiskLow, removed[0].Risk, "removing a workflow is low risk")
+
+	added := diffLockSummaries(nil, summary)
+	assert.Equal(t, lockCategoryWorkflow, added[0].Category, "new workflow should be reported first")
+	assert.Greater(t, len(added), 10, "everything in a new workflow should be reported as added")
+}
+
+func TestRenderLockDiffMarkdown(t *testing.T) {
+	report := &LockDiffReport{
+		Base: "origin/main",
+		Head: "HEAD",
+		Workflows: []LockFileDiff{{
+			LockFile: ".github/workflows/triage.lock.yml",
+			Status:   lockChangeChanged,
+			Changes: []LockDiffChange{
+				{Category: lockCategoryConditions, Kind: lockChangeChanged, Subject: "agent", Before: "a || b", After: "a", Risk: lockRiskMedium},
+				{Category: lockCategorySecrets, Kind: lockChangeAdded, Subject: "NPM_TOKEN", Risk: lockRiskHigh},
+			},
+		}},
+		Risk: map[string]int{lockRiskHigh: 1, lockRiskMedium: 1},
+	}
+
+	markdown := renderLockDiffMarkdown(report)
+	assert.Contains(t, markdown, "## Lock file changes: `origin/main` → `HEAD`", "heading should name both refs")
+	assert.Contains(t, markdown, "**Risk:** 1 high, 1 medium, 0 low across 1 workflow(s)", "risk summary should be shown")
+	assert.Contains(t, markdown, "| medium | conditions | `agent` changed: `a \\|\\| b` → `a` |", "pipes should be escaped in table cells")
+	assert.Contains(t, markdown, "| high | secrets | `NPM_TOKEN` added |", "added secrets should be listed")
+
+	empty := renderLockDiffMarkdown(&LockDiffReport{Base: "HEAD", Head: workingTreeRef})
+	assert.Contains(t, empty, "No semantic changes", "empty report should say so")
+}
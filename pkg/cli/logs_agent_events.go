@@ -0,0 +1,123 @@
+// This file contains the export of the normalized agent event stream.
+// It runs the engine-specific ParseAgentEvents parser over a run's agent logs
+// and writes the result as JSONL, one workflow.AgentEvent per line.
+
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/constants"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/workflow"
+)
+
+var logsAgentEventsLog = logger.New("cli:logs_agent_events")
+
+// agentEventsFileName is the event stream written into each run folder.
+const agentEventsFileName = "agent-events.jsonl"
+
+// buildRunAgentEvents parses the agent log files of a downloaded run into the normalized
+// event stream. Events from multiple log files are concatenated with sequence and turn
+// numbers continuing across files.
+func buildRunAgentEvents(logDir string, run WorkflowRun) ([]workflow.AgentEvent, error) {
+	engine := extractEngineFromAwInfo(filepath.Join(logDir, "aw_info.json"), false)
+	if engine == nil {
+		return nil, errors.New("could not detect the engine from aw_info.json")
+	}
+
+	var logFiles []string
+	err := filepath.Walk(logDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			// workflow-logs/ holds runner captures that duplicate the agent artifact logs
+			if info.Name() == "workflow-logs" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		fileName := strings.ToLower(info.Name())
+		if (strings.HasSuffix(fileName, ".log") ||
+			(strings.HasSuffix(fileName, ".txt") && strings.Contains(fileName, "log"))) &&
+			!strings.Contains(fileName, "aw_output") &&
+			fileName != constants.AgentOutputFilename {
+			logFiles = append(logFiles, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan logs: %w", err)
+	}
+
+	var events []workflow.AgentEvent
+	turnOffset := 0
+	for _, logFile := range logFiles {
+		content, err := os.ReadFile(logFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", filepath.Base(logFile), err)
+		}
+		fileTurns := 0
+		for _, evt := range engine.ParseAgentEvents(string(content)) {
+			fileTurns = max(fileTurns, evt.Turn)
+			if evt.Turn > 0 {
+				evt.Turn += turnOffset
+			}
+			evt.Seq = len(events) + 1
+			evt.RunID = run.DatabaseID
+			events = append(events, evt)
+		}
+		turnOffset += fileTurns
+	}
+
+	logsAgentEventsLog.Printf("Built %d agent events for run %d from %d log files (engine=%s)", len(events), run.DatabaseID, len(logFiles), engine.GetID())
+	return events, nil
+}
+
+// marshalAgentEventsJSONL encodes events as JSON Lines.
+func marshalAgentEventsJSONL(events []workflow.AgentEvent) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	for _, evt := range events {
+		if err := encoder.Encode(evt); err != nil {
+			return nil, fmt.Errorf("failed to encode agent event %d: %w", evt.Seq, err)
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// writeRunAgentEvents writes agent-events.jsonl into the run folder and returns its path
+// and the number of events written.
+func writeRunAgentEvents(logDir string, run WorkflowRun) (string, int, error) {
+	events, err := buildRunAgentEvents(logDir, run)
+	if err != nil {
+		return "", 0, err
+	}
+	data, err := marshalAgentEventsJSONL(events)
+	if err != nil {
+		return "", 0, err
+	}
+	eventsPath := filepath.Join(logDir, agentEventsFileName)
+	if err := os.WriteFile(eventsPath, data, constants.FilePermPublic); err != nil {
+		return "", 0, fmt.Errorf("failed to write agent events: %w", err)
+	}
+	return eventsPath, len(events), nil
+}
+
+// exportAgentEventsForRun exports the agent event stream for a downloaded run and reports the outcome.
+func exportAgentEventsForRun(logDir string, run WorkflowRun) {
+	eventsPath, count, err := writeRunAgentEvents(logDir, run)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to export agent events for run %d: %v", run.DatabaseID, err)))
+		return
+	}
+	fmt.Fprintln(os.Stderr, console.FormatSuccessMessage(fmt.Sprintf("✓ Exported %d agent events for run %d → %s", count, run.DatabaseID, eventsPath)))
+}
@@ -0,0 +1,57 @@
+//go:build !integration
+
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteRunAgentEvents(t *testing.T) {
+	logDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(logDir, "aw_info.json"), []byte(`{"engine_id":"pi"}`), 0644))
+	agentLog := `{"type":"init","model":"pi-large"}
+{"type":"assistant","content":"Working"}
+{"type":"tool_use","tool_name":"bash","tool_id":"t1"}
+{"type":"result","stats":{"input_tokens":10,"output_tokens":2}}
+`
+	require.NoError(t, os.WriteFile(filepath.Join(logDir, "agent-stdio.log"), []byte(agentLog), 0644))
+	workflowLogsDir := filepath.Join(logDir, "workflow-logs")
+	require.NoError(t, os.MkdirAll(workflowLogsDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(workflowLogsDir, "agent.log"), []byte(agentLog), 0644))
+
+	path, count, err := writeRunAgentEvents(logDir, WorkflowRun{DatabaseID: 42})
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(logDir, agentEventsFileName), path)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var events []workflow.AgentEvent
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		var evt workflow.AgentEvent
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &evt), "each line should be one JSON event")
+		events = append(events, evt)
+	}
+	require.Len(t, events, count)
+	assert.Len(t, events, 6, "workflow-logs should not be parsed twice")
+	for i, evt := range events {
+		assert.Equal(t, i+1, evt.Seq)
+		assert.Equal(t, int64(42), evt.RunID)
+		assert.Equal(t, "pi", evt.Engine)
+	}
+}
+
+func TestBuildRunAgentEvents_NoEngine(t *testing.T) {
+	_, err := buildRunAgentEvents(t.TempDir(), WorkflowRun{DatabaseID: 1})
+	require.Error(t, err, "runs without aw_info.json cannot be parsed")
+	assert.Contains(t, err.Error(), "aw_info.json")
+}
@@ -19,6 +19,7 @@ import (
 	"github.com/github/gh-aw/pkg/console"
 	"github.com/github/gh-aw/pkg/constants"
 	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/parser"
 	"github.com/github/gh-aw/pkg/workflow"
 	"github.com/spf13/cobra"
 )
@@ -95,6 +96,14 @@ Examples:
   ` + string(constants.CLIExtensionPrefix) + ` logs weekly-research --format markdown --last 10  # Cross-run report for last 10 runs
   ` + string(constants.CLIExtensionPrefix) + ` logs --train                   # Train log pattern weights from last 10 runs
   ` + string(constants.CLIExtensionPrefix) + ` logs my-workflow --train -c 50 # Train log pattern weights from up to 50 runs of a specific workflow
+  ` + string(constants.CLIExtensionPrefix) + ` logs triage -c 1 --record-transcript # Record a transcript for the replay engine
+  ` + string(constants.CLIExtensionPrefix) + ` logs --events jsonl            # Export engine-independent agent events to agent-events.jsonl
+  ` + string(constants.CLIExtensionPrefix) + ` logs --follow 1234567890       # Stream an in-progress run live, then show its audit summary
+
+  # Cost attribution (prices from the "cost" section of aw.json)
+  ` + string(constants.CLIExtensionPrefix) + ` logs --cost-report --start-date -1mo -c 500      # Spend by team, workflow, label, actor and episode
+  ` + string(constants.CLIExtensionPrefix) + ` logs --cost-report --format csv > chargeback.csv # Export monthly attribution as CSV
+  ` + string(constants.CLIExtensionPrefix) + ` logs --cost-report --json                        # Export the cost report as JSON
 
   # Cross-repository
   ` + string(constants.CLIExtensionPrefix) + ` logs weekly-research --repo owner/repo  # Download logs from specific repository
@@ -109,6 +118,17 @@ Examples:
 
 			stdin, _ := cmd.Flags().GetBool("stdin")
 
+			if err := validateCostReportFormat(cmd); err != nil {
+				return err
+			}
+			if err := validateEventsFormat(cmd); err != nil {
+				return err
+			}
+
+			if follow, _ := cmd.Flags().GetBool("follow"); follow {
+				return runLogsFollow(cmd, args)
+			}
+
 			// When --stdin is provided, read run IDs/URLs from stdin and bypass GitHub API discovery.
 			if stdin {
 				if len(args) > 0 {
@@ -132,6 +152,8 @@ Examples:
 				firewallOnly, _ := cmd.Flags().GetBool("firewall")
 				noFirewall, _ := cmd.Flags().GetBool("no-firewall")
 				parse, _ := cmd.Flags().GetBool("parse")
+				recordTranscript, _ := cmd.Flags().GetBool("record-transcript")
+				eventsFormat, _ := cmd.Flags().GetString("events")
 				jsonOutput, _ := cmd.Flags().GetBool("json")
 				timeout, _ := cmd.Flags().GetInt("timeout")
 				summaryFile, _ := cmd.Flags().GetString("summary-file")
@@ -139,6 +161,7 @@ Examples:
 				filteredIntegrity, _ := cmd.Flags().GetBool("filtered-integrity")
 				train, _ := cmd.Flags().GetBool("train")
 				format, _ := cmd.Flags().GetString("format")
+				costReport, _ := cmd.Flags().GetBool("cost-report")
 				artifacts, _ := cmd.Flags().GetStringSlice("artifacts")
 
 				if engine != "" {
@@ -150,7 +173,7 @@ Examples:
 					}
 				}
 
-				return DownloadWorkflowLogsFromStdin(cmd.Context(), runURLs, outputDir, engine, repoOverride, verbose, toolGraph, noStaged, firewallOnly, noFirewall, parse, jsonOutput, timeout, summaryFile, safeOutputType, filteredIntegrity, train, format, artifacts)
+				return DownloadWorkflowLogsFromStdin(cmd.Context(), runURLs, outputDir, engine, repoOverride, verbose, toolGraph, noStaged, firewallOnly, noFirewall, parse, recordTranscript, jsonOutput, timeout, summaryFile, safeOutputType, filteredIntegrity, train, costReport, format, eventsFormat, artifacts)
 			}
 
 			var workflowName string
@@ -212,6 +235,8 @@ Examples:
 			firewallOnly, _ := cmd.Flags().GetBool("firewall")
 			noFirewall, _ := cmd.Flags().GetBool("no-firewall")
 			parse, _ := cmd.Flags().GetBool("parse")
+			recordTranscript, _ := cmd.Flags().GetBool("record-transcript")
+			eventsFormat, _ := cmd.Flags().GetString("events")
 			jsonOutput, _ := cmd.Flags().GetBool("json")
 			timeout, _ := cmd.Flags().GetInt("timeout")
 			repoOverride, _ := cmd.Flags().GetString("repo")
@@ -220,6 +245,7 @@ Examples:
 			filteredIntegrity, _ := cmd.Flags().GetBool("filtered-integrity")
 			train, _ := cmd.Flags().GetBool("train")
 			format, _ := cmd.Flags().GetString("format")
+			costReport, _ := cmd.Flags().GetBool("cost-report")
 			artifacts, _ := cmd.Flags().GetStringSlice("artifacts")
 			after, _ := cmd.Flags().GetString("after")
 
@@ -273,6 +299,8 @@ Examples:
 				FirewallOnly:      firewallOnly,
 				NoFirewall:        noFirewall,
 				Parse:             parse,
+				RecordTranscript:  recordTranscript,
+				EventsFormat:      eventsFormat,
 				JSONOutput:        jsonOutput,
 				TimeoutMinutes:    timeout,
 				SummaryFile:       summaryFile,
@@ -280,6 +308,7 @@ Examples:
 				FilteredIntegrity: filteredIntegrity,
 				Train:             train,
 				Format:            format,
+				CostReport:        costReport,
 				ArtifactSets:      artifacts,
 				After:             after,
 			})
@@ -303,15 +332,19 @@ Examples:
 	logsCmd.Flags().String("safe-output", "", "Filter to runs containing a specific safe output type (e.g., create-issue, missing-tool, missing-data, noop, report-incomplete)")
 	logsCmd.Flags().Bool("filtered-integrity", false, "Filter to runs containing items that were filtered by gateway integrity checks")
 	logsCmd.Flags().Bool("parse", false, "Run JavaScript parsers on agent logs and firewall logs, writing Markdown to log.md and firewall.md")
+	logsCmd.Flags().Bool("record-transcript", false, "Record the MCP tool calls of the most recent run of each workflow to .github/aw/transcripts/<workflow-id>.json for the replay engine")
+	logsCmd.Flags().String("events", "", "Export the normalized agent event stream of each run to agent-events.jsonl (supported: jsonl)")
 	addJSONFlag(logsCmd)
 	logsCmd.Flags().Int("timeout", 0, "Download timeout in minutes (0 = no timeout)")
 	logsCmd.Flags().String("summary-file", "summary.json", "Path to write the summary JSON file relative to output directory (use empty string to disable)")
 	logsCmd.Flags().Bool("train", false, "Analyze log patterns across downloaded runs and save pattern weights to drain3_weights.json in the output directory")
-	logsCmd.Flags().String("format", "", "Output format for cross-run audit report: pretty, markdown (generates security audit report instead of default metrics table)")
+	logsCmd.Flags().String("format", "", "Output format for cross-run audit report: pretty, markdown (generates security audit report instead of default metrics table); with --cost-report: csv")
+	logsCmd.Flags().Bool("cost-report", false, "Attribute spend to teams (CODEOWNERS), workflows, labels, actors and episodes by month instead of showing the metrics table")
 	logsCmd.Flags().Int("last", 0, "Alias for --count: number of recent runs to download")
 	logsCmd.Flags().StringSlice("artifacts", nil, "Artifact sets to download (default: all). Valid sets: "+strings.Join(ValidArtifactSetNames(), ", "))
 	logsCmd.Flags().String("after", "", "(Cache eviction) Evict locally cached run folders for runs before this date, prior to downloading. Accepts deltas like -1d, -1w, -1mo (or explicit day counts like -30d), or an absolute date YYYY-MM-DD. Unlike --start-date, this only clears local cache and does not filter which runs are fetched.")
 	logsCmd.Flags().Bool("stdin", false, "Read workflow run IDs or URLs from stdin (one per line) instead of discovering runs via the GitHub API")
+	logsCmd.Flags().Bool("follow", false, "Stream the agent turns, tool calls and errors of the in-progress run given by run ID or URL until it completes, then show the audit summary (Ctrl-C cancels the run)")
 	logsCmd.MarkFlagsMutuallyExclusive("firewall", "no-firewall")
 
 	// Register completions for logs command
@@ -322,6 +355,28 @@ Examples:
 	return logsCmd
 }
 
+// validateCostReportFormat checks that --format is compatible with --cost-report
+func validateCostReportFormat(cmd *cobra.Command) error {
+	costReport, _ := cmd.Flags().GetBool("cost-report")
+	format, _ := cmd.Flags().GetString("format")
+	if costReport && format != "" && format != "csv" {
+		return fmt.Errorf("--format %s cannot be combined with --cost-report; use --format csv or --json to export the cost report", format)
+	}
+	if !costReport && format == "csv" {
+		return errors.New("--format csv is only supported with --cost-report")
+	}
+	return nil
+}
+
+// validateEventsFormat checks the --events export format.
+func validateEventsFormat(cmd *cobra.Command) error {
+	eventsFormat, _ := cmd.Flags().GetString("events")
+	if eventsFormat != "" && eventsFormat != "jsonl" {
+		return fmt.Errorf("unsupported --events format '%s'; supported formats: jsonl", eventsFormat)
+	}
+	return nil
+}
+
 // flattenSingleFileArtifacts applies the artifact unfold rule to downloaded artifacts
 // Unfold rule: If an artifact download folder contains a single file, move the file to root and delete the folder
 // This simplifies artifact access by removing unnecessary nesting for single-file artifacts
@@ -367,3 +422,31 @@ Examples:
 // parseAgentLog runs the JavaScript log parser on agent logs and writes markdown to log.md
 
 // parseFirewallLogs runs the JavaScript firewall log parser and writes markdown to firewall.md
+
+// runLogsFollow streams the run given as the only argument (run ID or URL)
+func runLogsFollow(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return errors.New("--follow requires exactly one run ID or run URL")
+	}
+	components, err := parser.ParseRunURLExtended(args[0])
+	if err != nil {
+		return fmt.Errorf("invalid run ID or URL '%s': %w", args[0], err)
+	}
+
+	repo, _ := cmd.Flags().GetString("repo")
+	if components.Owner != "" {
+		repo = components.Owner + "/" + components.Repo
+	}
+	engine, _ := cmd.Flags().GetString("engine")
+	outputDir, _ := cmd.Flags().GetString("output")
+	verbose, _ := cmd.Flags().GetBool("verbose")
+	logsCommandLog.Printf("Following run %d: repo=%s, engine=%s", components.Number, repo, engine)
+
+	return FollowWorkflowRun(cmd.Context(), FollowOptions{
+		RunID:     components.Number,
+		Repo:      repo,
+		Engine:    engine,
+		OutputDir: outputDir,
+		Verbose:   verbose,
+	})
+}
@@ -0,0 +1,459 @@
+package cli
+
+import (
+	"cmp"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/gitutil"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/parser"
+	"github.com/github/gh-aw/pkg/sliceutil"
+	"github.com/github/gh-aw/pkg/stringutil"
+	"github.com/github/gh-aw/pkg/workflow"
+)
+
+var costReportLog = logger.New("cli:logs_cost_report")
+
+// Cost report attribution dimensions
+const (
+	costDimensionEpisode  = "episode"
+	costDimensionWorkflow = "workflow"
+	costDimensionActor    = "actor"
+	costDimensionLabel    = "label"
+	costDimensionTeam     = "team"
+)
+
+// costDimensions lists the attribution dimensions in report order
+var costDimensions = []string{costDimensionTeam, costDimensionWorkflow, costDimensionLabel, costDimensionActor, costDimensionEpisode}
+
+// Placeholder keys for runs that cannot be attributed on a dimension
+const (
+	costKeyUnknownActor = "(unknown)"
+	costKeyUnlabeled    = "(unlabeled)"
+	costKeyUnowned      = "(no owner)"
+)
+
+// Cost sources recorded per run
+const (
+	costSourcePriceTable = "price-table"
+	costSourceEstimate   = "estimate"
+	costSourceUnpriced   = "unpriced"
+)
+
+// CostReport attributes the spend of downloaded runs to episodes, workflows,
+// triggering actors, workflow labels and CODEOWNERS teams.
+//
+// When aw.json configures a price table, costs are in its currency and runs
+// using a model missing from the table are kept out of the totals: they are
+// counted as unpriced, with their engine-reported USD estimate reported
+// separately. Without a price table every run uses the USD engine estimate.
+type CostReport struct {
+	Currency                 string            `json:"currency"`
+	TotalCost                float64           `json:"total_cost"`
+	TotalRuns                int               `json:"total_runs"`
+	PricedRuns               int               `json:"priced_runs"`
+	UnpricedRuns             int               `json:"unpriced_runs,omitempty"`
+	UnpricedEstimatedCostUSD float64           `json:"unpriced_estimated_cost_usd,omitempty"`
+	UnpricedModels           []string          `json:"unpriced_models,omitempty"`
+	Attributions             []CostAttribution `json:"attributions"`
+	Runs                     []RunCost         `json:"runs"`
+}
+
+// CostAttribution is the spend of one key on one dimension in one calendar month.
+// Runs counts distinct runs; a run with several labels or owners is counted once
+// for each of them, while its tokens and cost are split between them.
+type CostAttribution struct {
+	Dimension    string  `json:"dimension"`
+	Key          string  `json:"key"`
+	Month        string  `json:"month"`
+	Runs         int     `json:"runs"`
+	UnpricedRuns int     `json:"unpriced_runs,omitempty"`
+	Tokens       int     `json:"tokens"`
+	Cost         float64 `json:"cost"`
+
+	runIDs map[int64]bool
+}
+
+// RunCost is the priced cost of a single run together with its attribution keys
+type RunCost struct {
+	RunID        int64    `json:"run_id"`
+	WorkflowName string   `json:"workflow_name"`
+	EpisodeID    string   `json:"episode_id,omitempty"`
+	Actor        string   `json:"actor"`
+	Labels       []string `json:"labels,omitempty"`
+	Teams        []string `json:"teams,omitempty"`
+	Month        string   `json:"month"`
+	Tokens       int      `json:"tokens"`
+	Cost         float64  `json:"cost"`
+	CostSource   string   `json:"cost_source"`
+}
+
+// costWorkflowResolver returns the labels and CODEOWNERS owners of a workflow
+type costWorkflowResolver func(workflowPath string) (labels []string, owners []string)
+
+// buildCostReport prices every run and aggregates the spend per dimension and month.
+// Runs with several labels or owners have their cost split evenly between them so
+// that each dimension still adds up to the total spend.
+func buildCostReport(runs []RunData, episodes []EpisodeData, costModel *workflow.CostModelConfig, resolve costWorkflowResolver) *CostReport {
+	costReportLog.Printf("Building cost report: runs=%d, episodes=%d, price_table=%t", len(runs), len(episodes), costModel != nil)
+
+	episodeByRun := make(map[int64]string)
+	for _, episode := range episodes {
+		for _, runID := range episode.RunIDs {
+			episodeByRun[runID] = episode.EpisodeID
+		}
+	}
+
+	report := &CostReport{Currency: costModel.CurrencyCode(), TotalRuns: len(runs)}
+	unpriced := make(map[string]bool)
+	totals := make(map[[3]string]*CostAttribution)
+	add := func(run RunCost, dimension, key string, tokens int, cost float64) {
+		id := [3]string{dimension, key, run.Month}
+		entry, ok := totals[id]
+		if !ok {
+			entry = &CostAttribution{Dimension: dimension, Key: key, Month: run.Month, runIDs: make(map[int64]bool)}
+			totals[id] = entry
+		}
+		if !entry.runIDs[run.RunID] {
+			entry.runIDs[run.RunID] = true
+			entry.Runs++
+			if run.CostSource == costSourceUnpriced {
+				entry.UnpricedRuns++
+			}
+		}
+		entry.Tokens += tokens
+		entry.Cost += cost
+	}
+
+	for _, run := range runs {
+		cost, source, missing := priceRun(run, costModel)
+		for _, model := range missing {
+			unpriced[model] = true
+		}
+		switch source {
+		case costSourcePriceTable:
+			report.PricedRuns++
+		case costSourceUnpriced:
+			report.UnpricedRuns++
+			report.UnpricedEstimatedCostUSD += run.EstimatedCost
+		}
+
+		labels, owners := resolve(run.WorkflowPath)
+		labels, owners = sliceutil.Deduplicate(labels), sliceutil.Deduplicate(owners)
+		runCost := RunCost{
+			RunID:        run.RunID,
+			WorkflowName: run.WorkflowName,
+			EpisodeID:    episodeByRun[run.RunID],
+			Actor:        costRunActor(run),
+			Labels:       labels,
+			Teams:        owners,
+			Month:        run.CreatedAt.UTC().Format("2006-01"),
+			Tokens:       run.TokenUsage,
+			Cost:         cost,
+			CostSource:   source,
+		}
+		report.Runs = append(report.Runs, runCost)
+		report.TotalCost += cost
+
+		add(runCost, costDimensionWorkflow, run.WorkflowName, run.TokenUsage, cost)
+		add(runCost, costDimensionActor, runCost.Actor, run.TokenUsage, cost)
+		if runCost.EpisodeID != "" {
+			add(runCost, costDimensionEpisode, runCost.EpisodeID, run.TokenUsage, cost)
+		}
+		for i, label := range orDefault(labels, costKeyUnlabeled) {
+			add(runCost, costDimensionLabel, label, splitTokens(run.TokenUsage, len(labels), i), cost/float64(max(len(labels), 1)))
+		}
+		for i, owner := range orDefault(owners, costKeyUnowned) {
+			add(runCost, costDimensionTeam, owner, splitTokens(run.TokenUsage, len(owners), i), cost/float64(max(len(owners), 1)))
+		}
+	}
+
+	for _, entry := range totals {
+		report.Attributions = append(report.Attributions, *entry)
+	}
+	slices.SortFunc(report.Attributions, func(a, b CostAttribution) int {
+		return cmp.Or(
+			cmp.Compare(slices.Index(costDimensions, a.Dimension), slices.Index(costDimensions, b.Dimension)),
+			cmp.Compare(a.Month, b.Month),
+			cmp.Compare(b.Cost, a.Cost),
+			cmp.Compare(a.Key, b.Key),
+		)
+	})
+	for model := range unpriced {
+		report.UnpricedModels = append(report.UnpricedModels, model)
+	}
+	slices.Sort(report.UnpricedModels)
+	return report
+}
+
+// priceRun prices a run from its per-model token usage. Without a price table
+// the engine-reported (USD) estimated cost is used. With a price table, a run
+// without per-model usage or using a model missing from the table is unpriced:
+// it has no cost in the table's currency and the missing models are returned.
+func priceRun(run RunData, costModel *workflow.CostModelConfig) (float64, string, []string) {
+	if costModel == nil {
+		return run.EstimatedCost, costSourceEstimate, nil
+	}
+	if run.TokenUsageSummary == nil || len(run.TokenUsageSummary.ByModel) == 0 {
+		costReportLog.Printf("Run %d has no per-model token usage, leaving it unpriced", run.RunID)
+		return 0, costSourceUnpriced, nil
+	}
+
+	total, missing := priceTokenUsage(run.TokenUsageSummary, costModel)
+	if len(missing) > 0 {
+		costReportLog.Printf("Run %d uses unpriced models %v, leaving it unpriced", run.RunID, missing)
+		return 0, costSourceUnpriced, missing
+	}
+	return total, costSourcePriceTable, nil
+}
+
+// priceTokenUsage prices per-model token usage with the cost model and returns
+// the models missing from the price table
+func priceTokenUsage(usage *TokenUsageSummary, costModel *workflow.CostModelConfig) (float64, []string) {
+	var total float64
+	var missing []string
+	for model, modelUsage := range usage.ByModel {
+		price, ok := costModel.PriceFor(model)
+		if !ok {
+			missing = append(missing, model)
+			continue
+		}
+		total += price.Cost(modelUsage.InputTokens, modelUsage.OutputTokens, modelUsage.CacheReadTokens, modelUsage.CacheWriteTokens)
+	}
+	slices.Sort(missing)
+	return total, missing
+}
+
+// costRunActor returns the actor to charge for a run. For runs dispatched by
+// another workflow the actor of the calling workflow is used, since the
+// dispatched run itself is triggered by the Actions bot.
+func costRunActor(run RunData) string {
+	if run.AwContext != nil && run.AwContext.Actor != "" {
+		return run.AwContext.Actor
+	}
+	if run.Actor != "" {
+		return run.Actor
+	}
+	return costKeyUnknownActor
+}
+
+// splitTokens returns the i-th of n shares of tokens. The remainder goes to
+// the first shares so the shares add up to the run's token total.
+func splitTokens(tokens, n, i int) int {
+	if n <= 1 {
+		return tokens
+	}
+	share := tokens / n
+	if i < tokens%n {
+		share++
+	}
+	return share
+}
+
+func orDefault(values []string, fallback string) []string {
+	if len(values) == 0 {
+		return []string{fallback}
+	}
+	return values
+}
+
+// repoFileReader reads a file by its repository-relative path. Missing files
+// are reported with an error wrapping os.ErrNotExist.
+type repoFileReader func(path string) ([]byte, error)
+
+// localRepoFileReader reads files from a local checkout
+func localRepoFileReader(gitRoot string) repoFileReader {
+	return func(path string) ([]byte, error) {
+		return os.ReadFile(filepath.Join(gitRoot, filepath.Clean(path)))
+	}
+}
+
+// remoteRepoFileReader reads files from the default branch of a repository
+// given as [HOST/]owner/repo through the GitHub contents API
+func remoteRepoFileReader(ctx context.Context, repoOverride string) repoFileReader {
+	repo, host := repoOverride, ""
+	if parts := strings.SplitN(repoOverride, "/", 3); len(parts) == 3 {
+		host, repo = parts[0], parts[1]+"/"+parts[2]
+	}
+	return func(path string) ([]byte, error) {
+		args := []string{"api", fmt.Sprintf("/repos/%s/contents/%s", repo, path), "--jq", ".content"}
+		if host != "" {
+			args = append(args, "--hostname", host)
+		}
+		output, err := workflow.ExecGHContext(ctx, args...).CombinedOutput()
+		if err != nil {
+			if strings.Contains(string(output), "HTTP 404") {
+				return nil, fmt.Errorf("%s not found in %s: %w", path, repoOverride, os.ErrNotExist)
+			}
+			return nil, fmt.Errorf("failed to read %s from %s: %s", path, repoOverride, strings.TrimSpace(string(output)))
+		}
+		return decodeBase64FileContent(string(output))
+	}
+}
+
+// newCostWorkflowResolver resolves workflow labels from the workflow markdown
+// frontmatter and owners from the repository's CODEOWNERS file
+func newCostWorkflowResolver(read repoFileReader, codeOwners *CodeOwners) costWorkflowResolver {
+	labelCache := make(map[string][]string)
+	return func(workflowPath string) ([]string, []string) {
+		if workflowPath == "" {
+			return nil, nil
+		}
+		markdownPath := workflowPath
+		if strings.HasSuffix(workflowPath, ".lock.yml") {
+			markdownPath = stringutil.LockFileToMarkdown(workflowPath)
+		}
+
+		labels, ok := labelCache[markdownPath]
+		if !ok {
+			labels = readWorkflowLabels(read, markdownPath)
+			labelCache[markdownPath] = labels
+		}
+
+		owners := codeOwners.Owners(markdownPath)
+		if len(owners) == 0 {
+			owners = codeOwners.Owners(workflowPath)
+		}
+		return labels, owners
+	}
+}
+
+// readWorkflowLabels returns the labels declared in a workflow's frontmatter
+func readWorkflowLabels(read repoFileReader, markdownPath string) []string {
+	content, err := read(markdownPath)
+	if err != nil {
+		costReportLog.Printf("Could not read workflow %s for labels: %v", markdownPath, err)
+		return nil
+	}
+	result, err := parser.ExtractFrontmatterFromContent(string(content))
+	if err != nil || result.Frontmatter == nil {
+		return nil
+	}
+	labelsField, ok := result.Frontmatter["labels"].([]any)
+	if !ok {
+		return nil
+	}
+	var labels []string
+	for _, label := range labelsField {
+		if labelStr, ok := label.(string); ok {
+			labels = append(labels, labelStr)
+		}
+	}
+	return labels
+}
+
+// renderCostReportOutput builds the cost report for the processed runs and renders
+// it as a console table, JSON or CSV. Labels and CODEOWNERS are read from the
+// --repo repository when given, otherwise from the local checkout.
+func renderCostReportOutput(logsData LogsData, format, repoOverride string, jsonOutput bool) error {
+	var costModel *workflow.CostModelConfig
+	gitRoot, err := gitutil.FindGitRoot()
+	if err != nil {
+		costReportLog.Printf("Not in a git repository, using estimated costs only: %v", err)
+	} else {
+		repoConfig, err := workflow.LoadRepoConfig(gitRoot)
+		if err != nil {
+			return err
+		}
+		costModel = repoConfig.CostModel()
+	}
+
+	var read repoFileReader
+	switch {
+	case repoOverride != "":
+		read = remoteRepoFileReader(context.Background(), repoOverride)
+	case gitRoot != "":
+		read = localRepoFileReader(gitRoot)
+	default:
+		read = func(path string) ([]byte, error) { return nil, os.ErrNotExist }
+	}
+	codeOwners, err := loadCodeOwnersWith(read)
+	if err != nil {
+		return fmt.Errorf("failed to read CODEOWNERS: %w", err)
+	}
+
+	report := buildCostReport(logsData.Runs, logsData.Episodes, costModel, newCostWorkflowResolver(read, codeOwners))
+
+	switch {
+	case jsonOutput:
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	case format == "csv":
+		return writeCostReportCSV(os.Stdout, report)
+	default:
+		renderCostReportConsole(report)
+		return nil
+	}
+}
+
+// writeCostReportCSV writes one row per attribution, suitable for spreadsheets
+func writeCostReportCSV(w io.Writer, report *CostReport) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"dimension", "key", "month", "runs", "tokens", "cost", "currency"}); err != nil {
+		return err
+	}
+	for _, entry := range report.Attributions {
+		record := []string{
+			entry.Dimension,
+			entry.Key,
+			entry.Month,
+			strconv.Itoa(entry.Runs),
+			strconv.Itoa(entry.Tokens),
+			strconv.FormatFloat(entry.Cost, 'f', 4, 64),
+			report.Currency,
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// renderCostReportConsole prints a table per attribution dimension to stderr
+func renderCostReportConsole(report *CostReport) {
+	if report.PricedRuns > 0 || report.UnpricedRuns > 0 {
+		fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("Cost report: %d runs, total %.4f %s (%d priced from the %s price table)",
+			report.TotalRuns, report.TotalCost, report.Currency, report.PricedRuns, workflow.RepoConfigFileName)))
+	} else {
+		fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("Cost report: %d runs, total %.4f %s from engine estimates",
+			report.TotalRuns, report.TotalCost, report.Currency)))
+	}
+	if report.UnpricedRuns > 0 {
+		fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("%d runs are not included in the totals because they could not be priced (engine estimate: %.4f USD)",
+			report.UnpricedRuns, report.UnpricedEstimatedCostUSD)))
+	}
+	if len(report.UnpricedModels) > 0 {
+		fmt.Fprintln(os.Stderr, console.FormatWarningMessage("Models missing from the price table: "+strings.Join(report.UnpricedModels, ", ")))
+	}
+
+	for _, dimension := range costDimensions {
+		var rows [][]string
+		for _, entry := range report.Attributions {
+			if entry.Dimension != dimension {
+				continue
+			}
+			rows = append(rows, []string{entry.Key, entry.Month, strconv.Itoa(entry.Runs), console.FormatNumber(entry.Tokens), fmt.Sprintf("%.4f", entry.Cost)})
+		}
+		if len(rows) == 0 {
+			continue
+		}
+		fmt.Fprintln(os.Stderr)
+		fmt.Fprint(os.Stderr, console.RenderTable(console.TableConfig{
+			Title:   "Cost by " + dimension,
+			Headers: []string{strings.ToUpper(dimension[:1]) + dimension[1:], "Month", "Runs", "Tokens", "Cost (" + report.Currency + ")"},
+			Rows:    rows,
+		}))
+	}
+}
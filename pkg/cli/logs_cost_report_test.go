@@ -0,0 +1,155 @@
+//go:build !integration
+
+package cli
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func costReportTestRuns() []RunData {
+	september := time.Date(2026, 9, 15, 10, 0, 0, 0, time.UTC)
+	october := time.Date(2026, 10, 2, 10, 0, 0, 0, time.UTC)
+	return []RunData{
+		{
+			RunID: 1, WorkflowName: "Triage", WorkflowPath: ".github/workflows/triage.lock.yml", Actor: "alice",
+			CreatedAt: september, TokenUsage: 1_100_000, EstimatedCost: 9,
+			TokenUsageSummary: &TokenUsageSummary{ByModel: map[string]*ModelTokenUsage{
+				"claude-sonnet-4.5": {InputTokens: 1_000_000, OutputTokens: 100_000},
+			}},
+		},
+		{
+			RunID: 2, WorkflowName: "Docs", WorkflowPath: ".github/workflows/docs.lock.yml", Actor: "github-actions[bot]",
+			AwContext: &AwContext{Actor: "bob"}, CreatedAt: october, TokenUsage: 500, EstimatedCost: 2,
+			TokenUsageSummary: &TokenUsageSummary{ByModel: map[string]*ModelTokenUsage{
+				"gpt-unknown": {InputTokens: 500},
+			}},
+		},
+		{
+			RunID: 3, WorkflowName: "Triage", WorkflowPath: ".github/workflows/triage.lock.yml",
+			CreatedAt: october, EstimatedCost: 1,
+		},
+	}
+}
+
+func costReportTestResolver(workflowPath string) ([]string, []string) {
+	switch workflowPath {
+	case ".github/workflows/triage.lock.yml":
+		return []string{"automation", "issues"}, []string{"@org/triage"}
+	default:
+		return nil, nil
+	}
+}
+
+func findCostAttribution(report *CostReport, dimension, key, month string) *CostAttribution {
+	for i := range report.Attributions {
+		entry := &report.Attributions[i]
+		if entry.Dimension == dimension && entry.Key == key && entry.Month == month {
+			return entry
+		}
+	}
+	return nil
+}
+
+func TestBuildCostReport(t *testing.T) {
+	costModel := &workflow.CostModelConfig{Models: map[string]workflow.ModelPrice{
+		"claude-sonnet-*": {Input: 3, Output: 15},
+	}}
+	episodes := []EpisodeData{{EpisodeID: "ep-1", RunIDs: []int64{1, 3}}}
+
+	report := buildCostReport(costReportTestRuns(), episodes, costModel, costReportTestResolver)
+
+	assert.Equal(t, "USD", report.Currency, "currency should default to USD")
+	assert.Equal(t, 3, report.TotalRuns, "all runs should be counted")
+	assert.Equal(t, 1, report.PricedRuns, "only fully priced runs should use the price table")
+	assert.Equal(t, 2, report.UnpricedRuns, "runs with unpriced models or no per-model usage should be unpriced")
+	assert.InDelta(t, 3.0, report.UnpricedEstimatedCostUSD, 0.0001, "engine estimates of unpriced runs should be reported separately")
+	assert.Equal(t, []string{"gpt-unknown"}, report.UnpricedModels, "unpriced models should be reported")
+	// run 1: 3 + 1.5 priced; runs 2 and 3 are unpriced and kept out of the total
+	assert.InDelta(t, 4.5, report.TotalCost, 0.0001, "total should only include priced runs")
+
+	team := findCostAttribution(report, costDimensionTeam, "@org/triage", "2026-09")
+	require.NotNil(t, team, "team spend should be attributed per month")
+	assert.InDelta(t, 4.5, team.Cost, 0.0001, "team should be charged the priced run cost")
+
+	label := findCostAttribution(report, costDimensionLabel, "automation", "2026-09")
+	require.NotNil(t, label, "label spend should be attributed")
+	assert.InDelta(t, 2.25, label.Cost, 0.0001, "cost should be split evenly between labels")
+
+	unowned := findCostAttribution(report, costDimensionTeam, costKeyUnowned, "2026-10")
+	require.NotNil(t, unowned, "runs without owners should be attributed to a placeholder")
+	assert.Equal(t, 1, unowned.UnpricedRuns, "unpriced runs should be counted per attribution")
+	assert.Zero(t, unowned.Cost, "unpriced runs should not add cost")
+
+	assert.NotNil(t, findCostAttribution(report, costDimensionActor, "bob", "2026-10"), "dispatched runs should be charged to the calling actor")
+	assert.NotNil(t, findCostAttribution(report, costDimensionActor, costKeyUnknownActor, "2026-10"), "runs without an actor should use a placeholder")
+
+	episode := findCostAttribution(report, costDimensionEpisode, "ep-1", "2026-10")
+	require.NotNil(t, episode, "episode spend should be attributed")
+	assert.Equal(t, 1, episode.Runs, "episode should only include its runs in that month")
+	assert.Zero(t, episode.Cost, "the episode's run in that month is unpriced")
+
+	assert.Equal(t, costDimensionTeam, report.Attributions[0].Dimension, "team attributions should be listed first")
+}
+
+func TestBuildCostReport_NoPriceTable(t *testing.T) {
+	report := buildCostReport(costReportTestRuns(), nil, nil, costReportTestResolver)
+
+	assert.Equal(t, 0, report.PricedRuns, "no runs should be priced without a price table")
+	assert.InDelta(t, 12.0, report.TotalCost, 0.0001, "estimated costs should be used")
+	assert.Empty(t, report.UnpricedModels, "missing price table should not report unpriced models")
+}
+
+func TestBuildCostReport_CountsDistinctRuns(t *testing.T) {
+	runs := []RunData{{RunID: 7, WorkflowName: "Triage", WorkflowPath: ".github/workflows/triage.lock.yml", CreatedAt: time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC), TokenUsage: 100, EstimatedCost: 4}}
+	resolve := func(string) ([]string, []string) {
+		return []string{"automation", "automation", "issues"}, []string{"@org/a", "@org/b"}
+	}
+
+	report := buildCostReport(runs, nil, nil, resolve)
+
+	for _, entry := range report.Attributions {
+		assert.Equal(t, 1, entry.Runs, "a run should be counted once per %s %s", entry.Dimension, entry.Key)
+	}
+	label := findCostAttribution(report, costDimensionLabel, "automation", "2026-10")
+	require.NotNil(t, label, "label spend should be attributed")
+	assert.InDelta(t, 2.0, label.Cost, 0.0001, "duplicate labels should not be charged twice")
+}
+
+func TestBuildCostReport_SplitsTokensExactly(t *testing.T) {
+	runs := []RunData{{RunID: 8, WorkflowName: "Triage", WorkflowPath: ".github/workflows/triage.lock.yml", CreatedAt: time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC), TokenUsage: 100, EstimatedCost: 3}}
+	resolve := func(string) ([]string, []string) {
+		return []string{"automation", "issues", "triage"}, nil
+	}
+
+	report := buildCostReport(runs, nil, nil, resolve)
+
+	var tokens int
+	var cost float64
+	for _, entry := range report.Attributions {
+		if entry.Dimension == costDimensionLabel {
+			tokens += entry.Tokens
+			cost += entry.Cost
+		}
+	}
+	assert.Equal(t, 100, tokens, "label token shares should add up to the run total")
+	assert.InDelta(t, 3.0, cost, 0.0001, "label cost shares should add up to the run cost")
+	first := findCostAttribution(report, costDimensionLabel, "automation", "2026-10")
+	require.NotNil(t, first, "label spend should be attributed")
+	assert.Equal(t, 34, first.Tokens, "the remainder should go to the first label")
+}
+
+func TestWriteCostReportCSV(t *testing.T) {
+	report := &CostReport{Currency: "EUR", Attributions: []CostAttribution{
+		{Dimension: costDimensionTeam, Key: "@org/a, b", Month: "2026-10", Runs: 2, Tokens: 1500, Cost: 1.23456},
+	}}
+
+	var buf bytes.Buffer
+	require.NoError(t, writeCostReportCSV(&buf, report), "CSV should be written")
+	assert.Equal(t, "dimension,key,month,runs,tokens,cost,currency\nteam,\"@org/a, b\",2026-10,2,1500,1.2346,EUR\n", buf.String(), "CSV should have a header and quoted fields")
+}
@@ -70,6 +70,8 @@ type EpisodeData struct {
 	BlockedRequestAtCap            bool              `json:"blocked_request_at_cap,omitempty"`
 	ResourceHeavyNodeCount         int               `json:"resource_heavy_node_count"`
 	PoorControlNodeCount           int               `json:"poor_control_node_count"`
+	RuleAssessmentNodeCount        int               `json:"rule_assessment_node_count,omitempty"`
+	HighSeverityRuleNodeCount      int               `json:"high_severity_rule_node_count,omitempty"`
 	RiskDistribution               string            `json:"risk_distribution"`
 	EscalationEligible             bool              `json:"escalation_eligible"`
 	EscalationReason               string            `json:"escalation_reason,omitempty"`
@@ -202,6 +204,12 @@ func buildEpisodeData(runs []RunData, processedRuns []ProcessedRun) ([]EpisodeDa
 		if hasAssessmentKindAtLeast(run.AgenticAssessments, "poor_agentic_control", "medium") {
 			acc.metadata.PoorControlNodeCount++
 		}
+		if hasRuleAssessmentAtLeast(run.AgenticAssessments, "medium") {
+			acc.metadata.RuleAssessmentNodeCount++
+		}
+		if hasRuleAssessmentAtLeast(run.AgenticAssessments, "high") {
+			acc.metadata.HighSeverityRuleNodeCount++
+		}
 		acc.metadata.MissingToolCount += run.MissingToolCount
 		if pr, ok := processedByID[run.RunID]; ok {
 			acc.metadata.MCPFailureCount += len(pr.MCPFailures)
@@ -590,6 +598,17 @@ func hasAssessmentKindAtLeast(assessments []AgenticAssessment, kind, minimumSeve
 	return false
 }
 
+// hasRuleAssessmentAtLeast reports whether a user-defined assessment rule matched
+// with at least the given severity.
+func hasRuleAssessmentAtLeast(assessments []AgenticAssessment, minimumSeverity string) bool {
+	for _, assessment := range assessments {
+		if assessment.Source != "" && severityRank(assessment.Severity) >= severityRank(minimumSeverity) {
+			return true
+		}
+	}
+	return false
+}
+
 func severityRank(severity string) int {
 	switch severity {
 	case "high":
@@ -602,7 +621,7 @@ func severityRank(severity string) int {
 }
 
 func classifyEpisodeEscalation(episode EpisodeData) (bool, string) {
-	logsEpisodeLog.Printf("Classifying episode escalation: episode_id=%s risky_nodes=%d mcp_failures=%d resource_heavy=%d poor_control=%d", episode.EpisodeID, episode.RiskyNodeCount, episode.NewMCPFailureRunCount, episode.ResourceHeavyNodeCount, episode.PoorControlNodeCount)
+	logsEpisodeLog.Printf("Classifying episode escalation: episode_id=%s risky_nodes=%d mcp_failures=%d resource_heavy=%d poor_control=%d rule_assessments=%d", episode.EpisodeID, episode.RiskyNodeCount, episode.NewMCPFailureRunCount, episode.ResourceHeavyNodeCount, episode.PoorControlNodeCount, episode.RuleAssessmentNodeCount)
 	switch {
 	case episode.RiskyNodeCount >= 2:
 		return true, "repeated_risky_runs"
@@ -614,6 +633,10 @@ func classifyEpisodeEscalation(episode EpisodeData) (bool, string) {
 		return true, "repeated_resource_heavy_for_domain"
 	case episode.PoorControlNodeCount >= 2:
 		return true, "repeated_poor_agentic_control"
+	case episode.HighSeverityRuleNodeCount >= 1:
+		return true, "high_severity_assessment_rule"
+	case episode.RuleAssessmentNodeCount >= 2:
+		return true, "repeated_assessment_rule"
 	default:
 		return false, ""
 	}
@@ -531,9 +531,21 @@ func TestClassifyEpisodeEscalationThresholds(t *testing.T) {
 			expectedOK:     true,
 			expectedReason: "repeated_poor_agentic_control",
 		},
+		{
+			name:           "a high severity assessment rule escalates",
+			episode:        EpisodeData{HighSeverityRuleNodeCount: 1, RuleAssessmentNodeCount: 1},
+			expectedOK:     true,
+			expectedReason: "high_severity_assessment_rule",
+		},
+		{
+			name:           "repeated assessment rules escalate",
+			episode:        EpisodeData{RuleAssessmentNodeCount: 2},
+			expectedOK:     true,
+			expectedReason: "repeated_assessment_rule",
+		},
 		{
 			name:           "single signals do not escalate",
-			episode:        EpisodeData{RiskyNodeCount: 1, ResourceHeavyNodeCount: 1, PoorControlNodeCount: 1},
+			episode:        EpisodeData{RiskyNodeCount: 1, ResourceHeavyNodeCount: 1, PoorControlNodeCount: 1, RuleAssessmentNodeCount: 1},
 			expectedOK:     false,
 			expectedReason: "",
 		},
@@ -125,7 +125,7 @@ func fetchJobDetails(runID int64, verbose bool) ([]JobInfoWithDuration, error) {
 		fmt.Fprintln(os.Stderr, console.FormatVerboseMessage(fmt.Sprintf("Fetching job details for run %d", runID)))
 	}
 
-	output, err := workflow.RunGHCombined("Fetching job details...", "api", fmt.Sprintf("repos/{owner}/{repo}/actions/runs/%d/jobs", runID), "--jq", ".jobs[] | {name: .name, status: .status, conclusion: .conclusion, started_at: .started_at, completed_at: .completed_at}")
+	output, err := workflow.RunGHCombined("Fetching job details...", "api", fmt.Sprintf("repos/{owner}/{repo}/actions/runs/%d/jobs", runID), "--jq", ".jobs[] | {name: .name, status: .status, conclusion: .conclusion, started_at: .started_at, completed_at: .completed_at, steps: [(.steps // [])[] | {number: .number, name: .name, status: .status, conclusion: .conclusion, started_at: .started_at, completed_at: .completed_at}]}")
 	if err != nil {
 		if verbose {
 			fmt.Fprintln(os.Stderr, console.FormatVerboseMessage(fmt.Sprintf("Failed to fetch job details for run %d: %v", runID, err)))
@@ -178,6 +178,7 @@ type ListWorkflowRunsOptions struct {
 	ProcessedCount int    // number of runs already processed (for progress display)
 	TargetCount    int    // target number of runs to fetch (for progress display)
 	Verbose        bool   // enable verbose logging
+	Quiet          bool   // suppress the progress spinner (for callers that own the terminal, e.g. the dashboard)
 }
 
 // listWorkflowRunsWithPagination fetches workflow runs from GitHub Actions using the GitHub CLI.
@@ -232,7 +233,8 @@ func listWorkflowRunsWithPagination(opts ListWorkflowRunsOptions) ([]WorkflowRun
 	// Start spinner for network operation
 	spinnerMsg := fmt.Sprintf("Fetching workflow runs from GitHub... (%d / %d)", opts.ProcessedCount, opts.TargetCount)
 	spinner := console.NewSpinner(spinnerMsg)
-	if !opts.Verbose {
+	showSpinner := !opts.Verbose && !opts.Quiet
+	if showSpinner {
 		spinner.Start()
 	}
 
@@ -241,7 +243,7 @@ func listWorkflowRunsWithPagination(opts ListWorkflowRunsOptions) ([]WorkflowRun
 
 	if err != nil {
 		// Stop spinner on error
-		if !opts.Verbose {
+		if showSpinner {
 			spinner.Stop()
 		}
 
@@ -300,14 +302,14 @@ func listWorkflowRunsWithPagination(opts ListWorkflowRunsOptions) ([]WorkflowRun
 	var runs []WorkflowRun
 	if err := json.Unmarshal(output, &runs); err != nil {
 		// Stop spinner on parse error
-		if !opts.Verbose {
+		if showSpinner {
 			spinner.Stop()
 		}
 		return nil, 0, fmt.Errorf("failed to parse workflow runs: %w", err)
 	}
 
 	// Stop spinner silently - don't show per-iteration messages
-	if !opts.Verbose {
+	if showSpinner {
 		spinner.Stop()
 	}
 
@@ -252,6 +252,17 @@ type DownloadResult struct {
 
 // JobInfo represents basic information about a workflow job
 type JobInfo struct {
+	Name        string     `json:"name"`
+	Status      string     `json:"status"`
+	Conclusion  string     `json:"conclusion"`
+	StartedAt   time.Time  `json:"started_at,omitzero"`
+	CompletedAt time.Time  `json:"completed_at,omitzero"`
+	Steps       []StepInfo `json:"steps,omitempty"`
+}
+
+// StepInfo represents basic information about a single step within a job
+type StepInfo struct {
+	Number      int       `json:"number"`
 	Name        string    `json:"name"`
 	Status      string    `json:"status"`
 	Conclusion  string    `json:"conclusion"`
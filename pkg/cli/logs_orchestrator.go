@@ -52,6 +52,8 @@ type LogsDownloadOptions struct {
 	FirewallOnly      bool
 	NoFirewall        bool
 	Parse             bool
+	RecordTranscript  bool
+	EventsFormat      string
 	JSONOutput        bool
 	TimeoutMinutes    int
 	SummaryFile       string
@@ -59,6 +61,7 @@ type LogsDownloadOptions struct {
 	FilteredIntegrity bool
 	Train             bool
 	Format            string
+	CostReport        bool
 	ArtifactSets      []string
 	After             string
 }
@@ -81,6 +84,7 @@ func DownloadWorkflowLogs(ctx context.Context, opts LogsDownloadOptions) error {
 	firewallOnly := opts.FirewallOnly
 	noFirewall := opts.NoFirewall
 	parse := opts.Parse
+	eventsFormat := opts.EventsFormat
 	jsonOutput := opts.JSONOutput
 	timeoutMinutes := opts.TimeoutMinutes
 	summaryFile := opts.SummaryFile
@@ -88,9 +92,15 @@ func DownloadWorkflowLogs(ctx context.Context, opts LogsDownloadOptions) error {
 	filteredIntegrity := opts.FilteredIntegrity
 	train := opts.Train
 	format := opts.Format
+	costReport := opts.CostReport
 	artifactSets := opts.ArtifactSets
 	after := opts.After
 
+	var transcripts *transcriptRecorder
+	if opts.RecordTranscript {
+		transcripts = newTranscriptRecorder()
+	}
+
 	logsOrchestratorLog.Printf("Starting workflow log download: workflow=%s, count=%d, startDate=%s, endDate=%s, outputDir=%s, summaryFile=%s, safeOutputType=%s, filteredIntegrity=%v, train=%v, format=%s, artifactSets=%v, after=%s", workflowName, count, startDate, endDate, outputDir, summaryFile, safeOutputType, filteredIntegrity, train, format, artifactSets, after)
 
 	// Validate and resolve artifact sets into a concrete filter (list of artifact base names).
@@ -501,6 +511,16 @@ func DownloadWorkflowLogs(ctx context.Context, opts LogsDownloadOptions) error {
 					}
 				}
 
+				// If --record-transcript is set, write the replay transcript to .github/aw/transcripts
+				if transcripts != nil {
+					transcripts.record(result.LogsPath, run)
+				}
+
+				// If --events is set, export the normalized agent event stream to agent-events.jsonl
+				if eventsFormat != "" {
+					exportAgentEventsForRun(result.LogsPath, run)
+				}
+
 				// Stop processing this batch once we've collected enough runs.
 				if len(processedRuns) >= count {
 					break
@@ -597,13 +617,13 @@ func DownloadWorkflowLogs(ctx context.Context, opts LogsDownloadOptions) error {
 		}
 	}
 
-	return renderLogsOutput(processedRuns, outputDir, summaryFile, format, jsonOutput, toolGraph, train, continuation, verbose)
+	return renderLogsOutput(processedRuns, outputDir, summaryFile, format, repoOverride, jsonOutput, toolGraph, train, costReport, continuation, verbose)
 }
 
 // renderLogsOutput finalizes processedRuns and renders them in the appropriate output
-// format: JSON, console metrics table, or cross-run audit report (pretty/markdown).
+// format: JSON, console metrics table, cross-run audit report (pretty/markdown), or cost report.
 // continuation is optional and only set when a timeout was reached during a paginated download.
-func renderLogsOutput(processedRuns []ProcessedRun, outputDir, summaryFile, format string, jsonOutput, toolGraph, train bool, continuation *ContinuationData, verbose bool) error {
+func renderLogsOutput(processedRuns []ProcessedRun, outputDir, summaryFile, format, repoOverride string, jsonOutput, toolGraph, train, costReport bool, continuation *ContinuationData, verbose bool) error {
 	// Update MissingToolCount, MissingDataCount, and NoopCount in runs
 	for i := range processedRuns {
 		processedRuns[i].Run.MissingToolCount = len(processedRuns[i].MissingTools)
@@ -630,6 +650,11 @@ func renderLogsOutput(processedRuns []ProcessedRun, outputDir, summaryFile, form
 		}
 	}
 
+	// The cost report replaces the default metrics table; --format csv selects CSV export.
+	if costReport {
+		return renderCostReportOutput(logsData, format, repoOverride, jsonOutput)
+	}
+
 	// Render output based on format preference.
 	// When --format markdown or --format pretty is specified, generate a cross-run audit report
 	// instead of the default metrics table.
@@ -686,7 +711,7 @@ func renderLogsOutput(processedRuns []ProcessedRun, outputDir, summaryFile, form
 // DownloadWorkflowLogsFromStdin fetches and processes workflow run logs for runs
 // provided as IDs or URLs, bypassing the GitHub API run-discovery step.
 // This is used when the --stdin flag is passed to the logs command.
-func DownloadWorkflowLogsFromStdin(ctx context.Context, runURLs []string, outputDir, engine, repoOverride string, verbose, toolGraph, noStaged, firewallOnly, noFirewall bool, parse, jsonOutput bool, timeout int, summaryFile, safeOutputType string, filteredIntegrity, train bool, format string, artifactSets []string) error {
+func DownloadWorkflowLogsFromStdin(ctx context.Context, runURLs []string, outputDir, engine, repoOverride string, verbose, toolGraph, noStaged, firewallOnly, noFirewall bool, parse, recordTranscript, jsonOutput bool, timeout int, summaryFile, safeOutputType string, filteredIntegrity, train, costReport bool, format, eventsFormat string, artifactSets []string) error {
 	logsOrchestratorLog.Printf("Starting stdin log download: runs=%d, outputDir=%s", len(runURLs), outputDir)
 
 	if err := ValidateArtifactSets(artifactSets); err != nil {
@@ -700,6 +725,11 @@ func DownloadWorkflowLogsFromStdin(ctx context.Context, runURLs []string, output
 		}
 	}
 
+	var transcripts *transcriptRecorder
+	if recordTranscript {
+		transcripts = newTranscriptRecorder()
+	}
+
 	if err := ensureLogsGitignore(); err != nil {
 		logsOrchestratorLog.Printf("Failed to ensure logs .gitignore: %v", err)
 		if verbose {
@@ -978,6 +1008,14 @@ func DownloadWorkflowLogsFromStdin(ctx context.Context, runURLs []string, output
 				}
 			}
 		}
+
+		if transcripts != nil {
+			transcripts.record(result.LogsPath, run)
+		}
+
+		if eventsFormat != "" {
+			exportAgentEventsForRun(result.LogsPath, run)
+		}
 	}
 
 	if len(processedRuns) == 0 {
@@ -991,5 +1029,5 @@ func DownloadWorkflowLogsFromStdin(ctx context.Context, runURLs []string, output
 		return nil
 	}
 
-	return renderLogsOutput(processedRuns, outputDir, summaryFile, format, jsonOutput, toolGraph, train, nil, verbose)
+	return renderLogsOutput(processedRuns, outputDir, summaryFile, format, repoOverride, jsonOutput, toolGraph, train, costReport, nil, verbose)
 }
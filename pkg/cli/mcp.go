@@ -24,11 +24,13 @@ Available subcommands:
   - list-tools - List tools for a specific MCP server, or find workflows using it
   - inspect    - Inspect MCP servers and list available tools, resources, and roots
   - add        - Add an MCP server to an agentic workflow
+  - snapshot   - Record or verify snapshots of MCP server tools
 
 Examples:
   gh aw mcp list                              # List all workflows with MCP servers
   gh aw mcp inspect weekly-research           # Inspect MCP servers in workflow
   gh aw mcp add my-workflow tavily            # Add Tavily MCP server to workflow
+  gh aw mcp snapshot --verify                 # Fail if MCP tool descriptions or schemas drifted
   gh aw mcp inspect weekly-research --server github --tool create_issue  # Inspect specific tool`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return cmd.Help()
@@ -40,6 +42,7 @@ Examples:
 	cmd.AddCommand(NewMCPListSubcommand())
 	cmd.AddCommand(NewMCPListToolsSubcommand())
 	cmd.AddCommand(NewMCPInspectSubcommand())
+	cmd.AddCommand(NewMCPSnapshotSubcommand())
 
 	return cmd
 }
@@ -2,13 +2,13 @@ package cli
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
 	"strings"
 
 	"github.com/github/gh-aw/pkg/console"
-	"github.com/github/gh-aw/pkg/constants"
 	"github.com/github/gh-aw/pkg/logger"
 	"github.com/github/gh-aw/pkg/parser"
 	"github.com/github/gh-aw/pkg/stringutil"
@@ -34,12 +34,15 @@ func AddMCPTool(ctx context.Context, workflowFile string, mcpServerID string, re
 		fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("Adding MCP tool '%s' to workflow: %s", mcpServerID, console.ToRelativePath(workflowPath))))
 	}
 
-	// Create registry client
-	registryClient := NewMCPRegistryClient(registryURL)
+	// Create registry client (explicit --registry, aw.json registries, or the default registry)
+	registryClient, repoConfig, err := newMCPServerSource(registryURL)
+	if err != nil {
+		return err
+	}
 
 	// Search for the MCP server in the registry
 	if verbose {
-		fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("Searching for MCP server '%s' in registry: %s", mcpServerID, registryClient.registryURL)))
+		fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("Searching for MCP server '%s' in registry: %s", mcpServerID, registryClient.RegistryURL())))
 	}
 
 	mcpAddLog.Printf("Searching MCP registry for server: %s", mcpServerID)
@@ -119,11 +122,21 @@ func AddMCPTool(ctx context.Context, workflowFile string, mcpServerID string, re
 	}
 
 	// Create MCP tool configuration based on server info and preferences
-	mcpConfig, err := createMCPToolConfig(selectedServer, transportType, registryClient.registryURL, verbose)
+	serverRegistryURL := selectedServer.RegistryURL
+	if serverRegistryURL == "" {
+		serverRegistryURL = registryClient.RegistryURL()
+	}
+	mcpConfig, err := createMCPToolConfig(selectedServer, transportType, serverRegistryURL, verbose)
 	if err != nil {
 		return fmt.Errorf("failed to create MCP tool configuration: %w", err)
 	}
 
+	// Enforce the aw.json trust policy before modifying the workflow
+	if err := checkMCPServerTrust(repoConfig.TrustPolicy(), toolID, mcpConfig); err != nil {
+		mcpAddLog.Printf("MCP trust policy rejected server %s: %v", selectedServer.Name, err)
+		return err
+	}
+
 	// Add the tool to the workflow
 	if err := addToolToWorkflow(workflowPath, toolID, mcpConfig, verbose); err != nil {
 		return fmt.Errorf("failed to add tool to workflow: %w", err)
@@ -283,6 +296,25 @@ func createMCPToolConfig(server *MCPRegistryServerForProcessing, preferredTransp
 	return config, nil
 }
 
+// checkMCPServerTrust evaluates the trust policy against the generated tool
+// configuration with the same check the compiler runs, so that a server accepted
+// here is not rejected by the next compile. The configuration is decoded the way
+// it is read back from the workflow frontmatter.
+func checkMCPServerTrust(policy *workflow.MCPTrustPolicy, toolID string, toolConfig map[string]any) error {
+	if policy == nil {
+		return nil
+	}
+	data, err := json.Marshal(toolConfig["mcp"])
+	if err != nil {
+		return fmt.Errorf("failed to encode MCP tool configuration: %w", err)
+	}
+	var serverConfig map[string]any
+	if err := json.Unmarshal(data, &serverConfig); err != nil {
+		return fmt.Errorf("failed to decode MCP tool configuration: %w", err)
+	}
+	return workflow.CheckMCPServerTrust(policy, toolID, serverConfig)
+}
+
 // addToolToWorkflow adds a tool configuration to the workflow file
 func addToolToWorkflow(workflowPath string, toolID string, toolConfig map[string]any, verbose bool) error {
 	// Use frontmatter helper to update the workflow file
@@ -315,6 +347,11 @@ func NewMCPAddSubcommand() *cobra.Command {
 This command searches the MCP registry for the specified server, adds it to the workflow's tools section,
 and automatically compiles the workflow. If the server already exists, the command will fail.
 
+Registries are taken from the "mcp.registries" list in .github/workflows/aw.json when present
+(private registries and offline JSON mirror files, merged by priority), otherwise the default
+public registry is used. The "mcp.trust" policy in aw.json (allowed publishers, pinned versions
+or digests, denied servers) is enforced before the server is added.
+
 When called with no arguments, it will show a list of available MCP servers from the registry.
 
 The workflow-id-or-file can be:
@@ -339,10 +376,6 @@ The command will:
 
 			// If no arguments provided, show list of available servers
 			if len(args) == 0 {
-				// Use default registry URL if not provided
-				if registryURL == "" {
-					registryURL = string(constants.DefaultMCPRegistryURL)
-				}
 				return listAvailableServers(cmd.Context(), registryURL, verbose)
 			}
 
@@ -359,7 +392,7 @@ The command will:
 		},
 	}
 
-	cmd.Flags().StringVar(&registryURL, "registry", "", "MCP registry URL (default: https://api.mcp.github.com/v0.1)")
+	cmd.Flags().StringVar(&registryURL, "registry", "", "MCP registry URL (default: registries from aw.json, or https://api.mcp.github.com/v0.1)")
 	cmd.Flags().StringVar(&transportType, "transport", "", "Preferred transport type (stdio, http, docker)")
 	cmd.Flags().StringVar(&customToolID, "tool-id", "", "Custom tool ID to use in the workflow (default: uses server ID)")
 
@@ -11,7 +11,6 @@ import (
 
 	"github.com/github/gh-aw/pkg/console"
 	"github.com/github/gh-aw/pkg/logger"
-	"github.com/github/gh-aw/pkg/parser"
 	"github.com/github/gh-aw/pkg/workflow"
 	"github.com/spf13/cobra"
 )
@@ -49,12 +48,7 @@ func InspectWorkflowMCP(workflowFile string, serverFilter string, toolFilter str
 		fmt.Fprintln(os.Stderr, console.FormatInfoMessage("Inspecting MCP servers in: "+workflowPath))
 	}
 
-	// Use the compiler to parse the workflow file
-	// This automatically handles imports, merging, and validation
-	compiler := workflow.NewCompiler(
-		workflow.WithVerbose(verbose),
-	)
-	workflowData, err := compiler.ParseWorkflowFile(workflowPath)
+	workflowData, mcpConfigs, err := loadResolvedWorkflowMCPConfigs(workflowPath, serverFilter, verbose)
 	if err != nil {
 		// Handle shared workflow error separately (not a fatal error for inspection)
 		if errors.As(err, new(*workflow.SharedWorkflowError)) {
@@ -68,36 +62,10 @@ func InspectWorkflowMCP(workflowFile string, serverFilter string, toolFilter str
 			return nil
 		}
 
-		errMsg := fmt.Sprintf("failed to parse workflow file: %v", err)
-		fmt.Fprintln(os.Stderr, console.FormatErrorMessage(errMsg))
-		return fmt.Errorf("failed to parse workflow file: %w", err)
-	}
-
-	mcpInspectLog.Printf("Workflow parsed: name=%s, has_mcp_scripts=%t",
-		workflowData.Name, workflowData.MCPScripts != nil)
-
-	if verbose {
-		fmt.Fprintln(os.Stderr, console.FormatSuccessMessage("Workflow parsed successfully"))
-	}
-
-	// Build frontmatter map from WorkflowData for MCP extraction
-	// This includes all merged imports and tools
-	frontmatterForMCP := buildFrontmatterFromWorkflowData(workflowData)
-
-	// Extract MCP configurations from the merged frontmatter
-	mcpConfigs, err := parser.ExtractMCPConfigurations(frontmatterForMCP, serverFilter)
-	if err != nil {
-		errMsg := fmt.Sprintf("failed to extract MCP configurations: %v", err)
-		fmt.Fprintln(os.Stderr, console.FormatErrorMessage(errMsg))
-		return fmt.Errorf("failed to extract MCP configurations: %w", err)
+		fmt.Fprintln(os.Stderr, console.FormatErrorMessage(err.Error()))
+		return err
 	}
 
-	mcpInspectLog.Printf("Extracted %d MCP configs (server_filter=%q)", len(mcpConfigs), serverFilter)
-
-	// Filter out safe-outputs MCP servers for inspection
-	mcpConfigs = filterOutSafeOutputs(mcpConfigs)
-	mcpInspectLog.Printf("After filtering safe-outputs: %d MCP configs remain", len(mcpConfigs))
-
 	// Start mcp-scripts server if present
 	var mcpScriptsServerCmd *exec.Cmd
 	var mcpScriptsTmpDir string
@@ -28,6 +28,9 @@ type MCPRegistryServerForProcessing struct {
 	Transport            string                `json:"transport"`
 	Config               map[string]any        `json:"config"`
 	EnvironmentVariables []EnvironmentVariable `json:"environment_variables"`
+	Version              string                `json:"version,omitempty"`
+	Digest               string                `json:"digest,omitempty"`
+	RegistryURL          string                `json:"registry_url,omitempty"`
 }
 
 // MCPRegistryClient handles communication with MCP registries
@@ -125,6 +128,33 @@ func (c *MCPRegistryClient) SearchServers(ctx context.Context, query string) ([]
 	// Stop spinner with success message
 	spinner.StopWithMessage(fmt.Sprintf("✓ Fetched %d servers from registry", len(response.Servers)))
 
+	servers := processRegistryServers(response, c.registryURL)
+
+	// Apply local filtering if query is provided
+	if query != "" {
+		filteredServers := filterRegistryServers(servers, query)
+		mcpRegistryLog.Printf("Filtered to %d servers matching query", len(filteredServers))
+		return filteredServers, nil
+	}
+
+	// Validate minimum server count for production registry
+	// Note: This validation helps detect issues with the registry API, but we make it more lenient
+	// to accommodate potential changes in the registry size
+	if strings.Contains(c.registryURL, "api.mcp.github.com") && len(servers) < 10 {
+		return nil, fmt.Errorf("registry validation failed: expected at least 10 servers from production registry, got %d\nThis may indicate an issue with the registry API or access restrictions", len(servers))
+	}
+
+	return servers, nil
+}
+
+// RegistryURL returns the base URL of the registry
+func (c *MCPRegistryClient) RegistryURL() string {
+	return c.registryURL
+}
+
+// processRegistryServers converts a registry server list into the flattened
+// format, dropping inactive servers and recording the source registry URL
+func processRegistryServers(response ServerListResponse, registryURL string) []MCPRegistryServerForProcessing {
 	// Convert servers to flattened format and filter by status
 	mcpRegistryLog.Printf("Processing %d servers from registry", len(response.Servers))
 	servers := make([]MCPRegistryServerForProcessing, 0, len(response.Servers))
@@ -141,6 +171,8 @@ func (c *MCPRegistryClient) SearchServers(ctx context.Context, query string) ([]
 		processedServer := MCPRegistryServerForProcessing{
 			Name:        server.Name,
 			Description: server.Description,
+			Version:     server.Version,
+			RegistryURL: registryURL,
 		}
 
 		// Set repository URL if available
@@ -163,6 +195,14 @@ func (c *MCPRegistryClient) SearchServers(ctx context.Context, query string) ([]
 			// Set command from package identifier
 			processedServer.Command = pkg.Identifier
 
+			// Prefer the package version and record its digest for trust policy pins
+			if pkg.Version != "" {
+				processedServer.Version = pkg.Version
+			}
+			if pkg.FileSHA256 != "" {
+				processedServer.Digest = "sha256:" + pkg.FileSHA256
+			}
+
 			// Set runtime hint (used for the actual command execution)
 			processedServer.RuntimeHint = pkg.RuntimeHint
 
@@ -233,29 +273,22 @@ func (c *MCPRegistryClient) SearchServers(ctx context.Context, query string) ([]
 		servers = append(servers, processedServer)
 	}
 
-	// Apply local filtering if query is provided
-	if query != "" {
-		var filteredServers []MCPRegistryServerForProcessing
-		queryLower := strings.ToLower(query)
-
-		for _, server := range servers {
-			// Check if query matches name or description (case-insensitive)
-			if strings.Contains(strings.ToLower(server.Name), queryLower) ||
-				strings.Contains(strings.ToLower(server.Description), queryLower) {
-				filteredServers = append(filteredServers, server)
-			}
-		}
-
-		mcpRegistryLog.Printf("Filtered to %d servers matching query", len(filteredServers))
-		return filteredServers, nil
-	}
+	return servers
+}
 
-	// Validate minimum server count for production registry
-	// Note: This validation helps detect issues with the registry API, but we make it more lenient
-	// to accommodate potential changes in the registry size
-	if strings.Contains(c.registryURL, "api.mcp.github.com") && len(servers) < 10 {
-		return nil, fmt.Errorf("registry validation failed: expected at least 10 servers from production registry, got %d\nThis may indicate an issue with the registry API or access restrictions", len(servers))
+// filterRegistryServers returns the servers whose name or description contains
+// the query (case-insensitive)
+func filterRegistryServers(servers []MCPRegistryServerForProcessing, query string) []MCPRegistryServerForProcessing {
+	var filteredServers []MCPRegistryServerForProcessing
+	queryLower := strings.ToLower(query)
+
+	for _, server := range servers {
+		// Check if query matches name or description (case-insensitive)
+		if strings.Contains(strings.ToLower(server.Name), queryLower) ||
+			strings.Contains(strings.ToLower(server.Description), queryLower) {
+			filteredServers = append(filteredServers, server)
+		}
 	}
 
-	return servers, nil
+	return filteredServers
 }
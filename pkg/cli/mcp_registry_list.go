@@ -15,11 +15,14 @@ var mcpRegistryListLog = logger.New("cli:mcp_registry_list")
 func listAvailableServers(ctx context.Context, registryURL string, verbose bool) error {
 	mcpRegistryListLog.Printf("Listing available MCP servers: registry_url=%s", registryURL)
 	// Create registry client
-	registryClient := NewMCPRegistryClient(registryURL)
+	registryClient, _, err := newMCPServerSource(registryURL)
+	if err != nil {
+		return err
+	}
 
 	// Search for all servers (empty query)
 	if verbose {
-		fmt.Fprintln(os.Stderr, console.FormatInfoMessage("Fetching available MCP servers from registry: "+registryClient.registryURL))
+		fmt.Fprintln(os.Stderr, console.FormatInfoMessage("Fetching available MCP servers from registry: "+registryClient.RegistryURL()))
 	}
 
 	servers, err := registryClient.SearchServers(ctx, "")
@@ -71,7 +74,7 @@ func listAvailableServers(ctx context.Context, registryURL string, verbose bool)
 
 	// Create and render table
 	tableConfig := console.TableConfig{
-		Title:     "MCP registry: " + registryClient.registryURL,
+		Title:     "MCP registry: " + registryClient.RegistryURL(),
 		Headers:   headers,
 		Rows:      rows,
 		ShowTotal: true,
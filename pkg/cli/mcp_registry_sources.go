@@ -0,0 +1,167 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/gitutil"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/workflow"
+)
+
+var mcpRegistrySourcesLog = logger.New("cli:mcp_registry_sources")
+
+// mcpServerSource is a registry that can be searched for MCP servers
+type mcpServerSource interface {
+	SearchServers(ctx context.Context, query string) ([]MCPRegistryServerForProcessing, error)
+	RegistryURL() string
+}
+
+// MCPRegistryFileClient serves MCP servers from an offline JSON mirror file
+// stored in the registry /servers response format
+type MCPRegistryFileClient struct {
+	path string
+}
+
+// NewMCPRegistryFileClient creates a registry client backed by a mirror file
+func NewMCPRegistryFileClient(path string) *MCPRegistryFileClient {
+	mcpRegistrySourcesLog.Printf("Creating MCP registry file client: path=%s", path)
+	return &MCPRegistryFileClient{path: path}
+}
+
+// RegistryURL returns a file: URL identifying the mirror
+func (c *MCPRegistryFileClient) RegistryURL() string {
+	return "file:" + filepath.ToSlash(c.path)
+}
+
+// SearchServers reads the mirror file and filters servers locally
+func (c *MCPRegistryFileClient) SearchServers(_ context.Context, query string) ([]MCPRegistryServerForProcessing, error) {
+	data, err := os.ReadFile(filepath.Clean(c.path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read MCP registry mirror %s: %w", c.path, err)
+	}
+
+	var response ServerListResponse
+	if err := json.Unmarshal(data, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse MCP registry mirror %s: %w", c.path, err)
+	}
+
+	servers := processRegistryServers(response, c.RegistryURL())
+	if query != "" {
+		servers = filterRegistryServers(servers, query)
+	}
+	mcpRegistrySourcesLog.Printf("Mirror %s returned %d servers for query %q", c.path, len(servers), query)
+	return servers, nil
+}
+
+// MultiRegistryClient queries several registries in priority order and merges
+// the results. When several registries publish a server with the same name,
+// the entry from the highest-priority registry wins.
+type MultiRegistryClient struct {
+	sources []mcpServerSource
+}
+
+// NewMultiRegistryClient creates a client for the given registry sources.
+// File paths are resolved relative to baseDir.
+func NewMultiRegistryClient(sources []workflow.MCPRegistrySource, baseDir string) *MultiRegistryClient {
+	client := &MultiRegistryClient{}
+	for _, source := range sources {
+		if source.File != "" {
+			path := source.File
+			if !filepath.IsAbs(path) {
+				path = filepath.Join(baseDir, path)
+			}
+			client.sources = append(client.sources, NewMCPRegistryFileClient(path))
+			continue
+		}
+		client.sources = append(client.sources, NewMCPRegistryClient(source.URL))
+	}
+	return client
+}
+
+// RegistryURL returns the URLs of all configured registries
+func (m *MultiRegistryClient) RegistryURL() string {
+	urls := make([]string, 0, len(m.sources))
+	for _, source := range m.sources {
+		urls = append(urls, source.RegistryURL())
+	}
+	return strings.Join(urls, ", ")
+}
+
+// SearchServers searches every registry and merges the results by server name.
+// A registry that fails is reported as a warning as long as at least one
+// registry succeeds; when every registry fails the errors are returned.
+func (m *MultiRegistryClient) SearchServers(ctx context.Context, query string) ([]MCPRegistryServerForProcessing, error) {
+	var merged []MCPRegistryServerForProcessing
+	seen := make(map[string]bool)
+	var errs []error
+	succeeded := 0
+
+	for _, source := range m.sources {
+		servers, err := source.SearchServers(ctx, query)
+		if err != nil {
+			mcpRegistrySourcesLog.Printf("Registry %s failed: %v", source.RegistryURL(), err)
+			errs = append(errs, fmt.Errorf("%s: %w", source.RegistryURL(), err))
+			continue
+		}
+		succeeded++
+		for _, server := range servers {
+			if seen[server.Name] {
+				mcpRegistrySourcesLog.Printf("Skipping %s from %s: shadowed by higher-priority registry", server.Name, source.RegistryURL())
+				continue
+			}
+			seen[server.Name] = true
+			merged = append(merged, server)
+		}
+	}
+
+	if succeeded == 0 && len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	for _, err := range errs {
+		fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("MCP registry unavailable, results may be incomplete: %v", err)))
+	}
+
+	mcpRegistrySourcesLog.Printf("Merged %d servers from %d registries", len(merged), succeeded)
+	return merged, nil
+}
+
+// newMCPServerSource returns the registry client for an MCP command. An explicit
+// --registry URL always wins; otherwise the registries configured in aw.json are
+// used, falling back to the default public registry.
+func newMCPServerSource(registryURL string) (mcpServerSource, *workflow.RepoConfig, error) {
+	repoConfig, gitRoot, err := loadRepoConfigForMCP()
+	if err != nil {
+		return nil, nil, err
+	}
+	if registryURL != "" {
+		return NewMCPRegistryClient(registryURL), repoConfig, nil
+	}
+	if sources := repoConfig.RegistrySources(); len(sources) > 0 {
+		mcpRegistrySourcesLog.Printf("Using %d registries from %s", len(sources), workflow.RepoConfigFileName)
+		return NewMultiRegistryClient(sources, gitRoot), repoConfig, nil
+	}
+	return NewMCPRegistryClient(""), repoConfig, nil
+}
+
+// loadRepoConfigForMCP loads aw.json from the current repository. Running outside
+// a repository yields an empty config; an invalid aw.json is an error so that a
+// broken trust policy is never silently ignored.
+func loadRepoConfigForMCP() (*workflow.RepoConfig, string, error) {
+	gitRoot, err := gitutil.FindGitRoot()
+	if err != nil {
+		mcpRegistrySourcesLog.Printf("Not in a git repository, using default MCP registry: %v", err)
+		return &workflow.RepoConfig{}, "", nil
+	}
+	repoConfig, err := workflow.LoadRepoConfig(gitRoot)
+	if err != nil {
+		return nil, gitRoot, err
+	}
+	return repoConfig, gitRoot, nil
+}
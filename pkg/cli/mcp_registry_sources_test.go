@@ -0,0 +1,141 @@
+//go:build !integration
+
+package cli
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/goccy/go-yaml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// registryServerListJSON builds a minimal registry /servers response with one
+// npm package per server name.
+func registryServerListJSON(version string, names ...string) string {
+	entries := ""
+	for i, name := range names {
+		if i > 0 {
+			entries += ","
+		}
+		entries += fmt.Sprintf(`{
+			"server": {
+				"name": %q,
+				"description": "server %s",
+				"version": %q,
+				"packages": [{"registryType": "npm", "identifier": "pkg", "version": %q, "fileSha256": "abc", "transport": {"type": "stdio"}}]
+			}
+		}`, name, name, version, version)
+	}
+	return `{"servers": [` + entries + `]}`
+}
+
+func TestMCPRegistryFileClient_SearchServers(t *testing.T) {
+	mirror := filepath.Join(t.TempDir(), "mirror.json")
+	require.NoError(t, os.WriteFile(mirror, []byte(registryServerListJSON("1.0.0", "io.github.acme/notes", "io.github.acme/tickets")), 0o600), "failed to write mirror")
+
+	client := NewMCPRegistryFileClient(mirror)
+	servers, err := client.SearchServers(context.Background(), "tickets")
+	require.NoError(t, err, "mirror search should succeed")
+	require.Len(t, servers, 1, "query should filter mirror entries")
+	assert.Equal(t, "io.github.acme/tickets", servers[0].Name, "matching server should be returned")
+	assert.Equal(t, "1.0.0", servers[0].Version, "package version should be recorded")
+	assert.Equal(t, "sha256:abc", servers[0].Digest, "package digest should be recorded")
+	assert.Equal(t, client.RegistryURL(), servers[0].RegistryURL, "source registry should be recorded")
+}
+
+func TestMultiRegistryClient_MergesByPriority(t *testing.T) {
+	internal := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(registryServerListJSON("2.0.0", "io.github.acme/notes")))
+	}))
+	defer internal.Close()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "mirror.json"), []byte(registryServerListJSON("1.0.0", "io.github.acme/notes", "io.github.acme/tickets")), 0o600), "failed to write mirror")
+
+	client := NewMultiRegistryClient([]workflow.MCPRegistrySource{
+		{Name: "internal", URL: internal.URL},
+		{Name: "mirror", File: "mirror.json"},
+	}, dir)
+
+	servers, err := client.SearchServers(context.Background(), "")
+	require.NoError(t, err, "merged search should succeed")
+	require.Len(t, servers, 2, "duplicate server names should be merged")
+	assert.Equal(t, "io.github.acme/notes", servers[0].Name, "first registry result should come first")
+	assert.Equal(t, "2.0.0", servers[0].Version, "higher-priority registry should win for duplicate names")
+	assert.Equal(t, internal.URL, servers[0].RegistryURL, "winning registry should be recorded")
+	assert.Equal(t, "io.github.acme/tickets", servers[1].Name, "unique mirror entries should be included")
+}
+
+func TestMultiRegistryClient_SkipsFailingRegistry(t *testing.T) {
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "mirror.json"), []byte(registryServerListJSON("1.0.0", "io.github.acme/notes")), 0o600), "failed to write mirror")
+
+	client := NewMultiRegistryClient([]workflow.MCPRegistrySource{
+		{URL: failing.URL},
+		{File: "mirror.json"},
+	}, dir)
+	servers, err := client.SearchServers(context.Background(), "")
+	require.NoError(t, err, "a failing registry should not fail the search when another succeeds")
+	assert.Len(t, servers, 1, "results from healthy registries should be returned")
+
+	client = NewMultiRegistryClient([]workflow.MCPRegistrySource{{URL: failing.URL}}, dir)
+	_, err = client.SearchServers(context.Background(), "")
+	assert.Error(t, err, "search should fail when every registry fails")
+}
+
+func TestCheckMCPServerTrust(t *testing.T) {
+	policy := &workflow.MCPTrustPolicy{
+		AllowedPublishers: []string{"io.github.acme"},
+		Pinned:            map[string]string{"io.github.acme/notes": "1.2.3"},
+	}
+	toolConfig := func(name string, args ...string) map[string]any {
+		config, err := createMCPToolConfig(&MCPRegistryServerForProcessing{Name: name, Transport: "stdio", Command: "npx", Args: args, Version: "1.2.3", Digest: "sha256:abc"},
+			"", "https://registry.example/v0", false)
+		require.NoError(t, err)
+		return config
+	}
+
+	require.NoError(t, checkMCPServerTrust(policy, "notes", toolConfig("io.github.acme/notes", "-y", "@acme/notes@1.2.3")),
+		"a package pinned in the generated configuration should satisfy the policy")
+	require.Error(t, checkMCPServerTrust(policy, "notes", toolConfig("io.github.acme/notes", "-y", "@acme/notes")),
+		"the registry version alone should not satisfy the pin, since compile does not see it")
+	require.Error(t, checkMCPServerTrust(policy, "notes", toolConfig("io.github.other/notes", "-y", "@other/notes@1.2.3")),
+		"unlisted publisher should be rejected")
+	assert.NoError(t, checkMCPServerTrust(nil, "notes", toolConfig("io.github.other/notes")), "nil policy should allow everything")
+}
+
+func TestCheckMCPServerTrust_MatchesCompile(t *testing.T) {
+	policy := &workflow.MCPTrustPolicy{
+		AllowedPublishers: []string{"io.github.acme"},
+		Pinned:            map[string]string{"io.github.acme/notes": "1.2.3"},
+	}
+	for _, args := range [][]string{{"-y", "@acme/notes@1.2.3"}, {"-y", "@acme/notes@2.0.0"}} {
+		toolConfig, err := createMCPToolConfig(&MCPRegistryServerForProcessing{Name: "io.github.acme/notes", Transport: "stdio", Command: "npx", Args: args},
+			"", "https://registry.example/v0", false)
+		require.NoError(t, err)
+
+		// The compiler reads the server configuration back from YAML frontmatter
+		data, err := yaml.Marshal(toolConfig["mcp"])
+		require.NoError(t, err)
+		var compiled map[string]any
+		require.NoError(t, yaml.Unmarshal(data, &compiled))
+
+		addErr := checkMCPServerTrust(policy, "notes", toolConfig)
+		compileErr := workflow.CheckMCPServerTrust(policy, "notes", compiled)
+		assert.Equal(t, compileErr == nil, addErr == nil, "mcp add and compile should agree for args %v", args)
+	}
+}
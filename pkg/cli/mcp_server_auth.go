@@ -0,0 +1,526 @@
+// This file provides authentication, per-tool authorization, rate limiting and
+// audit logging for the HTTP transport of the MCP server.
+//
+// # Token File
+//
+// The --auth-file flag points to a JSON file mapping callers to roles:
+//
+//	{
+//	  "rate_limit": 60,
+//	  "tokens": [
+//	    {"name": "alice", "sha256": "<hex sha256 of the token>", "role": "privileged"},
+//	    {"name": "ci-bot", "sha256": "<hex sha256 of the token>", "role": "readonly", "repos": ["octo-org/*"], "rate_limit": 10}
+//	  ],
+//	  "clients": [
+//	    {"name": "build-box", "common_name": "build-box.internal", "role": "readonly"}
+//	  ]
+//	}
+//
+// Tokens are stored as SHA-256 hashes so the file never holds a usable secret.
+// Callers authenticate with an Authorization: Bearer header, or with a client
+// certificate whose common name is listed under clients when the server runs
+// with --client-ca (mutual TLS).
+//
+// The readonly role may call the read-only tools (status, compile, checks,
+// mcp-inspect); the privileged role may call every tool. When repos is set,
+// tool calls are limited to repositories matching one of its glob patterns.
+// rate_limit is the number of tool calls allowed per minute for the caller.
+//
+// Every tool call is written to the audit log as one JSON object per line with
+// the caller identity, the tool, the target repositories and the decision.
+
+package cli
+
+import (
+	"cmp"
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/github/gh-aw/pkg/constants"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/parser"
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+var mcpAuthLog = logger.New("mcp:auth")
+
+// MCP server roles accepted in the token file.
+const (
+	MCPRoleReadOnly   = "readonly"
+	MCPRolePrivileged = "privileged"
+)
+
+const (
+	// mcpCallerHeader carries the authenticated caller from the HTTP handler to
+	// the MCP middleware. Any value sent by the client is removed first.
+	mcpCallerHeader = "X-Gh-Aw-Mcp-Caller"
+
+	// defaultMCPRateLimit is the number of tool calls per minute allowed when
+	// the token file does not set a rate limit.
+	defaultMCPRateLimit = 60
+
+	mcpAuthMethodBearer = "bearer"
+	mcpAuthMethodMTLS   = "mtls"
+)
+
+// mcpReadOnlyTools lists the tools available to the readonly role.
+var mcpReadOnlyTools = map[string]bool{
+	"status":      true,
+	"compile":     true,
+	"checks":      true,
+	"mcp-inspect": true,
+}
+
+// mcpRepoScopedArgs lists the tool arguments that may name a target repository,
+// either as owner/repo or as a GitHub URL.
+var mcpRepoScopedArgs = []string{"repo", "pr_number", "run_id_or_url", "run_ids_or_urls", "base_run_id", "compare_run_ids"}
+
+var (
+	mcpTokenHashPattern = regexp.MustCompile(`^[0-9a-f]{64}$`)
+	mcpRepoSlugPattern  = regexp.MustCompile(`^[A-Za-z0-9_.-]+/[A-Za-z0-9_.-]+$`)
+)
+
+// mcpAuthFile is the on-disk format of the --auth-file token file.
+type mcpAuthFile struct {
+	RateLimit int                `json:"rate_limit,omitempty"`
+	Tokens    []mcpAuthPrincipal `json:"tokens,omitempty"`
+	Clients   []mcpAuthPrincipal `json:"clients,omitempty"`
+}
+
+// mcpAuthPrincipal is a caller of the HTTP MCP server.
+type mcpAuthPrincipal struct {
+	Name       string   `json:"name"`
+	SHA256     string   `json:"sha256,omitempty"`
+	CommonName string   `json:"common_name,omitempty"`
+	Role       string   `json:"role"`
+	Repos      []string `json:"repos,omitempty"`
+	RateLimit  int      `json:"rate_limit,omitempty"`
+
+	method string
+}
+
+// allowsTool reports whether the principal's role may call the tool.
+func (p *mcpAuthPrincipal) allowsTool(tool string) bool {
+	return p.Role == MCPRolePrivileged || mcpReadOnlyTools[tool]
+}
+
+// allowsRepo reports whether the principal may act on the repository.
+func (p *mcpAuthPrincipal) allowsRepo(repo string) bool {
+	if len(p.Repos) == 0 {
+		return true
+	}
+	for _, pattern := range p.Repos {
+		if matched, err := path.Match(strings.ToLower(pattern), strings.ToLower(repo)); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+// mcpAuthConfig holds the callers, rate limiter and audit log of the server.
+type mcpAuthConfig struct {
+	tokens      map[string]*mcpAuthPrincipal // keyed by token hash
+	clients     map[string]*mcpAuthPrincipal // keyed by certificate common name
+	principals  map[string]*mcpAuthPrincipal // keyed by name
+	defaultRepo string
+	limiter     *mcpRateLimiter
+	audit       *mcpAuditLogger
+}
+
+// loadMCPAuthConfig reads and validates the token file.
+func loadMCPAuthConfig(authFile string) (*mcpAuthConfig, error) {
+	mcpAuthLog.Printf("Loading MCP auth file: %s", authFile)
+	data, err := os.ReadFile(filepath.Clean(authFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read auth file: %w", err)
+	}
+	cfg, err := parseMCPAuthConfig(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid auth file %s: %w", authFile, err)
+	}
+	return cfg, nil
+}
+
+// parseMCPAuthConfig parses the token file content.
+func parseMCPAuthConfig(data []byte) (*mcpAuthConfig, error) {
+	var file mcpAuthFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON: %w", err)
+	}
+	if file.RateLimit < 0 {
+		return nil, errors.New("rate_limit must not be negative")
+	}
+	if len(file.Tokens) == 0 && len(file.Clients) == 0 {
+		return nil, errors.New("at least one token or client must be defined")
+	}
+
+	cfg := &mcpAuthConfig{
+		tokens:     make(map[string]*mcpAuthPrincipal),
+		clients:    make(map[string]*mcpAuthPrincipal),
+		principals: make(map[string]*mcpAuthPrincipal),
+		limiter:    newMCPRateLimiter(),
+	}
+	add := func(p mcpAuthPrincipal, method string) error {
+		if p.Name == "" {
+			return errors.New("every token and client must have a name")
+		}
+		if _, exists := cfg.principals[p.Name]; exists {
+			return fmt.Errorf("name %q is defined more than once", p.Name)
+		}
+		if p.Role != MCPRoleReadOnly && p.Role != MCPRolePrivileged {
+			return fmt.Errorf("%s: role must be %q or %q, got %q", p.Name, MCPRoleReadOnly, MCPRolePrivileged, p.Role)
+		}
+		if p.RateLimit < 0 {
+			return fmt.Errorf("%s: rate_limit must not be negative", p.Name)
+		}
+		if p.RateLimit == 0 {
+			p.RateLimit = cmp.Or(file.RateLimit, defaultMCPRateLimit)
+		}
+		for _, pattern := range p.Repos {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("%s: invalid repos pattern %q: %w", p.Name, pattern, err)
+			}
+		}
+		p.method = method
+		cfg.principals[p.Name] = &p
+		return nil
+	}
+
+	for _, token := range file.Tokens {
+		hash := strings.ToLower(token.SHA256)
+		if !mcpTokenHashPattern.MatchString(hash) {
+			return nil, fmt.Errorf("%s: sha256 must be the 64-character hex SHA-256 of the token", token.Name)
+		}
+		if err := add(token, mcpAuthMethodBearer); err != nil {
+			return nil, err
+		}
+		if _, exists := cfg.tokens[hash]; exists {
+			return nil, fmt.Errorf("%s: token is already assigned to another name", token.Name)
+		}
+		cfg.tokens[hash] = cfg.principals[token.Name]
+	}
+	for _, client := range file.Clients {
+		if client.CommonName == "" {
+			return nil, fmt.Errorf("%s: clients must set common_name", client.Name)
+		}
+		if err := add(client, mcpAuthMethodMTLS); err != nil {
+			return nil, err
+		}
+		if _, exists := cfg.clients[client.CommonName]; exists {
+			return nil, fmt.Errorf("%s: common name %q is already assigned to another name", client.Name, client.CommonName)
+		}
+		cfg.clients[client.CommonName] = cfg.principals[client.Name]
+	}
+
+	mcpAuthLog.Printf("Loaded %d tokens and %d clients", len(cfg.tokens), len(cfg.clients))
+	return cfg, nil
+}
+
+// authenticate resolves the caller of an HTTP request. It returns the HTTP
+// status and message to send when the caller cannot be authenticated.
+func (c *mcpAuthConfig) authenticate(r *http.Request) (*mcpAuthPrincipal, int, string) {
+	if authHeader := r.Header.Get("Authorization"); authHeader != "" {
+		scheme, token, found := strings.Cut(authHeader, " ")
+		if !found || !strings.EqualFold(scheme, "bearer") || strings.TrimSpace(token) == "" {
+			return nil, http.StatusUnauthorized, "malformed Authorization header"
+		}
+		sum := sha256.Sum256([]byte(strings.TrimSpace(token)))
+		hash := hex.EncodeToString(sum[:])
+		var match *mcpAuthPrincipal
+		// Compare against every hash so the time taken does not reveal a match.
+		for known, principal := range c.tokens {
+			if subtle.ConstantTimeCompare([]byte(known), []byte(hash)) == 1 {
+				match = principal
+			}
+		}
+		if match == nil {
+			return nil, http.StatusUnauthorized, "invalid token"
+		}
+		return match, 0, ""
+	}
+
+	// VerifiedChains is only populated when the certificate was verified
+	// against the --client-ca pool.
+	if r.TLS != nil && len(r.TLS.VerifiedChains) > 0 && len(r.TLS.PeerCertificates) > 0 {
+		commonName := r.TLS.PeerCertificates[0].Subject.CommonName
+		if principal, ok := c.clients[commonName]; ok {
+			return principal, 0, ""
+		}
+		return nil, http.StatusForbidden, "client certificate is not authorized"
+	}
+
+	return nil, http.StatusUnauthorized, "missing bearer token"
+}
+
+// mcpAuthHandler authenticates every HTTP request before it reaches the MCP
+// handler and records the caller in the mcpCallerHeader request header.
+func mcpAuthHandler(cfg *mcpAuthConfig, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.Header.Del(mcpCallerHeader)
+		principal, status, message := cfg.authenticate(r)
+		if principal == nil {
+			mcpAuthLog.Printf("Rejected request from %s: %s", sanitizeForLog(r.RemoteAddr), message)
+			cfg.audit.record(mcpAuditEntry{
+				Event:      "authentication",
+				RemoteAddr: r.RemoteAddr,
+				Allowed:    false,
+				Reason:     message,
+			})
+			if status == http.StatusUnauthorized {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="gh-aw"`)
+			}
+			http.Error(w, message, status)
+			return
+		}
+		r.Header.Set(mcpCallerHeader, principal.Name)
+		next.ServeHTTP(w, r)
+	})
+}
+
+// mcpAuthorizationMiddleware enforces roles, repository scopes and rate limits
+// on tool calls and writes every tool call to the audit log.
+func mcpAuthorizationMiddleware(cfg *mcpAuthConfig) mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			if method != "tools/call" {
+				return next(ctx, method, req)
+			}
+
+			start := time.Now()
+			entry := mcpAuditEntry{Event: "tool_call", Tool: extractMCPToolName(req)}
+			principal := cfg.caller(req)
+			if principal == nil {
+				entry.Reason = "unauthenticated"
+				cfg.audit.record(entry)
+				return nil, newMCPError(jsonrpc.CodeInvalidRequest, "permission denied: unauthenticated", nil)
+			}
+			entry.Caller = principal.Name
+			entry.Auth = principal.method
+			entry.Role = principal.Role
+			entry.Repos = mcpRequestRepos(req, cfg.defaultRepo)
+
+			deny := func(reason string, data map[string]any) (mcp.Result, error) {
+				entry.Reason = reason
+				cfg.audit.record(entry)
+				mcpAuthLog.Printf("Denied %s for %s: %s", entry.Tool, principal.Name, reason)
+				return nil, newMCPError(jsonrpc.CodeInvalidRequest, "permission denied: "+reason, data)
+			}
+
+			if !principal.allowsTool(entry.Tool) {
+				return deny(fmt.Sprintf("role %q cannot call the %s tool", principal.Role, entry.Tool), map[string]any{"caller": principal.Name, "role": principal.Role})
+			}
+			for _, repo := range entry.Repos {
+				if !principal.allowsRepo(repo) {
+					return deny(fmt.Sprintf("%s is not allowed to access %s", principal.Name, repo), map[string]any{"caller": principal.Name, "repo": repo})
+				}
+			}
+			if allowed, retryAfter := cfg.limiter.allow(principal.Name, principal.RateLimit, time.Now()); !allowed {
+				seconds := int(math.Ceil(retryAfter.Seconds()))
+				return deny(fmt.Sprintf("rate limit of %d tool calls per minute exceeded", principal.RateLimit), map[string]any{"caller": principal.Name, "retry_after_seconds": seconds})
+			}
+
+			result, err := next(ctx, method, req)
+			entry.Allowed = true
+			entry.DurationMS = time.Since(start).Milliseconds()
+			if toolResult, ok := result.(*mcp.CallToolResult); err != nil || (ok && toolResult.IsError) {
+				entry.IsError = true
+			}
+			cfg.audit.record(entry)
+			return result, err
+		}
+	}
+}
+
+// caller returns the principal recorded by mcpAuthHandler for the request.
+func (c *mcpAuthConfig) caller(req mcp.Request) *mcpAuthPrincipal {
+	extra := req.GetExtra()
+	if extra == nil || extra.Header == nil {
+		return nil
+	}
+	return c.principals[extra.Header.Get(mcpCallerHeader)]
+}
+
+// mcpRequestRepos returns the repositories targeted by a tool call: repositories
+// named in repository-scoped arguments, or the default repository of the server.
+func mcpRequestRepos(req mcp.Request, defaultRepo string) []string {
+	var args map[string]any
+	if params, ok := req.GetParams().(*mcp.CallToolParamsRaw); ok && len(params.Arguments) > 0 {
+		if err := json.Unmarshal(params.Arguments, &args); err != nil {
+			mcpAuthLog.Printf("Failed to parse tool arguments: %v", err)
+		}
+	}
+
+	seen := make(map[string]bool)
+	var repos []string
+	addRepo := func(value string) {
+		repo := repoFromMCPArgument(value)
+		if repo != "" && !seen[strings.ToLower(repo)] {
+			seen[strings.ToLower(repo)] = true
+			repos = append(repos, repo)
+		}
+	}
+	for _, name := range mcpRepoScopedArgs {
+		switch value := args[name].(type) {
+		case string:
+			addRepo(value)
+		case []any:
+			for _, item := range value {
+				if s, ok := item.(string); ok {
+					addRepo(s)
+				}
+			}
+		}
+	}
+
+	if len(repos) == 0 && defaultRepo != "" {
+		repos = append(repos, defaultRepo)
+	}
+	return repos
+}
+
+// repoFromMCPArgument extracts owner/repo from an argument value that is either
+// a repository slug or a GitHub URL.
+func repoFromMCPArgument(value string) string {
+	value = strings.TrimSpace(value)
+	if mcpRepoSlugPattern.MatchString(value) {
+		return value
+	}
+	if !strings.Contains(value, "://") {
+		return ""
+	}
+	components, err := parser.ParseGitHubURL(value)
+	if err != nil || components.Owner == "" || components.Repo == "" {
+		return ""
+	}
+	return components.Owner + "/" + components.Repo
+}
+
+// mcpRateLimiter is a per-caller token bucket refilled continuously at the
+// caller's rate limit, with a burst of one minute's worth of calls.
+type mcpRateLimiter struct {
+	mu      sync.Mutex
+	buckets map[string]*mcpRateBucket
+}
+
+type mcpRateBucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+func newMCPRateLimiter() *mcpRateLimiter {
+	return &mcpRateLimiter{buckets: make(map[string]*mcpRateBucket)}
+}
+
+// allow consumes one call from the caller's bucket. When the bucket is empty it
+// returns false and the time until the next call is allowed.
+func (l *mcpRateLimiter) allow(name string, perMinute int, now time.Time) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	capacity := float64(perMinute)
+	ratePerSecond := capacity / 60
+	bucket, ok := l.buckets[name]
+	if !ok {
+		bucket = &mcpRateBucket{tokens: capacity, updated: now}
+		l.buckets[name] = bucket
+	}
+	if elapsed := now.Sub(bucket.updated).Seconds(); elapsed > 0 {
+		bucket.tokens = min(capacity, bucket.tokens+elapsed*ratePerSecond)
+		bucket.updated = now
+	}
+	if bucket.tokens < 1 {
+		wait := time.Duration((1 - bucket.tokens) / ratePerSecond * float64(time.Second))
+		return false, wait
+	}
+	bucket.tokens--
+	return true, 0
+}
+
+// mcpAuditEntry is one line of the audit log.
+type mcpAuditEntry struct {
+	Time       time.Time `json:"time"`
+	Event      string    `json:"event"`
+	Caller     string    `json:"caller,omitempty"`
+	Auth       string    `json:"auth,omitempty"`
+	Role       string    `json:"role,omitempty"`
+	RemoteAddr string    `json:"remote_addr,omitempty"`
+	Tool       string    `json:"tool,omitempty"`
+	Repos      []string  `json:"repos,omitempty"`
+	Allowed    bool      `json:"allowed"`
+	Reason     string    `json:"reason,omitempty"`
+	DurationMS int64     `json:"duration_ms,omitempty"`
+	IsError    bool      `json:"is_error,omitempty"`
+}
+
+// mcpAuditLogger writes audit entries as JSON lines.
+type mcpAuditLogger struct {
+	mu     sync.Mutex
+	writer io.Writer
+}
+
+// openMCPAuditLog opens the audit log file for appending, or returns a logger
+// writing to stderr when no file is given.
+func openMCPAuditLog(auditFile string) (*mcpAuditLogger, func(), error) {
+	if auditFile == "" {
+		return &mcpAuditLogger{writer: os.Stderr}, func() {}, nil
+	}
+	file, err := os.OpenFile(filepath.Clean(auditFile), os.O_APPEND|os.O_CREATE|os.O_WRONLY, constants.FilePermSensitive)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	closeFn := func() {
+		if err := file.Close(); err != nil {
+			mcpAuthLog.Printf("Failed to close audit log: %v", err)
+		}
+	}
+	return &mcpAuditLogger{writer: file}, closeFn, nil
+}
+
+func (a *mcpAuditLogger) record(entry mcpAuditEntry) {
+	if a == nil {
+		return
+	}
+	if entry.Time.IsZero() {
+		entry.Time = time.Now().UTC()
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		mcpAuthLog.Printf("Failed to marshal audit entry: %v", err)
+		return
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if _, err := a.writer.Write(append(data, '\n')); err != nil {
+		mcpAuthLog.Printf("Failed to write audit entry: %v", err)
+	}
+}
+
+// loadClientCAPool reads the PEM bundle used to verify client certificates.
+func loadClientCAPool(caFile string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(filepath.Clean(caFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read client CA file: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("no PEM certificates found in %s", caFile)
+	}
+	return pool, nil
+}
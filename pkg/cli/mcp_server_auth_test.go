@@ -0,0 +1,253 @@
+//go:build !integration
+
+package cli
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func hashMCPToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// bearerRoundTripper adds an Authorization header to every request.
+type bearerRoundTripper struct {
+	token string
+}
+
+func (b bearerRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+b.token)
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+type mcpAuthTestArgs struct {
+	Repo string `json:"repo,omitempty"`
+}
+
+// newMCPAuthTestServer serves an MCP server with a read-only "status" tool and
+// a privileged "logs" tool behind the authenticated HTTP handler.
+func newMCPAuthTestServer(t *testing.T, authJSON string) (*httptest.Server, *bytes.Buffer) {
+	t.Helper()
+	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "1.0"}, nil)
+	handler := func(ctx context.Context, req *mcp.CallToolRequest, args mcpAuthTestArgs) (*mcp.CallToolResult, any, error) {
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: "ok"}}}, nil, nil
+	}
+	mcp.AddTool(server, &mcp.Tool{Name: "status", Description: "status"}, handler)
+	mcp.AddTool(server, &mcp.Tool{Name: "logs", Description: "logs"}, handler)
+
+	auth, err := parseMCPAuthConfig([]byte(authJSON))
+	require.NoError(t, err, "test auth file should be valid")
+	var auditLog bytes.Buffer
+	auth.audit = &mcpAuditLogger{writer: &auditLog}
+	auth.defaultRepo = "octo-org/app"
+
+	httpServer := httptest.NewServer(newMCPHTTPHandler(server, auth))
+	t.Cleanup(httpServer.Close)
+	return httpServer, &auditLog
+}
+
+func connectMCPWithToken(t *testing.T, endpoint, token string) *mcp.ClientSession {
+	t.Helper()
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "1.0"}, nil)
+	session, err := client.Connect(context.Background(), &mcp.StreamableClientTransport{
+		Endpoint:             endpoint,
+		HTTPClient:           &http.Client{Transport: bearerRoundTripper{token: token}},
+		MaxRetries:           -1,
+		DisableStandaloneSSE: true,
+	}, nil)
+	require.NoError(t, err, "client should connect with a valid token")
+	t.Cleanup(func() { session.Close() })
+	return session
+}
+
+func readMCPAuditEntries(t *testing.T, auditLog *bytes.Buffer) []mcpAuditEntry {
+	t.Helper()
+	var entries []mcpAuditEntry
+	for line := range strings.SplitSeq(strings.TrimSpace(auditLog.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var entry mcpAuditEntry
+		require.NoError(t, json.Unmarshal([]byte(line), &entry), "audit log lines should be JSON")
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestParseMCPAuthConfig(t *testing.T) {
+	hash := hashMCPToken("secret")
+
+	cfg, err := parseMCPAuthConfig([]byte(`{
+		"rate_limit": 30,
+		"tokens": [{"name": "alice", "sha256": "` + strings.ToUpper(hash) + `", "role": "privileged"}],
+		"clients": [{"name": "box", "common_name": "box.internal", "role": "readonly", "rate_limit": 5}]
+	}`))
+	require.NoError(t, err, "valid auth file should parse")
+	require.Contains(t, cfg.tokens, hash, "token hashes should be normalized to lowercase")
+	assert.Equal(t, 30, cfg.tokens[hash].RateLimit, "file rate limit should apply by default")
+	assert.Equal(t, 5, cfg.clients["box.internal"].RateLimit, "caller rate limit should override the file rate limit")
+	assert.Equal(t, mcpAuthMethodMTLS, cfg.principals["box"].method)
+
+	invalid := map[string]string{
+		"no callers":     `{}`,
+		"unknown role":   `{"tokens": [{"name": "a", "sha256": "` + hash + `", "role": "admin"}]}`,
+		"plain token":    `{"tokens": [{"name": "a", "sha256": "secret", "role": "readonly"}]}`,
+		"duplicate name": `{"tokens": [{"name": "a", "sha256": "` + hash + `", "role": "readonly"}], "clients": [{"name": "a", "common_name": "x", "role": "readonly"}]}`,
+		"missing cn":     `{"clients": [{"name": "a", "role": "readonly"}]}`,
+		"bad pattern":    `{"tokens": [{"name": "a", "sha256": "` + hash + `", "role": "readonly", "repos": ["["]}]}`,
+	}
+	for name, content := range invalid {
+		t.Run(name, func(t *testing.T) {
+			_, err := parseMCPAuthConfig([]byte(content))
+			assert.Error(t, err, "invalid auth file should be rejected")
+		})
+	}
+}
+
+func TestMCPHTTPHandler_RejectsUnauthenticatedRequests(t *testing.T) {
+	httpServer, auditLog := newMCPAuthTestServer(t, `{"tokens": [{"name": "alice", "sha256": "`+hashMCPToken("secret")+`", "role": "privileged"}]}`)
+
+	tests := map[string]string{
+		"no token":    "",
+		"wrong token": "Bearer not-the-secret",
+		"basic auth":  "Basic YWxpY2U6c2VjcmV0",
+	}
+	for name, header := range tests {
+		t.Run(name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodPost, httpServer.URL, strings.NewReader(`{}`))
+			require.NoError(t, err)
+			if header != "" {
+				req.Header.Set("Authorization", header)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+			assert.NotEmpty(t, resp.Header.Get("WWW-Authenticate"))
+		})
+	}
+
+	entries := readMCPAuditEntries(t, auditLog)
+	require.Len(t, entries, len(tests), "every rejected request should be audited")
+	assert.Equal(t, "authentication", entries[0].Event)
+	assert.False(t, entries[0].Allowed)
+}
+
+func TestMCPHTTPHandler_AuthorizesToolCalls(t *testing.T) {
+	httpServer, auditLog := newMCPAuthTestServer(t, `{"tokens": [
+		{"name": "alice", "sha256": "`+hashMCPToken("alice-token")+`", "role": "privileged"},
+		{"name": "reader", "sha256": "`+hashMCPToken("reader-token")+`", "role": "readonly", "repos": ["octo-org/*"]}
+	]}`)
+	ctx := context.Background()
+
+	reader := connectMCPWithToken(t, httpServer.URL, "reader-token")
+	result, err := reader.CallTool(ctx, &mcp.CallToolParams{Name: "status"})
+	require.NoError(t, err, "readonly callers should be allowed to call read-only tools")
+	assert.False(t, result.IsError)
+
+	_, err = reader.CallTool(ctx, &mcp.CallToolParams{Name: "logs"})
+	require.Error(t, err, "readonly callers should not call privileged tools")
+	assert.Contains(t, err.Error(), `role "readonly" cannot call the logs tool`)
+
+	_, err = reader.CallTool(ctx, &mcp.CallToolParams{Name: "status", Arguments: map[string]any{"repo": "other-org/app"}})
+	require.Error(t, err, "callers should be limited to their repositories")
+	assert.Contains(t, err.Error(), "not allowed to access other-org/app")
+
+	alice := connectMCPWithToken(t, httpServer.URL, "alice-token")
+	_, err = alice.CallTool(ctx, &mcp.CallToolParams{Name: "logs", Arguments: map[string]any{"repo": "other-org/app"}})
+	require.NoError(t, err, "privileged callers without repos should call any tool on any repository")
+
+	entries := readMCPAuditEntries(t, auditLog)
+	require.Len(t, entries, 4, "every tool call should be audited")
+	assert.Equal(t, mcpAuditEntry{
+		Time: entries[0].Time, Event: "tool_call", Caller: "reader", Auth: mcpAuthMethodBearer, Role: MCPRoleReadOnly,
+		Tool: "status", Repos: []string{"octo-org/app"}, Allowed: true, DurationMS: entries[0].DurationMS,
+	}, entries[0], "the default repository should be audited when no repository is given")
+	assert.False(t, entries[1].Allowed)
+	assert.Equal(t, []string{"other-org/app"}, entries[2].Repos)
+	assert.Equal(t, "alice", entries[3].Caller)
+	assert.True(t, entries[3].Allowed)
+}
+
+func TestMCPHTTPHandler_RateLimitsToolCalls(t *testing.T) {
+	httpServer, _ := newMCPAuthTestServer(t, `{"tokens": [{"name": "alice", "sha256": "`+hashMCPToken("secret")+`", "role": "privileged", "rate_limit": 2}]}`)
+	session := connectMCPWithToken(t, httpServer.URL, "secret")
+	ctx := context.Background()
+
+	for range 2 {
+		_, err := session.CallTool(ctx, &mcp.CallToolParams{Name: "status"})
+		require.NoError(t, err, "calls within the rate limit should succeed")
+	}
+	_, err := session.CallTool(ctx, &mcp.CallToolParams{Name: "status"})
+	require.Error(t, err, "calls beyond the rate limit should be rejected")
+	assert.Contains(t, err.Error(), "rate limit of 2 tool calls per minute exceeded")
+}
+
+func TestMCPRateLimiter_Refills(t *testing.T) {
+	limiter := newMCPRateLimiter()
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for range 3 {
+		allowed, _ := limiter.allow("alice", 3, now)
+		require.True(t, allowed, "the bucket should start full")
+	}
+	allowed, retryAfter := limiter.allow("alice", 3, now)
+	require.False(t, allowed, "an empty bucket should reject calls")
+	assert.Equal(t, 20*time.Second, retryAfter, "one call is refilled every 20 seconds at 3 per minute")
+
+	allowed, _ = limiter.allow("bob", 3, now)
+	assert.True(t, allowed, "buckets should be tracked per caller")
+
+	allowed, _ = limiter.allow("alice", 3, now.Add(20*time.Second))
+	assert.True(t, allowed, "the bucket should refill over time")
+}
+
+func TestMCPAuthConfig_AuthenticatesClientCertificates(t *testing.T) {
+	cfg, err := parseMCPAuthConfig([]byte(`{"clients": [{"name": "box", "common_name": "box.internal", "role": "readonly"}]}`))
+	require.NoError(t, err)
+
+	withCert := func(commonName string, verified bool) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+		cert := &x509.Certificate{Subject: pkix.Name{CommonName: commonName}}
+		req.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}}
+		if verified {
+			req.TLS.VerifiedChains = [][]*x509.Certificate{{cert}}
+		}
+		return req
+	}
+
+	principal, _, _ := cfg.authenticate(withCert("box.internal", true))
+	require.NotNil(t, principal, "a verified certificate with a known common name should authenticate")
+	assert.Equal(t, "box", principal.Name)
+
+	_, status, _ := cfg.authenticate(withCert("laptop.internal", true))
+	assert.Equal(t, http.StatusForbidden, status, "unknown common names should be rejected")
+
+	_, status, _ = cfg.authenticate(withCert("box.internal", false))
+	assert.Equal(t, http.StatusUnauthorized, status, "unverified certificates should not authenticate")
+}
+
+func TestRepoFromMCPArgument(t *testing.T) {
+	assert.Equal(t, "octo-org/app", repoFromMCPArgument("octo-org/app"))
+	assert.Equal(t, "octo-org/app", repoFromMCPArgument("https://github.com/octo-org/app/actions/runs/123"))
+	assert.Equal(t, "octo-org/app", repoFromMCPArgument("https://github.com/octo-org/app/pull/7"))
+	assert.Empty(t, repoFromMCPArgument("1234567"), "run IDs do not name a repository")
+}
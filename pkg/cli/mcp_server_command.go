@@ -2,6 +2,7 @@ package cli
 
 import (
 	"context"
+	"errors"
 	"os"
 	"strings"
 
@@ -18,6 +19,7 @@ func NewMCPServerCommand() *cobra.Command {
 	var port int
 	var cmdPath string
 	var validateActor bool
+	var httpOpts mcpHTTPOptions
 
 	cmd := &cobra.Command{
 		Use:   "mcp-server",
@@ -52,21 +54,42 @@ Access Control:
 By default, the server uses stdio transport. Use the --port flag to run
 an HTTP server with SSE (Server-Sent Events) transport instead.
 
+HTTP Authentication:
+  Without --auth-file the HTTP server accepts any request. Use --auth-file to require
+  a bearer token (or a client certificate with --client-ca) on every request. The token
+  file maps each caller to a role and optional repository patterns:
+    - readonly   - status, compile, checks and mcp-inspect only
+    - privileged - every tool
+  Tool calls are rate limited per caller and written to an audit log (stderr unless
+  --audit-log is set) with the caller identity, tool, repositories and decision.
+
 Examples:
   gh aw mcp-server                                     # Run with stdio transport (default for MCP clients)
   gh aw mcp-server --validate-actor                    # Run with actor validation enforced
   gh aw mcp-server --port 8080                         # Run HTTP server on port 8080 (for web-based clients)
+  gh aw mcp-server --port 8080 --auth-file tokens.json # Require bearer tokens on the HTTP server
+  gh aw mcp-server --port 8443 --auth-file tokens.json \
+    --tls-cert server.pem --tls-key server-key.pem \
+    --client-ca ca.pem                                 # Serve HTTPS and accept client certificates (mTLS)
   gh aw mcp-server --cmd ./gh-aw                       # Use custom gh-aw binary path
   GITHUB_ACTOR=octocat gh aw mcp-server                # Set actor via environment variable for access control
   DEBUG=mcp:* GITHUB_ACTOR=octocat gh aw mcp-server    # Run with verbose debug logging and actor set via environment variable`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runMCPServer(port, cmdPath, validateActor)
+			if port == 0 && (httpOpts != mcpHTTPOptions{}) {
+				return errors.New("--auth-file, --audit-log, --tls-cert, --tls-key and --client-ca require --port")
+			}
+			return runMCPServer(port, cmdPath, validateActor, httpOpts)
 		},
 	}
 
 	cmd.Flags().IntVarP(&port, "port", "p", 0, "Port to run HTTP server on (uses stdio if not specified)")
 	cmd.Flags().StringVar(&cmdPath, "cmd", "", "Path to gh aw command to use (defaults to 'gh aw')")
 	cmd.Flags().BoolVar(&validateActor, "validate-actor", false, "Enforce actor validation (logs/audit tools return errors without GITHUB_ACTOR)")
+	cmd.Flags().StringVar(&httpOpts.AuthFile, "auth-file", "", "Token file mapping bearer tokens and client certificates to roles (HTTP only)")
+	cmd.Flags().StringVar(&httpOpts.AuditLog, "audit-log", "", "Append the tool call audit log to this file instead of stderr (requires --auth-file)")
+	cmd.Flags().StringVar(&httpOpts.TLSCert, "tls-cert", "", "TLS certificate file to serve HTTPS (requires --tls-key)")
+	cmd.Flags().StringVar(&httpOpts.TLSKey, "tls-key", "", "TLS private key file to serve HTTPS (requires --tls-cert)")
+	cmd.Flags().StringVar(&httpOpts.ClientCA, "client-ca", "", "CA bundle used to verify client certificates for mutual TLS (requires --auth-file)")
 
 	return cmd
 }
@@ -88,7 +111,7 @@ func checkAndLogGHVersion() {
 }
 
 // runMCPServer starts the MCP server on stdio or HTTP transport
-func runMCPServer(port int, cmdPath string, validateActor bool) error {
+func runMCPServer(port int, cmdPath string, validateActor bool, httpOpts mcpHTTPOptions) error {
 	// Get actor from environment variable
 	actor := os.Getenv("GITHUB_ACTOR")
 
@@ -163,7 +186,7 @@ func runMCPServer(port int, cmdPath string, validateActor bool) error {
 
 	if port > 0 {
 		// Run HTTP server with SSE transport
-		return runHTTPServer(server, port)
+		return runHTTPServer(server, port, httpOpts)
 	}
 
 	// Run stdio transport
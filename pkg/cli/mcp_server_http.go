@@ -1,6 +1,8 @@
 package cli
 
 import (
+	"crypto/tls"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
@@ -60,33 +62,119 @@ func loggingHandler(handler http.Handler) http.Handler {
 	})
 }
 
-// runHTTPServer runs the MCP server with HTTP/SSE transport
-func runHTTPServer(server *mcp.Server, port int) error {
-	mcpLog.Printf("Creating HTTP server on port %d", port)
+// mcpHTTPOptions configures authentication and TLS for the HTTP transport.
+type mcpHTTPOptions struct {
+	AuthFile string // Token file mapping callers to roles; empty disables authentication
+	AuditLog string // File receiving the audit log; empty writes to stderr
+	TLSCert  string // Server certificate; enables HTTPS together with TLSKey
+	TLSKey   string // Server private key
+	ClientCA string // CA bundle used to verify client certificates (mutual TLS)
+}
+
+// validate checks that the HTTP options are consistent.
+func (o mcpHTTPOptions) validate() error {
+	if (o.TLSCert == "") != (o.TLSKey == "") {
+		return errors.New("--tls-cert and --tls-key must be used together")
+	}
+	if o.ClientCA != "" && o.TLSCert == "" {
+		return errors.New("--client-ca requires --tls-cert and --tls-key")
+	}
+	if o.ClientCA != "" && o.AuthFile == "" {
+		return errors.New("--client-ca requires --auth-file to map client certificates to roles")
+	}
+	if o.AuditLog != "" && o.AuthFile == "" {
+		return errors.New("--audit-log requires --auth-file")
+	}
+	return nil
+}
 
+// newMCPHTTPHandler returns the HTTP handler serving the MCP server. When auth
+// is set, every request must be authenticated and tool calls are authorized
+// and audited against the token file.
+func newMCPHTTPHandler(server *mcp.Server, auth *mcpAuthConfig) http.Handler {
 	// Create the streamable HTTP handler.
-	handler := mcp.NewStreamableHTTPHandler(func(req *http.Request) *mcp.Server {
+	var handler http.Handler = mcp.NewStreamableHTTPHandler(func(req *http.Request) *mcp.Server {
 		return server
 	}, &mcp.StreamableHTTPOptions{
 		SessionTimeout: 2 * time.Hour, // Close idle sessions after 2 hours
 		Logger:         logger.NewSlogLoggerWithHandler(mcpLog),
 	})
 
-	handlerWithLogging := loggingHandler(handler)
+	if auth != nil {
+		server.AddReceivingMiddleware(mcpAuthorizationMiddleware(auth))
+		handler = mcpAuthHandler(auth, handler)
+	}
+
+	return loggingHandler(handler)
+}
+
+// runHTTPServer runs the MCP server with HTTP/SSE transport
+func runHTTPServer(server *mcp.Server, port int, opts mcpHTTPOptions) error {
+	mcpLog.Printf("Creating HTTP server on port %d", port)
+
+	if err := opts.validate(); err != nil {
+		return err
+	}
+
+	var auth *mcpAuthConfig
+	if opts.AuthFile != "" {
+		var err error
+		auth, err = loadMCPAuthConfig(opts.AuthFile)
+		if err != nil {
+			return err
+		}
+		audit, closeAudit, err := openMCPAuditLog(opts.AuditLog)
+		if err != nil {
+			return err
+		}
+		defer closeAudit()
+		auth.audit = audit
+		auth.defaultRepo = os.Getenv("GITHUB_REPOSITORY")
+		if auth.defaultRepo == "" {
+			if slug, err := GetCurrentRepoSlug(); err == nil {
+				auth.defaultRepo = slug
+			}
+		}
+		mcpLog.Printf("Authentication enabled: default repository=%q", auth.defaultRepo)
+	} else {
+		fmt.Fprintln(os.Stderr, console.FormatWarningMessage("No --auth-file given: the HTTP server accepts unauthenticated requests"))
+	}
 
 	// Create HTTP server
 	addr := fmt.Sprintf(":%d", port)
 	httpServer := &http.Server{
 		Addr:              addr,
-		Handler:           handlerWithLogging,
+		Handler:           newMCPHTTPHandler(server, auth),
 		ReadHeaderTimeout: MCPServerHTTPTimeout,
 	}
 
-	fmt.Fprintln(os.Stderr, console.FormatInfoMessage("Starting MCP server on http://localhost"+addr))
-	mcpLog.Printf("HTTP server listening on %s", addr)
+	scheme := "http"
+	if opts.TLSCert != "" {
+		scheme = "https"
+		httpServer.TLSConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+		if opts.ClientCA != "" {
+			pool, err := loadClientCAPool(opts.ClientCA)
+			if err != nil {
+				return err
+			}
+			// Client certificates are optional so that bearer-token callers can
+			// still connect; certificates that are presented must verify.
+			httpServer.TLSConfig.ClientCAs = pool
+			httpServer.TLSConfig.ClientAuth = tls.VerifyClientCertIfGiven
+		}
+	}
+
+	fmt.Fprintln(os.Stderr, console.FormatInfoMessage("Starting MCP server on "+scheme+"://localhost"+addr))
+	mcpLog.Printf("HTTP server listening on %s (%s)", addr, scheme)
 
 	// Run the HTTP server
-	if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+	var err error
+	if opts.TLSCert != "" {
+		err = httpServer.ListenAndServeTLS(opts.TLSCert, opts.TLSKey)
+	} else {
+		err = httpServer.ListenAndServe()
+	}
+	if err != nil && err != http.ErrServerClosed {
 		mcpLog.Printf("HTTP server failed: %v", err)
 		return fmt.Errorf("HTTP server failed: %w", err)
 	}
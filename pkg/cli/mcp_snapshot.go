@@ -0,0 +1,402 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/constants"
+	"github.com/github/gh-aw/pkg/gitutil"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/spf13/cobra"
+)
+
+var mcpSnapshotLog = logger.New("cli:mcp_snapshot")
+
+// MCPSnapshotDir is the directory (relative to the git root) holding committed MCP tool snapshots
+const MCPSnapshotDir = ".github/aw/mcp-snapshots"
+
+// mcpSnapshotVersion is the snapshot file format version
+const mcpSnapshotVersion = 1
+
+// MCPWorkflowSnapshot records the tools every MCP server of a workflow exposes to the agent
+type MCPWorkflowSnapshot struct {
+	Version  int                           `json:"version"`
+	Workflow string                        `json:"workflow"`
+	Servers  map[string]*MCPServerSnapshot `json:"servers"`
+}
+
+// MCPServerSnapshot records the connection target and tools of a single MCP server
+type MCPServerSnapshot struct {
+	Source string            `json:"source"`
+	Tools  []MCPToolSnapshot `json:"tools"`
+}
+
+// MCPToolSnapshot records what the agent sees for a single tool
+type MCPToolSnapshot struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	InputSchema json.RawMessage `json:"input_schema,omitempty"`
+}
+
+// MCPSnapshotChange describes a single difference between a committed snapshot and the live servers
+type MCPSnapshotChange struct {
+	Server string
+	Tool   string
+	Kind   string // "server-added", "server-removed", "source-changed", "tool-added", "tool-removed", "description-changed", "schema-changed"
+	Before string
+	After  string
+}
+
+// newMCPServerSnapshot builds a snapshot from the tools reported by a connected server.
+// Tools are sorted by name and input schemas are re-encoded so that map key order
+// never produces spurious drift.
+func newMCPServerSnapshot(source string, tools []*mcp.Tool) (*MCPServerSnapshot, error) {
+	snapshot := &MCPServerSnapshot{Source: source, Tools: make([]MCPToolSnapshot, 0, len(tools))}
+	for _, tool := range tools {
+		if tool == nil {
+			continue
+		}
+		entry := MCPToolSnapshot{Name: tool.Name, Description: tool.Description}
+		if tool.InputSchema != nil {
+			schema, err := canonicalJSON(tool.InputSchema)
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode input schema for tool '%s': %w", tool.Name, err)
+			}
+			entry.InputSchema = schema
+		}
+		snapshot.Tools = append(snapshot.Tools, entry)
+	}
+	sort.Slice(snapshot.Tools, func(i, j int) bool {
+		return snapshot.Tools[i].Name < snapshot.Tools[j].Name
+	})
+	return snapshot, nil
+}
+
+// canonicalJSON encodes a value with sorted object keys
+func canonicalJSON(v any) (json.RawMessage, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	// Round-trip through a generic value so that struct field order and map order are normalised
+	var generic any
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return nil, err
+	}
+	return json.Marshal(generic)
+}
+
+// diffMCPSnapshots compares a committed snapshot with a freshly captured one
+func diffMCPSnapshots(committed, current *MCPWorkflowSnapshot) []MCPSnapshotChange {
+	var changes []MCPSnapshotChange
+
+	serverNames := make(map[string]bool)
+	for name := range committed.Servers {
+		serverNames[name] = true
+	}
+	for name := range current.Servers {
+		serverNames[name] = true
+	}
+	sortedServers := make([]string, 0, len(serverNames))
+	for name := range serverNames {
+		sortedServers = append(sortedServers, name)
+	}
+	sort.Strings(sortedServers)
+
+	for _, serverName := range sortedServers {
+		before, hadBefore := committed.Servers[serverName]
+		after, hasAfter := current.Servers[serverName]
+		switch {
+		case !hadBefore:
+			changes = append(changes, MCPSnapshotChange{Server: serverName, Kind: "server-added", After: after.Source})
+			continue
+		case !hasAfter:
+			changes = append(changes, MCPSnapshotChange{Server: serverName, Kind: "server-removed", Before: before.Source})
+			continue
+		}
+
+		if before.Source != after.Source {
+			changes = append(changes, MCPSnapshotChange{Server: serverName, Kind: "source-changed", Before: before.Source, After: after.Source})
+		}
+		changes = append(changes, diffMCPServerTools(serverName, before.Tools, after.Tools)...)
+	}
+
+	return changes
+}
+
+// diffMCPServerTools compares the tool lists of a single server
+func diffMCPServerTools(serverName string, before, after []MCPToolSnapshot) []MCPSnapshotChange {
+	var changes []MCPSnapshotChange
+
+	beforeByName := make(map[string]MCPToolSnapshot, len(before))
+	for _, tool := range before {
+		beforeByName[tool.Name] = tool
+	}
+	afterByName := make(map[string]MCPToolSnapshot, len(after))
+	for _, tool := range after {
+		afterByName[tool.Name] = tool
+	}
+
+	for _, tool := range before {
+		if _, ok := afterByName[tool.Name]; !ok {
+			changes = append(changes, MCPSnapshotChange{Server: serverName, Tool: tool.Name, Kind: "tool-removed"})
+		}
+	}
+	for _, tool := range after {
+		old, ok := beforeByName[tool.Name]
+		if !ok {
+			changes = append(changes, MCPSnapshotChange{Server: serverName, Tool: tool.Name, Kind: "tool-added", After: tool.Description})
+			continue
+		}
+		if old.Description != tool.Description {
+			changes = append(changes, MCPSnapshotChange{Server: serverName, Tool: tool.Name, Kind: "description-changed", Before: old.Description, After: tool.Description})
+		}
+		if string(old.InputSchema) != string(tool.InputSchema) {
+			changes = append(changes, MCPSnapshotChange{Server: serverName, Tool: tool.Name, Kind: "schema-changed", Before: string(old.InputSchema), After: string(tool.InputSchema)})
+		}
+	}
+
+	return changes
+}
+
+// formatMCPSnapshotDiff renders changes as a readable unified-style diff
+func formatMCPSnapshotDiff(workflowID string, changes []MCPSnapshotChange) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "MCP tool drift in workflow '%s' (%d change(s)):\n", workflowID, len(changes))
+	for _, change := range changes {
+		target := change.Server
+		if change.Tool != "" {
+			target += "." + change.Tool
+		}
+		fmt.Fprintf(&sb, "\n  %s: %s\n", target, change.Kind)
+		if change.Before != "" {
+			for line := range strings.SplitSeq(change.Before, "\n") {
+				fmt.Fprintf(&sb, "    - %s\n", line)
+			}
+		}
+		if change.After != "" {
+			for line := range strings.SplitSeq(change.After, "\n") {
+				fmt.Fprintf(&sb, "    + %s\n", line)
+			}
+		}
+	}
+	return sb.String()
+}
+
+// mcpSnapshotPath returns the snapshot file path for a workflow ID
+func mcpSnapshotPath(gitRoot, workflowID string) string {
+	return filepath.Join(gitRoot, MCPSnapshotDir, workflowID+".json")
+}
+
+// readMCPSnapshot loads a committed snapshot file
+func readMCPSnapshot(path string) (*MCPWorkflowSnapshot, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, err
+	}
+	var snapshot MCPWorkflowSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("failed to parse MCP snapshot %s: %w", path, err)
+	}
+	if snapshot.Servers == nil {
+		snapshot.Servers = map[string]*MCPServerSnapshot{}
+	}
+	// Schemas are stored indented; compare them in canonical compact form
+	for name, server := range snapshot.Servers {
+		if server == nil {
+			return nil, fmt.Errorf("failed to parse MCP snapshot %s: server %q is null", path, name)
+		}
+		for i, tool := range server.Tools {
+			if len(tool.InputSchema) == 0 {
+				continue
+			}
+			var generic any
+			if err := json.Unmarshal(tool.InputSchema, &generic); err != nil {
+				return nil, fmt.Errorf("invalid input schema for %s.%s in %s: %w", name, tool.Name, path, err)
+			}
+			schema, err := canonicalJSON(generic)
+			if err != nil {
+				return nil, err
+			}
+			server.Tools[i].InputSchema = schema
+		}
+	}
+	return &snapshot, nil
+}
+
+// writeMCPSnapshot writes a snapshot as indented JSON
+func writeMCPSnapshot(path string, snapshot *MCPWorkflowSnapshot) error {
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode MCP snapshot: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), constants.DirPermPublic); err != nil {
+		return fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+	return os.WriteFile(path, append(data, '\n'), constants.FilePermPublic)
+}
+
+// captureWorkflowMCPSnapshot connects to every MCP server of a workflow and records its tools
+func captureWorkflowMCPSnapshot(workflowPath string, verbose bool) (*MCPWorkflowSnapshot, error) {
+	workflowID := normalizeWorkflowID(workflowPath)
+	_, mcpConfigs, err := loadResolvedWorkflowMCPConfigs(workflowPath, "", verbose)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &MCPWorkflowSnapshot{
+		Version:  mcpSnapshotVersion,
+		Workflow: workflowID,
+		Servers:  make(map[string]*MCPServerSnapshot, len(mcpConfigs)),
+	}
+
+	for _, config := range mcpConfigs {
+		mcpSnapshotLog.Printf("Capturing tools for server %s in workflow %s", config.Name, workflowID)
+		info, err := connectToMCPServer(config, verbose)
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to MCP server '%s': %w", config.Name, err)
+		}
+		serverSnapshot, err := newMCPServerSnapshot(buildConnectionString(config), info.Tools)
+		if err != nil {
+			return nil, fmt.Errorf("MCP server '%s': %w", config.Name, err)
+		}
+		snapshot.Servers[config.Name] = serverSnapshot
+	}
+
+	return snapshot, nil
+}
+
+// SnapshotWorkflowMCP records or verifies the MCP tool snapshots of the given workflows.
+// With no workflows, every workflow with MCP servers is processed.
+func SnapshotWorkflowMCP(workflowFiles []string, verify bool, verbose bool) error {
+	mcpSnapshotLog.Printf("MCP snapshot: workflows=%v, verify=%v", workflowFiles, verify)
+
+	gitRoot, err := gitutil.FindGitRoot()
+	if err != nil {
+		return fmt.Errorf("mcp snapshot must be run inside a git repository: %w", err)
+	}
+
+	workflowPaths, err := resolveMCPSnapshotWorkflows(workflowFiles, verbose)
+	if err != nil {
+		return err
+	}
+	if len(workflowPaths) == 0 {
+		fmt.Fprintln(os.Stderr, console.FormatInfoMessage("No workflows with MCP servers found"))
+		return nil
+	}
+
+	driftCount := 0
+	for _, workflowPath := range workflowPaths {
+		workflowID := normalizeWorkflowID(workflowPath)
+		snapshotPath := mcpSnapshotPath(gitRoot, workflowID)
+
+		current, err := captureWorkflowMCPSnapshot(workflowPath, verbose)
+		if err != nil {
+			return fmt.Errorf("workflow '%s': %w", workflowID, err)
+		}
+
+		if !verify {
+			if err := writeMCPSnapshot(snapshotPath, current); err != nil {
+				return err
+			}
+			fmt.Fprintln(os.Stderr, console.FormatSuccessMessage(fmt.Sprintf("Recorded %d MCP server(s) for '%s' in %s", len(current.Servers), workflowID, console.ToRelativePath(snapshotPath))))
+			continue
+		}
+
+		committed, err := readMCPSnapshot(snapshotPath)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return fmt.Errorf("no MCP snapshot for workflow '%s' at %s; run 'gh aw mcp snapshot %s' and commit the result", workflowID, console.ToRelativePath(snapshotPath), workflowID)
+			}
+			return err
+		}
+
+		changes := diffMCPSnapshots(committed, current)
+		if len(changes) == 0 {
+			fmt.Fprintln(os.Stderr, console.FormatSuccessMessage(fmt.Sprintf("MCP tools for '%s' match the snapshot", workflowID)))
+			continue
+		}
+		driftCount++
+		fmt.Fprintln(os.Stderr, console.FormatErrorMessage(formatMCPSnapshotDiff(workflowID, changes)))
+	}
+
+	if driftCount > 0 {
+		return fmt.Errorf("MCP tool drift detected in %d workflow(s); review the changes and re-run 'gh aw mcp snapshot' to accept them", driftCount)
+	}
+	return nil
+}
+
+// resolveMCPSnapshotWorkflows resolves explicit workflow arguments, or scans for all
+// workflows that use MCP servers when none are given
+func resolveMCPSnapshotWorkflows(workflowFiles []string, verbose bool) ([]string, error) {
+	var paths []string
+	if len(workflowFiles) > 0 {
+		for _, workflowFile := range workflowFiles {
+			path, err := ResolveWorkflowPath(workflowFile)
+			if err != nil {
+				return nil, err
+			}
+			absPath, err := filepath.Abs(path)
+			if err != nil {
+				return nil, fmt.Errorf("failed to resolve workflow path: %w", err)
+			}
+			paths = append(paths, absPath)
+		}
+		return paths, nil
+	}
+
+	results, err := ScanWorkflowsForMCP(getWorkflowsDir(), "", verbose)
+	if err != nil {
+		return nil, err
+	}
+	for _, result := range results {
+		if len(filterOutSafeOutputs(result.MCPConfigs)) > 0 {
+			paths = append(paths, result.FilePath)
+		}
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// NewMCPSnapshotSubcommand creates the mcp snapshot subcommand
+func NewMCPSnapshotSubcommand() *cobra.Command {
+	var verify bool
+
+	cmd := &cobra.Command{
+		Use:   "snapshot [workflow]...",
+		Short: "Record or verify snapshots of the tools each MCP server exposes",
+		Long: `Record or verify snapshots of the tools each MCP server in a workflow exposes to the agent.
+
+A snapshot stores every tool's name, description and input schema, together with the server's
+connection target (container image, command or URL), in ` + MCPSnapshotDir + `/<workflow-id>.json.
+Commit the snapshot files so that changes are reviewed like code.
+
+With --verify the servers are started again and compared with the committed snapshot. The command
+fails with a readable diff when a tool is added or removed, a description or input schema changes,
+or the server's container or command changes. Tool description changes are a known prompt-injection
+vector, so run --verify in CI to catch unexpected drift such as silent container version bumps.
+
+When no workflow is given, every workflow with MCP servers is processed.
+
+Examples:
+  gh aw mcp snapshot                      # Record snapshots for all workflows with MCP servers
+  gh aw mcp snapshot weekly-research      # Record the snapshot for one workflow
+  gh aw mcp snapshot --verify             # Fail if any workflow's MCP tools drifted`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			verbose, _ := cmd.Flags().GetBool("verbose")
+			return SnapshotWorkflowMCP(args, verify, verbose)
+		},
+	}
+
+	cmd.Flags().BoolVar(&verify, "verify", false, "Compare live MCP servers with the committed snapshots and fail on drift")
+	cmd.ValidArgsFunction = CompleteWorkflowNames
+
+	return cmd
+}
@@ -4,8 +4,10 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/github/gh-aw/pkg/console"
 	"github.com/github/gh-aw/pkg/logger"
 	"github.com/github/gh-aw/pkg/parser"
+	"github.com/github/gh-aw/pkg/workflow"
 )
 
 var mcpWorkflowLoaderLog = logger.New("cli:mcp_workflow_loader")
@@ -45,3 +47,43 @@ func loadWorkflowMCPConfigs(workflowPath string, serverFilter string) (*parser.F
 	mcpWorkflowLoaderLog.Printf("Loaded %d MCP configurations from workflow", len(mcpConfigs))
 	return workflowData, mcpConfigs, nil
 }
+
+// loadResolvedWorkflowMCPConfigs parses a workflow with the compiler (so imported
+// MCP servers are included) and returns the MCP server configurations it uses,
+// excluding the built-in safe-outputs server.
+func loadResolvedWorkflowMCPConfigs(workflowPath string, serverFilter string, verbose bool) (*workflow.WorkflowData, []parser.RegistryMCPServerConfig, error) {
+	// Use the compiler to parse the workflow file
+	// This automatically handles imports, merging, and validation
+	compiler := workflow.NewCompiler(
+		workflow.WithVerbose(verbose),
+	)
+	workflowData, err := compiler.ParseWorkflowFile(workflowPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse workflow file: %w", err)
+	}
+
+	mcpWorkflowLoaderLog.Printf("Workflow parsed: name=%s, has_mcp_scripts=%t",
+		workflowData.Name, workflowData.MCPScripts != nil)
+
+	if verbose {
+		fmt.Fprintln(os.Stderr, console.FormatSuccessMessage("Workflow parsed successfully"))
+	}
+
+	// Build frontmatter map from WorkflowData for MCP extraction
+	// This includes all merged imports and tools
+	frontmatterForMCP := buildFrontmatterFromWorkflowData(workflowData)
+
+	// Extract MCP configurations from the merged frontmatter
+	mcpConfigs, err := parser.ExtractMCPConfigurations(frontmatterForMCP, serverFilter)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to extract MCP configurations: %w", err)
+	}
+
+	mcpWorkflowLoaderLog.Printf("Extracted %d MCP configs (server_filter=%q)", len(mcpConfigs), serverFilter)
+
+	// Filter out safe-outputs MCP servers for inspection
+	mcpConfigs = filterOutSafeOutputs(mcpConfigs)
+	mcpWorkflowLoaderLog.Printf("After filtering safe-outputs: %d MCP configs remain", len(mcpConfigs))
+
+	return workflowData, mcpConfigs, nil
+}
@@ -0,0 +1,494 @@
+// This file implements the `gh aw models` subcommands:
+//
+//   - list:    the models and aliases each engine supports, with their Effective
+//     Tokens multiplier and cost class
+//   - resolve: the fallback chain a workflow's engine.model resolves to
+//   - compare: the projected effective tokens (and cost, when aw.json has a cost
+//     model) of switching to other models, based on the token mix of cached runs
+//
+// Model catalogs and alias resolution come from pkg/workflow/model_catalog.go so
+// the output matches what the compiler validates.
+
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/constants"
+	"github.com/github/gh-aw/pkg/gitutil"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/workflow"
+)
+
+var modelsLog = logger.New("cli:models")
+
+// ModelListEntry is a model or alias supported by an engine
+type ModelListEntry struct {
+	Engine     string  `json:"engine"`
+	Name       string  `json:"name"`
+	Kind       string  `json:"kind"` // "alias" or "model"
+	ResolvesTo string  `json:"resolves_to,omitempty"`
+	Multiplier float64 `json:"multiplier,omitempty"`
+	CostClass  string  `json:"cost_class"`
+}
+
+// ModelResolution is the resolved fallback chain of a workflow's model
+type ModelResolution struct {
+	Workflow string                 `json:"workflow"`
+	Engine   string                 `json:"engine"`
+	Model    string                 `json:"model,omitempty"`
+	Source   string                 `json:"source"` // "frontmatter", "engine default" or "runtime"
+	Selected string                 `json:"selected,omitempty"`
+	Chain    []ModelResolutionEntry `json:"chain"`
+}
+
+// ModelResolutionEntry is one candidate of a resolved fallback chain
+type ModelResolutionEntry struct {
+	Entry      string   `json:"entry"`
+	Via        []string `json:"via,omitempty"`
+	ResolvesTo string   `json:"resolves_to,omitempty"`
+	Multiplier float64  `json:"multiplier,omitempty"`
+	CostClass  string   `json:"cost_class"`
+}
+
+// ModelComparison is the projected usage of one model for the compared token mix
+type ModelComparison struct {
+	Model           string  `json:"model" console:"header:Model"`
+	ResolvesTo      string  `json:"resolves_to,omitempty" console:"header:Resolves To"`
+	Multiplier      float64 `json:"multiplier" console:"header:Multiplier"`
+	CostClass       string  `json:"cost_class" console:"header:Cost Class"`
+	EffectiveTokens int     `json:"effective_tokens" console:"header:Effective Tokens,format:number"`
+	Change          string  `json:"change" console:"header:Change"`
+	Cost            float64 `json:"cost,omitempty" console:"header:Cost,format:cost"`
+}
+
+// ModelsCompareReport is the output of `models compare`
+type ModelsCompareReport struct {
+	Runs             int               `json:"runs"`
+	Workflow         string            `json:"workflow,omitempty"`
+	Engine           string            `json:"engine"`
+	Currency         string            `json:"currency,omitempty"`
+	InputTokens      int               `json:"input_tokens"`
+	OutputTokens     int               `json:"output_tokens"`
+	CacheReadTokens  int               `json:"cache_read_tokens"`
+	CacheWriteTokens int               `json:"cache_write_tokens"`
+	CurrentModels    []string          `json:"current_models"`
+	Current          ModelComparison   `json:"current"`
+	Candidates       []ModelComparison `json:"candidates"`
+}
+
+// ModelsCompareOptions holds the options of `models compare`
+type ModelsCompareOptions struct {
+	Candidates []string
+	Workflow   string
+	Engine     string
+	LogsDir    string
+	Last       int
+	JSON       bool
+	Verbose    bool
+}
+
+// modelEngines returns the engines to report on
+func modelEngines(engineFilter string) ([]string, error) {
+	engines := slices.Clone(constants.AgenticEngines)
+	if engineFilter == "" {
+		return engines, nil
+	}
+	if !slices.Contains(engines, engineFilter) {
+		return nil, fmt.Errorf("unknown engine '%s'; expected one of: %s", engineFilter, strings.Join(engines, ", "))
+	}
+	return []string{engineFilter}, nil
+}
+
+// buildModelList returns the aliases resolvable by each engine followed by the
+// models in the engine's catalog
+func buildModelList(engines []string) []ModelListEntry {
+	multipliers := workflow.BuiltinModelMultipliers()
+	aliases := workflow.BuiltinModelAliases()
+	aliasNames := slices.Sorted(maps.Keys(aliases))
+
+	var entries []ModelListEntry
+	for _, engine := range engines {
+		for _, alias := range aliasNames {
+			if alias == "" {
+				continue
+			}
+			candidate, ok := firstResolvedCandidate(workflow.ResolveModelFallbackChain(alias, aliases, engine, multipliers))
+			if !ok {
+				continue
+			}
+			entries = append(entries, ModelListEntry{
+				Engine:     engine,
+				Name:       alias,
+				Kind:       "alias",
+				ResolvesTo: candidate.Model,
+				Multiplier: candidate.Multiplier,
+				CostClass:  string(candidate.CostClass),
+			})
+		}
+		for _, model := range workflow.EngineModelCatalog(engine, multipliers) {
+			multiplier, _ := workflow.LookupModelMultiplier(model, multipliers)
+			entries = append(entries, ModelListEntry{
+				Engine:     engine,
+				Name:       model,
+				Kind:       "model",
+				Multiplier: multiplier,
+				CostClass:  string(workflow.CostClassForMultiplier(multiplier)),
+			})
+		}
+	}
+	modelsLog.Printf("Built model list for %d engine(s): %d entries", len(engines), len(entries))
+	return entries
+}
+
+// firstResolvedCandidate returns the candidate a fallback chain selects
+func firstResolvedCandidate(chain []workflow.ModelFallbackCandidate) (workflow.ModelFallbackCandidate, bool) {
+	for _, candidate := range chain {
+		if candidate.Resolved() {
+			return candidate, true
+		}
+	}
+	return workflow.ModelFallbackCandidate{}, false
+}
+
+// RunModelsList prints the models and aliases supported by each engine
+func RunModelsList(engineFilter string, jsonOutput bool) error {
+	engines, err := modelEngines(engineFilter)
+	if err != nil {
+		return err
+	}
+	entries := buildModelList(engines)
+
+	if jsonOutput {
+		jsonBytes, err := json.MarshalIndent(entries, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(jsonBytes))
+		return nil
+	}
+
+	for _, engine := range engines {
+		var rows [][]string
+		for _, entry := range entries {
+			if entry.Engine != engine {
+				continue
+			}
+			rows = append(rows, []string{entry.Name, entry.Kind, orDash(entry.ResolvesTo), formatMultiplier(entry.Multiplier), entry.CostClass})
+		}
+		fmt.Fprint(os.Stderr, console.RenderTable(console.TableConfig{
+			Title:   "Engine: " + engine,
+			Headers: []string{"Name", "Kind", "Resolves To", "Multiplier", "Cost Class"},
+			Rows:    rows,
+		}))
+	}
+	return nil
+}
+
+// resolveWorkflowModel parses a workflow and resolves its engine.model through
+// the workflow's merged alias map
+func resolveWorkflowModel(workflowFile string) (*ModelResolution, error) {
+	workflowPath, err := ResolveWorkflowPath(workflowFile)
+	if err != nil {
+		return nil, err
+	}
+	compiler := workflow.NewCompiler()
+	// Schedule scattering needs an identifier even though the schedule is not used here
+	compiler.SetWorkflowIdentifier(filepath.Base(workflowPath))
+	data, err := compiler.ParseWorkflowFile(workflowPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse workflow '%s': %w", workflowFile, err)
+	}
+
+	resolution := &ModelResolution{Workflow: workflowPath, Source: "frontmatter"}
+	if data.EngineConfig != nil {
+		resolution.Engine = data.EngineConfig.ID
+		resolution.Model = data.EngineConfig.Model
+	}
+	if resolution.Engine == "" {
+		resolution.Engine = string(constants.DefaultEngine)
+	}
+
+	switch {
+	case strings.Contains(resolution.Model, "${{"):
+		resolution.Source = "runtime"
+		return resolution, nil
+	case resolution.Model == "":
+		// The same default the compiler records for the run, resolved through the alias map
+		resolution.Model = workflow.DefaultAgentModel(resolution.Engine)
+		resolution.Source = "engine default"
+		if resolution.Model == "" {
+			return resolution, nil
+		}
+	}
+
+	multipliers := workflow.BuiltinModelMultipliers()
+	for _, candidate := range workflow.ResolveModelFallbackChain(resolution.Model, data.ModelMappings, resolution.Engine, multipliers) {
+		if candidate.Resolved() && resolution.Selected == "" {
+			resolution.Selected = candidate.Model
+		}
+		resolution.Chain = append(resolution.Chain, ModelResolutionEntry{
+			Entry:      candidate.Entry,
+			Via:        candidate.Via,
+			ResolvesTo: candidate.Model,
+			Multiplier: candidate.Multiplier,
+			CostClass:  string(candidate.CostClass),
+		})
+	}
+	modelsLog.Printf("Resolved model %q of %s for engine %s: %d candidate(s), selected=%s", resolution.Model, workflowPath, resolution.Engine, len(resolution.Chain), resolution.Selected)
+	return resolution, nil
+}
+
+// RunModelsResolve prints the fallback chain a workflow's model resolves to
+func RunModelsResolve(workflowFile string, jsonOutput bool) error {
+	resolution, err := resolveWorkflowModel(workflowFile)
+	if err != nil {
+		return err
+	}
+
+	if jsonOutput {
+		jsonBytes, err := json.MarshalIndent(resolution, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(jsonBytes))
+		return nil
+	}
+
+	switch {
+	case resolution.Source == "runtime":
+		fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("engine.model is the expression %s and is resolved at runtime", resolution.Model)))
+		return nil
+	case resolution.Model == "":
+		fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("No engine.model is set; engine '%s' uses its default model", resolution.Engine)))
+		return nil
+	}
+
+	fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("Engine '%s' with model %s (%s)", resolution.Engine, resolution.Model, resolution.Source)))
+	var rows [][]string
+	selected := false
+	for i, entry := range resolution.Chain {
+		status := "no match"
+		if entry.ResolvesTo != "" {
+			status = "fallback"
+			if !selected {
+				status = "selected"
+				selected = true
+			}
+		}
+		rows = append(rows, []string{fmt.Sprintf("%d", i+1), entry.Entry, orDash(strings.Join(entry.Via, " → ")), orDash(entry.ResolvesTo), formatMultiplier(entry.Multiplier), entry.CostClass, status})
+	}
+	fmt.Fprint(os.Stderr, console.RenderTable(console.TableConfig{
+		Title:   "Fallback chain",
+		Headers: []string{"#", "Entry", "Via", "Resolves To", "Multiplier", "Cost Class", "Status"},
+		Rows:    rows,
+	}))
+	if resolution.Selected == "" {
+		fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("No candidate resolves to a model available to engine '%s'", resolution.Engine)))
+	}
+	return nil
+}
+
+// loadCompareRunSummaries reads the cached run summaries with token usage from
+// logsDir, newest first, optionally filtered by workflow name or file
+func loadCompareRunSummaries(logsDir, workflowFilter string, last int, verbose bool) ([]*RunSummary, error) {
+	entries, err := os.ReadDir(logsDir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read logs directory %s: %w", logsDir, err)
+	}
+
+	var summaries []*RunSummary
+	for _, entry := range entries {
+		if !entry.IsDir() || !strings.HasPrefix(entry.Name(), "run-") {
+			continue
+		}
+		summary, ok := loadRunSummary(filepath.Join(logsDir, entry.Name()), verbose)
+		if !ok || summary.TokenUsage == nil || len(summary.TokenUsage.ByModel) == 0 {
+			continue
+		}
+		if workflowFilter != "" && !runMatchesWorkflow(summary.Run, workflowFilter) {
+			continue
+		}
+		summaries = append(summaries, summary)
+	}
+
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].Run.CreatedAt.After(summaries[j].Run.CreatedAt)
+	})
+	if last > 0 && len(summaries) > last {
+		summaries = summaries[:last]
+	}
+	modelsLog.Printf("Loaded %d cached run summaries from %s (workflow=%q)", len(summaries), logsDir, workflowFilter)
+	return summaries, nil
+}
+
+// runMatchesWorkflow reports whether a run belongs to the workflow given by name
+// or by workflow ID (file name without extension)
+func runMatchesWorkflow(run WorkflowRun, workflowFilter string) bool {
+	if strings.EqualFold(run.WorkflowName, workflowFilter) {
+		return true
+	}
+	id := strings.TrimSuffix(filepath.Base(run.WorkflowPath), ".lock.yml")
+	return id == strings.TrimSuffix(filepath.Base(workflowFilter), ".md")
+}
+
+// RunModelsCompare projects the effective tokens and cost of the candidate
+// models for the token mix of recent runs
+func RunModelsCompare(opts ModelsCompareOptions) error {
+	var costModel *workflow.CostModelConfig
+	if gitRoot, err := gitutil.FindGitRoot(); err == nil {
+		repoConfig, err := workflow.LoadRepoConfig(gitRoot)
+		if err != nil {
+			return err
+		}
+		costModel = repoConfig.CostModel()
+	}
+
+	summaries, err := loadCompareRunSummaries(opts.LogsDir, opts.Workflow, opts.Last, opts.Verbose)
+	if err != nil {
+		return err
+	}
+	if len(summaries) == 0 {
+		return fmt.Errorf("no cached runs with token usage found in %s; run '%s logs' first to download recent runs", opts.LogsDir, string(constants.CLIExtensionPrefix))
+	}
+
+	report, err := buildModelsCompareReport(summaries, opts, costModel)
+	if err != nil {
+		return err
+	}
+
+	if opts.JSON {
+		jsonBytes, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(jsonBytes))
+		return nil
+	}
+
+	fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("Token mix of %d run(s) using %s: %s input, %s output, %s cache read, %s cache write",
+		report.Runs, strings.Join(report.CurrentModels, ", "),
+		console.FormatNumber(report.InputTokens), console.FormatNumber(report.OutputTokens),
+		console.FormatNumber(report.CacheReadTokens), console.FormatNumber(report.CacheWriteTokens))))
+	fmt.Fprint(os.Stderr, console.RenderStruct(append([]ModelComparison{report.Current}, report.Candidates...)))
+	if costModel == nil {
+		fmt.Fprintln(os.Stderr, console.FormatInfoMessage("Add a cost model to "+workflow.RepoConfigFileName+" to include projected costs"))
+	}
+	return nil
+}
+
+// buildModelsCompareReport aggregates the token mix of the runs and projects it
+// onto each candidate model. The weighted token total (before the per-model
+// multiplier) stays the same; only the multiplier and price change.
+func buildModelsCompareReport(summaries []*RunSummary, opts ModelsCompareOptions, costModel *workflow.CostModelConfig) (*ModelsCompareReport, error) {
+	multipliers, weights := resolveEffectiveWeights(nil)
+	report := &ModelsCompareReport{Runs: len(summaries), Workflow: opts.Workflow, Engine: opts.Engine}
+	if costModel != nil {
+		report.Currency = costModel.CurrencyCode()
+	}
+
+	currentModels := make(map[string]bool)
+	currentPriced := costModel != nil
+	for _, summary := range summaries {
+		for model, usage := range summary.TokenUsage.ByModel {
+			if usage == nil {
+				continue
+			}
+			currentModels[model] = true
+			report.InputTokens += usage.InputTokens
+			report.OutputTokens += usage.OutputTokens
+			report.CacheReadTokens += usage.CacheReadTokens
+			report.CacheWriteTokens += usage.CacheWriteTokens
+			report.Current.EffectiveTokens += computeModelEffectiveTokensWithWeights(model, usage.InputTokens, usage.OutputTokens,
+				usage.CacheReadTokens, usage.CacheWriteTokens, multipliers, weights)
+			if price, ok := modelPrice(costModel, model); ok {
+				report.Current.Cost += price.Cost(usage.InputTokens, usage.OutputTokens, usage.CacheReadTokens, usage.CacheWriteTokens)
+			} else {
+				currentPriced = false
+			}
+		}
+	}
+	report.CurrentModels = slices.Sorted(maps.Keys(currentModels))
+	report.Current.Model = "current"
+	report.Current.ResolvesTo = strings.Join(report.CurrentModels, ", ")
+	report.Current.CostClass = "-"
+	report.Current.Change = "-"
+	if !currentPriced {
+		report.Current.Cost = 0
+	}
+
+	// Weighted tokens before the model multiplier: an empty model uses multiplier 1.0
+	baseTokens := computeModelEffectiveTokensWithWeights("", report.InputTokens, report.OutputTokens,
+		report.CacheReadTokens, report.CacheWriteTokens, multipliers, weights)
+	aliases := workflow.BuiltinModelAliases()
+	for _, candidate := range opts.Candidates {
+		comparison := ModelComparison{Model: candidate, ResolvesTo: candidate}
+		if chain := workflow.ResolveModelFallbackChain(candidate, aliases, opts.Engine, multipliers); len(chain) > 0 && len(chain[0].Via) > 0 {
+			resolved, ok := firstResolvedCandidate(chain)
+			if !ok {
+				return nil, fmt.Errorf("alias %q does not resolve to any model available to engine '%s'", candidate, opts.Engine)
+			}
+			comparison.ResolvesTo = resolved.Model
+		}
+		multiplier, ok := workflow.LookupModelMultiplier(comparison.ResolvesTo, multipliers)
+		if !ok {
+			return nil, fmt.Errorf("unknown model %q: it is neither a model alias nor a model with a known effective token multiplier", candidate)
+		}
+		comparison.Multiplier = multiplier
+		comparison.CostClass = string(workflow.CostClassForMultiplier(multiplier))
+		comparison.EffectiveTokens = int(float64(baseTokens) * multiplier)
+		comparison.Change = formatPercentChange(report.Current.EffectiveTokens, comparison.EffectiveTokens)
+		if price, ok := modelPrice(costModel, comparison.ResolvesTo); ok {
+			comparison.Cost = price.Cost(report.InputTokens, report.OutputTokens, report.CacheReadTokens, report.CacheWriteTokens)
+		}
+		report.Candidates = append(report.Candidates, comparison)
+	}
+	return report, nil
+}
+
+// modelPrice looks up a model in the cost model, with and without its provider prefix
+func modelPrice(costModel *workflow.CostModelConfig, model string) (workflow.ModelPrice, bool) {
+	if price, ok := costModel.PriceFor(model); ok {
+		return price, true
+	}
+	if _, name, found := strings.Cut(model, "/"); found {
+		return costModel.PriceFor(name)
+	}
+	return workflow.ModelPrice{}, false
+}
+
+// formatPercentChange formats the relative change from current to projected
+func formatPercentChange(current, projected int) string {
+	if current == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%+.0f%%", (float64(projected)-float64(current))/float64(current)*100)
+}
+
+// formatMultiplier formats an effective token multiplier, or "-" when unknown
+func formatMultiplier(multiplier float64) string {
+	if multiplier == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%gx", multiplier)
+}
+
+// orDash returns s, or "-" when s is empty
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
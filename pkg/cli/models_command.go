@@ -0,0 +1,145 @@
+package cli
+
+import (
+	"github.com/github/gh-aw/pkg/constants"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/spf13/cobra"
+)
+
+var modelsCommandLog = logger.New("cli:models_command")
+
+// NewModelsCommand creates the models command with subcommands
+func NewModelsCommand() *cobra.Command {
+	modelsCommandLog.Print("Creating models command with subcommands")
+	cmd := &cobra.Command{
+		Use:   "models",
+		Short: "Inspect engine models, model aliases and their cost",
+		Long: `Inspect the models each engine supports, how model aliases resolve and what
+switching models would cost.
+
+Available subcommands:
+  - list    - Show each engine's models, aliases and effective token multiplier
+  - resolve - Show the fallback chain a workflow's engine.model resolves to
+  - compare - Project the cost of switching models from recent runs' token mix
+
+Examples:
+  ` + string(constants.CLIExtensionPrefix) + ` models list --engine claude        # Models and aliases available to Claude
+  ` + string(constants.CLIExtensionPrefix) + ` models resolve my-workflow         # Fallback chain used by my-workflow
+  ` + string(constants.CLIExtensionPrefix) + ` models compare haiku gpt-5-mini    # Projected cost of switching models`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(newModelsListSubcommand())
+	cmd.AddCommand(newModelsResolveSubcommand())
+	cmd.AddCommand(newModelsCompareSubcommand())
+
+	return cmd
+}
+
+func newModelsListSubcommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List each engine's supported models, aliases and effective token multiplier",
+		Long: `List the models each engine can run and the built-in aliases that resolve for it.
+
+Aliases are shown with the model they resolve to. Every model is shown with its
+effective token multiplier and cost class (economy below 0.5x, standard below 5x,
+premium from 5x).
+
+Examples:
+  ` + string(constants.CLIExtensionPrefix) + ` models list                  # All engines
+  ` + string(constants.CLIExtensionPrefix) + ` models list --engine codex   # Only the Codex engine
+  ` + string(constants.CLIExtensionPrefix) + ` models list --json           # Machine-readable output`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			engine, _ := cmd.Flags().GetString("engine")
+			jsonOutput, _ := cmd.Flags().GetBool("json")
+			return RunModelsList(engine, jsonOutput)
+		},
+	}
+
+	cmd.Flags().StringP("engine", "e", "", "Only list models for this engine")
+	addJSONFlag(cmd)
+	RegisterEngineFlagCompletion(cmd)
+
+	return cmd
+}
+
+func newModelsResolveSubcommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "resolve <workflow>",
+		Short: "Show the model fallback chain a workflow will use",
+		Long: `Show the fallback chain of a workflow's engine.model: every candidate the alias
+expands to, in order, with the model it resolves to for the workflow's engine.
+The first resolved candidate is selected; the others are used when it is
+unavailable at runtime.
+
+The workflow's own models: aliases and imported aliases are taken into account.
+Without engine.model, the engine's default model is resolved.
+
+Examples:
+  ` + string(constants.CLIExtensionPrefix) + ` models resolve my-workflow
+  ` + string(constants.CLIExtensionPrefix) + ` models resolve .github/workflows/my-workflow.md --json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			jsonOutput, _ := cmd.Flags().GetBool("json")
+			return RunModelsResolve(args[0], jsonOutput)
+		},
+		ValidArgsFunction: CompleteWorkflowNames,
+	}
+
+	addJSONFlag(cmd)
+
+	return cmd
+}
+
+func newModelsCompareSubcommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "compare <model>...",
+		Short: "Project the cost of switching models based on recent runs' token mix",
+		Long: `Project the effective tokens and cost of running recent workflow runs on other
+models. The token mix (input, output, cache read and cache write tokens) of the
+cached runs downloaded by '` + string(constants.CLIExtensionPrefix) + ` logs' is kept and re-weighted with
+each candidate model's effective token multiplier.
+
+Candidates can be model names or aliases; aliases are resolved for --engine.
+Costs are shown when .github/workflows/aw.json defines a cost model that prices the model.
+
+Examples:
+  ` + string(constants.CLIExtensionPrefix) + ` models compare haiku mini                       # All cached runs
+  ` + string(constants.CLIExtensionPrefix) + ` models compare gpt-5-mini --workflow triage     # Runs of one workflow
+  ` + string(constants.CLIExtensionPrefix) + ` models compare sonnet --engine claude --last 5  # Latest five runs`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			workflowName, _ := cmd.Flags().GetString("workflow")
+			engine, _ := cmd.Flags().GetString("engine")
+			logsDir, _ := cmd.Flags().GetString("logs-dir")
+			last, _ := cmd.Flags().GetInt("last")
+			jsonOutput, _ := cmd.Flags().GetBool("json")
+			verbose, _ := cmd.Flags().GetBool("verbose")
+			if _, err := modelEngines(engine); err != nil {
+				return err
+			}
+			return RunModelsCompare(ModelsCompareOptions{
+				Candidates: args,
+				Workflow:   workflowName,
+				Engine:     engine,
+				LogsDir:    logsDir,
+				Last:       last,
+				JSON:       jsonOutput,
+				Verbose:    verbose,
+			})
+		},
+	}
+
+	cmd.Flags().StringP("workflow", "w", "", "Only use runs of this workflow (name or workflow ID)")
+	cmd.Flags().StringP("engine", "e", string(constants.CopilotEngine), "Engine used to resolve model aliases")
+	cmd.Flags().String("logs-dir", defaultLogsOutputDir, "Directory with run summaries downloaded by the logs command")
+	cmd.Flags().Int("last", 20, "Number of most recent runs to use (0 for all)")
+	addJSONFlag(cmd)
+	RegisterEngineFlagCompletion(cmd)
+
+	return cmd
+}
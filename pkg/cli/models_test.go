@@ -0,0 +1,161 @@
+//go:build !integration
+
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestModelEngines(t *testing.T) {
+	engines, err := modelEngines("")
+	require.NoError(t, err)
+	assert.NotContains(t, engines, "replay", "replay has no models")
+	assert.Contains(t, engines, "claude")
+
+	engines, err = modelEngines("codex")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"codex"}, engines)
+
+	_, err = modelEngines("replay")
+	require.Error(t, err, "replay should be rejected")
+	assert.Contains(t, err.Error(), "expected one of", "error should list the engines")
+}
+
+func TestBuildModelList(t *testing.T) {
+	entries := buildModelList([]string{"claude"})
+	require.NotEmpty(t, entries)
+
+	var haiku *ModelListEntry
+	for i := range entries {
+		assert.Equal(t, "claude", entries[i].Engine)
+		if entries[i].Kind == "model" {
+			assert.Regexp(t, `^anthropic/claude-`, entries[i].Name, "claude should only list Anthropic models")
+		}
+		if entries[i].Name == "haiku" {
+			haiku = &entries[i]
+		}
+	}
+	require.NotNil(t, haiku, "the haiku alias should resolve for claude")
+	assert.Equal(t, "alias", haiku.Kind)
+	assert.Regexp(t, `^anthropic/claude-haiku`, haiku.ResolvesTo)
+	assert.Equal(t, string(workflow.ModelCostClassEconomy), haiku.CostClass)
+}
+
+func TestResolveWorkflowModel(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "triage.md")
+	content := "---\non:\n  workflow_dispatch:\nengine:\n  id: claude\n  model: smart\nmodels:\n  smart: [copilot/*sonnet*, haiku]\n---\n\n# Triage\n"
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+
+	resolution, err := resolveWorkflowModel(path)
+	require.NoError(t, err)
+	assert.Equal(t, "claude", resolution.Engine)
+	assert.Equal(t, "smart", resolution.Model)
+	assert.Equal(t, "frontmatter", resolution.Source)
+	require.NotEmpty(t, resolution.Chain, "haiku should resolve for claude")
+	assert.Equal(t, []string{"smart", "haiku"}, resolution.Chain[0].Via, "copilot entries should be left out for claude")
+	assert.Equal(t, resolution.Chain[0].ResolvesTo, resolution.Selected)
+}
+
+func TestResolveWorkflowModel_EngineDefault(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "triage.md")
+	content := "---\non:\n  workflow_dispatch:\nengine: claude\n---\n\n# Triage\n"
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+
+	resolution, err := resolveWorkflowModel(path)
+	require.NoError(t, err)
+	assert.Equal(t, "engine default", resolution.Source)
+	assert.Equal(t, workflow.DefaultAgentModel("claude"), resolution.Model, "the engine's default model should be resolved")
+	require.NotEmpty(t, resolution.Chain, "the default model should have a fallback chain")
+	assert.Regexp(t, `^anthropic/`, resolution.Selected)
+}
+
+// writeCompareRunSummary caches a run summary with token usage for one model
+func writeCompareRunSummary(t *testing.T, logsDir string, runID int64, workflowName, model string, usage ModelTokenUsage) {
+	t.Helper()
+	summary := RunSummary{
+		CLIVersion: GetVersion(),
+		RunID:      runID,
+		Run: WorkflowRun{
+			DatabaseID:   runID,
+			WorkflowName: workflowName,
+			WorkflowPath: ".github/workflows/" + workflowName + ".lock.yml",
+			CreatedAt:    time.Unix(runID, 0),
+		},
+		TokenUsage: &TokenUsageSummary{ByModel: map[string]*ModelTokenUsage{model: &usage}},
+	}
+	data, err := json.Marshal(summary)
+	require.NoError(t, err)
+	runDir := filepath.Join(logsDir, fmt.Sprintf("run-%d", runID))
+	require.NoError(t, os.MkdirAll(runDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(runDir, runSummaryFileName), data, 0644))
+}
+
+func TestLoadCompareRunSummaries(t *testing.T) {
+	logsDir := t.TempDir()
+	usage := ModelTokenUsage{InputTokens: 1000}
+	writeCompareRunSummary(t, logsDir, 1, "triage", "gpt-5", usage)
+	writeCompareRunSummary(t, logsDir, 2, "triage", "gpt-5", usage)
+	writeCompareRunSummary(t, logsDir, 3, "review", "gpt-5", usage)
+
+	summaries, err := loadCompareRunSummaries(logsDir, "", 0, false)
+	require.NoError(t, err)
+	assert.Len(t, summaries, 3)
+	assert.Equal(t, int64(3), summaries[0].RunID, "newest run should come first")
+
+	summaries, err = loadCompareRunSummaries(logsDir, "triage", 1, false)
+	require.NoError(t, err)
+	require.Len(t, summaries, 1, "--last should limit the runs")
+	assert.Equal(t, int64(2), summaries[0].RunID)
+
+	summaries, err = loadCompareRunSummaries(filepath.Join(logsDir, "missing"), "", 0, false)
+	require.NoError(t, err, "a missing logs directory is not an error")
+	assert.Empty(t, summaries)
+}
+
+func TestBuildModelsCompareReport(t *testing.T) {
+	logsDir := t.TempDir()
+	writeCompareRunSummary(t, logsDir, 1, "triage", "gpt-5", ModelTokenUsage{InputTokens: 1000, OutputTokens: 100, CacheReadTokens: 1000})
+	summaries, err := loadCompareRunSummaries(logsDir, "", 0, false)
+	require.NoError(t, err)
+
+	costModel := &workflow.CostModelConfig{Models: map[string]workflow.ModelPrice{"claude-haiku-*": {Input: 2, Output: 10}}}
+	opts := ModelsCompareOptions{Candidates: []string{"haiku", "gpt-5.1"}, Engine: "copilot"}
+	report, err := buildModelsCompareReport(summaries, opts, costModel)
+	require.NoError(t, err)
+
+	// 1000 input + 0.1 × 1000 cache read + 4 × 100 output = 1500 weighted tokens
+	assert.Equal(t, 1500, report.Current.EffectiveTokens, "gpt-5 is the reference model")
+	assert.Equal(t, []string{"gpt-5"}, report.CurrentModels)
+	assert.Zero(t, report.Current.Cost, "gpt-5 is not priced")
+	require.Len(t, report.Candidates, 2)
+
+	haiku := report.Candidates[0]
+	assert.Regexp(t, `^copilot/claude-haiku`, haiku.ResolvesTo, "aliases should be resolved for the engine")
+	assert.Equal(t, string(workflow.ModelCostClassEconomy), haiku.CostClass)
+	assert.Equal(t, int(1500*haiku.Multiplier), haiku.EffectiveTokens)
+	assert.Equal(t, "-67%", haiku.Change)
+	assert.InDelta(t, (2000*2.0+100*10.0)/1_000_000, haiku.Cost, 1e-9, "cache reads should default to the input price")
+
+	model := report.Candidates[1]
+	assert.Equal(t, "gpt-5.1", model.ResolvesTo, "model names should be used as is")
+	assert.Equal(t, formatPercentChange(1500, model.EffectiveTokens), model.Change)
+	assert.Zero(t, model.Cost, "unpriced models should have no cost")
+}
+
+func TestBuildModelsCompareReport_UnknownModel(t *testing.T) {
+	summaries := []*RunSummary{{TokenUsage: &TokenUsageSummary{ByModel: map[string]*ModelTokenUsage{"gpt-5": {InputTokens: 10}}}}}
+	_, err := buildModelsCompareReport(summaries, ModelsCompareOptions{Candidates: []string{"llama-3"}, Engine: "copilot"}, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown model "llama-3"`)
+}
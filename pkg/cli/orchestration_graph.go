@@ -0,0 +1,764 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/goccy/go-yaml"
+)
+
+var orchestrationGraphLog = logger.New("cli:orchestration_graph")
+
+// Kinds of orchestration edges
+const (
+	edgeDispatchWorkflow   = "dispatch-workflow"
+	edgeCallWorkflow       = "call-workflow"
+	edgeReusableWorkflow   = "uses"
+	edgeWorkflowRun        = "workflow_run"
+	edgeDispatchRepository = "dispatch_repository"
+)
+
+// Kinds of orchestration issues
+const (
+	graphIssueCycle       = "cycle"
+	graphIssueUnreachable = "unreachable"
+	graphIssueUnresolved  = "unresolved"
+	graphIssueContract    = "contract"
+)
+
+// Severity levels of orchestration issues
+const (
+	graphSeverityError   = "error"
+	graphSeverityWarning = "warning"
+)
+
+// OrchestrationGraph is the static graph of how workflows trigger each other
+type OrchestrationGraph struct {
+	Nodes  []*OrchestrationNode `json:"nodes"`
+	Edges  []OrchestrationEdge  `json:"edges"`
+	Issues []OrchestrationIssue `json:"issues"`
+}
+
+// OrchestrationNode is a workflow in the graph. External nodes are targets in
+// other repositories, which are shown but not checked.
+type OrchestrationNode struct {
+	ID       string   `json:"id"`
+	Name     string   `json:"name,omitempty"`
+	File     string   `json:"file,omitempty"`
+	Triggers []string `json:"triggers,omitempty"`
+	External bool     `json:"external,omitempty"`
+
+	dispatchInputs map[string]orchestrationInput
+	callInputs     map[string]orchestrationInput
+	runSources     []string
+}
+
+// OrchestrationEdge is a directed trigger relationship between two workflows
+type OrchestrationEdge struct {
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Kind   string `json:"kind"`
+	Detail string `json:"detail,omitempty"`
+
+	// passedInputs are the inputs the compiled caller sends; nil when unknown
+	passedInputs map[string]bool
+}
+
+// OrchestrationIssue is a problem found in the orchestration graph
+type OrchestrationIssue struct {
+	Kind     string `json:"kind"`
+	Severity string `json:"severity"`
+	Workflow string `json:"workflow"`
+	Message  string `json:"message"`
+}
+
+// orchestrationInput is a declared workflow_dispatch or workflow_call input
+type orchestrationInput struct {
+	Required bool
+}
+
+// localWorkflowUsesPattern matches reusable workflow references within the repository
+var localWorkflowUsesPattern = regexp.MustCompile(`^\./\.github/workflows/([^/@]+?)(?:\.lock)?\.ya?ml$`)
+
+// buildOrchestrationGraph reads every compiled lock file and plain GitHub Actions
+// workflow in workflowDir and builds the graph of dispatch-workflow, call-workflow,
+// workflow_run and dispatch_repository relationships between them. Lock files are
+// used rather than markdown sources so that imported safe-outputs are included and
+// the inputs the compiled caller actually passes can be checked.
+func buildOrchestrationGraph(workflowDir string) (*OrchestrationGraph, error) {
+	orchestrationGraphLog.Printf("Building orchestration graph from %s", workflowDir)
+
+	files, err := filepath.Glob(filepath.Join(workflowDir, "*.yml"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workflow files: %w", err)
+	}
+	yamlFiles, err := filepath.Glob(filepath.Join(workflowDir, "*.yaml"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workflow files: %w", err)
+	}
+	files = append(files, yamlFiles...)
+	sort.Strings(files)
+
+	graph := &OrchestrationGraph{Nodes: []*OrchestrationNode{}, Edges: []OrchestrationEdge{}, Issues: []OrchestrationIssue{}}
+	parsed := make(map[string]map[string]any)
+	contents := make(map[string]string)
+	for _, file := range files {
+		id := orchestrationWorkflowID(file)
+		if _, exists := parsed[id]; exists {
+			// x.lock.yml sorts before x.yml, so a compiled workflow wins over a plain one of the same name
+			continue
+		}
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read workflow file: %w", err)
+		}
+		workflowYAML, err := parseWorkflowOutline(string(content))
+		if err != nil {
+			orchestrationGraphLog.Printf("Skipping %s: %v", file, err)
+			continue
+		}
+		parsed[id] = workflowYAML
+		contents[id] = string(content)
+		graph.addNode(newOrchestrationNode(id, filepath.ToSlash(file), workflowYAML))
+	}
+
+	for _, node := range slices.Clone(graph.Nodes) {
+		graph.addOutgoingEdges(node, parsed[node.ID], contents[node.ID])
+	}
+	graph.resolveWorkflowRunEdges()
+	graph.sortEdges()
+	graph.analyze()
+
+	orchestrationGraphLog.Printf("Orchestration graph: nodes=%d, edges=%d, issues=%d", len(graph.Nodes), len(graph.Edges), len(graph.Issues))
+	return graph, nil
+}
+
+// orchestrationWorkflowID returns the workflow ID for a workflow file (the file name without extension)
+func orchestrationWorkflowID(file string) string {
+	base := filepath.Base(file)
+	for _, ext := range []string{".lock.yml", ".yml", ".yaml"} {
+		if trimmed, ok := strings.CutSuffix(base, ext); ok {
+			return trimmed
+		}
+	}
+	return base
+}
+
+// newOrchestrationNode extracts the triggers and declared inputs of a workflow
+func newOrchestrationNode(id, file string, workflowYAML map[string]any) *OrchestrationNode {
+	node := &OrchestrationNode{ID: id, File: file, Name: id}
+	if name, ok := workflowYAML["name"].(string); ok && name != "" {
+		node.Name = name
+	} else {
+		// GitHub uses the file path as the name of unnamed workflows
+		node.Name = file
+	}
+
+	switch on := workflowYAML["on"].(type) {
+	case string:
+		node.Triggers = []string{on}
+	case []any:
+		for _, event := range on {
+			node.Triggers = append(node.Triggers, fmt.Sprint(event))
+		}
+	case map[string]any:
+		for event, config := range on {
+			node.Triggers = append(node.Triggers, event)
+			configMap, _ := config.(map[string]any)
+			switch event {
+			case "workflow_dispatch":
+				node.dispatchInputs = parseOrchestrationInputs(configMap["inputs"])
+			case "workflow_call":
+				node.callInputs = parseOrchestrationInputs(configMap["inputs"])
+			case "workflow_run":
+				node.runSources = toStringList(configMap["workflows"])
+			}
+		}
+	}
+	sort.Strings(node.Triggers)
+	return node
+}
+
+// parseOrchestrationInputs reads an inputs block. An input must be supplied by the
+// caller when it is required and has no default.
+func parseOrchestrationInputs(raw any) map[string]orchestrationInput {
+	inputs := make(map[string]orchestrationInput)
+	inputsMap, _ := raw.(map[string]any)
+	for name, rawInput := range inputsMap {
+		input, _ := rawInput.(map[string]any)
+		required, _ := input["required"].(bool)
+		_, hasDefault := input["default"]
+		inputs[name] = orchestrationInput{Required: required && !hasDefault}
+	}
+	return inputs
+}
+
+// toStringList converts a YAML string or list of strings into a slice
+func toStringList(raw any) []string {
+	switch value := raw.(type) {
+	case string:
+		return []string{value}
+	case []any:
+		var result []string
+		for _, item := range value {
+			if s, ok := item.(string); ok {
+				result = append(result, s)
+			}
+		}
+		return result
+	}
+	return nil
+}
+
+// addOutgoingEdges adds the edges a compiled workflow triggers: dispatch-workflow and
+// dispatch_repository targets from the safe outputs config, and reusable workflow jobs
+// (including call-workflow fan-out jobs) from uses: references
+func (g *OrchestrationGraph) addOutgoingEdges(node *OrchestrationNode, workflowYAML map[string]any, content string) {
+	if match := lockSafeOutputsConfigRegexp.FindStringSubmatch(content); match != nil {
+		g.addSafeOutputEdges(node, match[1], dispatchToolInputs(content))
+	}
+
+	callWorkflows := make(map[string]bool)
+	jobs, _ := workflowYAML["jobs"].(map[string]any)
+	for _, jobName := range sortedLockKeys(mapKeySet(jobs)) {
+		job, _ := jobs[jobName].(map[string]any)
+		uses, _ := job["uses"].(string)
+		match := localWorkflowUsesPattern.FindStringSubmatch(uses)
+		if match == nil {
+			continue
+		}
+		with, _ := job["with"].(map[string]any)
+		passed := make(map[string]bool, len(with))
+		for input := range with {
+			passed[input] = true
+		}
+		callWorkflows[match[1]] = true
+		// call-workflow fan-out jobs are gated on the agent's choice; other uses: jobs always run
+		kind := edgeReusableWorkflow
+		if condition, _ := job["if"].(string); strings.Contains(condition, "call_workflow_name") {
+			kind = edgeCallWorkflow
+		}
+		g.Edges = append(g.Edges, OrchestrationEdge{From: node.ID, To: match[1], Kind: kind, Detail: "job " + jobName, passedInputs: passed})
+	}
+	orchestrationGraphLog.Printf("Workflow %s calls %d reusable workflow(s)", node.ID, len(callWorkflows))
+}
+
+// addSafeOutputEdges adds dispatch-workflow and dispatch_repository edges from the
+// safe outputs config embedded in a lock file
+func (g *OrchestrationGraph) addSafeOutputEdges(node *OrchestrationNode, configJSON string, toolInputs map[string]map[string]bool) {
+	var config struct {
+		DispatchWorkflow *struct {
+			Workflows  []string `json:"workflows"`
+			TargetRepo string   `json:"target-repo"`
+		} `json:"dispatch_workflow"`
+		DispatchRepository *struct {
+			Tools map[string]struct {
+				Workflow            string   `json:"workflow"`
+				EventType           string   `json:"event_type"`
+				Repository          string   `json:"repository"`
+				AllowedRepositories []string `json:"allowed_repositories"`
+			} `json:"tools"`
+		} `json:"dispatch_repository"`
+	}
+	if err := json.Unmarshal([]byte(configJSON), &config); err != nil {
+		orchestrationGraphLog.Printf("Failed to parse safe outputs config of %s: %v", node.ID, err)
+		return
+	}
+
+	if dispatch := config.DispatchWorkflow; dispatch != nil {
+		for _, target := range dispatch.Workflows {
+			if strings.Contains(target, "${{") {
+				continue // resolved at runtime
+			}
+			to := target
+			if dispatch.TargetRepo != "" {
+				to = g.addExternalNode(dispatch.TargetRepo, target)
+			}
+			edge := OrchestrationEdge{From: node.ID, To: to, Kind: edgeDispatchWorkflow}
+			if inputs, ok := toolInputs[target]; ok {
+				edge.passedInputs = inputs
+			}
+			g.Edges = append(g.Edges, edge)
+		}
+	}
+
+	if dispatch := config.DispatchRepository; dispatch != nil {
+		for _, toolName := range sortedLockKeys(mapKeySet(dispatch.Tools)) {
+			tool := dispatch.Tools[toolName]
+			repos := tool.AllowedRepositories
+			if tool.Repository != "" {
+				repos = []string{tool.Repository}
+			}
+			for _, repo := range repos {
+				to := g.addExternalNode(repo, tool.Workflow)
+				g.Edges = append(g.Edges, OrchestrationEdge{From: node.ID, To: to, Kind: edgeDispatchRepository, Detail: tool.EventType})
+			}
+		}
+	}
+}
+
+// dispatchToolInputs returns, per dispatch-workflow target, the input names the
+// caller's generated MCP tool accepts (and therefore may pass on dispatch)
+func dispatchToolInputs(content string) map[string]map[string]bool {
+	result := make(map[string]map[string]bool)
+	for _, metaJSON := range extractBlockScalars(content, "GH_AW_TOOLS_META_JSON") {
+		var meta struct {
+			DynamicTools []struct {
+				WorkflowName string `json:"_workflow_name"`
+				InputSchema  struct {
+					Properties map[string]any `json:"properties"`
+				} `json:"inputSchema"`
+			} `json:"dynamic_tools"`
+		}
+		if err := json.Unmarshal([]byte(metaJSON), &meta); err != nil {
+			orchestrationGraphLog.Printf("Failed to parse tools metadata: %v", err)
+			continue
+		}
+		for _, tool := range meta.DynamicTools {
+			if tool.WorkflowName == "" {
+				continue
+			}
+			inputs := make(map[string]bool, len(tool.InputSchema.Properties))
+			for input := range tool.InputSchema.Properties {
+				inputs[input] = true
+			}
+			result[tool.WorkflowName] = inputs
+		}
+	}
+	return result
+}
+
+// extractBlockScalars returns the contents of every "key: |" literal block in a YAML document
+func extractBlockScalars(content, key string) []string {
+	var blocks []string
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		trimmed := strings.TrimLeft(line, " ")
+		if trimmed != key+": |" {
+			continue
+		}
+		keyIndent := len(line) - len(trimmed)
+		var block []string
+		for _, next := range lines[i+1:] {
+			nextTrimmed := strings.TrimLeft(next, " ")
+			if nextTrimmed != "" && len(next)-len(nextTrimmed) <= keyIndent {
+				break
+			}
+			block = append(block, nextTrimmed)
+		}
+		blocks = append(blocks, strings.Join(block, "\n"))
+	}
+	return blocks
+}
+
+// parseWorkflowOutline parses the parts of a workflow file the graph needs: every
+// top-level key except jobs, and only the jobs that call a reusable workflow in this
+// repository. Lock files are large, and fully parsing all of them would noticeably
+// slow down compiling a whole directory.
+func parseWorkflowOutline(content string) (map[string]any, error) {
+	lines := strings.Split(content, "\n")
+	jobsStart := slices.IndexFunc(lines, func(line string) bool { return strings.TrimRight(line, " ") == "jobs:" })
+	if jobsStart < 0 {
+		var workflowYAML map[string]any
+		err := yaml.Unmarshal([]byte(content), &workflowYAML)
+		return workflowYAML, err
+	}
+	jobsEnd := len(lines)
+	for i := jobsStart + 1; i < len(lines); i++ {
+		if line := lines[i]; line != "" && line[0] != ' ' && line[0] != '#' {
+			jobsEnd = i
+			break
+		}
+	}
+
+	header := strings.Join(slices.Concat(lines[:jobsStart], lines[jobsEnd:]), "\n")
+	var workflowYAML map[string]any
+	if err := yaml.Unmarshal([]byte(header), &workflowYAML); err != nil {
+		return nil, err
+	}
+	if workflowYAML == nil {
+		workflowYAML = make(map[string]any)
+	}
+
+	// Split the jobs section into one block per job, using the indentation of the first job
+	jobs := make(map[string]any)
+	jobIndent := -1
+	var block []string
+	flush := func() {
+		text := strings.Join(block, "\n")
+		if len(block) == 0 || !strings.Contains(text, "./.github/workflows/") {
+			return
+		}
+		var job map[string]any
+		if err := yaml.Unmarshal([]byte(text), &job); err != nil {
+			orchestrationGraphLog.Printf("Failed to parse job block: %v", err)
+			return
+		}
+		maps.Copy(jobs, job)
+	}
+	for _, line := range lines[jobsStart+1 : jobsEnd] {
+		trimmed := strings.TrimLeft(line, " ")
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			block = append(block, line)
+			continue
+		}
+		indent := len(line) - len(trimmed)
+		if jobIndent < 0 {
+			jobIndent = indent
+		}
+		if indent == jobIndent {
+			flush()
+			block = nil
+		}
+		block = append(block, line[min(indent, jobIndent):])
+	}
+	flush()
+	workflowYAML["jobs"] = jobs
+	return workflowYAML, nil
+}
+
+// addExternalNode adds a node for a workflow in another repository and returns its ID
+func (g *OrchestrationGraph) addExternalNode(repo, workflowName string) string {
+	id := repo
+	if workflowName != "" {
+		id = path.Join(repo, workflowName)
+	}
+	if g.node(id) == nil {
+		g.addNode(&OrchestrationNode{ID: id, Name: id, External: true})
+	}
+	return id
+}
+
+// resolveWorkflowRunEdges links workflow_run listeners to the workflows they listen to.
+// workflow_run refers to workflows by name, so names are matched against every node.
+func (g *OrchestrationGraph) resolveWorkflowRunEdges() {
+	byName := make(map[string][]string)
+	for _, node := range g.Nodes {
+		if !node.External {
+			byName[node.Name] = append(byName[node.Name], node.ID)
+		}
+	}
+	for _, node := range g.Nodes {
+		for _, source := range node.runSources {
+			sources, ok := byName[source]
+			if !ok {
+				g.addIssue(graphIssueUnresolved, graphSeverityWarning, node.ID,
+					fmt.Sprintf("workflow_run listens to %q but no workflow in this repository has that name", source))
+				continue
+			}
+			for _, from := range sources {
+				g.Edges = append(g.Edges, OrchestrationEdge{From: from, To: node.ID, Kind: edgeWorkflowRun})
+			}
+		}
+	}
+}
+
+// analyze checks the graph for unresolved targets, caller/callee input contract
+// mismatches, cycles and reusable workers that nothing calls
+func (g *OrchestrationGraph) analyze() {
+	incoming := make(map[string]int)
+	for _, edge := range g.Edges {
+		incoming[edge.To]++
+		target := g.node(edge.To)
+		if target == nil {
+			g.addIssue(graphIssueUnresolved, graphSeverityError, edge.From,
+				fmt.Sprintf("%s target %q does not exist in this repository (compile or add the workflow first)", edge.Kind, edge.To))
+			continue
+		}
+		if target.External {
+			continue
+		}
+		switch edge.Kind {
+		case edgeDispatchWorkflow:
+			g.checkInputContract(edge, target, "workflow_dispatch", target.dispatchInputs)
+		case edgeCallWorkflow, edgeReusableWorkflow:
+			g.checkInputContract(edge, target, "workflow_call", target.callInputs)
+		}
+	}
+
+	for _, node := range g.Nodes {
+		if !node.External && slices.Equal(node.Triggers, []string{"workflow_call"}) && incoming[node.ID] == 0 {
+			g.addIssue(graphIssueUnreachable, graphSeverityWarning, node.ID,
+				"only triggered by workflow_call, but no workflow calls it")
+		}
+	}
+
+	cycles, truncated := g.findCycles()
+	for _, cycle := range cycles {
+		g.addIssue(graphIssueCycle, graphSeverityWarning, cycle[0],
+			"orchestration cycle: "+strings.Join(append(cycle, cycle[0]), " → ")+" (make sure every step is guarded against retriggering)")
+	}
+	if truncated {
+		g.addIssue(graphIssueCycle, graphSeverityWarning, cycles[0][0],
+			fmt.Sprintf("more than %d orchestration cycles found; only the first %d are reported", maxReportedCycles, maxReportedCycles))
+	}
+}
+
+// checkInputContract verifies a caller/callee pair: the callee must accept the trigger,
+// every input the caller passes must be declared, and every required input without
+// a default must be passed
+func (g *OrchestrationGraph) checkInputContract(edge OrchestrationEdge, target *OrchestrationNode, trigger string, declared map[string]orchestrationInput) {
+	if !slices.Contains(target.Triggers, trigger) {
+		g.addIssue(graphIssueContract, graphSeverityError, edge.From,
+			fmt.Sprintf("%s target %q does not declare a %s trigger", edge.Kind, target.ID, trigger))
+		return
+	}
+	if edge.passedInputs == nil {
+		return
+	}
+	for _, input := range sortedLockKeys(edge.passedInputs) {
+		if _, ok := declared[input]; !ok {
+			g.addIssue(graphIssueContract, graphSeverityError, edge.From,
+				fmt.Sprintf("%s passes input %q that %q does not declare in %s.inputs (recompile the caller after changing the callee)", edge.Kind, input, target.ID, trigger))
+		}
+	}
+	for _, input := range sortedLockKeys(mapKeySet(declared)) {
+		if declared[input].Required && !edge.passedInputs[input] {
+			g.addIssue(graphIssueContract, graphSeverityError, edge.From,
+				fmt.Sprintf("%s does not pass required input %q declared by %q", edge.Kind, input, target.ID))
+		}
+	}
+}
+
+// maxReportedCycles caps the orchestration cycles reported for a graph; a dense
+// dispatch graph has exponentially many elementary cycles
+const maxReportedCycles = 50
+
+// findCycles returns the elementary cycles between local workflows, each once and
+// rotated to start at its lexically smallest workflow. It uses Johnson's algorithm,
+// whose running time is bounded by the number of cycles found, and stops after
+// maxReportedCycles; truncated reports whether more cycles exist.
+func (g *OrchestrationGraph) findCycles() (cycles [][]string, truncated bool) {
+	var ids []string
+	for _, node := range g.Nodes {
+		if !node.External {
+			ids = append(ids, node.ID)
+		}
+	}
+	slices.Sort(ids)
+	index := make(map[string]int, len(ids))
+	for i, id := range ids {
+		index[id] = i
+	}
+	adjacency := make([][]int, len(ids))
+	reverse := make([][]int, len(ids))
+	for _, edge := range g.Edges {
+		from, okFrom := index[edge.From]
+		to, okTo := index[edge.To]
+		if okFrom && okTo && !slices.Contains(adjacency[from], to) {
+			adjacency[from] = append(adjacency[from], to)
+			reverse[to] = append(reverse[to], from)
+		}
+	}
+	for _, targets := range adjacency {
+		slices.Sort(targets)
+	}
+
+	for start := range ids {
+		// Cycles through start only use workflows of its strongly connected
+		// component among the workflows not before it
+		component := intersectReachable(reachableFrom(adjacency, start), reachableFrom(reverse, start))
+		if len(component) == 1 && !slices.Contains(adjacency[start], start) {
+			continue
+		}
+
+		blocked := make(map[int]bool)
+		blockedBy := make(map[int][]int)
+		var unblock func(v int)
+		unblock = func(v int) {
+			blocked[v] = false
+			waiting := blockedBy[v]
+			delete(blockedBy, v)
+			for _, w := range waiting {
+				if blocked[w] {
+					unblock(w)
+				}
+			}
+		}
+		var stack []int
+		var circuit func(v int) bool
+		circuit = func(v int) bool {
+			found := false
+			stack = append(stack, v)
+			blocked[v] = true
+			for _, w := range adjacency[v] {
+				if !component[w] || len(cycles) > maxReportedCycles {
+					continue
+				}
+				if w == start {
+					cycle := make([]string, len(stack))
+					for i, u := range stack {
+						cycle[i] = ids[u]
+					}
+					cycles = append(cycles, cycle)
+					found = true
+				} else if !blocked[w] && circuit(w) {
+					found = true
+				}
+			}
+			if found {
+				unblock(v)
+			} else {
+				for _, w := range adjacency[v] {
+					if component[w] && !slices.Contains(blockedBy[w], v) {
+						blockedBy[w] = append(blockedBy[w], v)
+					}
+				}
+			}
+			stack = stack[:len(stack)-1]
+			return found
+		}
+		circuit(start)
+		if len(cycles) > maxReportedCycles {
+			break
+		}
+
+		// Later searches only use workflows after start
+		for v := range adjacency {
+			adjacency[v] = slices.DeleteFunc(adjacency[v], func(w int) bool { return w == start })
+			reverse[v] = slices.DeleteFunc(reverse[v], func(w int) bool { return w == start })
+		}
+	}
+
+	if len(cycles) > maxReportedCycles {
+		cycles, truncated = cycles[:maxReportedCycles], true
+	}
+	sort.Slice(cycles, func(i, j int) bool { return strings.Join(cycles[i], " ") < strings.Join(cycles[j], " ") })
+	return cycles, truncated
+}
+
+// reachableFrom returns the nodes reachable from start, including start itself
+func reachableFrom(adjacency [][]int, start int) map[int]bool {
+	reached := map[int]bool{start: true}
+	queue := []int{start}
+	for len(queue) > 0 {
+		v := queue[0]
+		queue = queue[1:]
+		for _, w := range adjacency[v] {
+			if !reached[w] {
+				reached[w] = true
+				queue = append(queue, w)
+			}
+		}
+	}
+	return reached
+}
+
+// intersectReachable returns the nodes present in both sets
+func intersectReachable(a, b map[int]bool) map[int]bool {
+	both := make(map[int]bool)
+	for v := range a {
+		if b[v] {
+			both[v] = true
+		}
+	}
+	return both
+}
+
+func (g *OrchestrationGraph) addNode(node *OrchestrationNode) {
+	g.Nodes = append(g.Nodes, node)
+}
+
+func (g *OrchestrationGraph) node(id string) *OrchestrationNode {
+	for _, node := range g.Nodes {
+		if node.ID == id {
+			return node
+		}
+	}
+	return nil
+}
+
+func (g *OrchestrationGraph) addIssue(kind, severity, workflowID, message string) {
+	g.Issues = append(g.Issues, OrchestrationIssue{Kind: kind, Severity: severity, Workflow: workflowID, Message: message})
+}
+
+func (g *OrchestrationGraph) sortEdges() {
+	sort.SliceStable(g.Edges, func(i, j int) bool {
+		if g.Edges[i].From != g.Edges[j].From {
+			return g.Edges[i].From < g.Edges[j].From
+		}
+		return g.Edges[i].To < g.Edges[j].To
+	})
+}
+
+// hasErrors reports whether any issue has error severity
+func (g *OrchestrationGraph) hasErrors() bool {
+	return slices.ContainsFunc(g.Issues, func(issue OrchestrationIssue) bool { return issue.Severity == graphSeverityError })
+}
+
+// mapKeySet returns the keys of a map as a set
+func mapKeySet[V any](m map[string]V) map[string]bool {
+	set := make(map[string]bool, len(m))
+	for key := range m {
+		set[key] = true
+	}
+	return set
+}
+
+// renderOrchestrationMermaid renders the graph as a Mermaid flowchart
+func renderOrchestrationMermaid(g *OrchestrationGraph) string {
+	var sb strings.Builder
+	sb.WriteString("flowchart LR\n")
+	ids := make(map[string]string, len(g.Nodes))
+	for i, node := range g.Nodes {
+		ids[node.ID] = fmt.Sprintf("n%d", i)
+		label := strings.ReplaceAll(node.ID, `"`, "'")
+		if node.External {
+			fmt.Fprintf(&sb, "  %s[/\"%s\"/]:::external\n", ids[node.ID], label)
+		} else {
+			fmt.Fprintf(&sb, "  %s[\"%s\"]\n", ids[node.ID], label)
+		}
+	}
+	for _, edge := range g.Edges {
+		to, ok := ids[edge.To]
+		if !ok {
+			to = "missing_" + sanitizeMermaidID(edge.To)
+			fmt.Fprintf(&sb, "  %s[\"%s (missing)\"]:::missing\n", to, strings.ReplaceAll(edge.To, `"`, "'"))
+		}
+		arrow := "-->"
+		if edge.Kind == edgeWorkflowRun {
+			arrow = "-.->"
+		}
+		fmt.Fprintf(&sb, "  %s %s|%s| %s\n", ids[edge.From], arrow, edge.Kind, to)
+	}
+	sb.WriteString("  classDef external stroke-dasharray: 5 5\n")
+	sb.WriteString("  classDef missing stroke:#d73a49,color:#d73a49\n")
+	return sb.String()
+}
+
+var mermaidIDInvalidChars = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+func sanitizeMermaidID(id string) string {
+	return mermaidIDInvalidChars.ReplaceAllString(id, "_")
+}
+
+// renderOrchestrationDOT renders the graph in Graphviz DOT format
+func renderOrchestrationDOT(g *OrchestrationGraph) string {
+	var sb strings.Builder
+	sb.WriteString("digraph orchestration {\n  rankdir=LR;\n  node [shape=box];\n")
+	for _, node := range g.Nodes {
+		if node.External {
+			fmt.Fprintf(&sb, "  %q [style=dashed];\n", node.ID)
+		} else {
+			fmt.Fprintf(&sb, "  %q;\n", node.ID)
+		}
+	}
+	for _, edge := range g.Edges {
+		style := ""
+		if edge.Kind == edgeWorkflowRun {
+			style = ", style=dashed"
+		}
+		fmt.Fprintf(&sb, "  %q -> %q [label=%q%s];\n", edge.From, edge.To, edge.Kind, style)
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
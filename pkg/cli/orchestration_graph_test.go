@@ -0,0 +1,214 @@
+//go:build !integration
+
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/github/gh-aw/pkg/constants"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var orchestrationGraphFixture = map[string]string{
+	"orchestrator.lock.yml": `name: "Orchestrator"
+"on":
+  workflow_dispatch:
+jobs:
+  agent:
+    runs-on: ubuntu-latest
+    steps:
+      - name: Write Safe Outputs Config
+        run: |
+          cat > "${RUNNER_TEMP}/gh-aw/safeoutputs/config.json" << 'GH_AW_SAFE_OUTPUTS_CONFIG_abc_EOF'
+          {"dispatch_workflow":{"max":1,"workflows":["worker","ghost"]},"dispatch_repository":{"tools":{"deploy":{"workflow":"deploy","event_type":"deploy","repository":"octo/infra"}}}}
+          GH_AW_SAFE_OUTPUTS_CONFIG_abc_EOF
+      - name: Generate Safe Outputs Tools
+        env:
+          GH_AW_TOOLS_META_JSON: |
+            {
+              "dynamic_tools": [
+                {"_workflow_name": "worker", "inputSchema": {"properties": {"task": {"type": "string"}, "extra": {"type": "string"}}}}
+              ]
+            }
+        run: echo
+  call-helper:
+    needs: [agent]
+    if: needs.safe_outputs.outputs.call_workflow_name == 'helper'
+    uses: ./.github/workflows/helper.lock.yml
+    with:
+      payload: "{}"
+      topic: "x"
+`,
+	"worker.lock.yml": `name: "Worker"
+"on":
+  workflow_dispatch:
+    inputs:
+      task:
+        required: true
+        type: string
+      priority:
+        required: true
+        type: string
+  workflow_run:
+    workflows: ["Listener"]
+    types: [completed]
+jobs:
+  agent:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo
+`,
+	"helper.lock.yml": `name: "Helper"
+"on":
+  workflow_call:
+    inputs:
+      payload:
+        type: string
+jobs:
+  agent:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo
+`,
+	"listener.yml": `name: Listener
+on:
+  workflow_run:
+    workflows: [Worker, Nope]
+    types: [completed]
+jobs:
+  report:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo
+`,
+	"orphan.yml": `on: workflow_call
+jobs:
+  work:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo
+`,
+}
+
+func buildOrchestrationGraphFixture(t *testing.T) *OrchestrationGraph {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range orchestrationGraphFixture {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), constants.FilePermPublic), "fixture should be written")
+	}
+	graph, err := buildOrchestrationGraph(dir)
+	require.NoError(t, err, "graph should be built")
+	return graph
+}
+
+func TestBuildOrchestrationGraph_Edges(t *testing.T) {
+	graph := buildOrchestrationGraphFixture(t)
+
+	edges := make(map[string]string)
+	for _, edge := range graph.Edges {
+		edges[edge.From+" -> "+edge.To] = edge.Kind
+	}
+	assert.Equal(t, map[string]string{
+		"orchestrator -> worker":            edgeDispatchWorkflow,
+		"orchestrator -> ghost":             edgeDispatchWorkflow,
+		"orchestrator -> octo/infra/deploy": edgeDispatchRepository,
+		"orchestrator -> helper":            edgeCallWorkflow,
+		"worker -> listener":                edgeWorkflowRun,
+		"listener -> worker":                edgeWorkflowRun,
+	}, edges, "edges should come from safe outputs, uses: jobs and workflow_run triggers")
+
+	external := graph.node("octo/infra/deploy")
+	require.NotNil(t, external, "cross-repo target should be a node")
+	assert.True(t, external.External, "cross-repo target should be external")
+}
+
+func TestBuildOrchestrationGraph_Issues(t *testing.T) {
+	graph := buildOrchestrationGraphFixture(t)
+
+	var messages []string
+	for _, issue := range graph.Issues {
+		messages = append(messages, issue.Severity+" "+issue.Kind+" "+issue.Workflow+": "+issue.Message)
+	}
+	assert.ElementsMatch(t, []string{
+		`warning unresolved listener: workflow_run listens to "Nope" but no workflow in this repository has that name`,
+		`error contract orchestrator: call-workflow passes input "topic" that "helper" does not declare in workflow_call.inputs (recompile the caller after changing the callee)`,
+		`error unresolved orchestrator: dispatch-workflow target "ghost" does not exist in this repository (compile or add the workflow first)`,
+		`error contract orchestrator: dispatch-workflow passes input "extra" that "worker" does not declare in workflow_dispatch.inputs (recompile the caller after changing the callee)`,
+		`error contract orchestrator: dispatch-workflow does not pass required input "priority" declared by "worker"`,
+		`warning unreachable orphan: only triggered by workflow_call, but no workflow calls it`,
+		`warning cycle listener: orchestration cycle: listener → worker → listener (make sure every step is guarded against retriggering)`,
+	}, messages, "all graph problems should be reported")
+	assert.True(t, graph.hasErrors(), "contract mismatches should be errors")
+}
+
+func TestRenderOrchestrationGraph(t *testing.T) {
+	graph := buildOrchestrationGraphFixture(t)
+
+	mermaid := renderOrchestrationMermaid(graph)
+	assert.Contains(t, mermaid, "flowchart LR\n", "mermaid output should be a flowchart")
+	assert.Contains(t, mermaid, `[/"octo/infra/deploy"/]:::external`, "external nodes should be styled")
+	assert.Contains(t, mermaid, `missing_ghost["ghost (missing)"]:::missing`, "missing targets should be shown")
+	assert.Contains(t, mermaid, "-.->|workflow_run|", "workflow_run edges should be dotted")
+
+	dot := renderOrchestrationDOT(graph)
+	assert.Contains(t, dot, `"orchestrator" -> "helper" [label="call-workflow"];`, "DOT output should label edges")
+	assert.Contains(t, dot, `"octo/infra/deploy" [style=dashed];`, "external nodes should be dashed")
+}
+
+// orchestrationGraphWithEdges creates a graph of local workflows connected by dispatch edges
+func orchestrationGraphWithEdges(edges ...[2]string) *OrchestrationGraph {
+	graph := &OrchestrationGraph{}
+	for _, edge := range edges {
+		for _, id := range edge {
+			if graph.node(id) == nil {
+				graph.addNode(&OrchestrationNode{ID: id})
+			}
+		}
+		graph.Edges = append(graph.Edges, OrchestrationEdge{From: edge[0], To: edge[1], Kind: edgeDispatchWorkflow})
+	}
+	return graph
+}
+
+func TestFindCycles(t *testing.T) {
+	graph := orchestrationGraphWithEdges(
+		[2]string{"a", "b"}, [2]string{"b", "a"}, [2]string{"b", "c"}, [2]string{"c", "b"},
+		[2]string{"c", "a"}, [2]string{"a", "a"}, [2]string{"c", "d"},
+	)
+	cycles, truncated := graph.findCycles()
+	assert.False(t, truncated)
+	assert.Equal(t, [][]string{{"a"}, {"a", "b"}, {"a", "b", "c"}, {"b", "c"}}, cycles,
+		"every elementary cycle should be found once, starting at its smallest workflow")
+}
+
+func TestFindCycles_DenseGraph(t *testing.T) {
+	// A complete dispatch graph of 40 workflows has far too many cycles to list
+	var edges [][2]string
+	for i := range 40 {
+		for j := range 40 {
+			if i != j {
+				edges = append(edges, [2]string{fmt.Sprintf("w%02d", i), fmt.Sprintf("w%02d", j)})
+			}
+		}
+	}
+	graph := orchestrationGraphWithEdges(edges...)
+
+	done := make(chan struct{})
+	var cycles [][]string
+	var truncated bool
+	go func() {
+		cycles, truncated = graph.findCycles()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("finding cycles in a dense graph should stop after the reported ones")
+	}
+	assert.True(t, truncated, "the cycles should be truncated")
+	assert.Len(t, cycles, maxReportedCycles)
+}
@@ -73,7 +73,7 @@ rulesConfig:
 }
 
 // runPoutineOnDirectory runs the poutine security scanner on a directory containing workflows
-func runPoutineOnDirectory(workflowDir string, verbose bool, strict bool) error {
+func runPoutineOnDirectory(workflowDir string, verbose bool, strict bool, collector *SecurityFindingCollector) error {
 	poutineLog.Printf("Running poutine security scanner on directory: %s", workflowDir)
 
 	// Find git root to get the absolute path for Docker volume mount
@@ -128,7 +128,7 @@ func runPoutineOnDirectory(workflowDir string, verbose bool, strict bool) error
 	err = cmd.Run()
 
 	// Parse and display output for all files (no filtering)
-	totalWarnings, parseErr := parseAndDisplayPoutineOutputForDirectory(stdout.String(), verbose, gitRoot)
+	totalWarnings, parseErr := parseAndDisplayPoutineOutputForDirectory(stdout.String(), verbose, gitRoot, collector)
 	if parseErr != nil {
 		poutineLog.Printf("Failed to parse poutine output: %v", parseErr)
 		// Fall back to showing raw output
@@ -168,7 +168,7 @@ func runPoutineOnDirectory(workflowDir string, verbose bool, strict bool) error
 
 // runPoutineOnFile runs the poutine security scanner on a single .lock.yml file using Docker
 // This is a wrapper that filters the directory scan results to a single file for backward compatibility
-func runPoutineOnFile(lockFile string, verbose bool, strict bool) error {
+func runPoutineOnFile(lockFile string, verbose bool, strict bool, collector *SecurityFindingCollector) error {
 	poutineLog.Printf("Running poutine security scanner: file=%s, strict=%v", lockFile, strict)
 
 	// Find git root to get the absolute path for Docker volume mount
@@ -229,7 +229,7 @@ func runPoutineOnFile(lockFile string, verbose bool, strict bool) error {
 	err = cmd.Run()
 
 	// Parse and reformat the output, get total warning count
-	totalWarnings, parseErr := parseAndDisplayPoutineOutput(stdout.String(), relPath, verbose)
+	totalWarnings, parseErr := parseAndDisplayPoutineOutput(stdout.String(), relPath, verbose, collector)
 	if parseErr != nil {
 		poutineLog.Printf("Failed to parse poutine output: %v", parseErr)
 		// Fall back to showing raw output
@@ -271,7 +271,7 @@ func runPoutineOnFile(lockFile string, verbose bool, strict bool) error {
 
 // parseAndDisplayPoutineOutput parses poutine JSON output and displays it in the desired format
 // Returns the total number of warnings found for the specific file
-func parseAndDisplayPoutineOutput(stdout, targetFile string, verbose bool) (int, error) {
+func parseAndDisplayPoutineOutput(stdout, targetFile string, verbose bool, collector *SecurityFindingCollector) (int, error) {
 	// Parse JSON output from stdout
 	var output poutineOutput
 	if stdout == "" {
@@ -373,6 +373,15 @@ func parseAndDisplayPoutineOutput(stdout, targetFile string, verbose bool) (int,
 			Context: context,
 		}
 
+		collector.Add(SecurityFinding{
+			Tool:    "poutine",
+			RuleID:  finding.RuleID,
+			Level:   securitySeverityLevel(severity),
+			Message: strings.TrimSuffix(title+" - "+finding.Meta.Details, " - "),
+			File:    finding.Meta.Path,
+			Line:    lineNum,
+		})
+
 		fmt.Fprint(os.Stderr, console.FormatError(compilerErr))
 	}
 
@@ -381,7 +390,7 @@ func parseAndDisplayPoutineOutput(stdout, targetFile string, verbose bool) (int,
 
 // parseAndDisplayPoutineOutputForDirectory parses poutine JSON output and displays all findings
 // Returns the total number of warnings found across all files
-func parseAndDisplayPoutineOutputForDirectory(stdout string, verbose bool, gitRoot string) (int, error) {
+func parseAndDisplayPoutineOutputForDirectory(stdout string, verbose bool, gitRoot string, collector *SecurityFindingCollector) (int, error) {
 	// Parse JSON output from stdout
 	var output poutineOutput
 	if stdout == "" {
@@ -516,6 +525,15 @@ func parseAndDisplayPoutineOutputForDirectory(stdout string, verbose bool, gitRo
 				Context: context,
 			}
 
+			collector.Add(SecurityFinding{
+				Tool:    "poutine",
+				RuleID:  finding.RuleID,
+				Level:   securitySeverityLevel(severity),
+				Message: strings.TrimSuffix(title+" - "+finding.Meta.Details, " - "),
+				File:    finding.Meta.Path,
+				Line:    lineNum,
+			})
+
 			fmt.Fprint(os.Stderr, console.FormatError(compilerErr))
 		}
 	}
@@ -260,7 +260,7 @@ func TestParseAndDisplayPoutineOutput(t *testing.T) {
 			r, w, _ := os.Pipe()
 			os.Stderr = w
 
-			warningCount, err := parseAndDisplayPoutineOutput(tt.stdout, tt.targetFile, tt.verbose)
+			warningCount, err := parseAndDisplayPoutineOutput(tt.stdout, tt.targetFile, tt.verbose, nil)
 
 			// Restore stderr
 			w.Close()
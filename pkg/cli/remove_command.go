@@ -156,7 +156,7 @@ func RemoveWorkflows(pattern string, keepOrphans bool, workflowDir string) error
 
 	// Clean up orphaned include files (if orphan removal is enabled)
 	if len(removedFiles) > 0 && !keepOrphans {
-		if err := cleanupOrphanedIncludes(false); err != nil {
+		if err := cleanupOrphanedIncludes("", false); err != nil {
 			fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to clean up orphaned includes: %v", err)))
 		}
 	}
@@ -170,17 +170,22 @@ func RemoveWorkflows(pattern string, keepOrphans bool, workflowDir string) error
 }
 
 // cleanupOrphanedIncludes removes include files that are no longer used by any workflow
-func cleanupOrphanedIncludes(verbose bool) error {
+// in workflowDir (default: .github/workflows)
+func cleanupOrphanedIncludes(workflowDir string, verbose bool) error {
 	removeLog.Print("Cleaning up orphaned include files")
+	workflowsDir := workflowDir
+	if workflowsDir == "" {
+		workflowsDir = constants.GetWorkflowDir()
+	}
 	// Get all remaining markdown files
-	mdFiles, err := getMarkdownWorkflowFiles("")
+	mdFiles, err := getMarkdownWorkflowFiles(workflowsDir)
 	if err != nil {
 		// No markdown files means we can clean up all includes
 		removeLog.Print("No markdown files found, cleaning up all includes")
 		if verbose {
 			fmt.Fprintln(os.Stderr, console.FormatInfoMessage("No markdown files found, cleaning up all includes"))
 		}
-		return cleanupAllIncludes(verbose)
+		return cleanupAllIncludes(workflowsDir, verbose)
 	}
 
 	// Collect all include dependencies from remaining workflows
@@ -212,7 +217,6 @@ func cleanupOrphanedIncludes(verbose bool) error {
 	// Find all include files in the workflows directory
 	// Only consider files in subdirectories (like shared/) as potential include files
 	// Root-level .md files are workflow files, not include files
-	workflowsDir := constants.GetWorkflowDir()
 	var allIncludes []string
 
 	err = filepath.Walk(workflowsDir, func(path string, info os.FileInfo, err error) error {
@@ -356,8 +360,7 @@ func getAllIncludeFiles() ([]string, error) {
 }
 
 // cleanupAllIncludes removes all include files when no workflows remain
-func cleanupAllIncludes(verbose bool) error {
-	workflowsDir := constants.GetWorkflowDir()
+func cleanupAllIncludes(workflowsDir string, verbose bool) error {
 
 	err := filepath.Walk(workflowsDir, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
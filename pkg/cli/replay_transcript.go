@@ -0,0 +1,183 @@
+// This file contains transcript recording for the replay engine.
+// It turns the MCP gateway's rpc-messages.jsonl into the transcript format
+// replayed by actions/setup/js/replay_engine.cjs.
+
+package cli
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/constants"
+	"github.com/github/gh-aw/pkg/gitutil"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/workflow"
+)
+
+var replayTranscriptLog = logger.New("cli:replay_transcript")
+
+// replayTranscriptVersion is the transcript format version understood by replay_engine.cjs.
+const replayTranscriptVersion = 1
+
+// ReplayTranscript is a recorded sequence of MCP tool calls, including safe-output
+// emissions, that the replay engine replays against a workflow's compiled MCP configuration.
+type ReplayTranscript struct {
+	Version  int              `json:"version"`
+	Workflow string           `json:"workflow,omitempty"`
+	RunID    int64            `json:"run_id,omitempty"`
+	RunURL   string           `json:"run_url,omitempty"`
+	Engine   string           `json:"engine,omitempty"`
+	Calls    []ReplayToolCall `json:"calls"`
+}
+
+// ReplayToolCall is a single recorded tools/call request.
+type ReplayToolCall struct {
+	Server    string          `json:"server"`
+	Tool      string          `json:"tool"`
+	Arguments json.RawMessage `json:"arguments,omitempty"`
+}
+
+// rpcToolCallRequestParams holds the tools/call params recorded in a transcript.
+type rpcToolCallRequestParams struct {
+	Name      string          `json:"name"`
+	Arguments json.RawMessage `json:"arguments"`
+}
+
+// buildReplayTranscript builds a transcript from the outgoing tools/call requests in the
+// run's rpc-messages.jsonl, in the order they were sent to the MCP servers.
+func buildReplayTranscript(logDir string, run WorkflowRun) (*ReplayTranscript, error) {
+	rpcPath := findRPCMessagesPath(logDir)
+	if rpcPath == "" {
+		return nil, errors.New("no rpc-messages.jsonl found in the run artifacts; transcripts require the MCP gateway")
+	}
+	replayTranscriptLog.Printf("Building replay transcript from %s", rpcPath)
+
+	file, err := os.Open(rpcPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open rpc-messages.jsonl: %w", err)
+	}
+	defer file.Close()
+
+	transcript := &ReplayTranscript{
+		Version:  replayTranscriptVersion,
+		Workflow: workflowIDFromPath(run.WorkflowPath),
+		RunID:    run.DatabaseID,
+		RunURL:   run.URL,
+		Calls:    []ReplayToolCall{},
+	}
+	if info, err := parseAwInfo(filepath.Join(logDir, "aw_info.json"), false); err == nil {
+		transcript.Engine = info.EngineID
+	}
+
+	scanner := bufio.NewScanner(file)
+	buf := make([]byte, maxScannerBufferSize)
+	scanner.Buffer(buf, maxScannerBufferSize)
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var entry RPCMessageEntry
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			continue
+		}
+		if entry.Direction != "OUT" || entry.Type != "REQUEST" || entry.ServerID == "" {
+			continue
+		}
+		var req rpcRequestPayload
+		if err := json.Unmarshal(entry.Payload, &req); err != nil || req.Method != "tools/call" {
+			continue
+		}
+		var params rpcToolCallRequestParams
+		if err := json.Unmarshal(req.Params, &params); err != nil || params.Name == "" {
+			continue
+		}
+		transcript.Calls = append(transcript.Calls, ReplayToolCall{
+			Server:    entry.ServerID,
+			Tool:      params.Name,
+			Arguments: params.Arguments,
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading rpc-messages.jsonl: %w", err)
+	}
+
+	replayTranscriptLog.Printf("Recorded %d tool calls for run %d", len(transcript.Calls), run.DatabaseID)
+	return transcript, nil
+}
+
+// recordRunTranscript writes the run's transcript to <workflow-id>.json in
+// transcriptsDir, where the replay engine reads it, and returns its path.
+func recordRunTranscript(logDir string, run WorkflowRun, transcriptsDir string) (string, error) {
+	transcript, err := buildReplayTranscript(logDir, run)
+	if err != nil {
+		return "", err
+	}
+	if transcript.Workflow == "" {
+		return "", fmt.Errorf("cannot determine the workflow ID of run %d from %q", run.DatabaseID, run.WorkflowPath)
+	}
+	data, err := json.MarshalIndent(transcript, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal transcript: %w", err)
+	}
+	if err := os.MkdirAll(transcriptsDir, constants.DirPermPublic); err != nil {
+		return "", fmt.Errorf("failed to create transcripts directory: %w", err)
+	}
+	transcriptPath := filepath.Join(transcriptsDir, transcript.Workflow+".json")
+	if err := os.WriteFile(transcriptPath, append(data, '\n'), constants.FilePermPublic); err != nil {
+		return "", fmt.Errorf("failed to write transcript: %w", err)
+	}
+	return transcriptPath, nil
+}
+
+// workflowIDFromPath returns the workflow ID for a lock file path such as
+// .github/workflows/triage.lock.yml.
+func workflowIDFromPath(workflowPath string) string {
+	base := filepath.Base(workflowPath)
+	if base == "." || base == "/" {
+		return ""
+	}
+	base = strings.TrimSuffix(base, ".lock.yml")
+	return strings.TrimSuffix(base, filepath.Ext(base))
+}
+
+// transcriptRecorder records the transcript of the most recent downloaded run of
+// each workflow into the repository's transcripts directory.
+type transcriptRecorder struct {
+	dir      string
+	recorded map[string]bool
+}
+
+// newTranscriptRecorder returns a recorder writing to .github/aw/transcripts at the
+// root of the current repository, or relative to the working directory outside one.
+func newTranscriptRecorder() *transcriptRecorder {
+	dir := workflow.ReplayTranscriptDir
+	if gitRoot, err := gitutil.FindGitRoot(); err == nil {
+		dir = filepath.Join(gitRoot, dir)
+	}
+	return &transcriptRecorder{dir: dir, recorded: make(map[string]bool)}
+}
+
+// record records the transcript for a downloaded run and reports the outcome. Only the
+// first run of each workflow is recorded; runs are downloaded newest first.
+func (r *transcriptRecorder) record(logDir string, run WorkflowRun) {
+	workflowID := workflowIDFromPath(run.WorkflowPath)
+	if r.recorded[workflowID] {
+		replayTranscriptLog.Printf("Skipping run %d: a newer transcript of %s was recorded", run.DatabaseID, workflowID)
+		return
+	}
+	transcriptPath, err := recordRunTranscript(logDir, run, r.dir)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to record transcript for run %d: %v", run.DatabaseID, err)))
+		return
+	}
+	r.recorded[workflowID] = true
+	fmt.Fprintln(os.Stderr, console.FormatSuccessMessage(fmt.Sprintf("✓ Recorded transcript for run %d → %s", run.DatabaseID, transcriptPath)))
+}
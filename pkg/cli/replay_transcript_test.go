@@ -0,0 +1,87 @@
+//go:build !integration
+
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/constants"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildReplayTranscript(t *testing.T) {
+	logDir := t.TempDir()
+	mcpLogsDir := filepath.Join(logDir, "mcp-logs")
+	require.NoError(t, os.MkdirAll(mcpLogsDir, 0755))
+
+	rpcContent := `{"timestamp":"2024-01-12T10:00:00.000000000Z","direction":"OUT","type":"REQUEST","server_id":"github","payload":{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}}
+{"timestamp":"2024-01-12T10:00:01.000000000Z","direction":"OUT","type":"REQUEST","server_id":"github","payload":{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"issue_read","arguments":{"issue_number":42}}}}
+{"timestamp":"2024-01-12T10:00:02.000000000Z","direction":"IN","type":"RESPONSE","server_id":"github","payload":{"jsonrpc":"2.0","id":2,"result":{"content":[]}}}
+not json
+{"timestamp":"2024-01-12T10:00:03.000000000Z","direction":"OUT","type":"REQUEST","server_id":"safeoutputs","payload":{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"add_comment","arguments":{"body":"Triaged"}}}}
+`
+	require.NoError(t, os.WriteFile(filepath.Join(mcpLogsDir, "rpc-messages.jsonl"), []byte(rpcContent), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(logDir, "aw_info.json"), []byte(`{"engine_id":"copilot"}`), 0644))
+
+	run := WorkflowRun{DatabaseID: 1234, URL: "https://github.com/owner/repo/actions/runs/1234", WorkflowPath: ".github/workflows/triage.lock.yml"}
+	transcriptsDir := filepath.Join(t.TempDir(), ".github", "aw", "transcripts")
+	path, err := recordRunTranscript(logDir, run, transcriptsDir)
+	require.NoError(t, err, "transcript should be recorded from rpc-messages.jsonl")
+	assert.Equal(t, filepath.Join(transcriptsDir, "triage.json"), path, "transcript should be written where the replay engine reads it")
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var transcript ReplayTranscript
+	require.NoError(t, json.Unmarshal(data, &transcript))
+
+	assert.Equal(t, 1, transcript.Version)
+	assert.Equal(t, "triage", transcript.Workflow, "workflow ID should come from the lock file path")
+	assert.Equal(t, "copilot", transcript.Engine, "engine should come from aw_info.json")
+	assert.Equal(t, int64(1234), transcript.RunID)
+	require.Len(t, transcript.Calls, 2, "only outgoing tools/call requests should be recorded")
+	assert.Equal(t, "github", transcript.Calls[0].Server)
+	assert.Equal(t, "issue_read", transcript.Calls[0].Tool)
+	assert.JSONEq(t, `{"issue_number":42}`, string(transcript.Calls[0].Arguments))
+	assert.Equal(t, "safeoutputs", transcript.Calls[1].Server, "safe-output emissions should be recorded in order")
+	assert.Equal(t, "add_comment", transcript.Calls[1].Tool)
+}
+
+func TestTranscriptRecorder_RecordsNewestRunPerWorkflow(t *testing.T) {
+	writeRun := func(tool string) string {
+		logDir := t.TempDir()
+		rpc := `{"direction":"OUT","type":"REQUEST","server_id":"github","payload":{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"` + tool + `"}}}` + "\n"
+		require.NoError(t, os.WriteFile(filepath.Join(logDir, "rpc-messages.jsonl"), []byte(rpc), constants.FilePermPublic))
+		return logDir
+	}
+	recorder := &transcriptRecorder{dir: t.TempDir(), recorded: make(map[string]bool)}
+	run := WorkflowRun{WorkflowPath: ".github/workflows/triage.lock.yml"}
+
+	run.DatabaseID = 2
+	recorder.record(writeRun("issue_read"), run)
+	run.DatabaseID = 1
+	recorder.record(writeRun("list_issues"), run)
+
+	data, err := os.ReadFile(filepath.Join(recorder.dir, "triage.json"))
+	require.NoError(t, err)
+	var transcript ReplayTranscript
+	require.NoError(t, json.Unmarshal(data, &transcript))
+	assert.Equal(t, int64(2), transcript.RunID, "the first (newest) run should be kept")
+	require.Len(t, transcript.Calls, 1)
+	assert.Equal(t, "issue_read", transcript.Calls[0].Tool)
+}
+
+func TestBuildReplayTranscript_NoGatewayLogs(t *testing.T) {
+	_, err := buildReplayTranscript(t.TempDir(), WorkflowRun{DatabaseID: 1})
+	require.Error(t, err, "runs without rpc-messages.jsonl cannot be recorded")
+	assert.Contains(t, err.Error(), "rpc-messages.jsonl")
+}
+
+func TestWorkflowIDFromPath(t *testing.T) {
+	assert.Equal(t, "triage", workflowIDFromPath(".github/workflows/triage.lock.yml"))
+	assert.Equal(t, "ci", workflowIDFromPath(".github/workflows/ci.yml"))
+	assert.Empty(t, workflowIDFromPath(""))
+}
@@ -0,0 +1,371 @@
+// This file implements live streaming of in-progress workflow runs for
+// `gh aw run --follow` and `gh aw logs --follow <run-id>`.
+//
+// The run's jobs and the agent job's log are polled through the Actions API.
+// New log lines are fed to the engine's line by line agent event parser (which
+// emits the same events as the ParseAgentEvents used by `gh aw logs --events`),
+// and the new events are rendered: agent turns, tool calls and errors. When the
+// run completes the audit summary is printed. Ctrl-C cancels the run.
+//
+// The Actions API serves a job's log as a single file, usually only once the job
+// has finished; there is no API for the live log shown in the GitHub UI. Agent
+// events therefore appear when GitHub makes the log available rather than as the
+// agent writes them. Until then each poll downloads the whole log again, since
+// the endpoint serves it as one file: a log of a few megabytes costs a download of
+// that size every poll interval. Only its new lines are parsed, and a completed
+// job's log is not downloaded again.
+
+package cli
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/constants"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/stringutil"
+	"github.com/github/gh-aw/pkg/workflow"
+)
+
+var runFollowLog = logger.New("cli:run_follow")
+
+// runFollowPollInterval is how often the run status and agent job log are fetched
+const runFollowPollInterval = 5 * time.Second
+
+// followEventTextLength caps the message text shown per event
+const followEventTextLength = 200
+
+// FollowOptions configures live streaming of a workflow run
+type FollowOptions struct {
+	RunID     int64
+	Repo      string // owner/repo; empty for the current repository
+	Engine    string // Engine ID used to parse the agent log; detected from the lock file when empty
+	OutputDir string // Directory the final audit downloads artifacts to
+	Verbose   bool
+}
+
+// followRunStatus is the subset of the workflow run API response used while following
+type followRunStatus struct {
+	Status     string `json:"status"`
+	Conclusion string `json:"conclusion"`
+	Path       string `json:"path"`
+	HTMLURL    string `json:"html_url"`
+}
+
+// followJob is the subset of a workflow job API response used while following
+type followJob struct {
+	ID         int64        `json:"id"`
+	Name       string       `json:"name"`
+	Status     string       `json:"status"`
+	Conclusion string       `json:"conclusion"`
+	Steps      []followStep `json:"steps"`
+}
+
+// followStep is the subset of a workflow job step used while following
+type followStep struct {
+	Number     int    `json:"number"`
+	Name       string `json:"name"`
+	Status     string `json:"status"`
+	Conclusion string `json:"conclusion"`
+}
+
+// jobLogTimestampPattern matches the timestamp GitHub Actions prefixes to every job log line
+var jobLogTimestampPattern = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(\.\d+)?Z `)
+
+// agentLogFollower parses a growing agent job log incrementally. The job log is
+// fetched in full on every poll; only complete lines past the consumed offset
+// are fed to the engine's agent event parser.
+type agentLogFollower struct {
+	engine     workflow.LogParser
+	parser     workflow.AgentEventParser
+	jobID      int64    // agent job whose log is being parsed
+	consumed   int      // bytes of the job log fed to the parser
+	prefixHash [32]byte // hash of the consumed bytes, to detect a replaced log
+	shown      int      // events returned so far
+	skip       int      // events of a replaced log that were already returned
+}
+
+// newAgentLogFollower creates a follower using the log parser of the given engine,
+// falling back to the default engine for unknown IDs
+func newAgentLogFollower(engineID string) *agentLogFollower {
+	registry := workflow.GetGlobalEngineRegistry()
+	engine, err := registry.GetEngine(engineID)
+	if err != nil {
+		runFollowLog.Printf("Unknown engine %q, using %s log parser: %v", engineID, constants.DefaultEngine, err)
+		engine, _ = registry.GetEngine(string(constants.DefaultEngine))
+	}
+	follower := &agentLogFollower{engine: engine}
+	follower.reset()
+	return follower
+}
+
+// Feed parses the new complete lines of the log of job jobID and returns the
+// events they produced.
+func (f *agentLogFollower) Feed(jobID int64, jobLog string) []workflow.AgentEvent {
+	switch {
+	case jobID != f.jobID:
+		// A re-run attempt has a new agent job; all of its events are new
+		runFollowLog.Printf("Following the log of agent job %d (previous: %d)", jobID, f.jobID)
+		f.jobID = jobID
+		f.reset()
+		f.shown = 0
+		f.skip = 0
+	case len(jobLog) < f.consumed || sha256.Sum256([]byte(jobLog[:f.consumed])) != f.prefixHash:
+		// The log was truncated or replaced; parse it again from the start
+		// without repeating the events already shown
+		runFollowLog.Printf("Agent job log no longer starts with the %d bytes already parsed, parsing it again", f.consumed)
+		f.reset()
+		f.skip = f.shown
+	}
+	end := strings.LastIndexByte(jobLog[f.consumed:], '\n')
+	if end < 0 {
+		return nil
+	}
+	for line := range strings.SplitSeq(jobLog[f.consumed:f.consumed+end], "\n") {
+		f.parser.ParseLine(jobLogTimestampPattern.ReplaceAllString(strings.TrimSuffix(line, "\r"), ""))
+	}
+	f.consumed += end + 1
+	f.prefixHash = sha256.Sum256([]byte(jobLog[:f.consumed]))
+
+	events := f.parser.Pending()
+	skipped := min(f.skip, len(events))
+	f.skip -= skipped
+	events = events[skipped:]
+	f.shown += len(events)
+	return events
+}
+
+// reset restarts parsing from the start of the log
+func (f *agentLogFollower) reset() {
+	f.parser = f.engine.NewAgentEventParser()
+	f.consumed = 0
+	f.prefixHash = sha256.Sum256(nil)
+}
+
+// formatFollowEvent renders one agent event as a console line, or "" for events
+// that are not shown while following
+func formatFollowEvent(evt workflow.AgentEvent) string {
+	text := stringutil.Truncate(strings.Join(strings.Fields(evt.Text), " "), followEventTextLength)
+	switch evt.Type {
+	case workflow.AgentEventTurnStart:
+		return console.FormatSectionHeader(fmt.Sprintf("Turn %d", evt.Turn))
+	case workflow.AgentEventModelSwitch:
+		return console.FormatInfoMessage("Model: " + evt.Model)
+	case workflow.AgentEventAssistantMessage:
+		if text == "" {
+			return ""
+		}
+		return "💬 " + text
+	case workflow.AgentEventToolCall:
+		return "🔧 " + evt.Tool
+	case workflow.AgentEventToolResult:
+		if !evt.IsError {
+			return ""
+		}
+		return console.FormatWarningMessage(fmt.Sprintf("%s failed: %s", orDash(evt.Tool), text))
+	case workflow.AgentEventError:
+		return console.FormatErrorMessage(text)
+	default:
+		return ""
+	}
+}
+
+// runFollower tracks what has been shown for a followed run
+type runFollower struct {
+	opts       FollowOptions
+	out        io.Writer
+	jobStatus  map[int64]string
+	stepStatus map[string]string
+	agentLog   *agentLogFollower
+	agentDone  int64 // ID of the completed agent job whose log was read
+	lastRun    followRunStatus
+}
+
+// FollowWorkflowRun streams a workflow run until it completes, then prints its
+// audit summary. Interrupting the stream (Ctrl-C) cancels the run.
+func FollowWorkflowRun(ctx context.Context, opts FollowOptions) error {
+	runFollowLog.Printf("Following run: id=%d, repo=%s, engine=%s", opts.RunID, opts.Repo, opts.Engine)
+	follower := &runFollower{
+		opts:       opts,
+		out:        os.Stderr,
+		jobStatus:  make(map[int64]string),
+		stepStatus: make(map[string]string),
+	}
+
+	fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("Following run %d (press Ctrl-C to cancel the run)...", opts.RunID)))
+	err := PollWithSignalHandling(PollOptions{
+		Ctx:          ctx,
+		PollInterval: runFollowPollInterval,
+		Timeout:      time.Duration(workflowCompletionWaitTimeoutMinutes) * time.Minute,
+		PollFunc:     follower.poll,
+		Verbose:      opts.Verbose,
+	})
+	if errors.Is(err, ErrInterrupted) {
+		return cancelFollowedRun(opts)
+	}
+	if err != nil {
+		return err
+	}
+
+	conclusion := follower.lastRun.Conclusion
+	if conclusion == "success" {
+		fmt.Fprintln(os.Stderr, console.FormatSuccessMessage(fmt.Sprintf("Run %d completed successfully", opts.RunID)))
+	} else {
+		fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Run %d completed with conclusion: %s", opts.RunID, orDash(conclusion))))
+	}
+
+	owner, repo, _ := strings.Cut(opts.Repo, "/")
+	return AuditWorkflowRun(ctx, opts.RunID, AuditOptions{
+		Owner:     owner,
+		Repo:      repo,
+		OutputDir: opts.OutputDir,
+		Verbose:   opts.Verbose,
+	})
+}
+
+// poll fetches the run, renders job and step transitions and new agent log
+// events, and reports success once the run has completed
+func (f *runFollower) poll(ctx context.Context) (PollResult, error) {
+	var run followRunStatus
+	if err := f.getJSON(ctx, fmt.Sprintf("actions/runs/%d", f.opts.RunID), &run); err != nil {
+		return PollFailure, fmt.Errorf("failed to get run %d: %w", f.opts.RunID, err)
+	}
+	f.lastRun = run
+	if f.agentLog == nil {
+		f.agentLog = newAgentLogFollower(f.engineID(run.Path))
+	}
+
+	var jobs struct {
+		Jobs []followJob `json:"jobs"`
+	}
+	if err := f.getJSON(ctx, fmt.Sprintf("actions/runs/%d/jobs", f.opts.RunID), &jobs); err != nil {
+		runFollowLog.Printf("Failed to get jobs of run %d: %v", f.opts.RunID, err)
+	}
+	for _, job := range jobs.Jobs {
+		f.renderJob(job)
+		if job.Name == string(constants.AgentJobName) && job.Status != "queued" && job.ID != f.agentDone {
+			f.streamAgentLog(ctx, job)
+		}
+	}
+
+	if run.Status == "completed" {
+		return PollSuccess, nil
+	}
+	return PollContinue, nil
+}
+
+// engineID returns the engine used to parse the agent log: the configured one,
+// or the agent ID recorded in the local lock file of the run's workflow
+func (f *runFollower) engineID(workflowPath string) string {
+	if f.opts.Engine != "" {
+		return f.opts.Engine
+	}
+	if workflowPath != "" {
+		lockFile := filepath.Join(constants.GetWorkflowDir(), filepath.Base(workflowPath))
+		if content, err := os.ReadFile(lockFile); err == nil {
+			if metadata, _, err := workflow.ExtractMetadataFromLockFile(string(content)); err == nil && metadata != nil && metadata.AgentID != "" {
+				runFollowLog.Printf("Detected engine %s from %s", metadata.AgentID, lockFile)
+				return metadata.AgentID
+			}
+		}
+	}
+	console.LogVerbose(f.opts.Verbose, fmt.Sprintf("Could not detect the engine of run %d, parsing the agent log as %s (use --engine to override)", f.opts.RunID, constants.DefaultEngine))
+	return string(constants.DefaultEngine)
+}
+
+// renderJob prints job start and completion, and step progress in verbose mode
+func (f *runFollower) renderJob(job followJob) {
+	if previous := f.jobStatus[job.ID]; previous != job.Status {
+		f.jobStatus[job.ID] = job.Status
+		switch job.Status {
+		case "in_progress":
+			fmt.Fprintln(f.out, console.FormatProgressMessage(fmt.Sprintf("Job %s started", job.Name)))
+		case "completed":
+			if job.Conclusion == "success" || job.Conclusion == "skipped" {
+				fmt.Fprintln(f.out, console.FormatSuccessMessage(fmt.Sprintf("Job %s %s", job.Name, job.Conclusion)))
+			} else {
+				fmt.Fprintln(f.out, console.FormatErrorMessage(fmt.Sprintf("Job %s %s", job.Name, orDash(job.Conclusion))))
+			}
+		}
+	}
+	if !f.opts.Verbose {
+		return
+	}
+	for _, step := range job.Steps {
+		key := fmt.Sprintf("%d/%d", job.ID, step.Number)
+		if f.stepStatus[key] == step.Status {
+			continue
+		}
+		f.stepStatus[key] = step.Status
+		if step.Status == "in_progress" {
+			fmt.Fprintln(f.out, console.FormatVerboseMessage(fmt.Sprintf("%s: %s", job.Name, step.Name)))
+		}
+	}
+}
+
+// streamAgentLog fetches the agent job log and prints the events of its new lines.
+// The Actions API may not serve a job's log until its steps finish; errors are
+// only logged so that following continues. Each call downloads the whole log, so
+// once a completed job's log was read it is not fetched again.
+func (f *runFollower) streamAgentLog(ctx context.Context, job followJob) {
+	output, err := f.api(ctx, fmt.Sprintf("actions/jobs/%d/logs", job.ID))
+	if err != nil {
+		runFollowLog.Printf("Agent job log not available yet: %v", err)
+		return
+	}
+	for _, evt := range f.agentLog.Feed(job.ID, string(output)) {
+		if line := formatFollowEvent(evt); line != "" {
+			fmt.Fprintln(f.out, line)
+		}
+	}
+	if job.Status == "completed" {
+		f.agentDone = job.ID
+	}
+}
+
+// api calls a repository-scoped Actions API endpoint without a spinner, which
+// would interleave with the streamed output
+func (f *runFollower) api(ctx context.Context, endpoint string) ([]byte, error) {
+	repo := f.opts.Repo
+	if repo == "" {
+		repo = "{owner}/{repo}"
+	}
+	return workflow.ExecGHContext(ctx, "api", fmt.Sprintf("repos/%s/%s", repo, endpoint)).Output()
+}
+
+// getJSON calls an Actions API endpoint and decodes the JSON response into v
+func (f *runFollower) getJSON(ctx context.Context, endpoint string, v any) error {
+	output, err := f.api(ctx, endpoint)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(output, v)
+}
+
+// cancelFollowedRun cancels the followed run after the stream was interrupted.
+// It uses a fresh context because the command context is already cancelled.
+func cancelFollowedRun(opts FollowOptions) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	args := []string{"run", "cancel", strconv.FormatInt(opts.RunID, 10)}
+	if opts.Repo != "" {
+		args = append(args, "--repo", opts.Repo)
+	}
+	if output, err := workflow.ExecGHContext(ctx, args...).CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to cancel run %d: %s", opts.RunID, strings.TrimSpace(string(output)))
+	}
+	fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Cancelled run %d", opts.RunID)))
+	return ErrInterrupted
+}
@@ -0,0 +1,174 @@
+//go:build !integration
+
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// jobLogLines prefixes each line with a GitHub Actions job log timestamp
+func jobLogLines(lines ...string) string {
+	var sb strings.Builder
+	for _, line := range lines {
+		sb.WriteString("2025-06-01T10:00:00.1234567Z " + line + "\n")
+	}
+	return sb.String()
+}
+
+func followEventTypes(events []workflow.AgentEvent) []workflow.AgentEventType {
+	var types []workflow.AgentEventType
+	for _, evt := range events {
+		types = append(types, evt.Type)
+	}
+	return types
+}
+
+func TestAgentLogFollower_Feed(t *testing.T) {
+	follower := newAgentLogFollower("claude")
+
+	jobLog := jobLogLines(
+		"##[group]Run claude --print",
+		`{"type": "system", "subtype": "init", "model": "claude-sonnet-4"}`,
+		`{"type": "assistant", "message": {"id": "msg_1", "content": [{"type": "text", "text": "Listing files"}, {"type": "tool_use", "id": "toolu_1", "name": "Bash", "input": {"command": "ls"}}]}}`,
+	)
+	events := follower.Feed(1, jobLog)
+	assert.Equal(t, []workflow.AgentEventType{
+		workflow.AgentEventModelSwitch, workflow.AgentEventTurnStart, workflow.AgentEventAssistantMessage, workflow.AgentEventToolCall,
+	}, followEventTypes(events), "the trailing turn_end should be held back")
+
+	// A partial line is not parsed until it is complete
+	partial := `{"type": "user", "message": {"content": [{"type": "tool_result", "tool_use_id": "toolu_1", "content": "boom", "is_error": true}]}}`
+	jobLog += "2025-06-01T10:00:01.0000000Z " + partial[:20]
+	assert.Empty(t, follower.Feed(1, jobLog), "incomplete lines should not be parsed")
+
+	jobLog += partial[20:] + "\n"
+	events = follower.Feed(1, jobLog)
+	require.Equal(t, []workflow.AgentEventType{workflow.AgentEventToolResult}, followEventTypes(events), "only new events should be returned")
+	assert.True(t, events[0].IsError)
+	assert.Equal(t, "bash", events[0].Tool)
+
+	assert.Empty(t, follower.Feed(1, jobLog), "an unchanged log should produce no events")
+}
+
+func TestAgentLogFollower_FeedReset(t *testing.T) {
+	follower := newAgentLogFollower("claude")
+	system := `{"type": "system", "subtype": "init", "model": "claude-sonnet-4"}`
+	message := `{"type": "assistant", "message": {"id": "msg_1", "content": [{"type": "text", "text": "Listing files"}]}}`
+
+	events := follower.Feed(1, jobLogLines("##[group]Run claude --print with a long command line", system, message))
+	require.Len(t, events, 3, "model switch, turn start and message")
+
+	// A re-run replaces the log with a shorter one that repeats the same events
+	assert.Empty(t, follower.Feed(1, jobLogLines(system, message)), "events already shown should not be printed again")
+
+	retry := `{"type": "assistant", "message": {"id": "msg_2", "content": [{"type": "text", "text": "Retrying"}]}}`
+	events = follower.Feed(1, jobLogLines(system, message, retry))
+	require.Equal(t, []workflow.AgentEventType{workflow.AgentEventTurnEnd, workflow.AgentEventTurnStart, workflow.AgentEventAssistantMessage},
+		followEventTypes(events), "only events past the ones already shown should be returned")
+	assert.Equal(t, "Retrying", events[2].Text)
+}
+
+func TestAgentLogFollower_FeedReplacedLongerLog(t *testing.T) {
+	follower := newAgentLogFollower("claude")
+	system := `{"type": "system", "subtype": "init", "model": "claude-sonnet-4"}`
+	first := `{"type": "assistant", "message": {"id": "msg_1", "content": [{"type": "text", "text": "Listing files"}]}}`
+	second := `{"type": "assistant", "message": {"id": "msg_2", "content": [{"type": "text", "text": "Retrying"}]}}`
+
+	require.Len(t, follower.Feed(1, jobLogLines(system, first)), 3, "model switch, turn start and message")
+
+	// The replacement log is already longer than the bytes parsed so far
+	events := follower.Feed(1, jobLogLines("##[group]Run claude --print", system, first, second))
+	require.Equal(t, []workflow.AgentEventType{workflow.AgentEventTurnEnd, workflow.AgentEventTurnStart, workflow.AgentEventAssistantMessage},
+		followEventTypes(events), "a replaced log should be parsed from the start, not from the old offset")
+	assert.Equal(t, "Retrying", events[2].Text)
+}
+
+func TestAgentLogFollower_FeedNewJob(t *testing.T) {
+	follower := newAgentLogFollower("claude")
+	system := `{"type": "system", "subtype": "init", "model": "claude-sonnet-4"}`
+	message := `{"type": "assistant", "message": {"id": "msg_1", "content": [{"type": "text", "text": "Listing files"}]}}`
+	jobLog := jobLogLines(system, message)
+
+	require.Len(t, follower.Feed(1, jobLog), 3, "model switch, turn start and message")
+	assert.Len(t, follower.Feed(2, jobLog), 3, "the log of a re-run attempt's job should be shown from the start")
+}
+
+func TestAgentLogFollower_UnknownEngine(t *testing.T) {
+	follower := newAgentLogFollower("unknown-engine")
+	require.NotNil(t, follower.parser, "unknown engines should fall back to the default engine's parser")
+}
+
+func TestFormatFollowEvent(t *testing.T) {
+	tests := []struct {
+		name     string
+		event    workflow.AgentEvent
+		contains string
+	}{
+		{name: "turn start", event: workflow.AgentEvent{Type: workflow.AgentEventTurnStart, Turn: 2}, contains: "Turn 2"},
+		{name: "assistant message", event: workflow.AgentEvent{Type: workflow.AgentEventAssistantMessage, Text: "Looking\n  at the code"}, contains: "💬 Looking at the code"},
+		{name: "tool call", event: workflow.AgentEvent{Type: workflow.AgentEventToolCall, Tool: "github::get_issue"}, contains: "🔧 github::get_issue"},
+		{name: "failed tool result", event: workflow.AgentEvent{Type: workflow.AgentEventToolResult, Tool: "bash", Text: "exit 1", IsError: true}, contains: "bash failed: exit 1"},
+		{name: "error", event: workflow.AgentEvent{Type: workflow.AgentEventError, Text: "rate limited"}, contains: "rate limited"},
+		{name: "successful tool result is hidden", event: workflow.AgentEvent{Type: workflow.AgentEventToolResult, Tool: "bash"}},
+		{name: "turn end is hidden", event: workflow.AgentEvent{Type: workflow.AgentEventTurnEnd}},
+		{name: "token usage is hidden", event: workflow.AgentEvent{Type: workflow.AgentEventTokenUsage}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			line := formatFollowEvent(tt.event)
+			if tt.contains == "" {
+				assert.Empty(t, line)
+				return
+			}
+			assert.Contains(t, line, tt.contains)
+		})
+	}
+}
+
+func TestRunFollowerRenderJob(t *testing.T) {
+	var out bytes.Buffer
+	follower := &runFollower{out: &out, jobStatus: make(map[int64]string), stepStatus: make(map[string]string)}
+
+	follower.renderJob(followJob{ID: 1, Name: "agent", Status: "in_progress"})
+	follower.renderJob(followJob{ID: 1, Name: "agent", Status: "in_progress"})
+	follower.renderJob(followJob{ID: 1, Name: "agent", Status: "completed", Conclusion: "failure"})
+
+	assert.Equal(t, 1, strings.Count(out.String(), "Job agent started"), "transitions should be printed once")
+	assert.Contains(t, out.String(), "Job agent failure")
+}
+
+func TestRunFollowerEngineID(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, ".github", "workflows"), 0755))
+	lock := "# gh-aw-metadata: {\"schema_version\":\"v3\",\"agent_id\":\"codex\"}\nname: triage\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".github", "workflows", "triage.lock.yml"), []byte(lock), 0644))
+
+	follower := &runFollower{}
+	assert.Equal(t, "codex", follower.engineID(".github/workflows/triage.lock.yml"), "engine should be read from the lock file")
+	assert.Equal(t, "copilot", follower.engineID(".github/workflows/missing.lock.yml"), "default engine when the lock file is missing")
+
+	follower.opts.Engine = "claude"
+	assert.Equal(t, "claude", follower.engineID(".github/workflows/triage.lock.yml"), "--engine should take precedence")
+}
+
+func TestValidateFollowOptions(t *testing.T) {
+	require.NoError(t, validateFollowOptions(3, RunOptions{}), "without --follow any combination is valid")
+	require.NoError(t, validateFollowOptions(1, RunOptions{Follow: true}))
+
+	err := validateFollowOptions(2, RunOptions{Follow: true})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "single workflow")
+
+	err = validateFollowOptions(1, RunOptions{Follow: true, RepeatCount: 2})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "--repeat")
+}
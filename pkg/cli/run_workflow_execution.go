@@ -34,6 +34,7 @@ type RunOptions struct {
 	AutoMergePRs      bool     // Auto-merge PRs created during execution
 	Push              bool     // Commit and push workflow files before running
 	WaitForCompletion bool     // Wait for workflow completion
+	Follow            bool     // Stream the run's agent log until it completes, then print the audit summary
 	RepeatCount       int      // Number of times to repeat (0 = run once)
 	Inputs            []string // Workflow inputs in key=value format
 	Verbose           bool     // Enable verbose output
@@ -54,7 +55,7 @@ type WorkflowRunResult struct {
 
 // RunWorkflowOnGitHub runs an agentic workflow on GitHub Actions
 func RunWorkflowOnGitHub(ctx context.Context, workflowIdOrName string, opts RunOptions) error {
-	executionLog.Printf("Starting workflow run: workflow=%s, enable=%v, engineOverride=%s, repo=%s, ref=%s, push=%v, wait=%v, inputs=%v", workflowIdOrName, opts.Enable, opts.EngineOverride, opts.RepoOverride, opts.RefOverride, opts.Push, opts.WaitForCompletion, opts.Inputs)
+	executionLog.Printf("Starting workflow run: workflow=%s, enable=%v, engineOverride=%s, repo=%s, ref=%s, push=%v, wait=%v, follow=%v, inputs=%v", workflowIdOrName, opts.Enable, opts.EngineOverride, opts.RepoOverride, opts.RefOverride, opts.Push, opts.WaitForCompletion, opts.Follow, opts.Inputs)
 
 	// Check context cancellation at the start
 	select {
@@ -433,6 +434,26 @@ func RunWorkflowOnGitHub(ctx context.Context, workflowIdOrName string, opts RunO
 		fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("Note: Could not get workflow run URL: %v", runErr)))
 	}
 
+	// Stream the run until it completes; Ctrl-C cancels it
+	if opts.Follow {
+		var followErr error
+		if runErr != nil {
+			followErr = fmt.Errorf("could not find the workflow run to follow: %w", runErr)
+		} else {
+			followErr = FollowWorkflowRun(ctx, FollowOptions{
+				RunID:     runInfo.DatabaseID,
+				Repo:      opts.RepoOverride,
+				Engine:    opts.EngineOverride,
+				OutputDir: defaultLogsOutputDir,
+				Verbose:   opts.Verbose,
+			})
+		}
+		if opts.Enable && wasDisabled && workflowID != 0 {
+			restoreWorkflowState(workflowIdOrName, workflowID, opts.RepoOverride, opts.Verbose)
+		}
+		return followErr
+	}
+
 	// Wait for workflow completion if requested (for --repeat or --auto-merge-prs)
 	if opts.WaitForCompletion || opts.AutoMergePRs {
 		if runErr != nil {
@@ -494,11 +515,34 @@ func RunWorkflowOnGitHub(ctx context.Context, workflowIdOrName string, opts RunO
 	return nil
 }
 
+// validateFollowOptions rejects --follow combinations that would stream more than one run
+func validateFollowOptions(workflowCount int, opts RunOptions) error {
+	if !opts.Follow {
+		return nil
+	}
+	switch {
+	case workflowCount > 1:
+		return errors.New("--follow can only be used with a single workflow")
+	case opts.RepeatCount > 0:
+		return errors.New("--follow cannot be combined with --repeat")
+	case opts.AutoMergePRs:
+		return errors.New("--follow cannot be combined with --auto-merge-prs")
+	case opts.JSON:
+		return errors.New("--follow cannot be combined with --json")
+	case opts.DryRun:
+		return errors.New("--follow cannot be combined with --dry-run")
+	}
+	return nil
+}
+
 // RunWorkflowsOnGitHub runs multiple agentic workflows on GitHub Actions, optionally repeating a specified number of times
 func RunWorkflowsOnGitHub(ctx context.Context, workflowNames []string, opts RunOptions) error {
 	if len(workflowNames) == 0 {
 		return errors.New("at least one workflow name or ID is required")
 	}
+	if err := validateFollowOptions(len(workflowNames), opts); err != nil {
+		return err
+	}
 
 	// Check context cancellation at the start
 	select {
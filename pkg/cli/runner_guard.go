@@ -37,7 +37,7 @@ type runnerGuardOutput struct {
 
 // runRunnerGuardOnDirectory runs the runner-guard taint analysis scanner on a directory
 // containing workflows using the Docker image.
-func runRunnerGuardOnDirectory(workflowDir string, verbose bool, strict bool) error {
+func runRunnerGuardOnDirectory(workflowDir string, verbose bool, strict bool, collector *SecurityFindingCollector) error {
 	runnerGuardLog.Printf("Running runner-guard taint analysis on directory: %s", workflowDir)
 
 	// Find git root to get the absolute path for Docker volume mount
@@ -96,7 +96,7 @@ func runRunnerGuardOnDirectory(workflowDir string, verbose bool, strict bool) er
 	err = cmd.Run()
 
 	// Parse and display output
-	totalFindings, parseErr := parseAndDisplayRunnerGuardOutput(stdout.String(), verbose, gitRoot)
+	totalFindings, parseErr := parseAndDisplayRunnerGuardOutput(stdout.String(), verbose, gitRoot, collector)
 	if parseErr != nil {
 		runnerGuardLog.Printf("Failed to parse runner-guard output: %v", parseErr)
 		// Fall back to showing raw output
@@ -142,7 +142,7 @@ func runRunnerGuardOnDirectory(workflowDir string, verbose bool, strict bool) er
 
 // parseAndDisplayRunnerGuardOutput parses runner-guard JSON output and displays findings.
 // Returns the total number of findings found.
-func parseAndDisplayRunnerGuardOutput(stdout string, verbose bool, gitRoot string) (int, error) {
+func parseAndDisplayRunnerGuardOutput(stdout string, verbose bool, gitRoot string, collector *SecurityFindingCollector) (int, error) {
 	if stdout == "" {
 		return 0, nil // No output means no findings
 	}
@@ -261,6 +261,15 @@ func parseAndDisplayRunnerGuardOutput(stdout string, verbose bool, gitRoot strin
 				Context: context,
 			}
 
+			collector.Add(SecurityFinding{
+				Tool:    "runner-guard",
+				RuleID:  finding.RuleID,
+				Level:   securitySeverityLevel(finding.Severity),
+				Message: strings.TrimSuffix(finding.Name+" - "+finding.Description, " - "),
+				File:    finding.File,
+				Line:    lineNum,
+			})
+
 			fmt.Fprint(os.Stderr, console.FormatError(compilerErr))
 		}
 	}
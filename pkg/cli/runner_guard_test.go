@@ -253,7 +253,7 @@ func TestParseAndDisplayRunnerGuardOutput(t *testing.T) {
 
 			// Use a temp dir as gitRoot (no actual files — context display is skipped gracefully)
 			tmpDir := t.TempDir()
-			count, err := parseAndDisplayRunnerGuardOutput(tt.stdout, tt.verbose, tmpDir)
+			count, err := parseAndDisplayRunnerGuardOutput(tt.stdout, tt.verbose, tmpDir, nil)
 
 			// Restore stderr
 			w.Close()
@@ -321,7 +321,7 @@ func TestRunnerGuardPathTraversalGuard(t *testing.T) {
 			r, w, _ := os.Pipe()
 			os.Stderr = w
 
-			count, err := parseAndDisplayRunnerGuardOutput(stdout, false, tmpDir)
+			count, err := parseAndDisplayRunnerGuardOutput(stdout, false, tmpDir, nil)
 
 			w.Close()
 			os.Stderr = oldStderr
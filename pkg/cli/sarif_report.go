@@ -0,0 +1,311 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/constants"
+	"github.com/github/gh-aw/pkg/logger"
+)
+
+var sarifReportLog = logger.New("cli:sarif_report")
+
+// defaultSecurityBaselinePath is where the security baseline is committed, relative to the repository root
+const defaultSecurityBaselinePath = ".github/aw/security-baseline.json"
+
+// sarifFingerprintKey is the partial fingerprint name used for code scanning alert tracking
+const sarifFingerprintKey = "ghAwFindingHash/v1"
+
+// SecurityBaseline lists accepted findings; only findings missing from it fail compilation
+type SecurityBaseline struct {
+	Version  int                     `json:"version"`
+	Findings []SecurityBaselineEntry `json:"findings"`
+}
+
+// SecurityBaselineEntry is a single accepted finding
+type SecurityBaselineEntry struct {
+	Fingerprint   string `json:"fingerprint"`
+	Rule          string `json:"rule"`
+	File          string `json:"file"`
+	Message       string `json:"message"`
+	Justification string `json:"justification,omitempty"`
+}
+
+// SARIF 2.1.0 types, limited to the fields gh-aw emits
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+	HelpURI          string       `json:"helpUri,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID              string             `json:"ruleId"`
+	Level               string             `json:"level"`
+	Message             sarifMessage       `json:"message"`
+	Locations           []sarifLocation    `json:"locations"`
+	RelatedLocations    []sarifLocation    `json:"relatedLocations,omitempty"`
+	PartialFingerprints map[string]string  `json:"partialFingerprints"`
+	Suppressions        []sarifSuppression `json:"suppressions,omitempty"`
+	Properties          map[string]any     `json:"properties,omitempty"`
+}
+
+type sarifSuppression struct {
+	Kind          string `json:"kind"`
+	Justification string `json:"justification,omitempty"`
+}
+
+type sarifLocation struct {
+	ID               int                   `json:"id,omitempty"`
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+	Message          *sarifMessage         `json:"message,omitempty"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+// writeSecurityReport maps the collected findings to their sources, de-duplicates them,
+// writes the SARIF file and checks them against the baseline. It returns an error when
+// a baseline exists and findings are not covered by it.
+func writeSecurityReport(config CompileConfig, collector *SecurityFindingCollector) error {
+	if collector == nil {
+		return nil
+	}
+
+	files := newSourceFileCache(collector.gitRoot)
+	findings := make([]SecurityFinding, 0, len(collector.findings))
+	for _, finding := range collector.findings {
+		findings = append(findings, mapFindingToSource(finding, files))
+	}
+	findings = dedupeSecurityFindings(findings)
+
+	baselinePath := config.SARIFBaseline
+	if baselinePath == "" {
+		baselinePath = filepath.Join(collector.gitRoot, filepath.FromSlash(defaultSecurityBaselinePath))
+	}
+	baseline, err := loadSecurityBaseline(baselinePath)
+	if err != nil {
+		return err
+	}
+
+	fingerprints := make([]string, len(findings))
+	for i, finding := range findings {
+		fingerprints[i] = securityFindingFingerprint(finding, files)
+	}
+
+	if config.UpdateSARIFBaseline {
+		baseline = buildSecurityBaseline(findings, fingerprints, baseline)
+		if err := saveSecurityBaseline(baselinePath, baseline); err != nil {
+			return err
+		}
+		fmt.Fprintln(os.Stderr, console.FormatSuccessMessage(fmt.Sprintf("Updated security baseline %s with %d finding(s)", baselinePath, len(baseline.Findings))))
+	}
+
+	report := buildSARIFReport(findings, fingerprints, baseline)
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal SARIF report: %w", err)
+	}
+	if dir := filepath.Dir(config.SARIFFile); dir != "." {
+		if err := os.MkdirAll(dir, constants.DirPermPublic); err != nil {
+			return fmt.Errorf("failed to create SARIF output directory: %w", err)
+		}
+	}
+	if err := os.WriteFile(config.SARIFFile, data, constants.FilePermPublic); err != nil {
+		return fmt.Errorf("failed to write SARIF report: %w", err)
+	}
+
+	newFindings := 0
+	for _, result := range report.Runs[0].Results {
+		if len(result.Suppressions) == 0 {
+			newFindings++
+		}
+	}
+	sarifReportLog.Printf("Wrote %d SARIF result(s) to %s, %d not in baseline", len(findings), config.SARIFFile, newFindings)
+	fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("Wrote %d security finding(s) to %s", len(findings), config.SARIFFile)))
+
+	if baseline != nil && newFindings > 0 {
+		return fmt.Errorf("%d new security finding(s) not in baseline %s; fix them or run with --update-sarif-baseline to accept them", newFindings, baselinePath)
+	}
+	return nil
+}
+
+// buildSARIFReport converts findings into a single-run SARIF log. Findings present in
+// the baseline are emitted with an external suppression so code scanning does not alert on them.
+func buildSARIFReport(findings []SecurityFinding, fingerprints []string, baseline *SecurityBaseline) *sarifLog {
+	accepted := make(map[string]SecurityBaselineEntry)
+	if baseline != nil {
+		for _, entry := range baseline.Findings {
+			accepted[entry.Fingerprint] = entry
+		}
+	}
+
+	rules := make(map[string]sarifRule)
+	results := make([]sarifResult, 0, len(findings))
+	for i, finding := range findings {
+		ruleID := finding.Tool + "/" + finding.RuleID
+		if _, exists := rules[ruleID]; !exists {
+			rules[ruleID] = sarifRule{ID: ruleID, ShortDescription: sarifMessage{Text: ruleID}, HelpURI: finding.HelpURI}
+		}
+
+		result := sarifResult{
+			RuleID:              ruleID,
+			Level:               finding.Level,
+			Message:             sarifMessage{Text: finding.Message},
+			Locations:           []sarifLocation{{PhysicalLocation: sarifPhysicalLocationFor(finding.File, finding.Line, finding.Column)}},
+			PartialFingerprints: map[string]string{sarifFingerprintKey: fingerprints[i]},
+			Properties:          map[string]any{"tools": finding.Tools},
+		}
+		if finding.LockFile != "" {
+			result.RelatedLocations = []sarifLocation{{
+				ID:               1,
+				PhysicalLocation: sarifPhysicalLocationFor(finding.LockFile, finding.LockLine, 0),
+				Message:          &sarifMessage{Text: "Compiled location"},
+			}}
+		}
+		if entry, ok := accepted[fingerprints[i]]; ok {
+			result.Suppressions = []sarifSuppression{{Kind: "external", Justification: entry.Justification}}
+		}
+		results = append(results, result)
+	}
+
+	ruleList := make([]sarifRule, 0, len(rules))
+	for _, rule := range rules {
+		ruleList = append(ruleList, rule)
+	}
+	sort.Slice(ruleList, func(i, j int) bool { return ruleList[i].ID < ruleList[j].ID })
+
+	return &sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs: []sarifRun{{
+			Tool: sarifTool{Driver: sarifDriver{
+				Name:           "gh-aw",
+				Version:        GetVersion(),
+				InformationURI: "https://github.com/github/gh-aw",
+				Rules:          ruleList,
+			}},
+			Results: results,
+		}},
+	}
+}
+
+func sarifPhysicalLocationFor(file string, line, column int) sarifPhysicalLocation {
+	location := sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: file}}
+	if line > 0 {
+		location.Region = &sarifRegion{StartLine: line, StartColumn: column}
+	}
+	return location
+}
+
+// loadSecurityBaseline reads the baseline file; a missing file returns nil
+func loadSecurityBaseline(path string) (*SecurityBaseline, error) {
+	// #nosec G304 -- path is the baseline location chosen by the user or the default under .github/aw
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		sarifReportLog.Printf("No security baseline at %s", path)
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read security baseline: %w", err)
+	}
+	var baseline SecurityBaseline
+	if err := json.Unmarshal(data, &baseline); err != nil {
+		return nil, fmt.Errorf("failed to parse security baseline %s: %w", path, err)
+	}
+	return &baseline, nil
+}
+
+// buildSecurityBaseline accepts every current finding, keeping justifications
+// that were already recorded for findings still present
+func buildSecurityBaseline(findings []SecurityFinding, fingerprints []string, previous *SecurityBaseline) *SecurityBaseline {
+	justifications := make(map[string]string)
+	if previous != nil {
+		for _, entry := range previous.Findings {
+			justifications[entry.Fingerprint] = entry.Justification
+		}
+	}
+
+	baseline := &SecurityBaseline{Version: 1, Findings: []SecurityBaselineEntry{}}
+	seen := make(map[string]bool)
+	for i, finding := range findings {
+		if seen[fingerprints[i]] {
+			continue
+		}
+		seen[fingerprints[i]] = true
+		baseline.Findings = append(baseline.Findings, SecurityBaselineEntry{
+			Fingerprint:   fingerprints[i],
+			Rule:          finding.Tool + "/" + finding.RuleID,
+			File:          finding.File,
+			Message:       finding.Message,
+			Justification: justifications[fingerprints[i]],
+		})
+	}
+	sort.Slice(baseline.Findings, func(i, j int) bool {
+		a, b := baseline.Findings[i], baseline.Findings[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Rule != b.Rule {
+			return a.Rule < b.Rule
+		}
+		return a.Fingerprint < b.Fingerprint
+	})
+	return baseline
+}
+
+func saveSecurityBaseline(path string, baseline *SecurityBaseline) error {
+	data, err := json.MarshalIndent(baseline, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal security baseline: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), constants.DirPermPublic); err != nil {
+		return fmt.Errorf("failed to create security baseline directory: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), constants.FilePermPublic); err != nil {
+		return fmt.Errorf("failed to write security baseline: %w", err)
+	}
+	return nil
+}
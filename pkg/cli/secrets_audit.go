@@ -0,0 +1,485 @@
+package cli
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/constants"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/parser"
+	"github.com/goccy/go-yaml"
+)
+
+var secretsAuditLog = logger.New("cli:secrets_audit")
+
+// Secret scopes reported by the audit
+const (
+	secretScopeRepository   = "repository"
+	secretScopeOrganization = "organization"
+	secretScopeEnvironment  = "environment"
+)
+
+// SecretsAuditReport is the inventory of secrets referenced by workflows compared
+// with the secrets that exist at repository, organization and environment scope
+type SecretsAuditReport struct {
+	Repository string                 `json:"repository,omitempty"`
+	Workflows  []SecretsAuditWorkflow `json:"workflows"`
+	Scopes     *SecretScopes          `json:"scopes,omitempty"`
+	Missing    []SecretAuditFinding   `json:"missing"`
+	Unused     []SecretAuditFinding   `json:"unused"`
+	OverScoped []SecretAuditFinding   `json:"over_scoped"`
+	Warnings   []string               `json:"warnings,omitempty"`
+}
+
+// SecretsAuditWorkflow lists the secrets referenced by one workflow file
+type SecretsAuditWorkflow struct {
+	Workflow string        `json:"workflow"`
+	File     string        `json:"file"`
+	Secrets  []SecretUsage `json:"secrets"`
+}
+
+// SecretUsage describes why and where a workflow references a secret
+type SecretUsage struct {
+	Name         string   `json:"name"`
+	Reasons      []string `json:"reasons"`
+	Jobs         []string `json:"jobs,omitempty"`
+	Environments []string `json:"environments,omitempty"`
+	Alternatives []string `json:"alternatives,omitempty"` // Secrets that can be used instead (e.g. "A || B")
+	Optional     bool     `json:"optional"`
+
+	// outsideEnvironment is true when a job without an environment references the secret,
+	// so environment-scoped secrets alone cannot satisfy it
+	outsideEnvironment bool
+}
+
+// SecretScopes lists the secret names available to the repository at each scope
+type SecretScopes struct {
+	Repository   []string            `json:"repository"`
+	Organization []string            `json:"organization"`
+	Environments map[string][]string `json:"environments"`
+}
+
+// SecretAuditFinding is a missing, unused or over-scoped secret
+type SecretAuditFinding struct {
+	Name      string   `json:"name"`
+	Scope     string   `json:"scope,omitempty"`
+	Workflows []string `json:"workflows,omitempty"`
+	Message   string   `json:"message"`
+}
+
+var (
+	secretExpressionPattern = regexp.MustCompile(`\$\{\{(.*?)\}\}`)
+	secretNamePattern       = regexp.MustCompile(`\bsecrets\.([A-Za-z_][A-Za-z0-9_]*)`)
+)
+
+// buildSecretsInventory scans all workflow files in the directory and records the
+// secrets each one references. Compiled lock files are explained using the secrets:
+// section of their markdown source, and plain GitHub Actions workflows are included
+// so that secrets they use are not reported as unused.
+func buildSecretsInventory(workflowDir string) ([]SecretsAuditWorkflow, error) {
+	var files []string
+	for _, pattern := range []string{"*.yml", "*.yaml"} {
+		matches, err := filepath.Glob(filepath.Join(workflowDir, pattern))
+		if err != nil {
+			return nil, fmt.Errorf("failed to list workflow files: %w", err)
+		}
+		files = append(files, matches...)
+	}
+	sort.Strings(files)
+
+	var workflows []SecretsAuditWorkflow
+	for _, file := range files {
+		// #nosec G304 -- file comes from globbing the workflow directory
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", file, err)
+		}
+		name := strings.TrimSuffix(strings.TrimSuffix(filepath.Base(file), filepath.Ext(file)), ".lock")
+		var frontmatterSecrets map[string]string
+		if strings.HasSuffix(file, ".lock.yml") {
+			frontmatterSecrets = readFrontmatterSecretDescriptions(strings.TrimSuffix(file, ".lock.yml") + ".md")
+		}
+		secrets, err := extractSecretUsages(string(content), frontmatterSecrets)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read secrets from %s: %w", file, err)
+		}
+		if len(secrets) == 0 {
+			continue
+		}
+		workflows = append(workflows, SecretsAuditWorkflow{Workflow: name, File: filepath.ToSlash(file), Secrets: secrets})
+	}
+	secretsAuditLog.Printf("Found secret references in %d of %d workflow files", len(workflows), len(files))
+	return workflows, nil
+}
+
+// readFrontmatterSecretDescriptions maps secret names referenced from the frontmatter
+// secrets: section to the description given there, so the audit can explain them
+func readFrontmatterSecretDescriptions(markdownFile string) map[string]string {
+	// #nosec G304 -- markdownFile is the source of a lock file in the workflow directory
+	content, err := os.ReadFile(markdownFile)
+	if err != nil {
+		return nil
+	}
+	result, err := parser.ExtractFrontmatterFromContent(string(content))
+	if err != nil || result == nil {
+		return nil
+	}
+	declared, ok := result.Frontmatter["secrets"].(map[string]any)
+	if !ok {
+		return nil
+	}
+	descriptions := make(map[string]string)
+	for key, value := range declared {
+		expression, description := "", "declared in frontmatter secrets ("+key+")"
+		switch v := value.(type) {
+		case string:
+			expression = v
+		case map[string]any:
+			expression, _ = v["value"].(string)
+			if text, ok := v["description"].(string); ok && text != "" {
+				description = "declared in frontmatter secrets: " + text
+			}
+		}
+		for _, match := range secretNamePattern.FindAllStringSubmatch(expression, -1) {
+			descriptions[match[1]] = description
+		}
+	}
+	return descriptions
+}
+
+// secretsAuditWorkflowFile is the part of a GitHub Actions workflow that can reference secrets
+type secretsAuditWorkflowFile struct {
+	Env  map[string]any             `yaml:"env"`
+	Jobs map[string]secretsAuditJob `yaml:"jobs"`
+}
+
+type secretsAuditJob struct {
+	Environment any                `yaml:"environment"` // Environment name, or a map with a name
+	If          any                `yaml:"if"`
+	Env         map[string]any     `yaml:"env"`
+	With        map[string]any     `yaml:"with"`    // Inputs of a reusable workflow call
+	Secrets     any                `yaml:"secrets"` // Secrets passed to a reusable workflow call
+	Steps       []secretsAuditStep `yaml:"steps"`
+}
+
+type secretsAuditStep struct {
+	Name string         `yaml:"name"`
+	If   any            `yaml:"if"`
+	Run  any            `yaml:"run"`
+	Env  map[string]any `yaml:"env"`
+	With map[string]any `yaml:"with"`
+}
+
+// secretRedactionNamesKey is the env key of the step that redacts secret values from
+// logs; its SECRET_* env entries only exist to feed the redaction
+const secretRedactionNamesKey = "GH_AW_SECRET_NAMES"
+
+// secretReference is a single secrets.NAME reference in a ${{ }} expression
+type secretReference struct {
+	name, job, step, key string
+	optional             bool
+	chain                []string
+}
+
+// extractSecretUsages parses a workflow file and records every secrets.NAME reference
+// found in a ${{ }} expression of the workflow env, the jobs' env, if, with and secrets,
+// and the steps' env, with, if and run, together with the reason it is needed
+func extractSecretUsages(content string, frontmatterSecrets map[string]string) ([]SecretUsage, error) {
+	var file secretsAuditWorkflowFile
+	if err := yaml.Unmarshal([]byte(content), &file); err != nil {
+		return nil, fmt.Errorf("failed to parse workflow: %w", err)
+	}
+
+	var references []secretReference
+	for _, key := range slices.Sorted(maps.Keys(file.Env)) {
+		references = appendSecretReferences(references, file.Env[key], "", "", key)
+	}
+	jobEnvironments := make(map[string]string)
+	for _, job := range slices.Sorted(maps.Keys(file.Jobs)) {
+		spec := file.Jobs[job]
+		switch environment := spec.Environment.(type) {
+		case string:
+			jobEnvironments[job] = environment
+		case map[string]any:
+			jobEnvironments[job], _ = environment["name"].(string)
+		}
+		references = appendSecretReferences(references, spec.If, job, "", "if")
+		references = appendSecretReferences(references, spec.Secrets, job, "", "secrets")
+		for _, values := range []map[string]any{spec.Env, spec.With} {
+			for _, key := range slices.Sorted(maps.Keys(values)) {
+				references = appendSecretReferences(references, values[key], job, "", key)
+			}
+		}
+		for _, step := range spec.Steps {
+			_, redacts := step.Env[secretRedactionNamesKey]
+			references = appendSecretReferences(references, step.If, job, step.Name, "if")
+			references = appendSecretReferences(references, step.Run, job, step.Name, "run")
+			for _, values := range []map[string]any{step.Env, step.With} {
+				for _, key := range slices.Sorted(maps.Keys(values)) {
+					if redacts && strings.HasPrefix(key, "SECRET_") {
+						continue
+					}
+					references = appendSecretReferences(references, values[key], job, step.Name, key)
+				}
+			}
+		}
+	}
+
+	known := knownSecretRequirements()
+	usages := make(map[string]*SecretUsage)
+	for _, ref := range references {
+		if ref.name == "GITHUB_TOKEN" {
+			continue // Provided by GitHub Actions
+		}
+		requirement, isKnown := known[ref.name]
+		usage, exists := usages[ref.name]
+		if !exists {
+			usage = &SecretUsage{Name: ref.name, Optional: true}
+			usages[ref.name] = usage
+		}
+		usage.Optional = usage.Optional && (ref.optional || (isKnown && requirement.Optional))
+		addUnique(&usage.Reasons, secretReferenceReason(ref, frontmatterSecrets, known))
+		if ref.job != "" {
+			addUnique(&usage.Jobs, ref.job)
+		}
+		if environment := jobEnvironments[ref.job]; environment != "" {
+			addUnique(&usage.Environments, environment)
+		} else {
+			usage.outsideEnvironment = true
+		}
+		for _, alternative := range ref.chain {
+			if alternative != ref.name && alternative != "GITHUB_TOKEN" {
+				addUnique(&usage.Alternatives, alternative)
+			}
+		}
+		// An engine accepts its alternative secrets in place of its primary secret
+		if isKnown && requirement.Name == ref.name {
+			for _, alternative := range requirement.AlternativeEnvVars {
+				addUnique(&usage.Alternatives, alternative)
+			}
+		}
+	}
+
+	result := make([]SecretUsage, 0, len(usages))
+	for _, usage := range usages {
+		result = append(result, *usage)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result, nil
+}
+
+// appendSecretReferences records the secrets referenced in the ${{ }} expressions of a
+// YAML value. Nested maps and lists are walked so that values such as a reusable
+// workflow's secrets: map are covered.
+func appendSecretReferences(references []secretReference, value any, job, step, key string) []secretReference {
+	switch v := value.(type) {
+	case string:
+		for _, expression := range secretExpressionPattern.FindAllStringSubmatch(v, -1) {
+			var chain []string
+			for _, match := range secretNamePattern.FindAllStringSubmatch(expression[1], -1) {
+				if !slices.Contains(chain, match[1]) {
+					chain = append(chain, match[1])
+				}
+			}
+			// A fallback to the built-in token, or a comparison such as secrets.X != '',
+			// means the workflow still runs when the secret is not set
+			optional := slices.Contains(chain, "GITHUB_TOKEN") || strings.Contains(expression[1], "github.token") ||
+				strings.Contains(expression[1], "== ''") || strings.Contains(expression[1], "!= ''")
+			for _, name := range chain {
+				references = append(references, secretReference{name: name, job: job, step: step, key: key, optional: optional, chain: chain})
+			}
+		}
+	case map[string]any:
+		for _, nested := range slices.Sorted(maps.Keys(v)) {
+			references = appendSecretReferences(references, v[nested], job, step, nested)
+		}
+	case []any:
+		for _, item := range v {
+			references = appendSecretReferences(references, item, job, step, key)
+		}
+	}
+	return references
+}
+
+// knownSecretRequirements indexes the engine and system secret requirements by secret
+// name, including the alternative secrets an engine accepts. The first engine that
+// declares a secret wins.
+func knownSecretRequirements() map[string]SecretRequirement {
+	known := make(map[string]SecretRequirement)
+	for _, opt := range constants.EngineOptions {
+		for _, requirement := range getSecretRequirementsForEngine(opt.Value, true, true) {
+			for _, name := range append([]string{requirement.Name}, requirement.AlternativeEnvVars...) {
+				if _, exists := known[name]; !exists {
+					known[name] = requirement
+				}
+			}
+		}
+	}
+	return known
+}
+
+// secretReferenceReason explains why a secret is referenced: from the frontmatter
+// secrets: section, the engine and system secret metadata, the input or variable it is
+// passed to, or otherwise the job and step it appears in
+func secretReferenceReason(ref secretReference, frontmatterSecrets map[string]string, known map[string]SecretRequirement) string {
+	if description, ok := frontmatterSecrets[ref.name]; ok {
+		return description
+	}
+	if requirement, ok := known[ref.name]; ok {
+		if requirement.IsEngineSecret {
+			if opt := constants.GetEngineOption(requirement.EngineName); opt != nil {
+				return "engine authentication (" + opt.Label + ")"
+			}
+		}
+		return requirement.WhenNeeded
+	}
+	switch {
+	case ref.key == "private-key" || ref.key == "app-id" || ref.key == "client-id":
+		return "GitHub App credentials"
+	case strings.HasPrefix(ref.name, "GH_AW_OTEL_") || strings.HasPrefix(ref.key, "OTEL_") || strings.HasPrefix(ref.key, "GH_AW_OTLP_"):
+		return "OpenTelemetry export"
+	case ref.job == string(constants.SafeOutputsJobName) || ref.job == string(constants.ConclusionJobName):
+		return "safe outputs token"
+	case ref.key == "github-token" || ref.key == "GH_TOKEN" || ref.key == "GITHUB_TOKEN":
+		return "GitHub API token"
+	case ref.job == "":
+		return "workflow environment"
+	case ref.step == "":
+		return fmt.Sprintf("job %q", ref.job)
+	}
+	return fmt.Sprintf("job %q, step %q", ref.job, ref.step)
+}
+
+func addUnique(values *[]string, value string) {
+	if !slices.Contains(*values, value) {
+		*values = append(*values, value)
+	}
+}
+
+// classifySecrets compares the inventory with the existing secrets and fills in the
+// missing, unused and over-scoped findings of the report
+func classifySecrets(report *SecretsAuditReport) {
+	scopes := report.Scopes
+	repo := make(map[string]bool)
+	org := make(map[string]bool)
+	for _, name := range scopes.Repository {
+		repo[name] = true
+	}
+	for _, name := range scopes.Organization {
+		org[name] = true
+	}
+	inEnvironment := func(environment, name string) bool {
+		return slices.Contains(scopes.Environments[environment], name)
+	}
+	available := func(usage SecretUsage, name string) bool {
+		if repo[name] || org[name] {
+			return true
+		}
+		if usage.outsideEnvironment || len(usage.Environments) == 0 {
+			return false
+		}
+		for _, environment := range usage.Environments {
+			if !inEnvironment(environment, name) {
+				return false
+			}
+		}
+		return true
+	}
+
+	missing := make(map[string][]string)
+	referenced := make(map[string]bool)
+	usedInEnvironment := make(map[string]map[string]bool) // environment -> secret names
+	onlyInEnvironments := make(map[string]bool)           // secret -> referenced only by jobs with environments
+	usingWorkflows := make(map[string][]string)
+	for _, wf := range report.Workflows {
+		for _, usage := range wf.Secrets {
+			referenced[usage.Name] = true
+			for _, alternative := range usage.Alternatives {
+				referenced[alternative] = true
+			}
+			for _, environment := range usage.Environments {
+				if usedInEnvironment[environment] == nil {
+					usedInEnvironment[environment] = make(map[string]bool)
+				}
+				usedInEnvironment[environment][usage.Name] = true
+				for _, alternative := range usage.Alternatives {
+					usedInEnvironment[environment][alternative] = true
+				}
+			}
+			only, seen := onlyInEnvironments[usage.Name]
+			onlyInEnvironments[usage.Name] = (only || !seen) && !usage.outsideEnvironment && len(usage.Environments) > 0
+			usingWorkflows[usage.Name] = append(usingWorkflows[usage.Name], wf.Workflow)
+
+			if usage.Optional || available(usage, usage.Name) {
+				continue
+			}
+			if slices.ContainsFunc(usage.Alternatives, func(name string) bool { return available(usage, name) }) {
+				continue
+			}
+			missing[usage.Name] = append(missing[usage.Name], wf.Workflow)
+		}
+	}
+
+	report.Missing = []SecretAuditFinding{}
+	for _, name := range slices.Sorted(maps.Keys(missing)) {
+		report.Missing = append(report.Missing, SecretAuditFinding{
+			Name:      name,
+			Workflows: missing[name],
+			Message:   fmt.Sprintf("required by %d workflow(s) but not set at repository, organization or environment scope", len(missing[name])),
+		})
+	}
+
+	report.Unused = []SecretAuditFinding{}
+	for _, name := range scopes.Repository {
+		if !referenced[name] {
+			report.Unused = append(report.Unused, SecretAuditFinding{Name: name, Scope: secretScopeRepository, Message: "not referenced by any workflow"})
+		}
+	}
+	for _, environment := range slices.Sorted(maps.Keys(scopes.Environments)) {
+		for _, name := range scopes.Environments[environment] {
+			if !usedInEnvironment[environment][name] {
+				report.Unused = append(report.Unused, SecretAuditFinding{
+					Name:    name,
+					Scope:   secretScopeEnvironment + ":" + environment,
+					Message: fmt.Sprintf("not referenced by any job running in environment %q", environment),
+				})
+			}
+		}
+	}
+
+	report.OverScoped = []SecretAuditFinding{}
+	for _, name := range slices.Sorted(maps.Keys(onlyInEnvironments)) {
+		if !onlyInEnvironments[name] {
+			continue
+		}
+		scope := ""
+		switch {
+		case repo[name]:
+			scope = secretScopeRepository
+		case org[name]:
+			scope = secretScopeOrganization
+		default:
+			continue
+		}
+		var environments []string
+		for environment, names := range usedInEnvironment {
+			if names[name] {
+				environments = append(environments, environment)
+			}
+		}
+		sort.Strings(environments)
+		report.OverScoped = append(report.OverScoped, SecretAuditFinding{
+			Name:      name,
+			Scope:     scope,
+			Workflows: usingWorkflows[name],
+			Message:   fmt.Sprintf("only used by jobs in environment(s) %s; store it as an environment secret instead", strings.Join(environments, ", ")),
+		})
+	}
+	secretsAuditLog.Printf("Classified secrets: missing=%d, unused=%d, over-scoped=%d", len(report.Missing), len(report.Unused), len(report.OverScoped))
+}
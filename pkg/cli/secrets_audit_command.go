@@ -0,0 +1,309 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/constants"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/stringutil"
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/spf13/cobra"
+)
+
+var secretsAuditCommandLog = logger.New("cli:secrets_audit_command")
+
+// newSecretsAuditSubcommand creates the `secrets audit` subcommand
+func newSecretsAuditSubcommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Inventory secrets used by workflows and report drift",
+		Long: `Build an inventory of every secret referenced by the compiled workflows,
+with the reason each one is needed (engine authentication, MCP servers, GitHub
+App credentials, safe output tokens, frontmatter secrets, custom steps), and
+compare it with the secrets that exist for the repository.
+
+Secrets are looked up at repository, organization (secrets shared with this
+repository) and environment scope. The audit reports:
+  - missing:     required secrets that are not set at any scope available to the
+                 jobs that use them (fallbacks such as A || B are taken into account)
+  - unused:      repository and environment secrets no workflow references
+  - over-scoped: repository or organization secrets only used by jobs that run in
+                 a deployment environment, which should be environment secrets
+
+The inventory is read from lock files, so compile workflows first. The command
+fails when required secrets are missing.
+
+Examples:
+  ` + string(constants.CLIExtensionPrefix) + ` secrets audit                # Show the inventory and drift report
+  ` + string(constants.CLIExtensionPrefix) + ` secrets audit --json         # Machine-readable report for compliance
+  ` + string(constants.CLIExtensionPrefix) + ` secrets audit --fix          # Interactively set missing secrets`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			workflowDir, _ := cmd.Flags().GetString("dir")
+			repo, _ := cmd.Flags().GetString("repo")
+			jsonOutput, _ := cmd.Flags().GetBool("json")
+			fix, _ := cmd.Flags().GetBool("fix")
+			return runSecretsAudit(cmd, workflowDir, repo, jsonOutput, fix)
+		},
+	}
+
+	cmd.Flags().StringP("dir", "d", "", "Workflow directory (default: .github/workflows)")
+	cmd.Flags().Bool("fix", false, "Interactively set missing secrets as repository secrets")
+	addJSONFlag(cmd)
+	addRepoFlag(cmd)
+	RegisterDirFlagCompletion(cmd, "dir")
+
+	return cmd
+}
+
+// runSecretsAudit builds the inventory, compares it with the existing secrets and prints the report
+func runSecretsAudit(cmd *cobra.Command, workflowDir, repo string, jsonOutput, fix bool) error {
+	secretsAuditCommandLog.Printf("Running secrets audit: dir=%s, repo=%s, json=%v, fix=%v", workflowDir, repo, jsonOutput, fix)
+	if workflowDir == "" {
+		workflowDir = getWorkflowsDir()
+	}
+	if _, err := os.Stat(workflowDir); os.IsNotExist(err) {
+		return fmt.Errorf("no %s directory found", workflowDir)
+	}
+
+	workflows, err := buildSecretsInventory(workflowDir)
+	if err != nil {
+		return err
+	}
+	report := &SecretsAuditReport{Repository: repo, Workflows: workflows}
+	if report.Workflows == nil {
+		report.Workflows = []SecretsAuditWorkflow{}
+	}
+
+	if report.Repository == "" {
+		if report.Repository, err = GetCurrentRepoSlug(); err != nil {
+			report.Warnings = append(report.Warnings, fmt.Sprintf("could not detect the repository, existing secrets were not checked: %v", err))
+		}
+	}
+	if report.Repository != "" {
+		scopes, warnings, err := fetchSecretScopes(report.Repository)
+		report.Warnings = append(report.Warnings, warnings...)
+		if err != nil {
+			report.Warnings = append(report.Warnings, fmt.Sprintf("could not list repository secrets, existing secrets were not checked: %v", err))
+		} else {
+			report.Scopes = scopes
+			classifySecrets(report)
+		}
+	}
+
+	if fix && len(report.Missing) > 0 {
+		if err := fixMissingSecrets(cmd, report); err != nil {
+			return err
+		}
+	}
+
+	if jsonOutput {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal secrets audit: %w", err)
+		}
+		fmt.Println(string(data))
+	} else {
+		displaySecretsAudit(report)
+	}
+
+	if len(report.Missing) > 0 {
+		return fmt.Errorf("%d required secret(s) missing", len(report.Missing))
+	}
+	return nil
+}
+
+// fetchSecretScopes lists the secret names available to a repository at each scope.
+// Failing to list organization or environment secrets is reported as a warning, since
+// it usually means the token lacks access rather than that no secrets exist.
+func fetchSecretScopes(repoSlug string) (*SecretScopes, []string, error) {
+	scopes := &SecretScopes{Environments: make(map[string][]string)}
+	var warnings []string
+
+	output, err := workflow.RunGH("Checking repository secrets...", "api", "--paginate", fmt.Sprintf("/repos/%s/actions/secrets", repoSlug), "--jq", ".secrets[].name")
+	if err != nil {
+		return nil, nil, err
+	}
+	scopes.Repository = sortedSecretNames(output)
+
+	output, err = workflow.RunGH("Checking organization secrets...", "api", "--paginate", fmt.Sprintf("/repos/%s/actions/organization-secrets", repoSlug), "--jq", ".secrets[].name")
+	if err != nil {
+		secretsAuditCommandLog.Printf("Could not list organization secrets: %v", err)
+		warnings = append(warnings, "could not list organization secrets shared with the repository")
+	}
+	scopes.Organization = sortedSecretNames(output)
+
+	output, err = workflow.RunGH("Checking environments...", "api", "--paginate", fmt.Sprintf("/repos/%s/environments", repoSlug), "--jq", ".environments[].name")
+	if err != nil {
+		secretsAuditCommandLog.Printf("Could not list environments: %v", err)
+		warnings = append(warnings, "could not list repository environments")
+		return scopes, warnings, nil
+	}
+	for _, environment := range parseSecretNames(output) {
+		output, err := workflow.RunGH("Checking environment secrets...", "api", "--paginate", fmt.Sprintf("/repos/%s/environments/%s/secrets", repoSlug, url.PathEscape(environment)), "--jq", ".secrets[].name")
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("could not list secrets of environment %q", environment))
+			continue
+		}
+		scopes.Environments[environment] = sortedSecretNames(output)
+	}
+	return scopes, warnings, nil
+}
+
+func sortedSecretNames(output []byte) []string {
+	names := parseSecretNames(output)
+	if names == nil {
+		return []string{}
+	}
+	slices.Sort(names)
+	return slices.Compact(names)
+}
+
+// fixMissingSecrets prompts for each missing secret and stores it as a repository secret.
+// Secrets that were set are removed from the missing list.
+func fixMissingSecrets(cmd *cobra.Command, report *SecretsAuditReport) error {
+	if report.Repository == "" {
+		return errors.New("--fix requires a repository; use --repo")
+	}
+	existing := make(map[string]bool)
+	for _, name := range report.Scopes.Repository {
+		existing[name] = true
+	}
+	config := EngineSecretConfig{Ctx: cmd.Context(), RepoSlug: report.Repository, ExistingSecrets: existing}
+
+	var remaining []SecretAuditFinding
+	for _, finding := range report.Missing {
+		req := auditSecretRequirement(finding.Name, report)
+		fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("%s is required by %s", finding.Name, strings.Join(finding.Workflows, ", "))))
+		if err := promptForSecret(req, config); err != nil {
+			return err
+		}
+		if os.Getenv(finding.Name) == "" {
+			remaining = append(remaining, finding)
+		}
+	}
+	report.Missing = remaining
+	if report.Missing == nil {
+		report.Missing = []SecretAuditFinding{}
+	}
+	return nil
+}
+
+// auditSecretRequirement describes a missing secret so the existing secret prompts can
+// be reused: engine keys get the engine's key URL, GH_AW_* tokens get the PAT prompt
+// and any other secret is requested as an API key
+func auditSecretRequirement(name string, report *SecretsAuditReport) SecretRequirement {
+	for _, opt := range constants.EngineOptions {
+		if opt.SecretName == name || slices.Contains(opt.AlternativeSecrets, name) {
+			return SecretRequirement{Name: name, WhenNeeded: opt.WhenNeeded, KeyURL: opt.KeyURL, IsEngineSecret: true, EngineName: opt.Value}
+		}
+	}
+	var reasons []string
+	for _, wf := range report.Workflows {
+		for _, usage := range wf.Secrets {
+			if usage.Name == name {
+				for _, reason := range usage.Reasons {
+					addUnique(&reasons, reason)
+				}
+			}
+		}
+	}
+	req := SecretRequirement{Name: name, WhenNeeded: strings.Join(reasons, "; "), IsEngineSecret: !strings.HasPrefix(name, "GH_AW_")}
+	for _, sys := range constants.SystemSecrets {
+		if sys.Name == name {
+			req.WhenNeeded, req.Description = sys.WhenNeeded, sys.Description
+		}
+	}
+	return req
+}
+
+// displaySecretsAudit prints the inventory and findings to stderr
+func displaySecretsAudit(report *SecretsAuditReport) {
+	type inventoryEntry struct {
+		workflows int
+		reasons   []string
+		optional  bool
+	}
+	inventory := make(map[string]*inventoryEntry)
+	for _, wf := range report.Workflows {
+		for _, usage := range wf.Secrets {
+			entry, exists := inventory[usage.Name]
+			if !exists {
+				entry = &inventoryEntry{optional: true}
+				inventory[usage.Name] = entry
+			}
+			entry.workflows++
+			entry.optional = entry.optional && usage.Optional
+			for _, reason := range usage.Reasons {
+				addUnique(&entry.reasons, reason)
+			}
+		}
+	}
+
+	if len(inventory) == 0 {
+		fmt.Fprintln(os.Stderr, console.FormatInfoMessage("No workflow references any secret"))
+	} else {
+		var rows [][]string
+		for _, name := range slices.Sorted(maps.Keys(inventory)) {
+			entry := inventory[name]
+			required := "yes"
+			if entry.optional {
+				required = "no"
+			}
+			rows = append(rows, []string{name, strconv.Itoa(entry.workflows), required, secretScopeSummary(report.Scopes, name), stringutil.Truncate(strings.Join(entry.reasons, "; "), 80)})
+		}
+		fmt.Fprint(os.Stderr, console.RenderTable(console.TableConfig{
+			Title:   "Secrets inventory",
+			Headers: []string{"Secret", "Workflows", "Required", "Set at", "Used for"},
+			Rows:    rows,
+		}))
+	}
+
+	for _, warning := range report.Warnings {
+		fmt.Fprintln(os.Stderr, console.FormatWarningMessage(warning))
+	}
+	for _, finding := range report.Missing {
+		fmt.Fprintln(os.Stderr, console.FormatErrorMessage(fmt.Sprintf("missing %s: %s (%s)", finding.Name, finding.Message, strings.Join(finding.Workflows, ", "))))
+	}
+	for _, finding := range report.Unused {
+		fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("unused %s (%s): %s", finding.Name, finding.Scope, finding.Message)))
+	}
+	for _, finding := range report.OverScoped {
+		fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("over-scoped %s (%s): %s", finding.Name, finding.Scope, finding.Message)))
+	}
+	if report.Scopes != nil && len(report.Missing)+len(report.Unused)+len(report.OverScoped) == 0 {
+		fmt.Fprintln(os.Stderr, console.FormatSuccessMessage("All referenced secrets are set and no secret is unused or over-scoped"))
+	}
+}
+
+// secretScopeSummary lists the scopes a secret is set at, or "-" when it is not set
+func secretScopeSummary(scopes *SecretScopes, name string) string {
+	if scopes == nil {
+		return "?"
+	}
+	var set []string
+	if slices.Contains(scopes.Repository, name) {
+		set = append(set, secretScopeRepository)
+	}
+	if slices.Contains(scopes.Organization, name) {
+		set = append(set, secretScopeOrganization)
+	}
+	for _, environment := range slices.Sorted(maps.Keys(scopes.Environments)) {
+		if slices.Contains(scopes.Environments[environment], name) {
+			set = append(set, secretScopeEnvironment+":"+environment)
+		}
+	}
+	if len(set) == 0 {
+		return "-"
+	}
+	return strings.Join(set, ", ")
+}
@@ -0,0 +1,124 @@
+//go:build !integration
+
+package cli
+
+import (
+	"testing"
+
+	"github.com/github/gh-aw/pkg/constants"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const secretsAuditLockFixture = `name: "Deploy helper"
+env:
+  OTEL_EXPORTER_OTLP_HEADERS: ${{ secrets.GH_AW_OTEL_HEADERS }}
+jobs:
+  agent:
+    runs-on: ubuntu-latest
+    steps:
+      - name: Execute Claude Code CLI
+        env:
+          ANTHROPIC_API_KEY: ${{ secrets.ANTHROPIC_API_KEY }}
+          GH_TOKEN: ${{ secrets.GH_AW_GITHUB_TOKEN || secrets.GITHUB_TOKEN }}
+      - name: Start MCP Gateway
+        env:
+          SEARCH_API_KEY: ${{ secrets.SEARCH_API_KEY }}
+      - name: Redact secrets in logs
+        env:
+          GH_AW_SECRET_NAMES: 'ONLY_REDACTED'
+          SECRET_ONLY_REDACTED: ${{ secrets.ONLY_REDACTED }}
+  deploy:
+    environment:
+      name: production
+    steps:
+      - name: Publish
+        env:
+          TOKEN: ${{ secrets.DEPLOY_TOKEN || secrets.DEPLOY_TOKEN_FALLBACK }}
+`
+
+func TestExtractSecretUsages(t *testing.T) {
+	usages, err := extractSecretUsages(secretsAuditLockFixture, map[string]string{"SEARCH_API_KEY": "declared in frontmatter secrets: search"})
+	require.NoError(t, err)
+	byName := make(map[string]SecretUsage)
+	for _, usage := range usages {
+		byName[usage.Name] = usage
+	}
+
+	assert.NotContains(t, byName, "GITHUB_TOKEN", "built-in token should not be listed")
+	assert.NotContains(t, byName, "ONLY_REDACTED", "redaction-only references should be skipped")
+
+	assert.Equal(t, []string{"engine authentication (Claude)"}, byName["ANTHROPIC_API_KEY"].Reasons)
+	assert.False(t, byName["ANTHROPIC_API_KEY"].Optional)
+	assert.True(t, byName["GH_AW_GITHUB_TOKEN"].Optional, "fallback to GITHUB_TOKEN makes the secret optional")
+	assert.Equal(t, []string{constants.SystemSecrets[0].WhenNeeded}, byName["GH_AW_GITHUB_TOKEN"].Reasons, "system secrets should be explained by their metadata")
+	assert.Equal(t, []string{"declared in frontmatter secrets: search"}, byName["SEARCH_API_KEY"].Reasons)
+	assert.Equal(t, []string{"OpenTelemetry export"}, byName["GH_AW_OTEL_HEADERS"].Reasons)
+
+	deploy := byName["DEPLOY_TOKEN"]
+	assert.Equal(t, []string{"deploy"}, deploy.Jobs)
+	assert.Equal(t, []string{"production"}, deploy.Environments)
+	assert.Equal(t, []string{"DEPLOY_TOKEN_FALLBACK"}, deploy.Alternatives)
+	assert.Equal(t, []string{`job "deploy", step "Publish"`}, deploy.Reasons)
+	assert.False(t, deploy.outsideEnvironment)
+}
+
+func TestExtractSecretUsages_InvalidYAML(t *testing.T) {
+	_, err := extractSecretUsages("jobs: [unclosed", nil)
+	require.Error(t, err, "invalid YAML should be reported")
+}
+
+func TestClassifySecrets(t *testing.T) {
+	secrets, err := extractSecretUsages(secretsAuditLockFixture, nil)
+	require.NoError(t, err)
+	report := &SecretsAuditReport{
+		Workflows: []SecretsAuditWorkflow{{
+			Workflow: "deploy-helper",
+			Secrets:  secrets,
+		}},
+		Scopes: &SecretScopes{
+			Repository:   []string{"ANTHROPIC_API_KEY", "DEPLOY_TOKEN_FALLBACK", "LEFTOVER"},
+			Organization: []string{"GH_AW_OTEL_HEADERS"},
+			Environments: map[string][]string{"production": {"DEPLOY_TOKEN", "STALE"}},
+		},
+	}
+	classifySecrets(report)
+
+	require.Len(t, report.Missing, 1)
+	assert.Equal(t, "SEARCH_API_KEY", report.Missing[0].Name)
+	assert.Equal(t, []string{"deploy-helper"}, report.Missing[0].Workflows)
+
+	var unused []string
+	for _, finding := range report.Unused {
+		unused = append(unused, finding.Name+"@"+finding.Scope)
+	}
+	assert.Equal(t, []string{"LEFTOVER@repository", "STALE@environment:production"}, unused)
+
+	require.Len(t, report.OverScoped, 1, "repository secret only used in the production environment")
+	assert.Equal(t, "DEPLOY_TOKEN_FALLBACK", report.OverScoped[0].Name)
+	assert.Equal(t, secretScopeRepository, report.OverScoped[0].Scope)
+}
+
+func TestClassifySecrets_EnvironmentAlternative(t *testing.T) {
+	lock := `jobs:
+  agent:
+    environment: production
+    steps:
+      - name: Execute Codex
+        env:
+          OPENAI_API_KEY: ${{ secrets.OPENAI_API_KEY }}
+`
+	secrets, err := extractSecretUsages(lock, nil)
+	require.NoError(t, err)
+	require.Len(t, secrets, 1)
+	assert.Equal(t, []string{"CODEX_API_KEY"}, secrets[0].Alternatives, "the engine's alternative secret should be accepted")
+
+	report := &SecretsAuditReport{
+		Workflows: []SecretsAuditWorkflow{{Workflow: "codex", Secrets: secrets}},
+		Scopes:    &SecretScopes{Environments: map[string][]string{"production": {"CODEX_API_KEY"}}},
+	}
+	classifySecrets(report)
+
+	assert.Empty(t, report.Missing, "an environment secret should satisfy the alternative")
+	assert.Empty(t, report.Unused, "an environment secret used as an alternative is not unused")
+}
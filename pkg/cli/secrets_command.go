@@ -21,10 +21,12 @@ AI API keys (Anthropic, OpenAI, GitHub Copilot) and GitHub tokens for workflow e
 Available subcommands:
   - set       - Create or update individual secrets
   - bootstrap - Validate and configure all required secrets for workflows
+  - audit     - Inventory secrets used by workflows and report missing, unused or over-scoped ones
 
 Examples:
   gh aw secrets set MY_SECRET --value "secret123"    # Set a secret directly
-  gh aw secrets bootstrap                             # Check all required secrets`,
+  gh aw secrets bootstrap                             # Check all required secrets
+  gh aw secrets audit --json                          # Secrets inventory and drift report`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return cmd.Help()
 		},
@@ -33,6 +35,7 @@ Examples:
 	// Add subcommands
 	cmd.AddCommand(newSecretsSetSubcommand())
 	cmd.AddCommand(newSecretsBootstrapSubcommand())
+	cmd.AddCommand(newSecretsAuditSubcommand())
 
 	return cmd
 }
@@ -0,0 +1,314 @@
+package cli
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/stringutil"
+)
+
+var securityFindingsLog = logger.New("cli:security_findings")
+
+// SecurityFinding is a single finding from a workflow security scanner or from
+// gh-aw's own validators, normalised so that findings from all tools can be
+// merged into one SARIF report
+type SecurityFinding struct {
+	Tool     string   // "actionlint", "zizmor", "poutine", "runner-guard" or "gh-aw"
+	RuleID   string   // Tool-specific rule identifier
+	Level    string   // "error", "warning" or "note"
+	Message  string   // Human-readable description
+	HelpURI  string   // Optional documentation link
+	File     string   // Repository-relative path (forward slashes)
+	Line     int      // 1-based line, 0 when unknown
+	Column   int      // 1-based column, 0 when unknown
+	LockFile string   // Original lock file location when the finding was mapped to its source .md
+	LockLine int      // Original lock file line when the finding was mapped to its source .md
+	Tools    []string // All tools that reported this finding after de-duplication
+}
+
+// SecurityFindingCollector accumulates the findings of one compile run with --sarif:
+// the scanners' findings and the compiler's own diagnostics. Methods on a nil
+// collector discard findings, so scanners can record unconditionally.
+type SecurityFindingCollector struct {
+	mu       sync.Mutex
+	gitRoot  string
+	findings []SecurityFinding
+}
+
+// newSecurityFindingCollector creates a collector that reports paths relative to gitRoot
+func newSecurityFindingCollector(gitRoot string) *SecurityFindingCollector {
+	return &SecurityFindingCollector{gitRoot: gitRoot}
+}
+
+// Add records a finding
+func (c *SecurityFindingCollector) Add(finding SecurityFinding) {
+	if c == nil {
+		return
+	}
+	finding.File = c.relativePath(finding.File)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.findings = append(c.findings, finding)
+}
+
+// AddCompilerDiagnostics records the warnings the compiler emitted for a workflow and
+// the errors it failed with. Diagnostics without a file are attributed to the
+// workflow's markdown file.
+func (c *SecurityFindingCollector) AddCompilerDiagnostics(markdownFile string, diagnostics []console.CompilerError, errs []CompileValidationError) {
+	if c == nil {
+		return
+	}
+	for _, diagnostic := range diagnostics {
+		c.Add(SecurityFinding{
+			Tool:    "gh-aw",
+			RuleID:  "compiler-warning",
+			Level:   "warning",
+			Message: stringutil.StripANSI(diagnostic.Message),
+			File:    compilerDiagnosticPath(diagnostic.Position.File, markdownFile),
+			Line:    diagnostic.Position.Line,
+			Column:  diagnostic.Position.Column,
+		})
+	}
+	for _, err := range errs {
+		c.Add(SecurityFinding{
+			Tool:    "gh-aw",
+			RuleID:  "compiler-error",
+			Level:   "error",
+			Message: stringutil.StripANSI(err.Message),
+			File:    compilerDiagnosticPath("", markdownFile),
+			Line:    err.Line,
+		})
+	}
+}
+
+// compilerDiagnosticPath returns the absolute path of a compiler diagnostic's file.
+// Compiler paths are relative to the working directory, not the repository root.
+func compilerDiagnosticPath(file, markdownFile string) string {
+	if file == "" {
+		file = markdownFile
+	}
+	if absFile, err := filepath.Abs(file); err == nil {
+		return absFile
+	}
+	return file
+}
+
+// securitySeverityLevel maps the severity names used by the scanners onto SARIF levels
+func securitySeverityLevel(severity string) string {
+	switch strings.ToLower(severity) {
+	case "critical", "high", "error":
+		return "error"
+	case "note", "info", "informational", "low":
+		return "note"
+	default:
+		return "warning"
+	}
+}
+
+// relativePath converts a scanner-reported path into a repository-relative path.
+// Scanners run from the repository root, so relative paths are kept as-is.
+func (c *SecurityFindingCollector) relativePath(file string) string {
+	if file == "" {
+		return ""
+	}
+	if filepath.IsAbs(file) {
+		if rel, err := filepath.Rel(c.gitRoot, file); err == nil && !strings.HasPrefix(rel, "..") {
+			file = rel
+		}
+	}
+	return strings.TrimPrefix(filepath.ToSlash(filepath.Clean(file)), "./")
+}
+
+// mappedLockKeys are top-level lock file keys copied from the workflow frontmatter,
+// so findings under them can be attributed to the frontmatter line
+var mappedLockKeys = map[string]bool{
+	"on":              true,
+	"permissions":     true,
+	"concurrency":     true,
+	"env":             true,
+	"run-name":        true,
+	"timeout-minutes": true,
+}
+
+var lockTopLevelKeyPattern = regexp.MustCompile(`^"?([A-Za-z_-]+)"?:`)
+
+// sourceFileCache reads repository files at most once while mapping findings
+type sourceFileCache struct {
+	gitRoot string
+	lines   map[string][]string
+}
+
+func newSourceFileCache(gitRoot string) *sourceFileCache {
+	return &sourceFileCache{gitRoot: gitRoot, lines: make(map[string][]string)}
+}
+
+// get returns the lines of a repository-relative file, or nil when it cannot be read
+func (s *sourceFileCache) get(file string) []string {
+	if lines, ok := s.lines[file]; ok {
+		return lines
+	}
+	var lines []string
+	// #nosec G304 -- file is a repository-relative path reported by a scanner and joined with the git root
+	if content, err := os.ReadFile(filepath.Join(s.gitRoot, filepath.FromSlash(file))); err == nil {
+		lines = strings.Split(string(content), "\n")
+	}
+	s.lines[file] = lines
+	return lines
+}
+
+// lineText returns the trimmed text of a 1-based line, or "" when unavailable
+func (s *sourceFileCache) lineText(file string, line int) string {
+	lines := s.get(file)
+	if line < 1 || line > len(lines) {
+		return ""
+	}
+	return strings.TrimSpace(lines[line-1])
+}
+
+// mapFindingToSource attributes a finding reported on a compiled .lock.yml back to
+// the workflow's .md source where possible: first by finding the same line verbatim
+// in the markdown (custom steps, run scripts, uses: references), then by mapping the
+// enclosing top-level key (on:, permissions:, ...) to the frontmatter.
+func mapFindingToSource(finding SecurityFinding, files *sourceFileCache) SecurityFinding {
+	if !strings.HasSuffix(finding.File, ".lock.yml") || finding.Line < 1 {
+		return finding
+	}
+	markdownFile := strings.TrimSuffix(finding.File, ".lock.yml") + ".md"
+	markdownLines := files.get(markdownFile)
+	lockLines := files.get(finding.File)
+	if markdownLines == nil || finding.Line > len(lockLines) {
+		return finding
+	}
+
+	mapped := func(line int) SecurityFinding {
+		finding.LockFile, finding.LockLine = finding.File, finding.Line
+		finding.File, finding.Line, finding.Column = markdownFile, line, 0
+		return finding
+	}
+
+	// Lines shorter than this (e.g. "with:" or "}") are too generic to match reliably
+	const minMatchLength = 12
+	lockText := strings.TrimSpace(lockLines[finding.Line-1])
+	if len(lockText) >= minMatchLength {
+		match := 0
+		for i, line := range markdownLines {
+			if strings.TrimSpace(line) == lockText {
+				if match != 0 {
+					match = -1
+					break
+				}
+				match = i + 1
+			}
+		}
+		if match > 0 {
+			return mapped(match)
+		}
+	}
+
+	for i := finding.Line - 1; i >= 0; i-- {
+		line := lockLines[i]
+		if line == "" || strings.HasPrefix(line, " ") || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key := lockTopLevelKeyPattern.FindStringSubmatch(line)
+		if key == nil || !mappedLockKeys[key[1]] {
+			break
+		}
+		for j, mdLine := range markdownLines {
+			if j > 0 && strings.TrimSpace(mdLine) == "---" {
+				break // end of frontmatter
+			}
+			if strings.HasPrefix(mdLine, key[1]+":") {
+				return mapped(j + 1)
+			}
+		}
+		break
+	}
+	return finding
+}
+
+// securityFindingCategory groups rules from different scanners that report the same
+// underlying issue, so that a single problem is reported once
+func securityFindingCategory(finding SecurityFinding) string {
+	id := strings.ToLower(finding.RuleID)
+	switch {
+	case strings.Contains(id, "injection"):
+		return "injection"
+	case strings.Contains(id, "unpinned"):
+		return "unpinned-action"
+	case strings.Contains(id, "permission"):
+		return "permissions"
+	case strings.Contains(id, "artipacked"), strings.Contains(id, "persist-credentials"):
+		return "persisted-credentials"
+	}
+	return finding.Tool + "/" + finding.RuleID
+}
+
+// securityLevelRank orders SARIF levels (higher is more severe)
+func securityLevelRank(level string) int {
+	switch level {
+	case "error":
+		return 3
+	case "warning":
+		return 2
+	case "note":
+		return 1
+	default:
+		return 0
+	}
+}
+
+// dedupeSecurityFindings merges findings that different tools (or repeated runs of
+// the same tool) reported for the same location and category. The merged finding
+// keeps the first report's message and the most severe level.
+func dedupeSecurityFindings(findings []SecurityFinding) []SecurityFinding {
+	index := make(map[string]int)
+	var result []SecurityFinding
+	for _, finding := range findings {
+		key := strings.Join([]string{finding.File, strconv.Itoa(finding.Line), securityFindingCategory(finding), dedupeMessageKey(finding)}, "\x00")
+		if i, ok := index[key]; ok {
+			existing := &result[i]
+			if securityLevelRank(finding.Level) > securityLevelRank(existing.Level) {
+				existing.Level = finding.Level
+			}
+			if !slices.Contains(existing.Tools, finding.Tool) {
+				existing.Tools = append(existing.Tools, finding.Tool)
+			}
+			continue
+		}
+		finding.Tools = []string{finding.Tool}
+		index[key] = len(result)
+		result = append(result, finding)
+	}
+	securityFindingsLog.Printf("De-duplicated %d findings into %d", len(findings), len(result))
+	return result
+}
+
+// dedupeMessageKey distinguishes gh-aw warnings, which share a single rule ID,
+// by message so that different warnings on the same file are all kept
+func dedupeMessageKey(finding SecurityFinding) string {
+	if finding.Tool == "gh-aw" {
+		return finding.Message
+	}
+	return ""
+}
+
+// securityFindingFingerprint identifies a finding independently of line numbers,
+// so that baselines survive unrelated edits that shift lines
+func securityFindingFingerprint(finding SecurityFinding, files *sourceFileCache) string {
+	anchor := files.lineText(finding.File, finding.Line)
+	if anchor == "" && finding.Tool == "gh-aw" {
+		anchor = finding.Message
+	}
+	sum := sha256.Sum256([]byte(strings.Join([]string{securityFindingCategory(finding), finding.File, anchor}, "\x00")))
+	return hex.EncodeToString(sum[:8])
+}
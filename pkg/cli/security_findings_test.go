@@ -0,0 +1,142 @@
+//go:build !integration
+
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/constants"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const securityFindingsMarkdown = `---
+on:
+  issues:
+    types: [opened]
+permissions:
+  contents: read
+steps:
+  - uses: some/action@main
+---
+Triage the issue.
+`
+
+const securityFindingsLock = `name: "Triage"
+"on":
+  issues:
+    types: [opened]
+permissions: {}
+jobs:
+  agent:
+    steps:
+      - uses: actions/checkout@de0fac2e4500dabe0009e67214ff5f5447ce83dd
+      - uses: some/action@main
+`
+
+func writeSecurityFindingsFixture(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	dir := filepath.Join(root, ".github", "workflows")
+	require.NoError(t, os.MkdirAll(dir, constants.DirPermPublic), "workflow dir should be created")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "triage.md"), []byte(securityFindingsMarkdown), constants.FilePermPublic), "markdown should be written")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "triage.lock.yml"), []byte(securityFindingsLock), constants.FilePermPublic), "lock file should be written")
+	return root
+}
+
+func TestMapFindingToSource(t *testing.T) {
+	files := newSourceFileCache(writeSecurityFindingsFixture(t))
+	lockFile := ".github/workflows/triage.lock.yml"
+
+	tests := []struct {
+		name     string
+		line     int
+		wantFile string
+		wantLine int
+	}{
+		{name: "verbatim step line", line: 10, wantFile: ".github/workflows/triage.md", wantLine: 8},
+		{name: "verbatim trigger line", line: 4, wantFile: ".github/workflows/triage.md", wantLine: 4},
+		{name: "trigger maps to frontmatter key", line: 3, wantFile: ".github/workflows/triage.md", wantLine: 2},
+		{name: "generated step stays in lock file", line: 9, wantFile: lockFile, wantLine: 9},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mapped := mapFindingToSource(SecurityFinding{Tool: "zizmor", RuleID: "unpinned-uses", File: lockFile, Line: tt.line, Column: 7}, files)
+			assert.Equal(t, tt.wantFile, mapped.File, "file should be mapped")
+			assert.Equal(t, tt.wantLine, mapped.Line, "line should be mapped")
+			if tt.wantFile != lockFile {
+				assert.Equal(t, lockFile, mapped.LockFile, "original lock file should be kept")
+				assert.Equal(t, tt.line, mapped.LockLine, "original lock line should be kept")
+			}
+		})
+	}
+}
+
+func TestDedupeSecurityFindings(t *testing.T) {
+	findings := dedupeSecurityFindings([]SecurityFinding{
+		{Tool: "zizmor", RuleID: "template-injection", Level: "warning", File: "a.md", Line: 5, Message: "zizmor"},
+		{Tool: "runner-guard", RuleID: "RGS-001-expression-injection", Level: "error", File: "a.md", Line: 5, Message: "runner-guard"},
+		{Tool: "poutine", RuleID: "unpinnable_action", Level: "note", File: "a.md", Line: 5},
+		{Tool: "gh-aw", RuleID: "compiler-warning", Level: "warning", File: "a.md", Message: "first"},
+		{Tool: "gh-aw", RuleID: "compiler-warning", Level: "warning", File: "a.md", Message: "second"},
+	})
+
+	require.Len(t, findings, 4, "only the injection findings should be merged")
+	assert.Equal(t, "zizmor", findings[0].Message, "first report's message should be kept")
+	assert.Equal(t, "error", findings[0].Level, "most severe level should win")
+	assert.Equal(t, []string{"zizmor", "runner-guard"}, findings[0].Tools, "all reporting tools should be listed")
+}
+
+func TestSecurityBaselineSuppression(t *testing.T) {
+	root := writeSecurityFindingsFixture(t)
+	files := newSourceFileCache(root)
+	findings := []SecurityFinding{
+		{Tool: "zizmor", RuleID: "unpinned-uses", Level: "error", File: ".github/workflows/triage.md", Line: 8, Tools: []string{"zizmor"}},
+		{Tool: "actionlint", RuleID: "expression", Level: "error", File: ".github/workflows/triage.lock.yml", Line: 4, Tools: []string{"actionlint"}},
+	}
+	fingerprints := []string{securityFindingFingerprint(findings[0], files), securityFindingFingerprint(findings[1], files)}
+
+	shifted := findings[0]
+	shifted.Line = 9
+	assert.NotEqual(t, fingerprints[0], securityFindingFingerprint(shifted, files), "fingerprint should depend on the source line text")
+
+	previous := &SecurityBaseline{Version: 1, Findings: []SecurityBaselineEntry{{Fingerprint: fingerprints[0], Justification: "tracked in #123"}}}
+	baseline := buildSecurityBaseline(findings, fingerprints, previous)
+	require.Len(t, baseline.Findings, 2, "all current findings should be accepted")
+	assert.Equal(t, "tracked in #123", baseline.Findings[1].Justification, "existing justification should be preserved")
+
+	report := buildSARIFReport(findings, fingerprints, previous)
+	results := report.Runs[0].Results
+	require.Len(t, results, 2, "every finding should produce a result")
+	assert.Equal(t, "zizmor/unpinned-uses", results[0].RuleID, "rule IDs should be prefixed with the tool")
+	assert.Equal(t, []sarifSuppression{{Kind: "external", Justification: "tracked in #123"}}, results[0].Suppressions, "baselined finding should be suppressed")
+	assert.Empty(t, results[1].Suppressions, "new finding should not be suppressed")
+	assert.Equal(t, fingerprints[1], results[1].PartialFingerprints[sarifFingerprintKey], "fingerprint should be exported")
+}
+
+func TestSecurityFindingCollectorAddCompilerDiagnostics(t *testing.T) {
+	root := t.TempDir()
+	t.Chdir(root)
+	collector := newSecurityFindingCollector(root)
+
+	collector.AddCompilerDiagnostics(".github/workflows/triage.md",
+		[]console.CompilerError{
+			{Type: "warning", Message: "unpositioned"},
+			{Type: "warning", Message: "positioned", Position: console.ErrorPosition{File: ".github/workflows/shared.md", Line: 3, Column: 1}},
+		},
+		[]CompileValidationError{{Type: "compilation_error", Message: "failed"}},
+	)
+
+	require.Len(t, collector.findings, 3, "warnings and errors should be recorded")
+	assert.Equal(t, SecurityFinding{Tool: "gh-aw", RuleID: "compiler-warning", Level: "warning", Message: "unpositioned", File: ".github/workflows/triage.md"}, collector.findings[0], "unpositioned warnings should be attributed to the workflow")
+	assert.Equal(t, ".github/workflows/shared.md", collector.findings[1].File, "the diagnostic's own file should be kept")
+	assert.Equal(t, 3, collector.findings[1].Line, "the diagnostic's line should be kept")
+	assert.Equal(t, "error", collector.findings[2].Level, "compile errors should be reported as errors")
+
+	var disabled *SecurityFindingCollector
+	disabled.Add(SecurityFinding{Tool: "zizmor"})
+	disabled.AddCompilerDiagnostics("triage.md", []console.CompilerError{{Type: "warning", Message: "ignored"}}, nil)
+}
@@ -92,22 +92,15 @@ type ModelTokenUsageRow struct {
 const tokenUsageJSONLPath = "api-proxy-logs/token-usage.jsonl"
 const agentUsageJSONPath = "agent_usage.json"
 
-// parseTokenUsageFile parses a token-usage.jsonl file and returns the aggregated summary.
-// Custom weights, when non-nil, override the built-in model multipliers and token class
-// weights for effective token computation.
-func parseTokenUsageFile(filePath string, customWeights *types.TokenWeights) (*TokenUsageSummary, error) {
-	tokenUsageLog.Printf("Parsing token usage file: %s", filePath)
-
+// readTokenUsageEntries reads every valid entry from a token-usage.jsonl file in file order.
+// Invalid JSON lines are skipped.
+func readTokenUsageEntries(filePath string) ([]TokenUsageEntry, error) {
 	file, err := os.Open(filePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open token usage file: %w", err)
 	}
 	defer file.Close()
 
-	summary := &TokenUsageSummary{
-		ByModel: make(map[string]*ModelTokenUsage),
-	}
-
 	scanner := bufio.NewScanner(file)
 	// Increase buffer size for potentially large lines
 	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
@@ -133,6 +126,24 @@ func parseTokenUsageFile(filePath string, customWeights *types.TokenWeights) (*T
 		return nil, fmt.Errorf("error reading token usage file: %w", err)
 	}
 
+	return entries, nil
+}
+
+// parseTokenUsageFile parses a token-usage.jsonl file and returns the aggregated summary.
+// Custom weights, when non-nil, override the built-in model multipliers and token class
+// weights for effective token computation.
+func parseTokenUsageFile(filePath string, customWeights *types.TokenWeights) (*TokenUsageSummary, error) {
+	tokenUsageLog.Printf("Parsing token usage file: %s", filePath)
+
+	entries, err := readTokenUsageEntries(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &TokenUsageSummary{
+		ByModel: make(map[string]*ModelTokenUsage),
+	}
+
 	if len(entries) == 0 {
 		tokenUsageLog.Print("No token usage entries found")
 		return nil, nil
@@ -169,7 +180,7 @@ func parseTokenUsageFile(filePath string, customWeights *types.TokenWeights) (*T
 	}
 
 	tokenUsageLog.Printf("Parsed %d entries: %d input, %d output, %d cache_read, %d cache_write, %d requests",
-		lineNum, summary.TotalInputTokens, summary.TotalOutputTokens,
+		len(entries), summary.TotalInputTokens, summary.TotalOutputTokens,
 		summary.TotalCacheReadTokens, summary.TotalCacheWriteTokens, summary.TotalRequests)
 
 	// Compute effective tokens using per-model multipliers (with optional custom overrides)
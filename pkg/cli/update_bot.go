@@ -0,0 +1,699 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/constants"
+	"github.com/github/gh-aw/pkg/gitutil"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/stringutil"
+	"github.com/github/gh-aw/pkg/workflow"
+)
+
+var updateBotLog = logger.New("cli:update_bot")
+
+// updateBotBranchPrefix prefixes the branches of bot pull requests; the group
+// name and a random suffix follow.
+const updateBotBranchPrefix = "gh-aw-update/"
+
+// updateBotPRTitlePrefix starts the titles of bot pull requests so that they
+// can be found again to apply the schedule
+const updateBotPRTitlePrefix = "Update agentic workflows:"
+
+// maxUpdateBotPRBodyLength keeps pull request bodies below the GitHub limit
+const maxUpdateBotPRBodyLength = 60000
+
+// Outcomes of an update group
+const (
+	botGroupOpened   = "opened"
+	botGroupUpToDate = "up to date"
+	botGroupWaiting  = "waiting"
+	botGroupFailed   = "failed"
+)
+
+// UpdateBotOptions configures a non-interactive update bot run.
+type UpdateBotOptions struct {
+	// Kinds restricts the run to these kinds of update; empty runs all kinds.
+	Kinds          []workflow.UpdateKind
+	WorkflowsDir   string
+	EngineOverride string
+	CoolDown       time.Duration
+	Verbose        bool
+}
+
+// botUpdateChange is a single entry of a group changelog
+type botUpdateChange struct {
+	Kind   workflow.UpdateKind
+	Name   string
+	From   string
+	To     string
+	Detail string
+}
+
+// botGroupResult is the outcome of one update group
+type botGroupResult struct {
+	Group     string
+	Status    string
+	Reason    string
+	PRURL     string
+	Changes   []botUpdateChange
+	HeldBack  []botUpdateChange
+	Conflicts []updateFailure
+	Failures  []updateFailure
+}
+
+// botPullRequest is a pull request previously opened by the update bot
+type botPullRequest struct {
+	Number      int       `json:"number"`
+	URL         string    `json:"url"`
+	State       string    `json:"state"`
+	CreatedAt   time.Time `json:"createdAt"`
+	HeadRefName string    `json:"headRefName"`
+}
+
+// RunUpdateBot applies the update policy from aw.json without interaction and
+// opens one pull request per due update group. Workflows whose local changes
+// conflict with upstream and versions outside the semver range are skipped
+// and reported instead.
+func RunUpdateBot(ctx context.Context, opts UpdateBotOptions) error {
+	updateBotLog.Printf("Running update bot: kinds=%v, dir=%s", opts.Kinds, opts.WorkflowsDir)
+
+	if !isGHCLIAvailable() {
+		return errors.New("GitHub CLI (gh) is required for --bot but not available")
+	}
+	// Groups are applied one after another on the current branch
+	if err := checkCleanWorkingDirectory(opts.Verbose); err != nil {
+		return fmt.Errorf("--bot requires a clean working directory: %w", err)
+	}
+	gitRoot, err := gitutil.FindGitRoot()
+	if err != nil {
+		return fmt.Errorf("--bot must be run inside a git repository: %w", err)
+	}
+	repoConfig, err := workflow.LoadRepoConfig(gitRoot)
+	if err != nil {
+		return err
+	}
+	policy := repoConfig.UpdatePolicy()
+
+	workflowsDir := opts.WorkflowsDir
+	if workflowsDir == "" {
+		workflowsDir = getWorkflowsDir()
+	}
+
+	pullRequests, err := listUpdateBotPullRequests()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	var results []*botGroupResult
+	for _, group := range policy.EffectiveGroups() {
+		group.Updates = botKindsToRun(group, opts.Kinds)
+		if len(group.Updates) == 0 {
+			continue
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		interval := workflow.UpdateScheduleInterval(policy.ScheduleFor(group))
+		if reason := updateGroupWaitReason(group.Name, interval, pullRequests, now); reason != "" {
+			updateBotLog.Printf("Group %s is not due: %s", group.Name, reason)
+			results = append(results, &botGroupResult{Group: group.Name, Status: botGroupWaiting, Reason: reason})
+			continue
+		}
+
+		fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("Updating group %s (%s)", group.Name, joinUpdateKinds(group.Updates))))
+		result := applyUpdateGroup(ctx, policy, group, workflowsDir, opts)
+		openUpdateGroupPR(gitRoot, workflowsDir, result, opts.Verbose)
+		// Leave the base branch clean for the next group
+		if hasWorkingTreeChanges() {
+			if err := discardWorkingTreeChanges(); err != nil {
+				return err
+			}
+		}
+		results = append(results, result)
+	}
+
+	displayUpdateBotResults(results)
+
+	for _, result := range results {
+		if result.Status == botGroupFailed {
+			return errors.New("update bot failed for one or more groups")
+		}
+	}
+	return nil
+}
+
+// botKindsToRun returns the kinds of update of the group that this run handles
+func botKindsToRun(group workflow.UpdateGroupConfig, only []workflow.UpdateKind) []workflow.UpdateKind {
+	if len(only) == 0 {
+		return group.Updates
+	}
+	var kinds []workflow.UpdateKind
+	for _, kind := range group.Updates {
+		if slices.Contains(only, kind) {
+			kinds = append(kinds, kind)
+		}
+	}
+	return kinds
+}
+
+// updateGroupWaitReason explains why a group is not due yet, or returns an
+// empty string. A group waits while one of its pull requests is open and until
+// the schedule interval has passed since its last pull request.
+func updateGroupWaitReason(group string, interval time.Duration, pullRequests []botPullRequest, now time.Time) string {
+	var last *botPullRequest
+	for i, pr := range pullRequests {
+		if !isUpdateBotBranch(pr.HeadRefName, group) {
+			continue
+		}
+		if strings.EqualFold(pr.State, "open") {
+			return fmt.Sprintf("pull request #%d is still open", pr.Number)
+		}
+		if last == nil || pr.CreatedAt.After(last.CreatedAt) {
+			last = &pullRequests[i]
+		}
+	}
+	if last != nil {
+		if due := last.CreatedAt.Add(interval); now.Before(due) {
+			return fmt.Sprintf("last pull request #%d was opened %s; next update after %s", last.Number, last.CreatedAt.Format("2006-01-02"), due.Format("2006-01-02"))
+		}
+	}
+	return ""
+}
+
+// isUpdateBotBranch reports whether a branch was created for the group, which
+// CreatePRWithChanges names <prefix><group>-<number>
+func isUpdateBotBranch(branch, group string) bool {
+	suffix, ok := strings.CutPrefix(branch, updateBotBranchPrefix+group+"-")
+	if !ok || suffix == "" {
+		return false
+	}
+	return strings.Trim(suffix, "0123456789") == ""
+}
+
+// applyUpdateGroup applies the updates of one group to the working tree
+func applyUpdateGroup(ctx context.Context, policy *workflow.UpdatePolicyConfig, group workflow.UpdateGroupConfig, workflowsDir string, opts UpdateBotOptions) *botGroupResult {
+	result := &botGroupResult{Group: group.Name}
+	semver := policy.SemverFor(group)
+
+	workflowIDs, err := listWorkflowIDs(workflowsDir)
+	if err != nil {
+		result.Failures = append(result.Failures, updateFailure{Name: group.Name, Error: err.Error()})
+		return result
+	}
+
+	if group.Has(workflow.UpdateKindWorkflows) {
+		applyBotWorkflowUpdates(ctx, policy.WorkflowsFor(group, workflow.UpdateKindWorkflows, workflowIDs), semver, workflowsDir, opts, result)
+	}
+
+	recompileAll := false
+	if group.Has(workflow.UpdateKindActions) {
+		if err := applyBotActionUpdates(ctx, semver, workflowsDir, opts, result); err != nil {
+			result.Failures = append(result.Failures, updateFailure{Name: string(workflow.UpdateKindActions), Error: err.Error()})
+		}
+		recompileAll = true
+	}
+	if group.Has(workflow.UpdateKindContainers) {
+		if err := applyBotContainerUpdates(ctx, workflowsDir, opts, result); err != nil {
+			result.Failures = append(result.Failures, updateFailure{Name: string(workflow.UpdateKindContainers), Error: err.Error()})
+		}
+		recompileAll = true
+	}
+
+	var toCompile []string
+	if group.Has(workflow.UpdateKindCodemods) {
+		toCompile = applyBotCodemods(policy.WorkflowsFor(group, workflow.UpdateKindCodemods, workflowIDs), workflowsDir, opts, result)
+	}
+	if recompileAll {
+		// Pins are repository-wide, but excluded workflows keep their lock files
+		toCompile = nil
+		for _, id := range workflowIDs {
+			if !policy.IsExcluded(id) {
+				toCompile = append(toCompile, id)
+			}
+		}
+	}
+	for _, id := range toCompile {
+		markdownPath := filepath.Join(workflowsDir, id+".md")
+		if err := compileWorkflowWithRefresh(markdownPath, opts.Verbose, true, opts.EngineOverride, false); err != nil {
+			// Leave the workflow out of the pull request rather than ship a stale lock file
+			_ = restoreFromHEAD(markdownPath, stringutil.MarkdownToLockFile(markdownPath))
+			result.Failures = append(result.Failures, updateFailure{Name: id, Error: err.Error()})
+		}
+	}
+
+	updateBotLog.Printf("Group %s: changes=%d, heldBack=%d, conflicts=%d, failures=%d", group.Name, len(result.Changes), len(result.HeldBack), len(result.Conflicts), len(result.Failures))
+	return result
+}
+
+// applyBotWorkflowUpdates updates workflows from their sources one by one so
+// that conflicting and out-of-range updates can be left out of the group
+func applyBotWorkflowUpdates(ctx context.Context, ids []string, semver, workflowsDir string, opts UpdateBotOptions, result *botGroupResult) {
+	if len(ids) == 0 {
+		return
+	}
+	sources, err := findWorkflowsWithSource(workflowsDir, ids, opts.Verbose)
+	if err != nil {
+		result.Failures = append(result.Failures, updateFailure{Name: string(workflow.UpdateKindWorkflows), Error: err.Error()})
+		return
+	}
+
+	updateOpts := UpdateWorkflowsOptions{
+		AllowMajor:         semver == workflow.UpdateSemverMajor,
+		Verbose:            opts.Verbose,
+		EngineOverride:     opts.EngineOverride,
+		WorkflowsDir:       workflowsDir,
+		DisableReleaseBump: true,
+		CoolDown:           opts.CoolDown,
+		SkipConflicts:      true,
+	}
+	for _, wf := range sources {
+		files := []string{wf.Path, stringutil.MarkdownToLockFile(wf.Path)}
+		if err := updateWorkflow(ctx, wf, updateOpts); err != nil {
+			_ = restoreFromHEAD(files...)
+			if errors.Is(err, errUpdateConflict) {
+				result.Conflicts = append(result.Conflicts, updateFailure{Name: wf.Name, Error: err.Error()})
+			} else {
+				result.Failures = append(result.Failures, updateFailure{Name: wf.Name, Error: err.Error()})
+			}
+			continue
+		}
+
+		updated, err := findWorkflowsWithSource(workflowsDir, []string{wf.Name}, false)
+		if err != nil || len(updated) == 0 || updated[0].SourceSpec == wf.SourceSpec {
+			continue
+		}
+		change := botUpdateChange{Kind: workflow.UpdateKindWorkflows, Name: wf.Name, From: sourceSpecRef(wf.SourceSpec), To: sourceSpecRef(updated[0].SourceSpec)}
+		if !semverChangeAllowed(semver, change.From, change.To) {
+			_ = restoreFromHEAD(files...)
+			result.HeldBack = append(result.HeldBack, change)
+			continue
+		}
+		result.Changes = append(result.Changes, change)
+	}
+}
+
+// applyBotActionUpdates updates actions-lock.json and holds back pins that
+// move outside the semver range. Inline action references in workflow steps
+// cannot be held back individually, so they are only updated for the major
+// range.
+func applyBotActionUpdates(ctx context.Context, semver, workflowsDir string, opts UpdateBotOptions, result *botGroupResult) error {
+	before := workflow.NewActionCache(".")
+	if err := before.Load(); err != nil {
+		return fmt.Errorf("failed to parse actions lock file: %w", err)
+	}
+	allowMajor := semver == workflow.UpdateSemverMajor
+	if err := UpdateActions(ctx, allowMajor, opts.Verbose, !allowMajor, opts.CoolDown); err != nil {
+		return err
+	}
+
+	after := workflow.NewActionCache(".")
+	if err := after.Load(); err != nil {
+		return fmt.Errorf("failed to parse actions lock file: %w", err)
+	}
+	updated := maps.Clone(after.Entries)
+	heldBack := false
+	for _, change := range pairActionUpdates(before.Entries, updated) {
+		if semverChangeAllowed(semver, change.From, change.To) {
+			result.Changes = append(result.Changes, change)
+			continue
+		}
+		result.HeldBack = append(result.HeldBack, change)
+		heldBack = true
+		after.DeleteByKey(change.Name + "@" + change.To)
+		after.Entries[change.Name+"@"+change.From] = before.Entries[change.Name+"@"+change.From]
+	}
+	if !heldBack {
+		return updateBotInlineActionRefs(ctx, allowMajor, workflowsDir, opts)
+	}
+	// A held back version may share its target with an allowed change
+	for _, change := range result.Changes {
+		key := change.Name + "@" + change.To
+		if change.Kind == workflow.UpdateKindActions && updated[key].Repo != "" {
+			after.Entries[key] = updated[key]
+		}
+	}
+	if err := after.Save(); err != nil {
+		return fmt.Errorf("failed to save actions lock file: %w", err)
+	}
+	return updateBotInlineActionRefs(ctx, allowMajor, workflowsDir, opts)
+}
+
+// updateBotInlineActionRefs updates action references written in workflow steps
+func updateBotInlineActionRefs(ctx context.Context, allowMajor bool, workflowsDir string, opts UpdateBotOptions) error {
+	if !allowMajor {
+		return nil
+	}
+	return UpdateActionsInWorkflowFiles(ctx, workflowsDir, opts.EngineOverride, opts.Verbose, false, true, opts.CoolDown)
+}
+
+// applyBotContainerUpdates pins container images that are not pinned yet
+func applyBotContainerUpdates(ctx context.Context, workflowsDir string, opts UpdateBotOptions, result *botGroupResult) error {
+	before := workflow.NewActionCache(".")
+	if err := before.Load(); err != nil {
+		return fmt.Errorf("failed to parse actions lock file: %w", err)
+	}
+	if err := UpdateContainerPins(ctx, workflowsDir, opts.Verbose); err != nil {
+		return err
+	}
+	after := workflow.NewActionCache(".")
+	if err := after.Load(); err != nil {
+		return fmt.Errorf("failed to parse actions lock file: %w", err)
+	}
+	for _, image := range slices.Sorted(maps.Keys(after.ContainerPins)) {
+		pin := after.ContainerPins[image]
+		if old, ok := before.ContainerPins[image]; !ok || old.Digest != pin.Digest {
+			result.Changes = append(result.Changes, botUpdateChange{Kind: workflow.UpdateKindContainers, Name: image, From: before.ContainerPins[image].Digest, To: pin.Digest})
+		}
+	}
+	return nil
+}
+
+// applyBotCodemods applies codemods to the workflows of the group and returns
+// the workflows that changed
+func applyBotCodemods(ids []string, workflowsDir string, opts UpdateBotOptions, result *botGroupResult) []string {
+	codemods := GetAllCodemods()
+	var changed []string
+	for _, id := range ids {
+		applied, names, err := processWorkflowFileWithInfo(filepath.Join(workflowsDir, id+".md"), codemods, true, opts.Verbose)
+		if err != nil {
+			result.Failures = append(result.Failures, updateFailure{Name: id, Error: err.Error()})
+			continue
+		}
+		if applied {
+			changed = append(changed, id)
+			result.Changes = append(result.Changes, botUpdateChange{Kind: workflow.UpdateKindCodemods, Name: id, Detail: strings.Join(names, ", ")})
+		}
+	}
+	return changed
+}
+
+// pairActionUpdates matches the removed and added entries of each action
+// repository in actions-lock.json. Each removed version is paired with the
+// added version of the same major when there is one, otherwise with the
+// highest added version.
+func pairActionUpdates(before, after map[string]workflow.ActionCacheEntry) []botUpdateChange {
+	removed := make(map[string][]string)
+	added := make(map[string][]string)
+	for key, entry := range before {
+		if _, ok := after[key]; !ok {
+			removed[entry.Repo] = append(removed[entry.Repo], entry.Version)
+		}
+	}
+	for key, entry := range after {
+		if _, ok := before[key]; !ok {
+			added[entry.Repo] = append(added[entry.Repo], entry.Version)
+		} else if before[key].SHA != entry.SHA {
+			removed[entry.Repo] = append(removed[entry.Repo], entry.Version)
+			added[entry.Repo] = append(added[entry.Repo], entry.Version)
+		}
+	}
+
+	var changes []botUpdateChange
+	for _, repo := range slices.Sorted(maps.Keys(removed)) {
+		targets := added[repo]
+		if len(targets) == 0 {
+			continue
+		}
+		sort.Slice(targets, func(i, j int) bool {
+			vi, vj := parseVersion(targets[i]), parseVersion(targets[j])
+			if vi == nil || vj == nil {
+				return targets[i] > targets[j]
+			}
+			return vi.IsNewer(vj)
+		})
+		for _, from := range slices.Sorted(slices.Values(removed[repo])) {
+			to := targets[0]
+			if fromVer := parseVersion(from); fromVer != nil {
+				for _, target := range targets {
+					if targetVer := parseVersion(target); targetVer != nil && targetVer.Major == fromVer.Major {
+						to = target
+						break
+					}
+				}
+			}
+			changes = append(changes, botUpdateChange{Kind: workflow.UpdateKindActions, Name: repo, From: from, To: to})
+		}
+	}
+	return changes
+}
+
+// semverChangeAllowed reports whether a version change stays within the semver
+// range. Refs that are not semantic versions (branches, commit SHAs) are
+// always allowed.
+func semverChangeAllowed(semver, from, to string) bool {
+	fromVer, toVer := parseVersion(from), parseVersion(to)
+	if fromVer == nil || toVer == nil {
+		return true
+	}
+	switch semver {
+	case workflow.UpdateSemverPatch:
+		return fromVer.Major == toVer.Major && fromVer.Minor == toVer.Minor
+	case workflow.UpdateSemverMinor:
+		return fromVer.Major == toVer.Major
+	default:
+		return true
+	}
+}
+
+// openUpdateGroupPR opens the pull request of a group when it changed anything.
+// Updates that failed are left out of the pull request and listed in its body;
+// the group only fails when nothing else could be updated.
+func openUpdateGroupPR(gitRoot, workflowsDir string, result *botGroupResult, verbose bool) {
+	if !hasWorkingTreeChanges() {
+		result.Status = botGroupUpToDate
+		if len(result.Failures) > 0 {
+			result.Status = botGroupFailed
+		}
+		return
+	}
+
+	lockDir := workflowsDir
+	if rel, err := filepath.Rel(gitRoot, workflowsDir); err == nil && filepath.IsAbs(workflowsDir) {
+		lockDir = rel
+	}
+	var lockImpact string
+	if report, err := buildLockDiffReport(gitRoot, "HEAD", "", path.Clean(filepath.ToSlash(lockDir))); err == nil {
+		lockImpact = renderLockDiffMarkdown(report)
+	} else {
+		updateBotLog.Printf("Failed to build lock diff for group %s: %v", result.Group, err)
+	}
+
+	title := fmt.Sprintf("%s %s (%d change(s))", updateBotPRTitlePrefix, result.Group, len(result.Changes))
+	prURL, err := CreatePRWithChanges(updateBotBranchPrefix+result.Group, "chore: update agentic workflows ("+result.Group+")",
+		title, renderUpdateBotPRBody(result, lockImpact), verbose)
+	if err != nil {
+		result.Status = botGroupFailed
+		result.Failures = append(result.Failures, updateFailure{Name: result.Group, Error: err.Error()})
+		return
+	}
+	result.Status = botGroupOpened
+	result.PRURL = prURL
+}
+
+// renderUpdateBotPRBody renders the changelog and the lock file impact of a group
+func renderUpdateBotPRBody(result *botGroupResult, lockImpact string) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Automatic update of the **%s** group according to the update policy in `%s`.\n\n", result.Group, workflow.RepoConfigFileName)
+
+	sb.WriteString("## Changelog\n\n")
+	if len(result.Changes) == 0 {
+		sb.WriteString("Recompiled workflows without version changes.\n")
+	}
+	for _, kind := range workflow.UpdateKinds {
+		var rows []botUpdateChange
+		for _, change := range result.Changes {
+			if change.Kind == kind {
+				rows = append(rows, change)
+			}
+		}
+		if len(rows) == 0 {
+			continue
+		}
+		fmt.Fprintf(&sb, "### %s\n\n", updateKindTitle(kind))
+		for _, change := range rows {
+			sb.WriteString("- " + describeBotChange(change) + "\n")
+		}
+		sb.WriteString("\n")
+	}
+
+	if len(result.HeldBack) > 0 || len(result.Conflicts) > 0 || len(result.Failures) > 0 {
+		sb.WriteString("## Skipped\n\n")
+		for _, change := range result.HeldBack {
+			fmt.Fprintf(&sb, "- %s: outside the semver range\n", describeBotChange(change))
+		}
+		for _, conflict := range result.Conflicts {
+			fmt.Fprintf(&sb, "- `%s`: %s; run `%s update %s` to merge it manually\n", conflict.Name, conflict.Error, string(constants.CLIExtensionPrefix), conflict.Name)
+		}
+		for _, failure := range result.Failures {
+			fmt.Fprintf(&sb, "- `%s`: failed: %s\n", failure.Name, failure.Error)
+		}
+		sb.WriteString("\n")
+	}
+
+	if lockImpact != "" {
+		sb.WriteString(lockImpact)
+	}
+
+	body := sb.String()
+	if len(body) > maxUpdateBotPRBodyLength {
+		body = body[:maxUpdateBotPRBodyLength] + "\n\n_Truncated; run `" + string(constants.CLIExtensionPrefix) + " lock diff` for the full lock file impact._\n"
+	}
+	return body
+}
+
+func describeBotChange(change botUpdateChange) string {
+	switch {
+	case change.Detail != "":
+		return fmt.Sprintf("`%s`: %s", change.Name, change.Detail)
+	case change.From == "":
+		return fmt.Sprintf("`%s`: pinned to `%s`", change.Name, shortPin(change.To))
+	default:
+		return fmt.Sprintf("`%s`: `%s` → `%s`", change.Name, shortPin(change.From), shortPin(change.To))
+	}
+}
+
+func updateKindTitle(kind workflow.UpdateKind) string {
+	switch kind {
+	case workflow.UpdateKindWorkflows:
+		return "Workflows"
+	case workflow.UpdateKindActions:
+		return "Action pins"
+	case workflow.UpdateKindContainers:
+		return "Container pins"
+	default:
+		return "Codemods"
+	}
+}
+
+func joinUpdateKinds(kinds []workflow.UpdateKind) string {
+	names := make([]string, len(kinds))
+	for i, kind := range kinds {
+		names[i] = string(kind)
+	}
+	return strings.Join(names, ", ")
+}
+
+// displayUpdateBotResults prints one row per group and the skipped updates
+func displayUpdateBotResults(results []*botGroupResult) {
+	if len(results) == 0 {
+		fmt.Fprintln(os.Stderr, console.FormatInfoMessage("No update groups to run"))
+		return
+	}
+	rows := make([][]string, 0, len(results))
+	for _, result := range results {
+		details := result.Reason
+		switch result.Status {
+		case botGroupOpened:
+			details = result.PRURL
+		case botGroupFailed:
+			var names []string
+			for _, failure := range result.Failures {
+				names = append(names, failure.Name)
+			}
+			details = "failed: " + summarizeWorkflowList(names)
+		}
+		rows = append(rows, []string{result.Group, result.Status, fmt.Sprintf("%d", len(result.Changes)), fmt.Sprintf("%d", len(result.HeldBack)+len(result.Conflicts)), details})
+	}
+	fmt.Fprint(os.Stderr, console.RenderTable(console.TableConfig{
+		Title:   "Update Bot",
+		Headers: []string{"Group", "Status", "Changes", "Skipped", "Details"},
+		Rows:    rows,
+	}))
+
+	for _, result := range results {
+		for _, conflict := range result.Conflicts {
+			fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Skipped %s (%s): %s", conflict.Name, result.Group, conflict.Error)))
+		}
+		for _, change := range result.HeldBack {
+			fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("Held back %s %s → %s (%s): outside the semver range", change.Name, change.From, change.To, result.Group)))
+		}
+		for _, failure := range result.Failures {
+			fmt.Fprintln(os.Stderr, console.FormatErrorMessage(fmt.Sprintf("%s (%s): %s", failure.Name, result.Group, failure.Error)))
+		}
+	}
+}
+
+// listUpdateBotPullRequests returns the pull requests opened by the update bot
+func listUpdateBotPullRequests() ([]botPullRequest, error) {
+	output, err := workflow.RunGH("Listing update pull requests...", "pr", "list", "--state", "all", "--limit", "200",
+		"--search", `in:title "`+updateBotPRTitlePrefix+`"`, "--json", "number,url,state,createdAt,headRefName")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list update pull requests: %w", err)
+	}
+	var pullRequests []botPullRequest
+	if err := json.Unmarshal(output, &pullRequests); err != nil {
+		return nil, fmt.Errorf("failed to parse update pull requests: %w", err)
+	}
+	return pullRequests, nil
+}
+
+// listWorkflowIDs returns the IDs of the markdown workflows in the directory
+func listWorkflowIDs(workflowsDir string) ([]string, error) {
+	files, err := getMarkdownWorkflowFiles(workflowsDir)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(files))
+	for _, file := range files {
+		ids = append(ids, normalizeWorkflowID(file))
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// sourceSpecRef returns the ref of a source field, dropping any version comment
+func sourceSpecRef(source string) string {
+	_, ref, _ := strings.Cut(source, "@")
+	ref, _, _ = strings.Cut(ref, " ")
+	return ref
+}
+
+func hasWorkingTreeChanges() bool {
+	output, err := exec.Command("git", "status", "--porcelain").Output()
+	return err == nil && len(strings.TrimSpace(string(output))) > 0
+}
+
+// restoreFromHEAD restores files to their committed content and removes them
+// when they are not tracked
+func restoreFromHEAD(files ...string) error {
+	for _, file := range files {
+		if err := exec.Command("git", "ls-files", "--error-unmatch", "--", file).Run(); err != nil {
+			if removeErr := os.Remove(file); removeErr != nil && !os.IsNotExist(removeErr) {
+				return removeErr
+			}
+			continue
+		}
+		if output, err := exec.Command("git", "checkout", "HEAD", "--", file).CombinedOutput(); err != nil {
+			return fmt.Errorf("failed to restore %s: %s", file, strings.TrimSpace(string(output)))
+		}
+	}
+	return nil
+}
+
+// discardWorkingTreeChanges resets the working tree after a group. The bot
+// only runs on a clean working tree, so this only discards its own changes.
+func discardWorkingTreeChanges() error {
+	if output, err := exec.Command("git", "reset", "--hard", "HEAD").CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to reset working tree: %s", strings.TrimSpace(string(output)))
+	}
+	if output, err := exec.Command("git", "clean", "-fd").CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to clean working tree: %s", strings.TrimSpace(string(output)))
+	}
+	return nil
+}
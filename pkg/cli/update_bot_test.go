@@ -0,0 +1,106 @@
+//go:build !integration
+
+package cli
+
+import (
+	"testing"
+	"time"
+
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSemverChangeAllowed(t *testing.T) {
+	tests := []struct {
+		semver  string
+		from    string
+		to      string
+		allowed bool
+	}{
+		{semver: workflow.UpdateSemverPatch, from: "v1.2.3", to: "v1.2.4", allowed: true},
+		{semver: workflow.UpdateSemverPatch, from: "v1.2.3", to: "v1.3.0", allowed: false},
+		{semver: workflow.UpdateSemverMinor, from: "v1.2.3", to: "v1.3.0", allowed: true},
+		{semver: workflow.UpdateSemverMinor, from: "v1.2.3", to: "v2.0.0", allowed: false},
+		{semver: workflow.UpdateSemverMajor, from: "v1.2.3", to: "v2.0.0", allowed: true},
+		{semver: workflow.UpdateSemverPatch, from: "main", to: "main", allowed: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.semver+" "+tt.from+" to "+tt.to, func(t *testing.T) {
+			assert.Equal(t, tt.allowed, semverChangeAllowed(tt.semver, tt.from, tt.to))
+		})
+	}
+}
+
+func TestPairActionUpdates(t *testing.T) {
+	before := map[string]workflow.ActionCacheEntry{
+		"actions/checkout@v4":    {Repo: "actions/checkout", Version: "v4", SHA: "a"},
+		"actions/cache@v4.1.0":   {Repo: "actions/cache", Version: "v4.1.0", SHA: "b"},
+		"actions/cache@v3.0.0":   {Repo: "actions/cache", Version: "v3.0.0", SHA: "c"},
+		"actions/setup-go@v5":    {Repo: "actions/setup-go", Version: "v5", SHA: "d"},
+		"actions/unchanged@v1":   {Repo: "actions/unchanged", Version: "v1", SHA: "e"},
+		"actions/moved-tag@v2":   {Repo: "actions/moved-tag", Version: "v2", SHA: "f"},
+		"actions/not-upgraded@1": {Repo: "actions/not-upgraded", Version: "1", SHA: "g"},
+	}
+	after := map[string]workflow.ActionCacheEntry{
+		"actions/checkout@v5":    {Repo: "actions/checkout", Version: "v5", SHA: "a2"},
+		"actions/cache@v4.2.0":   {Repo: "actions/cache", Version: "v4.2.0", SHA: "b2"},
+		"actions/cache@v3.1.0":   {Repo: "actions/cache", Version: "v3.1.0", SHA: "c2"},
+		"actions/setup-go@v5":    {Repo: "actions/setup-go", Version: "v5", SHA: "d"},
+		"actions/unchanged@v1":   {Repo: "actions/unchanged", Version: "v1", SHA: "e"},
+		"actions/moved-tag@v2":   {Repo: "actions/moved-tag", Version: "v2", SHA: "f2"},
+		"actions/not-upgraded@1": {Repo: "actions/not-upgraded", Version: "1", SHA: "g"},
+	}
+
+	changes := pairActionUpdates(before, after)
+	assert.Equal(t, []botUpdateChange{
+		{Kind: workflow.UpdateKindActions, Name: "actions/cache", From: "v3.0.0", To: "v3.1.0"},
+		{Kind: workflow.UpdateKindActions, Name: "actions/cache", From: "v4.1.0", To: "v4.2.0"},
+		{Kind: workflow.UpdateKindActions, Name: "actions/checkout", From: "v4", To: "v5"},
+		{Kind: workflow.UpdateKindActions, Name: "actions/moved-tag", From: "v2", To: "v2"},
+	}, changes, "versions should be paired within their major version")
+}
+
+func TestUpdateGroupWaitReason(t *testing.T) {
+	now := time.Date(2026, 6, 10, 0, 0, 0, 0, time.UTC)
+	week := 7 * 24 * time.Hour
+	pullRequests := []botPullRequest{
+		{Number: 1, State: "MERGED", CreatedAt: now.AddDate(0, 0, -20), HeadRefName: "gh-aw-update/actions-1234"},
+		{Number: 2, State: "CLOSED", CreatedAt: now.AddDate(0, 0, -3), HeadRefName: "gh-aw-update/actions-5678"},
+		{Number: 3, State: "OPEN", CreatedAt: now.AddDate(0, 0, -30), HeadRefName: "gh-aw-update/codemods-1111"},
+		{Number: 4, State: "MERGED", CreatedAt: now.AddDate(0, 0, -1), HeadRefName: "gh-aw-update/actions-extra-2222"},
+	}
+
+	assert.Contains(t, updateGroupWaitReason("actions", week, pullRequests, now), "#2", "the last pull request should set the next update")
+	assert.Empty(t, updateGroupWaitReason("actions", 24*time.Hour, pullRequests, now), "a daily group should be due after three days")
+	assert.Contains(t, updateGroupWaitReason("codemods", week, pullRequests, now), "still open", "an open pull request should block the group")
+	assert.Empty(t, updateGroupWaitReason("workflows", week, pullRequests, now), "a group without pull requests should be due")
+}
+
+func TestRenderUpdateBotPRBody(t *testing.T) {
+	result := &botGroupResult{
+		Group: "dependencies",
+		Changes: []botUpdateChange{
+			{Kind: workflow.UpdateKindActions, Name: "actions/checkout", From: "v4", To: "v5"},
+			{Kind: workflow.UpdateKindContainers, Name: "node:lts", To: "sha256:0123456789abcdef0123"},
+			{Kind: workflow.UpdateKindCodemods, Name: "triage", Detail: "Timeout minutes migration"},
+		},
+		HeldBack:  []botUpdateChange{{Kind: workflow.UpdateKindActions, Name: "actions/cache", From: "v3", To: "v4"}},
+		Conflicts: []updateFailure{{Name: "docs", Error: "local modifications conflict with the upstream changes (v1.0.0 → v1.1.0)"}},
+	}
+
+	body := renderUpdateBotPRBody(result, "## Lock file changes\n")
+	require.Contains(t, body, "## Changelog")
+	assert.Contains(t, body, "### Action pins\n\n- `actions/checkout`: `v4` → `v5`")
+	assert.Contains(t, body, "### Container pins\n\n- `node:lts`: pinned to `sha256:0123456789ab`")
+	assert.Contains(t, body, "- `triage`: Timeout minutes migration")
+	assert.Contains(t, body, "- `actions/cache`: `v3` → `v4`: outside the semver range")
+	assert.Contains(t, body, "- `docs`: local modifications conflict")
+	assert.Contains(t, body, "## Lock file changes", "the lock file impact should be appended")
+}
+
+func TestSourceSpecRef(t *testing.T) {
+	assert.Equal(t, "v1.2.0", sourceSpecRef("githubnext/agentics/workflows/triage.md@v1.2.0"))
+	assert.Equal(t, "abc123", sourceSpecRef("githubnext/agentics/workflows/triage.md@abc123 # v1"))
+	assert.Empty(t, sourceSpecRef("githubnext/agentics/workflows/triage.md"))
+}
@@ -42,6 +42,12 @@ For workflow updates, it fetches the latest version based on the current ref:
 
 For extension updates, action updates, agent files, and codemods, use 'gh aw upgrade'.
 
+Use --bot to run without interaction, for example from a scheduled workflow. The
+bot applies the update policy in .github/workflows/aw.json: it groups workflow,
+action pin, container and codemod updates, opens one pull request per due group
+with a changelog and the lock file impact, and skips workflows whose local
+changes conflict with upstream or whose new version is outside the semver range.
+
 ` + WorkflowIDExplanation + `
 
 Examples:
@@ -57,6 +63,7 @@ Examples:
   ` + string(constants.CLIExtensionPrefix) + ` update --dir custom/workflows  # Update workflows in custom directory
   ` + string(constants.CLIExtensionPrefix) + ` update --repo owner/repo        # Update workflows in another repository
   ` + string(constants.CLIExtensionPrefix) + ` update --create-pull-request   # Update and open a pull request
+  ` + string(constants.CLIExtensionPrefix) + ` update --bot                   # Open one pull request per update group from aw.json
   ` + string(constants.CLIExtensionPrefix) + ` update --cool-down 0           # Disable cooldown and apply all pending releases immediately
   ` + string(constants.CLIExtensionPrefix) + ` update --cool-down 3d          # Apply a custom 3-day cooldown period`,
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -77,6 +84,7 @@ Examples:
 			createPR := createPRFlag || prFlagAlias
 			coolDownStr, _ := cmd.Flags().GetString("cool-down")
 			targetRepo, _ := cmd.Flags().GetString("repo")
+			bot, _ := cmd.Flags().GetBool("bot")
 
 			if err := validateEngine(engineOverride); err != nil {
 				return err
@@ -87,6 +95,18 @@ Examples:
 				return fmt.Errorf("invalid --cool-down value: %w", err)
 			}
 
+			if bot {
+				if len(args) > 0 {
+					return errors.New("--bot updates the workflows selected by the update policy in aw.json and does not accept workflow names")
+				}
+				return RunUpdateBot(cmd.Context(), UpdateBotOptions{
+					WorkflowsDir:   workflowDir,
+					EngineOverride: engineOverride,
+					CoolDown:       coolDown,
+					Verbose:        verbose,
+				})
+			}
+
 			if createPR && targetRepo == "" {
 				if err := PreflightCheckForCreatePR(verbose); err != nil {
 					return err
@@ -144,6 +164,9 @@ Examples:
 	cmd.Flags().Bool("pr", false, "Alias for --create-pull-request")
 	cmd.Flags().String("cool-down", "7d", "Cooldown period before applying a new release (e.g. 7d, 24h, 0 to disable). Does not apply to actions/* or github/* repositories")
 	_ = cmd.Flags().MarkHidden("pr") // Hide the short alias from help output
+	cmd.Flags().Bool("bot", false, "Apply the update policy from aw.json without interaction and open one pull request per update group")
+	cmd.MarkFlagsMutuallyExclusive("bot", "create-pull-request")
+	cmd.MarkFlagsMutuallyExclusive("bot", "repo")
 
 	// Register completions for update command
 	cmd.ValidArgsFunction = CompleteWorkflowNames
@@ -35,8 +35,14 @@ type UpdateWorkflowsOptions struct {
 	NoCompile              bool
 	NoRedirect             bool
 	CoolDown               time.Duration
+	// SkipConflicts leaves a workflow untouched instead of writing conflict
+	// markers when the 3-way merge with its local modifications conflicts.
+	SkipConflicts bool
 }
 
+// errUpdateConflict is returned for workflows skipped because of merge conflicts
+var errUpdateConflict = errors.New("local modifications conflict with the upstream changes")
+
 // UpdateWorkflows updates workflows from their source repositories
 func UpdateWorkflows(ctx context.Context, opts UpdateWorkflowsOptions) error {
 	updateLog.Printf("Scanning for workflows with source field: dir=%s, filter=%v, noMerge=%v, noCompile=%v, noRedirect=%v, disableSecurityScanner=%v, coolDown=%v", opts.WorkflowsDir, opts.WorkflowNames, opts.NoMerge, opts.NoCompile, opts.NoRedirect, opts.DisableSecurityScanner, opts.CoolDown)
@@ -534,6 +540,9 @@ func updateWorkflow(ctx context.Context, wf *workflowWithSource, opts UpdateWork
 
 		if hasConflicts {
 			updateLog.Printf("Merge conflicts detected in workflow: %s", wf.Name)
+			if opts.SkipConflicts {
+				return fmt.Errorf("%w (%s → %s)", errUpdateConflict, shortRef(currentRef), shortRef(latestRef))
+			}
 		}
 	} else {
 		// Override mode (default): replace local file with new content from source
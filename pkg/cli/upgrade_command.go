@@ -30,7 +30,7 @@ type UpgradeConfig struct {
 }
 
 // NewUpgradeCommand creates the upgrade command
-func NewUpgradeCommand() *cobra.Command {
+func NewUpgradeCommand(validateEngine func(string) error) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "upgrade",
 		Short: "Upgrade repository with latest agent files and apply codemods to all workflows",
@@ -57,12 +57,17 @@ The --audit flag skips the normal upgrade process.
 
 This command always upgrades all Markdown files in .github/workflows.
 
+Use --bot to run the action, container and codemod groups of the update policy in
+.github/workflows/aw.json without interaction; see 'gh aw update --help'. --engine
+and --cool-down apply to --bot runs only.
+
 Examples:
   ` + string(constants.CLIExtensionPrefix) + ` upgrade                    # Upgrade all workflows
   ` + string(constants.CLIExtensionPrefix) + ` upgrade --no-fix          # Update agent files only (skip codemods, actions, and compilation)
   ` + string(constants.CLIExtensionPrefix) + ` upgrade --no-actions      # Skip updating GitHub Actions versions
   ` + string(constants.CLIExtensionPrefix) + ` upgrade --no-compile      # Skip recompiling workflows (do not modify lock files)
   ` + string(constants.CLIExtensionPrefix) + ` upgrade --create-pull-request  # Upgrade and open a pull request
+  ` + string(constants.CLIExtensionPrefix) + ` upgrade --bot             # Open one pull request per update group from aw.json
   ` + string(constants.CLIExtensionPrefix) + ` upgrade --dir custom/workflows  # Upgrade workflows in custom directory
   ` + string(constants.CLIExtensionPrefix) + ` upgrade --audit           # Check dependency health without upgrading
   ` + string(constants.CLIExtensionPrefix) + ` upgrade --audit --json    # Output audit results in JSON format
@@ -82,12 +87,40 @@ Examples:
 			skipExtensionUpgrade, _ := cmd.Flags().GetBool("skip-extension-upgrade")
 			approveUpgrade, _ := cmd.Flags().GetBool("approve")
 			preReleases, _ := cmd.Flags().GetBool("pre-releases")
+			bot, _ := cmd.Flags().GetBool("bot")
+			engineOverride, _ := cmd.Flags().GetString("engine")
+			coolDownStr, _ := cmd.Flags().GetString("cool-down")
+
+			if !bot {
+				for _, flag := range []string{"engine", "cool-down"} {
+					if cmd.Flags().Changed(flag) {
+						return fmt.Errorf("--%s can only be used with --bot", flag)
+					}
+				}
+			}
 
 			// Handle audit mode
 			if auditFlag {
 				return runDependencyAudit(cmd.Context(), verbose, jsonOutput)
 			}
 
+			if bot {
+				if err := validateEngine(engineOverride); err != nil {
+					return err
+				}
+				coolDown, err := parseCoolDownFlag(coolDownStr)
+				if err != nil {
+					return fmt.Errorf("invalid --cool-down value: %w", err)
+				}
+				return RunUpdateBot(cmd.Context(), UpdateBotOptions{
+					Kinds:          []workflow.UpdateKind{workflow.UpdateKindActions, workflow.UpdateKindContainers, workflow.UpdateKindCodemods},
+					WorkflowsDir:   dir,
+					EngineOverride: engineOverride,
+					CoolDown:       coolDown,
+					Verbose:        verbose,
+				})
+			}
+
 			if createPR {
 				if err := PreflightCheckForCreatePR(verbose); err != nil {
 					return err
@@ -121,9 +154,15 @@ Examples:
 	cmd.Flags().Bool("approve", false, "Approve all safe update changes. When strict mode is active (the default), the compiler emits warnings for new restricted secrets or unapproved action additions/removals not present in the existing gh-aw-manifest. Use this flag to approve and skip safe update enforcement")
 	cmd.Flags().Bool("skip-extension-upgrade", false, "Skip automatic extension upgrade (used internally to prevent recursion after upgrade)")
 	_ = cmd.Flags().MarkHidden("skip-extension-upgrade")
+	cmd.Flags().Bool("bot", false, "Apply the action, container and codemod groups of the update policy in aw.json without interaction and open one pull request per group")
+	cmd.Flags().StringP("engine", "e", "", engineFlagUsage("Override AI engine when recompiling workflows with --bot"))
+	cmd.Flags().String("cool-down", "7d", "Cooldown period before applying a new release with --bot (e.g. 7d, 24h, 0 to disable). Does not apply to actions/* or github/* repositories")
 	addJSONFlag(cmd)
+	cmd.MarkFlagsMutuallyExclusive("bot", "create-pull-request")
+	cmd.MarkFlagsMutuallyExclusive("bot", "audit")
 
 	// Register completions
+	RegisterEngineFlagCompletion(cmd)
 	RegisterDirFlagCompletion(cmd, "dir")
 
 	return cmd
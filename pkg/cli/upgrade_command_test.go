@@ -3,6 +3,7 @@
 package cli
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -10,7 +11,7 @@ import (
 )
 
 func TestUpgradeCommandHelpTextConsistency(t *testing.T) {
-	cmd := NewUpgradeCommand()
+	cmd := NewUpgradeCommand(validateEngineStub)
 	require.NotNil(t, cmd, "upgrade command should be created")
 
 	assert.Contains(t, cmd.Long, "Upgrade the repository to the latest version of agentic workflows.", "long description should use correct grammar")
@@ -23,3 +24,27 @@ func TestUpgradeCommandHelpTextConsistency(t *testing.T) {
 	require.NotNil(t, preReleasesFlag, "--pre-releases flag should exist")
 	assert.Contains(t, preReleasesFlag.Usage, "Include pre-release versions", "--pre-releases description should mention pre-release upgrades")
 }
+
+func TestUpgradeCommandBotOnlyFlags(t *testing.T) {
+	for _, args := range [][]string{{"--engine", "claude"}, {"--cool-down", "3d"}} {
+		cmd := NewUpgradeCommand(validateEngineStub)
+		cmd.SetArgs(args)
+		cmd.SilenceUsage = true
+		cmd.SilenceErrors = true
+		err := cmd.Execute()
+		require.Error(t, err, "%s should be rejected without --bot", args[0])
+		assert.Contains(t, err.Error(), "can only be used with --bot")
+	}
+}
+
+func TestUpgradeCommandBotValidatesEngine(t *testing.T) {
+	cmd := NewUpgradeCommand(func(engine string) error {
+		return errors.New("invalid engine: " + engine)
+	})
+	cmd.SetArgs([]string{"--bot", "--engine", "cluade"})
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+	err := cmd.Execute()
+	require.Error(t, err, "an invalid --engine should be rejected before the bot runs")
+	assert.Contains(t, err.Error(), "invalid engine: cluade")
+}
@@ -0,0 +1,486 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/github/gh-aw/pkg/constants"
+	"github.com/github/gh-aw/pkg/gitutil"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/semverutil"
+	"github.com/github/gh-aw/pkg/workflow"
+)
+
+var verifyPinsLog = logger.New("cli:verify_pins")
+
+// Pin kinds
+const (
+	pinKindAction    = "action"
+	pinKindContainer = "container"
+)
+
+// Pin verification checks
+const (
+	pinCheckTag        = "tag"
+	pinCheckHistory    = "history"
+	pinCheckAge        = "age"
+	pinCheckAdvisory   = "advisory"
+	pinCheckDigest     = "digest"
+	pinCheckUnverified = "unverified"
+)
+
+// Pin finding severities
+const (
+	pinSeverityError   = "error"
+	pinSeverityWarning = "warning"
+)
+
+// errNotInSnapshot is returned by the snapshot source for pins it has no record of
+var errNotInSnapshot = errors.New("not in the pin snapshot")
+
+// CommitHistory answers questions about the commit history of an action repository.
+type CommitHistory interface {
+	// CommitInTagHistory reports whether sha is the commit of tag version in repo
+	// or one of its ancestors. Commits that only exist in a fork of the repository
+	// are not part of the history.
+	CommitInTagHistory(ctx context.Context, repo, version, sha string) (bool, error)
+	// CommitDate returns the committer date of sha in repo.
+	CommitDate(ctx context.Context, repo, sha string) (time.Time, error)
+}
+
+// ImageDigestResolver resolves the current digest of a container image tag.
+type ImageDigestResolver interface {
+	ResolveImageDigest(ctx context.Context, image string) (string, error)
+}
+
+// PinSource combines the lookups verify-pins needs. The online source queries
+// GitHub and the container registry; the snapshot source replays a recorded run.
+type PinSource interface {
+	workflow.SHAResolver
+	CommitHistory
+	ImageDigestResolver
+}
+
+// PinnedRef is a pinned action or container image found in the lock files
+type PinnedRef struct {
+	Kind      string   `json:"kind"`
+	Ref       string   `json:"ref"`     // owner/repo[/path] for actions, the image tag for containers
+	Version   string   `json:"version"` // version tag the action pin claims
+	Pinned    string   `json:"pinned"`  // commit SHA or image digest
+	Workflows []string `json:"workflows"`
+}
+
+// PinFinding is a problem found with a pinned reference
+type PinFinding struct {
+	PinnedRef
+	Check    string `json:"check"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+// PinVerificationReport is the result of verifying all pins
+type PinVerificationReport struct {
+	CheckedAt  time.Time    `json:"checked_at"`
+	Offline    bool         `json:"offline,omitempty"`
+	SnapshotAt *time.Time   `json:"snapshot_at,omitempty"`
+	Actions    int          `json:"actions"`
+	Containers int          `json:"containers"`
+	Advisories int          `json:"advisories"`
+	Findings   []PinFinding `json:"findings"`
+}
+
+// hasErrors reports whether any finding is an error
+func (r *PinVerificationReport) hasErrors() bool {
+	return slices.ContainsFunc(r.Findings, func(f PinFinding) bool { return f.Severity == pinSeverityError })
+}
+
+// PinVerifier checks pinned actions and container images
+type PinVerifier struct {
+	Source     PinSource
+	Advisories []ActionAdvisory
+	// MaxAge flags pins whose commit is older than this; zero disables the check.
+	MaxAge time.Duration
+	Now    time.Time
+}
+
+// collectPinnedRefs collects the pinned actions and containers from the
+// gh-aw-manifest of every lock file in dir
+func collectPinnedRefs(dir string) ([]PinnedRef, error) {
+	lockFiles, err := filepath.Glob(filepath.Join(dir, "*.lock.yml"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find lock files: %w", err)
+	}
+
+	byKey := make(map[string]*PinnedRef)
+	add := func(ref PinnedRef, workflowID string) {
+		key := ref.Kind + "|" + ref.Ref + "|" + ref.Version + "|" + ref.Pinned
+		existing, ok := byKey[key]
+		if !ok {
+			existing = &ref
+			byKey[key] = existing
+		}
+		if !slices.Contains(existing.Workflows, workflowID) {
+			existing.Workflows = append(existing.Workflows, workflowID)
+		}
+	}
+
+	for _, lockFile := range lockFiles {
+		content, err := os.ReadFile(lockFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", lockFile, err)
+		}
+		workflowID := normalizeWorkflowID(lockFile)
+		manifest, err := workflow.ExtractGHAWManifestFromLockFile(string(content))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", lockFile, err)
+		}
+		if manifest == nil {
+			// Lock files compiled before the manifest existed
+			actions, err := workflow.ExtractActionsFromLockContent(string(content), lockFile)
+			if err != nil {
+				verifyPinsLog.Printf("Skipping %s: %v", lockFile, err)
+				continue
+			}
+			for _, action := range actions {
+				add(PinnedRef{Kind: pinKindAction, Ref: action.Repo, Version: action.Version, Pinned: action.SHA}, workflowID)
+			}
+			continue
+		}
+		for _, action := range manifest.Actions {
+			add(PinnedRef{Kind: pinKindAction, Ref: action.Repo, Version: action.Version, Pinned: action.SHA}, workflowID)
+		}
+		for _, container := range manifest.Containers {
+			// Unpinned images have nothing to verify
+			if container.Digest != "" {
+				add(PinnedRef{Kind: pinKindContainer, Ref: container.Image, Pinned: container.Digest}, workflowID)
+			}
+		}
+	}
+
+	refs := make([]PinnedRef, 0, len(byKey))
+	for _, ref := range byKey {
+		sort.Strings(ref.Workflows)
+		refs = append(refs, *ref)
+	}
+	sort.Slice(refs, func(i, j int) bool {
+		if refs[i].Kind != refs[j].Kind {
+			return refs[i].Kind < refs[j].Kind
+		}
+		if refs[i].Ref != refs[j].Ref {
+			return refs[i].Ref < refs[j].Ref
+		}
+		return refs[i].Version < refs[j].Version
+	})
+	verifyPinsLog.Printf("Collected %d pinned references from %d lock files", len(refs), len(lockFiles))
+	return refs, nil
+}
+
+// Verify checks every pinned reference and returns the findings
+func (v *PinVerifier) Verify(ctx context.Context, refs []PinnedRef) []PinFinding {
+	findings := []PinFinding{}
+	for _, ref := range refs {
+		switch ref.Kind {
+		case pinKindAction:
+			findings = append(findings, v.verifyAction(ctx, ref)...)
+		case pinKindContainer:
+			findings = append(findings, v.verifyContainer(ctx, ref)...)
+		}
+	}
+	return findings
+}
+
+// verifyAction checks an action pin against its tag, the repository history,
+// the age policy and the advisory database
+func (v *PinVerifier) verifyAction(ctx context.Context, ref PinnedRef) []PinFinding {
+	finding := func(check, severity, message string) PinFinding {
+		return PinFinding{PinnedRef: ref, Check: check, Severity: severity, Message: message}
+	}
+	var findings []PinFinding
+
+	for _, advisory := range v.Advisories {
+		if advisory.affects(ref.Ref, ref.Version) {
+			findings = append(findings, finding(pinCheckAdvisory, pinSeverityError,
+				fmt.Sprintf("%s (%s): %s", advisory.ID(), advisory.Severity, advisory.Summary)))
+		}
+	}
+
+	if ref.Version == "" {
+		return append(findings, finding(pinCheckTag, pinSeverityWarning, "pin has no version comment, so the SHA cannot be checked against a tag"))
+	}
+
+	tagSHA, err := v.Source.ResolveSHA(ctx, ref.Ref, ref.Version)
+	switch {
+	case errors.Is(err, errNotInSnapshot):
+		return append(findings, finding(pinCheckUnverified, pinSeverityWarning, "tag is not in the pin snapshot; run verify-pins online with --update-snapshot"))
+	case err != nil:
+		return append(findings, finding(pinCheckTag, pinSeverityError, fmt.Sprintf("tag %s could not be resolved in %s: %v", ref.Version, gitutil.ExtractBaseRepo(ref.Ref), err)))
+	case tagSHA != ref.Pinned:
+		inHistory, err := v.Source.CommitInTagHistory(ctx, ref.Ref, ref.Version, ref.Pinned)
+		switch {
+		case err != nil:
+			findings = append(findings, finding(pinCheckUnverified, pinSeverityWarning,
+				fmt.Sprintf("tag %s now resolves to %s and the history could not be checked: %v", ref.Version, tagSHA, err)))
+		case inHistory:
+			findings = append(findings, finding(pinCheckTag, pinSeverityWarning,
+				fmt.Sprintf("tag %s has moved to %s; the pinned commit is an ancestor of the tag", ref.Version, tagSHA)))
+		default:
+			findings = append(findings, finding(pinCheckHistory, pinSeverityError,
+				fmt.Sprintf("pinned commit is not in the history of tag %s (%s); it may come from a fork of %s", ref.Version, tagSHA, gitutil.ExtractBaseRepo(ref.Ref))))
+			// Age of a commit outside the repository is meaningless
+			return findings
+		}
+	}
+
+	if v.MaxAge > 0 {
+		committed, err := v.Source.CommitDate(ctx, ref.Ref, ref.Pinned)
+		switch {
+		case err != nil:
+			verifyPinsLog.Printf("Could not get commit date of %s@%s: %v", ref.Ref, ref.Pinned, err)
+		case v.Now.Sub(committed) > v.MaxAge:
+			findings = append(findings, finding(pinCheckAge, pinSeverityWarning,
+				fmt.Sprintf("pinned commit is %d days old, exceeding the %d day policy", int(v.Now.Sub(committed).Hours()/24), int(v.MaxAge.Hours()/24))))
+		}
+	}
+	return findings
+}
+
+// verifyContainer checks a container digest against the current digest of its tag
+func (v *PinVerifier) verifyContainer(ctx context.Context, ref PinnedRef) []PinFinding {
+	digest, err := v.Source.ResolveImageDigest(ctx, ref.Ref)
+	switch {
+	case errors.Is(err, errNotInSnapshot):
+		return []PinFinding{{PinnedRef: ref, Check: pinCheckUnverified, Severity: pinSeverityWarning, Message: "image is not in the pin snapshot; run verify-pins online with --update-snapshot"}}
+	case err != nil:
+		return []PinFinding{{PinnedRef: ref, Check: pinCheckUnverified, Severity: pinSeverityWarning, Message: fmt.Sprintf("digest could not be resolved: %v", err)}}
+	case digest != ref.Pinned:
+		return []PinFinding{{PinnedRef: ref, Check: pinCheckDigest, Severity: pinSeverityWarning, Message: fmt.Sprintf("tag now points to %s; run '%s update' to refresh container pins", digest, string(constants.CLIExtensionPrefix))}}
+	}
+	return nil
+}
+
+// ActionAdvisory is a security advisory in the format of the GitHub global
+// advisories API (GET /advisories?ecosystem=actions)
+type ActionAdvisory struct {
+	GHSAID          string                  `json:"ghsa_id"`
+	CVEID           string                  `json:"cve_id,omitempty"`
+	Summary         string                  `json:"summary"`
+	Severity        string                  `json:"severity"`
+	WithdrawnAt     *time.Time              `json:"withdrawn_at,omitempty"`
+	Vulnerabilities []AdvisoryVulnerability `json:"vulnerabilities"`
+}
+
+// AdvisoryVulnerability is an affected package and version range of an advisory
+type AdvisoryVulnerability struct {
+	Package struct {
+		Ecosystem string `json:"ecosystem"`
+		Name      string `json:"name"`
+	} `json:"package"`
+	VulnerableVersionRange string `json:"vulnerable_version_range"`
+}
+
+// ID returns the identifier shown for the advisory
+func (a ActionAdvisory) ID() string {
+	if a.GHSAID != "" {
+		return a.GHSAID
+	}
+	return a.CVEID
+}
+
+// affects reports whether the advisory applies to an action version
+func (a ActionAdvisory) affects(repo, version string) bool {
+	if a.WithdrawnAt != nil || version == "" {
+		return false
+	}
+	baseRepo := strings.ToLower(gitutil.ExtractBaseRepo(repo))
+	for _, vuln := range a.Vulnerabilities {
+		if vuln.Package.Ecosystem != "" && !strings.EqualFold(vuln.Package.Ecosystem, "actions") {
+			continue
+		}
+		name := strings.ToLower(vuln.Package.Name)
+		if name != baseRepo && name != strings.ToLower(repo) {
+			continue
+		}
+		if versionInRange(version, vuln.VulnerableVersionRange) {
+			return true
+		}
+	}
+	return false
+}
+
+// versionInRange reports whether version satisfies a comma-separated range such
+// as ">= 1.0.0, < 45.0.8". An empty range matches every version.
+func versionInRange(version, versionRange string) bool {
+	if !semverutil.IsValid(version) {
+		return false
+	}
+	for constraint := range strings.SplitSeq(versionRange, ",") {
+		constraint = strings.TrimSpace(constraint)
+		if constraint == "" {
+			continue
+		}
+		op := ""
+		for _, candidate := range []string{"<=", ">=", "<", ">", "="} {
+			if strings.HasPrefix(constraint, candidate) {
+				op = candidate
+				break
+			}
+		}
+		bound := strings.TrimSpace(strings.TrimPrefix(constraint, op))
+		result := semverutil.Compare(version, bound)
+		var ok bool
+		switch op {
+		case "<":
+			ok = result < 0
+		case "<=":
+			ok = result <= 0
+		case ">":
+			ok = result > 0
+		case ">=":
+			ok = result >= 0
+		default:
+			ok = result == 0
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// loadAdvisories reads a local advisory database. A missing file yields no advisories.
+func loadAdvisories(path string) ([]ActionAdvisory, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read advisory database: %w", err)
+	}
+	var advisories []ActionAdvisory
+	if err := json.Unmarshal(data, &advisories); err != nil {
+		return nil, fmt.Errorf("failed to parse advisory database %s: %w", path, err)
+	}
+	verifyPinsLog.Printf("Loaded %d advisories from %s", len(advisories), path)
+	return advisories, nil
+}
+
+// PinSnapshot records the answers of an online verification so that later runs
+// can verify pins offline
+type PinSnapshot struct {
+	CreatedAt   time.Time            `json:"created_at"`
+	Tags        map[string]string    `json:"tags"`         // repo@version -> commit SHA
+	History     map[string]bool      `json:"history"`      // repo@version@sha -> in tag history
+	CommitDates map[string]time.Time `json:"commit_dates"` // repo@sha -> committer date
+	Images      map[string]string    `json:"images"`       // image tag -> digest
+}
+
+// newPinSnapshot creates an empty snapshot
+func newPinSnapshot(now time.Time) *PinSnapshot {
+	return &PinSnapshot{
+		CreatedAt:   now,
+		Tags:        make(map[string]string),
+		History:     make(map[string]bool),
+		CommitDates: make(map[string]time.Time),
+		Images:      make(map[string]string),
+	}
+}
+
+// loadPinSnapshot reads a snapshot written by --update-snapshot
+func loadPinSnapshot(path string) (*PinSnapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pin snapshot: %w", err)
+	}
+	snapshot := newPinSnapshot(time.Time{})
+	if err := json.Unmarshal(data, snapshot); err != nil {
+		return nil, fmt.Errorf("failed to parse pin snapshot %s: %w", path, err)
+	}
+	return snapshot, nil
+}
+
+// save writes the snapshot as indented JSON
+func (s *PinSnapshot) save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal pin snapshot: %w", err)
+	}
+	return os.WriteFile(path, append(data, '\n'), constants.FilePermPublic)
+}
+
+// ResolveSHA implements PinSource from the snapshot
+func (s *PinSnapshot) ResolveSHA(_ context.Context, repo, version string) (string, error) {
+	if sha, ok := s.Tags[repo+"@"+version]; ok {
+		return sha, nil
+	}
+	return "", errNotInSnapshot
+}
+
+// CommitInTagHistory implements PinSource from the snapshot
+func (s *PinSnapshot) CommitInTagHistory(_ context.Context, repo, version, sha string) (bool, error) {
+	if inHistory, ok := s.History[repo+"@"+version+"@"+sha]; ok {
+		return inHistory, nil
+	}
+	return false, errNotInSnapshot
+}
+
+// CommitDate implements PinSource from the snapshot
+func (s *PinSnapshot) CommitDate(_ context.Context, repo, sha string) (time.Time, error) {
+	if date, ok := s.CommitDates[repo+"@"+sha]; ok {
+		return date, nil
+	}
+	return time.Time{}, errNotInSnapshot
+}
+
+// ResolveImageDigest implements PinSource from the snapshot
+func (s *PinSnapshot) ResolveImageDigest(_ context.Context, image string) (string, error) {
+	if digest, ok := s.Images[image]; ok {
+		return digest, nil
+	}
+	return "", errNotInSnapshot
+}
+
+// recordingPinSource records the answers of another source into a snapshot
+type recordingPinSource struct {
+	source   PinSource
+	snapshot *PinSnapshot
+}
+
+func (r *recordingPinSource) ResolveSHA(ctx context.Context, repo, version string) (string, error) {
+	sha, err := r.source.ResolveSHA(ctx, repo, version)
+	if err == nil {
+		r.snapshot.Tags[repo+"@"+version] = sha
+	}
+	return sha, err
+}
+
+func (r *recordingPinSource) CommitInTagHistory(ctx context.Context, repo, version, sha string) (bool, error) {
+	inHistory, err := r.source.CommitInTagHistory(ctx, repo, version, sha)
+	if err == nil {
+		r.snapshot.History[repo+"@"+version+"@"+sha] = inHistory
+	}
+	return inHistory, err
+}
+
+func (r *recordingPinSource) CommitDate(ctx context.Context, repo, sha string) (time.Time, error) {
+	date, err := r.source.CommitDate(ctx, repo, sha)
+	if err == nil {
+		r.snapshot.CommitDates[repo+"@"+sha] = date
+	}
+	return date, err
+}
+
+func (r *recordingPinSource) ResolveImageDigest(ctx context.Context, image string) (string, error) {
+	digest, err := r.source.ResolveImageDigest(ctx, image)
+	if err == nil {
+		r.snapshot.Images[image] = digest
+	}
+	return digest, err
+}
@@ -0,0 +1,305 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/constants"
+	"github.com/github/gh-aw/pkg/gitutil"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/spf13/cobra"
+)
+
+var verifyPinsCommandLog = logger.New("cli:verify_pins_command")
+
+// Default locations of the verify-pins inputs, relative to the repository root
+const (
+	defaultPinSnapshotPath = ".github/aw/pins-snapshot.json"
+	defaultAdvisoriesPath  = ".github/aw/advisories.json"
+	defaultPinMaxAge       = "365d"
+)
+
+// NewVerifyPinsCommand creates the verify-pins command
+func NewVerifyPinsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "verify-pins",
+		Short: "Verify pinned action SHAs and container digests in compiled workflows",
+		Long: `Verify the supply chain of every pinned action and container image recorded
+in the compiled lock files.
+
+Each action pin is checked against the tag in its version comment:
+
+  - the tag must still exist in the action repository
+  - when the tag has moved, the pinned commit must be an ancestor of the tag;
+    a commit outside the tag history may come from a fork of the repository
+  - the pinned commit must not be older than --max-age
+  - the version must not be affected by an advisory in the local advisory
+    database (a JSON array in the format of the GitHub advisories API,
+    default: ` + defaultAdvisoriesPath + `)
+
+Each container digest is checked against the current digest of its tag.
+
+Use --update-snapshot to record the answers of an online run, and --offline to
+verify against that snapshot without network access. Pins missing from the
+snapshot are reported as unverified.
+
+The command exits with an error when any check fails; tags that moved, old
+pins and unverified pins are reported as warnings.
+
+Examples:
+  ` + string(constants.CLIExtensionPrefix) + ` verify-pins                      # Verify online
+  ` + string(constants.CLIExtensionPrefix) + ` verify-pins --update-snapshot    # Verify and record a snapshot
+  ` + string(constants.CLIExtensionPrefix) + ` verify-pins --offline            # Verify from the snapshot
+  ` + string(constants.CLIExtensionPrefix) + ` verify-pins --max-age 180d       # Flag pins older than 180 days
+  ` + string(constants.CLIExtensionPrefix) + ` verify-pins --json               # Output findings as JSON`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir, _ := cmd.Flags().GetString("dir")
+			offline, _ := cmd.Flags().GetBool("offline")
+			updateSnapshot, _ := cmd.Flags().GetBool("update-snapshot")
+			snapshot, _ := cmd.Flags().GetString("snapshot")
+			advisories, _ := cmd.Flags().GetString("advisories")
+			maxAge, _ := cmd.Flags().GetString("max-age")
+			jsonOutput, _ := cmd.Flags().GetBool("json")
+			verbose, _ := cmd.Flags().GetBool("verbose")
+			return RunVerifyPins(cmd.Context(), VerifyPinsOptions{
+				Dir:            dir,
+				Offline:        offline,
+				UpdateSnapshot: updateSnapshot,
+				SnapshotPath:   snapshot,
+				AdvisoriesPath: advisories,
+				MaxAge:         maxAge,
+				JSONOutput:     jsonOutput,
+				Verbose:        verbose,
+			})
+		},
+	}
+
+	cmd.Flags().StringP("dir", "d", "", "Workflow directory (default: .github/workflows)")
+	cmd.Flags().Bool("offline", false, "Verify from the pin snapshot without network access")
+	cmd.Flags().Bool("update-snapshot", false, "Record the online results in the pin snapshot")
+	cmd.Flags().String("snapshot", "", "Pin snapshot file (default: "+defaultPinSnapshotPath+")")
+	cmd.Flags().String("advisories", "", "Advisory database file (default: "+defaultAdvisoriesPath+")")
+	cmd.Flags().String("max-age", defaultPinMaxAge, "Flag pins whose commit is older than this (e.g., '180d', '4320h'); 0 disables")
+	addJSONFlag(cmd)
+	RegisterDirFlagCompletion(cmd, "dir")
+	cmd.MarkFlagsMutuallyExclusive("offline", "update-snapshot")
+
+	return cmd
+}
+
+// VerifyPinsOptions holds the options of the verify-pins command
+type VerifyPinsOptions struct {
+	Dir            string
+	Offline        bool
+	UpdateSnapshot bool
+	SnapshotPath   string
+	AdvisoriesPath string
+	MaxAge         string
+	JSONOutput     bool
+	Verbose        bool
+}
+
+// RunVerifyPins verifies the pinned references and prints the report
+func RunVerifyPins(ctx context.Context, opts VerifyPinsOptions) error {
+	verifyPinsCommandLog.Printf("Running verify-pins: offline=%v, update-snapshot=%v", opts.Offline, opts.UpdateSnapshot)
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	gitRoot, err := gitutil.FindGitRoot()
+	if err != nil {
+		gitRoot = "."
+	}
+	dir := opts.Dir
+	if dir == "" {
+		dir = filepath.Join(gitRoot, constants.GetWorkflowDir())
+	}
+	snapshotPath := opts.SnapshotPath
+	if snapshotPath == "" {
+		snapshotPath = filepath.Join(gitRoot, defaultPinSnapshotPath)
+	}
+	advisoriesPath := opts.AdvisoriesPath
+	if advisoriesPath == "" {
+		advisoriesPath = filepath.Join(gitRoot, defaultAdvisoriesPath)
+	}
+
+	var maxAge time.Duration
+	if opts.MaxAge != "" && opts.MaxAge != "0" {
+		if maxAge, err = parseCoolDownFlag(opts.MaxAge); err != nil {
+			return fmt.Errorf("invalid --max-age: %w", err)
+		}
+	}
+
+	advisories, err := loadAdvisories(advisoriesPath)
+	if err != nil {
+		return err
+	}
+
+	refs, err := collectPinnedRefs(dir)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	report := &PinVerificationReport{CheckedAt: now, Offline: opts.Offline, Advisories: len(advisories)}
+	for _, ref := range refs {
+		if ref.Kind == pinKindAction {
+			report.Actions++
+		} else {
+			report.Containers++
+		}
+	}
+
+	var source PinSource
+	var recorder *recordingPinSource
+	if opts.Offline {
+		snapshot, err := loadPinSnapshot(snapshotPath)
+		if err != nil {
+			return fmt.Errorf("%w (run '%s verify-pins --update-snapshot' while online first)", err, string(constants.CLIExtensionPrefix))
+		}
+		report.SnapshotAt = &snapshot.CreatedAt
+		source = snapshot
+	} else {
+		source = newGitHubPinSource(opts.Verbose)
+		if opts.UpdateSnapshot {
+			recorder = &recordingPinSource{source: source, snapshot: newPinSnapshot(now)}
+			source = recorder
+		}
+	}
+
+	verifier := &PinVerifier{Source: source, Advisories: advisories, MaxAge: maxAge, Now: now}
+	var spinner *console.SpinnerWrapper
+	if !opts.Offline && !opts.JSONOutput {
+		spinner = console.NewSpinner(fmt.Sprintf("Verifying %d pinned references...", len(refs)))
+		spinner.Start()
+	}
+	report.Findings = verifier.Verify(ctx, refs)
+	if spinner != nil {
+		spinner.Stop()
+	}
+
+	if recorder != nil {
+		if err := recorder.snapshot.save(snapshotPath); err != nil {
+			return err
+		}
+		if !opts.JSONOutput {
+			fmt.Fprintln(os.Stderr, console.FormatSuccessMessage("Updated pin snapshot: "+console.ToRelativePath(snapshotPath)))
+		}
+	}
+
+	if opts.JSONOutput {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal pin verification report: %w", err)
+		}
+		fmt.Println(string(data))
+	} else {
+		displayPinVerificationReport(report)
+	}
+
+	if report.hasErrors() {
+		return errors.New("pin verification failed")
+	}
+	return nil
+}
+
+// displayPinVerificationReport prints the findings as a table
+func displayPinVerificationReport(report *PinVerificationReport) {
+	summary := fmt.Sprintf("Checked %d action pins and %d container pins", report.Actions, report.Containers)
+	if report.SnapshotAt != nil {
+		summary += " against the snapshot from " + report.SnapshotAt.Format("2006-01-02")
+	}
+	fmt.Fprintln(os.Stderr, console.FormatInfoMessage(summary))
+
+	if len(report.Findings) == 0 {
+		fmt.Fprintln(os.Stderr, console.FormatSuccessMessage("All pins verified"))
+		return
+	}
+
+	rows := make([][]string, 0, len(report.Findings))
+	for _, f := range report.Findings {
+		ref := f.Ref
+		if f.Version != "" {
+			ref += "@" + f.Version
+		}
+		rows = append(rows, []string{f.Severity, f.Check, ref, shortPin(f.Pinned), f.Message, summarizeWorkflowList(f.Workflows)})
+	}
+	fmt.Fprint(os.Stderr, console.RenderTable(console.TableConfig{
+		Title:   "Pin Verification",
+		Headers: []string{"Severity", "Check", "Reference", "Pinned", "Finding", "Workflows"},
+		Rows:    rows,
+	}))
+}
+
+// summarizeWorkflowList keeps the workflows column readable for widely used pins
+func summarizeWorkflowList(workflows []string) string {
+	if len(workflows) <= 3 {
+		return strings.Join(workflows, ", ")
+	}
+	return fmt.Sprintf("%s (+%d more)", strings.Join(workflows[:2], ", "), len(workflows)-2)
+}
+
+// shortPin shortens a commit SHA or image digest for display
+func shortPin(pin string) string {
+	digest, isDigest := strings.CutPrefix(pin, "sha256:")
+	if isDigest {
+		return "sha256:" + digest[:min(12, len(digest))]
+	}
+	return pin[:min(12, len(pin))]
+}
+
+// gitHubPinSource answers pin lookups with the GitHub API and the container registry
+type gitHubPinSource struct {
+	resolver *workflow.ActionResolver
+	verbose  bool
+}
+
+// newGitHubPinSource creates an online pin source. The resolver uses an empty
+// cache so tags are always resolved again instead of trusting actions-lock.json.
+func newGitHubPinSource(verbose bool) *gitHubPinSource {
+	return &gitHubPinSource{resolver: workflow.NewActionResolver(workflow.NewActionCache("")), verbose: verbose}
+}
+
+func (s *gitHubPinSource) ResolveSHA(ctx context.Context, repo, version string) (string, error) {
+	return s.resolver.ResolveSHA(ctx, repo, version)
+}
+
+// CommitInTagHistory compares the tag with the commit. GitHub resolves commits
+// from the whole fork network, but a commit that only exists in a fork has no
+// ancestry relation with the tag.
+func (s *gitHubPinSource) CommitInTagHistory(ctx context.Context, repo, version, sha string) (bool, error) {
+	baseRepo := gitutil.ExtractBaseRepo(repo)
+	out, err := workflow.ExecGHContext(ctx, "api", fmt.Sprintf("/repos/%s/compare/%s...%s", baseRepo, version, sha), "--jq", ".status").CombinedOutput()
+	if err != nil {
+		msg := string(out)
+		if strings.Contains(msg, "Not Found") || strings.Contains(msg, "No common ancestor") || strings.Contains(msg, "HTTP 404") {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to compare %s with %s: %s", version, sha, strings.TrimSpace(msg))
+	}
+	// "behind" means the pinned commit is an ancestor of the tag
+	status := strings.TrimSpace(string(out))
+	verifyPinsCommandLog.Printf("Compare %s %s...%s: %s", baseRepo, version, sha, status)
+	return status == "identical" || status == "behind", nil
+}
+
+func (s *gitHubPinSource) CommitDate(ctx context.Context, repo, sha string) (time.Time, error) {
+	out, err := workflow.ExecGHContext(ctx, "api", fmt.Sprintf("/repos/%s/commits/%s", gitutil.ExtractBaseRepo(repo), sha), "--jq", ".commit.committer.date").Output()
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to get commit %s: %w", sha, err)
+	}
+	return time.Parse(time.RFC3339, strings.TrimSpace(string(out)))
+}
+
+func (s *gitHubPinSource) ResolveImageDigest(ctx context.Context, image string) (string, error) {
+	return resolveContainerDigest(ctx, image, s.verbose)
+}
@@ -0,0 +1,154 @@
+//go:build !integration
+
+package cli
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollectPinnedRefs(t *testing.T) {
+	dir := t.TempDir()
+	manifest := `# gh-aw-manifest: {"version":1,"secrets":[],"actions":[{"repo":"actions/checkout","sha":"aaa","version":"v4"}],"containers":[{"image":"node:lts","digest":"sha256:111","pinned_image":"node:lts@sha256:111"},{"image":"alpine:3"}]}
+name: A
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.lock.yml"), []byte(manifest), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "b.lock.yml"), []byte(manifest), 0o600))
+
+	refs, err := collectPinnedRefs(dir)
+	require.NoError(t, err)
+	require.Len(t, refs, 2, "duplicate pins should be merged and unpinned images skipped")
+
+	assert.Equal(t, PinnedRef{Kind: pinKindAction, Ref: "actions/checkout", Version: "v4", Pinned: "aaa", Workflows: []string{"a", "b"}}, refs[0])
+	assert.Equal(t, PinnedRef{Kind: pinKindContainer, Ref: "node:lts", Pinned: "sha256:111", Workflows: []string{"a", "b"}}, refs[1])
+}
+
+func TestPinVerifier_Verify(t *testing.T) {
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	source := &PinSnapshot{
+		Tags: map[string]string{
+			"actions/checkout@v4":   "aaa",
+			"actions/cache@v4":      "new",
+			"evil/action@v1":        "good",
+			"actions/old@v1":        "old",
+			"actions/setup-node@v4": "bbb",
+		},
+		History: map[string]bool{
+			"actions/cache@v4@moved": true,
+			"evil/action@v1@forked":  false,
+		},
+		CommitDates: map[string]time.Time{
+			"actions/checkout@aaa": now.AddDate(0, -1, 0),
+			"actions/cache@moved":  now.AddDate(0, -2, 0),
+			"actions/old@old":      now.AddDate(-2, 0, 0),
+		},
+		Images: map[string]string{"node:lts": "sha256:222"},
+	}
+	verifier := &PinVerifier{Source: source, MaxAge: 365 * 24 * time.Hour, Now: now}
+
+	tests := []struct {
+		name     string
+		ref      PinnedRef
+		check    string
+		severity string
+	}{
+		{name: "verified pin", ref: PinnedRef{Kind: pinKindAction, Ref: "actions/checkout", Version: "v4", Pinned: "aaa"}},
+		{name: "moved tag", ref: PinnedRef{Kind: pinKindAction, Ref: "actions/cache", Version: "v4", Pinned: "moved"}, check: pinCheckTag, severity: pinSeverityWarning},
+		{name: "commit outside history", ref: PinnedRef{Kind: pinKindAction, Ref: "evil/action", Version: "v1", Pinned: "forked"}, check: pinCheckHistory, severity: pinSeverityError},
+		{name: "old pin", ref: PinnedRef{Kind: pinKindAction, Ref: "actions/old", Version: "v1", Pinned: "old"}, check: pinCheckAge, severity: pinSeverityWarning},
+		{name: "missing from snapshot", ref: PinnedRef{Kind: pinKindAction, Ref: "actions/unknown", Version: "v1", Pinned: "ccc"}, check: pinCheckUnverified, severity: pinSeverityWarning},
+		{name: "container digest changed", ref: PinnedRef{Kind: pinKindContainer, Ref: "node:lts", Pinned: "sha256:111"}, check: pinCheckDigest, severity: pinSeverityWarning},
+		{name: "container unchanged", ref: PinnedRef{Kind: pinKindContainer, Ref: "node:lts", Pinned: "sha256:222"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings := verifier.Verify(context.Background(), []PinnedRef{tt.ref})
+			if tt.check == "" {
+				assert.Empty(t, findings, "pin should verify cleanly")
+				return
+			}
+			require.Len(t, findings, 1, "pin should have one finding")
+			assert.Equal(t, tt.check, findings[0].Check)
+			assert.Equal(t, tt.severity, findings[0].Severity)
+		})
+	}
+}
+
+func TestPinVerifier_TagNotFound(t *testing.T) {
+	verifier := &PinVerifier{Source: &failingPinSource{err: errors.New("HTTP 404")}, Now: time.Now()}
+	findings := verifier.Verify(context.Background(), []PinnedRef{{Kind: pinKindAction, Ref: "actions/gone", Version: "v9", Pinned: "aaa"}})
+	require.Len(t, findings, 1)
+	assert.Equal(t, pinCheckTag, findings[0].Check)
+	assert.Equal(t, pinSeverityError, findings[0].Severity, "a missing tag should fail verification")
+}
+
+func TestActionAdvisory_Affects(t *testing.T) {
+	advisory := ActionAdvisory{GHSAID: "GHSA-xxxx", Severity: "high", Summary: "leaks secrets"}
+	advisory.Vulnerabilities = make([]AdvisoryVulnerability, 1)
+	advisory.Vulnerabilities[0].Package.Ecosystem = "actions"
+	advisory.Vulnerabilities[0].Package.Name = "tj-actions/changed-files"
+	advisory.Vulnerabilities[0].VulnerableVersionRange = ">= 1.0.0, < 45.0.8"
+
+	assert.True(t, advisory.affects("tj-actions/changed-files", "v45.0.7"), "version inside the range should match")
+	assert.True(t, advisory.affects("tj-actions/changed-files/sub", "v44"), "sub-path actions should match their repository")
+	assert.False(t, advisory.affects("tj-actions/changed-files", "v45.0.8"), "patched version should not match")
+	assert.False(t, advisory.affects("actions/checkout", "v4"), "other actions should not match")
+	assert.False(t, advisory.affects("tj-actions/changed-files", "main"), "branches cannot be compared")
+
+	withdrawn := time.Now()
+	advisory.WithdrawnAt = &withdrawn
+	assert.False(t, advisory.affects("tj-actions/changed-files", "v45.0.7"), "withdrawn advisories should be ignored")
+}
+
+func TestPinSnapshot_RoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pins-snapshot.json")
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	recorder := &recordingPinSource{
+		source: &PinSnapshot{
+			Tags:        map[string]string{"actions/checkout@v4": "aaa"},
+			CommitDates: map[string]time.Time{"actions/checkout@aaa": now},
+		},
+		snapshot: newPinSnapshot(now),
+	}
+	verifier := &PinVerifier{Source: recorder, MaxAge: time.Hour, Now: now}
+	verifier.Verify(context.Background(), []PinnedRef{{Kind: pinKindAction, Ref: "actions/checkout", Version: "v4", Pinned: "aaa"}})
+	require.NoError(t, recorder.snapshot.save(path))
+
+	loaded, err := loadPinSnapshot(path)
+	require.NoError(t, err)
+	sha, err := loaded.ResolveSHA(context.Background(), "actions/checkout", "v4")
+	require.NoError(t, err)
+	assert.Equal(t, "aaa", sha)
+	date, err := loaded.CommitDate(context.Background(), "actions/checkout", "aaa")
+	require.NoError(t, err)
+	assert.True(t, now.Equal(date), "commit date should be recorded")
+}
+
+// failingPinSource fails every lookup with the same error
+type failingPinSource struct {
+	err error
+}
+
+func (f *failingPinSource) ResolveSHA(context.Context, string, string) (string, error) {
+	return "", f.err
+}
+
+func (f *failingPinSource) CommitInTagHistory(context.Context, string, string, string) (bool, error) {
+	return false, f.err
+}
+
+func (f *failingPinSource) CommitDate(context.Context, string, string) (time.Time, error) {
+	return time.Time{}, f.err
+}
+
+func (f *failingPinSource) ResolveImageDigest(context.Context, string) (string, error) {
+	return "", f.err
+}
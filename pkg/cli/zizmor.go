@@ -47,7 +47,7 @@ type zizmorFinding struct {
 }
 
 // runZizmorOnFiles runs the zizmor security scanner on one or more .lock.yml files using Docker
-func runZizmorOnFiles(lockFiles []string, verbose bool, strict bool) error {
+func runZizmorOnFiles(lockFiles []string, verbose bool, strict bool, collector *SecurityFindingCollector) error {
 	if len(lockFiles) == 0 {
 		return nil
 	}
@@ -115,7 +115,7 @@ func runZizmorOnFiles(lockFiles []string, verbose bool, strict bool) error {
 	err = cmd.Run()
 
 	// Parse and reformat the output, get total warning count
-	totalWarnings, parseErr := parseAndDisplayZizmorOutput(stdout.String(), stderr.String(), verbose)
+	totalWarnings, parseErr := parseAndDisplayZizmorOutput(stdout.String(), stderr.String(), verbose, collector)
 	if parseErr != nil {
 		zizmorLog.Printf("Failed to parse zizmor output: %v", parseErr)
 		// Fall back to showing raw output
@@ -167,14 +167,14 @@ func runZizmorOnFiles(lockFiles []string, verbose bool, strict bool) error {
 
 // runZizmorOnFile runs the zizmor security scanner on a single .lock.yml file using Docker
 // This is a wrapper around runZizmorOnFiles for backward compatibility
-func runZizmorOnFile(lockFile string, verbose bool, strict bool) error {
+func runZizmorOnFile(lockFile string, verbose bool, strict bool, collector *SecurityFindingCollector) error {
 	zizmorLog.Printf("Running zizmor security scanner: file=%s, strict=%v", lockFile, strict)
-	return runZizmorOnFiles([]string{lockFile}, verbose, strict)
+	return runZizmorOnFiles([]string{lockFile}, verbose, strict, collector)
 }
 
 // parseAndDisplayZizmorOutput parses zizmor JSON output and displays it in the desired format
 // Returns the total number of warnings found
-func parseAndDisplayZizmorOutput(stdout, stderr string, verbose bool) (int, error) {
+func parseAndDisplayZizmorOutput(stdout, stderr string, verbose bool, collector *SecurityFindingCollector) (int, error) {
 	// Map findings to files for detailed display
 	fileFindings := make(map[string][]zizmorFinding)
 
@@ -290,6 +290,17 @@ func parseAndDisplayZizmorOutput(stdout, stderr string, verbose bool) (int, erro
 					Context: context,
 				}
 
+				collector.Add(SecurityFinding{
+					Tool:    "zizmor",
+					RuleID:  ident,
+					Level:   errorType,
+					Message: desc,
+					HelpURI: url,
+					File:    filePath,
+					Line:    lineNum,
+					Column:  colNum,
+				})
+
 				fmt.Fprint(os.Stderr, console.FormatError(compilerErr))
 			}
 		}
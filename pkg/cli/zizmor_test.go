@@ -264,7 +264,7 @@ func TestParseAndDisplayZizmorOutput(t *testing.T) {
 			r, w, _ := os.Pipe()
 			os.Stderr = w
 
-			warningCount, err := parseAndDisplayZizmorOutput(tt.stdout, tt.stderr, tt.verbose)
+			warningCount, err := parseAndDisplayZizmorOutput(tt.stdout, tt.stderr, tt.verbose, nil)
 
 			// Restore stderr
 			w.Close()
@@ -26,6 +26,9 @@ const (
 	CrushEngine EngineName = "crush"
 	// PiEngine is the Pi engine identifier (experimental)
 	PiEngine EngineName = "pi"
+	// ReplayEngine is the transcript replay engine identifier used for deterministic tests
+	// (experimental). It is not part of AgenticEngines because it is not offered to users.
+	ReplayEngine EngineName = "replay"
 
 	// DefaultEngine is the default agentic engine used when no engine is explicitly specified.
 	// Currently defaults to CopilotEngine.
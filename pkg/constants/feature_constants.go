@@ -80,4 +80,14 @@ const (
 	//	features:
 	//	  group-concurrency-queue: false
 	GroupConcurrencyQueueFeatureFlag FeatureFlag = "group-concurrency-queue"
+	// RepositoryRuntimesFeatureFlag enables runtime detection from the repository's own
+	// manifests (go.mod, package.json, pyproject.toml, Cargo.toml, global.json,
+	// .tool-versions, ...). Detected runtimes get pinned setup steps, dependency caches
+	// and ecosystem firewall domains; explicit runtimes: entries take precedence.
+	//
+	// Workflow frontmatter usage:
+	//
+	//	features:
+	//	  repository-runtimes: true
+	RepositoryRuntimesFeatureFlag FeatureFlag = "repository-runtimes"
 )
@@ -194,6 +194,10 @@ const CheckSkipRolesStepID StepID = "check_skip_roles"
 const CheckSkipBotsStepID StepID = "check_skip_bots"
 const CheckSkipIfCheckFailingStepID StepID = "check_skip_if_check_failing"
 
+// AgentBudgetStepID is the step ID for the activation step that waits for a slot
+// in the repository-wide agent concurrency budget configured in aw.json
+const AgentBudgetStepID StepID = "agent_budget"
+
 // PreActivationAppTokenStepID is the step ID for the unified GitHub App token mint step
 // emitted in the pre-activation job when on.github-app is configured alongside skip-if checks.
 const PreActivationAppTokenStepID StepID = "pre-activation-app-token"
@@ -154,5 +154,8 @@ const DefaultGoVersion Version = "1.25"
 // DefaultHaskellVersion is the default version of GHC for runtime setup
 const DefaultHaskellVersion Version = "9.10"
 
+// DefaultRustVersion is the default Rust toolchain for runtime setup
+const DefaultRustVersion Version = "stable"
+
 // DefaultDenoVersion is the default version of Deno for runtime setup
 const DefaultDenoVersion Version = "2.x"
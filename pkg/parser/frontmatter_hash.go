@@ -455,6 +455,85 @@ func processImportsTextBased(frontmatterText, baseDir string, visited map[string
 	return importedFiles, importedFrontmatterTexts, nil
 }
 
+// extractSubAgentsFromText extracts the specs listed under the top-level "sub-agents:"
+// key using simple text parsing.
+func extractSubAgentsFromText(frontmatterText string) []string {
+	var specs []string
+	inSubAgents := false
+
+	for line := range strings.SplitSeq(frontmatterText, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		// A new top-level key ends the list; unindented list items still belong to it
+		lineIndent := len(line) - len(strings.TrimLeft(line, " \t"))
+		if lineIndent == 0 && !strings.HasPrefix(trimmed, "-") {
+			inSubAgents = strings.HasPrefix(trimmed, "sub-agents:")
+			continue
+		}
+
+		if inSubAgents && strings.HasPrefix(trimmed, "-") {
+			item := strings.TrimSpace(trimmed[1:])
+			item = strings.Trim(item, `"'`)
+			if item != "" {
+				specs = append(specs, item)
+			}
+		}
+	}
+
+	return specs
+}
+
+// resolveSubAgentTextPath resolves a local sub-agent spec the way ResolveIncludePath does:
+// ".github/" and "/" prefixed specs are relative to the repository root, others to baseDir.
+func resolveSubAgentTextPath(spec, baseDir string) string {
+	githubFolder := baseDir
+	for !strings.HasSuffix(githubFolder, ".github") {
+		parent := filepath.Dir(githubFolder)
+		if parent == githubFolder || parent == "." || parent == "/" {
+			return filepath.Join(baseDir, spec)
+		}
+		githubFolder = parent
+	}
+
+	repoRoot := filepath.Dir(githubFolder)
+	specSlash := filepath.ToSlash(spec)
+	if strings.HasPrefix(specSlash, ".github/") {
+		return filepath.Join(repoRoot, spec)
+	}
+	if stripped, ok := strings.CutPrefix(specSlash, "/"); ok {
+		return filepath.Join(repoRoot, filepath.FromSlash(stripped))
+	}
+	return filepath.Join(baseDir, spec)
+}
+
+// processSubAgentsTextBased reads the local files listed under sub-agents: so that editing
+// a sub-agent invalidates the hash. Remote specs are pinned to a commit SHA, which is part
+// of the frontmatter text already, and files that cannot be read are skipped.
+// Returns one "spec\ncontent" entry per sub-agent, sorted.
+func processSubAgentsTextBased(frontmatterText, baseDir string, fileReader FileReader) []string {
+	specs := extractSubAgentsFromText(frontmatterText)
+	if len(specs) == 0 {
+		return nil
+	}
+
+	frontmatterHashLog.Printf("Processing %d sub-agent(s) text-based from baseDir=%s", len(specs), baseDir)
+
+	var entries []string
+	for _, spec := range specs {
+		content, err := fileReader(resolveSubAgentTextPath(spec, baseDir))
+		if err != nil {
+			// Skip remote specs and missing files (matches JavaScript behavior)
+			continue
+		}
+		entries = append(entries, spec+"\n"+normalizeFrontmatterText(string(content)))
+	}
+	sort.Strings(entries)
+	return entries
+}
+
 // computeFrontmatterHashTextBasedWithReader computes the hash using text-based approach with custom file reader.
 // When markdown is non-empty, it is included as the full body text in the canonical data (used for
 // inlined-imports mode where the entire body is compiled into the lock file).
@@ -496,6 +575,11 @@ func computeFrontmatterHashTextBasedWithReader(frontmatterText, markdown, baseDi
 		canonical["imported-frontmatters"] = strings.Join(normalizedImportedTexts, "\n---\n")
 	}
 
+	// Add the contents of local sub-agent files, which are compiled into the prompt
+	if subAgents := processSubAgentsTextBased(frontmatterText, baseDir, fileReader); len(subAgents) > 0 {
+		canonical["sub-agents"] = subAgents
+	}
+
 	// When inlined-imports is enabled, include the full markdown body so any content
 	// change invalidates the hash. Otherwise, include only relevant template expressions.
 	if markdown != "" {
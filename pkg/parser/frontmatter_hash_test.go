@@ -276,3 +276,50 @@ func TestFrontmatterHashInputSizeLimit(t *testing.T) {
 	require.Error(t, err, "Should return the same deterministic error on repeated calls")
 	require.EqualError(t, err, "frontmatter hash input exceeds 1048576 bytes after normalization")
 }
+
+func TestComputeFrontmatterHashFromFileWithReader_WithSubAgents(t *testing.T) {
+	mockFS := map[string]string{
+		"/repo/.github/workflows/workflow.md": `---
+engine: claude
+sub-agents:
+  - /.github/agents/reviewer.md
+  - shared/planner.md
+  - octo/agents/agents/remote.md@0123456789abcdef0123456789abcdef01234567
+---
+
+# Main Workflow`,
+		"/repo/.github/agents/reviewer.md":          "---\nname: reviewer\n---\nReview the change.",
+		"/repo/.github/workflows/shared/planner.md": "---\nname: planner\n---\nPlan the work.",
+	}
+	customReader := func(filePath string) ([]byte, error) {
+		content, exists := mockFS[filePath]
+		if !exists {
+			return nil, os.ErrNotExist
+		}
+		return []byte(content), nil
+	}
+
+	hash, err := ComputeFrontmatterHashFromFileWithReader("/repo/.github/workflows/workflow.md", nil, customReader)
+	require.NoError(t, err, "Should compute hash with sub-agents")
+
+	for _, path := range []string{"/repo/.github/agents/reviewer.md", "/repo/.github/workflows/shared/planner.md"} {
+		original := mockFS[path]
+		mockFS[path] = original + "\nBe thorough."
+		changed, err := ComputeFrontmatterHashFromFileWithReader("/repo/.github/workflows/workflow.md", nil, customReader)
+		require.NoError(t, err, "Should compute hash after editing %s", path)
+		assert.NotEqual(t, hash, changed, "Editing %s should change the hash", path)
+		mockFS[path] = original
+	}
+}
+
+func TestExtractSubAgentsFromText(t *testing.T) {
+	frontmatter := `engine: claude
+sub-agents:
+  - .github/agents/a.md
+  - "shared/b.md"
+tools:
+  - github
+`
+	assert.Equal(t, []string{".github/agents/a.md", "shared/b.md"}, extractSubAgentsFromText(frontmatter))
+	assert.Empty(t, extractSubAgentsFromText("engine: claude\n"), "no sub-agents key")
+}
@@ -76,7 +76,7 @@ func ExpandIncludesWithManifest(content, baseDir string, extractTools bool) (str
 	// Find the repo root by walking up from baseDir to the parent of the .github folder.
 	// This allows files outside baseDir (e.g. .github/shared/ when baseDir is .github/workflows/)
 	// to be recorded with a clean repo-root-relative path instead of an absolute path.
-	repoRoot := findGitHubRepoRoot(baseDir)
+	repoRoot := FindGitHubRepoRoot(baseDir)
 
 	// Convert visited map to slice of file paths (make them relative to baseDir if possible,
 	// falling back to repo-root-relative, and only as a last resort using the absolute path)
@@ -116,7 +116,7 @@ func ExpandIncludesWithManifest(content, baseDir string, extractTools bool) (str
 	return currentContent, includedFiles, nil
 }
 
-// findGitHubRepoRoot walks up the directory tree from dir to find the parent of the
+// FindGitHubRepoRoot walks up the directory tree from dir to find the parent of the
 // first ".github" directory encountered. It is used to compute repo-root-relative
 // paths for files that live in sibling .github/ subdirectories (e.g. .github/shared/)
 // so that the lock file Includes header shows ".github/shared/editorial.md" rather
@@ -124,7 +124,7 @@ func ExpandIncludesWithManifest(content, baseDir string, extractTools bool) (str
 //
 // Returns the repo root directory (the parent of ".github"), or "" if no ".github"
 // ancestor directory is found before reaching the filesystem root.
-func findGitHubRepoRoot(dir string) string {
+func FindGitHubRepoRoot(dir string) string {
 	current := filepath.Clean(dir)
 	for {
 		if filepath.Base(current) == ".github" {
@@ -165,7 +165,7 @@ func ExtractBodyLevelImportPaths(content, baseDir string) []BodyLevelImport {
 		return nil
 	}
 
-	repoRoot := findGitHubRepoRoot(baseDir)
+	repoRoot := FindGitHubRepoRoot(baseDir)
 
 	var results []BodyLevelImport
 	scanner := bufio.NewScanner(strings.NewReader(content))
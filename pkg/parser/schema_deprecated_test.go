@@ -140,3 +140,54 @@ func TestExtractReplacementFromDescription(t *testing.T) {
 		})
 	}
 }
+
+func TestExtractSchemaDeprecations(t *testing.T) {
+	schemaDoc := map[string]any{
+		"properties": map[string]any{
+			"servers": map[string]any{
+				"additionalProperties": map[string]any{"$ref": "#/$defs/server"},
+			},
+			"old": map[string]any{
+				"deprecated":              true,
+				"description":             "Deprecated: Use 'new' instead",
+				"x-deprecation-value-map": map[string]any{"yes": true},
+			},
+			"output": map[string]any{
+				"properties": map[string]any{
+					"task": map[string]any{
+						"oneOf": []any{
+							map[string]any{"type": "object", "deprecated": true, "description": "Use 'session' instead"},
+							map[string]any{"type": "null"},
+						},
+					},
+				},
+			},
+		},
+		"$defs": map[string]any{
+			"server": map[string]any{
+				"properties": map[string]any{
+					"network": map[string]any{"deprecated": true, "description": "No longer supported"},
+					"child":   map[string]any{"$ref": "#/$defs/server"},
+				},
+			},
+		},
+	}
+
+	got := extractSchemaDeprecations(schemaDoc)
+	want := []SchemaDeprecation{
+		{Path: "old", Replacement: "new"},
+		{Path: "output.task", Replacement: "session"},
+		{Path: "servers.*.network"}, // recursive $refs are only expanded once
+	}
+	if len(got) != len(want) {
+		t.Fatalf("extractSchemaDeprecations() returned %d deprecations, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i].Path != want[i].Path || got[i].Replacement != want[i].Replacement {
+			t.Errorf("deprecation %d = %s (%q), want %s (%q)", i, got[i].Path, got[i].Replacement, want[i].Path, want[i].Replacement)
+		}
+	}
+	if got[0].ValueMap["yes"] != true {
+		t.Errorf("value map of 'old' = %v, want yes: true", got[0].ValueMap)
+	}
+}
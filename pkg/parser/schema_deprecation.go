@@ -4,7 +4,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"regexp"
+	"slices"
 	"sort"
+	"strings"
 	"sync"
 
 	"github.com/github/gh-aw/pkg/logger"
@@ -19,6 +21,15 @@ type DeprecatedField struct {
 	Description string // Description from the schema
 }
 
+// SchemaDeprecation describes a deprecated property anywhere in the main workflow schema,
+// together with the metadata needed to migrate it automatically.
+type SchemaDeprecation struct {
+	Path        string         // Dotted frontmatter path; "*" matches any map key (e.g. "mcp-servers.*.network")
+	Replacement string         // Replacement from the description, as a dotted path relative to the deprecated field's parent
+	Description string         // Description from the schema
+	ValueMap    map[string]any // Optional x-deprecation-value-map: new value keyed by the old scalar value
+}
+
 // deprecatedFieldsCache caches the result of parsing the main workflow schema so that
 // the expensive 414KB JSON unmarshal is only performed once per process lifetime.
 // Both the result and any error are cached permanently: since mainWorkflowSchema is an
@@ -52,6 +63,127 @@ func GetMainWorkflowDeprecatedFields() ([]DeprecatedField, error) {
 	return deprecatedFieldsCache, deprecatedFieldsErr
 }
 
+var (
+	schemaDeprecationsOnce  sync.Once
+	schemaDeprecationsCache []SchemaDeprecation
+	schemaDeprecationsErr   error
+)
+
+// GetMainWorkflowSchemaDeprecations returns every deprecated property of the main workflow
+// schema, including nested ones reached through $ref, oneOf/anyOf/allOf and map values.
+// Unlike GetMainWorkflowDeprecatedFields, which only covers top-level fields, this is the
+// source used to derive codemods. The result is cached; callers must not modify it.
+func GetMainWorkflowSchemaDeprecations() ([]SchemaDeprecation, error) {
+	schemaDeprecationsOnce.Do(func() {
+		var schemaDoc map[string]any
+		if err := json.Unmarshal([]byte(mainWorkflowSchema), &schemaDoc); err != nil {
+			schemaDeprecationsErr = fmt.Errorf("failed to parse main workflow schema: %w", err)
+			return
+		}
+		schemaDeprecationsCache = extractSchemaDeprecations(schemaDoc)
+		schemaDeprecationLog.Printf("Found %d deprecated properties in main workflow schema", len(schemaDeprecationsCache))
+	})
+	return schemaDeprecationsCache, schemaDeprecationsErr
+}
+
+// extractSchemaDeprecations walks a schema document and collects its deprecated properties.
+// Array items are not traversed: deprecations inside list entries cannot be addressed by a path.
+func extractSchemaDeprecations(schemaDoc map[string]any) []SchemaDeprecation {
+	defs, _ := schemaDoc["$defs"].(map[string]any)
+	found := make(map[string]SchemaDeprecation)
+	activeRefs := make(map[string]bool)
+
+	var walk func(node map[string]any, path []string)
+	walk = func(node map[string]any, path []string) {
+		if ref, ok := node["$ref"].(string); ok {
+			name, isDef := strings.CutPrefix(ref, "#/$defs/")
+			if def, ok := defs[name].(map[string]any); isDef && ok && !activeRefs[name] {
+				activeRefs[name] = true
+				walk(def, path)
+				delete(activeRefs, name)
+			}
+		}
+		for _, keyword := range []string{"oneOf", "anyOf", "allOf"} {
+			branches, _ := node[keyword].([]any)
+			for _, branch := range branches {
+				if branchMap, ok := branch.(map[string]any); ok {
+					walk(branchMap, path)
+				}
+			}
+		}
+		if properties, ok := node["properties"].(map[string]any); ok {
+			for name, property := range properties {
+				propertyMap, ok := property.(map[string]any)
+				if !ok {
+					continue
+				}
+				propertyPath := append(slices.Clone(path), name)
+				if deprecation, ok := schemaPropertyDeprecation(propertyMap); ok {
+					deprecation.Path = strings.Join(propertyPath, ".")
+					if _, seen := found[deprecation.Path]; !seen {
+						found[deprecation.Path] = deprecation
+					}
+				}
+				walk(propertyMap, propertyPath)
+			}
+		}
+		// Map values are addressed with a "*" path segment
+		patternProperties, _ := node["patternProperties"].(map[string]any)
+		for _, valueSchema := range patternProperties {
+			if valueMap, ok := valueSchema.(map[string]any); ok {
+				walk(valueMap, append(slices.Clone(path), "*"))
+			}
+		}
+		if valueMap, ok := node["additionalProperties"].(map[string]any); ok {
+			walk(valueMap, append(slices.Clone(path), "*"))
+		}
+	}
+	walk(schemaDoc, nil)
+
+	deprecations := make([]SchemaDeprecation, 0, len(found))
+	for _, deprecation := range found {
+		deprecations = append(deprecations, deprecation)
+	}
+	sort.Slice(deprecations, func(i, j int) bool {
+		return deprecations[i].Path < deprecations[j].Path
+	})
+	return deprecations
+}
+
+// schemaPropertyDeprecation reports whether a property schema is deprecated, either directly
+// or through one of its oneOf/anyOf alternatives, and returns the deprecation metadata.
+func schemaPropertyDeprecation(property map[string]any) (SchemaDeprecation, bool) {
+	candidates := []map[string]any{property}
+	for _, keyword := range []string{"oneOf", "anyOf"} {
+		branches, _ := property[keyword].([]any)
+		for _, branch := range branches {
+			if branchMap, ok := branch.(map[string]any); ok {
+				candidates = append(candidates, branchMap)
+			}
+		}
+	}
+
+	for _, candidate := range candidates {
+		if isDeprecated, ok := candidate["deprecated"].(bool); !ok || !isDeprecated {
+			continue
+		}
+		description, _ := candidate["description"].(string)
+		if description == "" {
+			description, _ = property["description"].(string)
+		}
+		valueMap, _ := candidate["x-deprecation-value-map"].(map[string]any)
+		if valueMap == nil {
+			valueMap, _ = property["x-deprecation-value-map"].(map[string]any)
+		}
+		return SchemaDeprecation{
+			Replacement: extractReplacementFromDescription(description),
+			Description: description,
+			ValueMap:    valueMap,
+		}, true
+	}
+	return SchemaDeprecation{}, false
+}
+
 // extractDeprecatedFields extracts deprecated fields from a schema document
 func extractDeprecatedFields(schemaDoc map[string]any) ([]DeprecatedField, error) {
 	var deprecated []DeprecatedField
@@ -26,6 +26,12 @@
 //   - description: Human-readable description of the sub-agent's role.
 //   - model: AI model to use.  Default is "inherited" (uses the parent
 //     workflow's model when not set).
+//   - tools: Engine-native tool names the sub-agent may use.
+//   - max-turns: Maximum number of turns for the sub-agent.
+//   - network: Per-sub-agent network restriction ({allowed: [domains]}).
+//
+// tools, max-turns and network are validated at compile time against the
+// engine's EngineCapabilities (see ParseSubAgentConfig).
 //
 // # Example
 //
@@ -83,6 +89,9 @@ var subAgentLog = logger.New("parser:sub_agent_extractor")
 var validSubAgentFrontmatterFields = map[string]bool{
 	"description": true,
 	"model":       true,
+	"tools":       true,
+	"max-turns":   true,
+	"network":     true,
 }
 
 // ValidateInlineSubAgentsFrontmatter performs best-effort frontmatter validation
@@ -95,7 +104,7 @@ var validSubAgentFrontmatterFields = map[string]bool{
 //
 // For each detected sub-agent the function:
 //  1. Attempts to parse its embedded frontmatter block (--- … ---).
-//  2. Reports unknown fields (anything other than "description" or "model").
+//  2. Reports unknown fields (see validSubAgentFrontmatterFields).
 //
 // All issues are returned as human-readable warning strings. Callers must not
 // fail compilation based on these messages — they are advisory only (best effort).
@@ -161,7 +170,7 @@ func validateSubAgentFrontmatterFields(agent InlineSubAgent) []string {
 
 	sort.Strings(unknown) // deterministic order
 	return []string{fmt.Sprintf(
-		"sub-agent %q: unknown frontmatter field(s): %s (valid fields: description, model)",
+		"sub-agent %q: unknown frontmatter field(s): %s (valid fields: description, model, tools, max-turns, network)",
 		agent.Name, strings.Join(unknown, ", "),
 	)}
 }
@@ -0,0 +1,177 @@
+// Package parser — sub_agent_import.go
+//
+// This file provides shared sub-agent support: sub-agent definitions that live in
+// their own Markdown file and are imported by workflows through the `sub-agents:`
+// frontmatter field.
+//
+// # Sub-Agent Files
+//
+// A sub-agent file has the same shape as an inline sub-agent block: an optional
+// frontmatter block followed by the sub-agent prompt. The sub-agent name is taken
+// from the file name (security-reviewer.md → security-reviewer).
+//
+//	---
+//	description: Reviews changes for security issues
+//	tools: [read, search]
+//	---
+//	You are a security reviewer...
+//
+// # Resolution
+//
+// Specs are resolved with ResolveIncludePath, so local paths and remote
+// workflowspecs (owner/repo/path@ref) behave exactly like imports, including the
+// SHA-keyed import cache. Remote specs should pin a full commit SHA so every
+// workflow sharing a sub-agent runs the same vetted revision.
+
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/gitutil"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/typeutil"
+)
+
+var subAgentImportLog = logger.New("parser:sub_agent_import")
+
+// subAgentNameRegex matches valid sub-agent names (the same rule as inline ## agent: markers).
+var subAgentNameRegex = regexp.MustCompile(`^[a-z][a-z0-9_-]*$`)
+
+// SubAgentConfig holds the parsed frontmatter of an inline or imported sub-agent.
+type SubAgentConfig struct {
+	Description string
+	Model       string
+	Tools       []string // Engine-native tool names the sub-agent may use; nil inherits the workflow tools
+	MaxTurns    int      // Maximum number of turns; 0 inherits the workflow limit
+	Network     []string // Domains the sub-agent may reach; nil inherits the workflow network
+}
+
+// ImportedSubAgent is a sub-agent definition loaded from its own Markdown file.
+type ImportedSubAgent struct {
+	Name    string // Sub-agent name derived from the file name
+	Spec    string // Import spec as written in the sub-agents: frontmatter field
+	Content string // File content: optional frontmatter block followed by the prompt
+}
+
+// ParseSubAgentConfig parses the frontmatter block at the start of a sub-agent's content.
+// Content without frontmatter yields an empty config.
+func ParseSubAgentConfig(content string) (*SubAgentConfig, error) {
+	parsed, err := ExtractFrontmatterFromContent(content)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse frontmatter: %w", err)
+	}
+	config := &SubAgentConfig{}
+	fm := parsed.Frontmatter
+
+	if description, ok := fm["description"].(string); ok {
+		config.Description = description
+	}
+	if model, ok := fm["model"].(string); ok {
+		config.Model = model
+	}
+
+	if toolsValue, ok := fm["tools"]; ok {
+		switch tools := toolsValue.(type) {
+		case string:
+			for tool := range strings.SplitSeq(tools, ",") {
+				if tool = strings.TrimSpace(tool); tool != "" {
+					config.Tools = append(config.Tools, tool)
+				}
+			}
+		case []any:
+			for _, tool := range tools {
+				name, ok := tool.(string)
+				if !ok || strings.TrimSpace(name) == "" {
+					return nil, fmt.Errorf("tools must be a list of tool names, got %v", tool)
+				}
+				config.Tools = append(config.Tools, strings.TrimSpace(name))
+			}
+		default:
+			return nil, errors.New("tools must be a list of tool names or a comma-separated string")
+		}
+		if config.Tools == nil {
+			config.Tools = []string{}
+		}
+	}
+
+	if maxTurnsValue, ok := fm["max-turns"]; ok {
+		maxTurns, ok := typeutil.ParseIntValue(maxTurnsValue)
+		if !ok || maxTurns <= 0 {
+			return nil, fmt.Errorf("max-turns must be a positive integer, got %v", maxTurnsValue)
+		}
+		config.MaxTurns = maxTurns
+	}
+
+	if networkValue, ok := fm["network"]; ok {
+		networkMap, ok := networkValue.(map[string]any)
+		if !ok {
+			return nil, errors.New("network must be an object with an 'allowed' list of domains")
+		}
+		allowed, _ := networkMap["allowed"].([]any)
+		config.Network = []string{}
+		for _, domain := range allowed {
+			name, ok := domain.(string)
+			if !ok {
+				return nil, fmt.Errorf("network.allowed must be a list of domains, got %v", domain)
+			}
+			config.Network = append(config.Network, name)
+		}
+	}
+
+	return config, nil
+}
+
+// SubAgentNameFromPath derives a sub-agent name from a file path or workflowspec,
+// stripping any ref, section and the .agent.md or .md extension.
+func SubAgentNameFromPath(spec string) string {
+	clean, _, _ := strings.Cut(spec, "#")
+	clean, _, _ = strings.Cut(clean, "@")
+	name := path.Base(strings.ReplaceAll(clean, "\\", "/"))
+	if trimmed, ok := strings.CutSuffix(name, ".agent.md"); ok {
+		return trimmed
+	}
+	return strings.TrimSuffix(name, ".md")
+}
+
+// IsSubAgentSpecPinned reports whether a sub-agent spec refers to an immutable revision.
+// Local paths are versioned with the workflow itself; remote workflowspecs must use a
+// full 40-character commit SHA as their ref.
+func IsSubAgentSpecPinned(spec string) bool {
+	if !IsWorkflowSpec(spec) {
+		return true
+	}
+	clean, _, _ := strings.Cut(spec, "#")
+	_, ref, hasRef := strings.Cut(clean, "@")
+	return hasRef && len(ref) == 40 && gitutil.IsHexString(ref)
+}
+
+// ResolveSubAgentImport resolves a sub-agent spec through the import resolver and loads
+// the sub-agent definition. baseDir is the directory of the importing workflow.
+func ResolveSubAgentImport(spec, baseDir string, cache *ImportCache) (*ImportedSubAgent, error) {
+	name := SubAgentNameFromPath(spec)
+	if !subAgentNameRegex.MatchString(name) {
+		return nil, fmt.Errorf("invalid sub-agent file name %q: names must start with a lowercase letter and contain only lowercase letters, digits, hyphens, or underscores", name)
+	}
+
+	fullPath, err := ResolveIncludePath(spec, baseDir, cache)
+	if err != nil {
+		return nil, err
+	}
+	content, err := ReadFile(fullPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sub-agent file %s: %w", spec, err)
+	}
+	subAgentImportLog.Printf("Resolved sub-agent %q from %s (%d bytes)", name, spec, len(content))
+
+	trimmed := strings.TrimSpace(string(content))
+	// Level-2 headings would end the sub-agent block early once it is embedded in the prompt
+	if h2HeadingRegex.MatchString(trimmed) {
+		return nil, fmt.Errorf("sub-agent file %s must not contain level-2 (##) headings; use ### or deeper headings for sections", spec)
+	}
+	return &ImportedSubAgent{Name: name, Spec: spec, Content: trimmed}, nil
+}
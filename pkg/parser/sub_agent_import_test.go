@@ -0,0 +1,121 @@
+//go:build !integration
+
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSubAgentConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected *SubAgentConfig
+		wantErr  string
+	}{
+		{
+			name:     "no frontmatter",
+			content:  "You are a reviewer.",
+			expected: &SubAgentConfig{},
+		},
+		{
+			name:    "all fields",
+			content: "---\ndescription: Reviews code\nmodel: claude-haiku-4.5\ntools: [Read, Grep]\nmax-turns: 5\nnetwork:\n  allowed: [api.example.com]\n---\nPrompt",
+			expected: &SubAgentConfig{
+				Description: "Reviews code",
+				Model:       "claude-haiku-4.5",
+				Tools:       []string{"Read", "Grep"},
+				MaxTurns:    5,
+				Network:     []string{"api.example.com"},
+			},
+		},
+		{
+			name:     "comma-separated tools",
+			content:  "---\ntools: Read, Grep ,\n---\nPrompt",
+			expected: &SubAgentConfig{Tools: []string{"Read", "Grep"}},
+		},
+		{
+			name:     "empty tools list disables all tools",
+			content:  "---\ntools: []\n---\nPrompt",
+			expected: &SubAgentConfig{Tools: []string{}},
+		},
+		{
+			name:    "zero max-turns",
+			content: "---\nmax-turns: 0\n---\nPrompt",
+			wantErr: "max-turns must be a positive integer",
+		},
+		{
+			name:    "network without allowed object",
+			content: "---\nnetwork: defaults\n---\nPrompt",
+			wantErr: "network must be an object",
+		},
+		{
+			name:    "tools of the wrong type",
+			content: "---\ntools: 3\n---\nPrompt",
+			wantErr: "tools must be a list",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := ParseSubAgentConfig(tt.content)
+			if tt.wantErr != "" {
+				require.Error(t, err, "expected a parse error")
+				assert.Contains(t, err.Error(), tt.wantErr, "error should explain the invalid field")
+				return
+			}
+			require.NoError(t, err, "valid frontmatter should parse")
+			assert.Equal(t, tt.expected, config, "parsed config should match")
+		})
+	}
+}
+
+func TestSubAgentNameFromPath(t *testing.T) {
+	assert.Equal(t, "security-reviewer", SubAgentNameFromPath(".github/agents/security-reviewer.md"))
+	assert.Equal(t, "planner", SubAgentNameFromPath(".github/agents/planner.agent.md"))
+	assert.Equal(t, "style-reviewer", SubAgentNameFromPath("acme/agents/reviewers/style-reviewer.md@v1"))
+	assert.Equal(t, "triage", SubAgentNameFromPath("acme/agents/triage.md@main#Section"))
+}
+
+func TestIsSubAgentSpecPinned(t *testing.T) {
+	assert.True(t, IsSubAgentSpecPinned(".github/agents/reviewer.md"), "local paths are versioned with the workflow")
+	assert.True(t, IsSubAgentSpecPinned("acme/agents/reviewer.md@0123456789abcdef0123456789abcdef01234567"), "full SHAs are pinned")
+	assert.False(t, IsSubAgentSpecPinned("acme/agents/reviewer.md@v1.0.0"), "tags are mutable")
+	assert.False(t, IsSubAgentSpecPinned("acme/agents/reviewer.md@0123456"), "short SHAs are not pinned")
+	assert.False(t, IsSubAgentSpecPinned("acme/agents/reviewer.md"), "remote specs without a ref are not pinned")
+}
+
+func TestResolveSubAgentImport(t *testing.T) {
+	dir := t.TempDir()
+	agentsDir := filepath.Join(dir, ".github", "agents")
+	require.NoError(t, os.MkdirAll(agentsDir, 0755))
+	workflowsDir := filepath.Join(dir, ".github", "workflows")
+	require.NoError(t, os.MkdirAll(workflowsDir, 0755))
+
+	content := "---\ndescription: Reviews changes for security issues\ntools: [Read]\n---\nYou are a security reviewer.\n\n### Checklist\n\n- Secrets\n"
+	require.NoError(t, os.WriteFile(filepath.Join(agentsDir, "security-reviewer.md"), []byte(content), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(agentsDir, "bad-heading.md"), []byte("Intro\n\n## Section\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(agentsDir, "Bad_Name.md"), []byte("Prompt"), 0644))
+
+	agent, err := ResolveSubAgentImport("../agents/security-reviewer.md", workflowsDir, nil)
+	require.NoError(t, err, "local sub-agent file should resolve")
+	assert.Equal(t, "security-reviewer", agent.Name)
+	assert.Equal(t, "../agents/security-reviewer.md", agent.Spec)
+	assert.Equal(t, "---\ndescription: Reviews changes for security issues\ntools: [Read]\n---\nYou are a security reviewer.\n\n### Checklist\n\n- Secrets", agent.Content)
+
+	_, err = ResolveSubAgentImport("../agents/bad-heading.md", workflowsDir, nil)
+	require.Error(t, err, "level-2 headings would split the sub-agent block")
+	assert.Contains(t, err.Error(), "level-2 (##) headings")
+
+	_, err = ResolveSubAgentImport("../agents/Bad_Name.md", workflowsDir, nil)
+	require.Error(t, err, "file names must be valid sub-agent names")
+	assert.Contains(t, err.Error(), "invalid sub-agent file name")
+
+	_, err = ResolveSubAgentImport("../agents/missing.md", workflowsDir, nil)
+	require.Error(t, err, "missing files should fail to resolve")
+}
@@ -58,6 +58,25 @@ func isActionDefinitionFile(filePath string, content []byte) (bool, error) {
 	return false, nil
 }
 
+// IsGitHubActionsWorkflowFile checks if a file is a plain GitHub Actions workflow:
+// a .yml/.yaml file (not a .lock.yml) that is not an action definition and has jobs.
+func IsGitHubActionsWorkflowFile(filePath string, content []byte) (bool, error) {
+	if !isYAMLWorkflowFile(filePath) {
+		return false, nil
+	}
+	isAction, err := isActionDefinitionFile(filePath, content)
+	if err != nil || isAction {
+		return false, err
+	}
+
+	var doc map[string]any
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return false, fmt.Errorf("failed to parse YAML: %w", err)
+	}
+	_, hasJobs := doc["jobs"]
+	return hasJobs, nil
+}
+
 // isCopilotSetupStepsFile checks if a file is the special copilot-setup-steps file
 // This file receives special handling - only steps are extracted from the setup job
 // Supports both .yml and .yaml extensions for consistency with GitHub Actions
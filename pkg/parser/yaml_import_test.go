@@ -57,6 +57,54 @@ func TestIsYAMLWorkflowFile(t *testing.T) {
 	}
 }
 
+func TestIsGitHubActionsWorkflowFile(t *testing.T) {
+	tests := []struct {
+		name     string
+		filePath string
+		content  string
+		expected bool
+	}{
+		{
+			name:     "workflow with jobs",
+			filePath: "ci.yml",
+			content:  "on: push\njobs:\n  build:\n    runs-on: ubuntu-latest\n",
+			expected: true,
+		},
+		{
+			name:     "action definition",
+			filePath: "action.yml",
+			content:  "runs:\n  using: node20\n",
+			expected: false,
+		},
+		{
+			name:     "lock file",
+			filePath: "ci.lock.yml",
+			content:  "on: push\njobs: {}\n",
+			expected: false,
+		},
+		{
+			name:     "yaml without jobs",
+			filePath: "config.yml",
+			content:  "key: value\n",
+			expected: false,
+		},
+		{
+			name:     "markdown file",
+			filePath: "workflow.md",
+			content:  "# Workflow\n",
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := IsGitHubActionsWorkflowFile(tt.filePath, []byte(tt.content))
+			require.NoError(t, err, "File: %s", tt.filePath)
+			assert.Equal(t, tt.expected, result, "File: %s", tt.filePath)
+		})
+	}
+}
+
 func TestIsActionDefinitionFile(t *testing.T) {
 	tests := []struct {
 		name     string
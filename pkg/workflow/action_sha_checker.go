@@ -40,8 +40,17 @@ func ExtractActionsFromLockFile(lockFilePath string) ([]ActionUsage, error) {
 		return nil, fmt.Errorf("failed to read lock file: %w", err)
 	}
 
+	return ExtractActionsFromLockContent(string(content), lockFilePath)
+}
+
+// ExtractActionsFromLockContent extracts all action usages from lock file content.
+// lockFilePath is only used in error messages, so content read from a git ref can be
+// passed with its repository-relative path.
+func ExtractActionsFromLockContent(contentStr string, lockFilePath string) ([]ActionUsage, error) {
+	content := []byte(contentStr)
+
 	// Validate lock file schema compatibility before parsing
-	if err := ValidateLockSchemaCompatibility(string(content), lockFilePath); err != nil {
+	if err := ValidateLockSchemaCompatibility(contentStr, lockFilePath); err != nil {
 		return nil, err
 	}
 
@@ -51,8 +60,7 @@ func ExtractActionsFromLockFile(lockFilePath string) ([]ActionUsage, error) {
 		return nil, fmt.Errorf("failed to parse lock file YAML: %w", err)
 	}
 
-	// Convert to string and extract all uses fields
-	contentStr := string(content)
+	// Extract all uses fields
 	actions := make(map[string]ActionUsage) // Use map to deduplicate
 	matches := actionUsesPattern.FindAllStringSubmatch(contentStr, -1)
 
@@ -0,0 +1,230 @@
+// This file contains the repository-wide agent concurrency budget loaded from
+// the concurrency section of aw.json.
+//
+// # Agent Concurrency Budget
+//
+// The budget caps how many agent jobs may be queued or running in the repository
+// at once, across all agentic workflows:
+//
+//	"concurrency": {
+//	  "max_agent_jobs": 5,
+//	  "reserved": {"high": 2, "normal": 1},
+//	  "queue_timeout_minutes": 60,
+//	  "workflows": {"issue-triage": "high", "daily-*": "low"}
+//	}
+//
+// GitHub Actions concurrency groups run one job per group at a time, so they
+// cannot express a counting semaphore across workflows. Instead, the
+// activation job of every workflow ends with a queue step that waits until the
+// number of active agent jobs is below the limit of the workflow's priority
+// class. Waiting runs are admitted in the order they started, each counting the
+// runs ahead of it as if they had already taken their slots, so two runs that
+// check at the same time cannot both take the last slot. The step name carries
+// the priority class so that every run can evaluate the others' limits. Slots
+// reserved for a class can only be used by that class and the classes above it:
+//
+//	high   may start while active < max_agent_jobs
+//	normal may start while active < max_agent_jobs - reserved.high
+//	low    may start while active < max_agent_jobs - reserved.high - reserved.normal
+//
+// so scheduled (low) runs can never take the capacity slash commands (high) need.
+// Workflows without an explicit class are classified from their triggers:
+// command and label-command workflows are high, schedule-only workflows are low,
+// everything else is normal.
+
+package workflow
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/constants"
+	"github.com/github/gh-aw/pkg/logger"
+)
+
+var agentBudgetLog = logger.New("workflow:agent_budget")
+
+// AgentPriority is the priority class of a workflow within the agent concurrency budget.
+type AgentPriority string
+
+const (
+	AgentPriorityHigh   AgentPriority = "high"
+	AgentPriorityNormal AgentPriority = "normal"
+	AgentPriorityLow    AgentPriority = "low"
+)
+
+// AgentPriorities lists the priority classes from highest to lowest.
+var AgentPriorities = []AgentPriority{AgentPriorityHigh, AgentPriorityNormal, AgentPriorityLow}
+
+// DefaultAgentQueueTimeoutMinutes is how long a run waits for a slot before it
+// starts anyway with a warning.
+const DefaultAgentQueueTimeoutMinutes = 60
+
+// AgentConcurrencyConfig holds the concurrency section of aw.json.
+type AgentConcurrencyConfig struct {
+	// MaxAgentJobs is the maximum number of agent jobs queued or running at once.
+	MaxAgentJobs int `json:"max_agent_jobs"`
+
+	// Reserved is the number of slots kept free for each priority class and above.
+	Reserved AgentReservedSlots `json:"reserved"`
+
+	// QueueTimeoutMinutes bounds how long a run waits for a slot.
+	QueueTimeoutMinutes int `json:"queue_timeout_minutes,omitempty"`
+
+	// Workflows maps workflow IDs or glob patterns to a priority class.
+	Workflows map[string]AgentPriority `json:"workflows,omitempty"`
+}
+
+// AgentReservedSlots is the number of budget slots reserved for each priority class.
+type AgentReservedSlots struct {
+	High   int `json:"high,omitempty"`
+	Normal int `json:"normal,omitempty"`
+}
+
+// AgentConcurrency returns the configured agent concurrency budget, or nil when none is configured.
+func (r *RepoConfig) AgentConcurrency() *AgentConcurrencyConfig {
+	if r == nil {
+		return nil
+	}
+	return r.Concurrency
+}
+
+// validate checks that the reserved slots leave room for low priority workflows.
+func (c *AgentConcurrencyConfig) validate() error {
+	if c == nil {
+		return nil
+	}
+	if c.Reserved.High+c.Reserved.Normal >= c.MaxAgentJobs {
+		return errors.New("concurrency.reserved must leave at least one of max_agent_jobs slots for low priority workflows")
+	}
+	return nil
+}
+
+// Limit returns the number of active agent jobs below which a workflow of the
+// given priority may start its agent job.
+func (c *AgentConcurrencyConfig) Limit(priority AgentPriority) int {
+	switch priority {
+	case AgentPriorityHigh:
+		return c.MaxAgentJobs
+	case AgentPriorityLow:
+		return c.MaxAgentJobs - c.Reserved.High - c.Reserved.Normal
+	default:
+		return c.MaxAgentJobs - c.Reserved.High
+	}
+}
+
+// QueueTimeout returns the configured queue timeout in minutes, or the default.
+func (c *AgentConcurrencyConfig) QueueTimeout() int {
+	if c.QueueTimeoutMinutes > 0 {
+		return c.QueueTimeoutMinutes
+	}
+	return DefaultAgentQueueTimeoutMinutes
+}
+
+// PriorityFor returns the priority class of a workflow. An exact workflow ID in
+// the workflows map wins, then the longest matching glob pattern, and otherwise
+// the class is inferred from the workflow's triggers.
+func (c *AgentConcurrencyConfig) PriorityFor(data *WorkflowData) AgentPriority {
+	if priority, ok := c.Workflows[data.WorkflowID]; ok {
+		return priority
+	}
+
+	patterns := make([]string, 0, len(c.Workflows))
+	for key := range c.Workflows {
+		if strings.ContainsAny(key, "*?[") {
+			patterns = append(patterns, key)
+		}
+	}
+	// Prefer the most specific (longest) pattern; break ties alphabetically so
+	// that the result does not depend on map iteration order.
+	sort.Slice(patterns, func(i, j int) bool {
+		if len(patterns[i]) != len(patterns[j]) {
+			return len(patterns[i]) > len(patterns[j])
+		}
+		return patterns[i] < patterns[j]
+	})
+	for _, pattern := range patterns {
+		if matched, err := path.Match(pattern, data.WorkflowID); err == nil && matched {
+			agentBudgetLog.Printf("Workflow %s matched priority pattern %s", data.WorkflowID, pattern)
+			return c.Workflows[pattern]
+		}
+	}
+	return inferAgentPriority(data)
+}
+
+// inferAgentPriority classifies a workflow without an explicit priority:
+// interactive command workflows are high, schedule-only workflows are low.
+func inferAgentPriority(data *WorkflowData) AgentPriority {
+	if len(data.Command) > 0 || len(data.LabelCommand) > 0 || isSlashCommandWorkflow(data.On) {
+		return AgentPriorityHigh
+	}
+	if strings.Contains(data.On, "schedule") && !hasSpecialTriggers(data) && !isScheduleWithEventTriggers(data.On) {
+		return AgentPriorityLow
+	}
+	return AgentPriorityNormal
+}
+
+// isScheduleWithEventTriggers reports whether a scheduled workflow is also started
+// by repository events, in which case it is not treated as background work
+func isScheduleWithEventTriggers(on string) bool {
+	for _, trigger := range []string{"workflow_run", "repository_dispatch", "release", "check_run", "check_suite", "merge_group"} {
+		if strings.Contains(on, trigger+":") {
+			return true
+		}
+	}
+	return false
+}
+
+// generateAgentBudgetStep generates the activation step that waits for a slot in the
+// repository-wide agent concurrency budget. It returns nil when no budget is configured.
+func (c *Compiler) generateAgentBudgetStep(data *WorkflowData) []string {
+	repoConfig, err := c.loadRepoConfig()
+	if err != nil {
+		agentBudgetLog.Printf("Skipping agent concurrency budget: %v", err)
+		return nil
+	}
+	budget := repoConfig.AgentConcurrency()
+	if budget == nil {
+		return nil
+	}
+
+	priority := budget.PriorityFor(data)
+	limit := budget.Limit(priority)
+	agentBudgetLog.Printf("Agent concurrency budget for %s: priority=%s, limit=%d of %d", data.WorkflowID, priority, limit, budget.MaxAgentJobs)
+
+	limits := make(map[AgentPriority]int, len(AgentPriorities))
+	for _, p := range AgentPriorities {
+		limits[p] = budget.Limit(p)
+	}
+	limitsJSON, err := json.Marshal(limits)
+	if err != nil {
+		agentBudgetLog.Printf("Failed to marshal agent budget limits: %v", err)
+		return nil
+	}
+
+	var steps []string
+	steps = append(steps, fmt.Sprintf("      - name: Wait for agent concurrency slot (%s)\n", priority))
+	steps = append(steps, fmt.Sprintf("        id: %s\n", constants.AgentBudgetStepID))
+	steps = append(steps, fmt.Sprintf("        uses: %s\n", getCachedActionPin("actions/github-script", data)))
+	steps = append(steps, "        env:\n")
+	steps = append(steps, fmt.Sprintf("          GH_AW_AGENT_JOB_NAME: %q\n", string(constants.AgentJobName)))
+	steps = append(steps, fmt.Sprintf("          GH_AW_AGENT_PRIORITY: %q\n", string(priority)))
+	steps = append(steps, fmt.Sprintf("          GH_AW_AGENT_LIMIT: \"%d\"\n", limit))
+	steps = append(steps, fmt.Sprintf("          GH_AW_AGENT_LIMITS: %q\n", string(limitsJSON)))
+	steps = append(steps, fmt.Sprintf("          GH_AW_AGENT_MAX: \"%d\"\n", budget.MaxAgentJobs))
+	steps = append(steps, fmt.Sprintf("          GH_AW_AGENT_QUEUE_TIMEOUT_MINUTES: \"%d\"\n", budget.QueueTimeout()))
+	steps = append(steps, "        with:\n")
+	steps = append(steps, "          script: |\n")
+	steps = append(steps, generateGitHubScriptWithRequire("wait_for_agent_slot.cjs"))
+	return steps
+}
+
+// hasAgentBudget reports whether the repository configures an agent concurrency budget.
+func (c *Compiler) hasAgentBudget() bool {
+	repoConfig, err := c.loadRepoConfig()
+	return err == nil && repoConfig.AgentConcurrency() != nil
+}
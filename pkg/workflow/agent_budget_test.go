@@ -0,0 +1,110 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadRepoConfig_ConcurrencySection(t *testing.T) {
+	dir := t.TempDir()
+	writeAWJSON(t, dir, `{
+		"concurrency": {
+			"max_agent_jobs": 5,
+			"reserved": {"high": 2, "normal": 1},
+			"workflows": {"issue-triage": "high", "daily-*": "low"}
+		}
+	}`)
+
+	cfg, err := LoadRepoConfig(dir)
+	require.NoError(t, err, "valid concurrency section should load without error")
+	budget := cfg.AgentConcurrency()
+	require.NotNil(t, budget, "agent concurrency budget should be set")
+	assert.Equal(t, 5, budget.MaxAgentJobs, "max agent jobs should be parsed")
+	assert.Equal(t, DefaultAgentQueueTimeoutMinutes, budget.QueueTimeout(), "queue timeout should default")
+	assert.Equal(t, AgentPriorityLow, budget.Workflows["daily-*"], "workflow priorities should be parsed")
+}
+
+func TestLoadRepoConfig_ConcurrencyValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{name: "missing max_agent_jobs", content: `{"concurrency": {"reserved": {"high": 1}}}`},
+		{name: "unknown priority", content: `{"concurrency": {"max_agent_jobs": 3, "workflows": {"triage": "urgent"}}}`},
+		{name: "reserved uses all slots", content: `{"concurrency": {"max_agent_jobs": 3, "reserved": {"high": 2, "normal": 1}}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeAWJSON(t, dir, tt.content)
+
+			_, err := LoadRepoConfig(dir)
+			require.Error(t, err, "invalid concurrency section should fail to load")
+		})
+	}
+}
+
+func TestAgentConcurrencyLimit(t *testing.T) {
+	budget := &AgentConcurrencyConfig{MaxAgentJobs: 5, Reserved: AgentReservedSlots{High: 2, Normal: 1}}
+
+	assert.Equal(t, 5, budget.Limit(AgentPriorityHigh), "high priority may use the whole budget")
+	assert.Equal(t, 3, budget.Limit(AgentPriorityNormal), "normal priority may not use slots reserved for high")
+	assert.Equal(t, 2, budget.Limit(AgentPriorityLow), "low priority may not use any reserved slots")
+}
+
+func TestAgentConcurrencyPriorityFor(t *testing.T) {
+	budget := &AgentConcurrencyConfig{
+		MaxAgentJobs: 5,
+		Workflows: map[string]AgentPriority{
+			"daily-report": "normal",
+			"daily-*":      "low",
+			"daily-sec*":   "high",
+		},
+	}
+
+	tests := []struct {
+		name     string
+		data     *WorkflowData
+		expected AgentPriority
+	}{
+		{name: "exact match", data: &WorkflowData{WorkflowID: "daily-report", On: "on:\n  schedule:\n    - cron: daily"}, expected: AgentPriorityNormal},
+		{name: "longest pattern wins", data: &WorkflowData{WorkflowID: "daily-security", On: "on:\n  schedule:\n    - cron: daily"}, expected: AgentPriorityHigh},
+		{name: "pattern match", data: &WorkflowData{WorkflowID: "daily-docs", On: "on:\n  issues:\n    types: [opened]"}, expected: AgentPriorityLow},
+		{name: "command is high", data: &WorkflowData{WorkflowID: "fix", Command: []string{"fix"}}, expected: AgentPriorityHigh},
+		{name: "schedule only is low", data: &WorkflowData{WorkflowID: "nightly", On: "on:\n  schedule:\n    - cron: daily\n  workflow_dispatch:"}, expected: AgentPriorityLow},
+		{name: "schedule with issues is normal", data: &WorkflowData{WorkflowID: "triage", On: "on:\n  schedule:\n    - cron: daily\n  issues:\n    types: [opened]"}, expected: AgentPriorityNormal},
+		{name: "events are normal", data: &WorkflowData{WorkflowID: "review", On: "on:\n  pull_request:\n    types: [opened]"}, expected: AgentPriorityNormal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, budget.PriorityFor(tt.data), "priority class should match")
+		})
+	}
+}
+
+func TestGenerateAgentBudgetStep(t *testing.T) {
+	gitRoot := t.TempDir()
+	writeAWJSON(t, gitRoot, `{"concurrency": {"max_agent_jobs": 4, "reserved": {"high": 1}, "queue_timeout_minutes": 30}}`)
+
+	compiler := NewCompiler()
+	compiler.gitRoot = gitRoot
+	require.True(t, compiler.hasAgentBudget(), "budget should be detected")
+
+	step := strings.Join(compiler.generateAgentBudgetStep(&WorkflowData{WorkflowID: "review", On: "on:\n  pull_request:"}), "")
+	assert.Contains(t, step, "name: Wait for agent concurrency slot (normal)", "step name should carry the priority class")
+	assert.Contains(t, step, "id: agent_budget", "step should have the budget step ID")
+	assert.Contains(t, step, `GH_AW_AGENT_PRIORITY: "normal"`, "priority should be passed to the script")
+	assert.Contains(t, step, `GH_AW_AGENT_LIMIT: "3"`, "normal limit should exclude the high reservation")
+	assert.Contains(t, step, `GH_AW_AGENT_LIMITS: "{\"high\":4,\"low\":3,\"normal\":3}"`, "limits of all classes should be passed to the script")
+	assert.Contains(t, step, `GH_AW_AGENT_QUEUE_TIMEOUT_MINUTES: "30"`, "queue timeout should be passed to the script")
+	assert.Contains(t, step, "wait_for_agent_slot.cjs", "step should run the queue script")
+
+	withoutBudget := NewCompiler()
+	withoutBudget.gitRoot = t.TempDir()
+	assert.Nil(t, withoutBudget.generateAgentBudgetStep(&WorkflowData{WorkflowID: "review"}), "no step without a budget")
+}
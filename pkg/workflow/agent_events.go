@@ -0,0 +1,203 @@
+package workflow
+
+import (
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/stringutil"
+	"github.com/github/gh-aw/pkg/typeutil"
+)
+
+var agentEventsLog = logger.New("workflow:agent_events")
+
+// AgentEventSchemaVersion is the version of the normalized agent event schema.
+// Bump it whenever a field changes meaning or is removed; adding optional fields
+// does not require a new version.
+const AgentEventSchemaVersion = 1
+
+// agentEventMaxTextLength caps the message and error text carried by an event so
+// exported streams stay small; sizes are reported separately.
+const agentEventMaxTextLength = 2000
+
+// AgentEventType identifies the kind of a normalized agent event.
+type AgentEventType string
+
+const (
+	// AgentEventTurnStart marks the start of an LLM turn (one model request/response).
+	AgentEventTurnStart AgentEventType = "turn_start"
+	// AgentEventTurnEnd marks the end of an LLM turn.
+	AgentEventTurnEnd AgentEventType = "turn_end"
+	// AgentEventAssistantMessage is text produced by the model.
+	AgentEventAssistantMessage AgentEventType = "assistant_message"
+	// AgentEventToolCall is a tool invocation requested by the model.
+	AgentEventToolCall AgentEventType = "tool_call"
+	// AgentEventToolResult is the result returned to the model for a tool call.
+	AgentEventToolResult AgentEventType = "tool_result"
+	// AgentEventError is an error reported by the engine.
+	AgentEventError AgentEventType = "error"
+	// AgentEventTokenUsage reports token usage, per turn or aggregated per model.
+	AgentEventTokenUsage AgentEventType = "token_usage"
+	// AgentEventModelSwitch reports the model in use; the first one carries the initial model.
+	AgentEventModelSwitch AgentEventType = "model_switch"
+)
+
+// AgentEvent is a single event in the normalized, engine-independent agent event stream.
+// Every engine log parser emits this schema so that analytics and log mining do not need
+// to know each engine's log format.
+type AgentEvent struct {
+	Version    int              `json:"v"`
+	Seq        int              `json:"seq"`
+	Type       AgentEventType   `json:"type"`
+	Engine     string           `json:"engine"`
+	RunID      int64            `json:"run_id,omitempty"`
+	Turn       int              `json:"turn,omitempty"`
+	Timestamp  string           `json:"timestamp,omitempty"`
+	Model      string           `json:"model,omitempty"`
+	Tool       string           `json:"tool,omitempty"`
+	ToolCallID string           `json:"tool_call_id,omitempty"`
+	InputSize  int              `json:"input_size,omitempty"`
+	OutputSize int              `json:"output_size,omitempty"`
+	Text       string           `json:"text,omitempty"`
+	IsError    bool             `json:"is_error,omitempty"`
+	Usage      *AgentEventUsage `json:"usage,omitempty"`
+}
+
+// AgentEventUsage holds the token usage carried by a token_usage event.
+type AgentEventUsage struct {
+	InputTokens      int     `json:"input_tokens,omitempty"`
+	OutputTokens     int     `json:"output_tokens,omitempty"`
+	CacheReadTokens  int     `json:"cache_read_tokens,omitempty"`
+	CacheWriteTokens int     `json:"cache_write_tokens,omitempty"`
+	TotalTokens      int     `json:"total_tokens"`
+	CostUSD          float64 `json:"cost_usd,omitempty"`
+}
+
+// AgentEventStream accumulates normalized events for one engine log, assigning the
+// schema version, sequence numbers, engine ID and turn numbers.
+type AgentEventStream struct {
+	engine   string
+	events   []AgentEvent
+	turn     int
+	inTurn   bool
+	model    string
+	returned int // events already returned by Pending
+}
+
+// NewAgentEventStream creates an empty event stream for the given engine ID.
+func NewAgentEventStream(engine string) *AgentEventStream {
+	return &AgentEventStream{engine: engine}
+}
+
+// Emit appends an event, stamping it with the stream's version, sequence, engine,
+// current turn and model.
+func (s *AgentEventStream) Emit(evt AgentEvent) {
+	evt.Version = AgentEventSchemaVersion
+	evt.Seq = len(s.events) + 1
+	evt.Engine = s.engine
+	evt.Turn = s.turn
+	if evt.Model == "" {
+		evt.Model = s.model
+	}
+	evt.Text = stringutil.Truncate(evt.Text, agentEventMaxTextLength)
+	s.events = append(s.events, evt)
+}
+
+// StartTurn closes any open turn and starts the next one.
+func (s *AgentEventStream) StartTurn(timestamp string) {
+	s.EndTurn(timestamp)
+	s.turn++
+	s.inTurn = true
+	s.Emit(AgentEvent{Type: AgentEventTurnStart, Timestamp: timestamp})
+}
+
+// EndTurn closes the open turn, if any.
+func (s *AgentEventStream) EndTurn(timestamp string) {
+	if !s.inTurn {
+		return
+	}
+	s.inTurn = false
+	s.Emit(AgentEvent{Type: AgentEventTurnEnd, Timestamp: timestamp})
+}
+
+// InTurn reports whether a turn is currently open.
+func (s *AgentEventStream) InTurn() bool {
+	return s.inTurn
+}
+
+// SetModel emits a model_switch event when the model differs from the current one.
+func (s *AgentEventStream) SetModel(model, timestamp string) {
+	if model == "" || model == s.model {
+		return
+	}
+	s.model = model
+	s.Emit(AgentEvent{Type: AgentEventModelSwitch, Model: model, Timestamp: timestamp})
+}
+
+// Events closes any open turn and returns the accumulated events.
+func (s *AgentEventStream) Events() []AgentEvent {
+	s.EndTurn("")
+	agentEventsLog.Printf("Collected %d agent events for engine %s: turns=%d", len(s.events), s.engine, s.turn)
+	return s.events
+}
+
+// Pending returns the events emitted since the previous call without closing the
+// open turn, so that a log that is still growing can be reported incrementally.
+func (s *AgentEventStream) Pending() []AgentEvent {
+	return s.pendingUntil(len(s.events))
+}
+
+// pendingUntil returns the events emitted since the previous call up to end, for
+// parsers that complete an emitted event from later lines.
+func (s *AgentEventStream) pendingUntil(end int) []AgentEvent {
+	if end <= s.returned {
+		return nil
+	}
+	pending := s.events[s.returned:end]
+	s.returned = end
+	return pending
+}
+
+// AgentEventParser converts an engine log into normalized agent events one line
+// at a time. It keeps the parse state between lines so that a growing log, such
+// as a job log being followed, is parsed only once.
+type AgentEventParser interface {
+	// ParseLine parses the next complete line of the log, without its newline.
+	ParseLine(line string)
+	// Pending returns the events completed since the previous call. An open turn
+	// is not closed, so its turn_end is only returned once the log ends the turn.
+	Pending() []AgentEvent
+}
+
+// noAgentEventParser is the AgentEventParser of engines without a log parser
+type noAgentEventParser struct{}
+
+func (noAgentEventParser) ParseLine(string) {}
+
+func (noAgentEventParser) Pending() []AgentEvent { return nil }
+
+// agentEventUsageFromMap converts an Anthropic- or OpenAI-style usage object into
+// an AgentEventUsage. It returns nil when the object carries no token counts.
+func agentEventUsageFromMap(usage map[string]any) *AgentEventUsage {
+	if usage == nil {
+		return nil
+	}
+	result := &AgentEventUsage{
+		InputTokens:      typeutil.ConvertToInt(usage["input_tokens"]),
+		OutputTokens:     typeutil.ConvertToInt(usage["output_tokens"]),
+		CacheReadTokens:  typeutil.ConvertToInt(usage["cache_read_input_tokens"]),
+		CacheWriteTokens: typeutil.ConvertToInt(usage["cache_creation_input_tokens"]),
+		TotalTokens:      typeutil.ConvertToInt(usage["total_tokens"]),
+	}
+	// OpenAI format: {"prompt_tokens": 10, "completion_tokens": 5, "total_tokens": 15}
+	if result.InputTokens == 0 {
+		result.InputTokens = typeutil.ConvertToInt(usage["prompt_tokens"])
+	}
+	if result.OutputTokens == 0 {
+		result.OutputTokens = typeutil.ConvertToInt(usage["completion_tokens"])
+	}
+	if result.TotalTokens == 0 {
+		result.TotalTokens = result.InputTokens + result.OutputTokens + result.CacheReadTokens + result.CacheWriteTokens
+	}
+	if result.TotalTokens == 0 {
+		return nil
+	}
+	return result
+}
@@ -48,6 +48,8 @@ type GitHubActionStep []string
 //
 //   LogParser (log analysis - optional)
 //   ├── ParseLogMetrics()
+//   ├── ParseAgentEvents()
+//   ├── NewAgentEventParser()
 //   ├── GetLogParserScriptId()
 //   └── GetLogFileForParsing()
 //
@@ -126,6 +128,17 @@ type EngineCapabilities struct {
 	// which suppresses automatic loading of context and custom instructions. When false,
 	// specifying bare: true emits a warning and has no effect.
 	BareMode bool
+
+	// SubAgentTools reports whether the engine enforces a per-sub-agent tool allowlist
+	// (the tools field of an inline or imported sub-agent).
+	SubAgentTools bool
+
+	// SubAgentMaxTurns reports whether the engine enforces a per-sub-agent max-turns limit.
+	SubAgentMaxTurns bool
+
+	// SubAgentNetwork reports whether the engine can restrict network access per sub-agent.
+	// Engines without it run every sub-agent behind the workflow's firewall.
+	SubAgentNetwork bool
 }
 
 // CapabilityProvider detects what capabilities an engine supports.
@@ -183,6 +196,12 @@ type LogParser interface {
 	// ParseLogMetrics extracts metrics from engine-specific log content
 	ParseLogMetrics(logContent string, verbose bool) LogMetrics
 
+	// ParseAgentEvents converts engine-specific log content into the normalized agent event stream
+	ParseAgentEvents(logContent string) []AgentEvent
+
+	// NewAgentEventParser returns a parser that emits the same events line by line
+	NewAgentEventParser() AgentEventParser
+
 	// GetLogParserScriptId returns the name of the JavaScript script to parse logs for this engine
 	GetLogParserScriptId() string
 
@@ -381,6 +400,18 @@ func (e *BaseEngine) ParseLogMetrics(logContent string, verbose bool) LogMetrics
 	return LogMetrics{}
 }
 
+// ParseAgentEvents provides a default implementation that emits no events
+// Engines with a log parser override this to emit the normalized agent event stream
+func (e *BaseEngine) ParseAgentEvents(logContent string) []AgentEvent {
+	return nil
+}
+
+// NewAgentEventParser provides a default implementation that emits no events
+// Engines with a log parser override this to parse a growing log line by line
+func (e *BaseEngine) NewAgentEventParser() AgentEventParser {
+	return noAgentEventParser{}
+}
+
 // GetLogParserScriptId returns empty string by default (no JavaScript parser)
 // Engines can override this to provide a JavaScript parser for log analysis
 func (e *BaseEngine) GetLogParserScriptId() string {
@@ -416,6 +447,10 @@ func (e *BaseEngine) RenderConfig(_ *ResolvedEngineTarget) ([]map[string]any, er
 type EngineRegistry struct {
 	engines map[string]CodingAgentEngine
 
+	// hidden holds the IDs of engines that can be used in workflows but are left
+	// out of the supported engine list, such as the test-only replay engine
+	hidden map[string]bool
+
 	// Cached results for GetAllAgentManifestFiles and GetAllAgentManifestFolders.
 	// These are computed once on first call and never change after engine registration.
 	cachedManifestFiles   []string
@@ -450,6 +485,10 @@ func NewEngineRegistry() *EngineRegistry {
 			panic(fmt.Sprintf("failed to register built-in engine: %v", err))
 		}
 	}
+	// The replay engine is for deterministic workflow tests and is not offered to users
+	if err := registry.RegisterHidden(NewReplayEngine()); err != nil {
+		panic(fmt.Sprintf("failed to register built-in engine: %v", err))
+	}
 
 	agenticEngineLog.Printf("Registered %d engines", len(registry.engines))
 
@@ -484,6 +523,24 @@ func (r *EngineRegistry) Register(engine CodingAgentEngine) error {
 	return nil
 }
 
+// RegisterHidden adds an engine that workflows can use but that is not listed by
+// GetSupportedEngines, shell completions or the engine catalog.
+func (r *EngineRegistry) RegisterHidden(engine CodingAgentEngine) error {
+	if err := r.Register(engine); err != nil {
+		return err
+	}
+	if r.hidden == nil {
+		r.hidden = make(map[string]bool)
+	}
+	r.hidden[engine.GetID()] = true
+	return nil
+}
+
+// IsHiddenEngine reports whether an engine was registered with RegisterHidden
+func (r *EngineRegistry) IsHiddenEngine(id string) bool {
+	return r.hidden[id]
+}
+
 // GetEngine retrieves an engine by ID
 func (r *EngineRegistry) GetEngine(id string) (CodingAgentEngine, error) {
 	agenticEngineLog.Printf("Looking up engine: id=%s", id)
@@ -496,12 +553,14 @@ func (r *EngineRegistry) GetEngine(id string) (CodingAgentEngine, error) {
 	return engine, nil
 }
 
-// GetSupportedEngines returns a list of all supported engine IDs
+// GetSupportedEngines returns a list of all supported engine IDs, excluding hidden engines
 func (r *EngineRegistry) GetSupportedEngines() []string {
 	agenticEngineLog.Print("Getting list of supported engines")
 	var engines []string
 	for id := range r.engines {
-		engines = append(engines, id)
+		if !r.hidden[id] {
+			engines = append(engines, id)
+		}
 	}
 	sort.Strings(engines)
 	return engines
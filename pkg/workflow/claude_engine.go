@@ -32,6 +32,7 @@ func NewClaudeEngine() *ClaudeEngine {
 				WebSearch:        true,  // Claude has built-in WebSearch support
 				NativeAgentFile:  false, // Claude does not support agent file natively; the compiler prepends the agent file content to prompt.txt
 				BareMode:         true,  // Claude CLI supports --bare
+				SubAgentTools:    true,  // Claude sub-agents accept a tools list in their frontmatter
 			},
 			dedicatedLLMGatewayPort: constants.ClaudeLLMGatewayPort,
 		},
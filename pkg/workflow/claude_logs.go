@@ -146,91 +146,9 @@ func (e *ClaudeEngine) parseClaudeJSONLog(logContent string, verbose bool) LogMe
 	claudeLogsLog.Print("Attempting to parse Claude JSON log")
 	var metrics LogMetrics
 
-	// Try to parse the entire log as a JSON array first (old format)
-	var logEntries []map[string]any
-	if err := json.Unmarshal([]byte(logContent), &logEntries); err != nil {
-		// If that fails, try to parse as mixed format (debug logs + JSONL)
-		claudeLogsLog.Print("JSON array parse failed, trying JSONL format")
-		if verbose {
-			fmt.Fprintf(os.Stderr, "Failed to parse Claude log as JSON array, trying JSONL format: %v\n", err)
-		}
-
-		logEntries = []map[string]any{}
-		lines := strings.Split(logContent, "\n")
-
-		for i := 0; i < len(lines); i++ {
-			line := lines[i]
-			trimmedLine := strings.TrimSpace(line)
-			if trimmedLine == "" {
-				continue // Skip empty lines
-			}
-
-			// If a line looks like a JSON array (starts with '['), try to parse it as an array
-			if strings.HasPrefix(trimmedLine, "[") {
-				buf := trimmedLine
-				// If the closing bracket is not on the same line, accumulate subsequent lines
-				if !strings.Contains(trimmedLine, "]") {
-					j := i + 1
-					var sb strings.Builder
-					for j < len(lines) {
-						sb.WriteString("\n" + lines[j])
-						if strings.Contains(lines[j], "]") {
-							// Advance outer loop to the line we consumed
-							i = j
-							break
-						}
-						j++
-					}
-					buf += sb.String()
-				}
-
-				var arr []map[string]any
-				if err := json.Unmarshal([]byte(buf), &arr); err == nil {
-					logEntries = append(logEntries, arr...)
-					continue
-				}
-
-				// If parsing as a single-line or multi-line array failed, attempt to extract a JSON array substring
-				openIdx := strings.Index(buf, "[")
-				closeIdx := strings.LastIndex(buf, "]")
-				if openIdx != -1 && closeIdx != -1 && closeIdx > openIdx {
-					sub := buf[openIdx : closeIdx+1]
-					var arr2 []map[string]any
-					if err2 := json.Unmarshal([]byte(sub), &arr2); err2 == nil {
-						logEntries = append(logEntries, arr2...)
-						continue
-					}
-				}
-			}
-
-			// Skip debug log lines that don't start with '{'
-			if !strings.HasPrefix(trimmedLine, "{") {
-				continue
-			}
-
-			// Try to parse each line as JSON
-			var jsonEntry map[string]any
-			if err := json.Unmarshal([]byte(trimmedLine), &jsonEntry); err != nil {
-				// Skip invalid JSON lines (could be partial debug output)
-				if verbose {
-					fmt.Fprintf(os.Stderr, "Skipping invalid JSON line: %s\n", trimmedLine)
-				}
-				continue
-			}
-
-			logEntries = append(logEntries, jsonEntry)
-		}
-
-		if len(logEntries) == 0 {
-			if verbose {
-				fmt.Fprintf(os.Stderr, "No valid JSON entries found in Claude log\n")
-			}
-			return metrics
-		}
-
-		if verbose {
-			fmt.Fprintf(os.Stderr, "Extracted %d JSON entries from mixed format Claude log\n", len(logEntries))
-		}
+	logEntries := e.parseClaudeLogEntries(logContent, verbose)
+	if len(logEntries) == 0 {
+		return metrics
 	}
 
 	// Look for the result entry with type: "result"
@@ -330,6 +248,98 @@ func (e *ClaudeEngine) parseClaudeJSONLog(logContent string, verbose bool) LogMe
 	return metrics
 }
 
+// parseClaudeLogEntries returns the JSON entries of a Claude log, which is either a JSON
+// array (old format) or debug log lines mixed with JSONL and embedded JSON arrays.
+func (e *ClaudeEngine) parseClaudeLogEntries(logContent string, verbose bool) []map[string]any {
+	var logEntries []map[string]any
+	if err := json.Unmarshal([]byte(logContent), &logEntries); err != nil {
+		// If that fails, try to parse as mixed format (debug logs + JSONL)
+		claudeLogsLog.Print("JSON array parse failed, trying JSONL format")
+		if verbose {
+			fmt.Fprintf(os.Stderr, "Failed to parse Claude log as JSON array, trying JSONL format: %v\n", err)
+		}
+
+		logEntries = []map[string]any{}
+		lines := strings.Split(logContent, "\n")
+
+		for i := 0; i < len(lines); i++ {
+			line := lines[i]
+			trimmedLine := strings.TrimSpace(line)
+			if trimmedLine == "" {
+				continue // Skip empty lines
+			}
+
+			// If a line looks like a JSON array (starts with '['), try to parse it as an array
+			if strings.HasPrefix(trimmedLine, "[") {
+				buf := trimmedLine
+				// If the closing bracket is not on the same line, accumulate subsequent lines
+				if !strings.Contains(trimmedLine, "]") {
+					j := i + 1
+					var sb strings.Builder
+					for j < len(lines) {
+						sb.WriteString("\n" + lines[j])
+						if strings.Contains(lines[j], "]") {
+							// Advance outer loop to the line we consumed
+							i = j
+							break
+						}
+						j++
+					}
+					buf += sb.String()
+				}
+
+				var arr []map[string]any
+				if err := json.Unmarshal([]byte(buf), &arr); err == nil {
+					logEntries = append(logEntries, arr...)
+					continue
+				}
+
+				// If parsing as a single-line or multi-line array failed, attempt to extract a JSON array substring
+				openIdx := strings.Index(buf, "[")
+				closeIdx := strings.LastIndex(buf, "]")
+				if openIdx != -1 && closeIdx != -1 && closeIdx > openIdx {
+					sub := buf[openIdx : closeIdx+1]
+					var arr2 []map[string]any
+					if err2 := json.Unmarshal([]byte(sub), &arr2); err2 == nil {
+						logEntries = append(logEntries, arr2...)
+						continue
+					}
+				}
+			}
+
+			// Skip debug log lines that don't start with '{'
+			if !strings.HasPrefix(trimmedLine, "{") {
+				continue
+			}
+
+			// Try to parse each line as JSON
+			var jsonEntry map[string]any
+			if err := json.Unmarshal([]byte(trimmedLine), &jsonEntry); err != nil {
+				// Skip invalid JSON lines (could be partial debug output)
+				if verbose {
+					fmt.Fprintf(os.Stderr, "Skipping invalid JSON line: %s\n", trimmedLine)
+				}
+				continue
+			}
+
+			logEntries = append(logEntries, jsonEntry)
+		}
+
+		if len(logEntries) == 0 {
+			if verbose {
+				fmt.Fprintf(os.Stderr, "No valid JSON entries found in Claude log\n")
+			}
+			return nil
+		}
+
+		if verbose {
+			fmt.Fprintf(os.Stderr, "Extracted %d JSON entries from mixed format Claude log\n", len(logEntries))
+		}
+	}
+
+	return logEntries
+}
+
 // parseToolCallsWithSequence extracts tool call information from Claude log content array and returns sequence
 func (e *ClaudeEngine) parseToolCallsWithSequence(contentArray []any, toolCallMap map[string]*ToolCallInfo) []string {
 	var sequence []string
@@ -363,11 +373,13 @@ func (e *ClaudeEngine) parseToolCallsWithSequence(contentArray []any, toolCallMa
 			prettifiedName := PrettifyToolName(nameStr)
 
 			// Special handling for bash - each invocation is unique
+			var bashCommand string
 			if nameStr == "Bash" {
 				if commandStr, ok := typeutil.LookupStringPath(contentMap, "input", "command"); ok {
 					// Create unique bash entry with command info, avoiding colons for
 					// filesystem-safe names in downstream summaries/artifacts.
 					prettifiedName = "bash_" + ShortenCommand(commandStr)
+					bashCommand = commandStr
 				}
 				// If command is missing or non-string, preserve the default "bash" fallback name.
 				// This occurs with partial/malformed tool_use payloads and keeps parsing robust.
@@ -383,19 +395,24 @@ func (e *ClaudeEngine) parseToolCallsWithSequence(contentArray []any, toolCallMa
 			}
 
 			// Initialize or update tool call info
-			if toolInfo, exists := toolCallMap[prettifiedName]; exists {
+			toolInfo, exists := toolCallMap[prettifiedName]
+			if exists {
 				toolInfo.CallCount++
 				if inputSize > toolInfo.MaxInputSize {
 					toolInfo.MaxInputSize = inputSize
 				}
 			} else {
-				toolCallMap[prettifiedName] = &ToolCallInfo{
+				toolInfo = &ToolCallInfo{
 					Name:          prettifiedName,
 					CallCount:     1,
 					MaxInputSize:  inputSize,
 					MaxOutputSize: 0, // Will be updated when we find tool results
 					MaxDuration:   0, // Will be updated when we find execution timing
 				}
+				toolCallMap[prettifiedName] = toolInfo
+			}
+			if bashCommand != "" {
+				toolInfo.Commands = append(toolInfo.Commands, bashCommand)
 			}
 		case "tool_result":
 			contentStr, ok := typeutil.LookupString(contentMap, "content")
@@ -435,3 +452,171 @@ func (e *ClaudeEngine) estimateInputSize(input any) int {
 	// Estimate token count (rough approximation: 1 token = ~4 characters)
 	return len(inputJSON) / 4
 }
+
+// ParseAgentEvents converts a Claude stream-json log into the normalized agent event stream
+func (e *ClaudeEngine) ParseAgentEvents(logContent string) []AgentEvent {
+	parser := newStreamJSONAgentEventParser(NewAgentEventStream(e.GetID()))
+	for _, entry := range e.parseClaudeLogEntries(logContent, false) {
+		parser.emitEntry(entry)
+	}
+	return parser.stream.Events()
+}
+
+// NewAgentEventParser returns a parser for a Claude stream-json log read line by line.
+// Unlike ParseAgentEvents it does not accept JSON arrays spanning several lines.
+func (e *ClaudeEngine) NewAgentEventParser() AgentEventParser {
+	return newStreamJSONAgentEventParser(NewAgentEventStream(e.GetID()))
+}
+
+// streamJSONAgentEventParser emits normalized events for stream-json entries
+// (system, assistant, user and result), the format written by Claude Code and
+// by the Copilot CLI session JSONL. Consecutive assistant entries that share a
+// message ID belong to the same turn.
+type streamJSONAgentEventParser struct {
+	stream        *AgentEventStream
+	toolNames     map[string]string // tool_use ID -> tool name
+	lastMessageID string
+}
+
+// newStreamJSONAgentEventParser creates a stream-json parser emitting into stream
+func newStreamJSONAgentEventParser(stream *AgentEventStream) *streamJSONAgentEventParser {
+	return &streamJSONAgentEventParser{stream: stream, toolNames: make(map[string]string)}
+}
+
+// ParseLine emits the events of a JSONL line, or of a single-line JSON array of entries
+func (p *streamJSONAgentEventParser) ParseLine(line string) {
+	trimmedLine := strings.TrimSpace(line)
+	if strings.HasPrefix(trimmedLine, "[") {
+		var entries []map[string]any
+		if err := json.Unmarshal([]byte(trimmedLine), &entries); err == nil {
+			for _, entry := range entries {
+				p.emitEntry(entry)
+			}
+		}
+		return
+	}
+	if entry, ok := parseStreamJSONLine(trimmedLine); ok {
+		p.emitEntry(entry)
+	}
+}
+
+// Pending returns the events emitted since the previous call
+func (p *streamJSONAgentEventParser) Pending() []AgentEvent {
+	return p.stream.Pending()
+}
+
+// parseStreamJSONLine decodes a stream-json entry from a trimmed log line
+func parseStreamJSONLine(trimmedLine string) (map[string]any, bool) {
+	if !strings.HasPrefix(trimmedLine, "{") {
+		return nil, false
+	}
+	var entry map[string]any
+	if err := json.Unmarshal([]byte(trimmedLine), &entry); err != nil {
+		return nil, false
+	}
+	return entry, true
+}
+
+// emitEntry emits the events of one stream-json entry
+func (p *streamJSONAgentEventParser) emitEntry(entry map[string]any) {
+	stream := p.stream
+	entryType, _ := typeutil.LookupString(entry, "type")
+	timestamp, _ := typeutil.LookupString(entry, "timestamp")
+
+	switch entryType {
+	case "system":
+		if model, ok := typeutil.LookupString(entry, "model"); ok {
+			stream.SetModel(model, timestamp)
+		}
+
+	case "assistant":
+		message, ok := entry["message"].(map[string]any)
+		if !ok {
+			return
+		}
+		messageID, _ := typeutil.LookupString(message, "id")
+		if !stream.InTurn() || messageID == "" || messageID != p.lastMessageID {
+			stream.StartTurn(timestamp)
+		}
+		p.lastMessageID = messageID
+		if model, ok := typeutil.LookupString(message, "model"); ok {
+			stream.SetModel(model, timestamp)
+		}
+
+		content, _ := message["content"].([]any)
+		for _, item := range content {
+			itemMap, ok := item.(map[string]any)
+			if !ok {
+				continue
+			}
+			itemType, _ := typeutil.LookupString(itemMap, "type")
+			switch itemType {
+			case "text":
+				text, _ := typeutil.LookupString(itemMap, "text")
+				if strings.TrimSpace(text) == "" {
+					continue
+				}
+				stream.Emit(AgentEvent{Type: AgentEventAssistantMessage, Timestamp: timestamp, Text: text, OutputSize: len(text)})
+			case "tool_use":
+				name, ok := typeutil.LookupString(itemMap, "name")
+				if !ok {
+					continue
+				}
+				toolName := PrettifyToolName(name)
+				toolID, _ := typeutil.LookupString(itemMap, "id")
+				if toolID != "" {
+					p.toolNames[toolID] = toolName
+				}
+				inputSize := 0
+				if input, exists := itemMap["input"]; exists {
+					if inputJSON, err := json.Marshal(input); err == nil {
+						inputSize = len(inputJSON)
+					}
+				}
+				stream.Emit(AgentEvent{Type: AgentEventToolCall, Timestamp: timestamp, Tool: toolName, ToolCallID: toolID, InputSize: inputSize})
+			}
+		}
+
+	case "user":
+		message, ok := entry["message"].(map[string]any)
+		if !ok {
+			return
+		}
+		content, _ := message["content"].([]any)
+		for _, item := range content {
+			itemMap, ok := item.(map[string]any)
+			if !ok || itemMap["type"] != "tool_result" {
+				continue
+			}
+			toolID, _ := typeutil.LookupString(itemMap, "tool_use_id")
+			outputSize := 0
+			if text, ok := itemMap["content"].(string); ok {
+				outputSize = len(text)
+			} else if raw, exists := itemMap["content"]; exists {
+				if rawJSON, err := json.Marshal(raw); err == nil {
+					outputSize = len(rawJSON)
+				}
+			}
+			isError, _ := itemMap["is_error"].(bool)
+			stream.Emit(AgentEvent{Type: AgentEventToolResult, Timestamp: timestamp, Tool: p.toolNames[toolID], ToolCallID: toolID, OutputSize: outputSize, IsError: isError})
+		}
+
+	case "result":
+		if usageMap, ok := entry["usage"].(map[string]any); ok {
+			if usage := agentEventUsageFromMap(usageMap); usage != nil {
+				usage.CostUSD = typeutil.ConvertToFloat(entry["total_cost_usd"])
+				stream.Emit(AgentEvent{Type: AgentEventTokenUsage, Timestamp: timestamp, Usage: usage})
+			}
+		}
+		subtype, _ := typeutil.LookupString(entry, "subtype")
+		isError, _ := entry["is_error"].(bool)
+		if isError || strings.HasPrefix(subtype, "error") {
+			text, _ := typeutil.LookupString(entry, "result")
+			if text == "" {
+				text = subtype
+			}
+			stream.Emit(AgentEvent{Type: AgentEventError, Timestamp: timestamp, Text: text, IsError: true})
+		}
+		stream.EndTurn(timestamp)
+	}
+}
@@ -164,16 +164,19 @@ func (e *CodexEngine) parseCodexToolCallsWithSequence(line string, toolCallMap m
 		uniqueBashName := "bash_" + ShortenCommand(execCommand)
 
 		// Initialize or update tool call info
-		if toolInfo, exists := toolCallMap[uniqueBashName]; exists {
+		toolInfo, exists := toolCallMap[uniqueBashName]
+		if exists {
 			toolInfo.CallCount++
 		} else {
-			toolCallMap[uniqueBashName] = &ToolCallInfo{
+			toolInfo = &ToolCallInfo{
 				Name:          uniqueBashName,
 				CallCount:     1,
 				MaxOutputSize: 0,
 				MaxDuration:   0, // Will be updated when duration is found
 			}
+			toolCallMap[uniqueBashName] = toolInfo
 		}
+		toolInfo.Commands = append(toolInfo.Commands, execCommand)
 
 		return uniqueBashName
 	}
@@ -381,3 +384,171 @@ func (e *CodexEngine) extractCodexTokenUsage(line string) int {
 func (e *CodexEngine) GetLogParserScriptId() string {
 	return "parse_codex_log"
 }
+
+// ParseAgentEvents converts a Codex log into the normalized agent event stream.
+// Thinking sections start turns, tool and exec lines become tool calls and the
+// success/failure lines that follow them become tool results.
+func (e *CodexEngine) ParseAgentEvents(logContent string) []AgentEvent {
+	parser := newCodexAgentEventParser(e)
+	for line := range strings.SplitSeq(logContent, "\n") {
+		parser.ParseLine(line)
+	}
+	parser.resolveResults(len(parser.results))
+	return parser.stream.Events()
+}
+
+// NewAgentEventParser returns a parser for a Codex log read line by line
+func (e *CodexEngine) NewAgentEventParser() AgentEventParser {
+	return newCodexAgentEventParser(e)
+}
+
+// codexAgentEventParser parses a Codex log line by line. A tool result's output
+// size comes from the JSON block after its result line, so events from the tool
+// result on are held back until that block is complete.
+type codexAgentEventParser struct {
+	engine            *CodexEngine
+	stream            *AgentEventStream
+	toolCallMap       map[string]*ToolCallInfo
+	inThinkingSection bool
+	lastToolName      string
+	results           []*codexResultOutput // tool results waiting for their output
+}
+
+// codexResultOutput collects the JSON block following a tool result line, the
+// line by line counterpart of extractOutputSizeFromResult
+type codexResultOutput struct {
+	event      int // index of the tool_result event in the stream
+	jsonLines  []string
+	inJSON     bool
+	braceCount int
+	done       bool
+}
+
+// add adds the next log line to the output until it is done
+func (o *codexResultOutput) add(line string) {
+	if o.done {
+		return
+	}
+	trimmedLine := strings.TrimSpace(line)
+	if !o.inJSON && trimmedLine == "{" {
+		o.inJSON = true
+		o.braceCount = 1
+		o.jsonLines = append(o.jsonLines, line)
+		return
+	}
+	if o.inJSON {
+		o.jsonLines = append(o.jsonLines, line)
+		o.braceCount += strings.Count(line, "{")
+		o.braceCount -= strings.Count(line, "}")
+		o.done = o.braceCount == 0
+		return
+	}
+	o.done = trimmedLine != ""
+}
+
+// newCodexAgentEventParser creates a Codex log parser
+func newCodexAgentEventParser(engine *CodexEngine) *codexAgentEventParser {
+	return &codexAgentEventParser{
+		engine:      engine,
+		stream:      NewAgentEventStream(engine.GetID()),
+		toolCallMap: make(map[string]*ToolCallInfo),
+	}
+}
+
+// ParseLine parses the next Codex log line
+func (p *codexAgentEventParser) ParseLine(line string) {
+	for _, result := range p.results {
+		result.add(line)
+	}
+	complete := 0
+	for complete < len(p.results) && p.results[complete].done {
+		complete++
+	}
+	p.resolveResults(complete)
+
+	trimmedLine := strings.TrimSpace(line)
+	if trimmedLine == "" {
+		return
+	}
+	stream := p.stream
+	timestamp := codexLineTimestamp(trimmedLine)
+
+	if model, ok := strings.CutPrefix(trimmedLine, "model: "); ok {
+		stream.SetModel(strings.TrimSpace(model), timestamp)
+		return
+	}
+
+	if strings.Contains(line, "] thinking") || trimmedLine == "thinking" {
+		if !p.inThinkingSection {
+			stream.StartTurn(timestamp)
+			p.inThinkingSection = true
+		}
+		return
+	}
+	if strings.Contains(line, "] tool") || strings.Contains(line, "] exec") || strings.Contains(line, "] codex") ||
+		strings.HasPrefix(trimmedLine, "tool ") || strings.HasPrefix(trimmedLine, "exec ") {
+		p.inThinkingSection = false
+	}
+
+	if toolName := p.engine.parseCodexToolCallsWithSequence(line, p.toolCallMap); toolName != "" {
+		if !stream.InTurn() {
+			stream.StartTurn(timestamp)
+		}
+		p.lastToolName = toolName
+		stream.Emit(AgentEvent{Type: AgentEventToolCall, Timestamp: timestamp, Tool: toolName})
+		return
+	}
+
+	if strings.Contains(line, "success in") || strings.Contains(line, "failure in") || strings.Contains(line, "failed in") {
+		p.results = append(p.results, &codexResultOutput{event: len(stream.events)})
+		stream.Emit(AgentEvent{
+			Type:      AgentEventToolResult,
+			Timestamp: timestamp,
+			Tool:      p.lastToolName,
+			IsError:   !strings.Contains(line, "success in"),
+		})
+		return
+	}
+
+	if tokenUsage := p.engine.extractCodexTokenUsage(line); tokenUsage > 0 {
+		stream.Emit(AgentEvent{Type: AgentEventTokenUsage, Timestamp: timestamp, Usage: &AgentEventUsage{TotalTokens: tokenUsage}})
+		return
+	}
+
+	if strings.Contains(line, "] ERROR") || strings.HasPrefix(trimmedLine, "ERROR") {
+		_, message, _ := strings.Cut(line, "ERROR")
+		stream.Emit(AgentEvent{Type: AgentEventError, Timestamp: timestamp, Text: strings.TrimLeft(message, ": "), IsError: true})
+	}
+}
+
+// resolveResults sets the output size of the first n waiting tool results
+func (p *codexAgentEventParser) resolveResults(n int) {
+	for _, result := range p.results[:n] {
+		if len(result.jsonLines) > 0 {
+			p.stream.events[result.event].OutputSize = p.engine.extractOutputSizeFromJSON(strings.Join(result.jsonLines, "\n"))
+		}
+	}
+	p.results = p.results[n:]
+}
+
+// Pending returns the events completed since the previous call, up to the first
+// tool result whose output is still being read
+func (p *codexAgentEventParser) Pending() []AgentEvent {
+	if len(p.results) > 0 {
+		return p.stream.pendingUntil(p.results[0].event)
+	}
+	return p.stream.Pending()
+}
+
+// codexLineTimestamp returns the bracketed timestamp prefix of an old-format Codex
+// log line ("[2025-08-31T12:37:33] ..."), or an empty string when there is none.
+func codexLineTimestamp(line string) string {
+	if !strings.HasPrefix(line, "[") {
+		return ""
+	}
+	timestamp, _, ok := strings.Cut(line[1:], "]")
+	if !ok {
+		return ""
+	}
+	return timestamp
+}
@@ -364,6 +364,7 @@ func TestCodexParseLogMetricsBashCommand(t *testing.T) {
 
 	toolCall := metrics.ToolCalls[0]
 	assert.True(t, strings.HasPrefix(toolCall.Name, "bash_"), "Tool name should start with bash_")
+	assert.Equal(t, []string{"ls -la"}, toolCall.Commands, "the full command should be kept")
 
 	// Verify output size was extracted
 	expectedSize := len("total 8\ndrwxr-xr-x  2 user group 4096 Aug 31 12:37 .\ndrwxr-xr-x 20 user group 4096 Aug 31 12:30 ..")
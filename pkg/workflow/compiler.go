@@ -175,6 +175,9 @@ func (c *Compiler) generateAndValidateYAML(workflowData *WorkflowData, markdownP
 		return "", nil, nil, err
 	}
 
+	// Warn about untrusted event data flowing indirectly into shell commands, prompts and safe outputs
+	c.reportTaintFlows(yamlContent, markdownPath, parsedWorkflow)
+
 	// Validate against GitHub Actions schema (unless skipped)
 	if needsSchemaCheck {
 		log.Print("Validating workflow against GitHub Actions schema")
@@ -212,8 +215,7 @@ func (c *Compiler) generateAndValidateYAML(workflowData *WorkflowData, markdownP
 		if err := c.validateContainerImages(workflowData); err != nil {
 			// Treat container image validation failures as warnings, not errors
 			// This is because validation may fail due to auth issues locally (e.g., private registries)
-			fmt.Fprintln(os.Stderr, formatCompilerMessage(markdownPath, "warning", fmt.Sprintf("container image validation failed: %v", err)))
-			c.IncrementWarningCount()
+			c.addWarningAt(markdownPath, fmt.Sprintf("container image validation failed: %v", err))
 		}
 
 		// Validate runtime packages (npx, uv)
@@ -234,8 +236,7 @@ func (c *Compiler) generateAndValidateYAML(workflowData *WorkflowData, markdownP
 			return "", nil, nil, formatCompilerError(markdownPath, "error", fmt.Sprintf("repository feature validation failed: %v", err), err)
 		}
 	} else if c.verbose {
-		fmt.Fprintln(os.Stderr, console.FormatWarningMessage("Schema validation available but skipped (use SetSkipValidation(false) to enable)"))
-		c.IncrementWarningCount()
+		c.addWarning("Schema validation available but skipped (use SetSkipValidation(false) to enable)")
 	}
 
 	return yamlContent, bodySecrets, bodyActions, nil
@@ -490,6 +491,11 @@ func (c *Compiler) CompileWorkflowData(workflowData *WorkflowData, markdownPath
 		return formatCompilerError(markdownPath, "error", "workflow validation: "+err.Error(), err)
 	}
 
+	// Enforce the organization policy (.github/aw/policy.yml) against the merged workflow data
+	if err := c.validateOrgPolicy(workflowData, markdownPath); err != nil {
+		return err
+	}
+
 	// Note: Markdown content size is now handled by splitting into multiple steps in generatePrompt
 	log.Printf("Workflow: %s, Tools: %d", workflowData.Name, len(workflowData.Tools))
 
@@ -519,8 +525,7 @@ func (c *Compiler) CompileWorkflowData(workflowData *WorkflowData, markdownPath
 		if enforceErr := EnforceSafeUpdate(oldManifest, bodySecrets, bodyActions, workflowData.Redirect); enforceErr != nil {
 			warningMsg := buildSafeUpdateWarningPrompt(enforceErr.Error())
 			c.AddSafeUpdateWarning(warningMsg)
-			fmt.Fprintln(os.Stderr, formatCompilerMessage(markdownPath, "warning", enforceErr.Error()))
-			c.IncrementWarningCount()
+			c.addWarningAt(markdownPath, enforceErr.Error())
 		}
 	}
 
@@ -55,6 +55,9 @@ func (c *Compiler) buildActivationJob(data *WorkflowData, preActivationJobCreate
 	compilerActivationJobLog.Print("Generating prompt in activation job")
 	c.generatePromptInActivationJob(&ctx.steps, data, preActivationJobCreated, ctx.customJobsBeforeActivation)
 	c.addActivationArtifactUploadStep(ctx)
+	// Wait for a slot in the repository-wide agent concurrency budget last, so that
+	// reactions and status comments are posted before the run is queued.
+	ctx.steps = append(ctx.steps, c.generateAgentBudgetStep(data)...)
 	if len(ctx.steps) == 0 {
 		ctx.steps = append(ctx.steps, "      - run: echo \"Activation success\"\n")
 	}
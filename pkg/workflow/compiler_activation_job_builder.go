@@ -487,7 +487,8 @@ func (c *Compiler) buildActivationPermissions(ctx *activationJobBuildContext) st
 	permsMap := map[PermissionScope]PermissionLevel{
 		PermissionContents: PermissionRead,
 	}
-	if !ctx.data.StaleCheckDisabled {
+	// The agent concurrency budget step lists workflow runs and their jobs
+	if !ctx.data.StaleCheckDisabled || c.hasAgentBudget() {
 		permsMap[PermissionActions] = PermissionRead
 	}
 	addActivationInteractionPermissionsMap(
@@ -233,6 +233,13 @@ func (c *Compiler) buildJobs(data *WorkflowData, markdownPath string) error {
 		return err
 	}
 
+	// Build the job that saves dependency caches after threat detection
+	if job := c.buildUpdateDependencyCacheJob(data); job != nil {
+		if err := c.jobManager.AddJob(job); err != nil {
+			return fmt.Errorf("failed to add update_dependency_cache job: %w", err)
+		}
+	}
+
 	// Final pass: ensure conclusion job depends on ALL remaining workflow jobs.
 	// This guarantees conclusion always runs last, even for custom user-defined jobs
 	// (e.g. post-issue, super_linter) that were not explicitly added to its needs.
@@ -3,13 +3,11 @@ package workflow
 import (
 	"fmt"
 	"maps"
-	"os"
 	"slices"
 	"sort"
 	"strconv"
 	"strings"
 
-	"github.com/github/gh-aw/pkg/console"
 	"github.com/github/gh-aw/pkg/constants"
 	"github.com/github/gh-aw/pkg/logger"
 )
@@ -189,8 +187,7 @@ func (c *Compiler) buildMainJob(data *WorkflowData, activationJobCreated bool) (
 						"this expression will silently evaluate to an empty string at runtime.",
 					builtinJobName,
 				)
-				fmt.Fprintln(os.Stderr, console.FormatWarningMessage(warningMsg))
-				c.IncrementWarningCount()
+				c.addWarning(warningMsg)
 			}
 		}
 	}
@@ -226,6 +223,13 @@ func (c *Compiler) buildMainJob(data *WorkflowData, activationJobCreated bool) (
 		compilerMainJobLog.Print("Added artifact_prefix output to agent job (workflow_call context)")
 	}
 
+	// Pass dependency cache keys to update_dependency_cache, which cannot hash the lockfiles itself
+	if IsDetectionJobEnabled(data.SafeOutputs) {
+		for _, cache := range data.DependencyCaches {
+			outputs[dependencyCacheKeyOutput(cache)] = fmt.Sprintf("${{ steps.%s.outputs.cache-primary-key }}", dependencyCacheStepID(cache))
+		}
+	}
+
 	// Add safe-output specific outputs if the workflow uses the safe-outputs feature
 	if data.SafeOutputs != nil {
 		outputs["output"] = "${{ steps.collect_output.outputs.output }}"
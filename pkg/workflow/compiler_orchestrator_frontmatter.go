@@ -7,7 +7,6 @@ import (
 	"path/filepath"
 	"strings"
 
-	"github.com/github/gh-aw/pkg/console"
 	"github.com/github/gh-aw/pkg/logger"
 	"github.com/github/gh-aw/pkg/parser"
 )
@@ -177,8 +176,7 @@ func (c *Compiler) parseFrontmatterSection(markdownPath string) (*frontmatterPar
 	// the compiler converts double quotes to single quotes automatically — but authors
 	// should fix the source to use single quotes to keep it consistent with the output.
 	for _, w := range detectDoubleQuotedExperimentComparisons(result.Markdown) {
-		fmt.Fprintln(os.Stderr, console.FormatWarningMessage(w))
-		c.IncrementWarningCount()
+		c.addWarning(w)
 	}
 
 	log.Printf("Frontmatter: %d chars, Markdown: %d chars", len(result.Frontmatter), len(result.Markdown))
@@ -2,11 +2,9 @@ package workflow
 
 import (
 	"fmt"
-	"os"
 	"sort"
 	"strings"
 
-	"github.com/github/gh-aw/pkg/console"
 	"github.com/github/gh-aw/pkg/logger"
 	"github.com/github/gh-aw/pkg/parser"
 )
@@ -35,7 +33,8 @@ type toolsProcessingResult struct {
 	safeOutputs           *SafeOutputsConfig
 	secretMasking         *SecretMaskingConfig
 	parsedFrontmatter     *FrontmatterConfig
-	hasExplicitGitHubTool bool // true if tools.github was explicitly configured in frontmatter
+	hasExplicitGitHubTool bool                       // true if tools.github was explicitly configured in frontmatter
+	importedSubAgents     []*parser.ImportedSubAgent // sub-agents imported through the sub-agents: field
 }
 
 // processToolsAndMarkdown processes tools configuration, runtimes, and markdown content.
@@ -63,10 +62,16 @@ func (c *Compiler) processToolsAndMarkdown(result *parser.FrontmatterResult, cle
 	}
 	orchestratorToolsLog.Printf("Effective markdown after stripping sub-agent sections: %d bytes", len(effectiveMarkdown))
 	orchestratorToolsLog.Printf("Extracted inline sub-agents: count=%d", len(subAgents))
+	importedSubAgents, err := c.resolveImportedSubAgents(result.Frontmatter, markdownDir)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateSubAgents(subAgents, importedSubAgents, agenticEngine); err != nil {
+		return nil, err
+	}
 	// Surface best-effort sub-agent frontmatter warnings collected during import BFS traversal.
 	for _, w := range importsResult.Warnings {
-		fmt.Fprintln(os.Stderr, console.FormatWarningMessage(w))
-		c.IncrementWarningCount()
+		c.addWarning(w)
 	}
 
 	// Extract SafeOutputs configuration early so we can use it when applying default tools
@@ -182,14 +187,12 @@ func (c *Compiler) processToolsAndMarkdown(result *parser.FrontmatterResult, cle
 
 	// Warn on deprecated APM configuration fields that are now ignored
 	if _, hasDependencies := result.Frontmatter["dependencies"]; hasDependencies {
-		fmt.Fprintln(os.Stderr, console.FormatWarningMessage("The 'dependencies' field is deprecated and no longer supported. Migrate to 'imports: - uses: shared/apm.md' to configure APM packages."))
-		c.IncrementWarningCount()
+		c.addWarning("The 'dependencies' field is deprecated and no longer supported. Migrate to 'imports: - uses: shared/apm.md' to configure APM packages.")
 	}
 	if importsVal, hasImports := result.Frontmatter["imports"]; hasImports {
 		if importsMap, ok := importsVal.(map[string]any); ok {
 			if _, hasAPMPackages := importsMap["apm-packages"]; hasAPMPackages {
-				fmt.Fprintln(os.Stderr, console.FormatWarningMessage("The 'imports.apm-packages' field is deprecated and no longer supported. Migrate to 'imports: - uses: shared/apm.md' to configure APM packages."))
-				c.IncrementWarningCount()
+				c.addWarning("The 'imports.apm-packages' field is deprecated and no longer supported. Migrate to 'imports: - uses: shared/apm.md' to configure APM packages.")
 			}
 		}
 	}
@@ -201,13 +204,22 @@ func (c *Compiler) processToolsAndMarkdown(result *parser.FrontmatterResult, cle
 		return nil, err
 	}
 
+	// Enforce the aw.json MCP trust policy (allowed publishers, pins, denied servers)
+	// An invalid aw.json is an error so that a broken trust policy is never silently ignored
+	repoConfig, err := c.loadRepoConfig()
+	if err != nil {
+		return nil, err
+	}
+	if err := validateMCPTrustPolicy(tools, repoConfig.TrustPolicy()); err != nil {
+		orchestratorToolsLog.Printf("MCP trust policy validation failed: %v", err)
+		return nil, err
+	}
+
 	if !agenticEngine.GetCapabilities().ToolsAllowlist {
 		// For engines that don't support tool allowlists (like custom engine), ignore tools section and provide warnings
-		fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Using experimental %s support (engine: %s)", agenticEngine.GetDisplayName(), agenticEngine.GetID())))
-		c.IncrementWarningCount()
+		c.addWarning(fmt.Sprintf("Using experimental %s support (engine: %s)", agenticEngine.GetDisplayName(), agenticEngine.GetID()))
 		if _, hasTools := result.Frontmatter["tools"]; hasTools {
-			fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("'tools' section ignored when using engine: %s (%s doesn't support MCP tool allow-listing)", agenticEngine.GetID(), agenticEngine.GetDisplayName())))
-			c.IncrementWarningCount()
+			c.addWarning(fmt.Sprintf("'tools' section ignored when using engine: %s (%s doesn't support MCP tool allow-listing)", agenticEngine.GetID(), agenticEngine.GetDisplayName()))
 		}
 		tools = map[string]any{}
 		// For now, we'll add a basic github tool (always uses docker MCP)
@@ -369,6 +381,7 @@ func (c *Compiler) processToolsAndMarkdown(result *parser.FrontmatterResult, cle
 		secretMasking:         secretMasking,
 		parsedFrontmatter:     parsedFrontmatter,
 		hasExplicitGitHubTool: hasExplicitGitHubTool,
+		importedSubAgents:     importedSubAgents,
 	}, nil
 }
 
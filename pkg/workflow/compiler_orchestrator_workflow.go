@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 
+	"github.com/github/gh-aw/pkg/constants"
 	"github.com/github/gh-aw/pkg/logger"
 	"github.com/github/gh-aw/pkg/parser"
 )
@@ -73,7 +74,9 @@ func (c *Compiler) ParseWorkflowFile(markdownPath string) (*WorkflowData, error)
 	workflowData.WorkflowID = GetWorkflowIDFromPath(cleanPath)
 
 	// Validate model alias map: identifier syntax, parameter values, glob-in-engine.model,
-	// alias key format, and circular references (V-MAF-001..006, V-MAF-010, V-MAF-011).
+	// alias key format, and circular references (V-MAF-001..006, V-MAF-010, V-MAF-011),
+	// then resolve engine.model and declared aliases against the engine's catalog
+	// (V-MAF-020, V-MAF-021).
 	{
 		var frontmatterModels map[string][]string
 		if toolsResult.parsedFrontmatter != nil {
@@ -91,6 +94,19 @@ func (c *Compiler) ParseWorkflowFile(markdownPath string) (*WorkflowData, error)
 		); err != nil {
 			return nil, err
 		}
+		var engineID string
+		if workflowData.EngineConfig != nil {
+			engineID = workflowData.EngineConfig.ID
+		}
+		if err := c.validateModelResolution(
+			engineID,
+			engineModel,
+			workflowData.ModelMappings,
+			frontmatterModels,
+			cleanPath,
+		); err != nil {
+			return nil, err
+		}
 	}
 
 	// Validate run-install-scripts setting (warning in non-strict mode, error in strict mode)
@@ -174,6 +190,11 @@ func (c *Compiler) ParseWorkflowFile(markdownPath string) (*WorkflowData, error)
 		return nil, formatCompilerError(cleanPath, "error", err.Error(), err)
 	}
 
+	// Detect runtimes from the repository's manifests (requires features.repository-runtimes).
+	// This must run after the features are extracted and before runtime setup steps and
+	// firewall domains are computed from workflowData.Runtimes.
+	c.applyRepositoryRuntimes(workflowData, markdownDir)
+
 	// Merge observability endpoints from imports with those from the main workflow.
 	// All OTLP endpoints from both sources are combined into an array, deduplicating
 	// by URL (main workflow endpoints take precedence). This allows multiple shared
@@ -451,6 +472,23 @@ func (c *Compiler) extractAdditionalConfigurations(
 		}
 	}
 
+	// The replay engine runs no model, so there is nothing to analyze the agent output
+	// with unless threat detection names another engine; treat it as engine: false.
+	if workflowData.SafeOutputs != nil && workflowData.SafeOutputs.ThreatDetection != nil &&
+		c.getThreatDetectionEngineID(workflowData) == string(constants.ReplayEngine) {
+		orchestratorWorkflowLog.Print("Disabling threat detection engine for the replay engine")
+		workflowData.SafeOutputs.ThreatDetection.EngineDisabled = true
+	}
+
+	// Replayed safe outputs repeat a recorded run's writes, so they are staged
+	// unless the workflow sets safe-outputs.staged explicitly.
+	if workflowData.SafeOutputs != nil && isReplayEngine(workflowData) {
+		if _, explicit := rawSafeOutputsMap["staged"]; !explicit {
+			orchestratorWorkflowLog.Print("Staging safe outputs for the replay engine")
+			workflowData.SafeOutputs.Staged = true
+		}
+	}
+
 	// Auto-inject create-issues if safe-outputs is configured but has no non-builtin outputs.
 	// This ensures every workflow with safe-outputs has at least one meaningful action handler.
 	applyDefaultCreateIssue(workflowData)
@@ -0,0 +1,81 @@
+package workflow
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/github/gh-aw/pkg/console"
+)
+
+// loadOrgPolicy loads and caches the organization policy from .github/aw/policy.yml.
+func (c *Compiler) loadOrgPolicy() (*OrgPolicy, error) {
+	if c.orgPolicyLoaded {
+		return c.orgPolicy, c.orgPolicyErr
+	}
+	c.orgPolicyLoaded = true
+	if c.gitRoot == "" {
+		return nil, nil
+	}
+	c.orgPolicy, c.orgPolicyErr = LoadOrgPolicy(c.gitRoot)
+	return c.orgPolicy, c.orgPolicyErr
+}
+
+// validateOrgPolicy evaluates the organization policy against the merged workflow data.
+// Error-severity violations fail compilation; warning-severity violations and waivers
+// that are about to expire are reported as warnings.
+func (c *Compiler) validateOrgPolicy(workflowData *WorkflowData, markdownPath string) error {
+	policy, err := c.loadOrgPolicy()
+	if err != nil {
+		policyPath := OrgPolicyPath
+		if c.gitRoot != "" {
+			policyPath = filepath.Join(c.gitRoot, OrgPolicyPath)
+		}
+		return formatCompilerError(policyPath, "error", err.Error(), err)
+	}
+	if policy == nil {
+		return nil
+	}
+
+	workflowName := strings.TrimSuffix(filepath.Base(markdownPath), ".md")
+	violations, waived := policy.Evaluate(workflowData, workflowName)
+	orgPolicyLog.Printf("Organization policy for %s: violations=%d, waived=%d", workflowName, len(violations), len(waived))
+
+	now := orgPolicyNow()
+	for _, waiver := range waived {
+		if remaining := waiver.expiresAt.Add(24 * time.Hour).Sub(now); remaining <= orgPolicyWaiverExpiryWarning {
+			c.addWarningAt(markdownPath, fmt.Sprintf("policy waiver for rule %s expires on %s (%s)", waiver.Rule, waiver.Expires, waiver.Justification))
+		}
+	}
+
+	frontmatterLines := strings.Split(workflowData.FrontmatterYAML, "\n")
+	collector := NewErrorCollector(false)
+	for _, violation := range violations {
+		message := fmt.Sprintf("policy violation [%s]: %s", violation.Rule.ID, violation.Message)
+		if violation.Rule.Description != "" {
+			message += " (" + violation.Rule.Description + ")"
+		}
+		if violation.ExpiredWaiver != nil {
+			message += fmt.Sprintf("; waiver expired on %s", violation.ExpiredWaiver.Expires)
+		}
+
+		line := findFrontmatterFieldLine(frontmatterLines, 2, orgPolicyFields[violation.Rule.Field].frontmatterKey)
+		if line == 0 {
+			line = 1
+		}
+
+		if violation.Rule.IsWarning() {
+			c.addDiagnostic(console.CompilerError{
+				Position: console.ErrorPosition{File: markdownPath, Line: line, Column: 1},
+				Type:     "warning",
+				Message:  message,
+			})
+			continue
+		}
+		if err := collector.Add(formatCompilerErrorWithPosition(markdownPath, line, 1, "error", message, nil)); err != nil {
+			return err
+		}
+	}
+	return collector.Error()
+}
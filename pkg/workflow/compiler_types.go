@@ -1,9 +1,11 @@
 package workflow
 
 import (
+	"fmt"
 	"os"
 
 	actionpins "github.com/github/gh-aw/pkg/actionpins"
+	"github.com/github/gh-aw/pkg/console"
 	"github.com/github/gh-aw/pkg/logger"
 	"github.com/github/gh-aw/pkg/parser"
 )
@@ -81,6 +83,7 @@ type Compiler struct {
 	engineCatalog           *EngineCatalog           // Catalog of engine definitions backed by the registry
 	fileTracker             FileCreationTracker      // Optional file tracker for tracking created files
 	warningCount            int                      // Number of warnings encountered during compilation
+	diagnostics             []console.CompilerError  // Warnings emitted during compilation, for machine-readable reports
 	stepOrderTracker        *StepOrderTracker        // Tracks step ordering for validation
 	actionCache             *ActionCache             // Shared cache for action pin resolutions across all workflows
 	actionResolver          *ActionResolver          // Shared resolver for action pins across all workflows
@@ -97,6 +100,9 @@ type Compiler struct {
 	repoConfig              *RepoConfig              // Cached repository-level aw.json config
 	repoConfigErr           error                    // Cached repo config load error
 	repoConfigLoaded        bool                     // True once repo config has been loaded (success or failure)
+	orgPolicy               *OrgPolicy               // Cached organization policy from .github/aw/policy.yml
+	orgPolicyErr            error                    // Cached organization policy load error
+	orgPolicyLoaded         bool                     // True once the organization policy has been loaded (success or failure)
 	contentOverride         string                   // If set, use this content instead of reading from disk (for Wasm/in-memory compilation)
 	skipHeader              bool                     // If true, skip ASCII art header in generated YAML (for Wasm/editor mode)
 	inlinePrompt            bool                     // If true, inline markdown content in YAML instead of using runtime-import macros (for Wasm builds)
@@ -285,9 +291,45 @@ func (c *Compiler) GetWarningCount() int {
 	return c.warningCount
 }
 
-// ResetWarningCount resets the warning counter to zero
+// ResetWarningCount resets the warning counter to zero and clears the recorded diagnostics
 func (c *Compiler) ResetWarningCount() {
 	c.warningCount = 0
+	c.diagnostics = nil
+}
+
+// GetDiagnostics returns the warnings emitted through addWarning, addWarningAt and
+// addDiagnostic since the last reset, in the order they were emitted
+func (c *Compiler) GetDiagnostics() []console.CompilerError {
+	return c.diagnostics
+}
+
+// addWarning prints a warning that is not tied to a file position, counts it and
+// records it as a diagnostic
+func (c *Compiler) addWarning(message string) {
+	fmt.Fprintln(os.Stderr, console.FormatWarningMessage(message))
+	c.recordDiagnostic(console.CompilerError{Type: "warning", Message: message})
+}
+
+// addWarningAt prints a warning attributed to filePath, counts it and records it as a diagnostic
+func (c *Compiler) addWarningAt(filePath string, message string) {
+	c.addDiagnostic(console.CompilerError{
+		Position: console.ErrorPosition{File: filePath},
+		Type:     "warning",
+		Message:  message,
+	})
+}
+
+// addDiagnostic prints a positioned warning, counts it and records it as a diagnostic
+func (c *Compiler) addDiagnostic(diagnostic console.CompilerError) {
+	fmt.Fprintln(os.Stderr, console.FormatError(diagnostic))
+	c.recordDiagnostic(diagnostic)
+}
+
+// recordDiagnostic counts a warning and records it without printing it, for warnings
+// that are displayed later (such as schedule warnings)
+func (c *Compiler) recordDiagnostic(diagnostic console.CompilerError) {
+	c.warningCount++
+	c.diagnostics = append(c.diagnostics, diagnostic)
 }
 
 // SetWorkflowIdentifier sets the identifier for the current workflow being compiled
@@ -440,24 +482,25 @@ type SkipIfCheckFailingConfig struct {
 }
 type WorkflowData struct {
 	Name                           string
-	WorkflowID                     string         // workflow identifier derived from markdown filename (basename without extension)
-	TrialMode                      bool           // whether the workflow is running in trial mode
-	TrialLogicalRepo               string         // target repository slug for trial mode (owner/repo)
-	FrontmatterName                string         // name field from frontmatter (for code scanning alert driver default)
-	FrontmatterEmoji               string         // emoji field from frontmatter (for display in footers and UI)
-	FrontmatterYAML                string         // raw frontmatter YAML content (rendered as comment in lock file for reference)
-	FrontmatterHash                string         // SHA-256 hash of frontmatter (computed before job building, used to derive stable heredoc delimiters)
-	RawMarkdown                    string         // raw markdown body before include expansion, used for frontmatter hash computation without re-reading the file
-	Description                    string         // optional description rendered as comment in lock file
-	Source                         string         // optional source field (owner/repo@ref/path) rendered as comment in lock file
-	Redirect                       string         // optional redirect field describing a moved workflow location
-	TrackerID                      string         // optional tracker identifier for created assets (min 8 chars, alphanumeric + hyphens/underscores)
-	ImportedFiles                  []string       // list of files imported via imports field (rendered as comment in lock file)
-	ImportedMarkdown               string         // Only imports WITH inputs (for compile-time substitution)
-	ImportPaths                    []string       // Import file paths for runtime-import macro generation (imports without inputs)
-	MainWorkflowMarkdown           string         // main workflow markdown without imports (for runtime-import)
-	IncludedFiles                  []string       // list of files included via @include directives (rendered as comment in lock file)
-	ImportInputs                   map[string]any // input values from imports with inputs (for github.aw.inputs.* substitution)
+	WorkflowID                     string                     // workflow identifier derived from markdown filename (basename without extension)
+	TrialMode                      bool                       // whether the workflow is running in trial mode
+	TrialLogicalRepo               string                     // target repository slug for trial mode (owner/repo)
+	FrontmatterName                string                     // name field from frontmatter (for code scanning alert driver default)
+	FrontmatterEmoji               string                     // emoji field from frontmatter (for display in footers and UI)
+	FrontmatterYAML                string                     // raw frontmatter YAML content (rendered as comment in lock file for reference)
+	FrontmatterHash                string                     // SHA-256 hash of frontmatter (computed before job building, used to derive stable heredoc delimiters)
+	RawMarkdown                    string                     // raw markdown body before include expansion, used for frontmatter hash computation without re-reading the file
+	Description                    string                     // optional description rendered as comment in lock file
+	Source                         string                     // optional source field (owner/repo@ref/path) rendered as comment in lock file
+	Redirect                       string                     // optional redirect field describing a moved workflow location
+	TrackerID                      string                     // optional tracker identifier for created assets (min 8 chars, alphanumeric + hyphens/underscores)
+	ImportedFiles                  []string                   // list of files imported via imports field (rendered as comment in lock file)
+	ImportedMarkdown               string                     // Only imports WITH inputs (for compile-time substitution)
+	ImportPaths                    []string                   // Import file paths for runtime-import macro generation (imports without inputs)
+	MainWorkflowMarkdown           string                     // main workflow markdown without imports (for runtime-import)
+	IncludedFiles                  []string                   // list of files included via @include directives (rendered as comment in lock file)
+	ImportedSubAgents              []*parser.ImportedSubAgent // sub-agents imported via the sub-agents field (appended to the prompt)
+	ImportInputs                   map[string]any             // input values from imports with inputs (for github.aw.inputs.* substitution)
 	On                             string
 	Permissions                    string
 	Network                        string // top-level network permissions configuration
@@ -531,6 +574,8 @@ type WorkflowData struct {
 	CacheMemoryConfig              *CacheMemoryConfig              // parsed cache-memory configuration
 	RepoMemoryConfig               *RepoMemoryConfig               // parsed repo-memory configuration
 	Runtimes                       map[string]any                  // runtime version overrides from frontmatter
+	RepositoryRuntimes             []RepositoryRuntime             // runtimes detected from repository manifests and added to Runtimes (features.repository-runtimes)
+	DependencyCaches               []DependencyCache               // dependency caches detected from repository lockfiles (features.repository-runtimes)
 	ToolsTimeout                   string                          // timeout for tool/MCP operations: numeric string (seconds) or GitHub Actions expression (empty = use engine default)
 	ToolsStartupTimeout            string                          // timeout for MCP server startup: numeric string (seconds) or GitHub Actions expression (empty = use engine default)
 	Features                       map[string]any                  // feature flags and configuration options from frontmatter (supports bool and string values)
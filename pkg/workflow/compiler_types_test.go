@@ -0,0 +1,29 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"testing"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompilerDiagnostics(t *testing.T) {
+	compiler := NewCompiler()
+
+	compiler.addWarning("unpositioned warning")
+	compiler.addWarningAt("workflow.md", "file warning")
+	compiler.addScheduleWarning("schedule warning")
+
+	assert.Equal(t, 3, compiler.GetWarningCount(), "every warning should be counted")
+	assert.Equal(t, []console.CompilerError{
+		{Type: "warning", Message: "unpositioned warning"},
+		{Type: "warning", Message: "file warning", Position: console.ErrorPosition{File: "workflow.md"}},
+		{Type: "warning", Message: "schedule warning"},
+	}, compiler.GetDiagnostics(), "every warning should be recorded in order")
+
+	compiler.ResetWarningCount()
+	assert.Zero(t, compiler.GetWarningCount(), "count should be reset")
+	assert.Empty(t, compiler.GetDiagnostics(), "diagnostics should be reset")
+}
@@ -35,8 +35,7 @@ func (c *Compiler) validateExpressions(workflowData *WorkflowData, markdownPath
 		// so they are counted and consistently formatted with all other warnings.
 		for _, w := range subAgentWarnings {
 			expressionValidationLog.Printf("%s", w)
-			fmt.Fprintln(os.Stderr, console.FormatWarningMessage(w))
-			c.IncrementWarningCount()
+			c.addWarning(w)
 		}
 		if err != nil {
 			return formatCompilerError(markdownPath, "error", err.Error(), err)
@@ -117,8 +116,7 @@ func (c *Compiler) validateToolConfiguration(workflowData *WorkflowData, markdow
 		if c.strictMode {
 			return formatCompilerError(markdownPath, "error", err.Error(), err)
 		}
-		fmt.Fprintln(os.Stderr, formatCompilerMessage(markdownPath, "warning", err.Error()))
-		c.IncrementWarningCount()
+		c.addWarningAt(markdownPath, err.Error())
 	}
 
 	// Validate safe-outputs allowed-domains configuration
@@ -241,24 +239,20 @@ func (c *Compiler) validateToolConfiguration(workflowData *WorkflowData, markdow
 	if workflowData.Concurrency != "" &&
 		strings.Contains(workflowData.Concurrency, "cancel-in-progress: true") &&
 		hasBotSelfCancelRisk(workflowData) {
-		fmt.Fprintln(os.Stderr, formatCompilerMessage(markdownPath, "warning",
-			"Custom workflow-level concurrency with cancel-in-progress: true may cause self-cancellation.\n"+
-				"safe-outputs.github-app can post comments that re-trigger this workflow via issue_comment,\n"+
-				"and those passive bot-authored runs can collide with the primary run's concurrency group.\n"+
-				"Add `contains(github.actor, '[bot]') && github.run_id ||` at the start of your concurrency\n"+
-				"group expression to route bot-triggered runs to a unique key and prevent self-cancellation.\n"+
-				"See: https://gh.io/gh-aw/reference/concurrency for details."))
-		c.IncrementWarningCount()
+		c.addWarningAt(markdownPath, "Custom workflow-level concurrency with cancel-in-progress: true may cause self-cancellation.\n"+
+			"safe-outputs.github-app can post comments that re-trigger this workflow via issue_comment,\n"+
+			"and those passive bot-authored runs can collide with the primary run's concurrency group.\n"+
+			"Add `contains(github.actor, '[bot]') && github.run_id ||` at the start of your concurrency\n"+
+			"group expression to route bot-triggered runs to a unique key and prevent self-cancellation.\n"+
+			"See: https://gh.io/gh-aw/reference/concurrency for details.")
 	}
 
 	// Emit warning for sandbox.agent: false (disables agent sandbox firewall)
 	if isAgentSandboxDisabled(workflowData) {
-		fmt.Fprintln(os.Stderr, formatCompilerMessage(markdownPath, "warning",
-			"Agent sandbox disabled (sandbox.agent: false). This removes firewall protection. "+
-				"The AI agent will have direct network access without firewall filtering. "+
-				"The MCP gateway remains enabled. Only use this for testing or in controlled "+
-				"environments where you trust the AI agent completely."))
-		c.IncrementWarningCount()
+		c.addWarningAt(markdownPath, "Agent sandbox disabled (sandbox.agent: false). This removes firewall protection. "+
+			"The AI agent will have direct network access without firewall filtering. "+
+			"The MCP gateway remains enabled. Only use this for testing or in controlled "+
+			"environments where you trust the AI agent completely.")
 	}
 
 	// Validate: threat detection requires sandbox.agent to be enabled (detection runs inside AWF)
@@ -273,52 +267,44 @@ func (c *Compiler) validateToolConfiguration(workflowData *WorkflowData, markdow
 		workflowData.SafeOutputs.AssignToAgent != nil &&
 		workflowData.SafeOutputs.GitHubApp != nil &&
 		workflowData.SafeOutputs.AssignToAgent.GitHubToken == "" {
-		fmt.Fprintln(os.Stderr, console.FormatWarningMessage(
-			"assign-to-agent does not support GitHub App tokens. "+
-				"The Copilot assignment API requires a fine-grained PAT. "+
-				"The token fallback chain (GH_AW_AGENT_TOKEN || GH_AW_GITHUB_TOKEN || GITHUB_TOKEN) will be used automatically. "+
-				"Add github-token: to your assign-to-agent config to specify a different token."))
-		c.IncrementWarningCount()
+		c.addWarning(
+			"assign-to-agent does not support GitHub App tokens. " +
+				"The Copilot assignment API requires a fine-grained PAT. " +
+				"The token fallback chain (GH_AW_AGENT_TOKEN || GH_AW_GITHUB_TOKEN || GITHUB_TOKEN) will be used automatically. " +
+				"Add github-token: to your assign-to-agent config to specify a different token.")
 	}
 
 	// Emit experimental warning for rate-limiting feature
 	if workflowData.RateLimit != nil {
-		fmt.Fprintln(os.Stderr, console.FormatWarningMessage("Using experimental feature: rate limiting"))
-		c.IncrementWarningCount()
+		c.addWarning("Using experimental feature: rate limiting")
 	}
 
 	// Emit experimental warning for dispatch_repository feature
 	if workflowData.SafeOutputs != nil && workflowData.SafeOutputs.DispatchRepository != nil {
-		fmt.Fprintln(os.Stderr, console.FormatWarningMessage("Using experimental feature: dispatch_repository"))
-		c.IncrementWarningCount()
+		c.addWarning("Using experimental feature: dispatch_repository")
 	}
 
 	// Emit experimental warning for merge-pull-request feature
 	if workflowData.SafeOutputs != nil && workflowData.SafeOutputs.MergePullRequest != nil {
-		fmt.Fprintln(os.Stderr, console.FormatWarningMessage("Using experimental feature: merge-pull-request"))
-		c.IncrementWarningCount()
+		c.addWarning("Using experimental feature: merge-pull-request")
 	}
 
 	// Emit experimental warning for experiments feature
 	if len(workflowData.Experiments) > 0 {
-		fmt.Fprintln(os.Stderr, console.FormatWarningMessage("Using experimental feature: experiments"))
-		c.IncrementWarningCount()
+		c.addWarning("Using experimental feature: experiments")
 	}
 	if shouldWarnSparseInteractionCells(workflowData) {
-		fmt.Fprintln(os.Stderr, console.FormatWarningMessage(
-			"experiments: potential sparse interaction cells detected (multiple active experiments with weighted traffic). "+
-				"Reporting should include factorial K1×K2 cell diagnostics before recommending promotion."))
-		c.IncrementWarningCount()
+		c.addWarning(
+			"experiments: potential sparse interaction cells detected (multiple active experiments with weighted traffic). " +
+				"Reporting should include factorial K1×K2 cell diagnostics before recommending promotion.")
 	}
 
 	// Emit experimental warning for centralized slash-command routing strategy
 	if workflowData.CommandCentralized {
-		fmt.Fprintln(os.Stderr, console.FormatWarningMessage("Using experimental feature: slash_command.strategy: centralized"))
-		c.IncrementWarningCount()
+		c.addWarning("Using experimental feature: slash_command.strategy: centralized")
 	}
 	if workflowData.LabelCommandDecentralized {
-		fmt.Fprintln(os.Stderr, console.FormatWarningMessage("Using experimental feature: label_command.strategy: decentralized"))
-		c.IncrementWarningCount()
+		c.addWarning("Using experimental feature: label_command.strategy: decentralized")
 	}
 
 	// Warn when slash_command and bots are both configured: if a bot listed in bots: posts
@@ -326,13 +312,11 @@ func (c *Compiler) validateToolConfiguration(workflowData *WorkflowData, markdow
 	// check_command_position check will pass and the bot will trigger the workflow —
 	// occupying the concurrency slot and potentially blocking a simultaneous manual invocation.
 	if len(workflowData.Command) > 0 && len(workflowData.Bots) > 0 {
-		fmt.Fprintln(os.Stderr, formatCompilerMessage(markdownPath, "warning",
-			"Both slash_command and bots triggers are configured. If a bot listed in bots: "+
-				"posts a comment that starts with the slash command text (e.g., /command-name), "+
-				"it will trigger the workflow and occupy the concurrency slot, potentially "+
-				"blocking simultaneous manual invocations. To ensure the workflow only runs on "+
-				"explicit user commands, remove the 'bots:' field."))
-		c.IncrementWarningCount()
+		c.addWarningAt(markdownPath, "Both slash_command and bots triggers are configured. If a bot listed in bots: "+
+			"posts a comment that starts with the slash command text (e.g., /command-name), "+
+			"it will trigger the workflow and occupy the concurrency slot, potentially "+
+			"blocking simultaneous manual invocations. To ensure the workflow only runs on "+
+			"explicit user commands, remove the 'bots:' field.")
 	}
 
 	// Inform users when this workflow is a redirect stub for updates.
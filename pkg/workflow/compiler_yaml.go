@@ -162,7 +162,7 @@ func (c *Compiler) generateWorkflowHeader(yaml *strings.Builder, data *WorkflowD
 		}
 	}
 
-	if len(visibleImports) > 0 || len(data.IncludedFiles) > 0 {
+	if len(visibleImports) > 0 || len(data.IncludedFiles) > 0 || len(data.ImportedSubAgents) > 0 {
 		yaml.WriteString("#\n")
 		yaml.WriteString("# Resolved workflow manifest:\n")
 
@@ -176,6 +176,13 @@ func (c *Compiler) generateWorkflowHeader(yaml *strings.Builder, data *WorkflowD
 			}
 		}
 
+		if len(data.ImportedSubAgents) > 0 {
+			yaml.WriteString("#   Sub-agents:\n")
+			for _, agent := range data.ImportedSubAgents {
+				fmt.Fprintf(yaml, "#     - %s\n", filepath.ToSlash(stringutil.StripANSI(agent.Spec)))
+			}
+		}
+
 		if len(data.IncludedFiles) > 0 {
 			yaml.WriteString("#   Includes:\n")
 			for _, file := range data.IncludedFiles {
@@ -608,6 +615,15 @@ func (c *Compiler) generatePrompt(yaml *strings.Builder, data *WorkflowData, pre
 		userPromptChunks = append(userPromptChunks, runtimeImportMacro)
 	}
 
+	// Step 3: Append imported sub-agents after the main markdown as ## agent: blocks.
+	// interpolate_prompt.cjs extracts them together with the inline sub-agents.
+	if len(data.ImportedSubAgents) > 0 {
+		subAgentChunks, subAgentMappings := importedSubAgentPromptChunks(data.ImportedSubAgents)
+		userPromptChunks = append(userPromptChunks, subAgentChunks...)
+		expressionMappings = append(expressionMappings, subAgentMappings...)
+		compilerYamlLog.Printf("Appended %d imported sub-agents in %d chunks", len(data.ImportedSubAgents), len(subAgentChunks))
+	}
+
 	// Enhance entity number expressions with || inputs.item_number fallback when the
 	// workflow has a workflow_dispatch trigger with item_number (generated by the label
 	// trigger shorthand). This is applied after all expression mappings (including inline
@@ -804,7 +820,7 @@ func (c *Compiler) generateCreateAwInfo(yaml *strings.Builder, data *WorkflowDat
 	} else {
 		// Use the engine's default model as fallback when neither explicit model nor
 		// model variable is configured, so the run details show "auto" rather than "(none)".
-		defaultModel := getDefaultAgentModel(engineID)
+		defaultModel := DefaultAgentModel(engineID)
 		if defaultModel != "" {
 			fmt.Fprintf(yaml, "          GH_AW_INFO_MODEL: ${{ vars.%s || '%s' }}\n", modelEnvVar, defaultModel)
 		} else {
@@ -3,10 +3,8 @@ package workflow
 import (
 	"encoding/json"
 	"fmt"
-	"os"
 	"strings"
 
-	"github.com/github/gh-aw/pkg/console"
 	"github.com/github/gh-aw/pkg/constants"
 )
 
@@ -284,6 +282,9 @@ func (c *Compiler) generateRuntimeAndWorkspaceSetupSteps(yaml *strings.Builder,
 	compilerYamlLog.Printf("Generating cache steps for workflow")
 	generateCacheSteps(yaml, data, c.verbose)
 
+	// Add dependency cache steps for package managers detected in the repository
+	generateDependencyCacheSteps(yaml, data)
+
 	// Add cache-memory steps if cache-memory configuration is present
 	compilerYamlLog.Printf("Generating cache-memory steps for workflow")
 	generateCacheMemorySteps(yaml, data)
@@ -758,6 +759,10 @@ func (c *Compiler) generatePostAgentCollectionAndUpload(yaml *strings.Builder, d
 	// This ensures artifacts are uploaded after the agent has finished modifying the cache
 	generateCacheMemoryArtifactUpload(yaml, data, c.getActionPin)
 
+	// Add dependency cache artifact upload (after agent execution)
+	// update_dependency_cache saves these caches once threat detection passes
+	generateDependencyCacheArtifactUpload(yaml, data, c.getActionPin)
+
 	// Add safe-outputs assets artifact upload (after agent execution)
 	// This creates a separate artifact for assets that will be downloaded by upload_assets job
 	generateSafeOutputsAssetsArtifactUpload(yaml, data, c.getActionPin)
@@ -1082,8 +1087,7 @@ func (c *Compiler) sanitizeAndWarnCustomSteps(customSteps string) string {
 		return customSteps
 	}
 	for _, w := range warnings {
-		fmt.Fprintln(os.Stderr, console.FormatWarningMessage(w))
-		c.IncrementWarningCount()
+		c.addWarning(w)
 	}
 	return sanitized
 }
@@ -3,11 +3,9 @@ package workflow
 import (
 	"fmt"
 	"maps"
-	"os"
 	"slices"
 	"strings"
 
-	"github.com/github/gh-aw/pkg/console"
 	"github.com/github/gh-aw/pkg/constants"
 	"github.com/github/gh-aw/pkg/logger"
 	"github.com/goccy/go-yaml"
@@ -109,8 +107,7 @@ func (c *Compiler) renderStepFromMap(out *strings.Builder, step map[string]any,
 	// for every expression that is moved so that authors know their script was changed.
 	if sanitized, warnings, changed := sanitizeRunStepExpressions(step); changed {
 		for _, w := range warnings {
-			fmt.Fprintln(os.Stderr, console.FormatWarningMessage(w))
-			c.IncrementWarningCount()
+			c.addWarning(w)
 		}
 		step = sanitized
 	}
@@ -44,6 +44,7 @@ func NewCopilotEngine() *CopilotEngine {
 				MaxContinuations: true,  // Copilot CLI supports --autopilot with --max-autopilot-continues
 				WebSearch:        false, // Copilot CLI does not have built-in web-search support
 				BareMode:         true,  // Copilot CLI supports --no-custom-instructions
+				SubAgentTools:    true,  // Copilot custom agents accept a tools list in their frontmatter
 			},
 			dedicatedLLMGatewayPort: constants.CopilotLLMGatewayPort,
 		},
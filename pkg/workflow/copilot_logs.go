@@ -457,3 +457,202 @@ func (e *CopilotEngine) GetLogParserScriptId() string {
 func (e *CopilotEngine) GetLogFileForParsing() string {
 	return "/tmp/gh-aw/sandbox/agent/logs/"
 }
+
+// ParseAgentEvents converts Copilot CLI logs into the normalized agent event stream.
+// Session JSONL uses the stream-json format shared with Claude; debug logs are parsed
+// per API response block, with "Executing tool:" lines as a fallback for tool calls
+// when the response block lists none.
+func (e *CopilotEngine) ParseAgentEvents(logContent string) []AgentEvent {
+	stream := NewAgentEventStream(e.GetID())
+
+	var sessionEntries []map[string]any
+	for line := range strings.SplitSeq(logContent, "\n") {
+		if entry, ok := parseStreamJSONLine(strings.TrimSpace(line)); ok {
+			sessionEntries = append(sessionEntries, entry)
+		}
+	}
+	if len(sessionEntries) > 0 {
+		copilotLogsLog.Printf("Emitting agent events from %d session JSONL entries", len(sessionEntries))
+		parser := newStreamJSONAgentEventParser(stream)
+		for _, entry := range sessionEntries {
+			parser.emitEntry(entry)
+		}
+		return stream.Events()
+	}
+
+	parser := newCopilotDebugAgentEventParser(e, stream)
+	for line := range strings.SplitSeq(logContent, "\n") {
+		parser.ParseLine(line)
+	}
+	parser.flushBlock()
+
+	return stream.Events()
+}
+
+// NewAgentEventParser returns a parser for Copilot CLI logs read line by line.
+// Lines are parsed as a debug log until the first session JSONL entry, and as
+// session JSONL from then on; ParseAgentEvents instead picks one format for the
+// whole log.
+func (e *CopilotEngine) NewAgentEventParser() AgentEventParser {
+	return &copilotAgentEventParser{debug: newCopilotDebugAgentEventParser(e, NewAgentEventStream(e.GetID()))}
+}
+
+// copilotAgentEventParser parses a Copilot CLI log line by line, switching from
+// the debug log format to session JSONL once a session entry is seen
+type copilotAgentEventParser struct {
+	debug   *copilotDebugAgentEventParser
+	session *streamJSONAgentEventParser
+}
+
+// ParseLine parses the next line in the detected log format
+func (p *copilotAgentEventParser) ParseLine(line string) {
+	if p.session != nil {
+		p.session.ParseLine(line)
+		return
+	}
+	entry, ok := parseStreamJSONLine(strings.TrimSpace(line))
+	if !ok {
+		p.debug.ParseLine(line)
+		return
+	}
+	copilotLogsLog.Print("Session JSONL entry found, parsing the rest of the log as session JSONL")
+	p.debug.flushBlock()
+	p.session = newStreamJSONAgentEventParser(p.debug.stream)
+	p.session.emitEntry(entry)
+}
+
+// Pending returns the events completed since the previous call
+func (p *copilotAgentEventParser) Pending() []AgentEvent {
+	return p.debug.stream.Pending()
+}
+
+// copilotDebugAgentEventParser parses a Copilot CLI debug log line by line. The
+// events of an API response block are emitted once the line after it is seen.
+type copilotDebugAgentEventParser struct {
+	engine           *CopilotEngine
+	stream           *AgentEventStream
+	inDataBlock      bool
+	currentJSONLines []string
+	blockTimestamp   string
+	blockToolCalls   int
+}
+
+// newCopilotDebugAgentEventParser creates a debug log parser emitting into stream
+func newCopilotDebugAgentEventParser(engine *CopilotEngine, stream *AgentEventStream) *copilotDebugAgentEventParser {
+	return &copilotDebugAgentEventParser{engine: engine, stream: stream}
+}
+
+// ParseLine parses the next debug log line
+func (p *copilotDebugAgentEventParser) ParseLine(line string) {
+	if strings.TrimSpace(line) == "" {
+		return
+	}
+	timestamp, _, _ := strings.Cut(line, " [")
+
+	if strings.Contains(line, "[DEBUG] data:") {
+		p.flushBlock()
+		p.stream.StartTurn(timestamp)
+		p.inDataBlock = true
+		p.blockTimestamp = timestamp
+		p.blockToolCalls = 0
+		return
+	}
+
+	if p.inDataBlock {
+		_, after, hasPrefix := strings.Cut(line, "[DEBUG]")
+		if !hasPrefix {
+			p.currentJSONLines = append(p.currentJSONLines, line)
+			return
+		}
+		cleanLine := strings.TrimSpace(after)
+		if strings.HasPrefix(cleanLine, "{") || strings.HasPrefix(cleanLine, "}") ||
+			strings.HasPrefix(cleanLine, "[") || strings.HasPrefix(cleanLine, "]") ||
+			strings.HasPrefix(cleanLine, "\"") {
+			p.currentJSONLines = append(p.currentJSONLines, cleanLine)
+			return
+		}
+		p.flushBlock()
+	}
+
+	if _, toolName, ok := strings.Cut(line, "Executing tool:"); ok && p.blockToolCalls == 0 {
+		if toolName = strings.TrimSpace(toolName); toolName != "" {
+			p.stream.Emit(AgentEvent{Type: AgentEventToolCall, Timestamp: timestamp, Tool: PrettifyToolName(toolName)})
+		}
+		return
+	}
+
+	if _, message, ok := strings.Cut(line, "[ERROR]"); ok {
+		p.stream.Emit(AgentEvent{Type: AgentEventError, Timestamp: timestamp, Text: strings.TrimSpace(message), IsError: true})
+	}
+}
+
+// flushBlock emits the events of the API response block being collected, if any
+func (p *copilotDebugAgentEventParser) flushBlock() {
+	if len(p.currentJSONLines) > 0 {
+		p.blockToolCalls += p.engine.emitDebugBlockAgentEvents(p.stream, strings.Join(p.currentJSONLines, "\n"), p.blockTimestamp)
+	}
+	p.inDataBlock = false
+	p.currentJSONLines = nil
+}
+
+// emitDebugBlockAgentEvents emits the model, message, tool call and usage events of a
+// single Copilot debug log API response block and returns the number of tool calls.
+func (e *CopilotEngine) emitDebugBlockAgentEvents(stream *AgentEventStream, jsonStr string, timestamp string) int {
+	var data map[string]any
+	if err := json.Unmarshal([]byte(jsonStr), &data); err != nil {
+		copilotLogsLog.Printf("Skipping unparseable debug block for agent events: %v", err)
+		return 0
+	}
+
+	if model, ok := data["model"].(string); ok {
+		stream.SetModel(model, timestamp)
+	}
+
+	var messages []map[string]any
+	if choices, ok := data["choices"].([]any); ok {
+		for _, choice := range choices {
+			if choiceMap, ok := choice.(map[string]any); ok {
+				if message, ok := choiceMap["message"].(map[string]any); ok {
+					messages = append(messages, message)
+				}
+			}
+		}
+	}
+	if message, ok := data["message"].(map[string]any); ok {
+		messages = append(messages, message)
+	}
+
+	toolCalls := 0
+	for _, message := range messages {
+		if text, ok := message["content"].(string); ok && strings.TrimSpace(text) != "" {
+			stream.Emit(AgentEvent{Type: AgentEventAssistantMessage, Timestamp: timestamp, Text: text, OutputSize: len(text)})
+		}
+		calls, _ := message["tool_calls"].([]any)
+		for _, call := range calls {
+			callMap, ok := call.(map[string]any)
+			if !ok {
+				continue
+			}
+			function, ok := callMap["function"].(map[string]any)
+			if !ok {
+				continue
+			}
+			name, ok := function["name"].(string)
+			if !ok {
+				continue
+			}
+			arguments, _ := function["arguments"].(string)
+			callID, _ := callMap["id"].(string)
+			stream.Emit(AgentEvent{Type: AgentEventToolCall, Timestamp: timestamp, Tool: PrettifyToolName(name), ToolCallID: callID, InputSize: len(arguments)})
+			toolCalls++
+		}
+	}
+
+	if usageMap, ok := data["usage"].(map[string]any); ok {
+		if usage := agentEventUsageFromMap(usageMap); usage != nil {
+			stream.Emit(AgentEvent{Type: AgentEventTokenUsage, Timestamp: timestamp, Usage: usage})
+		}
+	}
+
+	return toolCalls
+}
@@ -0,0 +1,119 @@
+// This file contains the cost model loaded from the cost section of aw.json.
+//
+// # Cost Model
+//
+// The cost model maps model names to prices (in currency units per million
+// tokens) so that `gh aw logs --cost-report` can price per-model token usage
+// instead of relying on the engine-reported estimate:
+//
+//	"cost": {
+//	  "currency": "USD",
+//	  "models": {
+//	    "claude-sonnet-4.5": {"input": 3, "output": 15, "cache_read": 0.3, "cache_write": 3.75},
+//	    "gpt-5*": {"input": 1.25, "output": 10}
+//	  }
+//	}
+//
+// Model keys may be glob patterns. An exact (case-insensitive) match always wins;
+// otherwise the longest matching pattern is used.
+
+package workflow
+
+import (
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/logger"
+)
+
+var costModelLog = logger.New("workflow:cost_model")
+
+// DefaultCostCurrency is the currency reported when aw.json does not set one.
+const DefaultCostCurrency = "USD"
+
+// CostModelConfig holds the cost section of aw.json.
+type CostModelConfig struct {
+	// Currency is the ISO 4217 code shown in cost reports.
+	Currency string `json:"currency,omitempty"`
+
+	// Models maps model names or glob patterns to per-million-token prices.
+	Models map[string]ModelPrice `json:"models,omitempty"`
+}
+
+// ModelPrice is the price of one million tokens of each token class.
+// Cache prices are optional and default to the input price.
+type ModelPrice struct {
+	Input      float64  `json:"input"`
+	Output     float64  `json:"output"`
+	CacheRead  *float64 `json:"cache_read,omitempty"`
+	CacheWrite *float64 `json:"cache_write,omitempty"`
+}
+
+// Cost returns the price of the given token counts.
+func (p ModelPrice) Cost(inputTokens, outputTokens, cacheReadTokens, cacheWriteTokens int) float64 {
+	cacheRead := p.Input
+	if p.CacheRead != nil {
+		cacheRead = *p.CacheRead
+	}
+	cacheWrite := p.Input
+	if p.CacheWrite != nil {
+		cacheWrite = *p.CacheWrite
+	}
+	total := float64(inputTokens)*p.Input +
+		float64(outputTokens)*p.Output +
+		float64(cacheReadTokens)*cacheRead +
+		float64(cacheWriteTokens)*cacheWrite
+	return total / 1_000_000
+}
+
+// CostModel returns the configured cost model, or nil when none is configured.
+func (r *RepoConfig) CostModel() *CostModelConfig {
+	if r == nil {
+		return nil
+	}
+	return r.Cost
+}
+
+// CurrencyCode returns the configured currency, defaulting to USD.
+func (c *CostModelConfig) CurrencyCode() string {
+	if c == nil || c.Currency == "" {
+		return DefaultCostCurrency
+	}
+	return c.Currency
+}
+
+// PriceFor looks up the price of a model. It reports false when the model is
+// not covered by the price table.
+func (c *CostModelConfig) PriceFor(model string) (ModelPrice, bool) {
+	if c == nil || model == "" || len(c.Models) == 0 {
+		return ModelPrice{}, false
+	}
+
+	normalized := strings.ToLower(model)
+	patterns := make([]string, 0, len(c.Models))
+	for key, price := range c.Models {
+		if strings.ToLower(key) == normalized {
+			return price, true
+		}
+		if strings.ContainsAny(key, "*?[") {
+			patterns = append(patterns, key)
+		}
+	}
+
+	// Prefer the most specific (longest) pattern; break ties alphabetically so
+	// that the result does not depend on map iteration order.
+	sort.Slice(patterns, func(i, j int) bool {
+		if len(patterns[i]) != len(patterns[j]) {
+			return len(patterns[i]) > len(patterns[j])
+		}
+		return patterns[i] < patterns[j]
+	})
+	for _, pattern := range patterns {
+		if matched, err := path.Match(strings.ToLower(pattern), normalized); err == nil && matched {
+			costModelLog.Printf("Model %s priced by pattern %s", model, pattern)
+			return c.Models[pattern], true
+		}
+	}
+	return ModelPrice{}, false
+}
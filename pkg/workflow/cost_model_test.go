@@ -0,0 +1,72 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadRepoConfig_CostSection(t *testing.T) {
+	dir := t.TempDir()
+	writeAWJSON(t, dir, `{
+		"cost": {
+			"currency": "EUR",
+			"models": {
+				"claude-sonnet-4.5": {"input": 3, "output": 15, "cache_read": 0.3},
+				"gpt-5*": {"input": 1.25, "output": 10}
+			}
+		}
+	}`)
+
+	cfg, err := LoadRepoConfig(dir)
+	require.NoError(t, err, "valid cost section should load without error")
+	costModel := cfg.CostModel()
+	require.NotNil(t, costModel, "cost model should be set")
+	assert.Equal(t, "EUR", costModel.CurrencyCode(), "currency should be parsed")
+	assert.Len(t, costModel.Models, 2, "all model prices should be parsed")
+}
+
+func TestLoadRepoConfig_CostPriceRequiresInputAndOutput(t *testing.T) {
+	dir := t.TempDir()
+	writeAWJSON(t, dir, `{"cost": {"models": {"gpt-5": {"input": 1}}}}`)
+
+	_, err := LoadRepoConfig(dir)
+	require.Error(t, err, "price without output should fail schema validation")
+}
+
+func TestCostModelPriceFor(t *testing.T) {
+	cacheRead := 0.3
+	costModel := &CostModelConfig{Models: map[string]ModelPrice{
+		"claude-sonnet-4.5": {Input: 3, Output: 15, CacheRead: &cacheRead},
+		"claude-*":          {Input: 1, Output: 1},
+		"claude-sonnet-*":   {Input: 2, Output: 2},
+	}}
+
+	price, ok := costModel.PriceFor("Claude-Sonnet-4.5")
+	require.True(t, ok, "exact match should be case-insensitive")
+	assert.InDelta(t, 3.0, price.Input, 0.0001, "exact match should win over patterns")
+
+	price, ok = costModel.PriceFor("claude-sonnet-4.6")
+	require.True(t, ok, "pattern should match")
+	assert.InDelta(t, 2.0, price.Input, 0.0001, "longest pattern should win")
+
+	_, ok = costModel.PriceFor("gpt-5")
+	assert.False(t, ok, "unknown model should not be priced")
+
+	var nilModel *CostModelConfig
+	_, ok = nilModel.PriceFor("claude-sonnet-4.5")
+	assert.False(t, ok, "nil cost model should price nothing")
+	assert.Equal(t, DefaultCostCurrency, nilModel.CurrencyCode(), "nil cost model should default the currency")
+}
+
+func TestModelPriceCost(t *testing.T) {
+	cacheRead := 0.3
+	price := ModelPrice{Input: 3, Output: 15, CacheRead: &cacheRead}
+
+	cost := price.Cost(1_000_000, 100_000, 2_000_000, 1_000_000)
+	// 3 + 1.5 + 0.6 + 3 (cache write defaults to the input price)
+	assert.InDelta(t, 8.1, cost, 0.0001, "cost should combine every token class")
+}
@@ -92,16 +92,14 @@ func (c *Compiler) GenerateDependabotManifests(workflowDataList []*WorkflowData,
 			if c.strictMode {
 				return fmt.Errorf("failed to generate package.json: %w", err)
 			}
-			c.IncrementWarningCount()
-			fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to generate package.json: %v", err)))
+			c.addWarning(fmt.Sprintf("Failed to generate package.json: %v", err))
 		} else {
 			// Generate package-lock.json
 			if err := c.generatePackageLock(workflowDir); err != nil {
 				if c.strictMode {
 					return fmt.Errorf("failed to generate package-lock.json: %w", err)
 				}
-				c.IncrementWarningCount()
-				fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to generate package-lock.json: %v", err)))
+				c.addWarning(fmt.Sprintf("Failed to generate package-lock.json: %v", err))
 			}
 		}
 	}
@@ -121,8 +119,7 @@ func (c *Compiler) GenerateDependabotManifests(workflowDataList []*WorkflowData,
 			if c.strictMode {
 				return fmt.Errorf("failed to generate requirements.txt: %w", err)
 			}
-			c.IncrementWarningCount()
-			fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to generate requirements.txt: %v", err)))
+			c.addWarning(fmt.Sprintf("Failed to generate requirements.txt: %v", err))
 		}
 	}
 
@@ -141,8 +138,7 @@ func (c *Compiler) GenerateDependabotManifests(workflowDataList []*WorkflowData,
 			if c.strictMode {
 				return fmt.Errorf("failed to generate go.mod: %w", err)
 			}
-			c.IncrementWarningCount()
-			fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to generate go.mod: %v", err)))
+			c.addWarning(fmt.Sprintf("Failed to generate go.mod: %v", err))
 		}
 	}
 
@@ -161,8 +157,7 @@ func (c *Compiler) GenerateDependabotManifests(workflowDataList []*WorkflowData,
 		if c.strictMode {
 			return fmt.Errorf("failed to generate dependabot.yml: %w", err)
 		}
-		c.IncrementWarningCount()
-		fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to generate dependabot.yml: %v", err)))
+		c.addWarning(fmt.Sprintf("Failed to generate dependabot.yml: %v", err))
 	}
 
 	if c.verbose {
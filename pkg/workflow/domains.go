@@ -378,6 +378,7 @@ var runtimeToEcosystem = map[string]string{
 	"go":      "go",
 	"java":    "java",
 	"ruby":    "ruby",
+	"rust":    "rust",
 	"dotnet":  "dotnet",
 	"haskell": "haskell",
 	"gh-aw":   "gh-aw",
@@ -208,17 +208,21 @@ func (c *EngineCatalog) Get(id string) *EngineDefinition {
 	return c.definitions[id]
 }
 
-// IDs returns a sorted list of all engine IDs in the catalog.
+// IDs returns a sorted list of all engine IDs in the catalog, excluding engines
+// hidden in the registry.
 func (c *EngineCatalog) IDs() []string {
 	ids := make([]string, 0, len(c.definitions))
 	for id := range c.definitions {
+		if c.registry != nil && c.registry.IsHiddenEngine(id) {
+			continue
+		}
 		ids = append(ids, id)
 	}
 	sort.Strings(ids)
 	return ids
 }
 
-// All returns all engine definitions in sorted ID order.
+// All returns all engine definitions in sorted ID order, excluding hidden engines.
 func (c *EngineCatalog) All() []*EngineDefinition {
 	ids := c.IDs()
 	defs := make([]*EngineDefinition, 0, len(ids))
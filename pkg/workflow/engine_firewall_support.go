@@ -3,11 +3,9 @@ package workflow
 import (
 	"errors"
 	"fmt"
-	"os"
 	"path"
 	"strings"
 
-	"github.com/github/gh-aw/pkg/console"
 	"github.com/github/gh-aw/pkg/constants"
 	"github.com/github/gh-aw/pkg/logger"
 )
@@ -88,8 +86,7 @@ func (c *Compiler) checkFirewallDisable(networkPermissions *NetworkPermissions)
 			}
 
 			// In non-strict mode, emit a warning
-			fmt.Fprintln(os.Stderr, console.FormatWarningMessage(message))
-			c.IncrementWarningCount()
+			c.addWarning(message)
 		}
 	}
 
@@ -3,6 +3,7 @@
 package workflow
 
 import (
+	"slices"
 	"testing"
 )
 
@@ -177,6 +178,11 @@ func TestClaudeEngine_ParseLogMetrics_WithDuration(t *testing.T) {
 	if !toolNames["bash_echo hello"] {
 		t.Error("Expected bash tool to be named 'bash_echo hello'")
 	}
+	for _, toolCall := range metrics.ToolCalls {
+		if toolCall.Name == "bash_echo hello" && !slices.Equal(toolCall.Commands, []string{"echo hello"}) {
+			t.Errorf("Expected the full bash command to be kept, got %v", toolCall.Commands)
+		}
+	}
 	if !toolNames["github_search_issues"] {
 		t.Error("Expected github tool to be named 'github_search_issues'")
 	}
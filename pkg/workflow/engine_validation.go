@@ -37,13 +37,11 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"os"
 	"path/filepath"
 	"regexp"
 	"strings"
 	"time"
 
-	"github.com/github/gh-aw/pkg/console"
 	"github.com/github/gh-aw/pkg/constants"
 	"github.com/github/gh-aw/pkg/parser"
 )
@@ -75,8 +73,7 @@ func (c *Compiler) validateEngineVersion(workflowData *WorkflowData) error {
 		return fmt.Errorf("strict mode: %s", warningMsg)
 	}
 
-	fmt.Fprintln(os.Stderr, console.FormatWarningMessage(warningMsg))
-	c.IncrementWarningCount()
+	c.addWarning(warningMsg)
 	return nil
 }
 
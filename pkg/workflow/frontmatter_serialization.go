@@ -168,6 +168,9 @@ func (fc *FrontmatterConfig) ToMap() map[string]any {
 	if fc.InlineSubAgents != nil {
 		result["inline-sub-agents"] = *fc.InlineSubAgents
 	}
+	if len(fc.SubAgents) > 0 {
+		result["sub-agents"] = fc.SubAgents
+	}
 	if fc.Env != nil {
 		result["env"] = fc.Env
 	}
@@ -289,6 +289,7 @@ type FrontmatterConfig struct {
 	// Deprecated: as of v1.1.0, inline sub-agents are always enabled.
 	// Remove this field from frontmatter. Setting false causes a compilation error.
 	InlineSubAgents *bool             `json:"inline-sub-agents,omitempty"`
+	SubAgents       []string          `json:"sub-agents,omitempty"` // Sub-agent files imported by path or workflowspec
 	Env             map[string]string `json:"env,omitempty"`
 	Secrets         map[string]any    `json:"secrets,omitempty"`
 
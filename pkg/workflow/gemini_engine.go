@@ -28,6 +28,8 @@ func NewGeminiEngine() *GeminiEngine {
 				MaxContinuations: false, // Gemini CLI does not support --max-autopilot-continues-style continuation mode
 				WebSearch:        false,
 				NativeAgentFile:  false, // Gemini does not support agent file natively; the compiler prepends the agent file content to prompt.txt
+				SubAgentTools:    true,  // Gemini sub-agents accept a tools list in their frontmatter
+				SubAgentMaxTurns: true,  // Gemini sub-agents accept max_turns in their frontmatter
 			},
 			dedicatedLLMGatewayPort: constants.GeminiLLMGatewayPort,
 		},
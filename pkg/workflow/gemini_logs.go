@@ -2,6 +2,8 @@ package workflow
 
 import (
 	"encoding/json"
+	"maps"
+	"slices"
 	"strings"
 
 	"github.com/github/gh-aw/pkg/logger"
@@ -104,3 +106,67 @@ func (e *GeminiEngine) GetLogFileForParsing() string {
 func (e *GeminiEngine) GetDefaultDetectionModel() string {
 	return ""
 }
+
+// ParseAgentEvents converts Gemini CLI JSON output into the normalized agent event stream.
+// Each JSON response is one turn; per-model token stats and per-tool stats are
+// reported as token_usage and tool_call events because the CLI does not log individual calls.
+func (e *GeminiEngine) ParseAgentEvents(logContent string) []AgentEvent {
+	parser := &geminiAgentEventParser{stream: NewAgentEventStream(e.GetID())}
+	for line := range strings.SplitSeq(logContent, "\n") {
+		parser.ParseLine(line)
+	}
+	return parser.stream.Events()
+}
+
+// NewAgentEventParser returns a parser for Gemini CLI output read line by line
+func (e *GeminiEngine) NewAgentEventParser() AgentEventParser {
+	return &geminiAgentEventParser{stream: NewAgentEventStream(e.GetID())}
+}
+
+// geminiAgentEventParser parses Gemini CLI JSON responses, one per line
+type geminiAgentEventParser struct {
+	stream *AgentEventStream
+}
+
+// ParseLine emits the events of one JSON response line
+func (p *geminiAgentEventParser) ParseLine(line string) {
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return
+	}
+
+	var response GeminiResponse
+	if err := json.Unmarshal([]byte(line), &response); err != nil {
+		return
+	}
+	if response.Response == "" && response.Stats == nil {
+		return
+	}
+
+	p.stream.StartTurn("")
+	if models, ok := response.Stats["models"].(map[string]any); ok {
+		for _, model := range slices.Sorted(maps.Keys(models)) {
+			stats, ok := models[model].(map[string]any)
+			if !ok {
+				continue
+			}
+			p.stream.SetModel(model, "")
+			if usage := agentEventUsageFromMap(stats); usage != nil {
+				p.stream.Emit(AgentEvent{Type: AgentEventTokenUsage, Model: model, Usage: usage})
+			}
+		}
+	}
+	if tools, ok := response.Stats["tools"].(map[string]any); ok {
+		for _, toolName := range slices.Sorted(maps.Keys(tools)) {
+			p.stream.Emit(AgentEvent{Type: AgentEventToolCall, Tool: toolName})
+		}
+	}
+	if strings.TrimSpace(response.Response) != "" {
+		p.stream.Emit(AgentEvent{Type: AgentEventAssistantMessage, Text: response.Response, OutputSize: len(response.Response)})
+	}
+}
+
+// Pending returns the events emitted since the previous call
+func (p *geminiAgentEventParser) Pending() []AgentEvent {
+	return p.stream.Pending()
+}
@@ -0,0 +1,368 @@
+// This file contains the MCP registry configuration and trust policy loaded from aw.json.
+//
+// # MCP Trust Policy
+//
+// The trust policy restricts which MCP servers may be installed by `gh aw mcp add`
+// and compiled from mcp-servers entries:
+//
+//   - allowed_publishers - publisher namespaces (registry name prefix before "/")
+//   - pinned             - required package version or sha256 digest per server
+//   - denied             - server names that must never be used
+//
+// A server is identified by its registry name (the registry field written by
+// `gh aw mcp add`). Entries without a registry field are identified by their
+// container image, URL or package runner command:
+//
+//   - container "ghcr.io/github/github-mcp-server:v1" - name "ghcr.io/github/github-mcp-server", publisher "ghcr.io/github"
+//   - url "https://mcp.example.com/mcp"               - name "mcp.example.com/mcp", publisher "mcp.example.com"
+//   - command "npx" with "@scope/server@1.0"          - name "@scope/server", publisher "@scope"
+//
+// When allowed_publishers or pinned rules are configured, servers that cannot
+// be identified (e.g. a local script) are rejected. Deny rules apply to every
+// mcp-servers entry and match either the server name or the entry key.
+
+package workflow
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+)
+
+var mcpTrustLog = newValidationLogger("mcp_trust_policy")
+
+// MCPRepoConfig holds the mcp section of aw.json.
+type MCPRepoConfig struct {
+	// Registries lists the MCP registries queried by `gh aw mcp add`.
+	// When non-empty it replaces the default public registry.
+	Registries []MCPRegistrySource `json:"registries,omitempty"`
+
+	// Trust is the trust policy enforced at install and compile time.
+	Trust *MCPTrustPolicy `json:"trust,omitempty"`
+}
+
+// MCPRegistrySource describes a single registry: either a live registry API
+// (URL) or an offline JSON mirror file in the registry /servers format (File).
+type MCPRegistrySource struct {
+	Name     string `json:"name,omitempty"`
+	URL      string `json:"url,omitempty"`
+	File     string `json:"file,omitempty"`
+	Priority int    `json:"priority,omitempty"`
+}
+
+// DisplayName returns the configured name, falling back to the URL or file path.
+func (s MCPRegistrySource) DisplayName() string {
+	if s.Name != "" {
+		return s.Name
+	}
+	if s.URL != "" {
+		return s.URL
+	}
+	return s.File
+}
+
+// MCPTrustPolicy restricts which MCP servers may be used.
+type MCPTrustPolicy struct {
+	AllowedPublishers []string          `json:"allowed_publishers,omitempty"`
+	Pinned            map[string]string `json:"pinned,omitempty"`
+	Denied            []string          `json:"denied,omitempty"`
+}
+
+// MCPServerIdentity describes an MCP server for trust policy evaluation.
+type MCPServerIdentity struct {
+	// Name is the registry server name (e.g. "io.github.github/github-mcp-server"),
+	// or the name derived from the container image, URL or package.
+	// Empty when the server cannot be identified.
+	Name string
+	// Publisher is the namespace matched against allowed_publishers. When empty
+	// it is derived from a registry server name.
+	Publisher string
+	// ToolID is the key under mcp-servers (empty during `mcp add` before the key is chosen).
+	ToolID string
+	// Refs are the version strings and digests the server is pinned to
+	// (package versions, container tags and digests).
+	Refs []string
+}
+
+// TrustPolicy returns the configured MCP trust policy or nil.
+func (r *RepoConfig) TrustPolicy() *MCPTrustPolicy {
+	if r == nil || r.MCP == nil {
+		return nil
+	}
+	return r.MCP.Trust
+}
+
+// RegistrySources returns the configured registries sorted by priority
+// (highest first, ties keep their configured order).
+func (r *RepoConfig) RegistrySources() []MCPRegistrySource {
+	if r == nil || r.MCP == nil || len(r.MCP.Registries) == 0 {
+		return nil
+	}
+	sources := make([]MCPRegistrySource, len(r.MCP.Registries))
+	copy(sources, r.MCP.Registries)
+	sort.SliceStable(sources, func(i, j int) bool {
+		return sources[i].Priority > sources[j].Priority
+	})
+	return sources
+}
+
+// MCPServerPublisher returns the publisher namespace of a registry server name,
+// i.e. everything before the first "/".
+func MCPServerPublisher(name string) string {
+	publisher, _, found := strings.Cut(name, "/")
+	if !found {
+		return ""
+	}
+	return publisher
+}
+
+// Check evaluates the policy against a server and returns a descriptive error
+// for the first violated rule. A nil policy allows everything.
+func (p *MCPTrustPolicy) Check(server MCPServerIdentity) error {
+	if p == nil {
+		return nil
+	}
+	label := server.Name
+	if label == "" {
+		label = server.ToolID
+	}
+	mcpTrustLog.Printf("Checking MCP trust policy for server: %s", label)
+
+	for _, pattern := range p.Denied {
+		if matchesTrustPattern(pattern, server.Name) || matchesTrustPattern(pattern, server.ToolID) {
+			return fmt.Errorf("MCP server '%s' is denied by the trust policy in %s (rule: %q)", label, RepoConfigFileName, pattern)
+		}
+	}
+
+	if server.Name == "" {
+		if len(p.AllowedPublishers) > 0 || len(p.Pinned) > 0 {
+			return fmt.Errorf("MCP server '%s' cannot be identified for the trust policy in %s; add a registry field or use a container, url or package runner command", label, RepoConfigFileName)
+		}
+		return nil
+	}
+
+	if len(p.AllowedPublishers) > 0 {
+		publisher := server.Publisher
+		if publisher == "" {
+			publisher = MCPServerPublisher(server.Name)
+		}
+		allowed := false
+		for _, pattern := range p.AllowedPublishers {
+			if matchesTrustPattern(pattern, publisher) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return fmt.Errorf("MCP server '%s' is published by '%s', which is not in the allowed_publishers list in %s (allowed: %s)",
+				server.Name, publisher, RepoConfigFileName, strings.Join(p.AllowedPublishers, ", "))
+		}
+	}
+
+	if pin, ok := p.Pinned[server.Name]; ok {
+		if !refsSatisfyPin(server.Refs, pin) {
+			found := "none"
+			if len(server.Refs) > 0 {
+				found = strings.Join(server.Refs, ", ")
+			}
+			return fmt.Errorf("MCP server '%s' must be pinned to %q by the trust policy in %s (found: %s)", server.Name, pin, RepoConfigFileName, found)
+		}
+	}
+
+	return nil
+}
+
+// matchesTrustPattern reports whether value matches a glob pattern. Empty values never match.
+func matchesTrustPattern(pattern, value string) bool {
+	if value == "" {
+		return false
+	}
+	if pattern == value {
+		return true
+	}
+	matched, err := path.Match(pattern, value)
+	return err == nil && matched
+}
+
+// refsSatisfyPin reports whether any ref equals the pin. A leading "v" is ignored
+// for versions so that "1.2.3" and "v1.2.3" are treated as the same pin.
+func refsSatisfyPin(refs []string, pin string) bool {
+	for _, ref := range refs {
+		if ref == pin || strings.TrimPrefix(ref, "v") == strings.TrimPrefix(pin, "v") {
+			return true
+		}
+	}
+	return false
+}
+
+// MCPServerIdentityFromConfig builds the trust identity of an mcp-servers entry.
+// The registry server name is derived from the registry field ("<url>/servers/<name>");
+// without it the name is derived from the container image, URL or package command.
+func MCPServerIdentityFromConfig(toolID string, config map[string]any) MCPServerIdentity {
+	identity := MCPServerIdentity{ToolID: toolID}
+
+	if registry, ok := config["registry"].(string); ok {
+		if _, name, found := strings.Cut(registry, "/servers/"); found {
+			identity.Name = strings.TrimSuffix(name, "/")
+			identity.Publisher = MCPServerPublisher(identity.Name)
+		}
+	}
+	if identity.Name == "" {
+		identity.Name, identity.Publisher = deriveMCPServerName(config)
+	}
+
+	if version, ok := config["version"].(string); ok && version != "" {
+		identity.Refs = append(identity.Refs, version)
+	}
+	if container, ok := config["container"].(string); ok && container != "" {
+		identity.Refs = append(identity.Refs, containerRefs(container)...)
+	}
+	if args, ok := config["args"].([]any); ok {
+		for _, arg := range args {
+			if s, ok := arg.(string); ok {
+				identity.Refs = append(identity.Refs, packageArgRefs(s)...)
+			}
+		}
+	}
+
+	return identity
+}
+
+// deriveMCPServerName identifies a server without registry provenance from its
+// container image, URL or package runner command. It returns empty strings when
+// the server cannot be identified.
+func deriveMCPServerName(config map[string]any) (name, publisher string) {
+	if container, ok := config["container"].(string); ok && container != "" {
+		name = containerRepository(container)
+		if idx := strings.LastIndex(name, "/"); idx > 0 {
+			return name, name[:idx]
+		}
+		return name, name
+	}
+
+	if rawURL, ok := config["url"].(string); ok && rawURL != "" {
+		parsed, err := url.Parse(rawURL)
+		if err != nil || parsed.Host == "" {
+			return "", ""
+		}
+		return parsed.Host + strings.TrimSuffix(parsed.Path, "/"), parsed.Host
+	}
+
+	command, _ := config["command"].(string)
+	if !mcpPackageRunners[path.Base(command)] {
+		return "", ""
+	}
+	args, _ := config["args"].([]any)
+	for _, arg := range args {
+		s, ok := arg.(string)
+		if !ok || s == "" || strings.HasPrefix(s, "-") || s == "run" || s == "dlx" {
+			continue
+		}
+		name = packageName(s)
+		if scope, _, found := strings.Cut(name, "/"); found && strings.HasPrefix(name, "@") {
+			return name, scope
+		}
+		return name, name
+	}
+	return "", ""
+}
+
+// mcpPackageRunners are commands that run a package from a public registry,
+// e.g. "npx -y @scope/server" or "uvx mcp-server-fetch".
+var mcpPackageRunners = map[string]bool{
+	"npx":  true,
+	"bunx": true,
+	"pnpm": true,
+	"yarn": true,
+	"uvx":  true,
+	"pipx": true,
+}
+
+// containerRepository returns a container image reference without its tag and digest.
+func containerRepository(image string) string {
+	image, _, _ = strings.Cut(image, "@")
+	if idx := strings.LastIndex(image, ":"); idx > strings.LastIndex(image, "/") {
+		image = image[:idx]
+	}
+	return image
+}
+
+// packageName returns a package argument without its version, e.g.
+// "@scope/pkg@1.2.3" becomes "@scope/pkg" and "pkg==1.2.3" becomes "pkg".
+func packageName(arg string) string {
+	if name, _, found := strings.Cut(arg, "=="); found {
+		return name
+	}
+	if idx := strings.LastIndex(arg, "@"); idx > 0 {
+		return arg[:idx]
+	}
+	return arg
+}
+
+// containerRefs returns the tag and digest of a container image reference.
+func containerRefs(image string) []string {
+	var refs []string
+	if name, digest, found := strings.Cut(image, "@"); found {
+		refs = append(refs, digest)
+		image = name
+	}
+	// A tag follows the last ":" after the last "/" (a ":" before it is a registry port).
+	if idx := strings.LastIndex(image, ":"); idx > strings.LastIndex(image, "/") {
+		refs = append(refs, image[idx+1:])
+	}
+	return refs
+}
+
+// packageArgRefs returns the version of a package argument such as
+// "@scope/pkg@1.2.3" or "pkg==1.2.3".
+func packageArgRefs(arg string) []string {
+	if strings.HasPrefix(arg, "-") {
+		return nil
+	}
+	if _, version, found := strings.Cut(arg, "=="); found && version != "" {
+		return []string{version}
+	}
+	if idx := strings.LastIndex(arg, "@"); idx > 0 && idx < len(arg)-1 {
+		return []string{arg[idx+1:]}
+	}
+	return nil
+}
+
+// validateMCPTrustPolicy enforces the aw.json trust policy for every custom MCP
+// server in the merged tools map.
+func validateMCPTrustPolicy(tools map[string]any, policy *MCPTrustPolicy) error {
+	if policy == nil {
+		return nil
+	}
+
+	toolNames := make([]string, 0, len(tools))
+	for name := range tools {
+		toolNames = append(toolNames, name)
+	}
+	sort.Strings(toolNames)
+
+	for _, toolName := range toolNames {
+		if builtInToolNames[toolName] {
+			continue
+		}
+		config, ok := tools[toolName].(map[string]any)
+		if !ok {
+			continue
+		}
+		if err := CheckMCPServerTrust(policy, toolName, config); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CheckMCPServerTrust evaluates the trust policy against one mcp-servers entry,
+// identified from its configuration the same way at install and compile time.
+func CheckMCPServerTrust(policy *MCPTrustPolicy, toolID string, config map[string]any) error {
+	if err := policy.Check(MCPServerIdentityFromConfig(toolID, config)); err != nil {
+		return fmt.Errorf("mcp-servers.%s: %w", toolID, err)
+	}
+	return nil
+}
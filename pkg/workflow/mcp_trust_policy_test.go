@@ -0,0 +1,205 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadRepoConfig_MCPSection(t *testing.T) {
+	dir := t.TempDir()
+	writeAWJSON(t, dir, `{
+		"mcp": {
+			"registries": [
+				{"name": "public", "url": "https://api.mcp.github.com/v0.1"},
+				{"name": "internal", "url": "https://mcp.internal.example.com/v0.1", "priority": 10},
+				{"name": "mirror", "file": ".github/aw/mcp-registry.json", "priority": 5}
+			],
+			"trust": {
+				"allowed_publishers": ["io.github.github"],
+				"pinned": {"io.github.github/github-mcp-server": "v1.2.3"},
+				"denied": ["io.github.evil/*"]
+			}
+		}
+	}`)
+
+	cfg, err := LoadRepoConfig(dir)
+	require.NoError(t, err, "valid mcp section should load without error")
+	require.NotNil(t, cfg.MCP, "mcp config should be set")
+
+	sources := cfg.RegistrySources()
+	require.Len(t, sources, 3, "all registries should be returned")
+	assert.Equal(t, "internal", sources[0].Name, "highest priority registry should come first")
+	assert.Equal(t, "mirror", sources[1].Name, "second priority registry should come second")
+	assert.Equal(t, "public", sources[2].Name, "default priority registry should come last")
+
+	policy := cfg.TrustPolicy()
+	require.NotNil(t, policy, "trust policy should be set")
+	assert.Equal(t, []string{"io.github.github"}, policy.AllowedPublishers, "allowed publishers should be parsed")
+	assert.Equal(t, "v1.2.3", policy.Pinned["io.github.github/github-mcp-server"], "pins should be parsed")
+}
+
+func TestLoadRepoConfig_MCPRegistryRequiresURLOrFile(t *testing.T) {
+	dir := t.TempDir()
+	writeAWJSON(t, dir, `{"mcp": {"registries": [{"name": "broken"}]}}`)
+
+	_, err := LoadRepoConfig(dir)
+	require.Error(t, err, "registry without url or file should fail schema validation")
+}
+
+func TestMCPTrustPolicyCheck(t *testing.T) {
+	policy := &MCPTrustPolicy{
+		AllowedPublishers: []string{"io.github.github", "com.example.*"},
+		Pinned: map[string]string{
+			"io.github.github/github-mcp-server": "1.2.3",
+			"com.example.tools/scanner":          "sha256:abc123",
+		},
+		Denied: []string{"io.github.github/legacy-*", "scratch"},
+	}
+
+	tests := []struct {
+		name      string
+		server    MCPServerIdentity
+		wantError string
+	}{
+		{
+			name:   "allowed publisher with matching pin",
+			server: MCPServerIdentity{Name: "io.github.github/github-mcp-server", Refs: []string{"v1.2.3"}},
+		},
+		{
+			name:      "allowed publisher with wrong pin",
+			server:    MCPServerIdentity{Name: "io.github.github/github-mcp-server", Refs: []string{"1.3.0"}},
+			wantError: "must be pinned to",
+		},
+		{
+			name:      "pin required but no refs",
+			server:    MCPServerIdentity{Name: "com.example.tools/scanner"},
+			wantError: "found: none",
+		},
+		{
+			name:   "glob publisher with digest pin",
+			server: MCPServerIdentity{Name: "com.example.tools/scanner", Refs: []string{"2.0.0", "sha256:abc123"}},
+		},
+		{
+			name:      "publisher not allowed",
+			server:    MCPServerIdentity{Name: "io.github.someone/server"},
+			wantError: "not in the allowed_publishers list",
+		},
+		{
+			name:      "denied registry name",
+			server:    MCPServerIdentity{Name: "io.github.github/legacy-server"},
+			wantError: "denied by the trust policy",
+		},
+		{
+			name:      "denied tool id without registry provenance",
+			server:    MCPServerIdentity{ToolID: "scratch"},
+			wantError: "denied by the trust policy",
+		},
+		{
+			name:      "unidentified server with publisher rules",
+			server:    MCPServerIdentity{ToolID: "my-local-tool"},
+			wantError: "cannot be identified",
+		},
+		{
+			name:      "derived name from a publisher that is not allowed",
+			server:    MCPServerIdentity{Name: "ghcr.io/someone/server", Publisher: "ghcr.io/someone"},
+			wantError: "not in the allowed_publishers list",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := policy.Check(tt.server)
+			if tt.wantError == "" {
+				assert.NoError(t, err, "server should be allowed")
+				return
+			}
+			require.Error(t, err, "server should be rejected")
+			assert.Contains(t, err.Error(), tt.wantError, "error should describe the violated rule")
+		})
+	}
+}
+
+func TestMCPTrustPolicyCheck_DenyOnlyPolicy(t *testing.T) {
+	policy := &MCPTrustPolicy{Denied: []string{"scratch"}}
+	assert.NoError(t, policy.Check(MCPServerIdentity{ToolID: "my-local-tool"}), "deny rules alone should not require an identity")
+}
+
+func TestMCPTrustPolicyCheck_NilPolicy(t *testing.T) {
+	var policy *MCPTrustPolicy
+	assert.NoError(t, policy.Check(MCPServerIdentity{Name: "anything/at-all"}), "nil policy should allow everything")
+}
+
+func TestMCPServerIdentityFromConfig(t *testing.T) {
+	tests := []struct {
+		name          string
+		config        map[string]any
+		wantName      string
+		wantPublisher string
+		wantRefs      []string
+	}{
+		{
+			name: "container with tag and registry",
+			config: map[string]any{
+				"container": "ghcr.io/github/github-mcp-server:v1.2.3",
+				"registry":  "https://api.mcp.github.com/v0.1/servers/io.github.github/github-mcp-server",
+			},
+			wantName:      "io.github.github/github-mcp-server",
+			wantPublisher: "io.github.github",
+			wantRefs:      []string{"v1.2.3"},
+		},
+		{
+			name:          "container digest and registry port",
+			config:        map[string]any{"container": "registry.local:5000/tools/scanner@sha256:deadbeef"},
+			wantName:      "registry.local:5000/tools/scanner",
+			wantPublisher: "registry.local:5000/tools",
+			wantRefs:      []string{"sha256:deadbeef"},
+		},
+		{
+			name:          "version field and package args",
+			config:        map[string]any{"command": "npx", "version": "2.0.0", "args": []any{"-y", "@scope/server@1.4.0", "mcp==0.9.1"}},
+			wantName:      "@scope/server",
+			wantPublisher: "@scope",
+			wantRefs:      []string{"2.0.0", "1.4.0", "0.9.1"},
+		},
+		{
+			name:          "url without registry",
+			config:        map[string]any{"type": "http", "url": "https://mcp.example.com/mcp/"},
+			wantName:      "mcp.example.com/mcp",
+			wantPublisher: "mcp.example.com",
+		},
+		{
+			name:   "local command cannot be identified",
+			config: map[string]any{"command": "python", "args": []any{"server.py"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			identity := MCPServerIdentityFromConfig("tool", tt.config)
+			assert.Equal(t, "tool", identity.ToolID, "tool id should be preserved")
+			assert.Equal(t, tt.wantName, identity.Name, "name should be derived from the registry field, container, url or package")
+			assert.Equal(t, tt.wantPublisher, identity.Publisher, "publisher should be derived from the name")
+			assert.Equal(t, tt.wantRefs, identity.Refs, "refs should be extracted from config")
+		})
+	}
+}
+
+func TestValidateMCPTrustPolicy(t *testing.T) {
+	policy := &MCPTrustPolicy{Denied: []string{"untrusted"}}
+
+	tools := map[string]any{
+		"github":    map[string]any{},
+		"untrusted": map[string]any{"command": "npx", "args": []any{"untrusted-server"}},
+	}
+	err := validateMCPTrustPolicy(tools, policy)
+	require.Error(t, err, "denied mcp-servers entry should be rejected")
+	assert.Contains(t, err.Error(), "mcp-servers.untrusted", "error should point at the mcp-servers entry")
+
+	delete(tools, "untrusted")
+	assert.NoError(t, validateMCPTrustPolicy(tools, policy), "built-in tools should never be checked")
+	assert.NoError(t, validateMCPTrustPolicy(tools, nil), "nil policy should allow everything")
+}
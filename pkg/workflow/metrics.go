@@ -19,6 +19,7 @@ type ToolCallInfo struct {
 	MaxInputSize  int           // Maximum input size in tokens for any call
 	MaxOutputSize int           // Maximum output size in tokens for any call
 	MaxDuration   time.Duration // Maximum execution duration for any call
+	Commands      []string      // Full commands of bash calls, whose names only carry a shortened command
 }
 
 // LogMetrics represents extracted metrics from log files
@@ -12,23 +12,23 @@
 //     error message MUST name the offending character and segment type.
 //   - V-MAF-010: Detect and report circular alias references (DFS, compile time).
 //   - V-MAF-011: Emit a warning for unrecognised parameter keys.
+//   - V-MAF-020: Warn when an alias resolves to zero entries in the engine's catalog
+//     (an error in strict mode when the alias is engine.model).
+//   - V-MAF-021: Warn when the fallback chain of engine.model changes cost class.
 //
-// # Entry Point
+// # Entry Points
 //
-//   - validateModelAliasMap() is called from ParseWorkflowFile (compiler_orchestrator_workflow.go)
-//     after ModelMappings is populated.
+//   - validateModelAliasMap() and validateModelResolution() are called from
+//     ParseWorkflowFile (compiler_orchestrator_workflow.go) after ModelMappings is populated.
 
 package workflow
 
 import (
 	"fmt"
 	"math"
-	"os"
 	"sort"
 	"strconv"
 	"strings"
-
-	"github.com/github/gh-aw/pkg/console"
 )
 
 var modelAliasValidationLog = newValidationLogger("model_alias")
@@ -219,9 +219,7 @@ func (c *Compiler) warnUnrecognizedModelParams(identifiers []string, markdownPat
 		for _, k := range UnrecognizedParams(p.Params) {
 			msg := fmt.Sprintf("models: unrecognised parameter key %q in %q — "+
 				"known parameters are: effort, temperature (V-MAF-011)", k, id)
-			fmt.Fprintln(os.Stderr, console.FormatWarningMessage(
-				formatCompilerMessage(markdownPath, "warning", msg)))
-			c.IncrementWarningCount()
+			c.addWarningAt(markdownPath, msg)
 		}
 	}
 }
@@ -338,6 +336,93 @@ func isAliasReference(base string, aliasMap map[string][]string) bool {
 	return exists
 }
 
+// ─── V-MAF-020, 021: resolution against the engine's catalog ─────────────────
+
+// validateModelResolution resolves engine.model and the aliases declared in the
+// main workflow's frontmatter against the engine's model catalog (see model_catalog.go).
+//
+//   - V-MAF-020: an alias with no candidate in the catalog is reported. This is always a
+//     warning: the catalog is derived from the multiplier registry rather than from the
+//     models each provider serves, so a model missing from it may still be available.
+//   - V-MAF-021: engine.model whose resolved fallbacks span several cost classes is
+//     reported, since a fallback silently changes what each run costs.
+//
+// Engines without a catalog, expressions and concrete model names are skipped.
+func (c *Compiler) validateModelResolution(
+	engineID string,
+	engineModel string,
+	mergedAliasMap map[string][]string,
+	frontmatterModels map[string][]string,
+	markdownPath string,
+) error {
+	multipliers := BuiltinModelMultipliers()
+	if engineID == "" || EngineModelCatalog(engineID, multipliers) == nil {
+		return nil
+	}
+	modelAliasValidationLog.Printf("Validating model resolution for engine %s: engine.model=%q, %d frontmatter alias(es)", engineID, engineModel, len(frontmatterModels))
+
+	modelAlias, _, _ := strings.Cut(engineModel, "?")
+	if engineModel != "" && !containsExpression(engineModel) && isAliasReference(modelAlias, mergedAliasMap) {
+		chain := ResolveModelFallbackChain(engineModel, mergedAliasMap, engineID, multipliers)
+		var resolved []ModelFallbackCandidate
+		for _, candidate := range chain {
+			if candidate.Resolved() {
+				resolved = append(resolved, candidate)
+			}
+		}
+
+		if len(resolved) == 0 {
+			msg := fmt.Sprintf("engine.model: alias %q does not resolve to any model known for engine '%s'; "+
+				"check that the alias has an entry for one of its providers (%s) (V-MAF-020)",
+				modelAlias, engineID, strings.Join(engineModelProviderNames(engineID), ", "))
+			c.addWarningAt(markdownPath, msg)
+		} else {
+			primary := resolved[0]
+			for _, fallback := range resolved[1:] {
+				if fallback.CostClass == ModelCostClassUnknown || primary.CostClass == ModelCostClassUnknown || fallback.CostClass == primary.CostClass {
+					continue
+				}
+				msg := fmt.Sprintf("engine.model: alias %q resolves to %s (%s, %gx) but falls back to %s (%s, %gx); "+
+					"the cost of a run changes when the fallback is used — run 'gh aw models resolve' to see the full chain (V-MAF-021)",
+					modelAlias, primary.Model, primary.CostClass, primary.Multiplier, fallback.Model, fallback.CostClass, fallback.Multiplier)
+				c.addWarningAt(markdownPath, msg)
+				break
+			}
+		}
+	}
+
+	keys := make([]string, 0, len(frontmatterModels))
+	for key := range frontmatterModels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if key == "" || key == modelAlias {
+			continue
+		}
+		resolves := false
+		for _, candidate := range ResolveModelFallbackChain(key, mergedAliasMap, engineID, multipliers) {
+			if candidate.Resolved() || containsExpression(candidate.Entry) {
+				resolves = true
+				break
+			}
+		}
+		if !resolves {
+			msg := fmt.Sprintf("models: alias %q does not resolve to any model known for engine '%s' (V-MAF-020)", key, engineID)
+			c.addWarningAt(markdownPath, msg)
+		}
+	}
+	return nil
+}
+
+// engineModelProviderNames returns the provider prefixes an engine accepts, for messages.
+func engineModelProviderNames(engineID string) []string {
+	if providers, ok := engineModelProviders[engineID]; ok {
+		return providers
+	}
+	return []string{"any provider"}
+}
+
 // ─── Utilities ────────────────────────────────────────────────────────────────
 
 // displayKey returns a human-readable representation of an alias key for use in
@@ -0,0 +1,312 @@
+// This file builds per-engine model catalogs and resolves model aliases against
+// them, following the fallback resolution algorithm of the Model Alias Format
+// specification (docs/src/content/docs/reference/model-alias-specification.md, §8).
+//
+// # Model Catalog
+//
+// An engine's catalog is the set of concrete provider-scoped model names it can
+// run. It is derived from the Effective Tokens multiplier registry in
+// data/model_multipliers.json (the list of models gh-aw knows about), scoped to the
+// providers the engine talks to:
+//
+//	copilot → copilot/<every model>        (the Copilot gateway serves all vendors)
+//	claude  → anthropic/claude-*
+//	codex   → openai/gpt-*, openai/o*
+//	gemini  → google/gemini-*, gemini/gemini-*
+//
+// Engines that accept any "provider/model" (opencode, crush, pi) get the union.
+//
+// # Cost Classes
+//
+// Each model is placed in a cost class from its ET multiplier so that fallbacks
+// which silently move a workflow to a much cheaper or more expensive model can be
+// reported at compile time.
+
+package workflow
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/logger"
+)
+
+var modelCatalogLog = logger.New("workflow:model_catalog")
+
+//go:embed data/model_multipliers.json
+var builtinModelMultipliersJSON []byte
+
+// BuiltinModelMultipliersJSON returns the embedded data/model_multipliers.json content.
+func BuiltinModelMultipliersJSON() []byte {
+	return builtinModelMultipliersJSON
+}
+
+// BuiltinModelMultipliers returns the built-in Effective Tokens multipliers keyed by
+// lowercase model name. The returned map is a freshly allocated copy.
+func BuiltinModelMultipliers() map[string]float64 {
+	var data struct {
+		Multipliers map[string]float64 `json:"multipliers"`
+	}
+	if err := json.Unmarshal(builtinModelMultipliersJSON, &data); err != nil {
+		panic(fmt.Sprintf("workflow: failed to parse embedded model_multipliers.json: %v (try 'make build' to rebuild with the latest data)", err))
+	}
+	result := make(map[string]float64, len(data.Multipliers))
+	for model, multiplier := range data.Multipliers {
+		result[strings.ToLower(model)] = multiplier
+	}
+	return result
+}
+
+// LookupModelMultiplier returns the multiplier for a model name (with or without a
+// provider prefix). An exact match wins; otherwise the longest prefix match is used,
+// the same rule as effective token computation.
+func LookupModelMultiplier(model string, multipliers map[string]float64) (float64, bool) {
+	key := strings.ToLower(strings.TrimSpace(model))
+	if _, name, ok := strings.Cut(key, "/"); ok {
+		key = name
+	}
+	if key == "" {
+		return 0, false
+	}
+	if multiplier, ok := multipliers[key]; ok {
+		return multiplier, true
+	}
+	best := ""
+	for name := range multipliers {
+		if strings.HasPrefix(key, name) && len(name) > len(best) {
+			best = name
+		}
+	}
+	if best == "" {
+		return 0, false
+	}
+	return multipliers[best], true
+}
+
+// ModelCostClass groups models by their Effective Tokens multiplier.
+type ModelCostClass string
+
+const (
+	ModelCostClassEconomy  ModelCostClass = "economy"  // multiplier below 0.5
+	ModelCostClassStandard ModelCostClass = "standard" // multiplier from 0.5 up to 5
+	ModelCostClassPremium  ModelCostClass = "premium"  // multiplier of 5 or more
+	ModelCostClassUnknown  ModelCostClass = "unknown"  // model missing from the registry
+)
+
+// CostClassForMultiplier returns the cost class of an Effective Tokens multiplier.
+func CostClassForMultiplier(multiplier float64) ModelCostClass {
+	switch {
+	case multiplier < 0.5:
+		return ModelCostClassEconomy
+	case multiplier < 5:
+		return ModelCostClassStandard
+	default:
+		return ModelCostClassPremium
+	}
+}
+
+// engineModelProviders lists the provider prefixes each engine accepts in model
+// identifiers. Engines missing from the map accept any provider.
+var engineModelProviders = map[string][]string{
+	"claude":  {"anthropic"},
+	"codex":   {"openai"},
+	"copilot": {"copilot"},
+	"gemini":  {"google", "gemini"},
+}
+
+// providerVendors maps vendor-scoped providers to the model vendor they serve.
+var providerVendors = map[string]string{
+	"anthropic": "anthropic",
+	"openai":    "openai",
+	"google":    "google",
+	"gemini":    "google",
+}
+
+// modelVendor returns the vendor of a registry model name, or "" when the model is
+// only available through the Copilot gateway.
+func modelVendor(model string) string {
+	switch {
+	case strings.HasPrefix(model, "claude-"):
+		return "anthropic"
+	case strings.HasPrefix(model, "gpt-"), strings.HasPrefix(model, "o1"), strings.HasPrefix(model, "o3"), strings.HasPrefix(model, "o4"):
+		return "openai"
+	case strings.HasPrefix(model, "gemini-"), strings.HasPrefix(model, "gemma-"), strings.HasPrefix(model, "deep-research-"):
+		return "google"
+	default:
+		return ""
+	}
+}
+
+// EngineModelCatalog returns the provider-scoped models available to an engine,
+// sorted by name. It returns nil for engines without a model catalog (replay).
+func EngineModelCatalog(engineID string, multipliers map[string]float64) []string {
+	if engineID == "replay" {
+		return nil
+	}
+	providers, scoped := engineModelProviders[engineID]
+	if !scoped {
+		providers = []string{"copilot", "anthropic", "openai", "google"}
+	}
+
+	models := slices.Sorted(maps.Keys(multipliers))
+	var catalog []string
+	for _, provider := range providers {
+		vendor, vendorScoped := providerVendors[provider]
+		for _, model := range models {
+			if !vendorScoped || modelVendor(model) == vendor {
+				catalog = append(catalog, provider+"/"+model)
+			}
+		}
+	}
+	modelCatalogLog.Printf("Engine %s catalog has %d models", engineID, len(catalog))
+	return catalog
+}
+
+// ModelFallbackCandidate is one entry of a resolved model fallback chain.
+type ModelFallbackCandidate struct {
+	Entry      string         // Alias list entry, e.g. "copilot/*sonnet*"
+	Via        []string       // Aliases expanded to reach the entry, outermost first
+	Model      string         // Catalog model the entry resolves to; empty when nothing matches
+	Multiplier float64        // Effective Tokens multiplier of Model
+	CostClass  ModelCostClass // Cost class of Model
+}
+
+// Resolved reports whether the candidate matched a model in the engine's catalog.
+func (c ModelFallbackCandidate) Resolved() bool {
+	return c.Model != ""
+}
+
+// ResolveModelFallbackChain expands model through the alias map and returns every
+// candidate in fallback order, each matched against the engine's catalog. The first
+// resolved candidate is the model the workflow runs with; later resolved candidates
+// are used when earlier ones are unavailable at runtime. Entries whose provider the
+// engine does not accept are left out. A model that is not an alias yields a single
+// candidate.
+func ResolveModelFallbackChain(model string, aliasMap map[string][]string, engineID string, multipliers map[string]float64) []ModelFallbackCandidate {
+	catalog := EngineModelCatalog(engineID, multipliers)
+	providers, scoped := engineModelProviders[engineID]
+
+	var chain []ModelFallbackCandidate
+	var expand func(entry string, via []string)
+	expand = func(entry string, via []string) {
+		base, _, _ := strings.Cut(entry, "?")
+		if isAliasReference(base, aliasMap) {
+			if slices.Contains(via, base) {
+				return // cycles are reported by V-MAF-010
+			}
+			for _, next := range aliasMap[base] {
+				expand(next, append(slices.Clone(via), base))
+			}
+			return
+		}
+		if provider, _, ok := strings.Cut(base, "/"); ok && scoped && !slices.Contains(providers, strings.ToLower(provider)) {
+			return
+		}
+		candidate := ModelFallbackCandidate{Entry: entry, Via: via, CostClass: ModelCostClassUnknown}
+		candidate.Model = matchModelCatalog(base, catalog)
+		if candidate.Model != "" {
+			if multiplier, ok := LookupModelMultiplier(candidate.Model, multipliers); ok {
+				candidate.Multiplier = multiplier
+				candidate.CostClass = CostClassForMultiplier(multiplier)
+			}
+		}
+		chain = append(chain, candidate)
+	}
+	expand(model, nil)
+
+	modelCatalogLog.Printf("Resolved %q for engine %s to %d candidate(s)", model, engineID, len(chain))
+	return chain
+}
+
+// matchModelCatalog returns the catalog model selected for an entry (§8.3). Globs
+// select the matching model with the highest version (§8.4); provider-scoped names
+// must be present in the catalog; bare names match any provider's model of that name.
+func matchModelCatalog(entry string, catalog []string) string {
+	entry = strings.ToLower(entry)
+	if !strings.Contains(entry, "/") {
+		for _, model := range catalog {
+			if _, name, _ := strings.Cut(model, "/"); name == entry {
+				return model
+			}
+		}
+		return ""
+	}
+	if !strings.Contains(entry, "*") {
+		if slices.Contains(catalog, entry) {
+			return entry
+		}
+		return ""
+	}
+
+	var best string
+	for _, model := range catalog {
+		if matched, _ := path.Match(entry, model); matched && (best == "" || compareModelVersions(model, best) > 0) {
+			best = model
+		}
+	}
+	return best
+}
+
+var (
+	modelVersionTokenPattern = regexp.MustCompile(`^\d+(\.\d+)*$`)
+	modelDateSuffixPattern   = regexp.MustCompile(`-(\d{8}|\d{4}-\d{2}-\d{2})$`)
+)
+
+// modelVersion extracts the version tuple and date suffix of a catalog model (§8.4.1).
+// Hyphen-separated version parts ("claude-opus-4-5") are read like dotted ones
+// ("claude-opus-4.5") so both spellings rank the same.
+func modelVersion(model string) ([]int, string) {
+	_, name, _ := strings.Cut(model, "/")
+	date := ""
+	if match := modelDateSuffixPattern.FindStringSubmatch(name); match != nil {
+		date = strings.ReplaceAll(match[1], "-", "")
+		name = strings.TrimSuffix(name, match[0])
+	}
+
+	tokens := strings.Split(name, "-")
+	end := len(tokens) - 1
+	for end >= 0 && !modelVersionTokenPattern.MatchString(tokens[end]) {
+		end--
+	}
+	if end < 0 {
+		return []int{0}, date
+	}
+	start := end
+	for start > 0 && modelVersionTokenPattern.MatchString(tokens[start-1]) {
+		start--
+	}
+	var version []int
+	for _, token := range tokens[start : end+1] {
+		for part := range strings.SplitSeq(token, ".") {
+			n, _ := strconv.Atoi(part)
+			version = append(version, n)
+		}
+	}
+	return version, date
+}
+
+// compareModelVersions orders catalog models by version, then date suffix (§8.4.2).
+func compareModelVersions(a, b string) int {
+	versionA, dateA := modelVersion(a)
+	versionB, dateB := modelVersion(b)
+	for i := range max(len(versionA), len(versionB)) {
+		var partA, partB int
+		if i < len(versionA) {
+			partA = versionA[i]
+		}
+		if i < len(versionB) {
+			partB = versionB[i]
+		}
+		if partA != partB {
+			return partA - partB
+		}
+	}
+	return strings.Compare(dateA, dateB)
+}
@@ -0,0 +1,187 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuiltinModelMultipliers(t *testing.T) {
+	multipliers := BuiltinModelMultipliers()
+	require.NotEmpty(t, multipliers, "embedded registry should not be empty")
+	assert.InDelta(t, 0.33, multipliers["claude-haiku-4.5"], 0.001, "keys should be lowercase model names")
+}
+
+func TestLookupModelMultiplier(t *testing.T) {
+	multipliers := map[string]float64{"gpt-5": 1.0, "gpt-5-mini": 0.33}
+
+	tests := []struct {
+		name     string
+		model    string
+		expected float64
+		found    bool
+	}{
+		{name: "exact match", model: "gpt-5-mini", expected: 0.33, found: true},
+		{name: "provider prefix is ignored", model: "copilot/GPT-5", expected: 1.0, found: true},
+		{name: "longest prefix match", model: "gpt-5-mini-2025", expected: 0.33, found: true},
+		{name: "unknown model", model: "llama-3", found: false},
+		{name: "empty model", model: "", found: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			multiplier, found := LookupModelMultiplier(tt.model, multipliers)
+			assert.Equal(t, tt.found, found, "found mismatch")
+			assert.InDelta(t, tt.expected, multiplier, 0.001, "multiplier mismatch")
+		})
+	}
+}
+
+func TestCostClassForMultiplier(t *testing.T) {
+	assert.Equal(t, ModelCostClassEconomy, CostClassForMultiplier(0.33))
+	assert.Equal(t, ModelCostClassStandard, CostClassForMultiplier(0.5))
+	assert.Equal(t, ModelCostClassStandard, CostClassForMultiplier(3))
+	assert.Equal(t, ModelCostClassPremium, CostClassForMultiplier(5))
+}
+
+func TestEngineModelCatalog(t *testing.T) {
+	multipliers := map[string]float64{"claude-sonnet-4": 1, "gpt-5": 1, "gemini-2.5-pro": 1, "raptor-mini": 0.33}
+
+	assert.Equal(t, []string{"anthropic/claude-sonnet-4"}, EngineModelCatalog("claude", multipliers))
+	assert.Equal(t, []string{"openai/gpt-5"}, EngineModelCatalog("codex", multipliers))
+	assert.Equal(t, []string{"google/gemini-2.5-pro", "gemini/gemini-2.5-pro"}, EngineModelCatalog("gemini", multipliers))
+	assert.Len(t, EngineModelCatalog("copilot", multipliers), 4, "copilot serves every model")
+	assert.Contains(t, EngineModelCatalog("opencode", multipliers), "anthropic/claude-sonnet-4", "provider-agnostic engines include vendor-scoped models")
+	assert.Nil(t, EngineModelCatalog("replay", multipliers), "replay has no catalog")
+}
+
+// TestMatchModelCatalog_T_MAF_026 and T-MAF-027: the highest version, then the latest date, wins.
+func TestMatchModelCatalog_T_MAF_026(t *testing.T) {
+	catalog := []string{"copilot/claude-opus-4", "copilot/claude-opus-4.5", "copilot/claude-opus-4-1"}
+	assert.Equal(t, "copilot/claude-opus-4.5", matchModelCatalog("copilot/*opus*", catalog))
+
+	catalog = []string{"copilot/claude-sonnet-4.5-20250310", "copilot/claude-sonnet-4.5-20250514"}
+	assert.Equal(t, "copilot/claude-sonnet-4.5-20250514", matchModelCatalog("copilot/*sonnet*", catalog))
+
+	assert.Equal(t, "copilot/claude-opus-4", matchModelCatalog("claude-opus-4", []string{"copilot/claude-opus-4"}), "bare names match any provider")
+	assert.Empty(t, matchModelCatalog("openai/gpt-5", []string{"copilot/gpt-5"}), "scoped names must be in the catalog")
+}
+
+func TestModelVersion(t *testing.T) {
+	tests := []struct {
+		model   string
+		version []int
+		date    string
+	}{
+		{model: "copilot/claude-opus-4.5", version: []int{4, 5}},
+		{model: "copilot/claude-opus-4-5-20251101", version: []int{4, 5}, date: "20251101"},
+		{model: "openai/gpt-4.1-2025-04-14", version: []int{4, 1}, date: "20250414"},
+		{model: "copilot/gemini-2.5-flash-lite", version: []int{2, 5}},
+		{model: "copilot/model-without-version", version: []int{0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.model, func(t *testing.T) {
+			version, date := modelVersion(tt.model)
+			assert.Equal(t, tt.version, version, "version mismatch")
+			assert.Equal(t, tt.date, date, "date mismatch")
+		})
+	}
+}
+
+func TestResolveModelFallbackChain(t *testing.T) {
+	multipliers := map[string]float64{"claude-sonnet-4.6": 9, "claude-haiku-4.5": 0.33, "gpt-5": 1}
+	aliases := map[string][]string{
+		"sonnet": {"copilot/*sonnet*", "anthropic/*sonnet*"},
+		"haiku":  {"copilot/*haiku*", "anthropic/*haiku*"},
+		"smart":  {"sonnet?effort=high", "haiku", "copilot/gpt-6"},
+	}
+
+	chain := ResolveModelFallbackChain("smart", aliases, "claude", multipliers)
+	require.Len(t, chain, 2, "copilot entries should be left out for claude")
+	assert.Equal(t, "anthropic/*sonnet*", chain[0].Entry)
+	assert.Equal(t, []string{"smart", "sonnet"}, chain[0].Via)
+	assert.Equal(t, "anthropic/claude-sonnet-4.6", chain[0].Model)
+	assert.Equal(t, ModelCostClassPremium, chain[0].CostClass)
+	assert.Equal(t, "anthropic/claude-haiku-4.5", chain[1].Model)
+	assert.Equal(t, ModelCostClassEconomy, chain[1].CostClass)
+
+	chain = ResolveModelFallbackChain("smart", aliases, "copilot", multipliers)
+	require.Len(t, chain, 3, "copilot keeps its own entries")
+	assert.False(t, chain[2].Resolved(), "copilot/gpt-6 is not in the catalog")
+	assert.Equal(t, ModelCostClassUnknown, chain[2].CostClass)
+
+	chain = ResolveModelFallbackChain("gpt-5", aliases, "codex", multipliers)
+	require.Len(t, chain, 1, "concrete models yield one candidate")
+	assert.Equal(t, "openai/gpt-5", chain[0].Model)
+}
+
+func TestCompileWorkflowModelResolution(t *testing.T) {
+	tests := []struct {
+		name        string
+		frontmatter string
+		strict      bool
+		wantErr     string
+		wantWarning string
+	}{
+		{
+			name:        "alias resolving to one cost class",
+			frontmatter: "engine:\n  id: claude\n  model: sonnet",
+		},
+		{
+			name:        "fallback changes cost class (V-MAF-021)",
+			frontmatter: "engine:\n  id: claude\n  model: smart\nmodels:\n  smart: [sonnet, haiku]",
+			wantWarning: "falls back to anthropic/claude-haiku-4-5-20251001 (economy, 0.33x)",
+		},
+		{
+			name:        "alias without candidates for the engine (V-MAF-020)",
+			frontmatter: "engine:\n  id: codex\n  model: sonnet",
+			wantWarning: `alias "sonnet" does not resolve to any model known for engine 'codex'`,
+		},
+		{
+			name:        "alias without candidates is only a warning in strict mode",
+			frontmatter: "engine:\n  id: claude\n  model: mybest\nmodels:\n  mybest: [anthropic/claude-sonnet-5]",
+			strict:      true,
+			wantWarning: `alias "mybest" does not resolve to any model known for engine 'claude'`,
+		},
+		{
+			name:        "declared alias without candidates (V-MAF-020)",
+			frontmatter: "engine:\n  id: claude\nmodels:\n  fast: [openai/gpt-5-mini]",
+			wantWarning: `models: alias "fast" does not resolve`,
+		},
+		{
+			name:        "concrete model is not resolved",
+			frontmatter: "engine:\n  id: claude\n  model: claude-sonnet-4-custom",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, "models.md")
+			content := "---\non:\n  workflow_dispatch:\npermissions:\n  contents: read\n" + tt.frontmatter + "\n---\n\n# Models\n\nDo the task.\n"
+			require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+
+			compiler := NewCompiler()
+			compiler.SetStrictMode(tt.strict)
+			var err error
+			stderr := testutil.CaptureStderr(t, func() {
+				err = compiler.CompileWorkflow(path)
+			})
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			if tt.wantWarning != "" {
+				assert.Contains(t, stderr, tt.wantWarning, "expected a model resolution warning")
+			} else {
+				assert.NotContains(t, stderr, "V-MAF-02", "expected no model resolution warning")
+			}
+		})
+	}
+}
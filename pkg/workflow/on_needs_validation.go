@@ -236,7 +236,8 @@ func isReservedOnNeedsTarget(jobName string) bool {
 		string(constants.DetectionJobName),
 		string(constants.UnlockJobName),
 		"push_repo_memory",
-		"update_cache_memory":
+		"update_cache_memory",
+		"update_dependency_cache":
 		return true
 	default:
 		return false
@@ -0,0 +1,642 @@
+// This file contains the organization policy loaded from .github/aw/policy.yml.
+//
+// # Organization Policy
+//
+// The policy is a list of rules evaluated at compile time against the fully merged
+// WorkflowData (after imports and defaults are applied). Each rule names a policy
+// field and one or more checks:
+//
+//	extends: my-org/.github/aw/policy.yml@main   # optional central policy
+//	rules:
+//	  - id: ORG-001
+//	    description: Only approved engines
+//	    field: engine
+//	    in: [copilot, claude]
+//	  - id: ORG-002
+//	    field: timeout-minutes
+//	    max: 30
+//	waivers:
+//	  - workflow: nightly-release
+//	    rule: ORG-002
+//	    expires: 2026-12-31
+//	    justification: Release builds need 45 minutes until the cache migration lands
+//
+// Checks: required, in, not-in (glob patterns, case-insensitive), equals, min, max.
+// Rules from the central policy cannot be redefined or waived locally: waivers exempt
+// a single workflow from a single rule until their expiry date and must be declared
+// in the policy that defines the rule.
+//
+// When the GH_AW_ORG_POLICY environment variable names a central policy
+// (owner/repo/path[@ref]), that policy is enforced: it is loaded even without a
+// local policy file, and a local policy must extend it.
+
+package workflow
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/github/gh-aw/pkg/constants"
+	"github.com/goccy/go-yaml"
+)
+
+var orgPolicyLog = newValidationLogger("org_policy")
+
+// OrgPolicyPath is the location of the organization policy file relative to the repository root.
+const OrgPolicyPath = ".github/aw/policy.yml"
+
+// maxOrgPolicyExtendsDepth bounds how many central policies may be chained via extends.
+const maxOrgPolicyExtendsDepth = 3
+
+// OrgPolicyEnvVar names a central policy that every repository must extend.
+// Organizations set it as an organization variable for their compile checks.
+const OrgPolicyEnvVar = "GH_AW_ORG_POLICY"
+
+// orgPolicyWaiverExpiryWarning is how far ahead of expiry a waiver starts producing warnings.
+const orgPolicyWaiverExpiryWarning = 14 * 24 * time.Hour
+
+// Rule severities.
+const (
+	orgPolicySeverityError   = "error"
+	orgPolicySeverityWarning = "warning"
+)
+
+// orgPolicyNow returns the current time for waiver expiry checks. Replaced in tests.
+var orgPolicyNow = time.Now
+
+// OrgPolicy is the parsed organization policy.
+type OrgPolicy struct {
+	Extends string            `yaml:"extends,omitempty"`
+	Rules   []OrgPolicyRule   `yaml:"rules,omitempty"`
+	Waivers []OrgPolicyWaiver `yaml:"waivers,omitempty"`
+
+	chain []string // central policies merged into this policy, nearest first
+}
+
+// OrgPolicyRule is a single policy rule.
+type OrgPolicyRule struct {
+	ID          string   `yaml:"id"`
+	Description string   `yaml:"description,omitempty"`
+	Field       string   `yaml:"field"`
+	Severity    string   `yaml:"severity,omitempty"` // "error" (default) or "warning"
+	Required    bool     `yaml:"required,omitempty"`
+	In          []string `yaml:"in,omitempty"`
+	NotIn       []string `yaml:"not-in,omitempty"`
+	Equals      any      `yaml:"equals,omitempty"`
+	Min         *float64 `yaml:"min,omitempty"`
+	Max         *float64 `yaml:"max,omitempty"`
+
+	source string // policy file or workflowspec the rule was loaded from
+}
+
+// OrgPolicyWaiver exempts one workflow from one rule until Expires (YYYY-MM-DD, inclusive).
+type OrgPolicyWaiver struct {
+	Workflow      string `yaml:"workflow"`
+	Rule          string `yaml:"rule"`
+	Expires       string `yaml:"expires"`
+	Justification string `yaml:"justification"`
+
+	expiresAt time.Time
+}
+
+// OrgPolicyViolation is a rule that a workflow does not satisfy.
+type OrgPolicyViolation struct {
+	Rule    OrgPolicyRule
+	Message string
+	// ExpiredWaiver is set when a waiver for this workflow and rule exists but has expired.
+	ExpiredWaiver *OrgPolicyWaiver
+}
+
+// orgPolicyFieldKind is the value type of a policy field.
+type orgPolicyFieldKind int
+
+const (
+	orgPolicyKindString orgPolicyFieldKind = iota
+	orgPolicyKindList
+	orgPolicyKindNumber
+	orgPolicyKindBool
+)
+
+// orgPolicyField describes a value that policy rules can inspect.
+type orgPolicyField struct {
+	kind orgPolicyFieldKind
+	// frontmatterKey is the top-level frontmatter key used to position violations.
+	frontmatterKey string
+	// extract returns the field value, or nil when the workflow does not set it.
+	extract func(data *WorkflowData) any
+}
+
+// orgPolicyFields lists the fields available to policy rules.
+var orgPolicyFields = map[string]orgPolicyField{
+	"engine": {kind: orgPolicyKindString, frontmatterKey: "engine", extract: func(data *WorkflowData) any {
+		if data.EngineConfig == nil || data.EngineConfig.ID == "" {
+			return nil
+		}
+		return data.EngineConfig.ID
+	}},
+	"model": {kind: orgPolicyKindString, frontmatterKey: "engine", extract: func(data *WorkflowData) any {
+		if data.EngineConfig != nil && data.EngineConfig.Model != "" {
+			return data.EngineConfig.Model
+		}
+		// Without an explicit model the engine's default model is used
+		engineID := string(constants.DefaultEngine)
+		if data.EngineConfig != nil && data.EngineConfig.ID != "" {
+			engineID = data.EngineConfig.ID
+		}
+		if model := DefaultAgentModel(engineID); model != "" {
+			return model
+		}
+		return nil
+	}},
+	"triggers": {kind: orgPolicyKindList, frontmatterKey: "on", extract: func(data *WorkflowData) any {
+		return orgPolicyNilIfEmpty(workflowTriggerNames(data.On))
+	}},
+	"timeout-minutes": {kind: orgPolicyKindNumber, frontmatterKey: "timeout-minutes", extract: func(data *WorkflowData) any {
+		value := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(data.TimeoutMinutes), "timeout-minutes:"))
+		minutes, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil
+		}
+		return minutes
+	}},
+	"firewall": {kind: orgPolicyKindBool, frontmatterKey: "network", extract: func(data *WorkflowData) any {
+		return isFirewallEnabled(data)
+	}},
+	"network-allowed": {kind: orgPolicyKindList, frontmatterKey: "network", extract: func(data *WorkflowData) any {
+		if data.NetworkPermissions == nil {
+			return nil
+		}
+		return orgPolicyNilIfEmpty(data.NetworkPermissions.Allowed)
+	}},
+	"safe-outputs": {kind: orgPolicyKindList, frontmatterKey: "safe-outputs", extract: func(data *WorkflowData) any {
+		return orgPolicyNilIfEmpty(safeOutputTypeNames(data.SafeOutputs))
+	}},
+	"target-repos": {kind: orgPolicyKindList, frontmatterKey: "safe-outputs", extract: func(data *WorkflowData) any {
+		return orgPolicyNilIfEmpty(safeOutputTargetRepos(data.SafeOutputs))
+	}},
+	"max-effective-tokens": {kind: orgPolicyKindNumber, frontmatterKey: "max-effective-tokens", extract: func(data *WorkflowData) any {
+		if data.EngineConfig == nil || data.EngineConfig.MaxEffectiveTokens <= 0 {
+			return nil
+		}
+		return float64(data.EngineConfig.MaxEffectiveTokens)
+	}},
+}
+
+// LoadOrgPolicy loads .github/aw/policy.yml from gitRoot, following extends references
+// to central policies. It returns nil when the repository has no policy file and no
+// central policy is required through GH_AW_ORG_POLICY.
+func LoadOrgPolicy(gitRoot string) (*OrgPolicy, error) {
+	required := strings.TrimSpace(os.Getenv(OrgPolicyEnvVar))
+	policyPath := filepath.Join(gitRoot, OrgPolicyPath)
+	content, err := os.ReadFile(policyPath)
+	if errors.Is(err, os.ErrNotExist) {
+		if required == "" {
+			orgPolicyLog.Printf("No organization policy at %s", policyPath)
+			return nil, nil
+		}
+		orgPolicyLog.Printf("No organization policy at %s, enforcing required central policy %s", policyPath, required)
+		content, err = []byte("extends: "+required+"\n"), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", OrgPolicyPath, err)
+	}
+
+	local, err := parseOrgPolicy(content, OrgPolicyPath)
+	if err != nil {
+		return nil, err
+	}
+
+	policy, err := resolveOrgPolicyExtends(local, 0)
+	if err != nil {
+		return nil, err
+	}
+	if required != "" && !slices.ContainsFunc(policy.chain, func(spec string) bool { return orgPolicySpecSatisfies(spec, required) }) {
+		return nil, fmt.Errorf("%s must extend the central policy %s required by %s", OrgPolicyPath, required, OrgPolicyEnvVar)
+	}
+	if err := policy.validate(); err != nil {
+		return nil, err
+	}
+	orgPolicyLog.Printf("Loaded organization policy: rules=%d, waivers=%d", len(policy.Rules), len(policy.Waivers))
+	return policy, nil
+}
+
+// parseOrgPolicy decodes a policy document, rejecting unknown keys.
+func parseOrgPolicy(content []byte, source string) (*OrgPolicy, error) {
+	var policy OrgPolicy
+	if err := yaml.UnmarshalWithOptions(content, &policy, yaml.DisallowUnknownField()); err != nil {
+		return nil, fmt.Errorf("invalid policy %s: %w", source, err)
+	}
+	for i := range policy.Rules {
+		policy.Rules[i].source = source
+	}
+	return &policy, nil
+}
+
+// resolveOrgPolicyExtends merges the central policy referenced by extends (if any) into
+// policy. Central rules come first and may not be redefined by the extending policy.
+func resolveOrgPolicyExtends(policy *OrgPolicy, depth int) (*OrgPolicy, error) {
+	if policy.Extends == "" {
+		return policy, nil
+	}
+	if depth >= maxOrgPolicyExtendsDepth {
+		return nil, fmt.Errorf("policy extends chain is deeper than %d levels at %s", maxOrgPolicyExtendsDepth, policy.Extends)
+	}
+
+	owner, repo, filePath, ref, err := parseOrgPolicySpec(policy.Extends)
+	if err != nil {
+		return nil, err
+	}
+	orgPolicyLog.Printf("Fetching central policy: %s", policy.Extends)
+	content, err := downloadOrgPolicyFile(owner, repo, filePath, ref)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch central policy %s: %w", policy.Extends, err)
+	}
+	central, err := parseOrgPolicy(content, policy.Extends)
+	if err != nil {
+		return nil, err
+	}
+	central, err = resolveOrgPolicyExtends(central, depth+1)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, waiver := range policy.Waivers {
+		if rule := central.findRule(waiver.Rule); rule != nil {
+			return nil, fmt.Errorf("policy waiver for workflow %s cannot waive rule %s defined by %s; waivers for central rules must be declared in the central policy", waiver.Workflow, waiver.Rule, rule.source)
+		}
+	}
+
+	merged := &OrgPolicy{
+		Rules:   slices.Clone(central.Rules),
+		Waivers: append(slices.Clone(central.Waivers), policy.Waivers...),
+		chain:   append([]string{policy.Extends}, central.chain...),
+	}
+	for _, rule := range policy.Rules {
+		if existing := merged.findRule(rule.ID); existing != nil {
+			return nil, fmt.Errorf("policy rule %s in %s is already defined by %s and cannot be redefined", rule.ID, rule.source, existing.source)
+		}
+		merged.Rules = append(merged.Rules, rule)
+	}
+	return merged, nil
+}
+
+// parseOrgPolicySpec parses owner/repo/path[@ref]; the ref defaults to main.
+func parseOrgPolicySpec(spec string) (owner, repo, filePath, ref string, err error) {
+	pathPart, ref, hasRef := strings.Cut(spec, "@")
+	if !hasRef || ref == "" {
+		ref = "main"
+	}
+	parts := strings.SplitN(pathPart, "/", 3)
+	if len(parts) < 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return "", "", "", "", fmt.Errorf("invalid policy extends %q: expected owner/repo/path[@ref]", spec)
+	}
+	return parts[0], parts[1], parts[2], ref, nil
+}
+
+// orgPolicySpecSatisfies reports whether an extends spec references the required
+// central policy. The ref only has to match when the required spec sets one.
+func orgPolicySpecSatisfies(spec, required string) bool {
+	specPath, specRef, _ := strings.Cut(spec, "@")
+	requiredPath, requiredRef, hasRef := strings.Cut(required, "@")
+	if !strings.EqualFold(specPath, requiredPath) {
+		return false
+	}
+	if !hasRef {
+		return true
+	}
+	if specRef == "" {
+		specRef = "main"
+	}
+	return specRef == requiredRef
+}
+
+// validate checks rule and waiver definitions.
+func (p *OrgPolicy) validate() error {
+	seen := make(map[string]bool)
+	for i := range p.Rules {
+		rule := &p.Rules[i]
+		if rule.ID == "" {
+			return fmt.Errorf("policy rule #%d in %s is missing an id", i+1, rule.source)
+		}
+		if seen[rule.ID] {
+			return fmt.Errorf("duplicate policy rule id %s in %s", rule.ID, rule.source)
+		}
+		seen[rule.ID] = true
+
+		field, ok := orgPolicyFields[rule.Field]
+		if !ok {
+			return fmt.Errorf("policy rule %s uses unknown field %q; valid fields: %s", rule.ID, rule.Field, strings.Join(orgPolicyFieldNames(), ", "))
+		}
+		if !rule.Required && len(rule.In) == 0 && len(rule.NotIn) == 0 && rule.Equals == nil && rule.Min == nil && rule.Max == nil {
+			return fmt.Errorf("policy rule %s has no checks; use required, in, not-in, equals, min or max", rule.ID)
+		}
+		if (rule.Min != nil || rule.Max != nil) && field.kind != orgPolicyKindNumber {
+			return fmt.Errorf("policy rule %s uses min/max on non-numeric field %q", rule.ID, rule.Field)
+		}
+		switch rule.Severity {
+		case "", orgPolicySeverityError, orgPolicySeverityWarning:
+		default:
+			return fmt.Errorf("policy rule %s has invalid severity %q; must be %s or %s", rule.ID, rule.Severity, orgPolicySeverityError, orgPolicySeverityWarning)
+		}
+	}
+
+	for i := range p.Waivers {
+		waiver := &p.Waivers[i]
+		if waiver.Workflow == "" || waiver.Rule == "" {
+			return fmt.Errorf("policy waiver #%d must set workflow and rule", i+1)
+		}
+		if !seen[waiver.Rule] {
+			return fmt.Errorf("policy waiver for workflow %s references unknown rule %s", waiver.Workflow, waiver.Rule)
+		}
+		if strings.TrimSpace(waiver.Justification) == "" {
+			return fmt.Errorf("policy waiver for workflow %s and rule %s must include a justification", waiver.Workflow, waiver.Rule)
+		}
+		expiresAt, err := time.Parse(time.DateOnly, waiver.Expires)
+		if err != nil {
+			return fmt.Errorf("policy waiver for workflow %s and rule %s has invalid expires %q: expected YYYY-MM-DD", waiver.Workflow, waiver.Rule, waiver.Expires)
+		}
+		waiver.expiresAt = expiresAt
+	}
+	return nil
+}
+
+func (p *OrgPolicy) findRule(id string) *OrgPolicyRule {
+	for i := range p.Rules {
+		if p.Rules[i].ID == id {
+			return &p.Rules[i]
+		}
+	}
+	return nil
+}
+
+// findWaiver returns the waiver for workflowName and ruleID, if any.
+func (p *OrgPolicy) findWaiver(workflowName, ruleID string) *OrgPolicyWaiver {
+	for i := range p.Waivers {
+		if p.Waivers[i].Workflow == workflowName && p.Waivers[i].Rule == ruleID {
+			return &p.Waivers[i]
+		}
+	}
+	return nil
+}
+
+// IsExpired reports whether the waiver no longer applies at now. A waiver is valid
+// through the end of its expiry date (UTC).
+func (w *OrgPolicyWaiver) IsExpired(now time.Time) bool {
+	return !now.UTC().Before(w.expiresAt.Add(24 * time.Hour))
+}
+
+// Evaluate checks data against every rule. Violations covered by an unexpired waiver for
+// workflowName are skipped and returned separately so callers can report them.
+func (p *OrgPolicy) Evaluate(data *WorkflowData, workflowName string) (violations []OrgPolicyViolation, waived []*OrgPolicyWaiver) {
+	if p == nil {
+		return nil, nil
+	}
+	now := orgPolicyNow()
+	for _, rule := range p.Rules {
+		message := rule.check(orgPolicyFields[rule.Field].extract(data))
+		if message == "" {
+			continue
+		}
+		violation := OrgPolicyViolation{Rule: rule, Message: message}
+		if waiver := p.findWaiver(workflowName, rule.ID); waiver != nil {
+			if !waiver.IsExpired(now) {
+				waived = append(waived, waiver)
+				continue
+			}
+			violation.ExpiredWaiver = waiver
+		}
+		violations = append(violations, violation)
+	}
+	return violations, waived
+}
+
+// check returns a description of why value fails the rule, or "" when it passes.
+func (r OrgPolicyRule) check(value any) string {
+	if value == nil {
+		if r.Required {
+			return r.Field + " is required"
+		}
+		return ""
+	}
+
+	values := orgPolicyValueStrings(value)
+	if len(r.In) > 0 {
+		for _, v := range values {
+			if !orgPolicyMatchesAny(v, r.In) {
+				return fmt.Sprintf("%s %q is not allowed; allowed: %s", r.Field, v, strings.Join(r.In, ", "))
+			}
+		}
+	}
+	for _, v := range values {
+		if orgPolicyMatchesAny(v, r.NotIn) {
+			return fmt.Sprintf("%s %q is not allowed", r.Field, v)
+		}
+	}
+	if r.Equals != nil && !strings.EqualFold(fmt.Sprint(value), fmt.Sprint(r.Equals)) {
+		return fmt.Sprintf("%s must be %v (got %v)", r.Field, r.Equals, value)
+	}
+	if number, ok := value.(float64); ok {
+		if r.Min != nil && number < *r.Min {
+			return fmt.Sprintf("%s is %s, minimum allowed is %s", r.Field, formatOrgPolicyNumber(number), formatOrgPolicyNumber(*r.Min))
+		}
+		if r.Max != nil && number > *r.Max {
+			return fmt.Sprintf("%s is %s, maximum allowed is %s", r.Field, formatOrgPolicyNumber(number), formatOrgPolicyNumber(*r.Max))
+		}
+	}
+	return ""
+}
+
+// IsWarning reports whether violations of the rule are reported as warnings.
+func (r OrgPolicyRule) IsWarning() bool {
+	return r.Severity == orgPolicySeverityWarning
+}
+
+// orgPolicyMatchesAny reports whether value matches any of the case-insensitive glob patterns.
+func orgPolicyMatchesAny(value string, patterns []string) bool {
+	value = strings.ToLower(value)
+	for _, pattern := range patterns {
+		pattern = strings.ToLower(pattern)
+		if pattern == value {
+			return true
+		}
+		if matched, err := path.Match(pattern, value); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+func orgPolicyValueStrings(value any) []string {
+	switch v := value.(type) {
+	case []string:
+		return v
+	case float64:
+		return []string{formatOrgPolicyNumber(v)}
+	default:
+		return []string{fmt.Sprint(v)}
+	}
+}
+
+func formatOrgPolicyNumber(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+func orgPolicyNilIfEmpty(values []string) any {
+	if len(values) == 0 {
+		return nil
+	}
+	return values
+}
+
+func orgPolicyFieldNames() []string {
+	names := make([]string, 0, len(orgPolicyFields))
+	for name := range orgPolicyFields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// workflowTriggerNames returns the event names from the rendered on: section.
+func workflowTriggerNames(onSection string) []string {
+	if strings.TrimSpace(onSection) == "" {
+		return nil
+	}
+	var parsed map[string]any
+	if err := yaml.Unmarshal([]byte(onSection), &parsed); err != nil {
+		orgPolicyLog.Printf("Failed to parse on: section: %v", err)
+		return nil
+	}
+	switch on := parsed["on"].(type) {
+	case string:
+		return []string{on}
+	case []any:
+		var names []string
+		for _, item := range on {
+			if name, ok := item.(string); ok {
+				names = append(names, name)
+			}
+		}
+		return names
+	case map[string]any:
+		names := make([]string, 0, len(on))
+		for name := range on {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return names
+	default:
+		return nil
+	}
+}
+
+// nonOutputSafeOutputsKeys are safe-outputs keys that configure the safe-outputs job
+// rather than enabling an output type.
+var nonOutputSafeOutputsKeys = map[string]bool{
+	"threat-detection": true,
+	"github-app":       true,
+	"messages":         true,
+	"mentions":         true,
+}
+
+// alwaysAvailableSafeOutputs are reporting outputs enabled for every workflow; they are
+// not subject to safe-output allowlists.
+var alwaysAvailableSafeOutputs = map[string]bool{
+	"noop":              true,
+	"missing-tool":      true,
+	"missing-data":      true,
+	"report-incomplete": true,
+}
+
+// safeOutputTypeNames returns the enabled safe-output types (their frontmatter keys),
+// sorted. Custom safe jobs, scripts and actions are reported as "jobs", "scripts" and
+// "actions".
+func safeOutputTypeNames(config *SafeOutputsConfig) []string {
+	if config == nil {
+		return nil
+	}
+	var names []string
+	value := reflect.ValueOf(config).Elem()
+	for i := range value.NumField() {
+		field := value.Type().Field(i)
+		key := yamlFieldName(field)
+		fieldValue := value.Field(i)
+		switch {
+		case key == "" || nonOutputSafeOutputsKeys[key] || alwaysAvailableSafeOutputs[key]:
+			continue
+		case key == "create-issue" && config.AutoInjectedCreateIssue:
+			continue
+		case fieldValue.Kind() == reflect.Pointer && fieldValue.Type().Elem().Kind() == reflect.Struct && !fieldValue.IsNil():
+			names = append(names, key)
+		case fieldValue.Kind() == reflect.Map && fieldValue.Len() > 0:
+			names = append(names, key)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// safeOutputTargetRepos returns every target-repo and allowed-repos value configured
+// across safe-output types, sorted and de-duplicated.
+func safeOutputTargetRepos(config *SafeOutputsConfig) []string {
+	if config == nil {
+		return nil
+	}
+	repos := make(map[string]bool)
+	value := reflect.ValueOf(config).Elem()
+	for i := range value.NumField() {
+		fieldValue := value.Field(i)
+		if fieldValue.Kind() == reflect.Pointer && !fieldValue.IsNil() && fieldValue.Elem().Kind() == reflect.Struct {
+			collectTargetRepos(fieldValue.Elem(), repos)
+		}
+	}
+	result := make([]string, 0, len(repos))
+	for repo := range repos {
+		result = append(result, repo)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// collectTargetRepos adds target-repo and allowed-repos values from a safe-output config
+// struct (including embedded structs) to repos.
+func collectTargetRepos(value reflect.Value, repos map[string]bool) {
+	for i := range value.NumField() {
+		field := value.Type().Field(i)
+		fieldValue := value.Field(i)
+		if field.Anonymous && fieldValue.Kind() == reflect.Struct {
+			collectTargetRepos(fieldValue, repos)
+			continue
+		}
+		switch yamlFieldName(field) {
+		case "target-repo":
+			if fieldValue.Kind() == reflect.String && fieldValue.String() != "" {
+				repos[fieldValue.String()] = true
+			}
+		case "allowed-repos":
+			if fieldValue.Kind() == reflect.Slice && fieldValue.Type().Elem().Kind() == reflect.String {
+				for j := range fieldValue.Len() {
+					repos[fieldValue.Index(j).String()] = true
+				}
+			}
+		}
+	}
+}
+
+// yamlFieldName returns the yaml key for a struct field, or "" when it is not serialized.
+func yamlFieldName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+	if name == "-" {
+		return ""
+	}
+	return name
+}
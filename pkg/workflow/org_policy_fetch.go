@@ -0,0 +1,8 @@
+//go:build !js && !wasm
+
+package workflow
+
+import "github.com/github/gh-aw/pkg/parser"
+
+// downloadOrgPolicyFile fetches a central policy file. Replaced in tests.
+var downloadOrgPolicyFile = parser.DownloadFileFromGitHub
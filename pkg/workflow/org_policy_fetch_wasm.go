@@ -0,0 +1,9 @@
+//go:build js || wasm
+
+package workflow
+
+import "errors"
+
+var downloadOrgPolicyFile = func(owner, repo, path, ref string) ([]byte, error) {
+	return nil, errors.New("central policies cannot be fetched in Wasm builds")
+}
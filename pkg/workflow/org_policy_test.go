@@ -0,0 +1,304 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeOrgPolicy(t *testing.T, gitRoot, content string) {
+	t.Helper()
+	dir := filepath.Join(gitRoot, ".github", "aw")
+	require.NoError(t, os.MkdirAll(dir, 0o755), "failed to create .github/aw")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "policy.yml"), []byte(content), 0o600), "failed to write policy.yml")
+}
+
+func setOrgPolicyNow(t *testing.T, now time.Time) {
+	t.Helper()
+	original := orgPolicyNow
+	orgPolicyNow = func() time.Time { return now }
+	t.Cleanup(func() { orgPolicyNow = original })
+}
+
+func orgPolicyTestWorkflowData() *WorkflowData {
+	return &WorkflowData{
+		EngineConfig:   &EngineConfig{ID: "codex", Model: "gpt-5"},
+		On:             "on:\n  issues:\n    types: [opened]\n  pull_request_target:\n",
+		TimeoutMinutes: "timeout-minutes: 45",
+		SafeOutputs: &SafeOutputsConfig{
+			CreateIssues: &CreateIssuesConfig{TargetRepoSlug: "other-org/tracker"},
+			AddComments:  &AddCommentsConfig{},
+			NoOp:         &NoOpConfig{},
+		},
+	}
+}
+
+func TestLoadOrgPolicy_Missing(t *testing.T) {
+	policy, err := LoadOrgPolicy(t.TempDir())
+	require.NoError(t, err, "missing policy file should not be an error")
+	assert.Nil(t, policy, "missing policy file should return nil")
+}
+
+func TestLoadOrgPolicy_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		errMsg  string
+	}{
+		{name: "unknown key", content: "rule: []\n", errMsg: "invalid policy"},
+		{name: "unknown field", content: "rules:\n  - id: A\n    field: color\n    required: true\n", errMsg: `unknown field "color"`},
+		{name: "no checks", content: "rules:\n  - id: A\n    field: engine\n", errMsg: "has no checks"},
+		{name: "min on string", content: "rules:\n  - id: A\n    field: engine\n    min: 1\n", errMsg: "non-numeric field"},
+		{name: "duplicate id", content: "rules:\n  - id: A\n    field: engine\n    required: true\n  - id: A\n    field: model\n    required: true\n", errMsg: "duplicate policy rule id A"},
+		{name: "waiver without justification", content: "rules:\n  - id: A\n    field: engine\n    required: true\nwaivers:\n  - workflow: w\n    rule: A\n    expires: \"2026-12-31\"\n", errMsg: "must include a justification"},
+		{name: "waiver bad date", content: "rules:\n  - id: A\n    field: engine\n    required: true\nwaivers:\n  - workflow: w\n    rule: A\n    expires: soon\n    justification: x\n", errMsg: "expected YYYY-MM-DD"},
+		{name: "waiver unknown rule", content: "rules:\n  - id: A\n    field: engine\n    required: true\nwaivers:\n  - workflow: w\n    rule: B\n    expires: \"2026-12-31\"\n    justification: x\n", errMsg: "unknown rule B"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeOrgPolicy(t, dir, tt.content)
+			_, err := LoadOrgPolicy(dir)
+			require.Error(t, err, "invalid policy should fail to load")
+			assert.Contains(t, err.Error(), tt.errMsg, "error should explain the problem")
+		})
+	}
+}
+
+func TestOrgPolicyEvaluate(t *testing.T) {
+	dir := t.TempDir()
+	writeOrgPolicy(t, dir, `rules:
+  - id: ENGINE
+    field: engine
+    in: [copilot, claude]
+  - id: MODEL
+    field: model
+    in: ["gpt-5*", "claude-*"]
+  - id: NO-PRT
+    field: triggers
+    not-in: [pull_request_target]
+  - id: TIMEOUT
+    field: timeout-minutes
+    max: 30
+  - id: FIREWALL
+    field: firewall
+    equals: true
+  - id: OUTPUTS
+    field: safe-outputs
+    in: [add-comment, create-issue]
+  - id: TARGETS
+    field: target-repos
+    in: ["my-org/*"]
+  - id: BUDGET
+    field: max-effective-tokens
+    required: true
+    severity: warning
+`)
+	policy, err := LoadOrgPolicy(dir)
+	require.NoError(t, err, "policy should load")
+
+	violations, waived := policy.Evaluate(orgPolicyTestWorkflowData(), "triage")
+	assert.Empty(t, waived, "no waivers should apply")
+
+	messages := make(map[string]string)
+	for _, v := range violations {
+		messages[v.Rule.ID] = v.Message
+	}
+	assert.Equal(t, `engine "codex" is not allowed; allowed: copilot, claude`, messages["ENGINE"], "unapproved engine should be reported")
+	assert.NotContains(t, messages, "MODEL", "model patterns should match")
+	assert.Equal(t, `triggers "pull_request_target" is not allowed`, messages["NO-PRT"], "forbidden trigger should be reported")
+	assert.Equal(t, "timeout-minutes is 45, maximum allowed is 30", messages["TIMEOUT"], "timeout should be capped")
+	assert.Equal(t, "firewall must be true (got false)", messages["FIREWALL"], "firewall should be required")
+	assert.NotContains(t, messages, "OUTPUTS", "always-available outputs should not be checked")
+	assert.Equal(t, `target-repos "other-org/tracker" is not allowed; allowed: my-org/*`, messages["TARGETS"], "target repos should be allowlisted")
+	assert.Equal(t, "max-effective-tokens is required", messages["BUDGET"], "budget should be mandatory")
+	assert.Len(t, violations, 6, "every failing rule should be reported")
+}
+
+func TestOrgPolicyWaivers(t *testing.T) {
+	dir := t.TempDir()
+	writeOrgPolicy(t, dir, `rules:
+  - id: TIMEOUT
+    field: timeout-minutes
+    max: 30
+waivers:
+  - workflow: triage
+    rule: TIMEOUT
+    expires: "2026-10-31"
+    justification: Large repository checkout
+`)
+	policy, err := LoadOrgPolicy(dir)
+	require.NoError(t, err, "policy should load")
+	data := orgPolicyTestWorkflowData()
+
+	setOrgPolicyNow(t, time.Date(2026, 10, 31, 23, 0, 0, 0, time.UTC))
+	violations, waived := policy.Evaluate(data, "triage")
+	assert.Empty(t, violations, "waiver should apply through its expiry date")
+	require.Len(t, waived, 1, "waived rule should be returned")
+
+	violations, _ = policy.Evaluate(data, "other")
+	assert.Len(t, violations, 1, "waiver should only apply to its workflow")
+
+	setOrgPolicyNow(t, time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC))
+	violations, waived = policy.Evaluate(data, "triage")
+	assert.Empty(t, waived, "expired waiver should not apply")
+	require.Len(t, violations, 1, "expired waiver should not suppress the violation")
+	require.NotNil(t, violations[0].ExpiredWaiver, "violation should reference the expired waiver")
+}
+
+func TestLoadOrgPolicy_Extends(t *testing.T) {
+	original := downloadOrgPolicyFile
+	t.Cleanup(func() { downloadOrgPolicyFile = original })
+
+	var requested []string
+	downloadOrgPolicyFile = func(owner, repo, path, ref string) ([]byte, error) {
+		requested = []string{owner, repo, path, ref}
+		return []byte("rules:\n  - id: CENTRAL\n    field: firewall\n    equals: true\n"), nil
+	}
+
+	dir := t.TempDir()
+	writeOrgPolicy(t, dir, `extends: my-org/policies/aw/policy.yml@v1
+rules:
+  - id: LOCAL
+    field: engine
+    in: [copilot]
+waivers:
+  - workflow: triage
+    rule: LOCAL
+    expires: "2026-12-31"
+    justification: Migration in progress
+`)
+	policy, err := LoadOrgPolicy(dir)
+	require.NoError(t, err, "extended policy should load")
+	assert.Equal(t, []string{"my-org", "policies", "aw/policy.yml", "v1"}, requested, "central policy should be fetched from the spec")
+	require.Len(t, policy.Rules, 2, "central and local rules should be merged")
+	assert.Equal(t, "CENTRAL", policy.Rules[0].ID, "central rules should come first")
+
+	writeOrgPolicy(t, dir, `extends: my-org/policies/aw/policy.yml@v1
+waivers:
+  - workflow: triage
+    rule: CENTRAL
+    expires: "2099-12-31"
+    justification: Not needed here
+`)
+	_, err = LoadOrgPolicy(dir)
+	require.Error(t, err, "local waivers should not apply to central rules")
+	assert.Contains(t, err.Error(), "cannot waive rule CENTRAL", "error should name the central rule")
+
+	writeOrgPolicy(t, dir, "extends: my-org/policies/aw/policy.yml\nrules:\n  - id: CENTRAL\n    field: firewall\n    equals: false\n")
+	_, err = LoadOrgPolicy(dir)
+	require.Error(t, err, "central rules should not be redefinable")
+	assert.Contains(t, err.Error(), "cannot be redefined", "error should explain the conflict")
+
+	downloadOrgPolicyFile = func(owner, repo, path, ref string) ([]byte, error) {
+		return nil, errors.New("not found")
+	}
+	_, err = LoadOrgPolicy(dir)
+	require.Error(t, err, "fetch errors should be reported")
+}
+
+func TestLoadOrgPolicy_RequiredCentralPolicy(t *testing.T) {
+	original := downloadOrgPolicyFile
+	t.Cleanup(func() { downloadOrgPolicyFile = original })
+	downloadOrgPolicyFile = func(owner, repo, path, ref string) ([]byte, error) {
+		return []byte("rules:\n  - id: CENTRAL\n    field: firewall\n    equals: true\n"), nil
+	}
+	t.Setenv(OrgPolicyEnvVar, "my-org/policies/aw/policy.yml@v1")
+
+	dir := t.TempDir()
+	policy, err := LoadOrgPolicy(dir)
+	require.NoError(t, err, "required central policy should load without a local policy file")
+	require.NotNil(t, policy, "required central policy should be enforced")
+	assert.Equal(t, "CENTRAL", policy.Rules[0].ID, "central rules should be loaded")
+
+	writeOrgPolicy(t, dir, "rules:\n  - id: LOCAL\n    field: engine\n    in: [copilot]\n")
+	_, err = LoadOrgPolicy(dir)
+	require.Error(t, err, "a local policy that drops extends should be rejected")
+	assert.Contains(t, err.Error(), "must extend the central policy", "error should name the required policy")
+
+	writeOrgPolicy(t, dir, "extends: my-org/policies/aw/policy.yml@v0\n")
+	_, err = LoadOrgPolicy(dir)
+	require.Error(t, err, "extending another ref of the required policy should be rejected")
+
+	writeOrgPolicy(t, dir, "extends: my-org/policies/aw/policy.yml@v1\n")
+	_, err = LoadOrgPolicy(dir)
+	require.NoError(t, err, "extending the required policy should be accepted")
+}
+
+func TestOrgPolicyEvaluate_DefaultModel(t *testing.T) {
+	policy := &OrgPolicy{Rules: []OrgPolicyRule{{ID: "MODEL", Field: "model", In: []string{"gpt-5*"}}}}
+
+	violations, _ := policy.Evaluate(&WorkflowData{EngineConfig: &EngineConfig{ID: "claude"}}, "triage")
+	require.Len(t, violations, 1, "the engine default model should be checked when engine.model is unset")
+	assert.Contains(t, violations[0].Message, `model "auto" is not allowed`)
+
+	violations, _ = policy.Evaluate(&WorkflowData{}, "triage")
+	require.Len(t, violations, 1, "the default engine's model should be checked when no engine is set")
+	assert.Contains(t, violations[0].Message, "claude-sonnet", "copilot defaults to its BYOK model")
+}
+
+func TestSafeOutputTypeNamesAndTargetRepos(t *testing.T) {
+	config := &SafeOutputsConfig{
+		CreateIssues:            &CreateIssuesConfig{TargetRepoSlug: "org/a", AllowedRepos: []string{"org/b"}},
+		AddComments:             &AddCommentsConfig{},
+		MissingTool:             &MissingToolConfig{},
+		ThreatDetection:         &ThreatDetectionConfig{},
+		Jobs:                    map[string]*SafeJobConfig{"deploy": {}},
+		AutoInjectedCreateIssue: false,
+	}
+	assert.Equal(t, []string{"add-comment", "create-issue", "jobs"}, safeOutputTypeNames(config), "only enabled output types should be listed")
+	assert.Equal(t, []string{"org/a", "org/b"}, safeOutputTargetRepos(config), "target-repo and allowed-repos should be collected")
+
+	config.AutoInjectedCreateIssue = true
+	assert.NotContains(t, safeOutputTypeNames(config), "create-issue", "auto-injected create-issue should be ignored")
+	assert.Nil(t, safeOutputTypeNames(nil), "nil config should have no outputs")
+}
+
+func TestCompileWorkflow_OrgPolicyViolation(t *testing.T) {
+	gitRoot := t.TempDir()
+	writeOrgPolicy(t, gitRoot, `rules:
+  - id: ORG-TIMEOUT
+    description: Keep runs short
+    field: timeout-minutes
+    max: 30
+`)
+	workflowsDir := filepath.Join(gitRoot, ".github", "workflows")
+	require.NoError(t, os.MkdirAll(workflowsDir, 0o755), "failed to create workflows dir")
+	mdPath := filepath.Join(workflowsDir, "slow.md")
+	require.NoError(t, os.WriteFile(mdPath, []byte(`---
+on:
+  workflow_dispatch:
+engine: copilot
+timeout-minutes: 45
+---
+Do something useful.
+`), 0o600), "failed to write workflow")
+
+	compiler := NewCompiler(WithNoEmit(true))
+	compiler.gitRoot = gitRoot
+	err := compiler.CompileWorkflow(mdPath)
+	require.Error(t, err, "policy violation should fail compilation")
+	assert.Contains(t, err.Error(), "policy violation [ORG-TIMEOUT]: timeout-minutes is 45, maximum allowed is 30 (Keep runs short)", "error should carry the rule ID")
+	assert.Contains(t, err.Error(), "slow.md:5:1", "error should point at the timeout-minutes line")
+
+	writeOrgPolicy(t, gitRoot, `rules:
+  - id: ORG-TIMEOUT
+    field: timeout-minutes
+    max: 30
+waivers:
+  - workflow: slow
+    rule: ORG-TIMEOUT
+    expires: "2099-12-31"
+    justification: Needs a full build
+`)
+	compiler = NewCompiler(WithNoEmit(true))
+	compiler.gitRoot = gitRoot
+	assert.NoError(t, compiler.CompileWorkflow(mdPath), "waived violation should not fail compilation")
+}
@@ -38,8 +38,6 @@ package workflow
 
 import (
 	"errors"
-	"fmt"
-	"os"
 )
 
 // validatePermissions validates all permission-related configuration: dangerous
@@ -128,8 +126,7 @@ func (c *Compiler) validatePermissions(workflowData *WorkflowData, markdownPath
 
 					// In non-strict mode, missing permissions are warnings.
 					// In strict mode with default-only toolsets, this is intentionally downgraded to warning.
-					fmt.Fprintln(os.Stderr, formatCompilerMessage(markdownPath, "warning", message))
-					c.IncrementWarningCount()
+					c.addWarningAt(markdownPath, message)
 				}
 			}
 		}
@@ -146,8 +143,7 @@ func (c *Compiler) validatePermissions(workflowData *WorkflowData, markdownPath
 		warningMsg := `This workflow grants id-token: write permission
 OIDC tokens can authenticate to cloud providers (AWS, Azure, GCP).
 Ensure proper audience validation and trust policies are configured.`
-		fmt.Fprintln(os.Stderr, formatCompilerMessage(markdownPath, "warning", warningMsg))
-		c.IncrementWarningCount()
+		c.addWarningAt(markdownPath, warningMsg)
 	}
 
 	return workflowPermissions, nil
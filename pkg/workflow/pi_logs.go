@@ -108,3 +108,87 @@ func (e *PiEngine) ParseLogMetrics(logContent string, verbose bool) LogMetrics {
 		metrics.Turns, metrics.TokenUsage, len(metrics.ToolCalls))
 	return metrics
 }
+
+// ParseAgentEvents converts Pi streaming JSONL into the normalized agent event stream.
+// Each non-delta assistant message starts a turn, matching how ParseLogMetrics counts turns.
+func (e *PiEngine) ParseAgentEvents(logContent string) []AgentEvent {
+	parser := newPiAgentEventParser(e)
+	for line := range strings.SplitSeq(logContent, "\n") {
+		parser.ParseLine(line)
+	}
+	return parser.stream.Events()
+}
+
+// NewAgentEventParser returns a parser for Pi streaming JSONL read line by line
+func (e *PiEngine) NewAgentEventParser() AgentEventParser {
+	return newPiAgentEventParser(e)
+}
+
+// piAgentEventParser parses Pi streaming JSONL line by line
+type piAgentEventParser struct {
+	stream    *AgentEventStream
+	toolNames map[string]string // tool ID -> tool name
+}
+
+// newPiAgentEventParser creates a Pi JSONL parser
+func newPiAgentEventParser(e *PiEngine) *piAgentEventParser {
+	return &piAgentEventParser{stream: NewAgentEventStream(e.GetID()), toolNames: make(map[string]string)}
+}
+
+// ParseLine emits the events of one JSONL line
+func (p *piAgentEventParser) ParseLine(line string) {
+	line = strings.TrimSpace(line)
+	if line == "" || !strings.HasPrefix(line, "{") {
+		return
+	}
+
+	var event piLogEvent
+	if err := json.Unmarshal([]byte(line), &event); err != nil {
+		return
+	}
+
+	switch event.Type {
+	case "init":
+		p.stream.SetModel(event.Model, "")
+
+	case "assistant":
+		if event.Delta || strings.TrimSpace(event.Content) == "" {
+			return
+		}
+		p.stream.StartTurn("")
+		p.stream.Emit(AgentEvent{Type: AgentEventAssistantMessage, Text: event.Content, OutputSize: len(event.Content)})
+
+	case "tool_use":
+		if event.ToolName == "" {
+			return
+		}
+		if event.ToolID != "" {
+			p.toolNames[event.ToolID] = event.ToolName
+		}
+		inputSize := 0
+		if event.Parameters != nil {
+			if paramsJSON, err := json.Marshal(event.Parameters); err == nil {
+				inputSize = len(paramsJSON)
+			}
+		}
+		p.stream.Emit(AgentEvent{Type: AgentEventToolCall, Tool: event.ToolName, ToolCallID: event.ToolID, InputSize: inputSize})
+
+	case "tool_result":
+		isError := event.Status != "" && event.Status != "success"
+		p.stream.Emit(AgentEvent{Type: AgentEventToolResult, Tool: p.toolNames[event.ToolID], ToolCallID: event.ToolID, OutputSize: len(event.Output), IsError: isError})
+		if isError {
+			p.stream.Emit(AgentEvent{Type: AgentEventError, Tool: p.toolNames[event.ToolID], ToolCallID: event.ToolID, Text: event.Output, IsError: true})
+		}
+
+	case "result":
+		if usage := agentEventUsageFromMap(event.Stats); usage != nil {
+			p.stream.Emit(AgentEvent{Type: AgentEventTokenUsage, Usage: usage})
+		}
+		p.stream.EndTurn("")
+	}
+}
+
+// Pending returns the events emitted since the previous call
+func (p *piAgentEventParser) Pending() []AgentEvent {
+	return p.stream.Pending()
+}
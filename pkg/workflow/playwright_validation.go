@@ -28,10 +28,6 @@
 package workflow
 
 import (
-	"fmt"
-	"os"
-
-	"github.com/github/gh-aw/pkg/console"
 	"github.com/github/gh-aw/pkg/logger"
 )
 
@@ -64,7 +60,6 @@ func (c *Compiler) validatePlaywrightMode(workflowData *WorkflowData) error {
 		"Update your prompts to run `playwright-cli <command>` in bash instead of using MCP browser tools. " +
 		"See: https://github.com/github/gh-aw/blob/main/docs/src/content/docs/reference/playwright.md"
 
-	fmt.Fprintln(os.Stderr, console.FormatWarningMessage(warningMsg))
-	c.IncrementWarningCount()
+	c.addWarning(warningMsg)
 	return nil
 }
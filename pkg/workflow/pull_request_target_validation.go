@@ -34,8 +34,6 @@
 package workflow
 
 import (
-	"fmt"
-	"os"
 	"strings"
 
 	"github.com/goccy/go-yaml"
@@ -109,8 +107,7 @@ func (c *Compiler) validatePullRequestTargetTrigger(workflowData *WorkflowData,
 			"Even with checkout: false, consider whether pull_request_target is truly necessary.\n" +
 			"If you only need to react to PR events without write access, use pull_request instead.\n" +
 			"See: https://securitylab.github.com/resources/github-actions-preventing-pwn-requests/"
-		fmt.Fprintln(os.Stderr, formatCompilerMessage(markdownPath, "warning", warningMsg))
-		c.IncrementWarningCount()
+		c.addWarningAt(markdownPath, warningMsg)
 	}
 
 	// If checkout is disabled, the workflow will not execute PR code — no further action needed.
@@ -137,8 +134,7 @@ func (c *Compiler) validatePullRequestTargetTrigger(workflowData *WorkflowData,
 	}
 
 	// Non-strict mode: emit a warning so existing workflows continue to compile.
-	fmt.Fprintln(os.Stderr, formatCompilerMessage(markdownPath, "warning", message))
-	c.IncrementWarningCount()
+	c.addWarningAt(markdownPath, message)
 
 	return nil
 }
@@ -1,11 +1,7 @@
 package workflow
 
 import (
-	"fmt"
-	"os"
 	"strings"
-
-	"github.com/github/gh-aw/pkg/console"
 )
 
 var pushToPullRequestBranchValidationLog = newValidationLogger("push_to_pull_request_branch_validation")
@@ -82,8 +78,7 @@ func (c *Compiler) validatePushToPullRequestBranchWarnings(safeOutputs *SafeOutp
 				"    fetch: [\"*\"]      # fetch all remote branches",
 				"    fetch-depth: 0   # fetch full history",
 			}, "\n")
-			fmt.Fprintln(os.Stderr, console.FormatWarningMessage(msg))
-			c.IncrementWarningCount()
+			c.addWarning(msg)
 		}
 	}
 
@@ -100,8 +95,7 @@ func (c *Compiler) validatePushToPullRequestBranchWarnings(safeOutputs *SafeOutp
 			"    title-prefix: \"[bot] \"  # only PRs whose title starts with this prefix",
 			"    labels: [automated]      # only PRs that carry all of these labels",
 		}, "\n")
-		fmt.Fprintln(os.Stderr, console.FormatWarningMessage(msg))
-		c.IncrementWarningCount()
+		c.addWarning(msg)
 	}
 }
 
@@ -0,0 +1,200 @@
+package workflow
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/constants"
+	"github.com/github/gh-aw/pkg/logger"
+)
+
+var replayLog = logger.New("workflow:replay_engine")
+
+const (
+	// replayTranscriptEnvVar names the transcript file replayed by the replay engine.
+	// The default can be overridden through engine.env.
+	replayTranscriptEnvVar = "GH_AW_REPLAY_TRANSCRIPT"
+
+	// replayAllowedToolsEnvVar carries the compiled tool policy as a JSON object
+	// mapping each MCP server ID to its allowed tools ("*" allows every tool).
+	replayAllowedToolsEnvVar = "GH_AW_REPLAY_ALLOWED_TOOLS"
+
+	// replayMCPConfigEnvVar names the gateway configuration written by
+	// convert_gateway_config_replay.cjs.
+	replayMCPConfigEnvVar = "GH_AW_REPLAY_MCP_CONFIG"
+
+	// replayMCPConfigPath is the host-side MCP configuration used by the replay runner.
+	replayMCPConfigPath = "/tmp/gh-aw/mcp-config/replay-mcp-config.json"
+
+	// ReplayTranscriptDir is the repository directory holding recorded transcripts.
+	ReplayTranscriptDir = ".github/aw/transcripts"
+)
+
+// ReplayEngine is a model-free engine for deterministic end-to-end workflow tests.
+// It replays a transcript of tool calls recorded from a real run (see
+// `gh aw logs --record-transcript`) against the workflow's MCP servers and the
+// safe-outputs MCP server, failing the run when a recorded call is not permitted
+// by the compiled tool configuration.
+type ReplayEngine struct {
+	BaseEngine
+}
+
+func NewReplayEngine() *ReplayEngine {
+	return &ReplayEngine{
+		BaseEngine: BaseEngine{
+			id:           "replay",
+			displayName:  "Replay",
+			description:  "Replays a recorded transcript of tool calls against the compiled MCP configuration without a model",
+			experimental: true,
+			capabilities: EngineCapabilities{
+				ToolsAllowlist: true, // Recorded calls are checked against the compiled allowlist
+				MaxTurns:       false,
+				WebSearch:      false,
+			},
+		},
+	}
+}
+
+// GetRequiredSecretNames returns the secrets needed by the MCP servers the transcript is
+// replayed against. The replay engine calls no model, so no provider key is required.
+func (e *ReplayEngine) GetRequiredSecretNames(workflowData *WorkflowData) []string {
+	replayLog.Print("Collecting required secrets for Replay engine")
+	secrets := collectCommonMCPSecrets(workflowData)
+
+	if hasGitHubTool(workflowData.ParsedTools) {
+		secrets = append(secrets, "GITHUB_MCP_SERVER_TOKEN")
+	}
+
+	headerSecrets := collectHTTPMCPHeaderSecrets(workflowData.Tools)
+	for varName := range headerSecrets {
+		secrets = append(secrets, varName)
+	}
+
+	return secrets
+}
+
+// GetInstallationSteps returns no steps: the replay runner ships with the setup action.
+func (e *ReplayEngine) GetInstallationSteps(workflowData *WorkflowData) []GitHubActionStep {
+	return []GitHubActionStep{}
+}
+
+// GetExecutionSteps returns the step that replays the recorded transcript.
+// The runner executes on the host and reaches the MCP gateway through
+// MCP_GATEWAY_HOST_DOMAIN, so no firewall container is started.
+func (e *ReplayEngine) GetExecutionSteps(workflowData *WorkflowData, logFile string) []GitHubActionStep {
+	replayLog.Printf("Generating execution steps for Replay engine: workflow=%s", workflowData.Name)
+
+	replayCommand := "node \"${RUNNER_TEMP}/gh-aw/actions/replay_engine.cjs\""
+	if workflowData.EngineConfig != nil && workflowData.EngineConfig.Command != "" {
+		replayCommand = workflowData.EngineConfig.Command
+	}
+	command := fmt.Sprintf("set -o pipefail\nprintf '%%s' \"$(date +%%s%%3N)\" > %s\n%s 2>&1 | tee -a %s", AgentCLIStartMsPath, replayCommand, logFile)
+
+	policyJSON, err := json.Marshal(computeReplayToolPolicy(workflowData))
+	if err != nil {
+		// Fall back to an empty policy, which makes every recorded MCP call fail the replay
+		replayLog.Printf("Failed to marshal replay tool policy, allowing no tools: %v", err)
+		policyJSON = []byte("{}")
+	}
+
+	env := map[string]string{
+		replayTranscriptEnvVar:   defaultReplayTranscriptPath(workflowData),
+		replayAllowedToolsEnvVar: string(policyJSON),
+		"GITHUB_WORKSPACE":       "${{ github.workspace }}",
+	}
+	if HasMCPServers(workflowData) {
+		env[replayMCPConfigEnvVar] = replayMCPConfigPath
+	}
+
+	applySafeOutputEnvToMap(env, workflowData)
+
+	// engine.env may point the engine at a different transcript
+	if workflowData.EngineConfig != nil && len(workflowData.EngineConfig.Env) > 0 {
+		maps.Copy(env, workflowData.EngineConfig.Env)
+	}
+
+	stepLines := []string{
+		"      - name: Replay transcript",
+		"        id: agentic_execution",
+	}
+	filteredEnv := FilterEnvForSecrets(env, e.GetRequiredSecretNames(workflowData))
+	stepLines = FormatStepWithCommandAndEnv(stepLines, command, filteredEnv)
+
+	return []GitHubActionStep{GitHubActionStep(stepLines)}
+}
+
+// RenderMCPConfig renders the gateway MCP configuration for the replay runner.
+func (e *ReplayEngine) RenderMCPConfig(sb *strings.Builder, tools map[string]any, mcpTools []string, workflowData *WorkflowData) error {
+	replayLog.Printf("Rendering MCP config for Replay: tool_count=%d, mcp_tool_count=%d", len(tools), len(mcpTools))
+
+	return renderStandardJSONMCPConfig(sb, tools, mcpTools, workflowData,
+		replayMCPConfigPath, false, false,
+		func(builder *strings.Builder, toolName string, toolConfig map[string]any, isLast bool) error {
+			return renderCustomMCPConfigWrapperWithContext(builder, toolName, toolConfig, isLast, workflowData)
+		}, nil)
+}
+
+// isReplayEngine reports whether the workflow's agent runs on the replay engine.
+func isReplayEngine(workflowData *WorkflowData) bool {
+	engineID := workflowData.AI
+	if engineID == "" && workflowData.EngineConfig != nil {
+		engineID = workflowData.EngineConfig.ID
+	}
+	return engineID == string(constants.ReplayEngine)
+}
+
+// defaultReplayTranscriptPath returns the checked-in transcript for the workflow:
+// .github/aw/transcripts/<workflow-id>.json in the workspace.
+func defaultReplayTranscriptPath(workflowData *WorkflowData) string {
+	return fmt.Sprintf("${{ github.workspace }}/%s/%s.json", ReplayTranscriptDir, workflowData.WorkflowID)
+}
+
+// computeReplayToolPolicy maps each gateway MCP server ID to the tools a replayed call
+// may invoke. The policy is strict: an explicit allowed list is used as-is unless it
+// contains "*", and servers absent from the map are rejected by the runner.
+func computeReplayToolPolicy(workflowData *WorkflowData) map[string][]string {
+	policy := make(map[string][]string)
+	for _, toolName := range collectMCPTools(workflowData) {
+		switch toolName {
+		case "safe-outputs":
+			policy[constants.SafeOutputsMCPServerID.String()] = []string{"*"}
+		case "mcp-scripts":
+			policy[constants.MCPScriptsMCPServerID.String()] = []string{"*"}
+		case "agentic-workflows":
+			policy[constants.AgenticWorkflowsMCPServerID.String()] = []string{"*"}
+		default:
+			policy[toolName] = replayAllowedTools(workflowData.Tools[toolName])
+		}
+	}
+	replayLog.Printf("Computed replay tool policy for %d servers", len(policy))
+	return policy
+}
+
+// replayAllowedTools returns the sorted allowed list of a tool configuration,
+// or ["*"] when the configuration allows every tool.
+func replayAllowedTools(toolConfig any) []string {
+	configMap, ok := toolConfig.(map[string]any)
+	if !ok {
+		return []string{"*"}
+	}
+	allowedList, ok := configMap["allowed"].([]any)
+	if !ok {
+		return []string{"*"}
+	}
+	allowed := []string{}
+	for _, item := range allowedList {
+		name, ok := item.(string)
+		if !ok {
+			continue
+		}
+		if name == "*" {
+			return []string{"*"}
+		}
+		allowed = append(allowed, name)
+	}
+	slices.Sort(allowed)
+	return slices.Compact(allowed)
+}
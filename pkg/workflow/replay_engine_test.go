@@ -0,0 +1,143 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/stringutil"
+	"github.com/github/gh-aw/pkg/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplayEngine(t *testing.T) {
+	engine := NewReplayEngine()
+
+	t.Run("engine identity", func(t *testing.T) {
+		assert.Equal(t, "replay", engine.GetID(), "Engine ID should be 'replay'")
+		assert.Equal(t, "Replay", engine.GetDisplayName(), "Display name should be 'Replay'")
+		assert.True(t, engine.IsExperimental(), "Replay engine should be experimental")
+		assert.True(t, engine.GetCapabilities().ToolsAllowlist, "Replay engine should enforce the tools allowlist")
+	})
+
+	t.Run("no model secrets or installation", func(t *testing.T) {
+		workflowData := &WorkflowData{Name: "test", ParsedTools: &ToolsConfig{}, Tools: map[string]any{}}
+		assert.Empty(t, engine.GetRequiredSecretNames(workflowData), "Replay engine should not require model secrets")
+		assert.Empty(t, engine.GetInstallationSteps(workflowData), "Replay engine should not install anything")
+		assert.Empty(t, engine.GetSecretValidationStep(workflowData), "Replay engine should not validate secrets")
+	})
+
+	t.Run("registered as built-in", func(t *testing.T) {
+		registered, err := NewEngineRegistry().GetEngine("replay")
+		require.NoError(t, err, "replay engine should be registered")
+		assert.Equal(t, "replay", registered.GetID())
+	})
+}
+
+func TestReplayEngineExecutionSteps(t *testing.T) {
+	engine := NewReplayEngine()
+	workflowData := &WorkflowData{
+		Name:        "triage",
+		WorkflowID:  "triage",
+		ParsedTools: &ToolsConfig{},
+		Tools: map[string]any{
+			"github": map[string]any{"allowed": []any{"list_issues", "get_issue"}},
+		},
+		SafeOutputs: &SafeOutputsConfig{AddComments: &AddCommentsConfig{}},
+	}
+
+	steps := engine.GetExecutionSteps(workflowData, "/tmp/gh-aw/agent-stdio.log")
+	require.Len(t, steps, 1, "Replay engine should emit a single execution step")
+	content := strings.Join(steps[0], "\n")
+
+	assert.Contains(t, content, "id: agentic_execution", "Step should carry the agentic execution ID")
+	assert.Contains(t, content, "replay_engine.cjs", "Step should run the replay runner")
+	assert.Contains(t, content, "tee -a /tmp/gh-aw/agent-stdio.log", "Step should tee output to the log file")
+	assert.Contains(t, content, "GH_AW_REPLAY_TRANSCRIPT: ${{ github.workspace }}/.github/aw/transcripts/triage.json",
+		"Transcript should default to the checked-in transcript for the workflow")
+	assert.Contains(t, content, `GH_AW_REPLAY_ALLOWED_TOOLS: '{"github":["get_issue","list_issues"],"safeoutputs":["*"]}'`,
+		"Allowed tools should reflect the compiled configuration")
+	assert.Contains(t, content, "GH_AW_REPLAY_MCP_CONFIG: /tmp/gh-aw/mcp-config/replay-mcp-config.json")
+	assert.NotContains(t, content, "awf", "Replay should not run inside the firewall")
+
+	t.Run("transcript override", func(t *testing.T) {
+		workflowData.EngineConfig = &EngineConfig{ID: "replay", Env: map[string]string{"GH_AW_REPLAY_TRANSCRIPT": "tests/triage-regression.json"}}
+		defer func() { workflowData.EngineConfig = nil }()
+
+		content := strings.Join(engine.GetExecutionSteps(workflowData, "/tmp/gh-aw/agent-stdio.log")[0], "\n")
+		assert.Contains(t, content, "GH_AW_REPLAY_TRANSCRIPT: tests/triage-regression.json", "engine.env should override the transcript")
+	})
+}
+
+func TestComputeReplayToolPolicy(t *testing.T) {
+	workflowData := &WorkflowData{
+		Tools: map[string]any{
+			"github":            map[string]any{"allowed": []any{"get_issue", "*"}},
+			"agentic-workflows": nil,
+			"notion": map[string]any{
+				"command": "npx",
+				"allowed": []any{"search", "search", "get_page"},
+			},
+			"locked": map[string]any{
+				"command": "npx",
+				"allowed": []any{},
+			},
+		},
+	}
+
+	policy := computeReplayToolPolicy(workflowData)
+	assert.Equal(t, []string{"*"}, policy["github"], "a wildcard should allow the whole server")
+	assert.Equal(t, []string{"*"}, policy["agenticworkflows"], "built-in servers should use their gateway ID")
+	assert.Equal(t, []string{"get_page", "search"}, policy["notion"], "allowed lists should be sorted and deduplicated")
+	assert.Equal(t, []string{}, policy["locked"], "an empty allowed list should permit no tools")
+	assert.NotContains(t, policy, "safeoutputs", "safe outputs should only be allowed when enabled")
+}
+
+func TestReplayEngineCompile(t *testing.T) {
+	tmpDir := testutil.TempDir(t, "replay-engine-test")
+
+	testContent := `---
+on: workflow_dispatch
+permissions:
+  contents: read
+  issues: read
+  pull-requests: read
+tools:
+  github:
+    allowed: [issue_read]
+safe-outputs:
+  add-comment:
+engine: replay
+---
+
+# Replay triage
+
+Replays a recorded triage run.
+`
+	testFile := filepath.Join(tmpDir, "triage.md")
+	require.NoError(t, os.WriteFile(testFile, []byte(testContent), 0644))
+
+	compiler := NewCompiler()
+	require.NoError(t, compiler.CompileWorkflow(testFile), "workflow using the replay engine should compile")
+
+	lockContent, err := os.ReadFile(stringutil.MarkdownToLockFile(testFile))
+	require.NoError(t, err)
+	lockStr := string(lockContent)
+
+	assert.Contains(t, lockStr, "- name: Replay transcript", "lock file should contain the replay step")
+	assert.Contains(t, lockStr, "GH_AW_ENGINE", "MCP gateway should be told the engine type")
+	assert.Contains(t, lockStr, `"github":["issue_read"]`, "lock file should embed the replay tool policy")
+	assert.NotContains(t, lockStr, "detection_agentic_execution", "threat detection has no model to run with the replay engine")
+	assert.Contains(t, lockStr, `GH_AW_SAFE_OUTPUTS_STAGED: "true"`, "replayed safe outputs should be staged by default")
+
+	unstaged := strings.Replace(testContent, "safe-outputs:\n", "safe-outputs:\n  staged: false\n", 1)
+	require.NoError(t, os.WriteFile(testFile, []byte(unstaged), 0644))
+	require.NoError(t, NewCompiler().CompileWorkflow(testFile))
+	lockContent, err = os.ReadFile(stringutil.MarkdownToLockFile(testFile))
+	require.NoError(t, err)
+	assert.NotContains(t, string(lockContent), "GH_AW_SAFE_OUTPUTS_STAGED", "an explicit staged: false should be kept")
+}
@@ -12,7 +12,33 @@
 //	    "runs_on": "custom runner", // string or string[] – runner label(s) for all
 //	    "action_failure_issue_expires": 72, // expiration (hours) for conclusion failure issues
 //	    "label_triggers": true // set to true to enable all label-triggered jobs (opt-in)
-//	  }                            // maintenance jobs (default: ubuntu-slim)
+//	  },                           // maintenance jobs (default: ubuntu-slim)
+//	  "mcp": {                      // MCP registry and trust policy settings
+//	    "registries": [             // registries queried by `gh aw mcp add`
+//	      {"name": "internal", "url": "https://mcp.example.com/v0.1", "priority": 10},
+//	      {"name": "mirror", "file": ".github/aw/mcp-registry.json"}
+//	    ],
+//	    "trust": {                  // enforced by `gh aw mcp add` and at compile time
+//	      "allowed_publishers": ["io.github.github"],
+//	      "pinned": {"io.github.github/github-mcp-server": "v1.2.3"},
+//	      "denied": ["io.github.example/*"]
+//	    }
+//	  },
+//	  "cost": {                     // price table for `gh aw logs --cost-report`
+//	    "currency": "USD",
+//	    "models": {"claude-sonnet-4.5": {"input": 3, "output": 15}}
+//	  },
+//	  "concurrency": {              // repository-wide agent job budget
+//	    "max_agent_jobs": 5,
+//	    "reserved": {"high": 2},    // slots only high priority workflows may use
+//	    "workflows": {"daily-*": "low"}
+//	  },
+//	  "updates": {                  // policy for `gh aw update --bot`
+//	    "schedule": "weekly",
+//	    "semver": "minor",
+//	    "exclude": ["experimental-*"],
+//	    "groups": [{"name": "dependencies", "updates": ["actions", "containers"]}]
+//	  }
 //	}
 //
 //	{
@@ -109,6 +135,22 @@ type RepoConfig struct {
 	// and an object was provided (nil when maintenance is not configured or is
 	// disabled).
 	Maintenance *MaintenanceConfig
+
+	// MCP holds MCP registry sources and the MCP server trust policy
+	// (nil when not configured).
+	MCP *MCPRepoConfig
+
+	// Cost holds the per-model price table used by cost reports
+	// (nil when not configured).
+	Cost *CostModelConfig
+
+	// Concurrency holds the repository-wide agent concurrency budget
+	// (nil when not configured).
+	Concurrency *AgentConcurrencyConfig
+
+	// Updates holds the automatic update policy used by the update bot
+	// (nil when not configured).
+	Updates *UpdatePolicyConfig
 }
 
 // UnmarshalJSON implements json.Unmarshaler to handle the polymorphic maintenance
@@ -116,14 +158,22 @@ type RepoConfig struct {
 func (r *RepoConfig) UnmarshalJSON(data []byte) error {
 	// Use an intermediate struct with json.RawMessage to defer maintenance parsing.
 	var raw struct {
-		GHES        bool            `json:"ghes,omitempty"`
-		Maintenance json.RawMessage `json:"maintenance,omitempty"`
+		GHES        bool                    `json:"ghes,omitempty"`
+		Maintenance json.RawMessage         `json:"maintenance,omitempty"`
+		MCP         *MCPRepoConfig          `json:"mcp,omitempty"`
+		Cost        *CostModelConfig        `json:"cost,omitempty"`
+		Concurrency *AgentConcurrencyConfig `json:"concurrency,omitempty"`
+		Updates     *UpdatePolicyConfig     `json:"updates,omitempty"`
 	}
 	if err := json.Unmarshal(data, &raw); err != nil {
 		return err
 	}
 
 	r.GHES = raw.GHES
+	r.MCP = raw.MCP
+	r.Cost = raw.Cost
+	r.Concurrency = raw.Concurrency
+	r.Updates = raw.Updates
 
 	if len(raw.Maintenance) == 0 || string(raw.Maintenance) == "null" {
 		return nil
@@ -175,6 +225,12 @@ func LoadRepoConfig(gitRoot string) (*RepoConfig, error) {
 	if err := json.Unmarshal(data, &cfg); err != nil {
 		return nil, fmt.Errorf("failed to parse %s: %w", RepoConfigFileName, err)
 	}
+	if err := cfg.Concurrency.validate(); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", RepoConfigFileName, err)
+	}
+	if err := cfg.Updates.validate(); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", RepoConfigFileName, err)
+	}
 
 	return &cfg, nil
 }
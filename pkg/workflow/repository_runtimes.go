@@ -0,0 +1,492 @@
+// This file detects the toolchains a repository needs from its own manifests so the agent
+// starts with the right runtimes, dependency caches and firewall domains instead of
+// spending turns installing toolchains that the firewall then blocks.
+//
+// Detection is enabled with the repository-runtimes feature flag:
+//
+//	features:
+//	  repository-runtimes: true
+//
+// The manifests are read at compile time from the repository that contains the workflow.
+// Each detected runtime is added to the workflow's runtimes unless the frontmatter (or an
+// import) already configures it, so explicit runtimes: entries always take precedence.
+// Because ecosystem domains are derived from runtimes, detected runtimes also open the
+// matching ecosystem in the firewall.
+
+package workflow
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/constants"
+	"github.com/github/gh-aw/pkg/fileutil"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/parser"
+)
+
+var repositoryRuntimesLog = logger.New("workflow:repository_runtimes")
+
+// RepositoryRuntime is a runtime detected from a repository manifest.
+type RepositoryRuntime struct {
+	ID      string // Runtime ID (e.g., "go", "node")
+	Version string // Version pinned by the manifest; empty uses the runtime default
+	Source  string // Manifest the runtime was detected from, relative to the repository root
+}
+
+// DependencyCache describes a dependency cache restored before the agent runs.
+type DependencyCache struct {
+	ID        string   // Package manager ID (e.g., "npm", "cargo")
+	Name      string   // Display name used in the step name
+	Paths     []string // Directories to cache
+	LockFiles []string // hashFiles patterns that key the cache
+}
+
+// repositoryRuntimeDetection is the result of scanning a repository's manifests.
+type repositoryRuntimeDetection struct {
+	runtimes map[string]*RepositoryRuntime
+	caches   []DependencyCache
+}
+
+// dependencyCaches maps package manager IDs to their cache directories and lockfiles.
+var dependencyCaches = map[string]DependencyCache{
+	"bun":   {ID: "bun", Name: "Bun packages", Paths: []string{"~/.bun/install/cache"}, LockFiles: []string{"**/bun.lock", "**/bun.lockb"}},
+	"cargo": {ID: "cargo", Name: "Cargo registry", Paths: []string{"~/.cargo/registry", "~/.cargo/git"}, LockFiles: []string{"**/Cargo.lock"}},
+	"go":    {ID: "go", Name: "Go modules", Paths: []string{"~/go/pkg/mod", "~/.cache/go-build"}, LockFiles: []string{"**/go.sum"}},
+	"npm":   {ID: "npm", Name: "npm packages", Paths: []string{"~/.npm"}, LockFiles: []string{"**/package-lock.json"}},
+	"pip":   {ID: "pip", Name: "pip packages", Paths: []string{"~/.cache/pip"}, LockFiles: []string{"**/requirements*.txt", "**/poetry.lock"}},
+	"pnpm":  {ID: "pnpm", Name: "pnpm store", Paths: []string{"~/.local/share/pnpm/store"}, LockFiles: []string{"**/pnpm-lock.yaml"}},
+	"uv":    {ID: "uv", Name: "uv packages", Paths: []string{"~/.cache/uv"}, LockFiles: []string{"**/uv.lock"}},
+	"yarn":  {ID: "yarn", Name: "Yarn packages", Paths: []string{"~/.cache/yarn"}, LockFiles: []string{"**/yarn.lock"}},
+}
+
+// toolVersionsRuntimes maps .tool-versions (asdf/mise) plugin names to runtime IDs.
+var toolVersionsRuntimes = map[string]string{
+	"bun":         "bun",
+	"deno":        "deno",
+	"dotnet":      "dotnet",
+	"dotnet-core": "dotnet",
+	"elixir":      "elixir",
+	"ghc":         "haskell",
+	"go":          "go",
+	"golang":      "go",
+	"java":        "java",
+	"node":        "node",
+	"nodejs":      "node",
+	"python":      "python",
+	"ruby":        "ruby",
+	"rust":        "rust",
+	"uv":          "uv",
+}
+
+var (
+	goModToolchainRegex    = regexp.MustCompile(`(?m)^toolchain\s+go(\S+)`)
+	goModGoRegex           = regexp.MustCompile(`(?m)^go\s+(\S+)`)
+	requiresPythonRegex    = regexp.MustCompile(`(?m)^\s*requires-python\s*=\s*["']([^"']+)["']`)
+	cargoRustVersionRegex  = regexp.MustCompile(`(?m)^\s*rust-version\s*=\s*["']([^"']+)["']`)
+	rustToolchainRegex     = regexp.MustCompile(`(?m)^\s*channel\s*=\s*["']([^"']+)["']`)
+	rustToolchainNameRegex = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+	manifestVersionRegex   = regexp.MustCompile(`\d+(\.\d+)*`)
+	minimumVersionOperator = regexp.MustCompile(`^(>=|~=|==|\^|~)?\s*v?(\d+(\.\d+)*)`)
+)
+
+// applyRepositoryRuntimes detects runtimes from the manifests of the repository that contains
+// the workflow and adds them to workflowData.Runtimes when the repository-runtimes feature is
+// enabled. Runtimes already configured in the frontmatter or imports are left untouched.
+func (c *Compiler) applyRepositoryRuntimes(workflowData *WorkflowData, markdownDir string) {
+	if !isFeatureEnabled(constants.RepositoryRuntimesFeatureFlag, workflowData) {
+		return
+	}
+	repoRoot := parser.FindGitHubRepoRoot(markdownDir)
+	if repoRoot == "" {
+		repositoryRuntimesLog.Printf("No repository root found for %s, skipping manifest detection", markdownDir)
+		return
+	}
+
+	detection := detectRepositoryRuntimes(repoRoot)
+	if workflowData.Runtimes == nil {
+		workflowData.Runtimes = make(map[string]any)
+	}
+	for _, id := range sortedRuntimeIDs(detection.runtimes) {
+		runtime := detection.runtimes[id]
+		if _, configured := workflowData.Runtimes[id]; configured {
+			repositoryRuntimesLog.Printf("Runtime %s detected from %s is configured in the frontmatter, keeping the frontmatter", id, runtime.Source)
+			continue
+		}
+		config := map[string]any{}
+		if runtime.Version != "" {
+			config["version"] = runtime.Version
+		}
+		workflowData.Runtimes[id] = config
+		workflowData.RepositoryRuntimes = append(workflowData.RepositoryRuntimes, *runtime)
+	}
+	workflowData.DependencyCaches = detection.caches
+
+	if c.verbose && len(workflowData.RepositoryRuntimes) > 0 {
+		var detected []string
+		for _, runtime := range workflowData.RepositoryRuntimes {
+			detected = append(detected, fmt.Sprintf("%s %s (%s)", runtime.ID, runtime.Version, runtime.Source))
+		}
+		fmt.Fprintf(os.Stderr, "Detected repository runtimes: %s\n", strings.Join(detected, ", "))
+	}
+}
+
+// detectRepositoryRuntimes scans the manifests at the repository root. Ecosystem-specific
+// manifests are read first; .tool-versions only fills in runtimes and versions they leave open.
+func detectRepositoryRuntimes(repoRoot string) *repositoryRuntimeDetection {
+	detection := &repositoryRuntimeDetection{runtimes: make(map[string]*RepositoryRuntime)}
+
+	detectGoManifest(repoRoot, detection)
+	detectNodeManifests(repoRoot, detection)
+	detectPythonManifests(repoRoot, detection)
+	detectRustManifests(repoRoot, detection)
+	detectDotNetManifest(repoRoot, detection)
+	detectToolVersions(repoRoot, detection)
+
+	sort.Slice(detection.caches, func(i, j int) bool { return detection.caches[i].ID < detection.caches[j].ID })
+	repositoryRuntimesLog.Printf("Detected %d runtimes and %d dependency caches in %s", len(detection.runtimes), len(detection.caches), repoRoot)
+	return detection
+}
+
+// add records a detected runtime. The first manifest that pins a version wins.
+func (d *repositoryRuntimeDetection) add(id, version, source string) {
+	if existing, ok := d.runtimes[id]; ok {
+		if existing.Version == "" && version != "" {
+			existing.Version = version
+			existing.Source = source
+		}
+		return
+	}
+	repositoryRuntimesLog.Printf("Detected runtime %s (version=%q) from %s", id, version, source)
+	d.runtimes[id] = &RepositoryRuntime{ID: id, Version: version, Source: source}
+}
+
+// addCache records a dependency cache when the package manager's lockfile is present.
+func (d *repositoryRuntimeDetection) addCache(repoRoot, id string) {
+	cache := dependencyCaches[id]
+	for _, pattern := range cache.LockFiles {
+		if matches, _ := filepath.Glob(filepath.Join(repoRoot, strings.TrimPrefix(pattern, "**/"))); len(matches) > 0 {
+			d.caches = append(d.caches, cache)
+			return
+		}
+	}
+}
+
+func detectGoManifest(repoRoot string, detection *repositoryRuntimeDetection) {
+	content, ok := readManifest(repoRoot, "go.mod")
+	if !ok {
+		return
+	}
+	version := ""
+	if match := goModToolchainRegex.FindStringSubmatch(content); match != nil {
+		version = match[1]
+	} else if match := goModGoRegex.FindStringSubmatch(content); match != nil {
+		version = match[1]
+	}
+	detection.add("go", version, "go.mod")
+	detection.addCache(repoRoot, "go")
+}
+
+func detectNodeManifests(repoRoot string, detection *repositoryRuntimeDetection) {
+	for _, lockFile := range []string{"bun.lock", "bun.lockb"} {
+		if fileutil.FileExists(filepath.Join(repoRoot, lockFile)) {
+			detection.add("bun", "", lockFile)
+			detection.addCache(repoRoot, "bun")
+			break
+		}
+	}
+
+	content, ok := readManifest(repoRoot, "package.json")
+	if !ok {
+		return
+	}
+	var manifest struct {
+		Engines map[string]any `json:"engines"`
+	}
+	if err := json.Unmarshal([]byte(content), &manifest); err != nil {
+		repositoryRuntimesLog.Printf("Failed to parse package.json: %v", err)
+	}
+	nodeRange, _ := manifest.Engines["node"].(string)
+	detection.add("node", minimumVersion(nodeRange), "package.json")
+	for _, id := range []string{"npm", "pnpm", "yarn"} {
+		detection.addCache(repoRoot, id)
+	}
+}
+
+func detectPythonManifests(repoRoot string, detection *repositoryRuntimeDetection) {
+	if content, ok := readManifest(repoRoot, ".python-version"); ok {
+		detection.add("python", firstManifestLine(content), ".python-version")
+	}
+	if content, ok := readManifest(repoRoot, "pyproject.toml"); ok {
+		version := ""
+		if match := requiresPythonRegex.FindStringSubmatch(content); match != nil {
+			version = minimumVersion(match[1])
+		}
+		detection.add("python", version, "pyproject.toml")
+	}
+	if fileutil.FileExists(filepath.Join(repoRoot, "requirements.txt")) {
+		detection.add("python", "", "requirements.txt")
+	}
+	if _, ok := detection.runtimes["python"]; !ok {
+		return
+	}
+	if fileutil.FileExists(filepath.Join(repoRoot, "uv.lock")) {
+		detection.add("uv", "", "uv.lock")
+		detection.addCache(repoRoot, "uv")
+		return
+	}
+	detection.addCache(repoRoot, "pip")
+}
+
+func detectRustManifests(repoRoot string, detection *repositoryRuntimeDetection) {
+	for _, name := range []string{"rust-toolchain.toml", "rust-toolchain"} {
+		content, ok := readManifest(repoRoot, name)
+		if !ok {
+			continue
+		}
+		version := firstManifestLine(content)
+		if match := rustToolchainRegex.FindStringSubmatch(content); match != nil {
+			version = match[1]
+		}
+		detection.add("rust", version, name)
+	}
+	if content, ok := readManifest(repoRoot, "Cargo.toml"); ok {
+		version := ""
+		if match := cargoRustVersionRegex.FindStringSubmatch(content); match != nil {
+			version = match[1]
+		}
+		detection.add("rust", version, "Cargo.toml")
+	}
+	if runtime, detected := detection.runtimes["rust"]; detected && runtime.Version != "" && !rustToolchainNameRegex.MatchString(runtime.Version) {
+		// Only channel, version and date toolchain names are passed on to rustup
+		repositoryRuntimesLog.Printf("Ignoring invalid Rust toolchain %q from %s", runtime.Version, runtime.Source)
+		runtime.Version = ""
+	}
+	if _, detected := detection.runtimes["rust"]; detected {
+		detection.addCache(repoRoot, "cargo")
+	}
+}
+
+func detectDotNetManifest(repoRoot string, detection *repositoryRuntimeDetection) {
+	content, ok := readManifest(repoRoot, "global.json")
+	if !ok {
+		return
+	}
+	var manifest struct {
+		SDK struct {
+			Version string `json:"version"`
+		} `json:"sdk"`
+	}
+	if err := json.Unmarshal([]byte(content), &manifest); err != nil {
+		repositoryRuntimesLog.Printf("Failed to parse global.json: %v", err)
+	}
+	detection.add("dotnet", manifest.SDK.Version, "global.json")
+}
+
+// detectToolVersions reads an asdf/mise .tool-versions file ("<plugin> <version> [<fallback>...]").
+func detectToolVersions(repoRoot string, detection *repositoryRuntimeDetection) {
+	content, ok := readManifest(repoRoot, ".tool-versions")
+	if !ok {
+		return
+	}
+	for line := range strings.SplitSeq(content, "\n") {
+		line, _, _ = strings.Cut(line, "#")
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		id, known := toolVersionsRuntimes[fields[0]]
+		if !known || fields[1] == "system" {
+			continue
+		}
+		version := fields[1]
+		if id == "java" {
+			// Java versions carry a distribution prefix (e.g. temurin-21.0.2+13.0.LTS)
+			version = manifestVersionRegex.FindString(version)
+		}
+		detection.add(id, version, ".tool-versions")
+	}
+}
+
+// minimumVersion extracts the lower bound of a version constraint such as ">=3.11" or "^20.1".
+// Constraints without a usable lower bound (e.g. "<4" or "*") yield an empty version.
+func minimumVersion(constraint string) string {
+	constraint, _, _ = strings.Cut(strings.TrimSpace(constraint), "||")
+	constraint, _, _ = strings.Cut(strings.TrimSpace(constraint), ",")
+	match := minimumVersionOperator.FindStringSubmatch(strings.TrimSpace(constraint))
+	if match == nil {
+		return ""
+	}
+	return match[2]
+}
+
+// firstManifestLine returns the first non-empty, non-comment line of a version file.
+func firstManifestLine(content string) string {
+	for line := range strings.SplitSeq(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "#") {
+			return line
+		}
+	}
+	return ""
+}
+
+func readManifest(repoRoot, name string) (string, bool) {
+	content, err := os.ReadFile(filepath.Join(repoRoot, name))
+	if err != nil {
+		return "", false
+	}
+	return string(content), true
+}
+
+func sortedRuntimeIDs(runtimes map[string]*RepositoryRuntime) []string {
+	ids := make([]string, 0, len(runtimes))
+	for id := range runtimes {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// generateDependencyCacheSteps emits a cache step for each dependency cache detected in the
+// repository. Keys are namespaced with gh-aw- so caches written by agent jobs are never
+// restored by the repository's own CI or release workflows, and only exact lockfile matches
+// are restored.
+//
+// When threat detection is enabled the agent job only restores the caches (actions/cache/restore).
+// Updated caches are uploaded as artifacts and saved by the update_dependency_cache job once
+// detection passes, so a run flagged by detection cannot poison the cache for later runs.
+func generateDependencyCacheSteps(builder *strings.Builder, data *WorkflowData) {
+	threatDetectionEnabled := IsDetectionJobEnabled(data.SafeOutputs)
+	for _, cache := range data.DependencyCaches {
+		hashArgs := make([]string, len(cache.LockFiles))
+		for i, pattern := range cache.LockFiles {
+			hashArgs[i] = "'" + pattern + "'"
+		}
+		fmt.Fprintf(builder, "      - name: Cache %s\n", cache.Name)
+		if threatDetectionEnabled {
+			fmt.Fprintf(builder, "        id: %s\n", dependencyCacheStepID(cache))
+			fmt.Fprintf(builder, "        uses: %s\n", getActionPin("actions/cache/restore"))
+		} else {
+			fmt.Fprintf(builder, "        uses: %s\n", getActionPin("actions/cache"))
+		}
+		builder.WriteString("        with:\n")
+		fmt.Fprintf(builder, "          key: gh-aw-%s-${{ runner.os }}-${{ hashFiles(%s) }}\n", cache.ID, strings.Join(hashArgs, ", "))
+		writeDependencyCachePaths(builder, cache)
+	}
+}
+
+// generateDependencyCacheArtifactUpload uploads the dependency caches that were not restored
+// from an exact key match, for the update_dependency_cache job to save after detection.
+// Nothing is uploaded when threat detection is disabled because actions/cache saves the caches.
+func generateDependencyCacheArtifactUpload(builder *strings.Builder, data *WorkflowData, pinAction func(string) string) {
+	if len(data.DependencyCaches) == 0 || !IsDetectionJobEnabled(data.SafeOutputs) {
+		return
+	}
+
+	prefix := artifactPrefixExprForDownstreamJob(data)
+	for _, cache := range data.DependencyCaches {
+		fmt.Fprintf(builder, "      - name: Upload %s as artifact\n", cache.Name)
+		fmt.Fprintf(builder, "        if: steps.%s.outputs.cache-hit != 'true'\n", dependencyCacheStepID(cache))
+		fmt.Fprintf(builder, "        uses: %s\n", pinAction("actions/upload-artifact"))
+		builder.WriteString("        with:\n")
+		fmt.Fprintf(builder, "          name: %s%s\n", prefix, dependencyCacheArtifactName(cache))
+		writeDependencyCachePaths(builder, cache)
+		builder.WriteString("          include-hidden-files: true\n")
+		builder.WriteString("          if-no-files-found: ignore\n")
+		builder.WriteString("          retention-days: 1\n")
+	}
+}
+
+// buildUpdateDependencyCacheJob builds the job that saves the dependency caches uploaded by the
+// agent job once detection passes. The cache keys are taken from the agent job's outputs because
+// this job has no checkout to hash the lockfiles. Returns nil when there is nothing to save.
+func (c *Compiler) buildUpdateDependencyCacheJob(data *WorkflowData) *Job {
+	if len(data.DependencyCaches) == 0 || !IsDetectionJobEnabled(data.SafeOutputs) {
+		return nil
+	}
+	repositoryRuntimesLog.Printf("Building update_dependency_cache job for %d caches", len(data.DependencyCaches))
+
+	prefix := artifactPrefixExprForAgentDownstreamJob(data)
+	var steps []string
+	for _, cache := range data.DependencyCaches {
+		downloadStepID := "download_" + dependencyCacheStepID(cache)
+
+		var step strings.Builder
+		fmt.Fprintf(&step, "      - name: Download %s artifact\n", cache.Name)
+		fmt.Fprintf(&step, "        id: %s\n", downloadStepID)
+		fmt.Fprintf(&step, "        uses: %s\n", c.getActionPin("actions/download-artifact"))
+		step.WriteString("        continue-on-error: true\n")
+		step.WriteString("        with:\n")
+		fmt.Fprintf(&step, "          name: %s%s\n", prefix, dependencyCacheArtifactName(cache))
+		fmt.Fprintf(&step, "          path: %s\n", dependencyCacheArtifactRoot(cache.Paths))
+
+		fmt.Fprintf(&step, "      - name: Save %s to cache\n", cache.Name)
+		fmt.Fprintf(&step, "        if: steps.%s.outcome == 'success'\n", downloadStepID)
+		fmt.Fprintf(&step, "        uses: %s\n", getActionPin("actions/cache/save"))
+		step.WriteString("        with:\n")
+		fmt.Fprintf(&step, "          key: ${{ needs.%s.outputs.%s }}\n", constants.AgentJobName, dependencyCacheKeyOutput(cache))
+		writeDependencyCachePaths(&step, cache)
+		steps = append(steps, step.String())
+	}
+
+	// Same condition as update_cache_memory: detection passed or was skipped, and the agent succeeded
+	agentSucceeded := BuildEquals(
+		BuildPropertyAccess(fmt.Sprintf("needs.%s.result", constants.AgentJobName)),
+		BuildStringLiteral("success"),
+	)
+
+	return &Job{
+		Name:        "update_dependency_cache",
+		RunsOn:      c.formatFrameworkJobRunsOn(data),
+		If:          RenderCondition(BuildAnd(BuildAnd(BuildFunctionCall("always"), buildDetectionPassedCondition()), agentSucceeded)),
+		Permissions: NewPermissionsEmpty().RenderToYAML(),
+		Needs:       []string{string(constants.AgentJobName), string(constants.DetectionJobName)},
+		Steps:       steps,
+	}
+}
+
+// writeDependencyCachePaths writes the path: input of a cache or artifact step.
+func writeDependencyCachePaths(builder *strings.Builder, cache DependencyCache) {
+	builder.WriteString("          path: |\n")
+	for _, cachePath := range cache.Paths {
+		fmt.Fprintf(builder, "            %s\n", cachePath)
+	}
+}
+
+// dependencyCacheArtifactRoot returns the directory the cache artifact is downloaded to.
+// upload-artifact roots a single directory at itself and several paths at their closest
+// common parent, so downloading there restores every path in place.
+func dependencyCacheArtifactRoot(paths []string) string {
+	if len(paths) == 1 {
+		return paths[0]
+	}
+	root := path.Dir(paths[0])
+	for _, p := range paths[1:] {
+		for root != "." && root != "/" && !strings.HasPrefix(p, root+"/") {
+			root = path.Dir(root)
+		}
+	}
+	return root
+}
+
+// dependencyCacheStepID returns the id of the agent job step that restores a dependency cache.
+func dependencyCacheStepID(cache DependencyCache) string {
+	return "dependency_cache_" + cache.ID
+}
+
+// dependencyCacheArtifactName returns the artifact a dependency cache is uploaded to.
+func dependencyCacheArtifactName(cache DependencyCache) string {
+	return "dependency-cache-" + cache.ID
+}
+
+// dependencyCacheKeyOutput returns the agent job output carrying a dependency cache's key.
+func dependencyCacheKeyOutput(cache DependencyCache) string {
+	return "dependency_cache_key_" + cache.ID
+}
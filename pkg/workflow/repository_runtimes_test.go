@@ -0,0 +1,202 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/stringutil"
+	"github.com/github/gh-aw/pkg/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeRepoFiles writes the given files (path → content) below root.
+func writeRepoFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+}
+
+func TestDetectRepositoryRuntimes(t *testing.T) {
+	root := t.TempDir()
+	writeRepoFiles(t, root, map[string]string{
+		"go.mod":            "module example.com/app\n\ngo 1.22\n\ntoolchain go1.22.3\n",
+		"go.sum":            "",
+		"package.json":      `{"name": "app", "engines": {"node": ">=20.11 <23"}}`,
+		"pnpm-lock.yaml":    "",
+		"pyproject.toml":    "[project]\nname = \"app\"\nrequires-python = \">=3.11\"\n",
+		"uv.lock":           "",
+		"Cargo.toml":        "[package]\nname = \"app\"\nrust-version = \"1.75\"\n",
+		"Cargo.lock":        "",
+		"global.json":       `{"sdk": {"version": "8.0.100"}}`,
+		".tool-versions":    "nodejs 18.19.0\nruby 3.3.0 # pinned\njava temurin-21.0.2+13.0.LTS\nterraform 1.7.0\npython system\n",
+		"unrelated/go.mod":  "module nested\n\ngo 1.21\n",
+		"requirements.txt":  "",
+		".python-version":   "# comment\n3.12.1\n",
+		"rust-toolchain":    "",
+		"docs/package.json": "{}",
+	})
+
+	detection := detectRepositoryRuntimes(root)
+
+	versions := make(map[string]string)
+	sources := make(map[string]string)
+	for id, runtime := range detection.runtimes {
+		versions[id] = runtime.Version
+		sources[id] = runtime.Source
+	}
+	assert.Equal(t, map[string]string{
+		"dotnet": "8.0.100",
+		"go":     "1.22.3",
+		"java":   "21.0.2",
+		"node":   "20.11",
+		"python": "3.12.1",
+		"ruby":   "3.3.0",
+		"rust":   "1.75",
+		"uv":     "",
+	}, versions, "ecosystem manifests should win over .tool-versions")
+	assert.Equal(t, ".python-version", sources["python"])
+	assert.Equal(t, "Cargo.toml", sources["rust"], "the first manifest that pins a version should be recorded")
+
+	var cacheIDs []string
+	for _, cache := range detection.caches {
+		cacheIDs = append(cacheIDs, cache.ID)
+	}
+	assert.Equal(t, []string{"cargo", "go", "pnpm", "uv"}, cacheIDs, "caches should only be added for lockfiles that exist")
+}
+
+func TestDetectRepositoryRuntimes_Empty(t *testing.T) {
+	detection := detectRepositoryRuntimes(t.TempDir())
+	assert.Empty(t, detection.runtimes)
+	assert.Empty(t, detection.caches)
+}
+
+func TestMinimumVersion(t *testing.T) {
+	tests := map[string]string{
+		">=3.11":           "3.11",
+		">= 20.11.0 <23":   "20.11.0",
+		"^18.2":            "18.2",
+		"~=3.10":           "3.10",
+		"v22":              "22",
+		"20 || 22":         "20",
+		">=3.9,<3.13":      "3.9",
+		"<4":               "",
+		"*":                "",
+		"":                 "",
+		"lts/*":            "",
+		"==3.12.*":         "3.12",
+		"22.x":             "22",
+		">=3.11.0, !=3.11": "3.11.0",
+	}
+	for constraint, expected := range tests {
+		assert.Equal(t, expected, minimumVersion(constraint), "minimumVersion(%q)", constraint)
+	}
+}
+
+func TestCompileWorkflowWithRepositoryRuntimes(t *testing.T) {
+	root := testutil.TempDir(t, "repository-runtimes-*")
+	writeRepoFiles(t, root, map[string]string{
+		"go.mod":       "module example.com/app\n\ngo 1.23.4\n",
+		"go.sum":       "",
+		"package.json": `{"engines": {"node": ">=20"}}`,
+		"Cargo.toml":   "[package]\nname = \"app\"\n",
+	})
+
+	workflow := `---
+on: push
+engine: copilot
+permissions:
+  contents: read
+features:
+  repository-runtimes: true
+runtimes:
+  node:
+    version: "22"
+---
+
+# Build
+
+Build the project.
+`
+	workflowFile := filepath.Join(root, ".github", "workflows", "build.md")
+	writeRepoFiles(t, root, map[string]string{".github/workflows/build.md": workflow})
+
+	require.NoError(t, NewCompiler().CompileWorkflow(workflowFile), "workflow should compile")
+	lockContent, err := os.ReadFile(stringutil.MarkdownToLockFile(workflowFile))
+	require.NoError(t, err)
+	lock := string(lockContent)
+
+	assert.Contains(t, lock, "- name: Setup Go", "go.mod should add a Go setup step")
+	assert.Contains(t, lock, "go-version: '1.23.4'", "Go should be pinned to the go.mod version")
+	assert.Contains(t, lock, "node-version: '22'", "frontmatter runtimes should override the package.json engines")
+	assert.Contains(t, lock, "RUST_TOOLCHAIN: 'stable'", "Cargo.toml without rust-version should use the default toolchain")
+	assert.Contains(t, lock, `rustup toolchain install "$RUST_TOOLCHAIN" --profile minimal`, "the toolchain should be passed through env")
+	assert.Contains(t, lock, "key: gh-aw-go-${{ runner.os }}-${{ hashFiles('**/go.sum') }}", "go.sum should add a Go module cache")
+	assert.NotContains(t, lock, "update_dependency_cache", "caches are saved by actions/cache without threat detection")
+	assert.Contains(t, lock, "proxy.golang.org", "the Go ecosystem should be allowed through the firewall")
+	assert.Contains(t, lock, "crates.io", "the Rust ecosystem should be allowed through the firewall")
+
+	// Without the feature flag the repository manifests are ignored
+	writeRepoFiles(t, root, map[string]string{".github/workflows/build.md": strings.Replace(workflow, "  repository-runtimes: true\n", "  repository-runtimes: false\n", 1)})
+	require.NoError(t, NewCompiler().CompileWorkflow(workflowFile), "workflow should compile")
+	lockContent, err = os.ReadFile(stringutil.MarkdownToLockFile(workflowFile))
+	require.NoError(t, err)
+	assert.NotContains(t, string(lockContent), "- name: Setup Go", "manifests should not be scanned without the feature flag")
+}
+
+func TestCompileWorkflowWithRepositoryRuntimes_ThreatDetection(t *testing.T) {
+	root := testutil.TempDir(t, "repository-runtimes-*")
+	writeRepoFiles(t, root, map[string]string{
+		"go.mod":     "module example.com/app\n\ngo 1.23.4\n",
+		"go.sum":     "",
+		"Cargo.lock": "",
+		"Cargo.toml": "[package]\nname = \"app\"\nrust-version = \"1.80; curl evil.example\"\n",
+		".github/workflows/build.md": `---
+on: push
+engine: copilot
+permissions:
+  contents: read
+features:
+  repository-runtimes: true
+safe-outputs:
+  create-issue:
+---
+
+# Build
+
+Build the project.
+`,
+	})
+
+	workflowFile := filepath.Join(root, ".github", "workflows", "build.md")
+	require.NoError(t, NewCompiler().CompileWorkflow(workflowFile), "workflow should compile")
+	lockContent, err := os.ReadFile(stringutil.MarkdownToLockFile(workflowFile))
+	require.NoError(t, err)
+	lock := string(lockContent)
+
+	assert.NotContains(t, lock, "curl evil.example", "invalid toolchain names should be dropped")
+	assert.Contains(t, lock, "RUST_TOOLCHAIN: 'stable'", "an invalid rust-version should fall back to the default toolchain")
+
+	assert.Contains(t, lock, "id: dependency_cache_go", "the agent job should only restore the cache")
+	assert.NotContains(t, lock, getActionPin("actions/cache")+"\n", "the agent job should not save the cache")
+	assert.Contains(t, lock, "dependency_cache_key_go: ${{ steps.dependency_cache_go.outputs.cache-primary-key }}", "the cache key should be an agent job output")
+	assert.Contains(t, lock, "if: steps.dependency_cache_go.outputs.cache-hit != 'true'", "exact cache hits should not be uploaded")
+
+	assert.Contains(t, lock, "  update_dependency_cache:", "the caches should be saved in a separate job")
+	assert.Contains(t, lock, "name: dependency-cache-go\n          path: ~\n", "the Go artifact should be downloaded to the paths' common parent")
+	assert.Contains(t, lock, "name: dependency-cache-cargo\n          path: ~/.cargo\n", "the Cargo artifact should be downloaded to the paths' common parent")
+	assert.Contains(t, lock, "key: ${{ needs.agent.outputs.dependency_cache_key_go }}", "the save job should reuse the agent job's key")
+}
+
+func TestDependencyCacheArtifactRoot(t *testing.T) {
+	assert.Equal(t, "~/.npm", dependencyCacheArtifactRoot([]string{"~/.npm"}))
+	assert.Equal(t, "~/.cargo", dependencyCacheArtifactRoot([]string{"~/.cargo/registry", "~/.cargo/git"}))
+	assert.Equal(t, "~", dependencyCacheArtifactRoot([]string{"~/go/pkg/mod", "~/.cache/go-build"}))
+}
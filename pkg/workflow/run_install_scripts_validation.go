@@ -33,9 +33,6 @@ package workflow
 
 import (
 	"fmt"
-	"os"
-
-	"github.com/github/gh-aw/pkg/console"
 )
 
 var runInstallScriptsLog = newValidationLogger("run_install_scripts")
@@ -97,7 +94,6 @@ func (c *Compiler) validateRunInstallScripts(workflowData *WorkflowData) error {
 		return fmt.Errorf("strict mode: %s", warningMsg)
 	}
 
-	fmt.Fprintln(os.Stderr, console.FormatWarningMessage(warningMsg))
-	c.IncrementWarningCount()
+	c.addWarning(warningMsg)
 	return nil
 }
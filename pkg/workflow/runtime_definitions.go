@@ -155,6 +155,16 @@ var knownRuntimes = []*Runtime{
 		Commands:       []string{"ruby", "gem", "bundle"},
 		ManifestFiles:  []string{"Gemfile", "Gemfile.lock"},
 	},
+	{
+		ID:   "rust",
+		Name: "Rust",
+		// Rust is set up with the rustup preinstalled on GitHub-hosted runners rather than
+		// a setup action (see generateSetupStep), so there is no action repository.
+		VersionField:   "toolchain",
+		DefaultVersion: string(constants.DefaultRustVersion),
+		Commands:       nil,
+		ManifestFiles:  nil,
+	},
 	{
 		ID:             "uv",
 		Name:           "uv",
@@ -188,7 +198,9 @@ func init() {
 	// Build the action repo to runtime mapping
 	actionRepoToRuntime = make(map[string]*Runtime)
 	for _, runtime := range knownRuntimes {
-		actionRepoToRuntime[runtime.ActionRepo] = runtime
+		if runtime.ActionRepo != "" {
+			actionRepoToRuntime[runtime.ActionRepo] = runtime
+		}
 	}
 	runtimeDefLog.Printf("Built action repo to runtime mapping: total_actions=%d", len(actionRepoToRuntime))
 }
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"maps"
 	"sort"
+	"strings"
 
 	"github.com/github/gh-aw/pkg/logger"
 )
@@ -84,6 +85,24 @@ func generateSetupStep(req *RuntimeRequirement) GitHubActionStep {
 		}
 	}
 
+	// Rust toolchains are installed with the runner's rustup instead of a third-party
+	// setup action, unless a custom action-repo is configured in runtimes.rust.
+	// The toolchain is passed through env so a version is never interpreted by the shell.
+	if runtime.ID == "rust" && runtime.ActionRepo == "" {
+		step := GitHubActionStep{"      - name: Setup " + runtime.Name}
+		if req.IfCondition != "" {
+			step = append(step, "        if: "+req.IfCondition)
+		}
+		step = append(step,
+			"        env:",
+			fmt.Sprintf("          RUST_TOOLCHAIN: '%s'", strings.ReplaceAll(version, "'", "''")),
+			"        run: |",
+			`          rustup toolchain install "$RUST_TOOLCHAIN" --profile minimal`,
+			`          rustup default "$RUST_TOOLCHAIN"`,
+		)
+		return step
+	}
+
 	// Use SHA-pinned action reference for security if available
 	actionRef := getActionPin(runtime.ActionRepo)
 
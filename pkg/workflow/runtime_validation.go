@@ -42,7 +42,6 @@ package workflow
 import (
 	"errors"
 	"fmt"
-	"os"
 	"strings"
 
 	"github.com/github/gh-aw/pkg/console"
@@ -250,8 +249,7 @@ func (c *Compiler) validateRuntimePackages(workflowData *WorkflowData) error {
 					// The workflow may still compile and run successfully in environments
 					// that have npm (e.g., GitHub Actions).
 					runtimeValidationLog.Print("npm not available, skipping npx package validation")
-					fmt.Fprintln(os.Stderr, console.FormatWarningMessage("npm not found, skipping npx package validation"))
-					c.IncrementWarningCount()
+					c.addWarning("npm not found, skipping npx package validation")
 				} else {
 					runtimeValidationLog.Printf("Npx package validation failed: %v", err)
 					errors = append(errors, err.Error())
@@ -58,7 +58,6 @@ func (c *Compiler) normalizeScheduleString(scheduleStr string, itemIndex int) (p
 			} else {
 				// Warn if repository slug is not available - scattering will not be org-aware
 				schedulePreprocessingLog.Printf("Warning: repository slug not available for fuzzy schedule scattering")
-				c.IncrementWarningCount()
 				c.addScheduleWarning("Fuzzy schedule scattering without repository context. Workflows with the same name in different repositories may collide. Ensure you are in a git repository with a configured remote.")
 			}
 		} else {
@@ -475,10 +474,6 @@ func (c *Compiler) addDailyCronWarning(cronExpr string) {
 			hour, minute,
 		)
 
-		// This warning is added to the warning count
-		// It will be collected and displayed by the compilation process
-		c.IncrementWarningCount()
-
 		// Store the warning for later display
 		c.addScheduleWarning(warningMsg)
 	}
@@ -502,9 +497,6 @@ func (c *Compiler) addHourlyCronWarning(cronExpr string) {
 			minute, interval,
 		)
 
-		// This warning is added to the warning count
-		c.IncrementWarningCount()
-
 		// Store the warning for later display
 		c.addScheduleWarning(warningMsg)
 	}
@@ -541,18 +533,17 @@ func (c *Compiler) addWeeklyCronWarning(cronExpr string) {
 			weekdayName, hour, minute, strings.ToLower(weekdayName),
 		)
 
-		// This warning is added to the warning count
-		c.IncrementWarningCount()
-
 		// Store the warning for later display
 		c.addScheduleWarning(warningMsg)
 	}
 }
 
-// addScheduleWarning adds a warning to the compiler's schedule warnings list
+// addScheduleWarning adds a warning to the compiler's schedule warnings list and counts it.
+// Schedule warnings are displayed by the compilation process after the workflow compiles.
 func (c *Compiler) addScheduleWarning(warning string) {
 	if c.scheduleWarnings == nil {
 		c.scheduleWarnings = []string{}
 	}
 	c.scheduleWarnings = append(c.scheduleWarnings, warning)
+	c.recordDiagnostic(console.CompilerError{Type: "warning", Message: warning})
 }
@@ -10,6 +10,7 @@ import (
 	"github.com/github/gh-aw/pkg/stringutil"
 
 	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/constants"
 	"github.com/github/gh-aw/pkg/logger"
 )
 
@@ -106,11 +107,42 @@ func (c *Compiler) processStopAfterConfiguration(frontmatter map[string]any, wor
 				fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("Parsed absolute stop-after from '%s' to: %s", originalStopTime, resolvedStopTime)))
 			}
 		}
+
+		if warning := stopTimeWarning(workflowData.WorkflowID, workflowData.StopTime, time.Now().UTC()); warning != "" {
+			c.addWarningAt(markdownPath, warning)
+		}
 	}
 
 	return nil
 }
 
+// StopTimeWarningWindow is how far ahead of a workflow's stop time the compiler
+// and the lifecycle command start warning that the workflow is about to stop.
+const StopTimeWarningWindow = 7 * 24 * time.Hour
+
+// ParseStopTime parses a resolved stop time ("YYYY-MM-DD HH:MM:SS", UTC) as
+// written to lock files.
+func ParseStopTime(stopTime string) (time.Time, error) {
+	return time.ParseInLocation("2006-01-02 15:04:05", stopTime, time.UTC)
+}
+
+// stopTimeWarning returns a warning when the resolved stop time has passed or is
+// within StopTimeWarningWindow of now, and an empty string otherwise.
+func stopTimeWarning(workflowID, stopTime string, now time.Time) string {
+	stop, err := ParseStopTime(stopTime)
+	if err != nil {
+		return ""
+	}
+	extend := fmt.Sprintf("Run '%s lifecycle extend %s' to extend it.", constants.CLIExtensionPrefix, workflowID)
+	if !now.Before(stop) {
+		return fmt.Sprintf("stop-after time %s UTC has passed; the workflow no longer runs its agent. %s", stopTime, extend)
+	}
+	if stop.Sub(now) <= StopTimeWarningWindow {
+		return fmt.Sprintf("stop-after time %s UTC is in less than %d days. %s", stopTime, int(StopTimeWarningWindow.Hours()/24), extend)
+	}
+	return ""
+}
+
 // resolveStopTime resolves a stop-time value to an absolute timestamp
 // If the stop-time is relative (starts with '+'), it calculates the absolute time
 // from the compilation time. Otherwise, it parses the absolute time using various formats.
@@ -271,3 +271,36 @@ jobs:
 		}
 	})
 }
+
+// TestStopTimeWarning tests the compile-time warning for stop times that passed or are near
+func TestStopTimeWarning(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		stopTime string
+		expected string
+	}{
+		{name: "passed", stopTime: "2026-02-28 12:00:00", expected: "has passed"},
+		{name: "exactly now", stopTime: "2026-03-01 12:00:00", expected: "has passed"},
+		{name: "within window", stopTime: "2026-03-05 12:00:00", expected: "is in less than 7 days"},
+		{name: "far away", stopTime: "2026-04-01 12:00:00", expected: ""},
+		{name: "invalid", stopTime: "not a time", expected: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			warning := stopTimeWarning("daily-report", tt.stopTime, now)
+			if tt.expected == "" {
+				if warning != "" {
+					t.Errorf("Expected no warning, got %q", warning)
+				}
+				return
+			}
+			if !strings.Contains(warning, tt.expected) {
+				t.Errorf("Expected warning to contain %q, got %q", tt.expected, warning)
+			}
+			if !strings.Contains(warning, "lifecycle extend daily-report") {
+				t.Errorf("Expected warning to suggest lifecycle extend, got %q", warning)
+			}
+		})
+	}
+}
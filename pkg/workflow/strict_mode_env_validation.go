@@ -7,10 +7,7 @@ package workflow
 
 import (
 	"fmt"
-	"os"
 	"strings"
-
-	"github.com/github/gh-aw/pkg/console"
 )
 
 // validateEnvSecrets detects secrets in the top-level env section and the engine.env section,
@@ -140,8 +137,7 @@ func (c *Compiler) validateEnvSecretsSection(config map[string]any, sectionName
 
 	// In non-strict mode, emit a warning
 	warningMsg := fmt.Sprintf("Warning: secrets detected in '%s' section will be leaked to the agent container. Found: %s. Consider using engine-specific secret configuration instead.", sectionName, strings.Join(secretRefs, ", "))
-	fmt.Fprintln(os.Stderr, console.FormatWarningMessage(warningMsg))
-	c.IncrementWarningCount()
+	c.addWarning(warningMsg)
 
 	return nil
 }
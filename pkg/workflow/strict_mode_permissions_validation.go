@@ -8,11 +8,9 @@ package workflow
 import (
 	"errors"
 	"fmt"
-	"os"
 	"slices"
 	"strings"
 
-	"github.com/github/gh-aw/pkg/console"
 	"github.com/github/gh-aw/pkg/parser"
 )
 
@@ -178,8 +176,7 @@ func (c *Compiler) validateStrictFirewall(engineID string, networkPermissions *N
 			warningMsg := "strict mode: recommend using ecosystem identifiers instead of individual domain names for better maintainability: " + strings.Join(suggestions, ", ")
 
 			// Print warning message and increment warning count
-			fmt.Fprintln(os.Stderr, console.FormatWarningMessage(warningMsg))
-			c.IncrementWarningCount()
+			c.addWarning(warningMsg)
 		}
 	}
 
@@ -14,13 +14,11 @@ package workflow
 
 import (
 	"fmt"
-	"os"
 	"regexp"
 	"slices"
 	"sort"
 	"strings"
 
-	"github.com/github/gh-aw/pkg/console"
 	"github.com/github/gh-aw/pkg/sliceutil"
 )
 
@@ -112,8 +110,7 @@ func (c *Compiler) validateStepsSectionSecrets(frontmatter map[string]any, secti
 			"Consider moving operations requiring secrets to a separate job outside the agent job.",
 		sectionName, strings.Join(allSecretRefs, ", "),
 	)
-	fmt.Fprintln(os.Stderr, console.FormatWarningMessage(warningMsg))
-	c.IncrementWarningCount()
+	c.addWarning(warningMsg)
 
 	return nil
 }
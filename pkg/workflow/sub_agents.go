@@ -0,0 +1,129 @@
+// This file handles sub-agents imported through the sub-agents: frontmatter field and
+// validates the restrictions of every sub-agent (inline or imported) against the
+// engine's capabilities.
+//
+// Imported sub-agents are appended to the prompt as ## agent: blocks after the main
+// workflow markdown. interpolate_prompt.cjs then writes them to the engine's agent
+// directory together with the inline sub-agents, so both kinds share one runtime path.
+
+package workflow
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/parser"
+)
+
+var subAgentsLog = logger.New("workflow:sub_agents")
+
+// extractSubAgentSpecs returns the specs listed in the sub-agents: frontmatter field.
+func extractSubAgentSpecs(frontmatter map[string]any) []string {
+	raw, ok := frontmatter["sub-agents"].([]any)
+	if !ok {
+		return nil
+	}
+	var specs []string
+	for _, item := range raw {
+		if spec, ok := item.(string); ok && spec != "" {
+			specs = append(specs, spec)
+		}
+	}
+	return specs
+}
+
+// resolveImportedSubAgents resolves the sub-agents: frontmatter field through the import
+// resolver. Remote specs that are not pinned to a commit SHA are rejected in strict mode
+// and produce a warning otherwise.
+func (c *Compiler) resolveImportedSubAgents(frontmatter map[string]any, markdownDir string) ([]*parser.ImportedSubAgent, error) {
+	specs := extractSubAgentSpecs(frontmatter)
+	if len(specs) == 0 {
+		return nil, nil
+	}
+	subAgentsLog.Printf("Resolving %d imported sub-agent(s)", len(specs))
+
+	var agents []*parser.ImportedSubAgent
+	seen := make(map[string]string)
+	for _, spec := range specs {
+		if !parser.IsSubAgentSpecPinned(spec) {
+			message := fmt.Sprintf("sub-agent '%s' is not pinned to a commit SHA; use owner/repo/path@<sha> so every run uses the same reviewed revision", spec)
+			if c.strictMode {
+				return nil, fmt.Errorf("strict mode: %s", message)
+			}
+			c.addWarning(message)
+		}
+
+		agent, err := parser.ResolveSubAgentImport(spec, markdownDir, c.getSharedImportCache())
+		if err != nil {
+			return nil, fmt.Errorf("failed to import sub-agent '%s': %w", spec, err)
+		}
+		if previous, exists := seen[agent.Name]; exists {
+			return nil, fmt.Errorf("sub-agents '%s' and '%s' both define sub-agent %q", previous, spec, agent.Name)
+		}
+		seen[agent.Name] = spec
+		agents = append(agents, agent)
+	}
+	return agents, nil
+}
+
+// validateSubAgents checks that inline and imported sub-agents have unique names and that
+// every restriction they declare can be enforced by the engine. Inline sub-agents whose
+// frontmatter cannot be parsed are skipped: those are already reported as warnings.
+func validateSubAgents(inline []parser.InlineSubAgent, imported []*parser.ImportedSubAgent, engine CodingAgentEngine) error {
+	names := make(map[string]bool, len(inline))
+	var errs []error
+	for _, agent := range inline {
+		names[agent.Name] = true
+		config, err := parser.ParseSubAgentConfig(agent.Content)
+		if err != nil {
+			subAgentsLog.Printf("Skipping capability validation of inline sub-agent %q: %v", agent.Name, err)
+			continue
+		}
+		errs = append(errs, validateSubAgentConfig(agent.Name, config, engine)...)
+	}
+	for _, agent := range imported {
+		if names[agent.Name] {
+			errs = append(errs, fmt.Errorf("sub-agent %q imported from '%s' conflicts with an inline sub-agent of the same name", agent.Name, agent.Spec))
+			continue
+		}
+		config, err := parser.ParseSubAgentConfig(agent.Content)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("sub-agent %q imported from '%s': %w", agent.Name, agent.Spec, err))
+			continue
+		}
+		errs = append(errs, validateSubAgentConfig(agent.Name, config, engine)...)
+	}
+	return errors.Join(errs...)
+}
+
+// validateSubAgentConfig returns an error for each sub-agent restriction the engine cannot enforce.
+func validateSubAgentConfig(name string, config *parser.SubAgentConfig, engine CodingAgentEngine) []error {
+	capabilities := engine.GetCapabilities()
+	var errs []error
+	if config.Tools != nil && !capabilities.SubAgentTools {
+		errs = append(errs, fmt.Errorf("sub-agent %q restricts 'tools', but engine '%s' cannot enforce a per-sub-agent tool allowlist; remove 'tools' or use an engine that supports it", name, engine.GetID()))
+	}
+	if config.MaxTurns > 0 && !capabilities.SubAgentMaxTurns {
+		errs = append(errs, fmt.Errorf("sub-agent %q sets 'max-turns', but engine '%s' cannot limit turns per sub-agent; remove 'max-turns' or use an engine that supports it", name, engine.GetID()))
+	}
+	if config.Network != nil && !capabilities.SubAgentNetwork {
+		errs = append(errs, fmt.Errorf("sub-agent %q restricts 'network', but engine '%s' runs all sub-agents behind the workflow firewall; restrict the workflow's 'network' instead", name, engine.GetID()))
+	}
+	return errs
+}
+
+// importedSubAgentPromptChunks renders imported sub-agents as ## agent: prompt blocks.
+// Expressions in sub-agent content are extracted into environment variables like any
+// other imported markdown.
+func importedSubAgentPromptChunks(agents []*parser.ImportedSubAgent) ([]string, []*ExpressionMapping) {
+	var chunks []string
+	var mappings []*ExpressionMapping
+	for _, agent := range agents {
+		block := fmt.Sprintf("## agent: `%s`\n\n%s", agent.Name, agent.Content)
+		agentChunks, agentMappings := extractPromptChunksFromMarkdown(block)
+		chunks = append(chunks, agentChunks...)
+		mappings = append(mappings, agentMappings...)
+	}
+	return chunks, mappings
+}
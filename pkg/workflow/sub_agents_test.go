@@ -0,0 +1,112 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/parser"
+	"github.com/github/gh-aw/pkg/stringutil"
+	"github.com/github/gh-aw/pkg/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateSubAgents(t *testing.T) {
+	restricted := "---\ntools: [Read]\nmax-turns: 3\n---\nPrompt"
+
+	t.Run("engine enforces tools but not max-turns", func(t *testing.T) {
+		inline := []parser.InlineSubAgent{{Name: "reviewer", Content: restricted}}
+		err := validateSubAgents(inline, nil, NewClaudeEngine())
+		require.Error(t, err, "claude cannot limit turns per sub-agent")
+		assert.Contains(t, err.Error(), "sets 'max-turns'")
+		assert.NotContains(t, err.Error(), "restricts 'tools'")
+	})
+
+	t.Run("engine enforces tools and max-turns", func(t *testing.T) {
+		inline := []parser.InlineSubAgent{{Name: "reviewer", Content: restricted}}
+		assert.NoError(t, validateSubAgents(inline, nil, NewGeminiEngine()), "gemini supports both restrictions")
+	})
+
+	t.Run("engine without sub-agent restrictions", func(t *testing.T) {
+		imported := []*parser.ImportedSubAgent{{Name: "reviewer", Spec: ".github/agents/reviewer.md", Content: restricted}}
+		err := validateSubAgents(nil, imported, NewCodexEngine())
+		require.Error(t, err, "codex cannot enforce sub-agent restrictions")
+		assert.Contains(t, err.Error(), "restricts 'tools'")
+		assert.Contains(t, err.Error(), "sets 'max-turns'")
+	})
+
+	t.Run("network restriction", func(t *testing.T) {
+		inline := []parser.InlineSubAgent{{Name: "fetcher", Content: "---\nnetwork:\n  allowed: [example.com]\n---\nPrompt"}}
+		err := validateSubAgents(inline, nil, NewGeminiEngine())
+		require.Error(t, err, "no engine isolates sub-agent network access")
+		assert.Contains(t, err.Error(), "restrict the workflow's 'network' instead")
+	})
+
+	t.Run("imported name conflicts with inline sub-agent", func(t *testing.T) {
+		inline := []parser.InlineSubAgent{{Name: "reviewer", Content: "Prompt"}}
+		imported := []*parser.ImportedSubAgent{{Name: "reviewer", Spec: ".github/agents/reviewer.md", Content: "Prompt"}}
+		err := validateSubAgents(inline, imported, NewCopilotEngine())
+		require.Error(t, err, "duplicate names should be rejected")
+		assert.Contains(t, err.Error(), "conflicts with an inline sub-agent")
+	})
+
+	t.Run("unrestricted sub-agents", func(t *testing.T) {
+		inline := []parser.InlineSubAgent{{Name: "summarizer", Content: "---\nmodel: claude-haiku-4.5\n---\nPrompt"}}
+		assert.NoError(t, validateSubAgents(inline, nil, NewCodexEngine()), "sub-agents without restrictions work on every engine")
+	})
+}
+
+func TestImportedSubAgentPromptChunks(t *testing.T) {
+	agents := []*parser.ImportedSubAgent{
+		{Name: "security-reviewer", Content: "---\ndescription: Reviews code\n---\nReview ${{ github.repository }}."},
+	}
+	chunks, mappings := importedSubAgentPromptChunks(agents)
+	prompt := strings.Join(chunks, "")
+
+	assert.Contains(t, prompt, "## agent: `security-reviewer`\n\n---\ndescription: Reviews code\n---\n")
+	assert.NotContains(t, prompt, "${{ github.repository }}", "expressions should be extracted into environment variables")
+	require.Len(t, mappings, 1, "one expression should be extracted")
+	assert.Equal(t, "github.repository", mappings[0].Content)
+}
+
+func TestCompileWorkflowWithImportedSubAgents(t *testing.T) {
+	tmpDir := testutil.TempDir(t, "sub-agents-*")
+	agentsDir := filepath.Join(tmpDir, "agents")
+	require.NoError(t, os.MkdirAll(agentsDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(agentsDir, "security-reviewer.md"), []byte("---\ndescription: Reviews changes for security issues\ntools: [Read, Grep]\n---\nYou are a security reviewer.\n"), 0644))
+
+	workflowFile := filepath.Join(tmpDir, "review.md")
+	workflow := `---
+on: pull_request
+engine: claude
+permissions:
+  contents: read
+sub-agents:
+  - agents/security-reviewer.md
+---
+
+# Review
+
+Use the security-reviewer sub-agent.
+`
+	require.NoError(t, os.WriteFile(workflowFile, []byte(workflow), 0644))
+
+	compiler := NewCompiler()
+	require.NoError(t, compiler.CompileWorkflow(workflowFile), "workflow with an imported sub-agent should compile")
+
+	lockContent, err := os.ReadFile(stringutil.MarkdownToLockFile(workflowFile))
+	require.NoError(t, err)
+	lock := string(lockContent)
+	assert.Contains(t, lock, "#   Sub-agents:", "lock file header should list imported sub-agents")
+	assert.Contains(t, lock, "agents/security-reviewer.md")
+	assert.Contains(t, lock, "## agent: `security-reviewer`", "sub-agent should be appended to the prompt")
+
+	require.NoError(t, os.WriteFile(workflowFile, []byte(strings.Replace(workflow, "engine: claude", "engine: codex", 1)), 0644))
+	err = NewCompiler().CompileWorkflow(workflowFile)
+	require.Error(t, err, "codex cannot enforce the sub-agent tool allowlist")
+	assert.Contains(t, err.Error(), "restricts 'tools'")
+}
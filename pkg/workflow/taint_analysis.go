@@ -0,0 +1,423 @@
+// This file provides dataflow analysis of untrusted event data in compiled workflows.
+//
+// # Taint Tracking
+//
+// template_injection_validation.go rejects ${{ }} expressions used directly in
+// run: scripts, and expression_safety_validation.go allowlists the expressions a
+// prompt may use. Untrusted data can still reach dangerous places indirectly:
+//
+//   - through env: variables defined at workflow, job or step level
+//   - through step outputs and job outputs consumed via needs.<job>.outputs
+//   - through files written by one step (e.g. pre-steps) and read by a later one
+//   - through steps merged from imports
+//
+// analyzeTaintFlows follows attacker-controlled github.event.* fields (titles,
+// bodies, branch names, commit messages) across the compiled job graph and
+// reports every flow that ends in a sink, together with the full path.
+//
+// # Sinks
+//
+//   - shell: a tainted variable evaluated as code (eval, source, sh -c, ...)
+//   - github-env: a tainted value written to $GITHUB_ENV or $GITHUB_PATH
+//   - script: a tainted expression interpolated into an actions/github-script script
+//   - prompt: a tainted value reaching the agent prompt through an indirect path
+//   - safe-output: a tainted value reaching a step of the safe_outputs job
+//
+// Direct expressions in prompt steps are not reported: they are written by the
+// workflow author and governed by the expression allowlist. The analysis is
+// conservative: a step with a tainted input taints all of its outputs, unless it
+// is a known sanitizing step.
+
+package workflow
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/constants"
+	"github.com/goccy/go-yaml"
+)
+
+var taintAnalysisLog = newValidationLogger("taint_analysis")
+
+// Taint sink kinds
+const (
+	taintSinkShell      = "shell"
+	taintSinkGitHubEnv  = "github-env"
+	taintSinkScript     = "script"
+	taintSinkPrompt     = "prompt"
+	taintSinkSafeOutput = "safe-output"
+)
+
+var (
+	// untrustedSourceRegex matches event fields that any user who can open an issue,
+	// comment or pull request controls
+	untrustedSourceRegex = regexp.MustCompile(`\bgithub\.(?:head_ref\b|event\.(?:` +
+		`(?:issue|pull_request|discussion)\.(?:title|body)|` +
+		`(?:comment|review|review_comment)\.body|` +
+		`pull_request\.head\.(?:ref|label|repo\.default_branch)|` +
+		`(?:head_commit|commits\[?[^\]\s.]*\]?)\.(?:message|author\.(?:email|name))|` +
+		`pages\[?[^\]\s.]*\]?\.page_name|` +
+		`workflow_run\.(?:head_branch|display_title|head_commit\.(?:message|author\.(?:email|name)))` +
+		`)\b)`)
+
+	taintEnvExprRegex        = regexp.MustCompile(`\benv\.([A-Za-z_][A-Za-z0-9_]*)`)
+	taintStepOutputExprRegex = regexp.MustCompile(`\bsteps\.([A-Za-z0-9_-]+)\.outputs\b`)
+	taintNeedsOutputRegex    = regexp.MustCompile(`\bneeds\.([A-Za-z0-9_-]+)\.outputs\.([A-Za-z0-9_-]+)`)
+	taintShellVarRegex       = regexp.MustCompile(`\$\{?([A-Za-z_][A-Za-z0-9_]*)`)
+	taintFileWriteRegex      = regexp.MustCompile(`(?:>>?|\btee\s+(?:-a\s+)?)\s*"?([^\s"';|&<>]+)`)
+	taintShellEvalRegex      = regexp.MustCompile(`\beval\b|\bsource\b|(?:^|[\s;&|(])\.\s|\b(?:ba|z|da)?sh\s+-c\b|\bpython3?\s+-c\b|\bnode\s+-e\b`)
+)
+
+// taintSanitizingStepIDs are compiler-generated steps whose outputs are sanitized
+// versions of untrusted event text
+var taintSanitizingStepIDs = map[string]bool{
+	"sanitized": true,
+}
+
+// TaintHop is one carrier of untrusted data on its way to a sink
+type TaintHop struct {
+	Job  string
+	Step string // empty for job-level carriers
+	Via  string // e.g. "env.TITLE", "steps.fetch.outputs", "needs.activation.outputs.text"
+}
+
+// TaintFinding is an untrusted source reaching a sink
+type TaintFinding struct {
+	Source string // the untrusted expression, e.g. "github.event.issue.title"
+	Sink   string
+	Job    string
+	Step   string
+	Path   []TaintHop
+}
+
+// String renders the finding with its full taint path
+func (f TaintFinding) String() string {
+	parts := []string{f.Source}
+	for _, hop := range f.Path {
+		parts = append(parts, hop.Via+" ("+taintLocation(hop.Job, hop.Step)+")")
+	}
+	parts = append(parts, f.Sink+" ("+taintLocation(f.Job, f.Step)+")")
+	return strings.Join(parts, " → ")
+}
+
+// indirect reports whether the data passed through a carrier outside the sink step
+func (f TaintFinding) indirect() bool {
+	return slices.ContainsFunc(f.Path, func(hop TaintHop) bool { return hop.Job != f.Job || hop.Step != f.Step })
+}
+
+func taintLocation(job, step string) string {
+	if step == "" {
+		return "job " + job
+	}
+	return fmt.Sprintf("job %s, step %q", job, step)
+}
+
+// taintLabel records where a tainted value came from and how it travelled
+type taintLabel struct {
+	source string
+	path   []TaintHop
+}
+
+// extend returns a copy of the label with one more hop
+func (l *taintLabel) extend(hop TaintHop) *taintLabel {
+	return &taintLabel{source: l.source, path: append(slices.Clone(l.path), hop)}
+}
+
+// taintJobState is the taint known while walking the steps of one job
+type taintJobState struct {
+	job         string
+	env         map[string]*taintLabel
+	stepOutputs map[string]*taintLabel
+	files       map[string]*taintLabel
+	jobOutputs  map[string]*taintLabel // "<job>.<output>" across the workflow
+}
+
+// expressionTaint returns the label of the first tainted value referenced by the
+// ${{ }} expressions in value, or nil
+func (s *taintJobState) expressionTaint(value string) *taintLabel {
+	for _, match := range expressionRegex.FindAllStringSubmatch(value, -1) {
+		expr := match[1]
+		if source := untrustedSourceRegex.FindString(expr); source != "" {
+			return &taintLabel{source: source}
+		}
+		for _, m := range taintEnvExprRegex.FindAllStringSubmatch(expr, -1) {
+			if label := s.env[m[1]]; label != nil {
+				return label
+			}
+		}
+		for _, m := range taintStepOutputExprRegex.FindAllStringSubmatch(expr, -1) {
+			if label := s.stepOutputs[m[1]]; label != nil {
+				return label
+			}
+		}
+		for _, m := range taintNeedsOutputRegex.FindAllStringSubmatch(expr, -1) {
+			if label := s.jobOutputs[m[1]+"."+m[2]]; label != nil {
+				return label
+			}
+		}
+	}
+	return nil
+}
+
+// shellTaint returns the label of the first tainted variable or file referenced by a
+// line of a run: script, or nil
+func (s *taintJobState) shellTaint(line string, env map[string]*taintLabel) *taintLabel {
+	for _, m := range taintShellVarRegex.FindAllStringSubmatch(line, -1) {
+		if label := env[m[1]]; label != nil {
+			return label
+		}
+	}
+	for _, path := range slices.Sorted(maps.Keys(s.files)) {
+		if strings.Contains(line, path) {
+			return s.files[path]
+		}
+	}
+	return s.expressionTaint(line)
+}
+
+// analyzeTaintFlows follows untrusted event data through a parsed compiled workflow
+// and returns the flows that reach a sink
+func analyzeTaintFlows(workflow map[string]any) []TaintFinding {
+	jobs, _ := workflow["jobs"].(map[string]any)
+	if len(jobs) == 0 {
+		return nil
+	}
+
+	jobOutputs := make(map[string]*taintLabel)
+	workflowEnv := make(map[string]*taintLabel)
+	root := &taintJobState{env: workflowEnv, jobOutputs: jobOutputs}
+	for _, name := range sortedTaintKeys(workflow["env"]) {
+		value := fmt.Sprint(workflow["env"].(map[string]any)[name])
+		if label := root.expressionTaint(value); label != nil {
+			workflowEnv[name] = label.extend(TaintHop{Job: "workflow", Via: "env." + name})
+		}
+	}
+
+	var findings []TaintFinding
+	for _, jobName := range taintJobOrder(jobs) {
+		job, _ := jobs[jobName].(map[string]any)
+		state := &taintJobState{
+			job:         jobName,
+			env:         maps.Clone(workflowEnv),
+			stepOutputs: make(map[string]*taintLabel),
+			files:       make(map[string]*taintLabel),
+			jobOutputs:  jobOutputs,
+		}
+		for _, name := range sortedTaintKeys(job["env"]) {
+			value := fmt.Sprint(job["env"].(map[string]any)[name])
+			if label := state.expressionTaint(value); label != nil {
+				state.env[name] = label.extend(TaintHop{Job: jobName, Via: "env." + name})
+			}
+		}
+
+		steps, _ := job["steps"].([]any)
+		for i, rawStep := range steps {
+			step, ok := rawStep.(map[string]any)
+			if !ok {
+				continue
+			}
+			findings = append(findings, state.analyzeStep(step, taintStepName(step, i))...)
+		}
+
+		for _, name := range sortedTaintKeys(job["outputs"]) {
+			value := fmt.Sprint(job["outputs"].(map[string]any)[name])
+			if label := state.expressionTaint(value); label != nil {
+				jobOutputs[jobName+"."+name] = label.extend(TaintHop{Job: jobName, Via: "needs." + jobName + ".outputs." + name})
+			}
+		}
+	}
+
+	taintAnalysisLog.Printf("Taint analysis found %d flows into sinks", len(findings))
+	return findings
+}
+
+// analyzeStep propagates taint through one step and returns the sinks it reaches
+func (s *taintJobState) analyzeStep(step map[string]any, stepName string) []TaintFinding {
+	var findings []TaintFinding
+	report := func(sink string, label *taintLabel) {
+		findings = append(findings, TaintFinding{Source: label.source, Sink: sink, Job: s.job, Step: stepName, Path: label.path})
+	}
+
+	env := maps.Clone(s.env)
+	stepEnv, _ := step["env"].(map[string]any)
+	var inputs []*taintLabel
+	for _, name := range sortedTaintKeys(step["env"]) {
+		if label := s.expressionTaint(fmt.Sprint(stepEnv[name])); label != nil {
+			env[name] = label.extend(TaintHop{Job: s.job, Step: stepName, Via: "env." + name})
+			inputs = append(inputs, env[name])
+		}
+	}
+	_, buildsPrompt := stepEnv["GH_AW_PROMPT"]
+	stepID, _ := step["id"].(string)
+
+	writesOutput := false
+	if run, ok := step["run"].(string); ok {
+		for line := range strings.SplitSeq(removeHeredocContent(run), "\n") {
+			label := s.shellTaint(line, env)
+			if label == nil {
+				continue
+			}
+			inputs = append(inputs, label)
+			switch {
+			case strings.Contains(line, "GITHUB_ENV") || strings.Contains(line, "GITHUB_PATH"):
+				report(taintSinkGitHubEnv, label)
+			case taintShellEvalRegex.MatchString(line):
+				report(taintSinkShell, label)
+			}
+			if strings.Contains(line, "GITHUB_OUTPUT") {
+				writesOutput = true
+			}
+			for _, m := range taintFileWriteRegex.FindAllStringSubmatch(line, -1) {
+				path := m[1]
+				if strings.Contains(path, "GITHUB_") || strings.HasPrefix(path, "&") || path == "/dev/null" {
+					continue
+				}
+				if strings.Contains(path, "aw-prompts") || strings.Contains(path, "GH_AW_PROMPT") {
+					report(taintSinkPrompt, label)
+					continue
+				}
+				s.files[path] = label.extend(TaintHop{Job: s.job, Step: stepName, Via: "file " + path})
+			}
+		}
+	} else {
+		// Actions see every input and environment variable; any of them may end up in an output
+		with, _ := step["with"].(map[string]any)
+		for _, name := range sortedTaintKeys(step["with"]) {
+			value := fmt.Sprint(with[name])
+			label := s.expressionTaint(value)
+			if label == nil {
+				continue
+			}
+			if name == "script" {
+				if uses, _ := step["uses"].(string); strings.HasPrefix(uses, "actions/github-script@") {
+					report(taintSinkScript, label)
+				}
+			}
+			inputs = append(inputs, label.extend(TaintHop{Job: s.job, Step: stepName, Via: "with." + name}))
+		}
+		for _, name := range slices.Sorted(maps.Keys(s.env)) {
+			if _, overridden := stepEnv[name]; !overridden && s.env[name] != nil {
+				inputs = append(inputs, s.env[name])
+			}
+		}
+		writesOutput = len(inputs) > 0
+	}
+
+	// The agent can read any file written earlier in its job, e.g. by pre-steps
+	runsAgent := stepID == "agentic_execution"
+	if runsAgent {
+		for _, path := range slices.Sorted(maps.Keys(s.files)) {
+			inputs = append(inputs, s.files[path])
+		}
+	}
+
+	for _, input := range inputs {
+		switch {
+		case buildsPrompt || runsAgent:
+			report(taintSinkPrompt, input)
+		case s.job == string(constants.SafeOutputsJobName):
+			report(taintSinkSafeOutput, input)
+		}
+	}
+	if len(inputs) > 0 && stepID != "" && writesOutput && !taintSanitizingStepIDs[stepID] {
+		s.stepOutputs[stepID] = inputs[0].extend(TaintHop{Job: s.job, Step: stepName, Via: "steps." + stepID + ".outputs"})
+	}
+	return findings
+}
+
+// taintJobOrder returns job names with every job after the jobs it needs
+func taintJobOrder(jobs map[string]any) []string {
+	needs := make(map[string][]string, len(jobs))
+	for name, raw := range jobs {
+		job, _ := raw.(map[string]any)
+		switch v := job["needs"].(type) {
+		case string:
+			needs[name] = []string{v}
+		case []any:
+			for _, n := range v {
+				needs[name] = append(needs[name], fmt.Sprint(n))
+			}
+		}
+	}
+
+	var order []string
+	visited := make(map[string]bool, len(jobs))
+	var visit func(name string)
+	visit = func(name string) {
+		if visited[name] {
+			return
+		}
+		visited[name] = true
+		for _, dep := range needs[name] {
+			if _, ok := jobs[dep]; ok {
+				visit(dep)
+			}
+		}
+		order = append(order, name)
+	}
+	for _, name := range slices.Sorted(maps.Keys(jobs)) {
+		visit(name)
+	}
+	return order
+}
+
+// taintStepName returns a readable name for a step
+func taintStepName(step map[string]any, index int) string {
+	for _, key := range []string{"name", "id", "uses"} {
+		if value, ok := step[key].(string); ok && value != "" {
+			return value
+		}
+	}
+	return fmt.Sprintf("step %d", index+1)
+}
+
+// sortedTaintKeys returns the keys of a YAML mapping in a stable order
+func sortedTaintKeys(value any) []string {
+	m, ok := value.(map[string]any)
+	if !ok {
+		return nil
+	}
+	return slices.Sorted(maps.Keys(m))
+}
+
+// taintSinkAdvice explains how to break each kind of flow
+var taintSinkAdvice = map[string]string{
+	taintSinkShell:      "quote the variable and never evaluate it as code",
+	taintSinkGitHubEnv:  "untrusted values in $GITHUB_ENV or $GITHUB_PATH can inject variables into later steps; validate the value first",
+	taintSinkScript:     "pass the value through env: and read it with process.env instead of interpolating it into the script",
+	taintSinkPrompt:     "reference ${{ steps.sanitized.outputs.text }} in the prompt instead of passing the raw field through steps or files",
+	taintSinkSafeOutput: "safe output configuration should not depend on untrusted event fields",
+}
+
+// reportTaintFlows warns about untrusted event data reaching a sink in the compiled
+// workflow. Direct prompt expressions are left to the expression allowlist.
+func (c *Compiler) reportTaintFlows(yamlContent, markdownPath string, parsedWorkflow map[string]any) {
+	// Cheap pre-check: most workflows never reference an untrusted field
+	if !untrustedSourceRegex.MatchString(yamlContent) {
+		return
+	}
+	if parsedWorkflow == nil {
+		if err := yaml.Unmarshal([]byte(yamlContent), &parsedWorkflow); err != nil {
+			taintAnalysisLog.Printf("Skipping taint analysis, failed to parse YAML: %v", err)
+			return
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, finding := range analyzeTaintFlows(parsedWorkflow) {
+		if finding.Sink == taintSinkPrompt && !finding.indirect() {
+			continue
+		}
+		path := finding.String()
+		if seen[path] {
+			continue
+		}
+		seen[path] = true
+		msg := fmt.Sprintf("Untrusted event data reaches a %s sink: %s. To fix, %s.", finding.Sink, path, taintSinkAdvice[finding.Sink])
+		c.addWarningAt(markdownPath, msg)
+	}
+}
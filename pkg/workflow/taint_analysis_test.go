@@ -0,0 +1,206 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"testing"
+
+	"github.com/goccy/go-yaml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func analyzeTaintYAML(t *testing.T, content string) []TaintFinding {
+	t.Helper()
+	var parsed map[string]any
+	require.NoError(t, yaml.Unmarshal([]byte(content), &parsed), "test workflow should parse")
+	return analyzeTaintFlows(parsed)
+}
+
+func TestAnalyzeTaintFlows_PathAcrossJobs(t *testing.T) {
+	findings := analyzeTaintYAML(t, `
+jobs:
+  agent:
+    needs: pre
+    runs-on: ubuntu-latest
+    steps:
+      - name: Create prompt
+        env:
+          GH_AW_PROMPT: /tmp/gh-aw/aw-prompts/prompt.txt
+          SUMMARY: ${{ needs.pre.outputs.summary }}
+        run: echo done
+  pre:
+    runs-on: ubuntu-latest
+    outputs:
+      summary: ${{ steps.fetch.outputs.summary }}
+    env:
+      TITLE: ${{ github.event.issue.title }}
+    steps:
+      - name: Fetch
+        id: fetch
+        run: echo "summary=$TITLE" >> "$GITHUB_OUTPUT"
+`)
+	require.Len(t, findings, 1, "the title should reach the prompt through the job output")
+	finding := findings[0]
+	assert.Equal(t, "github.event.issue.title", finding.Source)
+	assert.Equal(t, taintSinkPrompt, finding.Sink)
+	assert.True(t, finding.indirect(), "flow through another job is indirect")
+	assert.Equal(t,
+		`github.event.issue.title → env.TITLE (job pre) → steps.fetch.outputs (job pre, step "Fetch") → needs.pre.outputs.summary (job pre) → env.SUMMARY (job agent, step "Create prompt") → prompt (job agent, step "Create prompt")`,
+		finding.String())
+}
+
+func TestAnalyzeTaintFlows_Sinks(t *testing.T) {
+	tests := []struct {
+		name  string
+		steps string
+		sink  string
+	}{
+		{
+			name: "eval in shell",
+			steps: `
+      - name: Run
+        env:
+          BRANCH: ${{ github.head_ref }}
+        run: eval "git checkout $BRANCH"`,
+			sink: taintSinkShell,
+		},
+		{
+			name: "github env",
+			steps: `
+      - name: Export
+        env:
+          BODY: ${{ github.event.comment.body }}
+        run: echo "BODY=$BODY" >> "$GITHUB_ENV"`,
+			sink: taintSinkGitHubEnv,
+		},
+		{
+			name: "github-script interpolation",
+			steps: `
+      - name: Script
+        uses: actions/github-script@v8
+        with:
+          script: console.log("${{ github.event.pull_request.body }}")`,
+			sink: taintSinkScript,
+		},
+		{
+			name: "file read by the agent",
+			steps: `
+      - name: Save title
+        env:
+          TITLE: ${{ github.event.issue.title }}
+        run: echo "$TITLE" > /tmp/gh-aw/title.txt
+      - name: Execute agent
+        id: agentic_execution
+        run: agent --prompt-file /tmp/gh-aw/aw-prompts/prompt.txt`,
+			sink: taintSinkPrompt,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings := analyzeTaintYAML(t, "jobs:\n  agent:\n    runs-on: ubuntu-latest\n    steps:"+tt.steps+"\n")
+			require.Len(t, findings, 1, "expected one finding")
+			assert.Equal(t, tt.sink, findings[0].Sink)
+		})
+	}
+}
+
+func TestAnalyzeTaintFlows_SafeOutputsJob(t *testing.T) {
+	findings := analyzeTaintYAML(t, `
+jobs:
+  activation:
+    runs-on: ubuntu-latest
+    outputs:
+      title: ${{ steps.read.outputs.title }}
+    steps:
+      - id: read
+        uses: some/action@v1
+        with:
+          text: ${{ github.event.discussion.title }}
+  safe_outputs:
+    needs: [activation]
+    runs-on: ubuntu-latest
+    steps:
+      - name: Create issue
+        uses: actions/github-script@v8
+        env:
+          GH_AW_TITLE_PREFIX: ${{ needs.activation.outputs.title }}
+        with:
+          script: await main()
+`)
+	require.Len(t, findings, 1)
+	assert.Equal(t, taintSinkSafeOutput, findings[0].Sink)
+	assert.Equal(t, "github.event.discussion.title", findings[0].Source)
+}
+
+func TestAnalyzeTaintFlows_NoFindings(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{
+			name: "sanitized text",
+			content: `
+jobs:
+  activation:
+    runs-on: ubuntu-latest
+    steps:
+      - name: Compute current body text
+        id: sanitized
+        uses: actions/github-script@v8
+        env:
+          BODY: ${{ github.event.issue.body }}
+        with:
+          script: await main()
+      - name: Create prompt
+        env:
+          GH_AW_PROMPT: /tmp/gh-aw/aw-prompts/prompt.txt
+          TEXT: ${{ steps.sanitized.outputs.text }}
+        run: echo done
+`,
+		},
+		{
+			name: "quoted variable",
+			content: `
+jobs:
+  agent:
+    runs-on: ubuntu-latest
+    steps:
+      - env:
+          TITLE: ${{ github.event.issue.title }}
+        run: echo "$TITLE"
+`,
+		},
+		{
+			name: "trusted fields",
+			content: `
+jobs:
+  agent:
+    runs-on: ubuntu-latest
+    steps:
+      - env:
+          NUMBER: ${{ github.event.issue.number }}
+        run: eval "echo $NUMBER" >> "$GITHUB_ENV"
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, finding := range analyzeTaintYAML(t, tt.content) {
+				assert.False(t, finding.Sink != taintSinkPrompt || finding.indirect(), "unexpected finding: %s", finding)
+			}
+		})
+	}
+}
+
+func TestTaintJobOrder(t *testing.T) {
+	jobs := map[string]any{
+		"agent":      map[string]any{"needs": []any{"activation"}},
+		"activation": map[string]any{"needs": "pre"},
+		"pre":        map[string]any{},
+		"conclusion": map[string]any{"needs": []any{"agent", "missing"}},
+	}
+	assert.Equal(t, []string{"pre", "activation", "agent", "conclusion"}, taintJobOrder(jobs))
+}
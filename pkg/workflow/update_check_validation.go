@@ -13,10 +13,6 @@ package workflow
 
 import (
 	"errors"
-	"fmt"
-	"os"
-
-	"github.com/github/gh-aw/pkg/console"
 )
 
 var updateCheckValidationLog = newValidationLogger("update_check")
@@ -44,12 +40,11 @@ func (c *Compiler) validateUpdateCheck(frontmatter map[string]any) error {
 	}
 
 	// Non-strict mode: emit a warning and continue
-	fmt.Fprintln(os.Stderr, console.FormatWarningMessage(
-		"'check-for-updates: false' disables the compile-agentic version check. "+
-			"The workflow will not verify that it was compiled with a supported version of gh-aw. "+
+	c.addWarning(
+		"'check-for-updates: false' disables the compile-agentic version check. " +
+			"The workflow will not verify that it was compiled with a supported version of gh-aw. " +
 			"It is strongly recommended to keep check-for-updates enabled.",
-	))
-	c.IncrementWarningCount()
+	)
 
 	return nil
 }
@@ -0,0 +1,248 @@
+// This file contains the automatic update policy loaded from the updates
+// section of aw.json.
+//
+// # Update Policy
+//
+// The policy drives `gh aw update --bot` and `gh aw upgrade --bot`, which open
+// one pull request per update group:
+//
+//	"updates": {
+//	  "schedule": "weekly",
+//	  "semver": "minor",
+//	  "exclude": ["experimental-*"],
+//	  "groups": [
+//	    {"name": "workflows", "updates": ["workflows"]},
+//	    {"name": "dependencies", "updates": ["actions", "containers"], "schedule": "daily"},
+//	    {"name": "codemods", "updates": ["codemods"]}
+//	  ]
+//	}
+//
+// Each group bundles one or more kinds of update. Workflow-scoped kinds
+// (workflows and codemods) only touch the workflows matched by the group's
+// workflow patterns, and a workflow is handled by the first group that matches
+// it. Actions and containers are repository-wide, so each may appear in at most
+// one group.
+//
+// The schedule is the minimum time between two pull requests of a group, and
+// semver bounds how far workflow sources and action pins may move: patch keeps
+// major and minor, minor keeps major, major allows any newer release.
+
+package workflow
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"time"
+
+	"github.com/github/gh-aw/pkg/logger"
+)
+
+var updatePolicyLog = logger.New("workflow:update_policy")
+
+// UpdateKind is a kind of automatic update handled by the update bot.
+type UpdateKind string
+
+const (
+	UpdateKindWorkflows  UpdateKind = "workflows"
+	UpdateKindActions    UpdateKind = "actions"
+	UpdateKindContainers UpdateKind = "containers"
+	UpdateKindCodemods   UpdateKind = "codemods"
+)
+
+// UpdateKinds lists every update kind in the order the bot applies them.
+var UpdateKinds = []UpdateKind{UpdateKindWorkflows, UpdateKindActions, UpdateKindContainers, UpdateKindCodemods}
+
+// Update schedules and semver ranges accepted in aw.json.
+const (
+	UpdateScheduleDaily   = "daily"
+	UpdateScheduleWeekly  = "weekly"
+	UpdateScheduleMonthly = "monthly"
+
+	UpdateSemverPatch = "patch"
+	UpdateSemverMinor = "minor"
+	UpdateSemverMajor = "major"
+)
+
+// updateGroupNamePattern keeps group names usable in branch names.
+var updateGroupNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+// UpdatePolicyConfig holds the updates section of aw.json.
+type UpdatePolicyConfig struct {
+	// Schedule is the default minimum interval between pull requests of a group.
+	Schedule string `json:"schedule,omitempty"`
+
+	// Semver is the default range of allowed version changes.
+	Semver string `json:"semver,omitempty"`
+
+	// Exclude lists workflow IDs or glob patterns that are never updated.
+	Exclude []string `json:"exclude,omitempty"`
+
+	// Groups lists the update groups; each group becomes one pull request.
+	Groups []UpdateGroupConfig `json:"groups,omitempty"`
+}
+
+// UpdateGroupConfig is a single update group.
+type UpdateGroupConfig struct {
+	// Name identifies the group in branch names and pull request titles.
+	Name string `json:"name"`
+
+	// Updates lists the kinds of update bundled in the group.
+	Updates []UpdateKind `json:"updates"`
+
+	// Workflows restricts workflow-scoped updates to matching workflow IDs or
+	// glob patterns. Empty matches every workflow.
+	Workflows []string `json:"workflows,omitempty"`
+
+	// Schedule overrides the policy schedule for this group.
+	Schedule string `json:"schedule,omitempty"`
+
+	// Semver overrides the policy semver range for this group.
+	Semver string `json:"semver,omitempty"`
+}
+
+// defaultUpdateGroups is used when aw.json does not configure any group.
+var defaultUpdateGroups = []UpdateGroupConfig{
+	{Name: "workflows", Updates: []UpdateKind{UpdateKindWorkflows}},
+	{Name: "actions", Updates: []UpdateKind{UpdateKindActions, UpdateKindContainers}},
+	{Name: "codemods", Updates: []UpdateKind{UpdateKindCodemods}},
+}
+
+// UpdatePolicy returns the update policy of the repository, or the default
+// policy when aw.json has no updates section.
+func (r *RepoConfig) UpdatePolicy() *UpdatePolicyConfig {
+	if r == nil || r.Updates == nil {
+		return &UpdatePolicyConfig{}
+	}
+	return r.Updates
+}
+
+func (p *UpdatePolicyConfig) validate() error {
+	if p == nil {
+		return nil
+	}
+	seenNames := make(map[string]bool)
+	seenKinds := make(map[UpdateKind]string)
+	for _, group := range p.Groups {
+		if !updateGroupNamePattern.MatchString(group.Name) {
+			return fmt.Errorf("updates group name %q must contain only lowercase letters, digits and dashes", group.Name)
+		}
+		if seenNames[group.Name] {
+			return fmt.Errorf("updates group %q is defined more than once", group.Name)
+		}
+		seenNames[group.Name] = true
+		if len(group.Updates) == 0 {
+			return fmt.Errorf("updates group %q must list at least one update kind", group.Name)
+		}
+		for _, kind := range group.Updates {
+			if kind != UpdateKindActions && kind != UpdateKindContainers {
+				continue
+			}
+			if other, ok := seenKinds[kind]; ok {
+				return fmt.Errorf("%s updates are repository-wide and cannot be in both groups %q and %q", kind, other, group.Name)
+			}
+			seenKinds[kind] = group.Name
+		}
+	}
+	return nil
+}
+
+// EffectiveGroups returns the configured groups, or the default groups (one
+// each for workflows, actions with containers, and codemods) when none are set.
+func (p *UpdatePolicyConfig) EffectiveGroups() []UpdateGroupConfig {
+	if p == nil || len(p.Groups) == 0 {
+		return defaultUpdateGroups
+	}
+	return p.Groups
+}
+
+// IsExcluded reports whether a workflow is excluded from automatic updates.
+func (p *UpdatePolicyConfig) IsExcluded(workflowID string) bool {
+	if p == nil {
+		return false
+	}
+	return matchesAnyWorkflowPattern(p.Exclude, workflowID)
+}
+
+// WorkflowsFor returns the workflows handled by a group for one update kind:
+// the workflows matching the group, minus excluded workflows and workflows
+// claimed by an earlier group with the same kind.
+func (p *UpdatePolicyConfig) WorkflowsFor(group UpdateGroupConfig, kind UpdateKind, workflowIDs []string) []string {
+	var selected []string
+	for _, id := range workflowIDs {
+		if p.IsExcluded(id) || !groupMatchesWorkflow(group, id) {
+			continue
+		}
+		if owner := p.groupOwningWorkflow(kind, id); owner != group.Name {
+			updatePolicyLog.Printf("Workflow %s is handled by group %s for %s updates", id, owner, kind)
+			continue
+		}
+		selected = append(selected, id)
+	}
+	return selected
+}
+
+// groupOwningWorkflow returns the name of the first group handling the kind of
+// update for the workflow.
+func (p *UpdatePolicyConfig) groupOwningWorkflow(kind UpdateKind, workflowID string) string {
+	for _, group := range p.EffectiveGroups() {
+		if group.Has(kind) && groupMatchesWorkflow(group, workflowID) {
+			return group.Name
+		}
+	}
+	return ""
+}
+
+// Has reports whether the group bundles the kind of update.
+func (g UpdateGroupConfig) Has(kind UpdateKind) bool {
+	return slices.Contains(g.Updates, kind)
+}
+
+// ScheduleFor returns the effective schedule of a group (default: weekly).
+func (p *UpdatePolicyConfig) ScheduleFor(group UpdateGroupConfig) string {
+	if group.Schedule != "" {
+		return group.Schedule
+	}
+	if p != nil && p.Schedule != "" {
+		return p.Schedule
+	}
+	return UpdateScheduleWeekly
+}
+
+// SemverFor returns the effective semver range of a group (default: minor).
+func (p *UpdatePolicyConfig) SemverFor(group UpdateGroupConfig) string {
+	if group.Semver != "" {
+		return group.Semver
+	}
+	if p != nil && p.Semver != "" {
+		return p.Semver
+	}
+	return UpdateSemverMinor
+}
+
+// UpdateScheduleInterval returns the minimum time between two pull requests
+// for a schedule.
+func UpdateScheduleInterval(schedule string) time.Duration {
+	switch schedule {
+	case UpdateScheduleDaily:
+		return 24 * time.Hour
+	case UpdateScheduleMonthly:
+		return 30 * 24 * time.Hour
+	default:
+		return 7 * 24 * time.Hour
+	}
+}
+
+func groupMatchesWorkflow(group UpdateGroupConfig, workflowID string) bool {
+	return len(group.Workflows) == 0 || matchesAnyWorkflowPattern(group.Workflows, workflowID)
+}
+
+func matchesAnyWorkflowPattern(patterns []string, workflowID string) bool {
+	for _, pattern := range patterns {
+		if matched, err := path.Match(pattern, workflowID); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
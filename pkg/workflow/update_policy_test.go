@@ -0,0 +1,87 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadRepoConfig_UpdatesSection(t *testing.T) {
+	dir := t.TempDir()
+	writeAWJSON(t, dir, `{
+		"updates": {
+			"schedule": "weekly",
+			"semver": "patch",
+			"exclude": ["experimental-*"],
+			"groups": [
+				{"name": "daily", "updates": ["workflows", "codemods"], "workflows": ["daily-*"], "semver": "minor"},
+				{"name": "dependencies", "updates": ["actions", "containers"], "schedule": "daily"}
+			]
+		}
+	}`)
+
+	cfg, err := LoadRepoConfig(dir)
+	require.NoError(t, err, "valid updates section should load without error")
+	policy := cfg.UpdatePolicy()
+	require.Len(t, policy.EffectiveGroups(), 2, "configured groups should replace the defaults")
+
+	daily, dependencies := policy.Groups[0], policy.Groups[1]
+	assert.Equal(t, UpdateSemverMinor, policy.SemverFor(daily), "group semver should override the policy")
+	assert.Equal(t, UpdateSemverPatch, policy.SemverFor(dependencies), "policy semver should apply by default")
+	assert.Equal(t, UpdateScheduleDaily, policy.ScheduleFor(dependencies), "group schedule should override the policy")
+	assert.Equal(t, UpdateScheduleWeekly, policy.ScheduleFor(daily), "policy schedule should apply by default")
+	assert.True(t, dependencies.Has(UpdateKindContainers), "group kinds should be parsed")
+}
+
+func TestLoadRepoConfig_UpdatesValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{name: "unknown kind", content: `{"updates": {"groups": [{"name": "all", "updates": ["npm"]}]}}`},
+		{name: "invalid group name", content: `{"updates": {"groups": [{"name": "My Group", "updates": ["workflows"]}]}}`},
+		{name: "duplicate group", content: `{"updates": {"groups": [{"name": "a", "updates": ["workflows"]}, {"name": "a", "updates": ["codemods"]}]}}`},
+		{name: "actions in two groups", content: `{"updates": {"groups": [{"name": "a", "updates": ["actions"]}, {"name": "b", "updates": ["actions"]}]}}`},
+		{name: "unknown schedule", content: `{"updates": {"schedule": "hourly"}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeAWJSON(t, dir, tt.content)
+
+			_, err := LoadRepoConfig(dir)
+			require.Error(t, err, "invalid updates section should fail to load")
+		})
+	}
+}
+
+func TestUpdatePolicy_Defaults(t *testing.T) {
+	policy := (&RepoConfig{}).UpdatePolicy()
+
+	groups := policy.EffectiveGroups()
+	require.Len(t, groups, 3, "one default group per kind of update")
+	assert.Equal(t, UpdateScheduleWeekly, policy.ScheduleFor(groups[0]), "weekly should be the default schedule")
+	assert.Equal(t, UpdateSemverMinor, policy.SemverFor(groups[0]), "minor should be the default semver range")
+	assert.Equal(t, 7*24*time.Hour, UpdateScheduleInterval(policy.ScheduleFor(groups[0])))
+}
+
+func TestUpdatePolicy_WorkflowsFor(t *testing.T) {
+	policy := &UpdatePolicyConfig{
+		Exclude: []string{"experimental-*"},
+		Groups: []UpdateGroupConfig{
+			{Name: "daily", Updates: []UpdateKind{UpdateKindWorkflows}, Workflows: []string{"daily-*"}},
+			{Name: "rest", Updates: []UpdateKind{UpdateKindWorkflows, UpdateKindCodemods}},
+		},
+	}
+	ids := []string{"daily-docs", "experimental-agent", "triage"}
+
+	assert.Equal(t, []string{"daily-docs"}, policy.WorkflowsFor(policy.Groups[0], UpdateKindWorkflows, ids))
+	assert.Equal(t, []string{"triage"}, policy.WorkflowsFor(policy.Groups[1], UpdateKindWorkflows, ids),
+		"workflows claimed by an earlier group and excluded workflows should be skipped")
+	assert.Equal(t, []string{"daily-docs", "triage"}, policy.WorkflowsFor(policy.Groups[1], UpdateKindCodemods, ids),
+		"a workflow is claimed per kind of update")
+}
@@ -71,6 +71,7 @@ func (c *Compiler) buildInitialWorkflowData(
 		RawFrontmatter:        result.Frontmatter,
 		ResolvedMCPServers:    toolsResult.resolvedMCPServers,
 		HasExplicitGitHubTool: toolsResult.hasExplicitGitHubTool,
+		ImportedSubAgents:     toolsResult.importedSubAgents,
 		ActionMode:            c.actionMode,
 		InlinedImports:        inlinedImports,
 		EngineConfigSteps:     engineSetup.configSteps,
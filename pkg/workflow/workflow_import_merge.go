@@ -2,12 +2,9 @@ package workflow
 
 import (
 	"encoding/json"
-	"fmt"
 	"maps"
-	"os"
 	"strings"
 
-	"github.com/github/gh-aw/pkg/console"
 	"github.com/github/gh-aw/pkg/logger"
 	"github.com/github/gh-aw/pkg/parser"
 	"github.com/goccy/go-yaml"
@@ -63,8 +60,7 @@ func (c *Compiler) processAndMergeServices(frontmatter map[string]any, workflowD
 		workflowData.ServicePortExpressions = expressions
 		for _, w := range warnings {
 			workflowImportMergeLog.Printf("Warning: %s", w)
-			fmt.Fprintln(os.Stderr, console.FormatWarningMessage(w))
-			c.IncrementWarningCount()
+			c.addWarning(w)
 		}
 		if expressions != "" {
 			workflowImportMergeLog.Printf("Extracted service port expressions: %s", expressions)
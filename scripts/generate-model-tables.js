@@ -9,8 +9,8 @@
  *   node scripts/generate-model-tables.js
  *
  * Inputs:
- *   pkg/cli/data/model_aliases.json     – Built-in alias → pattern mappings
- *   pkg/cli/data/model_multipliers.json – Per-model Effective Token multipliers
+ *   pkg/workflow/data/model_aliases.json     – Built-in alias → pattern mappings
+ *   pkg/workflow/data/model_multipliers.json – Per-model Effective Token multipliers
  *
  * Output:
  *   docs/src/content/docs/reference/model-tables.md
@@ -25,7 +25,7 @@ const __dirname = path.dirname(__filename);
 
 const ROOT = path.resolve(__dirname, "..");
 const ALIASES_PATH = path.join(ROOT, "pkg/workflow/data/model_aliases.json");
-const MULTIPLIERS_PATH = path.join(ROOT, "pkg/cli/data/model_multipliers.json");
+const MULTIPLIERS_PATH = path.join(ROOT, "pkg/workflow/data/model_multipliers.json");
 const OUTPUT_PATH = path.join(ROOT, "docs/src/content/docs/reference/model-tables.md");
 
 // ---------------------------------------------------------------------------